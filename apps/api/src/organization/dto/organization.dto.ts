@@ -107,6 +107,13 @@ export class OrganizationDto {
   })
   sandboxLifecycleRateLimit: number | null
 
+  @ApiProperty({
+    description: 'Preview traffic limit in bytes per second',
+    nullable: true,
+    required: false,
+  })
+  maxPreviewBandwidth?: number | null
+
   static fromOrganization(organization: Organization): OrganizationDto {
     const dto: OrganizationDto = {
       id: organization.id,
@@ -128,6 +135,7 @@ export class OrganizationDto {
       authenticatedRateLimit: organization.authenticatedRateLimit,
       sandboxCreateRateLimit: organization.sandboxCreateRateLimit,
       sandboxLifecycleRateLimit: organization.sandboxLifecycleRateLimit,
+      maxPreviewBandwidth: organization.maxPreviewBandwidth,
     }
 
     return dto
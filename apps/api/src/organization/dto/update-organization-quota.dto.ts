@@ -33,4 +33,7 @@ export class UpdateOrganizationQuotaDto {
 
   @ApiProperty({ nullable: true })
   sandboxLifecycleRateLimit?: number
+
+  @ApiProperty({ nullable: true, required: false })
+  maxPreviewBandwidth?: number
 }
@@ -104,6 +104,13 @@ export class Organization {
   })
   sandboxLifecycleRateLimit: number | null
 
+  @Column({
+    type: 'int',
+    nullable: true,
+    name: 'max_preview_bandwidth',
+  })
+  maxPreviewBandwidth: number | null
+
   @OneToMany(() => RegionQuota, (quota) => quota.organization, {
     cascade: true,
     onDelete: 'CASCADE',
@@ -165,6 +165,7 @@ export class OrganizationService implements OnModuleInit, TrackableJobExecutions
     organization.sandboxCreateRateLimit = updateDto.sandboxCreateRateLimit ?? organization.sandboxCreateRateLimit
     organization.sandboxLifecycleRateLimit =
       updateDto.sandboxLifecycleRateLimit ?? organization.sandboxLifecycleRateLimit
+    organization.maxPreviewBandwidth = updateDto.maxPreviewBandwidth ?? organization.maxPreviewBandwidth
 
     await this.organizationRepository.save(organization)
   }
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2025 Daytona Platforms Inc.
+ * SPDX-License-Identifier: AGPL-3.0
+ */
+
+import { ApiPropertyOptional, ApiSchema } from '@nestjs/swagger'
+import { IsInt, IsOptional, IsString, Max, Min } from 'class-validator'
+
+@ApiSchema({ name: 'RegionRunnerPool' })
+export class RegionRunnerPoolDto {
+  @ApiPropertyOptional({
+    description: 'Minimum number of idle runners',
+    example: 1,
+  })
+  @IsOptional()
+  @IsInt()
+  @Min(0)
+  minIdleRunners?: number
+
+  @ApiPropertyOptional({
+    description: 'Minimum idle CPU across the runners',
+    example: 8,
+  })
+  @IsOptional()
+  @IsInt()
+  @Min(0)
+  minIdleCpu?: number
+
+  @ApiPropertyOptional({
+    description: 'Minimum idle memory across the runners in GiB',
+    example: 16,
+  })
+  @IsOptional()
+  @IsInt()
+  @Min(0)
+  minIdleMemory?: number
+
+  @ApiPropertyOptional({
+    description: 'Resource utilization percent above which the pool scales up',
+    example: 80,
+  })
+  @IsOptional()
+  @IsInt()
+  @Min(1)
+  @Max(100)
+  maxResourceUtilizationPercent?: number
+
+  @ApiPropertyOptional({
+    description: 'Node label key selecting the runner nodes',
+    example: 'daytona.io/runner',
+  })
+  @IsOptional()
+  @IsString()
+  nodeSelectorKey?: string
+
+  @ApiPropertyOptional({
+    description: 'Taint key reserving the runner nodes',
+    example: 'daytona.io/runner',
+  })
+  @IsOptional()
+  @IsString()
+  taintKey?: string
+
+  @ApiPropertyOptional({
+    description: 'Runner version the runners are rolled out to',
+    example: '0.1.0',
+  })
+  @IsOptional()
+  @IsString()
+  runnerVersion?: string
+}
@@ -7,6 +7,7 @@ import { ApiProperty, ApiSchema } from '@nestjs/swagger'
 import { IsEnum } from 'class-validator'
 import { Region } from '../entities/region.entity'
 import { RegionType } from '../enums/region-type.enum'
+import { RegionRunnerPoolDto } from './region-runner-pool.dto'
 
 @ApiSchema({ name: 'Region' })
 export class RegionDto {
@@ -75,6 +76,14 @@ export class RegionDto {
   })
   snapshotManagerUrl?: string | null
 
+  @ApiProperty({
+    description: 'Runner pool configuration expected from the runner-managers of the region',
+    type: RegionRunnerPoolDto,
+    nullable: true,
+    required: false,
+  })
+  runnerPool?: RegionRunnerPoolDto | null
+
   static fromRegion(region: Region): RegionDto {
     return {
       id: region.id,
@@ -86,6 +95,7 @@ export class RegionDto {
       proxyUrl: region.proxyUrl,
       sshGatewayUrl: region.sshGatewayUrl,
       snapshotManagerUrl: region.snapshotManagerUrl,
+      runnerPool: region.runnerPool,
     }
   }
 }
@@ -4,6 +4,9 @@
  */
 
 import { ApiProperty, ApiSchema } from '@nestjs/swagger'
+import { Type } from 'class-transformer'
+import { IsOptional, ValidateNested } from 'class-validator'
+import { RegionRunnerPoolDto } from './region-runner-pool.dto'
 
 @ApiSchema({ name: 'UpdateRegion' })
 export class UpdateRegionDto {
@@ -30,4 +33,15 @@ export class UpdateRegionDto {
     required: false,
   })
   snapshotManagerUrl?: string
+
+  @ApiProperty({
+    description: 'Runner pool configuration expected from the runner-managers of the region',
+    type: RegionRunnerPoolDto,
+    nullable: true,
+    required: false,
+  })
+  @IsOptional()
+  @ValidateNested()
+  @Type(() => RegionRunnerPoolDto)
+  runnerPool?: RegionRunnerPoolDto | null
 }
@@ -17,6 +17,18 @@ import {
 import { nanoid } from 'nanoid'
 import { RegionType } from '../enums/region-type.enum'
 
+// The runner pool configuration runner-managers of the region compare their local configuration with.
+// Unset fields are not managed by the control plane.
+export interface RegionRunnerPool {
+  minIdleRunners?: number
+  minIdleCpu?: number
+  minIdleMemory?: number
+  maxResourceUtilizationPercent?: number
+  nodeSelectorKey?: string
+  taintKey?: string
+  runnerVersion?: string
+}
+
 @Entity()
 @Index('region_organizationId_name_unique', ['organizationId', 'name'], {
   unique: true,
@@ -92,6 +104,12 @@ export class Region {
   @Column({ nullable: true })
   snapshotManagerUrl: string | null
 
+  @Column({
+    type: 'jsonb',
+    nullable: true,
+  })
+  runnerPool: RegionRunnerPool | null
+
   constructor(params: {
     name: string
     enforceQuotas: boolean
@@ -104,6 +122,7 @@ export class Region {
     proxyApiKeyHash?: string | null
     sshGatewayApiKeyHash?: string | null
     snapshotManagerUrl?: string | null
+    runnerPool?: RegionRunnerPool | null
   }) {
     this.name = params.name
     this.enforceQuotas = params.enforceQuotas
@@ -124,6 +143,7 @@ export class Region {
     this.proxyApiKeyHash = params.proxyApiKeyHash ?? null
     this.sshGatewayApiKeyHash = params.sshGatewayApiKeyHash ?? null
     this.snapshotManagerUrl = params.snapshotManagerUrl ?? null
+    this.runnerPool = params.runnerPool ?? null
   }
 
   @BeforeInsert()
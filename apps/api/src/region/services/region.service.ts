@@ -322,6 +322,10 @@ export class RegionService {
         )
       }
 
+      if (updateRegion.runnerPool !== undefined) {
+        region.runnerPool = updateRegion.runnerPool ?? null
+      }
+
       await em.save(region)
     })
   }
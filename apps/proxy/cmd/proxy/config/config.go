@@ -5,7 +5,9 @@ package config
 
 import (
 	"context"
+	"errors"
 	"log"
+	"math"
 	"net/http"
 	"strings"
 	"time"
@@ -18,19 +20,37 @@ import (
 )
 
 type Config struct {
-	ProxyPort             int          `envconfig:"PROXY_PORT" validate:"required"`
-	ProxyProtocol         string       `envconfig:"PROXY_PROTOCOL" validate:"required"`
-	ProxyApiKey           string       `envconfig:"PROXY_API_KEY" validate:"required"`
-	CookieDomain          *string      `envconfig:"COOKIE_DOMAIN"`
-	TLSCertFile           string       `envconfig:"TLS_CERT_FILE"`
-	TLSKeyFile            string       `envconfig:"TLS_KEY_FILE"`
-	EnableTLS             bool         `envconfig:"ENABLE_TLS"`
-	DaytonaApiUrl         string       `envconfig:"DAYTONA_API_URL" validate:"required"`
-	Oidc                  OidcConfig   `envconfig:"OIDC"`
-	Redis                 *RedisConfig `envconfig:"REDIS"`
-	ToolboxOnlyMode       bool         `envconfig:"TOOLBOX_ONLY_MODE"`
-	PreviewWarningEnabled bool         `envconfig:"PREVIEW_WARNING_ENABLED"`
-	ShutdownTimeoutSec    int          `envconfig:"SHUTDOWN_TIMEOUT_SEC"`
+	ProxyPort             int                  `envconfig:"PROXY_PORT" validate:"required"`
+	ProxyProtocol         string               `envconfig:"PROXY_PROTOCOL" validate:"required"`
+	ProxyApiKey           string               `envconfig:"PROXY_API_KEY" validate:"required"`
+	CookieDomain          *string              `envconfig:"COOKIE_DOMAIN"`
+	TLSCertFile           string               `envconfig:"TLS_CERT_FILE"`
+	TLSKeyFile            string               `envconfig:"TLS_KEY_FILE"`
+	EnableTLS             bool                 `envconfig:"ENABLE_TLS"`
+	TLSPassthroughEnabled bool                 `envconfig:"TLS_PASSTHROUGH_ENABLED"`
+	DaytonaApiUrl         string               `envconfig:"DAYTONA_API_URL" validate:"required"`
+	Oidc                  OidcConfig           `envconfig:"OIDC"`
+	Redis                 *RedisConfig         `envconfig:"REDIS"`
+	ToolboxOnlyMode       bool                 `envconfig:"TOOLBOX_ONLY_MODE"`
+	PreviewWarningEnabled bool                 `envconfig:"PREVIEW_WARNING_ENABLED"`
+	EvictionBannerEnabled bool                 `envconfig:"EVICTION_BANNER_ENABLED"`
+	EvictionNoticeToken   string               `envconfig:"EVICTION_NOTICE_TOKEN"`
+	HeartbeatRelayEnabled bool                 `envconfig:"RUNNER_HEARTBEAT_RELAY_ENABLED"`
+	ShutdownTimeoutSec    int                  `envconfig:"SHUTDOWN_TIMEOUT_SEC"`
+	DrainDelaySec         int                  `envconfig:"DRAIN_DELAY_SEC" validate:"gte=0"`
+	UpgradeHandoffEnabled bool                 `envconfig:"UPGRADE_HANDOFF_ENABLED"`
+	SandboxMiddlewares    bool                 `envconfig:"SANDBOX_MIDDLEWARES_ENABLED"`
+	RateLimit             RateLimitConfig      `envconfig:"RATE_LIMIT"`
+	Quota                 QuotaConfig          `envconfig:"QUOTA"`
+	TrafficReport         TrafficReportConfig  `envconfig:"TRAFFIC_REPORT"`
+	AdminAuth             AdminAuthConfig      `envconfig:"ADMIN_AUTH"`
+	Slo                   SloConfig            `envconfig:"SLO"`
+	ShortLink             ShortLinkConfig      `envconfig:"SHORT_LINK"`
+	SignedUrl             SignedUrlConfig      `envconfig:"SIGNED_URL"`
+	Directives            DirectivesConfig     `envconfig:"DIRECTIVES"`
+	Analytics             AnalyticsConfig      `envconfig:"ANALYTICS"`
+	RoutingReplica        RoutingReplicaConfig `envconfig:"ROUTING_REPLICA"`
+	CertExpiry            CertExpiryConfig     `envconfig:"CERT_EXPIRY"`
 	ApiClient             *apiclient.APIClient
 }
 
@@ -49,6 +69,108 @@ type RedisConfig struct {
 	TLS      *bool   `envconfig:"TLS"`
 }
 
+// RateLimitConfig limits the request rate per client IP. Limiting is disabled when RequestsPerSecond is 0.
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `envconfig:"REQUESTS_PER_SECOND" validate:"gte=0"`
+	Burst             int     `envconfig:"BURST" validate:"gte=0"`
+}
+
+// QuotaConfig holds the default organization quotas enforced at the proxy. With PlanLimits the limits set on an
+// organization in the API, its preview bandwidth quota and its plan's preview session limit, take precedence. Enforcement is disabled when all limits are 0 and plan limits are not read.
+type QuotaConfig struct {
+	MaxPreviewBandwidth   int64 `envconfig:"MAX_PREVIEW_BANDWIDTH" validate:"gte=0"`
+	MaxPreviewSessions    int   `envconfig:"MAX_PREVIEW_SESSIONS" validate:"gte=0"`
+	PreviewSessionIdleSec int   `envconfig:"PREVIEW_SESSION_IDLE_SEC" validate:"gte=0"`
+	PlanLimits            bool  `envconfig:"PLAN_LIMITS"`
+}
+
+// TrafficReportConfig configures the per-sandbox traffic reports sent to runner-managers, which keep the nodes hosting
+// high-traffic sandboxes on scale-down. The control plane takes no traffic reports, so sandbox placement does not
+// account for traffic. Reporting is disabled when no runner-manager URL is set, and requires the token runner-managers
+// accept reports with.
+type TrafficReportConfig struct {
+	RunnerManagerUrls []string `envconfig:"RUNNER_MANAGER_URLS" validate:"dive,url"`
+	Token             string   `envconfig:"TOKEN" validate:"required_with=RunnerManagerUrls"`
+	IntervalSec       int      `envconfig:"INTERVAL_SEC" validate:"gte=0"`
+}
+
+// AdminAuthConfig protects the admin endpoints with a static token, client certificates (requires ENABLE_TLS)
+// or control-plane-issued JWTs. Admin endpoints reject every request when no method is configured.
+type AdminAuthConfig struct {
+	Token        string `envconfig:"TOKEN"`
+	ClientCAFile string `envconfig:"CLIENT_CA_FILE"`
+	JwksUrl      string `envconfig:"JWKS_URL" validate:"omitempty,url"`
+	JwtIssuer    string `envconfig:"JWT_ISSUER"`
+	JwtAudience  string `envconfig:"JWT_AUDIENCE"`
+}
+
+// SloConfig enables per-organization upstream latency and availability tracking over a rolling window.
+// Burn alerts are logged and posted to the webhook when the burn rate threshold is set.
+type SloConfig struct {
+	Enabled                bool    `envconfig:"ENABLED"`
+	WindowSec              int     `envconfig:"WINDOW_SEC" validate:"gte=0"`
+	LatencyP95TargetMs     float64 `envconfig:"LATENCY_P95_TARGET_MS" validate:"gte=0"`
+	AvailabilityTarget     float64 `envconfig:"AVAILABILITY_TARGET" validate:"gte=0,lt=1"`
+	BurnRateAlertThreshold float64 `envconfig:"BURN_RATE_ALERT_THRESHOLD" validate:"gte=0"`
+	AlertWebhookUrl        string  `envconfig:"ALERT_WEBHOOK_URL" validate:"omitempty,url"`
+}
+
+// ShortLinkConfig enables minting short aliases for signed preview URLs on the proxy's base domain.
+// Aliases expire with the signed URL, but never later than MaxTtlSec.
+type ShortLinkConfig struct {
+	Enabled   bool `envconfig:"ENABLED"`
+	MaxTtlSec int  `envconfig:"MAX_TTL_SEC" validate:"gte=0"`
+}
+
+// DirectivesConfig configures polling the control plane for emergency directives, which can also be pushed to the
+// admin endpoint. Every applied or lifted directive is appended to AuditLogFile when set.
+type DirectivesConfig struct {
+	Url             string `envconfig:"URL" validate:"omitempty,url"`
+	Token           string `envconfig:"TOKEN"`
+	PollIntervalSec int    `envconfig:"POLL_INTERVAL_SEC" validate:"gte=0"`
+	AuditLogFile    string `envconfig:"AUDIT_LOG_FILE"`
+}
+
+// SignedUrlConfig enables preview URLs signed with per-organization secrets derived from Secret. URLs expiring
+// more than MaxTtlSec in the future are rejected so a leaked secret cannot mint permanent links.
+type SignedUrlConfig struct {
+	Secret    string `envconfig:"SECRET"`
+	MaxTtlSec int    `envconfig:"MAX_TTL_SEC" validate:"gte=0"`
+}
+
+// AnalyticsConfig enables differentially private preview usage reports, posted to ReportUrls every IntervalSec and
+// served on the admin endpoint. A lower Epsilon adds more noise, clients contribute at most MaxSessionsPerClient
+// sessions to a report.
+type AnalyticsConfig struct {
+	Enabled              bool     `envconfig:"ENABLED"`
+	ReportUrls           []string `envconfig:"REPORT_URLS" validate:"dive,url"`
+	Token                string   `envconfig:"TOKEN"`
+	IntervalSec          int      `envconfig:"INTERVAL_SEC" validate:"gte=0"`
+	Epsilon              float64  `envconfig:"EPSILON" validate:"gte=0"`
+	SessionIdleSec       int      `envconfig:"SESSION_IDLE_SEC" validate:"gte=0"`
+	MaxSessionsPerClient int      `envconfig:"MAX_SESSIONS_PER_CLIENT" validate:"gte=0"`
+}
+
+// RoutingReplicaConfig keeps a copy of the routing and visibility data of recently used sandboxes in File, which
+// lookups are served from when not cached. Entries are synced every SyncIntervalSec at no more than
+// SyncRequestsPerSecond and dropped once unused for RetentionSec.
+type RoutingReplicaConfig struct {
+	File                  string `envconfig:"FILE"`
+	SyncIntervalSec       int    `envconfig:"SYNC_INTERVAL_SEC" validate:"gte=0"`
+	SyncRequestsPerSecond int    `envconfig:"SYNC_REQUESTS_PER_SECOND" validate:"gte=0"`
+	RetentionSec          int    `envconfig:"RETENTION_SEC" validate:"gte=0"`
+}
+
+// CertExpiryConfig configures the expiry monitoring of the loaded certificates, checked every CheckIntervalSec.
+// An alert is logged and posted to AlertWebhookUrl when a certificate crosses each of AlertDays. RenewCommand is run
+// by the admin endpoint forcing a renewal, e.g. the ACME client managing TLS_CERT_FILE, before the files are reloaded.
+type CertExpiryConfig struct {
+	CheckIntervalSec int    `envconfig:"CHECK_INTERVAL_SEC" validate:"gte=0"`
+	AlertDays        []int  `envconfig:"ALERT_DAYS" validate:"dive,gt=0"`
+	AlertWebhookUrl  string `envconfig:"ALERT_WEBHOOK_URL" validate:"omitempty,url"`
+	RenewCommand     string `envconfig:"RENEW_COMMAND"`
+}
+
 var DEFAULT_PROXY_PORT int = 4000
 
 var config *Config
@@ -78,6 +200,14 @@ func GetConfig() (*Config, error) {
 		return nil, err
 	}
 
+	if config.AdminAuth.ClientCAFile != "" && !config.EnableTLS {
+		return nil, errors.New("ADMIN_AUTH_CLIENT_CA_FILE requires ENABLE_TLS")
+	}
+
+	if config.TLSPassthroughEnabled && !config.EnableTLS {
+		return nil, errors.New("TLS_PASSTHROUGH_ENABLED requires ENABLE_TLS")
+	}
+
 	if config.ProxyPort == 0 {
 		config.ProxyPort = DEFAULT_PROXY_PORT
 	}
@@ -86,6 +216,74 @@ func GetConfig() (*Config, error) {
 		config.ShutdownTimeoutSec = 60 * 60 // default to 1 hour
 	}
 
+	if config.RateLimit.RequestsPerSecond > 0 && config.RateLimit.Burst == 0 {
+		config.RateLimit.Burst = int(math.Ceil(config.RateLimit.RequestsPerSecond))
+	}
+
+	if config.TrafficReport.IntervalSec == 0 {
+		config.TrafficReport.IntervalSec = 60
+	}
+
+	if config.Quota.PreviewSessionIdleSec == 0 {
+		config.Quota.PreviewSessionIdleSec = 5 * 60
+	}
+
+	if config.Slo.WindowSec == 0 {
+		config.Slo.WindowSec = 60 * 60
+	}
+
+	if config.Slo.AvailabilityTarget == 0 {
+		config.Slo.AvailabilityTarget = 0.999
+	}
+
+	if config.ShortLink.MaxTtlSec == 0 {
+		config.ShortLink.MaxTtlSec = 24 * 60 * 60
+	}
+
+	if config.Directives.PollIntervalSec == 0 {
+		config.Directives.PollIntervalSec = 5
+	}
+
+	if config.Analytics.IntervalSec == 0 {
+		config.Analytics.IntervalSec = 60 * 60
+	}
+
+	if config.Analytics.Epsilon == 0 {
+		config.Analytics.Epsilon = 1
+	}
+
+	if config.Analytics.SessionIdleSec == 0 {
+		config.Analytics.SessionIdleSec = 30 * 60
+	}
+
+	if config.Analytics.MaxSessionsPerClient == 0 {
+		config.Analytics.MaxSessionsPerClient = 4
+	}
+
+	if config.RoutingReplica.SyncIntervalSec == 0 {
+		config.RoutingReplica.SyncIntervalSec = 60
+	}
+
+	if config.RoutingReplica.SyncRequestsPerSecond == 0 {
+		config.RoutingReplica.SyncRequestsPerSecond = 20
+	}
+
+	if config.RoutingReplica.RetentionSec == 0 {
+		config.RoutingReplica.RetentionSec = 24 * 60 * 60
+	}
+
+	if config.CertExpiry.CheckIntervalSec == 0 {
+		config.CertExpiry.CheckIntervalSec = 60 * 60
+	}
+
+	if len(config.CertExpiry.AlertDays) == 0 {
+		config.CertExpiry.AlertDays = []int{30, 14, 7, 1}
+	}
+
+	if config.SignedUrl.MaxTtlSec == 0 {
+		config.SignedUrl.MaxTtlSec = 7 * 24 * 60 * 60
+	}
+
 	if config.Redis != nil {
 		if config.Redis.Host == nil || *config.Redis.Host == "" {
 			config.Redis = nil
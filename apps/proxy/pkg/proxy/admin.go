@@ -0,0 +1,14 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// adminHandler guards an admin endpoint with the admin authenticator, rejecting every request when no method is
+// configured
+func (p *Proxy) adminHandler(handler gin.HandlerFunc) gin.HandlerFunc {
+	return p.adminAuth.GinHandler(handler)
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	common_analytics "github.com/daytonaio/common-go/pkg/analytics"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// analyticsReporter holds the most recent analytics report, the only usage data that leaves the aggregator
+type analyticsReporter struct {
+	mu     sync.Mutex
+	latest *common_analytics.Report
+}
+
+// recordAnalytics accounts the preview request to the usage analytics. Clients are identified by their preview
+// session, or by address and user agent when they carry none, and the aggregator only keeps salted hashes of either.
+func (p *Proxy) recordAnalytics(ctx *gin.Context) {
+	clientId := ctx.ClientIP() + "|" + ctx.Request.UserAgent()
+	if sessionId, err := ctx.Cookie(PREVIEW_SESSION_COOKIE_NAME); err == nil && sessionId != "" {
+		clientId = sessionId
+	}
+
+	p.analyticsAggregator.Record(clientId, time.Now())
+}
+
+// runAnalyticsReporter periodically flushes the usage analytics into a report and posts it to the configured URLs
+func (p *Proxy) runAnalyticsReporter(ctx context.Context) {
+	source := sloReportSource()
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	ticker := time.NewTicker(time.Duration(p.config.Analytics.IntervalSec) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			report := p.analyticsAggregator.Flush(source, now)
+
+			p.analyticsReporter.mu.Lock()
+			p.analyticsReporter.latest = report
+			p.analyticsReporter.mu.Unlock()
+
+			for _, url := range p.config.Analytics.ReportUrls {
+				if err := p.sendAnalyticsReport(ctx, httpClient, url, report); err != nil {
+					log.WithField("url", url).WithError(err).Warn("Failed to send analytics report")
+				}
+			}
+		}
+	}
+}
+
+func (p *Proxy) sendAnalyticsReport(ctx context.Context, httpClient *http.Client, url string, report *common_analytics.Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.config.Analytics.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.Analytics.Token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("analytics endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// handleAnalyticsReport serves the most recent analytics report, the current window is never exposed
+func (p *Proxy) handleAnalyticsReport(ctx *gin.Context) {
+	p.analyticsReporter.mu.Lock()
+	report := p.analyticsReporter.latest
+	p.analyticsReporter.mu.Unlock()
+
+	if report == nil {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
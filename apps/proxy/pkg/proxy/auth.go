@@ -12,6 +12,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
+
+	common_signedurl "github.com/daytonaio/common-go/pkg/signedurl"
 )
 
 func (p *Proxy) Authenticate(ctx *gin.Context, sandboxIdOrSignedToken string, port float32) (sandboxId string, didRedirect bool, err error) {
@@ -102,6 +104,24 @@ func (p *Proxy) Authenticate(ctx *gin.Context, sandboxIdOrSignedToken string, po
 		}
 	}
 
+	// Try HMAC-signed URL
+	if p.config.SignedUrl.Secret != "" && ctx.Query(common_signedurl.SignatureParam) != "" {
+		startTime := time.Now()
+		err := p.authenticateSignedUrl(ctx, sandboxIdOrSignedToken, port)
+		duration := time.Since(startTime)
+		if err == nil {
+			log.WithField("sandboxId", sandboxIdOrSignedToken).
+				WithField("duration", duration).
+				Info("Signed URL validation successful")
+			return sandboxIdOrSignedToken, false, nil
+		}
+		log.WithField("sandboxId", sandboxIdOrSignedToken).
+			WithField("duration", duration).
+			WithError(err).
+			Warn("Signed URL is invalid")
+		authErrors = append(authErrors, fmt.Sprintf("Signed URL is invalid: %v", err))
+	}
+
 	// Try cookie authentication
 	cookieName := SANDBOX_AUTH_COOKIE_NAME + sandboxIdOrSignedToken
 	cookieValue, err := ctx.Cookie(cookieName)
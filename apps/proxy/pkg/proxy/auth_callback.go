@@ -134,7 +134,11 @@ func (p *Proxy) getAuthUrl(ctx *gin.Context, sandboxId string) (string, error) {
 
 	_, _, baseHost, err := p.parseHost(ctx.Request.Host)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse request host: %w", err)
+		// Bare sandbox domains used for the port directory have no port prefix
+		_, baseHost, err = parseBareSandboxHost(ctx.Request.Host)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse request host: %w", err)
+		}
 	}
 
 	oauth2Config := oauth2.Config{
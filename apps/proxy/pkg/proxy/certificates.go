@@ -0,0 +1,326 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	CERTIFICATES_PATH       = "/certificates"
+	CERTIFICATES_RENEW_PATH = "/certificates/renew"
+
+	certificateRenewTimeout   = 5 * time.Minute
+	certificateRenewMaxOutput = 16 * 1024
+)
+
+var (
+	certificateExpiryDays = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "proxy_tls_certificate_expiry_days",
+			Help: "Days until a loaded certificate expires, negative once expired",
+		},
+		[]string{"certificate", "subject"},
+	)
+	certificateExpiryAlerts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "proxy_tls_certificate_expiry_alerts_total",
+			Help: "Number of certificate expiry alerts raised",
+		},
+		[]string{"certificate"},
+	)
+)
+
+// certificateInfo describes a loaded certificate. Name is the role of the certificate: the serving certificate, an
+// intermediate of its chain or a CA trusted for admin client certificates.
+type certificateInfo struct {
+	Name         string    `json:"name"`
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	DNSNames     []string  `json:"dnsNames,omitempty"`
+	SerialNumber string    `json:"serialNumber"`
+	NotAfter     time.Time `json:"notAfter"`
+	DaysToExpiry float64   `json:"daysToExpiry"`
+}
+
+// certificateExpiryAlert is the payload posted to the alert webhook
+type certificateExpiryAlert struct {
+	Source        string          `json:"source"`
+	Certificate   certificateInfo `json:"certificate"`
+	ThresholdDays int             `json:"thresholdDays"`
+}
+
+// certificateMonitor serves the proxy's certificate, reloading it when its files change, and tracks the expiry of
+// all loaded certificates
+type certificateMonitor struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	mu       sync.RWMutex
+	serving  *tls.Certificate
+	loadedAt time.Time
+
+	// alerted holds the thresholds already alerted on by certificate serial number
+	alerted map[string]int
+}
+
+func newCertificateMonitor(certFile, keyFile, clientCAFile string) (*certificateMonitor, error) {
+	m := &certificateMonitor{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+		alerted:      make(map[string]int),
+	}
+
+	if err := m.reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// getCertificate is the server's tls.Config.GetCertificate
+func (m *certificateMonitor) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.serving, nil
+}
+
+// reload loads the serving certificate from its files, the previous one is kept when they are invalid
+func (m *certificateMonitor) reload() error {
+	loadedAt := time.Now()
+	certificate, err := tls.LoadX509KeyPair(m.certFile, m.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.serving = &certificate
+	m.loadedAt = loadedAt
+	return nil
+}
+
+// reloadIfChanged reloads the serving certificate when its files were modified since it was loaded, picking up
+// renewals made by an external ACME client or cert-manager without a restart
+func (m *certificateMonitor) reloadIfChanged() {
+	m.mu.RLock()
+	loadedAt := m.loadedAt
+	m.mu.RUnlock()
+
+	changed := false
+	for _, file := range []string{m.certFile, m.keyFile} {
+		if info, err := os.Stat(file); err == nil && info.ModTime().After(loadedAt) {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	if err := m.reload(); err != nil {
+		log.WithError(err).Warn("Certificate files changed but could not be reloaded, keeping the current certificate")
+		return
+	}
+	log.Info("Reloaded TLS certificate")
+}
+
+// certificates returns the serving certificate with its chain and the admin client CAs
+func (m *certificateMonitor) certificates() ([]certificateInfo, error) {
+	m.mu.RLock()
+	serving := m.serving
+	m.mu.RUnlock()
+
+	var certificates []certificateInfo
+	for i, der := range serving.Certificate {
+		certificate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse serving certificate: %w", err)
+		}
+		name := "serving"
+		if i > 0 {
+			name = "serving-chain"
+		}
+		certificates = append(certificates, newCertificateInfo(name, certificate))
+	}
+
+	if m.clientCAFile != "" {
+		data, err := os.ReadFile(m.clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read admin client CA file: %w", err)
+		}
+		for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			certificate, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse admin client CA: %w", err)
+			}
+			certificates = append(certificates, newCertificateInfo("admin-client-ca", certificate))
+		}
+	}
+
+	return certificates, nil
+}
+
+func newCertificateInfo(name string, certificate *x509.Certificate) certificateInfo {
+	return certificateInfo{
+		Name:         name,
+		Subject:      certificate.Subject.String(),
+		Issuer:       certificate.Issuer.String(),
+		DNSNames:     certificate.DNSNames,
+		SerialNumber: certificate.SerialNumber.Text(16),
+		NotAfter:     certificate.NotAfter,
+		DaysToExpiry: time.Until(certificate.NotAfter).Hours() / 24,
+	}
+}
+
+// runCertificateMonitor checks the certificates every check interval until the context is done
+func (p *Proxy) runCertificateMonitor(ctx context.Context) {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	ticker := time.NewTicker(time.Duration(p.config.CertExpiry.CheckIntervalSec) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		p.certificates.reloadIfChanged()
+		p.checkCertificateExpiry(ctx, httpClient)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkCertificateExpiry publishes the expiry metrics and raises an alert whenever a certificate crosses one of the
+// alert thresholds. A certificate already past several thresholds, e.g. when the proxy starts, is alerted once.
+func (p *Proxy) checkCertificateExpiry(ctx context.Context, httpClient *http.Client) {
+	certificates, err := p.certificates.certificates()
+	if err != nil {
+		log.WithError(err).Warn("Failed to check certificate expiry")
+		return
+	}
+
+	thresholds := slices.Clone(p.config.CertExpiry.AlertDays)
+	slices.Sort(thresholds)
+
+	certificateExpiryDays.Reset()
+	for _, certificate := range certificates {
+		certificateExpiryDays.WithLabelValues(certificate.Name, certificate.Subject).Set(certificate.DaysToExpiry)
+
+		crossed := slices.IndexFunc(thresholds, func(days int) bool { return certificate.DaysToExpiry <= float64(days) })
+		if crossed == -1 {
+			continue
+		}
+		threshold := thresholds[crossed]
+		if alerted, found := p.certificates.alerted[certificate.SerialNumber]; found && alerted <= threshold {
+			continue
+		}
+		p.certificates.alerted[certificate.SerialNumber] = threshold
+
+		log.WithFields(log.Fields{
+			"certificate":  certificate.Name,
+			"subject":      certificate.Subject,
+			"notAfter":     certificate.NotAfter,
+			"daysToExpiry": int(certificate.DaysToExpiry),
+		}).Warnf("Certificate expires within %d days", threshold)
+		certificateExpiryAlerts.WithLabelValues(certificate.Name).Inc()
+
+		if p.config.CertExpiry.AlertWebhookUrl != "" {
+			alert := certificateExpiryAlert{Source: sloReportSource(), Certificate: certificate, ThresholdDays: threshold}
+			if err := sendCertificateExpiryAlert(ctx, httpClient, p.config.CertExpiry.AlertWebhookUrl, alert); err != nil {
+				log.WithField("certificate", certificate.Name).WithError(err).Warn("Failed to send certificate expiry alert")
+			}
+		}
+	}
+}
+
+// sendCertificateExpiryAlert posts an expiry alert to the alert webhook
+func sendCertificateExpiryAlert(ctx context.Context, httpClient *http.Client, url string, alert certificateExpiryAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (p *Proxy) handleCertificates(ctx *gin.Context) {
+	certificates, err := p.certificates.certificates()
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"certificates": certificates})
+}
+
+// handleCertificateRenewal forces a renewal by running the renew command, then reloads the serving certificate
+func (p *Proxy) handleCertificateRenewal(ctx *gin.Context) {
+	if p.config.CertExpiry.RenewCommand == "" {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "no renew command configured"})
+		return
+	}
+
+	renewCtx, cancel := context.WithTimeout(ctx.Request.Context(), certificateRenewTimeout)
+	defer cancel()
+
+	log.Info("Forcing certificate renewal")
+	output, err := exec.CommandContext(renewCtx, "sh", "-c", p.config.CertExpiry.RenewCommand).CombinedOutput()
+	if len(output) > certificateRenewMaxOutput {
+		output = output[len(output)-certificateRenewMaxOutput:]
+	}
+	if err != nil {
+		log.WithError(err).Warn("Certificate renewal failed")
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("renew command failed: %v", err), "output": string(output)})
+		return
+	}
+
+	if err := p.certificates.reload(); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "output": string(output)})
+		return
+	}
+
+	certificates, err := p.certificates.certificates()
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	log.Info("Certificate renewal completed, reloaded TLS certificate")
+	ctx.JSON(http.StatusOK, gin.H{"certificates": certificates, "output": string(output)})
+}
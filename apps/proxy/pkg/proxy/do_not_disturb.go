@@ -0,0 +1,59 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	common_protection "github.com/daytonaio/common-go/pkg/protection"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// applyDoNotDisturbHeader surfaces the sandbox's do-not-disturb flag on the response
+func (p *Proxy) applyDoNotDisturbHeader(ctx *gin.Context, sandboxId string) {
+	doNotDisturb, err := p.getSandboxDoNotDisturb(ctx.Request.Context(), sandboxId)
+	if err != nil {
+		log.WithField("sandboxId", sandboxId).WithError(err).Debug("Failed to resolve sandbox do-not-disturb flag")
+		return
+	}
+
+	if *doNotDisturb {
+		ctx.Header(common_protection.DoNotDisturbHeader, "true")
+	}
+}
+
+func (p *Proxy) getSandboxDoNotDisturb(ctx context.Context, sandboxId string) (*bool, error) {
+	has, err := p.sandboxDoNotDisturbCache.Has(ctx, sandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	if has {
+		return p.sandboxDoNotDisturbCache.Get(ctx, sandboxId)
+	}
+
+	organizationId, err := p.getSandboxOrganizationId(ctx, sandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	sandbox, _, err := p.apiclient.SandboxAPI.GetSandbox(ctx, sandboxId).XDaytonaOrganizationID(organizationId).Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	doNotDisturb := common_protection.IsDoNotDisturb(sandbox.Labels)
+
+	// Short TTL so toggling the label through the API is reflected quickly
+	err = p.sandboxDoNotDisturbCache.Set(ctx, sandboxId, doNotDisturb, 1*time.Minute)
+	if err != nil {
+		log.Errorf("Failed to set sandbox do-not-disturb in cache: %v", err)
+	}
+
+	return &doNotDisturb, nil
+}
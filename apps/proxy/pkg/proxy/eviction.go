@@ -0,0 +1,143 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	common_eviction "github.com/daytonaio/common-go/pkg/eviction"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	EVICTION_AT_KEY = "daytona-eviction-at"
+
+	// Larger HTML documents are passed through without the banner
+	EVICTION_BANNER_MAX_BODY_SIZE = 5 << 20
+)
+
+// evictionNoticeHandler authenticates the eviction notices posted by runner-manager with its EVICTION_NOTICE_TOKEN,
+// or admin credentials otherwise. Notices are rejected when neither is configured.
+func (p *Proxy) evictionNoticeHandler() gin.HandlerFunc {
+	admin := p.adminHandler(p.receiveEvictionNotices)
+	return func(ctx *gin.Context) {
+		token := p.config.EvictionNoticeToken
+		if token != "" && subtle.ConstantTimeCompare([]byte(ctx.GetHeader("Authorization")), []byte("Bearer "+token)) == 1 {
+			p.receiveEvictionNotices(ctx)
+			return
+		}
+		admin(ctx)
+	}
+}
+
+// receiveEvictionNotices stores the eviction notices posted by runner-manager
+func (p *Proxy) receiveEvictionNotices(ctx *gin.Context) {
+	var batch common_eviction.NoticeBatch
+	if err := json.NewDecoder(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, 10<<20)).Decode(&batch); err != nil {
+		ctx.String(http.StatusBadRequest, "invalid eviction notices: %v", err)
+		return
+	}
+
+	for _, notice := range batch.Notices {
+		if notice.SandboxId == "" {
+			continue
+		}
+		err := p.sandboxEvictionCache.Set(ctx, notice.SandboxId, notice.EvictionAt, common_eviction.NoticeTTL)
+		if err != nil {
+			log.WithField("sandboxId", notice.SandboxId).WithError(err).Error("Failed to store eviction notice")
+		}
+	}
+
+	log.WithField("source", batch.Source).Debugf("Received %d eviction notices", len(batch.Notices))
+	ctx.Status(http.StatusAccepted)
+}
+
+// applyEvictionNotice surfaces a scheduled removal of the sandbox's node on the response
+func (p *Proxy) applyEvictionNotice(ctx *gin.Context, sandboxId string) {
+	evictionAt, err := p.getSandboxEvictionAt(ctx, sandboxId)
+	if err != nil || evictionAt == nil {
+		return
+	}
+
+	ctx.Header(common_eviction.EvictionAtHeader, evictionAt.UTC().Format(time.RFC3339))
+	ctx.Set(EVICTION_AT_KEY, *evictionAt)
+}
+
+func (p *Proxy) getSandboxEvictionAt(ctx context.Context, sandboxId string) (*time.Time, error) {
+	has, err := p.sandboxEvictionCache.Has(ctx, sandboxId)
+	if err != nil || !has {
+		return nil, err
+	}
+
+	return p.sandboxEvictionCache.Get(ctx, sandboxId)
+}
+
+// injectEvictionBanner adds a banner announcing the eviction to uncompressed HTML responses
+func (p *Proxy) injectEvictionBanner(ctx *gin.Context, res *http.Response) error {
+	value, found := ctx.Get(EVICTION_AT_KEY)
+	if !found {
+		return nil
+	}
+	evictionAt, ok := value.(time.Time)
+	if !ok {
+		return nil
+	}
+
+	if !strings.HasPrefix(res.Header.Get("Content-Type"), "text/html") || res.Header.Get("Content-Encoding") != "" {
+		return nil
+	}
+	if res.ContentLength > EVICTION_BANNER_MAX_BODY_SIZE {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, EVICTION_BANNER_MAX_BODY_SIZE+1))
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	if len(body) <= EVICTION_BANNER_MAX_BODY_SIZE {
+		body = insertEvictionBanner(body, evictionAt)
+	}
+
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	res.ContentLength = int64(len(body))
+	res.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	return nil
+}
+
+// insertEvictionBanner places the banner right after the opening body tag, or at the start of the document
+func insertEvictionBanner(body []byte, evictionAt time.Time) []byte {
+	banner := fmt.Sprintf(
+		`<div style="position:sticky;top:0;z-index:2147483647;padding:8px 12px;background:#fef3c7;color:#78350f;font:14px sans-serif;text-align:center">`+
+			`This sandbox's host is scheduled for maintenance at %s. Save your work, the sandbox may be restarted.</div>`,
+		html.EscapeString(evictionAt.UTC().Format(time.RFC1123)),
+	)
+
+	lower := bytes.ToLower(body)
+	insertAt := 0
+	if bodyStart := bytes.Index(lower, []byte("<body")); bodyStart >= 0 {
+		if tagEnd := bytes.IndexByte(lower[bodyStart:], '>'); tagEnd >= 0 {
+			insertAt = bodyStart + tagEnd + 1
+		}
+	}
+
+	result := make([]byte, 0, len(body)+len(banner))
+	result = append(result, body[:insertAt]...)
+	result = append(result, banner...)
+	return append(result, body[insertAt:]...)
+}
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	common_directive "github.com/daytonaio/common-go/pkg/directive"
 	common_errors "github.com/daytonaio/common-go/pkg/errors"
 	"github.com/gin-gonic/gin"
 
@@ -78,12 +79,46 @@ func (p *Proxy) GetProxyTarget(ctx *gin.Context, toolboxSubpathRequest bool) (*u
 		}
 	}
 
+	if !toolboxSubpathRequest {
+		if directive := p.directives.Active(common_directive.KindBlockSandboxPreview, sandboxId); directive != nil {
+			ctx.Error(common_errors.NewCustomError(http.StatusForbidden, "preview traffic of this sandbox is blocked", "SANDBOX_PREVIEW_BLOCKED"))
+			return nil, nil, fmt.Errorf("sandbox preview blocked by directive %s", directive.Id)
+		}
+	}
+
 	runnerInfo, err := p.getSandboxRunnerInfo(ctx, sandboxId)
 	if err != nil {
 		ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("failed to get runner info: %w", err)))
 		return nil, nil, fmt.Errorf("failed to get runner info: %w", err)
 	}
 
+	if p.quotaEnforcer != nil && !toolboxSubpathRequest {
+		if err := p.applyOrgQuota(ctx, sandboxId); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if !toolboxSubpathRequest {
+		p.applyDoNotDisturbHeader(ctx, sandboxId)
+		p.applyEvictionNotice(ctx, sandboxId)
+	}
+
+	if p.config.SandboxMiddlewares && !toolboxSubpathRequest {
+		p.attachMiddlewareChain(ctx, sandboxId)
+	}
+
+	if p.trafficRecorder != nil && !toolboxSubpathRequest {
+		p.recordTraffic(ctx, sandboxId, runnerInfo.ApiUrl)
+	}
+
+	if p.analyticsAggregator != nil && !toolboxSubpathRequest {
+		p.recordAnalytics(ctx)
+	}
+
+	if p.sloTracker != nil && !toolboxSubpathRequest {
+		p.recordSlo(ctx, sandboxId)
+	}
+
 	// Skip last activity update if header is set
 	if ctx.Request.Header.Get(SKIP_LAST_ACTIVITY_UPDATE_HEADER) != "true" {
 		doneCh := make(chan struct{})
@@ -127,6 +162,12 @@ func (p *Proxy) getSandboxRunnerInfo(ctx context.Context, sandboxId string) (*Ru
 		return p.sandboxRunnerCache.Get(ctx, sandboxId)
 	}
 
+	if p.routingReplica != nil {
+		if info, ok := p.routingReplica.runner(sandboxId); ok {
+			return info, nil
+		}
+	}
+
 	runner, _, err := p.apiclient.RunnersAPI.GetRunnerBySandboxId(context.Background(), sandboxId).Execute()
 	if err != nil {
 		return nil, err
@@ -146,6 +187,12 @@ func (p *Proxy) getSandboxRunnerInfo(ctx context.Context, sandboxId string) (*Ru
 		log.Errorf("Failed to set runner info in cache: %v", err)
 	}
 
+	if p.routingReplica != nil {
+		p.routingReplica.update(sandboxId, func(entry *routingEntry) {
+			entry.Runner = &info
+		})
+	}
+
 	return &info, nil
 }
 
@@ -159,6 +206,12 @@ func (p *Proxy) getSandboxPublic(ctx context.Context, sandboxId string) (*bool,
 		return p.sandboxPublicCache.Get(ctx, sandboxId)
 	}
 
+	if p.routingReplica != nil {
+		if isPublic, ok := p.routingReplica.public(sandboxId); ok {
+			return isPublic, nil
+		}
+	}
+
 	isPublic := false
 	_, resp, _ := p.apiclient.PreviewAPI.IsSandboxPublic(context.Background(), sandboxId).Execute()
 	if resp != nil && resp.StatusCode == http.StatusOK {
@@ -170,6 +223,13 @@ func (p *Proxy) getSandboxPublic(ctx context.Context, sandboxId string) (*bool,
 		log.Errorf("Failed to set sandbox public in cache: %v", err)
 	}
 
+	// Only a definite answer is replicated, a failed check would otherwise keep the sandbox private until synced
+	if p.routingReplica != nil && resp != nil {
+		p.routingReplica.update(sandboxId, func(entry *routingEntry) {
+			entry.Public = &isPublic
+		})
+	}
+
 	return &isPublic, nil
 }
 
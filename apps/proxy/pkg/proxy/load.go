@@ -0,0 +1,209 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// LOAD_PATH serves the current load as JSON for autoscalers that cannot query Prometheus, e.g. the KEDA metrics-api scaler
+	LOAD_PATH = "/load"
+
+	// loadSampleInterval is the window the per-second rates of the load report are computed over
+	loadSampleInterval = 10 * time.Second
+)
+
+var (
+	openConnections = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "proxy_open_connections",
+			Help: "Number of open client connections, WebSocket connections count as in-flight requests once upgraded",
+		},
+	)
+
+	inFlightRequests = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "proxy_in_flight_requests",
+			Help: "Number of requests being served, including open WebSocket sessions",
+		},
+	)
+
+	requestsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "proxy_requests_total",
+			Help: "Total number of requests received",
+		},
+	)
+
+	tlsHandshakeDuration = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "proxy_tls_handshake_seconds",
+			Help:    "Time spent in TLS handshakes with clients, dominated by the handshake's asymmetric cryptography",
+			Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		},
+	)
+
+	draining = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "proxy_draining",
+			Help: "1 while the proxy drains before shutting down, 0 otherwise",
+		},
+	)
+)
+
+// loadReport is the current load of the proxy instance
+type loadReport struct {
+	OpenConnections   int64   `json:"openConnections"`
+	InFlightRequests  int64   `json:"inFlightRequests"`
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	// TlsHandshakesPerSecond and TlsHandshakeSecondsPerSecond approximate the CPU spent on TLS handshakes
+	TlsHandshakesPerSecond       float64 `json:"tlsHandshakesPerSecond"`
+	TlsHandshakeSecondsPerSecond float64 `json:"tlsHandshakeSecondsPerSecond"`
+	Draining                     bool    `json:"draining"`
+}
+
+// loadTracker measures the load signals the proxy tier is autoscaled on
+type loadTracker struct {
+	connections    atomic.Int64
+	inFlight       atomic.Int64
+	requests       atomic.Int64
+	handshakes     atomic.Int64
+	handshakeNanos atomic.Int64
+	draining       atomic.Bool
+
+	mu    sync.Mutex
+	rates loadReport
+}
+
+func newLoadTracker() *loadTracker {
+	return &loadTracker{}
+}
+
+// trackConnState counts open connections, passed as the server's ConnState hook
+func (t *loadTracker) trackConnState(_ net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		t.connections.Add(1)
+		openConnections.Inc()
+	case http.StateHijacked, http.StateClosed:
+		// Hijacked connections are no longer tracked by the server, the request they belong to stays in flight
+		t.connections.Add(-1)
+		openConnections.Dec()
+	}
+}
+
+func (t *loadTracker) requestStarted() {
+	t.requests.Add(1)
+	t.inFlight.Add(1)
+	requestsTotal.Inc()
+	inFlightRequests.Inc()
+}
+
+func (t *loadTracker) requestFinished() {
+	t.inFlight.Add(-1)
+	inFlightRequests.Dec()
+}
+
+func (t *loadTracker) observeHandshake(duration time.Duration) {
+	t.handshakes.Add(1)
+	t.handshakeNanos.Add(int64(duration))
+	tlsHandshakeDuration.Observe(duration.Seconds())
+}
+
+func (t *loadTracker) startDraining() {
+	t.draining.Store(true)
+	draining.Set(1)
+}
+
+func (t *loadTracker) isDraining() bool {
+	return t.draining.Load()
+}
+
+// instrumentTLS times the handshakes of the server's TLS config. The certificates and protocols must be set on the
+// config before serving, since every handshake runs on a copy of it.
+func (t *loadTracker) instrumentTLS(tlsConfig *tls.Config) {
+	base := tlsConfig.Clone()
+	tlsConfig.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		start := time.Now()
+		handshakeConfig := base.Clone()
+		handshakeConfig.VerifyConnection = func(tls.ConnectionState) error {
+			t.observeHandshake(time.Since(start))
+			return nil
+		}
+		return handshakeConfig, nil
+	}
+}
+
+// run computes the per-second rates of the load report every sample interval until the context is done
+func (t *loadTracker) run(ctx context.Context) {
+	ticker := time.NewTicker(loadSampleInterval)
+	defer ticker.Stop()
+
+	lastRequests, lastHandshakes, lastHandshakeNanos := t.requests.Load(), t.handshakes.Load(), t.handshakeNanos.Load()
+	lastSample := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			requests, handshakes, handshakeNanos := t.requests.Load(), t.handshakes.Load(), t.handshakeNanos.Load()
+			elapsed := now.Sub(lastSample).Seconds()
+
+			t.mu.Lock()
+			t.rates.RequestsPerSecond = float64(requests-lastRequests) / elapsed
+			t.rates.TlsHandshakesPerSecond = float64(handshakes-lastHandshakes) / elapsed
+			t.rates.TlsHandshakeSecondsPerSecond = time.Duration(handshakeNanos-lastHandshakeNanos).Seconds() / elapsed
+			t.mu.Unlock()
+
+			lastRequests, lastHandshakes, lastHandshakeNanos, lastSample = requests, handshakes, handshakeNanos, now
+		}
+	}
+}
+
+func (t *loadTracker) report() loadReport {
+	t.mu.Lock()
+	report := t.rates
+	t.mu.Unlock()
+
+	report.OpenConnections = t.connections.Load()
+	report.InFlightRequests = t.inFlight.Load()
+	report.Draining = t.isDraining()
+	return report
+}
+
+// drain takes the instance out of rotation before shutdown. The health check fails and keep-alive connections are
+// closed after their current request for the configured delay, so load balancers and clients move to other instances
+// while this one still serves them.
+func (p *Proxy) drain(httpServer *http.Server) {
+	p.load.startDraining()
+	httpServer.SetKeepAlivesEnabled(false)
+
+	if p.config.DrainDelaySec <= 0 {
+		return
+	}
+
+	delay := time.Duration(p.config.DrainDelaySec) * time.Second
+	log.WithField("delay", delay).WithField("inFlightRequests", p.load.inFlight.Load()).Info("Draining before shutdown")
+	time.Sleep(delay)
+	log.WithField("inFlightRequests", p.load.inFlight.Load()).Info("Drain delay elapsed, shutting down")
+}
+
+// handleLoadReport serves the current load of the instance
+func (p *Proxy) handleLoadReport(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, p.load.report())
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+var sandboxIdPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+var portDirectoryTemplate = template.Must(template.New("ports").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Sandbox {{.SandboxId}}</title>
+<style>
+body { font-family: sans-serif; max-width: 640px; margin: 48px auto; padding: 0 16px; color: #111827; }
+li { margin: 8px 0; }
+.muted { color: #6b7280; }
+</style>
+</head>
+<body>
+<h1>Sandbox {{.SandboxId}}</h1>
+{{if .Ports}}
+<p>The following ports are currently listening:</p>
+<ul>
+{{range .Ports}}<li><a href="{{.Url}}">{{.Port}}</a></li>
+{{end}}</ul>
+{{else}}
+<p class="muted">No ports are currently listening in this sandbox.</p>
+{{end}}
+</body>
+</html>
+`))
+
+type listeningPort struct {
+	Port int    `json:"port"`
+	Url  string `json:"url"`
+}
+
+// parseBareSandboxHost extracts the sandbox ID from a host without a port prefix (e.g. some-id-uuid.proxy.domain)
+func parseBareSandboxHost(host string) (sandboxId string, baseHost string, err error) {
+	sandboxId, baseHost, found := strings.Cut(host, ".")
+	if !found || baseHost == "" {
+		return "", "", errors.New("invalid host format: must have subdomain")
+	}
+
+	if !sandboxIdPattern.MatchString(sandboxId) {
+		return "", "", errors.New("invalid host format: sandbox ID not found")
+	}
+
+	return sandboxId, baseHost, nil
+}
+
+// handlePortDirectory lists the ports the sandbox is listening on for requests to the bare sandbox domain
+func (p *Proxy) handlePortDirectory(ctx *gin.Context, sandboxId string, baseHost string) {
+	sandboxId, didRedirect, err := p.Authenticate(ctx, sandboxId, 0)
+	if err != nil {
+		if !didRedirect {
+			ctx.Error(common_errors.NewUnauthorizedError(err))
+		}
+		return
+	}
+
+	ports, err := p.getSandboxListeningPorts(ctx, sandboxId)
+	if err != nil {
+		ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("failed to get sandbox ports: %w", err)))
+		return
+	}
+
+	listening := []listeningPort{}
+	for _, port := range ports {
+		if strconv.Itoa(port) == TOOLBOX_PORT {
+			continue
+		}
+		listening = append(listening, listeningPort{
+			Port: port,
+			Url:  fmt.Sprintf("%s://%d-%s.%s/", p.config.ProxyProtocol, port, sandboxId, baseHost),
+		})
+	}
+
+	if strings.Contains(ctx.GetHeader("Accept"), "application/json") {
+		ctx.JSON(http.StatusOK, gin.H{"sandboxId": sandboxId, "ports": listening})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+	ctx.Header("Content-Type", "text/html; charset=utf-8")
+	err = portDirectoryTemplate.Execute(ctx.Writer, gin.H{"SandboxId": sandboxId, "Ports": listening})
+	if err != nil {
+		ctx.Error(err)
+	}
+}
+
+// getSandboxListeningPorts asks the sandbox's runner which ports the sandbox is currently listening on
+func (p *Proxy) getSandboxListeningPorts(ctx *gin.Context, sandboxId string) ([]int, error) {
+	runnerInfo, err := p.getSandboxRunnerInfo(ctx, sandboxId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get runner info: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx.Request.Context(), http.MethodGet, fmt.Sprintf("%s/sandboxes/%s/toolbox/port", runnerInfo.ApiUrl, sandboxId), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Daytona-Authorization", fmt.Sprintf("Bearer %s", runnerInfo.ApiKey))
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("runner returned status %d", resp.StatusCode)
+	}
+
+	var portList struct {
+		Ports []int `json:"ports"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&portList); err != nil {
+		return nil, err
+	}
+
+	slices.Sort(portList.Ports)
+	return portList.Ports, nil
+}
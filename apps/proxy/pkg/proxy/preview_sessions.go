@@ -0,0 +1,186 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	common_quota "github.com/daytonaio/common-go/pkg/quota"
+	common_ratelimit "github.com/daytonaio/common-go/pkg/ratelimit"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	PREVIEW_SESSION_COOKIE_NAME = "daytona-preview-session"
+	PREVIEW_SESSION_CAPS_PATH   = "/preview-session-caps"
+)
+
+var previewSessionLimitTemplate = template.Must(template.New("preview-session-limit").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Daytona Preview - Too many sessions</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', 'Roboto', sans-serif; background: #0a0a0a; color: #fff; display: flex; align-items: center; justify-content: center; min-height: 100vh; margin: 0; }
+main { max-width: 32rem; padding: 2rem; }
+h1 { font-size: 1.5rem; }
+p { color: #a3a3a3; line-height: 1.5; }
+</style>
+</head>
+<body>
+<main>
+<h1>Too many preview sessions</h1>
+<p>The organization owning this sandbox has reached its limit of {{.Limit}} simultaneously active preview sessions.</p>
+<p>A session frees up after {{.IdleMinutes}} minutes without activity. Please try again in about {{.RetryMinutes}} minutes.</p>
+</main>
+</body>
+</html>`))
+
+type previewSessionCapOverride struct {
+	OrganizationId string `json:"organizationId" binding:"required"`
+	// MaxPreviewSessions replaces the plan limit, 0 lifts the limit and null removes the override
+	MaxPreviewSessions *int `json:"maxPreviewSessions" binding:"omitempty,gte=0"`
+	// TtlSeconds expires the override, it is kept until removed when 0
+	TtlSeconds int `json:"ttlSeconds" binding:"gte=0"`
+}
+
+func (p *Proxy) initPreviewSessionStore() error {
+	if p.config.Redis != nil {
+		store, err := common_ratelimit.NewRedisStore(p.config.Redis, "proxy:preview-sessions:")
+		if err != nil {
+			return err
+		}
+		p.previewSessionStore = store
+		return nil
+	}
+
+	p.previewSessionStore = common_ratelimit.NewMemoryStore()
+	return nil
+}
+
+// enforcePreviewSessionCap admits the request's preview session if the organization is below its limit of active
+// sessions. Rejected requests are answered with a 429 and an error is returned.
+func (p *Proxy) enforcePreviewSessionCap(ctx *gin.Context, organizationId string, quota *common_quota.OrgQuota) error {
+	limit := quota.MaxPreviewSessions
+	if override, err := p.getPreviewSessionCapOverride(ctx, organizationId); err != nil {
+		log.WithField("organizationId", organizationId).WithError(err).Warn("Failed to get preview session cap override")
+	} else if override != nil {
+		limit = *override
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	idleTimeout := time.Duration(p.config.Quota.PreviewSessionIdleSec) * time.Second
+	result, err := p.previewSessionStore.AdmitMember(ctx.Request.Context(), organizationId, p.previewSessionId(ctx), limit, idleTimeout, time.Now())
+	if err != nil {
+		// Fail open - an unavailable store should not take down preview traffic
+		log.WithField("organizationId", organizationId).WithError(err).Warn("Preview session cap check failed")
+		return nil
+	}
+	if result.Allowed {
+		return nil
+	}
+
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+	if !isBrowser(ctx.Request.UserAgent()) {
+		ctx.Error(common_errors.NewCustomError(http.StatusTooManyRequests, fmt.Sprintf("organization reached its limit of %d active preview sessions", limit), "PREVIEW_SESSION_LIMIT_REACHED"))
+		return errors.New("preview session limit reached")
+	}
+
+	var page bytes.Buffer
+	err = previewSessionLimitTemplate.Execute(&page, map[string]any{
+		"Limit":        limit,
+		"IdleMinutes":  int(math.Ceil(idleTimeout.Minutes())),
+		"RetryMinutes": int(math.Ceil(result.RetryAfter.Minutes())),
+	})
+	if err != nil {
+		ctx.Error(common_errors.NewCustomError(http.StatusTooManyRequests, "too many preview sessions", "PREVIEW_SESSION_LIMIT_REACHED"))
+		return errors.New("preview session limit reached")
+	}
+
+	ctx.Data(http.StatusTooManyRequests, "text/html; charset=utf-8", page.Bytes())
+	ctx.Abort()
+	return errors.New("preview session limit reached")
+}
+
+// previewSessionId returns the session of the request, starting one if it carries none. Browsers get a random
+// session shared across all previews of the proxy domain, other clients one derived from their address and
+// user agent since they often drop cookies and would otherwise open a new session on every request.
+func (p *Proxy) previewSessionId(ctx *gin.Context) string {
+	if sessionId, err := ctx.Cookie(PREVIEW_SESSION_COOKIE_NAME); err == nil && sessionId != "" && len(sessionId) <= 64 {
+		return sessionId
+	}
+
+	var sessionId string
+	if state, err := GenerateRandomState(); err == nil && isBrowser(ctx.Request.UserAgent()) {
+		sessionId = strings.TrimRight(state, "=")
+	} else {
+		sum := sha256.Sum256([]byte(ctx.ClientIP() + "|" + ctx.Request.UserAgent()))
+		sessionId = hex.EncodeToString(sum[:16])
+	}
+
+	cookieHost := ctx.Request.Host
+	if _, _, baseHost, err := p.parseHost(ctx.Request.Host); err == nil {
+		cookieHost = baseHost
+	}
+	ctx.SetCookie(PREVIEW_SESSION_COOKIE_NAME, sessionId, 0, "/", p.getCookieDomain(cookieHost), p.config.EnableTLS, true)
+
+	return sessionId
+}
+
+func (p *Proxy) getPreviewSessionCapOverride(ctx *gin.Context, organizationId string) (*int, error) {
+	has, err := p.previewSessionCapOverrideCache.Has(ctx, organizationId)
+	if err != nil || !has {
+		return nil, err
+	}
+
+	return p.previewSessionCapOverrideCache.Get(ctx, organizationId)
+}
+
+// setPreviewSessionCapOverride lets operators raise, lower or lift an organization's preview session limit. It is
+// only served with admin authentication configured.
+func (p *Proxy) setPreviewSessionCapOverride(ctx *gin.Context) {
+	var override previewSessionCapOverride
+	if err := ctx.ShouldBindJSON(&override); err != nil {
+		ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("invalid preview session cap override: %w", err)))
+		return
+	}
+
+	logger := log.WithField("organizationId", override.OrganizationId)
+
+	if override.MaxPreviewSessions == nil {
+		if err := p.previewSessionCapOverrideCache.Delete(ctx, override.OrganizationId); err != nil {
+			ctx.Error(fmt.Errorf("failed to remove preview session cap override: %w", err))
+			return
+		}
+		logger.Info("Removed preview session cap override")
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	ttl := time.Duration(override.TtlSeconds) * time.Second
+	if err := p.previewSessionCapOverrideCache.Set(ctx, override.OrganizationId, *override.MaxPreviewSessions, ttl); err != nil {
+		ctx.Error(fmt.Errorf("failed to store preview session cap override: %w", err))
+		return
+	}
+
+	logger.WithField("maxPreviewSessions", *override.MaxPreviewSessions).WithField("ttl", ttl).Info("Set preview session cap override")
+	ctx.JSON(http.StatusOK, override)
+}
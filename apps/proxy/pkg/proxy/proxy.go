@@ -5,16 +5,19 @@ package proxy
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"maps"
-	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"os/signal"
 	"regexp"
 	"slices"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	apiclient "github.com/daytonaio/daytona/libs/api-client-go"
@@ -23,10 +26,21 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/securecookie"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	common_adminauth "github.com/daytonaio/common-go/pkg/adminauth"
+	common_analytics "github.com/daytonaio/common-go/pkg/analytics"
 	common_cache "github.com/daytonaio/common-go/pkg/cache"
+	common_directive "github.com/daytonaio/common-go/pkg/directive"
 	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	common_eviction "github.com/daytonaio/common-go/pkg/eviction"
 	common_proxy "github.com/daytonaio/common-go/pkg/proxy"
+	common_quota "github.com/daytonaio/common-go/pkg/quota"
+	common_ratelimit "github.com/daytonaio/common-go/pkg/ratelimit"
+	common_signedurl "github.com/daytonaio/common-go/pkg/signedurl"
+	common_slo "github.com/daytonaio/common-go/pkg/slo"
+	common_traffic "github.com/daytonaio/common-go/pkg/traffic"
+	common_tunnel "github.com/daytonaio/common-go/pkg/tunnel"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -65,6 +79,29 @@ type Proxy struct {
 	sandboxPublicCache             common_cache.ICache[bool]
 	sandboxAuthKeyValidCache       common_cache.ICache[bool]
 	sandboxLastActivityUpdateCache common_cache.ICache[bool]
+	sandboxOrganizationCache       common_cache.ICache[string]
+	sandboxDoNotDisturbCache       common_cache.ICache[bool]
+	sandboxEvictionCache           common_cache.ICache[time.Time]
+	shortLinkCache                 common_cache.ICache[string]
+	previewSessionCapOverrideCache common_cache.ICache[int]
+	sandboxTlsPassthroughCache     common_cache.ICache[[]int]
+	sandboxMiddlewarePolicyCache   common_cache.ICache[string]
+	middlewareResponseCache        common_cache.ICache[cachedResponse]
+	middlewareChains               compiledMiddlewareChains
+	sandboxRateLimitStore          common_ratelimit.IStore
+	clientRateLimiter              common_ratelimit.ILimiter
+	previewSessionStore            common_ratelimit.IStore
+	quotaEnforcer                  *common_quota.Enforcer
+	trafficRecorder                *common_traffic.Recorder
+	analyticsAggregator            *common_analytics.Aggregator
+	analyticsReporter              *analyticsReporter
+	tunnelTracker                  *common_tunnel.Tracker
+	sloTracker                     *common_slo.Tracker
+	routingReplica                 *routingReplica
+	certificates                   *certificateMonitor
+	load                           *loadTracker
+	directives                     *common_directive.Store
+	adminAuth                      *common_adminauth.Authenticator
 }
 
 func StartProxy(ctx context.Context, config *config.Config) error {
@@ -102,24 +139,171 @@ func StartProxy(ctx context.Context, config *config.Config) error {
 		if err != nil {
 			return err
 		}
+		proxy.sandboxOrganizationCache, err = common_cache.NewRedisCache[string](config.Redis, "proxy:sandbox-organization:")
+		if err != nil {
+			return err
+		}
+		proxy.sandboxDoNotDisturbCache, err = common_cache.NewRedisCache[bool](config.Redis, "proxy:sandbox-do-not-disturb:")
+		if err != nil {
+			return err
+		}
+		proxy.sandboxEvictionCache, err = common_cache.NewRedisCache[time.Time](config.Redis, "proxy:sandbox-eviction-at:")
+		if err != nil {
+			return err
+		}
+		proxy.shortLinkCache, err = common_cache.NewRedisCache[string](config.Redis, "proxy:short-link:")
+		if err != nil {
+			return err
+		}
+		proxy.previewSessionCapOverrideCache, err = common_cache.NewRedisCache[int](config.Redis, "proxy:preview-session-cap-override:")
+		if err != nil {
+			return err
+		}
+		proxy.sandboxTlsPassthroughCache, err = common_cache.NewRedisCache[[]int](config.Redis, "proxy:sandbox-tls-passthrough-ports:")
+		if err != nil {
+			return err
+		}
+		proxy.sandboxMiddlewarePolicyCache, err = common_cache.NewRedisCache[string](config.Redis, "proxy:sandbox-middleware-policy:")
+		if err != nil {
+			return err
+		}
+		proxy.middlewareResponseCache, err = common_cache.NewRedisCache[cachedResponse](config.Redis, "proxy:middleware-response:")
+		if err != nil {
+			return err
+		}
 	} else {
 		proxy.sandboxRunnerCache = common_cache.NewMapCache[RunnerInfo]()
 		proxy.runnerCache = common_cache.NewMapCache[RunnerInfo]()
 		proxy.sandboxPublicCache = common_cache.NewMapCache[bool]()
 		proxy.sandboxAuthKeyValidCache = common_cache.NewMapCache[bool]()
 		proxy.sandboxLastActivityUpdateCache = common_cache.NewMapCache[bool]()
+		proxy.sandboxOrganizationCache = common_cache.NewMapCache[string]()
+		proxy.sandboxDoNotDisturbCache = common_cache.NewMapCache[bool]()
+		proxy.sandboxEvictionCache = common_cache.NewMapCache[time.Time]()
+		proxy.shortLinkCache = common_cache.NewMapCache[string]()
+		proxy.previewSessionCapOverrideCache = common_cache.NewMapCache[int]()
+		proxy.sandboxTlsPassthroughCache = common_cache.NewMapCache[[]int]()
+		proxy.sandboxMiddlewarePolicyCache = common_cache.NewMapCache[string]()
+		proxy.middlewareResponseCache = common_cache.NewMapCache[cachedResponse]()
+	}
+
+	if config.Quota.MaxPreviewBandwidth > 0 || config.Quota.MaxPreviewSessions > 0 || config.Quota.PlanLimits {
+		source := common_quota.NewAPIQuotaSource(config.ApiClient, "", common_quota.OrgQuota{
+			MaxPreviewBandwidth: config.Quota.MaxPreviewBandwidth,
+			MaxPreviewSessions:  config.Quota.MaxPreviewSessions,
+		})
+		if config.Quota.PlanLimits {
+			source.WithPlanLimits()
+		}
+		proxy.quotaEnforcer = common_quota.NewEnforcer(source, common_cache.NewMapCache[common_quota.OrgQuota](), 5*time.Minute)
+
+		err := proxy.initPreviewSessionStore()
+		if err != nil {
+			return err
+		}
+	}
+
+	if config.RateLimit.RequestsPerSecond > 0 {
+		err := proxy.initClientRateLimiter()
+		if err != nil {
+			return err
+		}
+	}
+
+	if config.SandboxMiddlewares {
+		err := proxy.initSandboxRateLimitStore()
+		if err != nil {
+			return err
+		}
+	}
+
+	adminAuth, err := common_adminauth.NewAuthenticator(ctx, common_adminauth.Config{
+		Token:        config.AdminAuth.Token,
+		ClientCAFile: config.AdminAuth.ClientCAFile,
+		JWKSUrl:      config.AdminAuth.JwksUrl,
+		Issuer:       config.AdminAuth.JwtIssuer,
+		Audience:     config.AdminAuth.JwtAudience,
+	})
+	if err != nil {
+		return err
+	}
+	proxy.adminAuth = adminAuth
+	if !adminAuth.Enabled() {
+		log.Warn("No admin authentication configured, admin endpoints reject every request")
+		log.Warn("Directive pushes are rejected without admin authentication, directives are only polled")
+		if proxy.quotaEnforcer != nil {
+			log.Warn("Preview session cap overrides are disabled without admin authentication")
+		}
+		if config.SignedUrl.Secret != "" {
+			log.Warn("Signed URL secrets are not served without admin authentication, the control plane must derive them from SIGNED_URL_SECRET")
+		}
+	}
+
+	if len(config.TrafficReport.RunnerManagerUrls) > 0 {
+		proxy.trafficRecorder = common_traffic.NewRecorder()
+		go proxy.runTrafficReporter(ctx)
+	}
+
+	if config.Analytics.Enabled {
+		proxy.analyticsAggregator = common_analytics.NewAggregator(common_analytics.Config{
+			Epsilon:              config.Analytics.Epsilon,
+			SessionIdleTimeout:   time.Duration(config.Analytics.SessionIdleSec) * time.Second,
+			MaxSessionsPerClient: config.Analytics.MaxSessionsPerClient,
+		})
+		proxy.analyticsReporter = &analyticsReporter{}
+		go proxy.runAnalyticsReporter(ctx)
+	}
+
+	if config.HeartbeatRelayEnabled {
+		proxy.tunnelTracker = common_tunnel.NewTracker()
+		if len(config.TrafficReport.RunnerManagerUrls) > 0 {
+			go proxy.runTunnelReporter(ctx)
+		}
+	}
+
+	if config.Slo.Enabled {
+		proxy.sloTracker = common_slo.NewTracker(time.Duration(config.Slo.WindowSec)*time.Second, common_slo.Objectives{
+			LatencyP95Ms: config.Slo.LatencyP95TargetMs,
+			Availability: config.Slo.AvailabilityTarget,
+		})
+		go proxy.runSloEvaluator(ctx)
+	}
+
+	var directiveAudit *common_directive.AuditLog
+	if config.Directives.AuditLogFile != "" {
+		directiveAudit, err = common_directive.OpenAuditLog(config.Directives.AuditLogFile)
+		if err != nil {
+			return fmt.Errorf("failed to open directive audit log: %w", err)
+		}
+	}
+	proxy.directives = common_directive.NewStore(directiveAudit)
+	if config.Directives.Url != "" {
+		go proxy.directives.Poll(ctx, config.Directives.Url, config.Directives.Token, time.Duration(config.Directives.PollIntervalSec)*time.Second)
+	}
+
+	if config.RoutingReplica.File != "" {
+		proxy.routingReplica, err = openRoutingReplica(config.RoutingReplica.File)
+		if err != nil {
+			return fmt.Errorf("failed to open routing replica: %w", err)
+		}
+		go proxy.runRoutingReplicaSync(ctx)
 	}
 
+	proxy.load = newLoadTracker()
+	go proxy.load.run(ctx)
+
 	shutdownWg := &sync.WaitGroup{}
 
 	router := gin.New()
 	router.Use(func(ctx *gin.Context) {
 		shutdownWg.Add(1)
+		proxy.load.requestStarted()
 
 		cleanupOnce := sync.Once{}
 		cleanup := func() {
 			cleanupOnce.Do(func() {
 				stopActivityPoll(ctx)
+				proxy.load.requestFinished()
 				shutdownWg.Done()
 			})
 		}
@@ -161,6 +345,10 @@ func StartProxy(ctx context.Context, config *config.Config) error {
 		cors.New(corsConfig)(ctx)
 	})
 
+	if proxy.clientRateLimiter != nil {
+		router.Use(proxy.clientRateLimitMiddleware())
+	}
+
 	if config.PreviewWarningEnabled {
 		router.Use(proxy.browserWarningMiddleware())
 	}
@@ -175,6 +363,37 @@ func StartProxy(ctx context.Context, config *config.Config) error {
 		// if the host is not valid, we don't proxy the request
 		if err != nil {
 			switch ctx.Request.Method {
+			case "POST":
+				if ctx.Request.URL.Path == common_eviction.NoticePath {
+					proxy.evictionNoticeHandler()(ctx)
+					return
+				}
+				if proxy.tunnelTracker != nil && ctx.Request.URL.Path == common_tunnel.HeartbeatPathPrefix+common_tunnel.HealthcheckPath {
+					proxy.relayRunnerHeartbeat(ctx)
+					return
+				}
+				if proxy.quotaEnforcer != nil && proxy.adminAuth.Enabled() && ctx.Request.URL.Path == PREVIEW_SESSION_CAPS_PATH {
+					proxy.adminHandler(proxy.setPreviewSessionCapOverride)(ctx)
+					return
+				}
+				if config.ShortLink.Enabled && ctx.Request.URL.Path == strings.TrimSuffix(SHORT_LINK_PATH_PREFIX, "/") {
+					proxy.createShortLink(ctx)
+					return
+				}
+				if config.SignedUrl.Secret != "" && proxy.adminAuth.Enabled() && ctx.Request.URL.Path == common_signedurl.SecretPath {
+					proxy.adminHandler(proxy.createSignedUrlSecret)(ctx)
+					return
+				}
+				if proxy.certificates != nil && ctx.Request.URL.Path == CERTIFICATES_RENEW_PATH {
+					proxy.adminHandler(proxy.handleCertificateRenewal)(ctx)
+					return
+				}
+			case "PUT":
+				// Kill-switch writes are only served with admin authentication configured, directives are polled otherwise
+				if proxy.adminAuth.Enabled() && ctx.Request.URL.Path == common_directive.Path {
+					proxy.adminHandler(gin.WrapF(proxy.directives.Handler()))(ctx)
+					return
+				}
 			case "GET":
 				{
 					switch ctx.Request.URL.Path {
@@ -182,8 +401,49 @@ func StartProxy(ctx context.Context, config *config.Config) error {
 						proxy.AuthCallback(ctx)
 						return
 					case "/health":
+						// Failing while draining takes the instance out of load balancing before it stops accepting connections
+						if proxy.load.isDraining() {
+							ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining", "version": internal.Version})
+							return
+						}
 						ctx.JSON(http.StatusOK, gin.H{"status": "ok", "version": internal.Version})
 						return
+					case "/metrics":
+						proxy.adminHandler(gin.WrapH(promhttp.Handler()))(ctx)
+						return
+					case common_directive.Path:
+						proxy.adminHandler(gin.WrapF(proxy.directives.Handler()))(ctx)
+						return
+					case LOAD_PATH:
+						proxy.adminHandler(proxy.handleLoadReport)(ctx)
+						return
+					case common_analytics.ReportPath:
+						if proxy.analyticsReporter != nil {
+							proxy.adminHandler(proxy.handleAnalyticsReport)(ctx)
+							return
+						}
+					case common_slo.ReportPath:
+						if proxy.sloTracker != nil {
+							proxy.adminHandler(proxy.handleSloReport)(ctx)
+							return
+						}
+					case CERTIFICATES_PATH:
+						if proxy.certificates != nil {
+							proxy.adminHandler(proxy.handleCertificates)(ctx)
+							return
+						}
+					}
+
+					if config.ShortLink.Enabled && strings.HasPrefix(ctx.Request.URL.Path, SHORT_LINK_PATH_PREFIX) {
+						proxy.resolveShortLink(ctx)
+						return
+					}
+
+					if ctx.Request.URL.Path == "/" {
+						if sandboxId, baseHost, err := parseBareSandboxHost(ctx.Request.Host); err == nil {
+							proxy.handlePortDirectory(ctx, sandboxId, baseHost)
+							return
+						}
 					}
 
 					if regexp.MustCompile(`^/snapshots/[\w-]+/build-logs$`).MatchString(ctx.Request.URL.Path) {
@@ -234,68 +494,139 @@ func StartProxy(ctx context.Context, config *config.Config) error {
 			return
 		}
 
+		// Resolved before proxying so the sandbox's middleware chain runs on authenticated requests only
+		target, extraHeaders, err := proxy.GetProxyTarget(ctx, false)
+		if err != nil {
+			// Error already sent to the context
+			return
+		}
 		getProxyTarget := func(ctx *gin.Context) (*url.URL, map[string]string, error) {
-			return proxy.GetProxyTarget(ctx, false)
+			return target, extraHeaders, nil
 		}
 
-		common_proxy.NewProxyRequestHandler(getProxyTarget, nil)(ctx)
+		var modifyResponse func(*http.Response) error
+		if config.EvictionBannerEnabled {
+			modifyResponse = func(res *http.Response) error {
+				return proxy.injectEvictionBanner(ctx, res)
+			}
+		}
+
+		serveWithSandboxMiddlewares(ctx, common_proxy.NewProxyRequestHandler(getProxyTarget, modifyResponse))
 	})
 
 	httpServer := &http.Server{
-		Addr:    fmt.Sprintf(":%d", config.ProxyPort),
-		Handler: router,
+		Addr:      fmt.Sprintf(":%d", config.ProxyPort),
+		Handler:   router,
+		TLSConfig: &tls.Config{},
+		ConnState: proxy.load.trackConnState,
 	}
+	proxy.adminAuth.ConfigureTLS(httpServer.TLSConfig)
 
-	listener, err := net.Listen("tcp", httpServer.Addr)
+	// Certificates and protocols are set up front since handshakes are timed on copies of the config
+	if config.EnableTLS {
+		proxy.certificates, err = newCertificateMonitor(config.TLSCertFile, config.TLSKeyFile, config.AdminAuth.ClientCAFile)
+		if err != nil {
+			return err
+		}
+		go proxy.runCertificateMonitor(ctx)
+
+		httpServer.TLSConfig.GetCertificate = proxy.certificates.getCertificate
+		httpServer.TLSConfig.NextProtos = []string{"h2", "http/1.1"}
+		proxy.load.instrumentTLS(httpServer.TLSConfig)
+	}
+
+	socket, err := listen(httpServer.Addr)
 	if err != nil {
 		return err
 	}
 
+	// Connections to passthrough ports are routed by their SNI before the server terminates TLS
+	listener := socket
+	if config.TLSPassthroughEnabled {
+		listener = proxy.newPassthroughListener(socket)
+	}
+
+	upgradeSignal := make(chan os.Signal, 1)
+	if config.UpgradeHandoffEnabled {
+		signal.Notify(upgradeSignal, syscall.SIGUSR2)
+		defer signal.Stop(upgradeSignal)
+	}
+
 	log.Infof("Proxy server is running on port %d", config.ProxyPort)
 
 	serveErr := make(chan error, 1)
 	go func() {
 		if config.EnableTLS {
-			serveErr <- httpServer.ServeTLS(listener, config.TLSCertFile, config.TLSKeyFile)
+			serveErr <- httpServer.ServeTLS(listener, "", "")
 		} else {
 			serveErr <- httpServer.Serve(listener)
 		}
 	}()
 
-	select {
-	case err := <-serveErr:
-		return err
-	case <-ctx.Done():
-		errChan := make(chan error, 1)
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(config.ShutdownTimeoutSec)*time.Second)
-		defer cancel()
+	reportUpgradeReady()
 
-		go func() {
-			err := httpServer.Shutdown(shutdownCtx)
-			if err != nil {
-				errChan <- err
-				return
+	handedOff := false
+	for shuttingDown := false; !shuttingDown; {
+		select {
+		case err := <-serveErr:
+			return err
+		case <-upgradeSignal:
+			log.Info("Received upgrade signal, handing off the listener to a new proxy")
+			if err := startUpgrade(socket); err != nil {
+				log.Errorf("Upgrade handoff failed, continuing to serve: %v", err)
+				continue
 			}
+			// The new proxy serves on the same socket, so this instance stays in rotation and only finishes the
+			// requests and WebSocket sessions it already serves instead of draining
+			log.WithField("inFlightRequests", proxy.load.inFlight.Load()).Info("Handed off the listener, finishing open sessions")
+			handedOff = true
+			shuttingDown = true
+		case <-ctx.Done():
+			proxy.drain(httpServer)
+			shuttingDown = true
+		}
+	}
 
-			wgChan := make(chan struct{})
+	errChan := make(chan error, 1)
+	shutdownTimeout := time.Duration(config.ShutdownTimeoutSec) * time.Second
+	var shutdownCtx context.Context
+	var cancel context.CancelFunc
+	if handedOff {
+		// Nothing waits for this instance to exit after a handoff, so WebSocket and tunnel sessions are kept until they
+		// close and the shutdown timeout only starts once the proxy is asked to stop
+		shutdownCtx, cancel = context.WithCancel(context.Background())
+		stopTimeout := context.AfterFunc(ctx, func() { time.AfterFunc(shutdownTimeout, cancel) })
+		defer stopTimeout()
+	} else {
+		shutdownCtx, cancel = context.WithTimeout(context.Background(), shutdownTimeout)
+	}
+	defer cancel()
 
-			go func() {
-				log.Info("Waiting for active requests to finish...")
-				shutdownWg.Wait()
-				log.Info("All active requests finished, shutting down proxy")
-				close(wgChan)
-			}()
+	go func() {
+		err := httpServer.Shutdown(shutdownCtx)
+		if err != nil {
+			errChan <- err
+			return
+		}
 
-			select {
-			case <-shutdownCtx.Done():
-				errChan <- fmt.Errorf("shutdown timeout reached, forcing exit")
-			case <-wgChan:
-				errChan <- nil
-			}
+		wgChan := make(chan struct{})
 
-			errChan <- nil
+		go func() {
+			log.Info("Waiting for active requests to finish...")
+			shutdownWg.Wait()
+			log.Info("All active requests finished, shutting down proxy")
+			close(wgChan)
 		}()
 
-		return <-errChan
-	}
+		select {
+		case <-shutdownCtx.Done():
+			errChan <- fmt.Errorf("shutdown timeout reached, forcing exit")
+		case <-wgChan:
+			errChan <- nil
+		}
+
+		errChan <- nil
+	}()
+
+	return <-errChan
 }
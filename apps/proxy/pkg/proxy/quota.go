@@ -0,0 +1,118 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	common_quota "github.com/daytonaio/common-go/pkg/quota"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// bandwidthThrottledWriter paces the response body to the organization's preview bandwidth quota
+type bandwidthThrottledWriter struct {
+	gin.ResponseWriter
+	ctx            context.Context
+	limiter        *common_quota.BandwidthLimiter
+	organizationId string
+	bytesPerSecond int64
+}
+
+func (w *bandwidthThrottledWriter) Write(b []byte) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		chunk := min(len(b), int(w.bytesPerSecond))
+		if err := w.limiter.WaitN(w.ctx, w.organizationId, w.bytesPerSecond, chunk); err != nil {
+			return written, err
+		}
+
+		n, err := w.ResponseWriter.Write(b[:chunk])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		b = b[chunk:]
+	}
+	return written, nil
+}
+
+func (w *bandwidthThrottledWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// applyOrgQuota enforces the sandbox organization's preview session limit and throttles the response of a preview
+// request when the organization has a bandwidth quota. An error is returned when the request was rejected.
+func (p *Proxy) applyOrgQuota(ctx *gin.Context, sandboxId string) error {
+	organizationId, err := p.getSandboxOrganizationId(ctx, sandboxId)
+	if err != nil {
+		log.WithField("sandboxId", sandboxId).WithError(err).Warn("Failed to resolve sandbox organization for quota enforcement")
+		return nil
+	}
+
+	quota, err := p.quotaEnforcer.GetOrgQuota(ctx, organizationId)
+	if err != nil {
+		log.WithField("organizationId", organizationId).WithError(err).Warn("Failed to get organization quota")
+		return nil
+	}
+
+	if err := p.enforcePreviewSessionCap(ctx, organizationId, quota); err != nil {
+		return err
+	}
+
+	if quota.MaxPreviewBandwidth <= 0 {
+		return nil
+	}
+
+	ctx.Writer = &bandwidthThrottledWriter{
+		ResponseWriter: ctx.Writer,
+		ctx:            ctx.Request.Context(),
+		limiter:        p.quotaEnforcer.Bandwidth(),
+		organizationId: organizationId,
+		bytesPerSecond: quota.MaxPreviewBandwidth,
+	}
+	return nil
+}
+
+func (p *Proxy) getSandboxOrganizationId(ctx context.Context, sandboxId string) (string, error) {
+	has, err := p.sandboxOrganizationCache.Has(ctx, sandboxId)
+	if err != nil {
+		return "", err
+	}
+
+	if has {
+		organizationId, err := p.sandboxOrganizationCache.Get(ctx, sandboxId)
+		if err != nil {
+			return "", err
+		}
+		return *organizationId, nil
+	}
+
+	if p.routingReplica != nil {
+		if organizationId, ok := p.routingReplica.organizationId(sandboxId); ok {
+			return organizationId, nil
+		}
+	}
+
+	organization, _, err := p.apiclient.OrganizationsAPI.GetOrganizationBySandboxId(context.Background(), sandboxId).Execute()
+	if err != nil {
+		return "", err
+	}
+
+	err = p.sandboxOrganizationCache.Set(ctx, sandboxId, organization.Id, 1*time.Hour)
+	if err != nil {
+		log.Errorf("Failed to set sandbox organization in cache: %v", err)
+	}
+
+	if p.routingReplica != nil {
+		p.routingReplica.update(sandboxId, func(entry *routingEntry) {
+			entry.OrganizationId = organization.Id
+		})
+	}
+
+	return organization.Id, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	common_ratelimit "github.com/daytonaio/common-go/pkg/ratelimit"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const CLIENT_RATE_LIMITER_NAME = "proxy-client"
+
+func (p *Proxy) initClientRateLimiter() error {
+	var store common_ratelimit.IStore
+	if p.config.Redis != nil {
+		redisStore, err := common_ratelimit.NewRedisStore(p.config.Redis, "proxy:rate-limit:")
+		if err != nil {
+			return err
+		}
+		store = redisStore
+	} else {
+		store = common_ratelimit.NewMemoryStore()
+	}
+
+	limiter, err := common_ratelimit.NewTokenBucketLimiter(CLIENT_RATE_LIMITER_NAME, store, p.config.RateLimit.RequestsPerSecond, p.config.RateLimit.Burst)
+	if err != nil {
+		return fmt.Errorf("failed to create client rate limiter: %w", err)
+	}
+
+	p.clientRateLimiter = limiter
+	return nil
+}
+
+// initSandboxRateLimitStore creates the store of the rate limits in sandbox middleware policies
+func (p *Proxy) initSandboxRateLimitStore() error {
+	if p.config.Redis != nil {
+		redisStore, err := common_ratelimit.NewRedisStore(p.config.Redis, "proxy:sandbox-rate-limit:")
+		if err != nil {
+			return err
+		}
+		p.sandboxRateLimitStore = redisStore
+	} else {
+		p.sandboxRateLimitStore = common_ratelimit.NewMemoryStore()
+	}
+	return nil
+}
+
+// clientRateLimitMiddleware rejects requests from client IPs that exceed the configured request rate
+func (p *Proxy) clientRateLimitMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		clientIP := ctx.ClientIP()
+
+		result, err := p.clientRateLimiter.Allow(ctx.Request.Context(), clientIP)
+		if err != nil {
+			// Fail open - an unavailable store should not take down preview traffic
+			log.WithField("clientIP", clientIP).WithError(err).Warn("Client rate limit check failed")
+			ctx.Next()
+			return
+		}
+
+		if !result.Allowed {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+			ctx.Error(common_errors.NewCustomError(http.StatusTooManyRequests, "too many requests", "TOO_MANY_REQUESTS"))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var routingReplicaEntries = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "proxy_routing_replica_entries",
+		Help: "Number of sandboxes in the local routing replica",
+	},
+)
+
+// routingEntry is the routing and visibility data of a sandbox, fields are nil until first looked up
+type routingEntry struct {
+	Runner         *RunnerInfo `json:"runner,omitempty"`
+	Public         *bool       `json:"public,omitempty"`
+	OrganizationId string      `json:"organizationId,omitempty"`
+	SyncedAt       time.Time   `json:"syncedAt"`
+	LastUsedAt     time.Time   `json:"lastUsedAt"`
+}
+
+// routingReplica is a locally persisted copy of the routing data of recently used sandboxes. Lookups missing the
+// cache are served from it instead of the API, and it is synced in the background at a bounded rate, so a cold proxy
+// doesn't stampede the API and routing keeps working while the control plane is unavailable.
+type routingReplica struct {
+	file string
+
+	mu      sync.Mutex
+	entries map[string]*routingEntry
+	dirty   bool
+}
+
+// openRoutingReplica loads the replica persisted in the file. A missing or unreadable file starts an empty replica.
+func openRoutingReplica(file string) (*routingReplica, error) {
+	r := &routingReplica{
+		file:    file,
+		entries: map[string]*routingEntry{},
+	}
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &r.entries); err != nil {
+		log.Warnf("Discarding unreadable routing replica %s: %v", file, err)
+		r.entries = map[string]*routingEntry{}
+	}
+	routingReplicaEntries.Set(float64(len(r.entries)))
+
+	log.Infof("Loaded routing replica with %d sandboxes", len(r.entries))
+	return r, nil
+}
+
+// lookup returns a copy of the sandbox's entry and marks it as used
+func (r *routingReplica) lookup(sandboxId string) (routingEntry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[sandboxId]
+	if !ok {
+		return routingEntry{}, false
+	}
+	entry.LastUsedAt = time.Now()
+	return *entry, true
+}
+
+func (r *routingReplica) runner(sandboxId string) (*RunnerInfo, bool) {
+	entry, ok := r.lookup(sandboxId)
+	if !ok || entry.Runner == nil {
+		return nil, false
+	}
+	return entry.Runner, true
+}
+
+func (r *routingReplica) public(sandboxId string) (*bool, bool) {
+	entry, ok := r.lookup(sandboxId)
+	if !ok || entry.Public == nil {
+		return nil, false
+	}
+	return entry.Public, true
+}
+
+func (r *routingReplica) organizationId(sandboxId string) (string, bool) {
+	entry, ok := r.lookup(sandboxId)
+	if !ok || entry.OrganizationId == "" {
+		return "", false
+	}
+	return entry.OrganizationId, true
+}
+
+// update applies the data fetched from the API to the sandbox's entry
+func (r *routingReplica) update(sandboxId string, apply func(entry *routingEntry)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[sandboxId]
+	if !ok {
+		now := time.Now()
+		entry = &routingEntry{SyncedAt: now, LastUsedAt: now}
+		r.entries[sandboxId] = entry
+		routingReplicaEntries.Set(float64(len(r.entries)))
+	}
+	apply(entry)
+	r.dirty = true
+}
+
+func (r *routingReplica) remove(sandboxId string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.entries, sandboxId)
+	routingReplicaEntries.Set(float64(len(r.entries)))
+	r.dirty = true
+}
+
+// due drops the sandboxes unused for the retention period and returns those not synced for the interval
+func (r *routingReplica) due(interval, retention time.Duration) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	var sandboxIds []string
+	for sandboxId, entry := range r.entries {
+		if now.Sub(entry.LastUsedAt) > retention {
+			delete(r.entries, sandboxId)
+			r.dirty = true
+			continue
+		}
+		if now.Sub(entry.SyncedAt) >= interval {
+			sandboxIds = append(sandboxIds, sandboxId)
+		}
+	}
+	routingReplicaEntries.Set(float64(len(r.entries)))
+
+	return sandboxIds
+}
+
+// save persists the replica if it changed. The file is replaced atomically so a crash never leaves it truncated.
+// It holds runner API keys and is only readable by the proxy's user.
+func (r *routingReplica) save() error {
+	r.mu.Lock()
+	if !r.dirty {
+		r.mu.Unlock()
+		return nil
+	}
+	data, err := json.Marshal(r.entries)
+	r.dirty = false
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.file), filepath.Base(r.file)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), r.file)
+}
+
+// runRoutingReplicaSync refreshes the replica's entries every sync interval, limited to the configured request rate
+func (p *Proxy) runRoutingReplicaSync(ctx context.Context) {
+	interval := time.Duration(p.config.RoutingReplica.SyncIntervalSec) * time.Second
+	retention := time.Duration(p.config.RoutingReplica.RetentionSec) * time.Second
+	pace := time.Second / time.Duration(p.config.RoutingReplica.SyncRequestsPerSecond)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	defer func() {
+		if err := p.routingReplica.save(); err != nil {
+			log.Errorf("Failed to save routing replica: %v", err)
+		}
+	}()
+
+	for {
+		sandboxIds := p.routingReplica.due(interval, retention)
+		synced := 0
+		for _, sandboxId := range sandboxIds {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pace):
+			}
+
+			if err := p.syncRoutingEntry(ctx, sandboxId); err != nil {
+				// The API is likely unavailable, the remaining entries keep serving until the next cycle
+				log.Warnf("Routing replica sync stopped after %d of %d sandboxes: %v", synced, len(sandboxIds), err)
+				break
+			}
+			synced++
+		}
+
+		if err := p.routingReplica.save(); err != nil {
+			log.Errorf("Failed to save routing replica: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncRoutingEntry refetches the sandbox's runner and visibility. Sandboxes the API no longer knows are removed.
+func (p *Proxy) syncRoutingEntry(ctx context.Context, sandboxId string) error {
+	runner, resp, err := p.apiclient.RunnersAPI.GetRunnerBySandboxId(ctx, sandboxId).Execute()
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		p.routingReplica.remove(sandboxId)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if runner.ProxyUrl == nil {
+		return fmt.Errorf("runner proxy URL of sandbox %s not found", sandboxId)
+	}
+
+	_, resp, _ = p.apiclient.PreviewAPI.IsSandboxPublic(ctx, sandboxId).Execute()
+	if resp == nil {
+		return errors.New("failed to check whether sandbox is public")
+	}
+	isPublic := resp.StatusCode == http.StatusOK
+
+	p.routingReplica.update(sandboxId, func(entry *routingEntry) {
+		entry.Runner = &RunnerInfo{
+			ApiUrl: *runner.ProxyUrl,
+			ApiKey: runner.ApiKey,
+		}
+		entry.Public = &isPublic
+		entry.SyncedAt = time.Now()
+	})
+
+	return nil
+}
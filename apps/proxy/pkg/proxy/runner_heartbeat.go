@@ -0,0 +1,94 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	common_proxy "github.com/daytonaio/common-go/pkg/proxy"
+	common_tunnel "github.com/daytonaio/common-go/pkg/tunnel"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// relayRunnerHeartbeat forwards the healthcheck of a runner that cannot reach the API directly and, once the API
+// accepts it, records the runner's tunnel as alive. The runner's own credentials are passed through unchanged.
+func (p *Proxy) relayRunnerHeartbeat(ctx *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, 1<<20))
+	if err != nil {
+		ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("failed to read healthcheck: %w", err)))
+		return
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var healthcheck struct {
+		Domain string `json:"domain"`
+	}
+	if err := json.Unmarshal(body, &healthcheck); err != nil {
+		ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("invalid healthcheck: %w", err)))
+		return
+	}
+	nodeName := ctx.Request.Header.Get(common_tunnel.NodeNameHeader)
+	ctx.Request.Header.Del(common_tunnel.NodeNameHeader)
+
+	target, err := url.Parse(p.config.DaytonaApiUrl + common_tunnel.HealthcheckPath)
+	if err != nil {
+		ctx.Error(fmt.Errorf("failed to parse healthcheck URL: %w", err))
+		return
+	}
+
+	getTarget := func(ctx *gin.Context) (*url.URL, map[string]string, error) {
+		return target, nil, nil
+	}
+
+	modifyResponse := func(res *http.Response) error {
+		if res.StatusCode >= 200 && res.StatusCode < 300 && healthcheck.Domain != "" {
+			p.tunnelTracker.Heartbeat(healthcheck.Domain, nodeName)
+		}
+		return nil
+	}
+
+	common_proxy.NewProxyRequestHandler(getTarget, modifyResponse)(ctx)
+}
+
+// runTunnelReporter periodically reports the liveness of tunneled runners to the configured runner-managers
+func (p *Proxy) runTunnelReporter(ctx context.Context) {
+	source, err := os.Hostname()
+	if err != nil {
+		source = "proxy"
+	}
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	ticker := time.NewTicker(time.Duration(p.config.TrafficReport.IntervalSec) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report := p.tunnelTracker.Report(source)
+			if len(report.Runners) == 0 {
+				continue
+			}
+
+			for _, url := range p.config.TrafficReport.RunnerManagerUrls {
+				if err := p.sendRunnerManagerReport(ctx, httpClient, url+common_tunnel.ReportPath, report); err != nil {
+					log.WithField("url", url).WithError(err).Warn("Failed to send tunnel report")
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,587 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	common_ratelimit "github.com/daytonaio/common-go/pkg/ratelimit"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// MIDDLEWARE_POLICY_LABEL holds the sandbox's middleware policy document as JSON
+	MIDDLEWARE_POLICY_LABEL = "daytona.io/proxy-middlewares"
+
+	MIDDLEWARE_CHAIN_KEY             = "daytona-middleware-chain"
+	SANDBOX_RATE_LIMITER_NAME        = "proxy-sandbox"
+	MIDDLEWARE_CACHE_HEADER          = "X-Daytona-Cache"
+	middlewareCacheMaxBodyBytes      = 1 << 20
+	middlewareCompressionMinBytes    = 1024
+	maxCompiledMiddlewareChainsCount = 1000
+)
+
+// Middleware names of the policy document
+const (
+	MiddlewareRateLimit   = "rateLimit"
+	MiddlewareWaf         = "waf"
+	MiddlewareCompression = "compression"
+	MiddlewareHeaders     = "headers"
+	MiddlewareCache       = "cache"
+)
+
+// middlewarePolicy enables the proxy's optional per-sandbox features in the order they are listed, the first
+// middleware sees the request first and the response last. Each middleware reads only its own fields.
+type middlewarePolicy struct {
+	Middlewares []middlewareSpec `json:"middlewares"`
+}
+
+type middlewareSpec struct {
+	Name    string `json:"name"`
+	Enabled *bool  `json:"enabled,omitempty"`
+
+	// rateLimit limits the requests per client IP to the sandbox
+	RequestsPerSecond float64 `json:"requestsPerSecond,omitempty"`
+	Burst             int     `json:"burst,omitempty"`
+
+	// waf rejects requests by method, path, user agent and body size
+	AllowMethods    []string `json:"allowMethods,omitempty"`
+	BlockPaths      []string `json:"blockPaths,omitempty"`
+	BlockUserAgents []string `json:"blockUserAgents,omitempty"`
+	MaxBodyBytes    int64    `json:"maxBodyBytes,omitempty"`
+
+	// compression gzips compressible responses of at least MinBytes
+	MinBytes int `json:"minBytes,omitempty"`
+
+	// headers sets request headers sent to the sandbox and response headers sent to the client
+	RequestHeaders  map[string]string `json:"requestHeaders,omitempty"`
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+
+	// cache stores cacheable GET responses for TtlSec
+	TtlSec int `json:"ttlSec,omitempty"`
+}
+
+// sandboxMiddleware handles the request of a sandbox, calling next to pass it down the chain
+type sandboxMiddleware func(ctx *gin.Context, sandboxId string, next func())
+
+// middlewareChain is a compiled middleware policy
+type middlewareChain struct {
+	middlewares []sandboxMiddleware
+}
+
+// boundMiddlewareChain is the chain applying to the request being served
+type boundMiddlewareChain struct {
+	chain     *middlewareChain
+	sandboxId string
+}
+
+// serveWithSandboxMiddlewares runs the handler through the middleware chain attached to the request, if any
+func serveWithSandboxMiddlewares(ctx *gin.Context, handler gin.HandlerFunc) {
+	value, ok := ctx.Get(MIDDLEWARE_CHAIN_KEY)
+	if !ok {
+		handler(ctx)
+		return
+	}
+	bound := value.(boundMiddlewareChain)
+
+	var run func(i int)
+	run = func(i int) {
+		if i == len(bound.chain.middlewares) {
+			handler(ctx)
+			return
+		}
+		bound.chain.middlewares[i](ctx, bound.sandboxId, func() { run(i + 1) })
+	}
+	run(0)
+}
+
+// attachMiddlewareChain attaches the sandbox's compiled middleware chain to the request. A policy that cannot be
+// read or compiled leaves the request without middlewares rather than failing it.
+func (p *Proxy) attachMiddlewareChain(ctx *gin.Context, sandboxId string) {
+	policy, err := p.getSandboxMiddlewarePolicy(ctx, sandboxId)
+	if err != nil {
+		log.WithField("sandboxId", sandboxId).WithError(err).Debug("Failed to resolve sandbox middleware policy")
+		return
+	}
+	if policy == "" {
+		return
+	}
+
+	chain := p.middlewareChains.get(policy, p.compileMiddlewarePolicy)
+	if chain == nil || len(chain.middlewares) == 0 {
+		return
+	}
+
+	ctx.Set(MIDDLEWARE_CHAIN_KEY, boundMiddlewareChain{chain: chain, sandboxId: sandboxId})
+}
+
+func (p *Proxy) getSandboxMiddlewarePolicy(ctx context.Context, sandboxId string) (string, error) {
+	has, err := p.sandboxMiddlewarePolicyCache.Has(ctx, sandboxId)
+	if err != nil {
+		return "", err
+	}
+
+	if has {
+		policy, err := p.sandboxMiddlewarePolicyCache.Get(ctx, sandboxId)
+		if err != nil {
+			return "", err
+		}
+		return *policy, nil
+	}
+
+	organizationId, err := p.getSandboxOrganizationId(ctx, sandboxId)
+	if err != nil {
+		return "", err
+	}
+
+	sandbox, _, err := p.apiclient.SandboxAPI.GetSandbox(context.Background(), sandboxId).XDaytonaOrganizationID(organizationId).Execute()
+	if err != nil {
+		return "", err
+	}
+
+	policy := sandbox.Labels[MIDDLEWARE_POLICY_LABEL]
+
+	// Short TTL so policy changes through the API are reflected quickly
+	err = p.sandboxMiddlewarePolicyCache.Set(ctx, sandboxId, policy, 1*time.Minute)
+	if err != nil {
+		log.Errorf("Failed to set sandbox middleware policy in cache: %v", err)
+	}
+
+	return policy, nil
+}
+
+// compiledMiddlewareChains memoizes compiled chains by policy document, so sandboxes sharing a policy share a chain
+type compiledMiddlewareChains struct {
+	mu       sync.Mutex
+	byPolicy map[string]*middlewareChain
+}
+
+func (c *compiledMiddlewareChains) get(policy string, compile func(string) (*middlewareChain, error)) *middlewareChain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if chain, ok := c.byPolicy[policy]; ok {
+		return chain
+	}
+
+	chain, err := compile(policy)
+	if err != nil {
+		// Memoized as well so an invalid policy is only reported once
+		log.Warnf("Ignoring invalid sandbox middleware policy: %v", err)
+	}
+
+	if c.byPolicy == nil || len(c.byPolicy) >= maxCompiledMiddlewareChainsCount {
+		c.byPolicy = make(map[string]*middlewareChain)
+	}
+	c.byPolicy[policy] = chain
+
+	return chain
+}
+
+func (p *Proxy) compileMiddlewarePolicy(document string) (*middlewareChain, error) {
+	var policy middlewarePolicy
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		return nil, fmt.Errorf("invalid policy document: %w", err)
+	}
+
+	chain := &middlewareChain{}
+	for _, spec := range policy.Middlewares {
+		if spec.Enabled != nil && !*spec.Enabled {
+			continue
+		}
+
+		var middleware sandboxMiddleware
+		var err error
+		switch spec.Name {
+		case MiddlewareRateLimit:
+			middleware, err = p.rateLimitMiddleware(spec)
+		case MiddlewareWaf:
+			middleware, err = wafMiddleware(spec)
+		case MiddlewareCompression:
+			middleware = compressionMiddleware(spec)
+		case MiddlewareHeaders:
+			middleware = headersMiddleware(spec)
+		case MiddlewareCache:
+			middleware, err = p.cacheMiddleware(spec)
+		default:
+			err = fmt.Errorf("unknown middleware %q", spec.Name)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		chain.middlewares = append(chain.middlewares, middleware)
+	}
+
+	return chain, nil
+}
+
+func (p *Proxy) rateLimitMiddleware(spec middlewareSpec) (sandboxMiddleware, error) {
+	burst := spec.Burst
+	if burst == 0 {
+		burst = int(math.Ceil(spec.RequestsPerSecond))
+	}
+
+	limiter, err := common_ratelimit.NewTokenBucketLimiter(SANDBOX_RATE_LIMITER_NAME, p.sandboxRateLimitStore, spec.RequestsPerSecond, burst)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s middleware: %w", MiddlewareRateLimit, err)
+	}
+
+	return func(ctx *gin.Context, sandboxId string, next func()) {
+		result, err := limiter.Allow(ctx.Request.Context(), sandboxId+":"+ctx.ClientIP())
+		if err != nil {
+			// Fail open like the global client rate limit
+			log.WithField("sandboxId", sandboxId).WithError(err).Warn("Sandbox rate limit check failed")
+			next()
+			return
+		}
+
+		if !result.Allowed {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+			ctx.Error(common_errors.NewCustomError(http.StatusTooManyRequests, "too many requests", "TOO_MANY_REQUESTS"))
+			return
+		}
+
+		next()
+	}, nil
+}
+
+func wafMiddleware(spec middlewareSpec) (sandboxMiddleware, error) {
+	compile := func(patterns []string) ([]*regexp.Regexp, error) {
+		compiled := make([]*regexp.Regexp, 0, len(patterns))
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s middleware pattern %q: %w", MiddlewareWaf, pattern, err)
+			}
+			compiled = append(compiled, re)
+		}
+		return compiled, nil
+	}
+
+	blockPaths, err := compile(spec.BlockPaths)
+	if err != nil {
+		return nil, err
+	}
+	blockUserAgents, err := compile(spec.BlockUserAgents)
+	if err != nil {
+		return nil, err
+	}
+
+	allowMethods := make([]string, 0, len(spec.AllowMethods))
+	for _, method := range spec.AllowMethods {
+		allowMethods = append(allowMethods, strings.ToUpper(method))
+	}
+
+	matchesAny := func(patterns []*regexp.Regexp, value string) bool {
+		return slices.ContainsFunc(patterns, func(re *regexp.Regexp) bool { return re.MatchString(value) })
+	}
+
+	return func(ctx *gin.Context, sandboxId string, next func()) {
+		if len(allowMethods) > 0 && !slices.Contains(allowMethods, ctx.Request.Method) {
+			ctx.Error(common_errors.NewCustomError(http.StatusMethodNotAllowed, "method not allowed by sandbox policy", "REQUEST_BLOCKED"))
+			return
+		}
+
+		if matchesAny(blockPaths, ctx.Request.URL.Path) || matchesAny(blockUserAgents, ctx.Request.UserAgent()) {
+			ctx.Error(common_errors.NewCustomError(http.StatusForbidden, "request blocked by sandbox policy", "REQUEST_BLOCKED"))
+			return
+		}
+
+		if spec.MaxBodyBytes > 0 {
+			if ctx.Request.ContentLength > spec.MaxBodyBytes {
+				ctx.Error(common_errors.NewCustomError(http.StatusRequestEntityTooLarge, "request body too large", "REQUEST_BLOCKED"))
+				return
+			}
+			// Bodies without a declared length are cut off while streamed to the sandbox
+			ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, spec.MaxBodyBytes)
+		}
+
+		next()
+	}, nil
+}
+
+func headersMiddleware(spec middlewareSpec) sandboxMiddleware {
+	return func(ctx *gin.Context, sandboxId string, next func()) {
+		for name, value := range spec.RequestHeaders {
+			ctx.Request.Header.Set(name, value)
+		}
+
+		if len(spec.ResponseHeaders) == 0 {
+			next()
+			return
+		}
+
+		writer := &responseHeaderWriter{ResponseWriter: ctx.Writer, headers: spec.ResponseHeaders}
+		ctx.Writer = writer
+		defer func() { ctx.Writer = writer.ResponseWriter }()
+
+		next()
+	}
+}
+
+// responseHeaderWriter overrides the response headers of the sandbox right before they are written
+type responseHeaderWriter struct {
+	gin.ResponseWriter
+	headers map[string]string
+	applied bool
+}
+
+func (w *responseHeaderWriter) apply() {
+	if w.applied {
+		return
+	}
+	for name, value := range w.headers {
+		w.Header().Set(name, value)
+	}
+	w.applied = true
+}
+
+func (w *responseHeaderWriter) WriteHeader(code int) {
+	w.apply()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseHeaderWriter) Write(data []byte) (int, error) {
+	w.apply()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseHeaderWriter) WriteString(s string) (int, error) {
+	w.apply()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func compressionMiddleware(spec middlewareSpec) sandboxMiddleware {
+	minBytes := spec.MinBytes
+	if minBytes == 0 {
+		minBytes = middlewareCompressionMinBytes
+	}
+
+	return func(ctx *gin.Context, sandboxId string, next func()) {
+		if ctx.Request.Method == http.MethodHead || ctx.GetHeader("Upgrade") != "" || !acceptsGzip(ctx.Request) {
+			next()
+			return
+		}
+
+		writer := &gzipResponseWriter{ResponseWriter: ctx.Writer, minBytes: minBytes}
+		ctx.Writer = writer
+		defer func() {
+			writer.close()
+			ctx.Writer = writer.ResponseWriter
+		}()
+
+		next()
+	}
+}
+
+func acceptsGzip(req *http.Request) bool {
+	for _, encoding := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		if strings.TrimSpace(strings.SplitN(encoding, ";", 2)[0]) == "gzip" {
+			return true
+		}
+	}
+	return false
+}
+
+// compressibleContentType reports whether the media type is text that benefits from compression
+func compressibleContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	case strings.HasSuffix(mediaType, "+json"), strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	return slices.Contains([]string{"application/json", "application/javascript", "application/xml", "application/wasm"}, mediaType)
+}
+
+// gzipResponseWriter compresses the response if the sandbox did not encode it already. The decision is taken once the
+// headers are written, so streamed responses are compressed as they are flushed.
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	minBytes int
+	decided  bool
+	gz       *gzip.Writer
+}
+
+func (w *gzipResponseWriter) decide(code int) {
+	if w.decided {
+		return
+	}
+	w.decided = true
+
+	header := w.Header()
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified {
+		return
+	}
+	if header.Get("Content-Encoding") != "" || !compressibleContentType(header.Get("Content-Type")) {
+		return
+	}
+	if length, err := strconv.Atoi(header.Get("Content-Length")); err == nil && length < w.minBytes {
+		return
+	}
+
+	header.Del("Content-Length")
+	header.Set("Content-Encoding", "gzip")
+	header.Add("Vary", "Accept-Encoding")
+	w.gz = gzip.NewWriter(w.ResponseWriter)
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	w.decide(code)
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	w.decide(http.StatusOK)
+	if w.gz != nil {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *gzipResponseWriter) Flush() {
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *gzipResponseWriter) close() {
+	if w.gz != nil {
+		_ = w.gz.Close()
+	}
+}
+
+// cachedResponse is a sandbox response stored by the cache middleware
+type cachedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+func (p *Proxy) cacheMiddleware(spec middlewareSpec) (sandboxMiddleware, error) {
+	if spec.TtlSec <= 0 {
+		return nil, fmt.Errorf("invalid %s middleware: ttlSec must be positive", MiddlewareCache)
+	}
+	ttl := time.Duration(spec.TtlSec) * time.Second
+
+	return func(ctx *gin.Context, sandboxId string, next func()) {
+		// Only anonymous, complete GET responses are shared between clients
+		req := ctx.Request
+		if req.Method != http.MethodGet || req.Header.Get("Authorization") != "" || req.Header.Get("Range") != "" || req.Header.Get("Upgrade") != "" {
+			next()
+			return
+		}
+
+		key := middlewareCacheKey(sandboxId, req)
+		if cached, err := p.middlewareResponseCache.Get(req.Context(), key); err == nil && cached != nil {
+			for name, values := range cached.Header {
+				ctx.Writer.Header()[name] = values
+			}
+			ctx.Header(MIDDLEWARE_CACHE_HEADER, "HIT")
+			ctx.Status(cached.Status)
+			_, _ = ctx.Writer.Write(cached.Body)
+			return
+		}
+
+		ctx.Header(MIDDLEWARE_CACHE_HEADER, "MISS")
+		writer := &cachingResponseWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+		defer func() { ctx.Writer = writer.ResponseWriter }()
+
+		next()
+
+		if !writer.cacheable() {
+			return
+		}
+
+		header := writer.Header().Clone()
+		header.Del(MIDDLEWARE_CACHE_HEADER)
+		err := p.middlewareResponseCache.Set(context.Background(), key, cachedResponse{
+			Status: writer.Status(),
+			Header: header,
+			Body:   writer.body.Bytes(),
+		}, ttl)
+		if err != nil {
+			log.WithField("sandboxId", sandboxId).WithError(err).Warn("Failed to cache sandbox response")
+		}
+	}, nil
+}
+
+// middlewareCacheKey identifies a response by sandbox, port, URL and whether it may be compressed
+func middlewareCacheKey(sandboxId string, req *http.Request) string {
+	hash := sha256.Sum256([]byte(req.Host + req.URL.RequestURI() + "|" + strconv.FormatBool(acceptsGzip(req))))
+	return sandboxId + ":" + hex.EncodeToString(hash[:])
+}
+
+// cachingResponseWriter keeps a copy of the response body until it exceeds the cacheable size
+type cachingResponseWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *cachingResponseWriter) Write(data []byte) (int, error) {
+	if !w.overflow {
+		if w.body.Len()+len(data) > middlewareCacheMaxBodyBytes {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(data)
+		}
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cachingResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// cacheable reports whether the response may be shared according to its status and caching headers
+func (w *cachingResponseWriter) cacheable() bool {
+	if w.overflow || w.Status() != http.StatusOK {
+		return false
+	}
+
+	header := w.Header()
+	if header.Get("Set-Cookie") != "" || header.Get("Vary") == "*" {
+		return false
+	}
+
+	cacheControl := strings.ToLower(header.Get("Cache-Control"))
+	for _, directive := range []string{"no-store", "no-cache", "private"} {
+		if strings.Contains(cacheControl, directive) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	SHORT_LINK_PATH_PREFIX = "/r/"
+
+	// Unambiguous lowercase letters and digits so codes survive being read out or retyped
+	SHORT_LINK_ALPHABET    = "23456789abcdefghjkmnpqrstuvwxyz"
+	SHORT_LINK_CODE_LENGTH = 8
+)
+
+type shortLinkRequest struct {
+	Url string `json:"url" binding:"required"`
+	// ExpiresInSeconds should match the expiry the signed preview URL was created with
+	ExpiresInSeconds int `json:"expiresInSeconds" binding:"required,gt=0"`
+}
+
+type shortLinkResponse struct {
+	Url       string    `json:"url"`
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// createShortLink mints a short alias for a signed preview URL served by this proxy. Holding a valid signed
+// preview URL is the only authorization needed, the alias grants nothing the URL itself does not.
+func (p *Proxy) createShortLink(ctx *gin.Context) {
+	var req shortLinkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("invalid short link request: %w", err)))
+		return
+	}
+
+	target, err := url.Parse(req.Url)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") {
+		ctx.Error(common_errors.NewBadRequestError(errors.New("url must be an absolute http or https URL")))
+		return
+	}
+
+	// Only preview URLs under this proxy's domain can be aliased, so the alias never redirects elsewhere
+	port, signedToken, baseHost, err := p.parseHost(target.Host)
+	if err != nil || baseHost != ctx.Request.Host {
+		ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("url must be a preview URL under %s", ctx.Request.Host)))
+		return
+	}
+
+	portFloat, err := strconv.ParseFloat(port, 64)
+	if err != nil {
+		ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("failed to parse target port: %w", err)))
+		return
+	}
+	if _, _, err := p.apiclient.PreviewAPI.GetSandboxIdFromSignedPreviewUrlToken(ctx.Request.Context(), signedToken, float32(portFloat)).Execute(); err != nil {
+		ctx.Error(common_errors.NewBadRequestError(errors.New("url is not a valid signed preview URL. Is the token expired?")))
+		return
+	}
+
+	expiresIn := min(time.Duration(req.ExpiresInSeconds)*time.Second, time.Duration(p.config.ShortLink.MaxTtlSec)*time.Second)
+
+	code, err := p.reserveShortLinkCode(ctx)
+	if err != nil {
+		ctx.Error(fmt.Errorf("failed to generate short link code: %w", err))
+		return
+	}
+
+	if err := p.shortLinkCache.Set(ctx, code, target.String(), expiresIn); err != nil {
+		ctx.Error(fmt.Errorf("failed to store short link: %w", err))
+		return
+	}
+
+	log.WithField("code", code).WithField("expiresIn", expiresIn).Info("Created preview short link")
+
+	ctx.JSON(http.StatusCreated, shortLinkResponse{
+		Url:       fmt.Sprintf("%s://%s%s%s", p.config.ProxyProtocol, ctx.Request.Host, SHORT_LINK_PATH_PREFIX, code),
+		Code:      code,
+		ExpiresAt: time.Now().Add(expiresIn),
+	})
+}
+
+// resolveShortLink redirects a short link to the signed preview URL it aliases, which then runs the usual token flow
+func (p *Proxy) resolveShortLink(ctx *gin.Context) {
+	code := strings.TrimPrefix(ctx.Request.URL.Path, SHORT_LINK_PATH_PREFIX)
+
+	target, err := p.shortLinkCache.Get(ctx, code)
+	if err != nil || target == nil {
+		ctx.Error(common_errors.NewNotFoundError(errors.New("short link not found or expired")))
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, *target)
+}
+
+// reserveShortLinkCode generates a random code not in use by another short link
+func (p *Proxy) reserveShortLinkCode(ctx *gin.Context) (string, error) {
+	for range 5 {
+		code, err := generateShortLinkCode()
+		if err != nil {
+			return "", err
+		}
+
+		taken, err := p.shortLinkCache.Has(ctx, code)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return code, nil
+		}
+	}
+	return "", errors.New("no free code found")
+}
+
+func generateShortLinkCode() (string, error) {
+	alphabetSize := big.NewInt(int64(len(SHORT_LINK_ALPHABET)))
+
+	var code strings.Builder
+	for range SHORT_LINK_CODE_LENGTH {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code.WriteByte(SHORT_LINK_ALPHABET[n.Int64()])
+	}
+	return code.String(), nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	common_signedurl "github.com/daytonaio/common-go/pkg/signedurl"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type signedUrlSecretRequest struct {
+	OrganizationId string `json:"organizationId" binding:"required"`
+}
+
+type signedUrlSecretResponse struct {
+	OrganizationId string `json:"organizationId"`
+	Secret         string `json:"secret"`
+}
+
+// authenticateSignedUrl validates the HMAC signature query parameters of the request for the sandbox port and
+// removes them before the request is forwarded. The signature is checked first so forged URLs never reach the
+// control plane, the sandbox's organization is then resolved through the shared cache: any organization can sign for
+// its own ID, so the signature alone does not prove the sandbox belongs to it.
+func (p *Proxy) authenticateSignedUrl(ctx *gin.Context, sandboxId string, port float32) error {
+	params, err := common_signedurl.ParseQuery(ctx.Request.URL.Query())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if params.ExpiresAt.After(now.Add(time.Duration(p.config.SignedUrl.MaxTtlSec) * time.Second)) {
+		return fmt.Errorf("signed URL expires more than %d seconds in the future", p.config.SignedUrl.MaxTtlSec)
+	}
+
+	if err := params.Verify(p.config.SignedUrl.Secret, sandboxId, int(port), now); err != nil {
+		return err
+	}
+
+	organizationId, err := p.getSandboxOrganizationId(ctx, sandboxId)
+	if err != nil {
+		return fmt.Errorf("failed to get sandbox organization: %w", err)
+	}
+	// Reported as a bad signature so the response does not reveal which organization owns the sandbox
+	if organizationId != params.OrganizationId {
+		return common_signedurl.ErrSignature
+	}
+
+	query := ctx.Request.URL.Query()
+	query.Del(common_signedurl.OrganizationParam)
+	query.Del(common_signedurl.ExpiresParam)
+	query.Del(common_signedurl.SignatureParam)
+	ctx.Request.URL.RawQuery = query.Encode()
+
+	return nil
+}
+
+// createSignedUrlSecret derives the signing secret of an organization, for the control plane or operators to hand out.
+// It is only served with admin authentication configured.
+func (p *Proxy) createSignedUrlSecret(ctx *gin.Context) {
+	var req signedUrlSecretRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("invalid signed URL secret request: %w", err)))
+		return
+	}
+
+	log.WithField("organizationId", req.OrganizationId).Info("Issued signed URL secret")
+
+	ctx.JSON(http.StatusOK, signedUrlSecretResponse{
+		OrganizationId: req.OrganizationId,
+		Secret:         common_signedurl.OrgSecret(p.config.SignedUrl.Secret, req.OrganizationId),
+	})
+}
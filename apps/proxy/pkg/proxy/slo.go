@@ -0,0 +1,170 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	common_slo "github.com/daytonaio/common-go/pkg/slo"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// sloEvaluationInterval is how often the SLO metrics are refreshed and burn alerts are evaluated
+	sloEvaluationInterval = time.Minute
+
+	// sloAlertMinRequests keeps organizations with a handful of requests from alerting on a single failure
+	sloAlertMinRequests = 20
+)
+
+// sloRecordingWriter measures the time until the upstream response starts and whether it was a server error
+type sloRecordingWriter struct {
+	gin.ResponseWriter
+	tracker        *common_slo.Tracker
+	organizationId string
+	start          time.Time
+	recorded       bool
+}
+
+func (w *sloRecordingWriter) record(statusCode int) {
+	if w.recorded {
+		return
+	}
+	w.recorded = true
+	w.tracker.Observe(w.organizationId, time.Since(w.start), statusCode >= http.StatusInternalServerError)
+}
+
+func (w *sloRecordingWriter) WriteHeader(statusCode int) {
+	w.record(statusCode)
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *sloRecordingWriter) Write(b []byte) (int, error) {
+	w.record(http.StatusOK)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *sloRecordingWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// recordSlo measures the upstream response of a preview request towards the sandbox organization's SLO
+func (p *Proxy) recordSlo(ctx *gin.Context, sandboxId string) {
+	organizationId, err := p.getSandboxOrganizationId(ctx, sandboxId)
+	if err != nil {
+		log.WithField("sandboxId", sandboxId).WithError(err).Warn("Failed to resolve sandbox organization for SLO tracking")
+		return
+	}
+
+	ctx.Writer = &sloRecordingWriter{
+		ResponseWriter: ctx.Writer,
+		tracker:        p.sloTracker,
+		organizationId: organizationId,
+		start:          time.Now(),
+	}
+}
+
+// handleSloReport serves the rolling-window SLO measurement of every organization with recent preview traffic
+func (p *Proxy) handleSloReport(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, p.sloTracker.Report(sloReportSource()))
+}
+
+// runSloEvaluator periodically refreshes the SLO metrics and raises alerts for organizations burning their error
+// budget faster than the configured threshold, at most once per window per organization
+func (p *Proxy) runSloEvaluator(ctx context.Context) {
+	source := sloReportSource()
+	window := time.Duration(p.config.Slo.WindowSec) * time.Second
+	lastAlerts := make(map[string]time.Time)
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	ticker := time.NewTicker(sloEvaluationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report := p.sloTracker.Report(source)
+			common_slo.PublishMetrics(report)
+
+			if p.config.Slo.BurnRateAlertThreshold <= 0 {
+				continue
+			}
+
+			for organizationId, alertedAt := range lastAlerts {
+				if time.Since(alertedAt) >= window {
+					delete(lastAlerts, organizationId)
+				}
+			}
+
+			for _, org := range report.Organizations {
+				if org.Requests < sloAlertMinRequests || org.BurnRate < p.config.Slo.BurnRateAlertThreshold {
+					continue
+				}
+				if _, alerted := lastAlerts[org.OrganizationId]; alerted {
+					continue
+				}
+				lastAlerts[org.OrganizationId] = time.Now()
+
+				log.WithFields(log.Fields{
+					"organizationId": org.OrganizationId,
+					"burnRate":       org.BurnRate,
+					"availability":   org.Availability,
+					"requests":       org.Requests,
+				}).Warn("Organization is burning its preview availability error budget")
+				common_slo.CountAlert(org.OrganizationId)
+
+				if p.config.Slo.AlertWebhookUrl != "" {
+					alert := common_slo.Alert{Source: source, Objectives: report.Objectives, OrgReport: org}
+					if err := sendSloAlert(ctx, httpClient, p.config.Slo.AlertWebhookUrl, alert); err != nil {
+						log.WithField("organizationId", org.OrganizationId).WithError(err).Warn("Failed to send SLO burn alert")
+					}
+				}
+			}
+		}
+	}
+}
+
+// sendSloAlert posts a burn alert to the alert webhook
+func sendSloAlert(ctx context.Context, httpClient *http.Client, url string, alert common_slo.Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func sloReportSource() string {
+	source, err := os.Hostname()
+	if err != nil {
+		return "proxy"
+	}
+	return source
+}
@@ -0,0 +1,280 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	common_directive "github.com/daytonaio/common-go/pkg/directive"
+	common_passthrough "github.com/daytonaio/common-go/pkg/passthrough"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// tlsPassthroughHelloTimeout bounds how long a new connection may take to send its ClientHello
+	tlsPassthroughHelloTimeout = 10 * time.Second
+
+	// tlsPassthroughDialTimeout bounds opening the tunnel through the sandbox's runner
+	tlsPassthroughDialTimeout = 10 * time.Second
+)
+
+var tlsPassthroughConnections = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "proxy_tls_passthrough_connections",
+		Help: "Number of open TLS passthrough connections to sandbox ports",
+	},
+)
+
+// passthroughListener routes connections whose SNI names a sandbox port configured for TLS passthrough to the
+// sandbox, and hands all others to the HTTP server, which terminates TLS as usual
+type passthroughListener struct {
+	net.Listener
+	proxy *Proxy
+
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+	acceptErr error
+}
+
+func (p *Proxy) newPassthroughListener(listener net.Listener) *passthroughListener {
+	l := &passthroughListener{
+		Listener: listener,
+		proxy:    p,
+		conns:    make(chan net.Conn),
+		closed:   make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+func (l *passthroughListener) run() {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			l.acceptErr = err
+			l.Close()
+			return
+		}
+		go l.route(conn)
+	}
+}
+
+// route peeks the ClientHello of the connection without blocking the accept loop
+func (l *passthroughListener) route(conn net.Conn) {
+	serverName, replaying, err := common_passthrough.PeekServerName(conn, tlsPassthroughHelloTimeout)
+	if replaying == nil {
+		conn.Close()
+		return
+	}
+
+	// Anything that is not a passthrough connection is left to the HTTP server, including its handshake errors
+	if err == nil && serverName != "" {
+		if sandboxId, port, ok := l.proxy.tlsPassthroughTarget(serverName); ok {
+			l.proxy.passThrough(replaying, sandboxId, port)
+			return
+		}
+	}
+
+	select {
+	case l.conns <- replaying:
+	case <-l.closed:
+		replaying.Close()
+	}
+}
+
+func (l *passthroughListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		if l.acceptErr != nil {
+			return nil, l.acceptErr
+		}
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *passthroughListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		err = l.Listener.Close()
+	})
+	return err
+}
+
+// tlsPassthroughTarget returns the sandbox and port the server name routes to if the port is configured for TLS
+// passthrough. Signed preview tokens are not supported, as their validation needs the request.
+func (p *Proxy) tlsPassthroughTarget(serverName string) (string, int, bool) {
+	targetPort, sandboxId, _, err := p.parseHost(serverName)
+	if err != nil {
+		return "", 0, false
+	}
+	port, err := strconv.Atoi(targetPort)
+	if err != nil {
+		return "", 0, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tlsPassthroughDialTimeout)
+	defer cancel()
+
+	ports, err := p.getSandboxTlsPassthroughPorts(ctx, sandboxId)
+	if err != nil {
+		log.WithField("sandboxId", sandboxId).WithError(err).Debug("Failed to resolve sandbox TLS passthrough ports")
+		return "", 0, false
+	}
+	return sandboxId, port, slices.Contains(ports, port)
+}
+
+func (p *Proxy) getSandboxTlsPassthroughPorts(ctx context.Context, sandboxId string) ([]int, error) {
+	has, err := p.sandboxTlsPassthroughCache.Has(ctx, sandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	if has {
+		ports, err := p.sandboxTlsPassthroughCache.Get(ctx, sandboxId)
+		if err != nil {
+			return nil, err
+		}
+		return *ports, nil
+	}
+
+	organizationId, err := p.getSandboxOrganizationId(ctx, sandboxId)
+	if err != nil {
+		return nil, err
+	}
+
+	sandbox, _, err := p.apiclient.SandboxAPI.GetSandbox(ctx, sandboxId).XDaytonaOrganizationID(organizationId).Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	ports := common_passthrough.Ports(sandbox.Labels)
+
+	// Short TTL so changing the label through the API is reflected quickly
+	err = p.sandboxTlsPassthroughCache.Set(ctx, sandboxId, ports, 1*time.Minute)
+	if err != nil {
+		log.Errorf("Failed to set sandbox TLS passthrough ports in cache: %v", err)
+	}
+
+	return ports, nil
+}
+
+// passThrough splices the client connection to the sandbox port through a tunnel opened on the sandbox's runner.
+// The proxy never sees the requests, so the application is responsible for authenticating clients.
+func (p *Proxy) passThrough(conn net.Conn, sandboxId string, port int) {
+	logger := log.WithField("sandboxId", sandboxId).WithField("port", port)
+
+	if directive := p.directives.Active(common_directive.KindBlockSandboxPreview, sandboxId); directive != nil {
+		logger.WithField("directive", directive.Id).Info("Rejected TLS passthrough connection of blocked sandbox")
+		conn.Close()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tlsPassthroughDialTimeout)
+	runnerInfo, err := p.getSandboxRunnerInfo(ctx, sandboxId)
+	if err != nil {
+		cancel()
+		logger.WithError(err).Warn("Failed to get runner info for TLS passthrough")
+		conn.Close()
+		return
+	}
+
+	upstream, err := dialRunnerTunnel(ctx, runnerInfo, sandboxId, port)
+	cancel()
+	if err != nil {
+		logger.WithError(err).Warn("Failed to open TLS passthrough tunnel")
+		conn.Close()
+		return
+	}
+
+	go p.updateLastActivity(context.Background(), sandboxId, false, nil)
+
+	tlsPassthroughConnections.Inc()
+	defer tlsPassthroughConnections.Dec()
+
+	common_passthrough.Splice(conn, upstream)
+}
+
+// dialRunnerTunnel opens a TCP stream to the sandbox port by upgrading a request to the runner's tunnel endpoint
+func dialRunnerTunnel(ctx context.Context, runnerInfo *RunnerInfo, sandboxId string, port int) (net.Conn, error) {
+	runnerUrl, err := url.Parse(runnerInfo.ApiUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid runner API URL: %w", err)
+	}
+
+	address := runnerUrl.Host
+	if runnerUrl.Port() == "" {
+		if runnerUrl.Scheme == "https" {
+			address = net.JoinHostPort(runnerUrl.Hostname(), "443")
+		} else {
+			address = net.JoinHostPort(runnerUrl.Hostname(), "80")
+		}
+	}
+
+	dialer := &net.Dialer{}
+	var conn net.Conn
+	if runnerUrl.Scheme == "https" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: runnerUrl.Hostname()}}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, runnerUrl.JoinPath(common_passthrough.TunnelPath(sandboxId, port)).String(), nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+runnerInfo.ApiKey)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", common_passthrough.UpgradeProtocol)
+
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		conn.Close()
+		return nil, fmt.Errorf("runner returned status %d", resp.StatusCode)
+	}
+	if reader.Buffered() > 0 {
+		conn.Close()
+		return nil, errors.New("runner sent data before the tunnel was established")
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
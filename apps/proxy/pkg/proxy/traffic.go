@@ -0,0 +1,138 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	common_traffic "github.com/daytonaio/common-go/pkg/traffic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// trafficCountingWriter counts the response bytes of a proxied request towards the sandbox's traffic
+type trafficCountingWriter struct {
+	gin.ResponseWriter
+	recorder       *common_traffic.Recorder
+	sandboxId      string
+	runnerProxyUrl string
+}
+
+func (w *trafficCountingWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.recorder.Record(w.sandboxId, w.runnerProxyUrl, 0, 0, int64(n))
+	return n, err
+}
+
+func (w *trafficCountingWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Hijack hands out the connection of an upgraded request counting the bytes in both directions, so websocket traffic
+// is accounted like the responses of regular requests
+func (w *trafficCountingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := w.ResponseWriter.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &trafficCountingConn{Conn: conn, writer: w}, rw, nil
+}
+
+// trafficCountingConn counts the bytes of a hijacked connection towards the sandbox's traffic
+type trafficCountingConn struct {
+	net.Conn
+	writer *trafficCountingWriter
+}
+
+func (c *trafficCountingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.writer.recorder.Record(c.writer.sandboxId, c.writer.runnerProxyUrl, 0, int64(n), 0)
+	return n, err
+}
+
+func (c *trafficCountingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.writer.recorder.Record(c.writer.sandboxId, c.writer.runnerProxyUrl, 0, 0, int64(n))
+	return n, err
+}
+
+// recordTraffic accounts the request and its response to the sandbox's traffic
+func (p *Proxy) recordTraffic(ctx *gin.Context, sandboxId string, runnerProxyUrl string) {
+	p.trafficRecorder.Record(sandboxId, runnerProxyUrl, 1, max(ctx.Request.ContentLength, 0), 0)
+
+	ctx.Writer = &trafficCountingWriter{
+		ResponseWriter: ctx.Writer,
+		recorder:       p.trafficRecorder,
+		sandboxId:      sandboxId,
+		runnerProxyUrl: runnerProxyUrl,
+	}
+}
+
+// runTrafficReporter periodically sends the recorded traffic to the configured runner-managers
+func (p *Proxy) runTrafficReporter(ctx context.Context) {
+	source, err := os.Hostname()
+	if err != nil {
+		source = "proxy"
+	}
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	ticker := time.NewTicker(time.Duration(p.config.TrafficReport.IntervalSec) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report := p.trafficRecorder.Flush(source)
+			if len(report.Sandboxes) == 0 {
+				continue
+			}
+
+			for _, url := range p.config.TrafficReport.RunnerManagerUrls {
+				if err := p.sendRunnerManagerReport(ctx, httpClient, url+common_traffic.ReportPath, report); err != nil {
+					log.WithField("url", url).WithError(err).Warn("Failed to send traffic report")
+				}
+			}
+		}
+	}
+}
+
+// sendRunnerManagerReport posts a report to a runner-manager endpoint
+func (p *Proxy) sendRunnerManagerReport(ctx context.Context, httpClient *http.Client, url string, report any) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.config.TrafficReport.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.TrafficReport.Token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("runner-manager returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// upgradeEnv is set for a proxy started by an upgrade handoff. It serves on the listening socket passed as file
+	// descriptor 3 and reports that it is ready to accept connections on file descriptor 4.
+	upgradeEnv = "DAYTONA_PROXY_UPGRADE"
+
+	// upgradeReadyTimeout bounds how long the new proxy may take to start serving before the handoff is abandoned
+	upgradeReadyTimeout = 2 * time.Minute
+)
+
+// listen opens the proxy's listening socket, or takes over the one passed by the proxy that started this one
+func listen(addr string) (net.Listener, error) {
+	if os.Getenv(upgradeEnv) == "" {
+		return net.Listen("tcp", addr)
+	}
+
+	file := os.NewFile(3, "listener")
+	defer file.Close()
+
+	listener, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take over the listener of the previous proxy: %w", err)
+	}
+
+	log.Info("Took over the listener of the previous proxy")
+	return listener, nil
+}
+
+// reportUpgradeReady tells the proxy that started this one that it may stop accepting connections
+func reportUpgradeReady() {
+	if os.Getenv(upgradeEnv) == "" {
+		return
+	}
+
+	// Unset so that a later upgrade of this proxy starts from a clean environment
+	os.Unsetenv(upgradeEnv)
+
+	ready := os.NewFile(4, "ready")
+	defer ready.Close()
+
+	if _, err := ready.Write([]byte{1}); err != nil {
+		log.Warnf("Failed to report readiness to the previous proxy: %v", err)
+	}
+}
+
+// startUpgrade starts the proxy binary on disk, which may have been replaced with a new version, passing it the
+// listening socket. Both processes accept connections from the same socket until the new one reports it is ready,
+// so no connection is refused during the upgrade.
+//
+// The new proxy is not a child the caller waits for: the process supervisor must not stop the pod or service when
+// the original process exits, e.g. by running the proxy under a minimal init that waits for all its children. The
+// original process keeps its hijacked WebSocket and tunnel connections until they close, however long that takes,
+// and only cuts them SHUTDOWN_TIMEOUT_SEC after it is asked to stop.
+func startUpgrade(listener net.Listener) error {
+	tcpListener, ok := listener.(*net.TCPListener)
+	if !ok {
+		return errors.New("listener does not support handoff")
+	}
+
+	listenerFile, err := tcpListener.File()
+	if err != nil {
+		return err
+	}
+	defer listenerFile.Close()
+
+	readyReader, readyWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer readyReader.Close()
+
+	executable, err := os.Executable()
+	if err != nil {
+		readyWriter.Close()
+		return err
+	}
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Env = append(os.Environ(), upgradeEnv+"=1")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = []*os.File{listenerFile, readyWriter}
+
+	err = cmd.Start()
+	readyWriter.Close()
+	if err != nil {
+		return fmt.Errorf("failed to start new proxy: %w", err)
+	}
+
+	// Reaps the new proxy if it exits before this one, e.g. when it fails to start
+	go func() {
+		_ = cmd.Wait()
+	}()
+
+	log.WithField("pid", cmd.Process.Pid).Info("Started new proxy, waiting for it to be ready")
+
+	if err := readyReader.SetReadDeadline(time.Now().Add(upgradeReadyTimeout)); err != nil {
+		_ = cmd.Process.Kill()
+		return err
+	}
+
+	_, err = io.ReadFull(readyReader, make([]byte, 1))
+	if err != nil {
+		_ = cmd.Process.Kill()
+		return fmt.Errorf("new proxy did not become ready: %w", err)
+	}
+
+	return nil
+}
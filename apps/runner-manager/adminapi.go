@@ -0,0 +1,262 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/daytonaio/common-go/pkg/protection"
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// StatePath is the admin endpoint exposing the cluster state and resource metrics of the latest controller cycle
+	StatePath = "/state"
+
+	// ScaleUpPath is the admin endpoint adding nodes to the pool, ?count=N nodes, one when unset
+	ScaleUpPath = "/scale-up"
+
+	// ScaleDownPath is the admin endpoint removing the node of a runner, followed by the runner's domain
+	ScaleDownPath = "/scale-down/"
+)
+
+// manualActions holds the scale-ups and scale-downs requested through the admin API. They are carried out by the
+// pool's next controller cycle, so they never race with its own decisions.
+type manualActions struct {
+	mu        sync.Mutex
+	scaleUp   int
+	scaleDown []string
+
+	// notify triggers a controller cycle for the requested actions
+	notify chan struct{}
+}
+
+func newManualActions() *manualActions {
+	return &manualActions{notify: make(chan struct{}, 1)}
+}
+
+func (m *manualActions) requestScaleUp(count int) {
+	m.mu.Lock()
+	m.scaleUp += count
+	m.mu.Unlock()
+	m.trigger()
+}
+
+func (m *manualActions) requestScaleDown(domain string) {
+	m.mu.Lock()
+	for _, requested := range m.scaleDown {
+		if requested == domain {
+			m.mu.Unlock()
+			return
+		}
+	}
+	m.scaleDown = append(m.scaleDown, domain)
+	m.mu.Unlock()
+	m.trigger()
+}
+
+func (m *manualActions) trigger() {
+	select {
+	case m.notify <- struct{}{}:
+	default:
+	}
+}
+
+// take returns and clears the requested actions
+func (m *manualActions) take() (scaleUp int, scaleDown []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	scaleUp, scaleDown = m.scaleUp, m.scaleDown
+	m.scaleUp, m.scaleDown = 0, nil
+	return scaleUp, scaleDown
+}
+
+// handleManualActions carries out the actions requested through the admin API. Manual scale-ups bypass the idle
+// thresholds and the cooldowns but not the pool size limits. A manually scaled-down runner is made unschedulable and
+// its node removed regardless of the idle thresholds, unless it is protected; a runner still hosting sandboxes is left
+// unschedulable and removed by the regular scale-down once empty. It returns a description of the actions, empty if
+// none were requested.
+func handleManualActions(backend clusterBackend, apiClient *daytona.APIClient, cfg *Config, state *ClusterState, actions *manualActions) string {
+	scaleUp, scaleDown := actions.take()
+
+	if scaleUp > 0 {
+		nodesToCreate := capScaleUp(cfg, state, scaleUp)
+		log.WithField("reason", "manual").Infof("Manual scale-up of %d nodes requested, creating %d placeholder pods.", scaleUp, nodesToCreate)
+		for i := 0; i < nodesToCreate; i++ {
+			pod, err := createPlaceholderPod(backend, cfg, PlaceholderPodLabel, placeholderOptions{CapacityType: state.CapacityType})
+			if err != nil {
+				log.Errorf("Error creating placeholder pod for manual scale-up: %v", err)
+				continue
+			}
+			recordPlaceholderEvent(backend, pod, corev1.EventTypeNormal, EventReasonScaleUp, "Placeholder created to add a node: manual scale-up requested through the admin API")
+			state.PlaceholdersCreated++
+		}
+	}
+
+	for _, domain := range scaleDown {
+		scaleDownRunner(backend, apiClient, state, domain)
+	}
+
+	var descriptions []string
+	if scaleUp > 0 {
+		descriptions = append(descriptions, fmt.Sprintf("manual scale-up of %d nodes", scaleUp))
+	}
+	if len(scaleDown) > 0 {
+		descriptions = append(descriptions, "manual scale-down of "+strings.Join(scaleDown, ", "))
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// scaleDownRunner removes the node of the runner with the given domain for a manual scale-down
+func scaleDownRunner(backend clusterBackend, apiClient *daytona.APIClient, state *ClusterState, domain string) {
+	runnerLog := log.WithFields(log.Fields{"domain": domain, "reason": "manual"})
+
+	runner, found := state.RunnerByDomain[domain]
+	if !found {
+		runnerLog.Warnf("Manual scale-down requested for unknown runner domain %s. Skipping.", domain)
+		return
+	}
+	node, found := state.NodeByIP[domain]
+	if !found {
+		runnerLog.Warnf("Could not find K8s Node for runner with domain %s. Skipping manual scale-down.", domain)
+		return
+	}
+	runnerLog = runnerLog.WithFields(log.Fields{"node": node.Name, "runner": runner.GetId()})
+
+	if protection.IsDoNotDisturb(node.Annotations) || state.ProtectedRunnerIDs[runner.GetId()] {
+		runnerLog.Warnf("Runner on node %s (%s) is protected as do-not-disturb. Skipping manual scale-down.", node.Name, domain)
+		recordScaleDownSkipped(backend, node, "Manual scale-down skipped: the node or one of its sandboxes is protected as do-not-disturb")
+		return
+	}
+
+	if !runner.GetUnschedulable() {
+		if err := updateRunnerScheduling(apiClient, runner.GetId(), true); err != nil {
+			runnerLog.Errorf("Error marking runner %s unschedulable for manual scale-down: %v", runner.GetId(), err)
+			return
+		}
+		markRunnerUnschedulable(state, runner.GetId())
+	}
+
+	if runner.GetCurrentStartedSandboxes() > 0 {
+		runnerLog.Infof("Runner on node %s (%s) hosts %.0f started sandboxes. It is now unschedulable and will be removed once empty.", node.Name, domain, runner.GetCurrentStartedSandboxes())
+		return
+	}
+
+	var placeholder *corev1.Pod
+	scheduled := state.ScheduledPlaceholders[:0]
+	for _, pod := range state.ScheduledPlaceholders {
+		if pod.Spec.NodeName == node.Name && placeholder == nil {
+			placeholder = pod
+			continue
+		}
+		scheduled = append(scheduled, pod)
+	}
+	state.ScheduledPlaceholders = scheduled
+	if placeholder == nil {
+		runnerLog.Warnf("Could not find a scheduled placeholder pod on node %s for runner with domain %s. Skipping manual scale-down.", node.Name, domain)
+		return
+	}
+
+	// The regular scale-down of this cycle must not consider the runner again
+	deletable := state.DeletableRunners[:0]
+	for _, deletableRunner := range state.DeletableRunners {
+		if deletableRunner.GetId() != runner.GetId() {
+			deletable = append(deletable, deletableRunner)
+		}
+	}
+	state.DeletableRunners = deletable
+
+	runnerLog.WithField("placeholder", placeholder.Name).Infof("Deleting placeholder pod %s for manual scale-down.", placeholder.Name)
+	recordNodeEvent(backend, node, corev1.EventTypeNormal, EventReasonScaleDown,
+		fmt.Sprintf("Selected for scale-down: manual scale-down of runner %s requested through the admin API, deleting placeholder pod %s", runner.GetId(), placeholder.Name))
+	if err := backend.DeletePlaceholder(context.Background(), placeholder.Name); err != nil {
+		runnerLog.WithField("placeholder", placeholder.Name).Errorf("Error deleting placeholder pod %s: %v", placeholder.Name, err)
+	}
+}
+
+// stateHandler serves the cluster state and resource metrics of the selected pool's latest controller cycle
+func stateHandler(pools []*runnerPool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		pool := selectPool(w, r, pools)
+		if pool == nil {
+			return
+		}
+
+		snapshot := pool.statuses.getState()
+		if snapshot == nil {
+			http.Error(w, "no controller cycle completed yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(snapshot)
+	}
+}
+
+// scaleUpHandler requests a manual scale-up of the selected pool
+func scaleUpHandler(pools []*runnerPool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		count := 1
+		if countStr := r.URL.Query().Get("count"); countStr != "" {
+			var err error
+			count, err = strconv.Atoi(countStr)
+			if err != nil || count <= 0 {
+				http.Error(w, fmt.Sprintf("invalid count %q, expected a positive integer", countStr), http.StatusBadRequest)
+				return
+			}
+		}
+
+		pool := selectPool(w, r, pools)
+		if pool == nil {
+			return
+		}
+
+		pool.manual.requestScaleUp(count)
+		log.WithField("pool", pool.cfg.PoolName).Infof("Manual scale-up of %d nodes requested.", count)
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// scaleDownHandler requests the manual scale-down of a runner of the selected pool
+func scaleDownHandler(pools []*runnerPool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		domain := strings.TrimPrefix(r.URL.Path, ScaleDownPath)
+		if domain == "" || strings.Contains(domain, "/") {
+			http.Error(w, "expected "+ScaleDownPath+"{runnerDomain}", http.StatusBadRequest)
+			return
+		}
+
+		pool := selectPool(w, r, pools)
+		if pool == nil {
+			return
+		}
+
+		pool.manual.requestScaleDown(domain)
+		log.WithFields(log.Fields{"pool": pool.cfg.PoolName, "domain": domain}).Infof("Manual scale-down of runner %s requested.", domain)
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
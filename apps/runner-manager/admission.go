@@ -0,0 +1,247 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// AdmissionPath serves the validating webhook protecting pool nodes and placeholder pods from manual changes
+	AdmissionPath = "/admission/validate"
+
+	// AdmissionWebhookName is the name of the ValidatingWebhookConfiguration reconciled by runner-manager
+	AdmissionWebhookName = "daytona-runner-manager"
+
+	// AdmissionWebhookHashAnnotation holds the hash of the reconciled webhook configuration
+	AdmissionWebhookHashAnnotation = "daytona.io/admission-webhook-hash"
+
+	// ManualChangeOverrideAnnotation set to "true" on a pool node or placeholder pod allows deleting or cordoning it
+	ManualChangeOverrideAnnotation = "daytona.io/allow-manual-changes"
+
+	// DefaultAdmissionWebhookServicePort is the port of the service the API server reaches runner-manager through
+	DefaultAdmissionWebhookServicePort = 443
+
+	// DefaultAdmissionExemptUserPrefixes exempts the cluster's own components, including runner-manager's service
+	// account and the cluster autoscaler, which remove nodes and placeholders as part of normal operation
+	DefaultAdmissionExemptUserPrefixes = "system:"
+)
+
+// admissionHandler answers the API server's admission reviews
+func admissionHandler(cfg *Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var review admissionv1.AdmissionReview
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Request == nil {
+			http.Error(w, "invalid admission review", http.StatusBadRequest)
+			return
+		}
+
+		response := &admissionv1.AdmissionResponse{
+			UID:     review.Request.UID,
+			Allowed: true,
+		}
+		if reason := reviewManualChange(cfg, review.Request); reason != "" {
+			log.Infof("Denied %s of %s %s by %s: %s", strings.ToLower(string(review.Request.Operation)), review.Request.Resource.Resource, review.Request.Name, review.Request.UserInfo.Username, reason)
+			admissionDenials.WithLabelValues(review.Request.Resource.Resource).Inc()
+			response.Allowed = false
+			response.Result = &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Code:    http.StatusForbidden,
+				Reason:  metav1.StatusReasonForbidden,
+				Message: reason,
+			}
+		}
+
+		review.Response = response
+		review.Request = nil
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(review); err != nil {
+			log.Errorf("Error writing admission response: %v", err)
+		}
+	}
+}
+
+// reviewManualChange returns why the request interferes with the nodes or placeholders runner-manager manages, or
+// an empty string when it is allowed
+func reviewManualChange(cfg *Config, req *admissionv1.AdmissionRequest) string {
+	for _, prefix := range cfg.AdmissionExemptUserPrefixes {
+		if strings.HasPrefix(req.UserInfo.Username, prefix) {
+			return ""
+		}
+	}
+
+	overrideHint := fmt.Sprintf("annotate it with %s=true to override", ManualChangeOverrideAnnotation)
+
+	switch {
+	case req.Resource.Resource == "nodes" && req.SubResource == "":
+		var oldNode, newNode corev1.Node
+		if err := json.Unmarshal(req.OldObject.Raw, &oldNode); err != nil || oldNode.Labels[cfg.NodeSelectorKey] != "true" {
+			return ""
+		}
+		if hasManualChangeOverride(oldNode.ObjectMeta) {
+			return ""
+		}
+
+		switch req.Operation {
+		case admissionv1.Delete:
+			return fmt.Sprintf("node %s is managed by runner-manager, which removes it once its runner is drained; %s", oldNode.Name, overrideHint)
+		case admissionv1.Update:
+			if err := json.Unmarshal(req.Object.Raw, &newNode); err != nil || hasManualChangeOverride(newNode.ObjectMeta) {
+				return ""
+			}
+			if !oldNode.Spec.Unschedulable && newNode.Spec.Unschedulable {
+				return fmt.Sprintf("node %s is managed by runner-manager, which cordons it when scaling down; %s", oldNode.Name, overrideHint)
+			}
+		}
+
+	case req.Resource.Resource == "pods" && req.SubResource == "" && req.Operation == admissionv1.Delete:
+		var pod corev1.Pod
+		if err := json.Unmarshal(req.OldObject.Raw, &pod); err != nil || !isPlaceholderPod(cfg, &pod) {
+			return ""
+		}
+		if !hasManualChangeOverride(pod.ObjectMeta) {
+			return fmt.Sprintf("pod %s reserves capacity for the runner pool, deleting it can remove a node hosting sandboxes; %s", pod.Name, overrideHint)
+		}
+
+	case req.Resource.Resource == "pods" && req.SubResource == "eviction" && req.Operation == admissionv1.Create:
+		// The eviction does not carry the pod's labels, placeholders are recognized by name
+		if req.Namespace == cfg.ProviderNamespace && strings.HasPrefix(req.Name, PlaceholderPodLabel+"-") {
+			return fmt.Sprintf("pod %s reserves capacity for the runner pool and cannot be evicted; delete it with the %s=true annotation instead", req.Name, ManualChangeOverrideAnnotation)
+		}
+	}
+
+	return ""
+}
+
+func isPlaceholderPod(cfg *Config, pod *corev1.Pod) bool {
+	return pod.Namespace == cfg.ProviderNamespace && pod.Labels["app"] == PlaceholderPodLabel
+}
+
+func hasManualChangeOverride(meta metav1.ObjectMeta) bool {
+	return meta.Annotations[ManualChangeOverrideAnnotation] == "true"
+}
+
+// reconcileAdmissionWebhook makes sure the ValidatingWebhookConfiguration routing changes of pool nodes and placeholder
+// pods to runner-manager matches the configuration. Failures are ignored by the API server, so an unavailable
+// runner-manager never blocks cluster operations.
+func reconcileAdmissionWebhook(clientset *kubernetes.Clientset, cfg *Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	caBundle, err := os.ReadFile(cfg.AdmissionWebhookCAFile)
+	if err != nil {
+		return fmt.Errorf("failed to read admission webhook CA bundle: %w", err)
+	}
+
+	webhooks := buildAdmissionWebhooks(cfg, caBundle)
+	webhooksJSON, err := json.Marshal(webhooks)
+	if err != nil {
+		return err
+	}
+	hashBytes := sha256.Sum256(webhooksJSON)
+	hash := hex.EncodeToString(hashBytes[:])
+
+	configuration := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        AdmissionWebhookName,
+			Annotations: map[string]string{AdmissionWebhookHashAnnotation: hash},
+		},
+		Webhooks: webhooks,
+	}
+
+	configurations := clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations()
+	existing, err := configurations.Get(ctx, AdmissionWebhookName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		if _, err := configurations.Create(ctx, configuration, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create admission webhook configuration: %w", err)
+		}
+		log.Infof("Created admission webhook configuration %s", AdmissionWebhookName)
+	case err != nil:
+		return fmt.Errorf("failed to get admission webhook configuration: %w", err)
+	case existing.Annotations[AdmissionWebhookHashAnnotation] != hash:
+		configuration.ResourceVersion = existing.ResourceVersion
+		if _, err := configurations.Update(ctx, configuration, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update admission webhook configuration: %w", err)
+		}
+		log.Infof("Updated admission webhook configuration %s", AdmissionWebhookName)
+	}
+
+	return nil
+}
+
+// buildAdmissionWebhooks builds one webhook per protected resource, as each needs its own selectors
+func buildAdmissionWebhooks(cfg *Config, caBundle []byte) []admissionregistrationv1.ValidatingWebhook {
+	path := AdmissionPath
+	port := int32(cfg.AdmissionWebhookServicePort)
+	clientConfig := admissionregistrationv1.WebhookClientConfig{
+		Service: &admissionregistrationv1.ServiceReference{
+			Namespace: cfg.AdmissionWebhookServiceNamespace,
+			Name:      cfg.AdmissionWebhookServiceName,
+			Path:      &path,
+			Port:      &port,
+		},
+		CABundle: caBundle,
+	}
+
+	failurePolicy := admissionregistrationv1.Ignore
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	timeoutSeconds := int32(5)
+	clusterScope := admissionregistrationv1.ClusterScope
+	namespacedScope := admissionregistrationv1.NamespacedScope
+	providerNamespace := &metav1.LabelSelector{
+		MatchLabels: map[string]string{corev1.LabelMetadataName: cfg.ProviderNamespace},
+	}
+
+	webhook := func(name string, operations []admissionregistrationv1.OperationType, resource string, scope *admissionregistrationv1.ScopeType) admissionregistrationv1.ValidatingWebhook {
+		return admissionregistrationv1.ValidatingWebhook{
+			Name:         name,
+			ClientConfig: clientConfig,
+			Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: operations,
+				Rule: admissionregistrationv1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{resource},
+					Scope:       scope,
+				},
+			}},
+			FailurePolicy:           &failurePolicy,
+			SideEffects:             &sideEffects,
+			TimeoutSeconds:          &timeoutSeconds,
+			AdmissionReviewVersions: []string{"v1"},
+		}
+	}
+
+	nodes := webhook("nodes.runner-manager.daytona.io", []admissionregistrationv1.OperationType{admissionregistrationv1.Delete, admissionregistrationv1.Update}, "nodes", &clusterScope)
+	nodes.ObjectSelector = &metav1.LabelSelector{MatchLabels: map[string]string{cfg.NodeSelectorKey: "true"}}
+
+	placeholders := webhook("placeholders.runner-manager.daytona.io", []admissionregistrationv1.OperationType{admissionregistrationv1.Delete}, "pods", &namespacedScope)
+	placeholders.NamespaceSelector = providerNamespace
+	placeholders.ObjectSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": PlaceholderPodLabel}}
+
+	// Evictions are matched by namespace only, as object selectors are evaluated against the eviction itself
+	evictions := webhook("evictions.runner-manager.daytona.io", []admissionregistrationv1.OperationType{admissionregistrationv1.Create}, "pods/eviction", &namespacedScope)
+	evictions.NamespaceSelector = providerNamespace
+
+	return []admissionregistrationv1.ValidatingWebhook{nodes, placeholders, evictions}
+}
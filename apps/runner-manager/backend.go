@@ -0,0 +1,147 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+const (
+	ClusterBackendKubernetes = "kubernetes"
+	ClusterBackendNomad      = "nomad"
+)
+
+// clusterBackend is the platform hosting the pool's runner nodes and the placeholders that make its infrastructure
+// autoscaler add nodes. Every backend represents nodes and placeholders as Kubernetes objects, so the scaling logic
+// is shared between them.
+type clusterBackend interface {
+	// ListNodes returns the nodes of the pool
+	ListNodes(ctx context.Context) ([]corev1.Node, error)
+	// ListPlaceholders returns the placeholders, those without a node name are pending
+	ListPlaceholders(ctx context.Context) ([]*corev1.Pod, error)
+	// CreatePlaceholder creates a placeholder with the given options
+	CreatePlaceholder(ctx context.Context, name, appName string, options placeholderOptions) (*corev1.Pod, error)
+	DeletePlaceholder(ctx context.Context, name string) error
+	// PatchNode sets the annotations of the node, removing those with a nil value, and its schedulability if not nil
+	PatchNode(ctx context.Context, nodeName string, annotations map[string]*string, unschedulable *bool) error
+	// LabelNode sets the labels of the node, removing those with a nil value
+	LabelNode(ctx context.Context, nodeName string, labels map[string]*string) error
+	// RecordNodeEvent reports a notable change of the node to the platform's event stream
+	RecordNodeEvent(ctx context.Context, node *corev1.Node, eventType, reason, message string) error
+	// RecordPlaceholderEvent reports a notable change of the placeholder to the platform's event stream
+	RecordPlaceholderEvent(ctx context.Context, pod *corev1.Pod, eventType, reason, message string) error
+}
+
+// newClusterBackend creates the configured backend. The Kubernetes clientset is also returned for the features only
+// available on Kubernetes, it is nil on other backends.
+func newClusterBackend(cfg *Config) (clusterBackend, *kubernetes.Clientset, error) {
+	if cfg.ClusterBackend == ClusterBackendNomad {
+		return newNomadBackend(cfg), nil, nil
+	}
+
+	clientset, err := initializeKubernetesClient(cfg.KubeContext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &kubernetesBackend{clientset: clientset, cfg: cfg}, clientset, nil
+}
+
+// kubernetesBackend runs placeholders as pods, which the cluster autoscaler brings up nodes for
+type kubernetesBackend struct {
+	clientset kubernetes.Interface
+	cfg       *Config
+
+	// Set once the backend is watched, nodes and placeholders are then listed from the informers' caches
+	nodeLister        corelisters.NodeLister
+	placeholderLister corelisters.PodNamespaceLister
+
+	events eventSeriesCache
+}
+
+func (b *kubernetesBackend) ListNodes(ctx context.Context) ([]corev1.Node, error) {
+	if b.nodeLister != nil {
+		return b.listCachedNodes()
+	}
+
+	nodes, err := b.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+		LabelSelector: b.cfg.NodeSelectorKey + "=true",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nodes.Items, nil
+}
+
+func (b *kubernetesBackend) ListPlaceholders(ctx context.Context) ([]*corev1.Pod, error) {
+	if b.placeholderLister != nil {
+		return b.listCachedPlaceholders()
+	}
+
+	pods, err := b.clientset.CoreV1().Pods(b.cfg.ProviderNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=" + PlaceholderPodLabel,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	placeholders := make([]*corev1.Pod, 0, len(pods.Items))
+	for i := range pods.Items {
+		placeholders = append(placeholders, &pods.Items[i])
+	}
+	return placeholders, nil
+}
+
+func (b *kubernetesBackend) CreatePlaceholder(ctx context.Context, name, appName string, options placeholderOptions) (*corev1.Pod, error) {
+	pod, err := buildPlaceholderPod(b.cfg, name, appName, options)
+	if err != nil {
+		return nil, err
+	}
+	return b.clientset.CoreV1().Pods(b.cfg.ProviderNamespace).Create(ctx, pod, metav1.CreateOptions{})
+}
+
+func (b *kubernetesBackend) DeletePlaceholder(ctx context.Context, name string) error {
+	return b.clientset.CoreV1().Pods(b.cfg.ProviderNamespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+func (b *kubernetesBackend) PatchNode(ctx context.Context, nodeName string, annotations map[string]*string, unschedulable *bool) error {
+	patch := map[string]any{
+		"metadata": map[string]any{
+			"annotations": annotations,
+		},
+	}
+	if unschedulable != nil {
+		patch["spec"] = map[string]any{
+			"unschedulable": *unschedulable,
+		}
+	}
+
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.clientset.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, body, metav1.PatchOptions{})
+	return err
+}
+
+func (b *kubernetesBackend) LabelNode(ctx context.Context, nodeName string, labels map[string]*string) error {
+	body, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"labels": labels,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = b.clientset.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, body, metav1.PatchOptions{})
+	return err
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// sandboxShape is the resources requested by a sandbox, or the room left on a node
+type sandboxShape struct {
+	Cpu       float32
+	MemoryGiB float32
+	DiskGiB   float32
+	Gpu       float32
+}
+
+// sandboxBacklog is the sandboxes waiting for capacity and the resources they requested
+type sandboxBacklog struct {
+	Sandboxes int
+	Cpu       float32
+	MemoryGiB float32
+	DiskGiB   float32
+	Gpu       float32
+
+	// Shapes are the requests of the individual sandboxes, for the node profile each needs
+	Shapes []sandboxShape
+}
+
+// add adds a queued sandbox to the backlog
+func (b *sandboxBacklog) add(shape sandboxShape) {
+	b.Sandboxes++
+	b.Cpu += shape.Cpu
+	b.MemoryGiB += shape.MemoryGiB
+	b.DiskGiB += shape.DiskGiB
+	b.Gpu += shape.Gpu
+	b.Shapes = append(b.Shapes, shape)
+}
+
+// gatherSandboxBacklog returns the sandboxes of the region waiting for a runner with enough capacity, by requested
+// operating system: the ones pending a build and the ones being created that were not placed on a runner yet
+func gatherSandboxBacklog(apiClient *daytona.APIClient, regionID string) (map[string]*sandboxBacklog, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	backlogs := make(map[string]*sandboxBacklog)
+
+	for page := 1; ; page++ {
+		sandboxes, _, err := apiClient.SandboxAPI.ListSandboxesPaginated(ctx).
+			Regions([]string{regionID}).
+			States([]string{string(daytona.SANDBOXSTATE_PENDING_BUILD), string(daytona.SANDBOXSTATE_CREATING)}).
+			Page(float32(page)).
+			Limit(SandboxListPageSize).
+			Execute()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list queued sandboxes from Daytona API: %w", err)
+		}
+
+		for _, sandbox := range sandboxes.Items {
+			// A sandbox being created on a runner is already part of the runner's allocation
+			if sandbox.GetState() == daytona.SANDBOXSTATE_CREATING && sandbox.GetRunnerId() != "" {
+				continue
+			}
+			backlog, found := backlogs[sandboxOS(sandbox)]
+			if !found {
+				backlog = &sandboxBacklog{}
+				backlogs[sandboxOS(sandbox)] = backlog
+			}
+			backlog.add(sandboxShape{
+				Cpu:       sandbox.GetCpu(),
+				MemoryGiB: sandbox.GetMemory(),
+				DiskGiB:   sandbox.GetDisk(),
+				Gpu:       sandbox.GetGpu(),
+			})
+		}
+
+		if float32(page) >= sandboxes.TotalPages || len(sandboxes.Items) == 0 {
+			break
+		}
+	}
+
+	return backlogs, nil
+}
+
+// includeBacklog returns a copy of the metrics with the backlog's requested resources treated as allocated, so the
+// idle thresholds and the utilization limit scale the pool up in proportion to the demand before it lands on runners
+func includeBacklog(metrics *ResourceMetrics, backlog sandboxBacklog) *ResourceMetrics {
+	if backlog.Sandboxes == 0 {
+		return metrics
+	}
+	log.Infof("Counting the backlog of %d queued sandboxes as demand: CPU=%.2f, Mem=%.2fGiB, Disk=%.2fGiB, GPU=%.0f.",
+		backlog.Sandboxes, backlog.Cpu, backlog.MemoryGiB, backlog.DiskGiB, backlog.Gpu)
+
+	adjusted := *metrics
+	adjusted.TotalAllocatedCPU += backlog.Cpu
+	adjusted.TotalAllocatedMemoryGiB += backlog.MemoryGiB
+	adjusted.TotalAllocatedDiskGiB += backlog.DiskGiB
+	adjusted.TotalAllocatedGPU += backlog.Gpu
+	adjusted.TotalAvailableCPU -= backlog.Cpu
+	adjusted.TotalAvailableMemoryGiB -= backlog.MemoryGiB
+	adjusted.TotalAvailableDiskGiB -= backlog.DiskGiB
+	adjusted.TotalAvailableGPU -= backlog.Gpu
+	return &adjusted
+}
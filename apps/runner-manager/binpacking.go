@@ -0,0 +1,96 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"math"
+	"sort"
+
+	"github.com/daytonaio/daytona/apps/runner-manager/pkg/status"
+)
+
+const (
+	// DefaultSandboxCpu and DefaultSandboxMemoryGiB are the sandbox shape assumed when nothing is allocated yet
+	DefaultSandboxCpu       = 1
+	DefaultSandboxMemoryGiB = 2
+)
+
+// analyzePacking scores every schedulable runner for fragmentation and placement fit against the average sandbox shape.
+// The ranking is advisory: it is exposed on the status endpoint, the control plane's scheduler does not consume it and
+// keeps placing sandboxes by its own availability score.
+func analyzePacking(state *ClusterState) status.PackingReport {
+	report := status.PackingReport{
+		SandboxCpu:       DefaultSandboxCpu,
+		SandboxMemoryGiB: DefaultSandboxMemoryGiB,
+		Runners:          []status.RunnerPacking{},
+	}
+
+	var allocatedCpu, allocatedMemoryGiB, startedSandboxes float32
+	var activeCpu, activeMemoryGiB float32
+	for _, runner := range state.ActiveRunners {
+		allocatedCpu += runner.GetCurrentAllocatedCpu()
+		allocatedMemoryGiB += runner.GetCurrentAllocatedMemoryGiB()
+		startedSandboxes += runner.GetCurrentStartedSandboxes()
+		activeCpu += runner.GetCpu()
+		activeMemoryGiB += runner.GetMemory()
+	}
+	if startedSandboxes > 0 && allocatedCpu > 0 && allocatedMemoryGiB > 0 {
+		report.SandboxCpu = allocatedCpu / startedSandboxes
+		report.SandboxMemoryGiB = allocatedMemoryGiB / startedSandboxes
+	}
+	memoryPerCpu := report.SandboxMemoryGiB / report.SandboxCpu
+
+	for _, runner := range state.Runners {
+		if runner.GetUnschedulable() || runner.GetCpu() <= 0 || runner.GetMemory() <= 0 {
+			continue
+		}
+
+		freeCpu := float32(math.Max(0, float64(runner.GetCpu()-runner.GetCurrentAllocatedCpu())))
+		freeMemoryGiB := float32(math.Max(0, float64(runner.GetMemory()-runner.GetCurrentAllocatedMemoryGiB())))
+
+		// Free resources are usable only in the sandbox shape's proportion, the remainder is stranded
+		usableCpu := float32(math.Min(float64(freeCpu), float64(freeMemoryGiB/memoryPerCpu)))
+		strandedCpu := freeCpu - usableCpu
+		strandedMemoryGiB := freeMemoryGiB - usableCpu*memoryPerCpu
+		report.StrandedCpu += strandedCpu
+		report.StrandedMemoryGiB += strandedMemoryGiB
+
+		packing := status.RunnerPacking{
+			RunnerID:           runner.GetId(),
+			Domain:             runner.GetDomain(),
+			FragmentationScore: (strandedCpu/runner.GetCpu() + strandedMemoryGiB/runner.GetMemory()) / 2,
+			FreeCpu:            freeCpu,
+			FreeMemoryGiB:      freeMemoryGiB,
+		}
+
+		// Best fit: prefer the fullest runners that can still take the sandbox shape, penalizing fragmentation
+		if freeCpu >= report.SandboxCpu && freeMemoryGiB >= report.SandboxMemoryGiB {
+			utilization := math.Max(
+				float64(runner.GetCurrentAllocatedCpu()/runner.GetCpu()),
+				float64(runner.GetCurrentAllocatedMemoryGiB()/runner.GetMemory()),
+			)
+			packing.PackingScore = float32((0.01 + utilization) * (1 - float64(packing.FragmentationScore)))
+		}
+
+		report.Runners = append(report.Runners, packing)
+	}
+
+	sort.SliceStable(report.Runners, func(i, j int) bool {
+		return report.Runners[i].PackingScore > report.Runners[j].PackingScore
+	})
+
+	// Runners needed to hold the current allocation if it were perfectly packed
+	if len(state.ActiveRunners) > 0 && activeCpu > 0 && activeMemoryGiB > 0 {
+		avgCpu := activeCpu / float32(len(state.ActiveRunners))
+		avgMemoryGiB := activeMemoryGiB / float32(len(state.ActiveRunners))
+		needed := int(math.Ceil(math.Max(float64(allocatedCpu/avgCpu), float64(allocatedMemoryGiB/avgMemoryGiB))))
+		report.DefragmentationOpportunityNodes = max(0, len(state.ActiveRunners)-needed)
+	}
+
+	defragmentationOpportunityNodes.Set(float64(report.DefragmentationOpportunityNodes))
+	strandedCpu.Set(float64(report.StrandedCpu))
+	strandedMemoryGiB.Set(float64(report.StrandedMemoryGiB))
+
+	return report
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// blackoutWindow is an entry of SCALE_DOWN_BLACKOUT_WINDOWS, in the format
+// [{"name": "nightly-ci", "cron": "0 1 * * *", "duration": "3h"}].
+// Scale-down is disallowed for the duration from each of the cron expression's start times, scale-up is not affected.
+type blackoutWindow struct {
+	Name     string `json:"name"`
+	Cron     string `json:"cron"`
+	Duration string `json:"duration"`
+
+	schedule *cronSchedule
+	duration time.Duration
+}
+
+// scaleDownBlackout is the blackout window disallowing scale-down in the running cycle
+type scaleDownBlackout struct {
+	Window string
+	Until  time.Time
+}
+
+// parseBlackoutWindows parses and validates SCALE_DOWN_BLACKOUT_WINDOWS
+func parseBlackoutWindows(value string) ([]blackoutWindow, error) {
+	var windows []blackoutWindow
+	if err := json.Unmarshal([]byte(value), &windows); err != nil {
+		return nil, err
+	}
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("no blackout windows defined")
+	}
+
+	names := make(map[string]bool)
+	for i := range windows {
+		window := &windows[i]
+		if window.Name == "" {
+			return nil, fmt.Errorf("every blackout window needs a name")
+		}
+		if names[window.Name] {
+			return nil, fmt.Errorf("blackout window %q is defined more than once", window.Name)
+		}
+		names[window.Name] = true
+
+		schedule, err := parseCron(window.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("blackout window %q: invalid cron expression %q: %v", window.Name, window.Cron, err)
+		}
+		window.schedule = schedule
+
+		window.duration, err = time.ParseDuration(window.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("blackout window %q: invalid duration %q: %v", window.Name, window.Duration, err)
+		}
+		if window.duration <= 0 || window.duration > cronLookbackDays*24*time.Hour {
+			return nil, fmt.Errorf("blackout window %q: duration must be positive and at most %d days", window.Name, cronLookbackDays)
+		}
+	}
+
+	return windows, nil
+}
+
+// activeBlackout returns the blackout window covering now, the one ending last when several overlap, nil when
+// scale-down is allowed. The windows are evaluated in SCALING_SCHEDULE_TIMEZONE.
+func activeBlackout(cfg *Config, now time.Time) *scaleDownBlackout {
+	now = now.In(cfg.ScalingScheduleLocation)
+
+	var active *scaleDownBlackout
+	for _, window := range cfg.ScaleDownBlackoutWindows {
+		startedAt, found := window.schedule.previous(now)
+		until := startedAt.Add(window.duration)
+		isActive := found && until.After(now)
+		if isActive {
+			scaleDownBlackoutActive.WithLabelValues(window.Name).Set(1)
+		} else {
+			scaleDownBlackoutActive.WithLabelValues(window.Name).Set(0)
+		}
+		if isActive && (active == nil || until.After(active.Until)) {
+			active = &scaleDownBlackout{Window: window.Name, Until: until}
+		}
+	}
+	return active
+}
+
+// reason describes the blackout for logs, events and the scaling explanation
+func (b *scaleDownBlackout) reason() string {
+	return fmt.Sprintf("in blackout window %s until %s", b.Window, b.Until.Format(time.RFC3339))
+}
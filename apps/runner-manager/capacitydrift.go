@@ -0,0 +1,164 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Sources of the capacity of a node whose runner-reported capacity drifts from its allocatable resources
+	CapacitySourceRunner = "runner"
+	CapacitySourceNode   = "node"
+	CapacitySourceLower  = "lower"
+
+	// CapacitySourceAnnotation on a pool node overrides CAPACITY_DRIFT_SOURCE for that node
+	CapacitySourceAnnotation = "daytona.io/capacity-source"
+
+	// DefaultCapacityDriftThresholdPercent is the divergence reported as drift when CAPACITY_DRIFT_THRESHOLD_PERCENT is
+	// not set
+	DefaultCapacityDriftThresholdPercent = 20
+
+	// EventReasonCapacityDrift is the reason of the event recorded on a node whose runner reports a diverging capacity
+	EventReasonCapacityDrift = "CapacityDrift"
+)
+
+// capacityDrift is a runner whose self-reported CPU or memory diverges from the allocatable resources of its node
+type capacityDrift struct {
+	NodeName        string
+	RunnerCpu       float32
+	RunnerMemoryGiB float32
+	NodeCpu         float32
+	NodeMemoryGiB   float32
+	// Source is the capacity counted for the node, one of the CapacitySource values
+	Source string
+}
+
+// isCapacitySource reports whether the value is a valid CAPACITY_DRIFT_SOURCE or CapacitySourceAnnotation
+func isCapacitySource(value string) bool {
+	switch value {
+	case CapacitySourceRunner, CapacitySourceNode, CapacitySourceLower:
+		return true
+	}
+	return false
+}
+
+// driftPercent returns how far the runner-reported value is from the node's, relative to the node's
+func driftPercent(runnerValue, nodeValue float32) float64 {
+	if nodeValue <= 0 {
+		return 0
+	}
+	return math.Abs(float64(runnerValue-nodeValue)) / float64(nodeValue) * 100
+}
+
+// detectCapacityDrift compares the capacity each schedulable runner reports with the allocatable resources of its node
+// and returns the runners diverging by more than CAPACITY_DRIFT_THRESHOLD_PERCENT, by runner ID. Drift not present in
+// the previous cycle is logged and recorded on the node, drift gone since is logged as resolved.
+func detectCapacityDrift(backend clusterBackend, cfg *Config, state, previous *ClusterState) map[string]*capacityDrift {
+	drifts := make(map[string]*capacityDrift)
+	capacityDriftPercent.Reset()
+
+	for _, runner := range state.Runners {
+		if runner.GetUnschedulable() {
+			continue
+		}
+		node, found := state.NodeByIP[runner.GetDomain()]
+		if !found {
+			continue
+		}
+		nodeCpu, nodeMemoryGiB, err := getNodeAllocatableResources(node)
+		if err != nil {
+			continue
+		}
+
+		cpuDrift := driftPercent(runner.GetCpu(), nodeCpu)
+		memoryDrift := driftPercent(runner.GetMemory(), nodeMemoryGiB)
+		if cpuDrift <= float64(cfg.CapacityDriftThresholdPercent) && memoryDrift <= float64(cfg.CapacityDriftThresholdPercent) {
+			continue
+		}
+		capacityDriftPercent.WithLabelValues(node.Name, "cpu").Set(cpuDrift)
+		capacityDriftPercent.WithLabelValues(node.Name, "memory").Set(memoryDrift)
+
+		source := cfg.CapacityDriftSource
+		if override := node.Annotations[CapacitySourceAnnotation]; override != "" {
+			if isCapacitySource(override) {
+				source = override
+			} else {
+				log.WithField("node", node.Name).Warnf("Ignoring invalid %s %q on node %s, must be %q, %q or %q.",
+					CapacitySourceAnnotation, override, node.Name, CapacitySourceRunner, CapacitySourceNode, CapacitySourceLower)
+			}
+		}
+		drift := &capacityDrift{
+			NodeName:        node.Name,
+			RunnerCpu:       runner.GetCpu(),
+			RunnerMemoryGiB: runner.GetMemory(),
+			NodeCpu:         nodeCpu,
+			NodeMemoryGiB:   nodeMemoryGiB,
+			Source:          source,
+		}
+		drifts[runner.GetId()] = drift
+
+		if previous != nil && previous.CapacityDrift[runner.GetId()] != nil {
+			continue
+		}
+		message := fmt.Sprintf("Runner %s reports CPU=%.2f, Mem=%.2fGiB, the node's allocatable resources are CPU=%.2f, Mem=%.2fGiB (%.0f%% and %.0f%% apart), counting the %s capacity",
+			runner.GetId(), drift.RunnerCpu, drift.RunnerMemoryGiB, drift.NodeCpu, drift.NodeMemoryGiB, cpuDrift, memoryDrift, describeCapacitySource(source))
+		log.WithFields(log.Fields{"node": node.Name, "runner": runner.GetId()}).Warnf("Capacity drift on node %s: %s.", node.Name, message)
+		recordNodeEvent(backend, node, corev1.EventTypeWarning, EventReasonCapacityDrift, message)
+	}
+
+	if previous != nil {
+		for runnerID, drift := range previous.CapacityDrift {
+			if drifts[runnerID] == nil {
+				log.WithFields(log.Fields{"node": drift.NodeName, "runner": runnerID}).Infof("Capacity drift on node %s resolved.", drift.NodeName)
+			}
+		}
+	}
+	capacityDriftNodes.Set(float64(len(drifts)))
+	return drifts
+}
+
+// describeCapacitySource names the capacity a source counts in log messages
+func describeCapacitySource(source string) string {
+	switch source {
+	case CapacitySourceNode:
+		return "node's allocatable"
+	case CapacitySourceLower:
+		return "lower"
+	}
+	return "runner-reported"
+}
+
+// runnerCapacity returns the CPU and memory counted for the runner: the ones it reports, unless its capacity drifts
+// from its node's and the node's or the lower of both is selected for it
+func runnerCapacity(state *ClusterState, runner daytona.RunnerFull) (float32, float32) {
+	drift, found := state.CapacityDrift[runner.GetId()]
+	if !found {
+		return runner.GetCpu(), runner.GetMemory()
+	}
+	switch drift.Source {
+	case CapacitySourceNode:
+		return drift.NodeCpu, drift.NodeMemoryGiB
+	case CapacitySourceLower:
+		return float32(math.Min(float64(drift.RunnerCpu), float64(drift.NodeCpu))), float32(math.Min(float64(drift.RunnerMemoryGiB), float64(drift.NodeMemoryGiB)))
+	}
+	return runner.GetCpu(), runner.GetMemory()
+}
+
+// driftingNodes returns the sorted names of the nodes with capacity drift
+func driftingNodes(state *ClusterState) []string {
+	nodes := make([]string, 0, len(state.CapacityDrift))
+	for _, drift := range state.CapacityDrift {
+		nodes = append(nodes, drift.NodeName)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
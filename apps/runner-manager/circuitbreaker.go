@@ -0,0 +1,95 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultCircuitBreakerErrorCycles is the number of consecutive cycles with control-plane errors after which the
+	// controller loop degrades when CIRCUIT_BREAKER_ERROR_CYCLES is not set
+	DefaultCircuitBreakerErrorCycles = 5
+
+	// DegradedMaxScaleUpPerCycle is the number of nodes a degraded controller loop adds per cycle at most
+	DegradedMaxScaleUpPerCycle = 1
+)
+
+// controlPlaneBreaker degrades a pool's controller loop after CIRCUIT_BREAKER_ERROR_CYCLES consecutive cycles in which
+// calls to the Daytona API or the cluster failed. A degraded loop decides on partial or stale information, so it makes
+// no scale-down decisions and adds at most DegradedMaxScaleUpPerCycle nodes per cycle. The first cycle without errors
+// closes the breaker again.
+type controlPlaneBreaker struct {
+	errorCycles int
+
+	mu sync.Mutex
+	// cycleErrors are the errors of the running cycle
+	cycleErrors []error
+	// consecutive counts the consecutive cycles with errors
+	consecutive int
+	lastErr     error
+	openedAt    time.Time
+}
+
+func newControlPlaneBreaker(cfg *Config) *controlPlaneBreaker {
+	return &controlPlaneBreaker{errorCycles: cfg.CircuitBreakerErrorCycles}
+}
+
+// recordError records a failed control-plane call of the running cycle
+func (b *controlPlaneBreaker) recordError(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cycleErrors = append(b.cycleErrors, err)
+}
+
+// endCycle counts the outcome of the cycle and returns true if it opened the breaker
+func (b *controlPlaneBreaker) endCycle(cfg *Config) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	errs := b.cycleErrors
+	b.cycleErrors = nil
+	if len(errs) == 0 {
+		if !b.openedAt.IsZero() {
+			log.Infof("Control-plane calls succeeded again, leaving degraded mode after %s.", time.Since(b.openedAt).Round(time.Second))
+			controlPlaneDegraded.WithLabelValues(cfg.RegionID, cfg.PoolName).Set(0)
+		}
+		b.consecutive, b.lastErr, b.openedAt = 0, nil, time.Time{}
+		return false
+	}
+
+	b.consecutive++
+	b.lastErr = errs[len(errs)-1]
+	controlPlaneErrorCycles.WithLabelValues(cfg.RegionID, cfg.PoolName).Inc()
+	if !b.openedAt.IsZero() || b.consecutive < b.errorCycles {
+		return false
+	}
+	b.openedAt = time.Now()
+	controlPlaneDegraded.WithLabelValues(cfg.RegionID, cfg.PoolName).Set(1)
+	log.Errorf("Control-plane calls failed in %d consecutive cycles, entering degraded mode: no scale-down and at most %d node added per cycle until a cycle succeeds. Last error: %v",
+		b.consecutive, DegradedMaxScaleUpPerCycle, b.lastErr)
+	return true
+}
+
+// degraded reports whether the breaker is open
+func (b *controlPlaneBreaker) degraded() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.openedAt.IsZero()
+}
+
+// check returns why the controller loop is degraded, nil when it is not
+func (b *controlPlaneBreaker) check() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openedAt.IsZero() {
+		return nil
+	}
+	return fmt.Errorf("degraded for %s after %d consecutive cycles with control-plane errors, last error: %v",
+		time.Since(b.openedAt).Round(time.Second), b.consecutive, b.lastErr)
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// capScaleUp limits the number of nodes to add so the pool stays within MAX_NODES and MAX_RUNNERS, which guard
+// against a misconfigured threshold or an anomaly in the Daytona API provisioning unbounded nodes. Pending
+// placeholders, including those created earlier in the cycle, count as nodes and nascent nodes as runners, since
+// both are on their way.
+//
+// MAX_SCALE_UP_PER_CYCLE then bounds the placeholders created in a single cycle to protect the cloud provider's
+// quota from sudden spikes. The rest of the deficit is carried over: the next cycle, which the new placeholders
+// trigger when the cluster is watched, still sees it. A loop degraded by repeated control-plane errors adds at most
+// DegradedMaxScaleUpPerCycle nodes per cycle.
+func capScaleUp(cfg *Config, state *ClusterState, requested int) int {
+	allowed := requested
+
+	if cfg.MaxNodes > 0 {
+		nodes := len(state.Nodes) + len(state.PendingPlaceholders) + state.PlaceholdersCreated
+		if headroom := cfg.MaxNodes - nodes; headroom < allowed {
+			allowed = max(headroom, 0)
+			log.WithField("limit", "max-nodes").Warnf("Scale-up of %d nodes capped to %d by MAX_NODES (%d nodes including in-flight, max is %d).", requested, allowed, nodes, cfg.MaxNodes)
+			scaleUpCapped.WithLabelValues("max-nodes").Inc()
+			state.ScaleUpCappedBy = "MAX_NODES"
+		}
+	}
+
+	if cfg.MaxRunners > 0 {
+		runners := len(state.Runners) + len(state.NascentNodes) + len(state.PendingPlaceholders) + state.PlaceholdersCreated
+		if headroom := cfg.MaxRunners - runners; headroom < allowed {
+			allowed = max(headroom, 0)
+			log.WithField("limit", "max-runners").Warnf("Scale-up of %d nodes capped to %d by MAX_RUNNERS (%d runners including in-flight, max is %d).", requested, allowed, runners, cfg.MaxRunners)
+			scaleUpCapped.WithLabelValues("max-runners").Inc()
+			state.ScaleUpCappedBy = "MAX_RUNNERS"
+		}
+	}
+
+	if cfg.MaxScaleUpPerCycle > 0 {
+		if headroom := cfg.MaxScaleUpPerCycle - state.PlaceholdersCreated; headroom < allowed {
+			allowed = max(headroom, 0)
+			log.WithField("limit", "max-scale-up-per-cycle").Infof("Scale-up of %d nodes limited to %d this cycle by MAX_SCALE_UP_PER_CYCLE (%d), carrying the rest over to the next cycles.", requested, allowed, cfg.MaxScaleUpPerCycle)
+			scaleUpCapped.WithLabelValues("max-scale-up-per-cycle").Inc()
+		}
+	}
+
+	if state.Degraded {
+		if headroom := DegradedMaxScaleUpPerCycle - state.PlaceholdersCreated; headroom < allowed {
+			allowed = max(headroom, 0)
+			log.WithField("limit", "degraded").Warnf("Scale-up of %d nodes limited to %d this cycle while degraded by repeated control-plane errors.", requested, allowed)
+			scaleUpCapped.WithLabelValues("degraded").Inc()
+		}
+	}
+
+	return allowed
+}
@@ -0,0 +1,230 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/daytona/apps/runner-manager/pkg/status"
+)
+
+// StaleAfterPolls is the number of missed polls after which a region's status is reported as stale
+const StaleAfterPolls = 3
+
+// Config holds the configuration for the capacity-aggregator
+type Config struct {
+	APIPort            string
+	RunnerManagerURLs  []string
+	RunnerManagerToken string
+	PollInterval       time.Duration
+}
+
+// RegionStatus is the latest status fetched from a region's runner-manager
+type RegionStatus struct {
+	URL       string         `json:"url"`
+	Status    *status.Status `json:"status,omitempty"`
+	FetchedAt time.Time      `json:"fetchedAt"`
+	LastError string         `json:"lastError,omitempty"`
+	Stale     bool           `json:"stale"`
+}
+
+// GlobalCapacity is the unified view across all regions
+type GlobalCapacity struct {
+	Timestamp time.Time           `json:"timestamp"`
+	Capacity  status.Capacity     `json:"capacity"`
+	Runners   status.RunnerCounts `json:"runners"`
+	Nodes     int                 `json:"nodes"`
+	InFlight  int                 `json:"inFlight"`
+	Regions   []RegionStatus      `json:"regions"`
+}
+
+// aggregator polls every runner-manager and keeps their latest status
+type aggregator struct {
+	cfg        *Config
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	regions map[string]*RegionStatus
+}
+
+// main function to start the capacity-aggregator
+func main() {
+	log.Println("Starting capacity-aggregator...")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	agg := &aggregator{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		regions:    make(map[string]*RegionStatus),
+	}
+	for _, url := range cfg.RunnerManagerURLs {
+		agg.regions[url] = &RegionStatus{URL: url}
+	}
+
+	go agg.run()
+
+	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+	http.HandleFunc("/capacity", agg.capacityHandler)
+
+	log.Printf("capacity-aggregator listening on :%s", cfg.APIPort)
+	if err := http.ListenAndServe(":"+cfg.APIPort, nil); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("Could not start server: %v", err)
+	}
+}
+
+// loadConfig reads and validates configuration from environment variables
+func loadConfig() (*Config, error) {
+	cfg := &Config{
+		PollInterval: 30 * time.Second,
+	}
+
+	cfg.APIPort = os.Getenv("API_PORT")
+	if cfg.APIPort == "" {
+		return nil, fmt.Errorf("environment variable API_PORT not set")
+	}
+
+	for _, url := range strings.Split(os.Getenv("RUNNER_MANAGER_URLS"), ",") {
+		url = strings.TrimSuffix(strings.TrimSpace(url), "/")
+		if url != "" {
+			cfg.RunnerManagerURLs = append(cfg.RunnerManagerURLs, url)
+		}
+	}
+	if len(cfg.RunnerManagerURLs) == 0 {
+		return nil, fmt.Errorf("environment variable RUNNER_MANAGER_URLS not set")
+	}
+
+	cfg.RunnerManagerToken = os.Getenv("RUNNER_MANAGER_TOKEN")
+
+	if pollIntervalStr := os.Getenv("POLL_INTERVAL"); pollIntervalStr != "" {
+		pollInterval, err := time.ParseDuration(pollIntervalStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid POLL_INTERVAL: %v", err)
+		}
+		if pollInterval <= 0 {
+			return nil, fmt.Errorf("POLL_INTERVAL must be positive")
+		}
+		cfg.PollInterval = pollInterval
+	}
+
+	return cfg, nil
+}
+
+// run polls all runner-managers every poll interval
+func (a *aggregator) run() {
+	ticker := time.NewTicker(a.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		var wg sync.WaitGroup
+		for _, url := range a.cfg.RunnerManagerURLs {
+			wg.Add(1)
+			go func(url string) {
+				defer wg.Done()
+				a.poll(url)
+			}(url)
+		}
+		wg.Wait()
+		<-ticker.C
+	}
+}
+
+// poll fetches the status of a single runner-manager, keeping the previous status on failure
+func (a *aggregator) poll(url string) {
+	fetched, err := a.fetchStatus(url)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	region := a.regions[url]
+	if err != nil {
+		log.Printf("Error fetching status from %s: %v", url, err)
+		region.LastError = err.Error()
+		return
+	}
+	region.Status = fetched
+	region.FetchedAt = time.Now()
+	region.LastError = ""
+}
+
+func (a *aggregator) fetchStatus(url string) (*status.Status, error) {
+	req, err := http.NewRequest(http.MethodGet, url+status.StatusPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	if a.cfg.RunnerManagerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+a.cfg.RunnerManagerToken)
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("runner-manager returned status %d", resp.StatusCode)
+	}
+
+	var fetched status.Status
+	if err := json.NewDecoder(resp.Body).Decode(&fetched); err != nil {
+		return nil, err
+	}
+	return &fetched, nil
+}
+
+// capacityHandler serves the global capacity view; stale regions are listed but not counted in the totals
+func (a *aggregator) capacityHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	global := GlobalCapacity{
+		Timestamp: now,
+		Regions:   []RegionStatus{},
+	}
+
+	for _, url := range a.cfg.RunnerManagerURLs {
+		region := *a.regions[url]
+		region.Stale = region.Status == nil || now.Sub(region.FetchedAt) > StaleAfterPolls*a.cfg.PollInterval
+		global.Regions = append(global.Regions, region)
+		if region.Stale {
+			continue
+		}
+
+		global.Capacity.TotalCPU += region.Status.Capacity.TotalCPU
+		global.Capacity.TotalMemoryGiB += region.Status.Capacity.TotalMemoryGiB
+		global.Capacity.AllocatedCPU += region.Status.Capacity.AllocatedCPU
+		global.Capacity.AllocatedMemoryGiB += region.Status.Capacity.AllocatedMemoryGiB
+		global.Capacity.AvailableCPU += region.Status.Capacity.AvailableCPU
+		global.Capacity.AvailableMemoryGiB += region.Status.Capacity.AvailableMemoryGiB
+		global.Runners.Total += region.Status.Runners.Total
+		global.Runners.Active += region.Status.Runners.Active
+		global.Runners.Idle += region.Status.Runners.Idle
+		global.Runners.Deletable += region.Status.Runners.Deletable
+		global.Nodes += region.Status.Nodes
+		global.InFlight += len(region.Status.InFlight)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(global)
+}
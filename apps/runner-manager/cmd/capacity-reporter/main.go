@@ -0,0 +1,264 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/daytonaio/daytona/apps/runner-manager/pkg/hostreport"
+)
+
+// Config holds the configuration for the capacity-reporter
+type Config struct {
+	NodeName         string
+	RunnerManagerURL string
+	ReportToken      string
+	ReportInterval   time.Duration
+	ProcPath         string
+	DockerSocket     string
+}
+
+// cpuSample holds the aggregate CPU counters from /proc/stat
+type cpuSample struct {
+	total uint64
+	steal uint64
+}
+
+// main function to start the capacity-reporter
+func main() {
+	log.Println("Starting capacity-reporter...")
+
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	dockerClient := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", cfg.DockerSocket)
+			},
+		},
+	}
+
+	previous, err := readCpuSample(cfg.ProcPath)
+	if err != nil {
+		log.Printf("Warning: Could not read initial CPU sample: %v", err)
+	}
+
+	ticker := time.NewTicker(cfg.ReportInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		report := &hostreport.Report{
+			NodeName:  cfg.NodeName,
+			Timestamp: time.Now(),
+		}
+
+		current, err := readCpuSample(cfg.ProcPath)
+		if err != nil {
+			log.Printf("Warning: Could not read CPU sample: %v", err)
+		} else {
+			if previous != nil && current.total > previous.total {
+				report.CpuStealPercent = float64(current.steal-previous.steal) / float64(current.total-previous.total) * 100
+			}
+			previous = current
+		}
+
+		report.IOPressureSome, report.IOPressureFull, err = readIOPressure(cfg.ProcPath)
+		if err != nil {
+			log.Printf("Warning: Could not read IO pressure: %v", err)
+		}
+
+		report.CachedImages, err = listCachedImages(dockerClient)
+		if err != nil {
+			log.Printf("Warning: Could not list cached images: %v", err)
+		}
+
+		if err := sendReport(httpClient, cfg, report); err != nil {
+			log.Printf("Error sending report to runner-manager: %v", err)
+		}
+	}
+}
+
+// loadConfig reads and validates configuration from environment variables
+func loadConfig() (*Config, error) {
+	cfg := &Config{
+		ReportInterval: 15 * time.Second,
+		ProcPath:       "/proc",
+		DockerSocket:   "/var/run/docker.sock",
+	}
+
+	cfg.NodeName = os.Getenv("NODE_NAME")
+	if cfg.NodeName == "" {
+		return nil, fmt.Errorf("environment variable NODE_NAME not set")
+	}
+
+	cfg.RunnerManagerURL = strings.TrimSuffix(os.Getenv("RUNNER_MANAGER_URL"), "/")
+	if cfg.RunnerManagerURL == "" {
+		return nil, fmt.Errorf("environment variable RUNNER_MANAGER_URL not set")
+	}
+
+	cfg.ReportToken = os.Getenv("NODE_REPORT_TOKEN")
+
+	if reportIntervalStr := os.Getenv("REPORT_INTERVAL"); reportIntervalStr != "" {
+		reportInterval, err := time.ParseDuration(reportIntervalStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REPORT_INTERVAL: %v", err)
+		}
+		if reportInterval <= 0 {
+			return nil, fmt.Errorf("REPORT_INTERVAL must be positive")
+		}
+		cfg.ReportInterval = reportInterval
+	}
+
+	if procPath := os.Getenv("HOST_PROC_PATH"); procPath != "" {
+		cfg.ProcPath = procPath
+	}
+
+	if dockerSocket := os.Getenv("DOCKER_SOCKET"); dockerSocket != "" {
+		cfg.DockerSocket = dockerSocket
+	}
+
+	return cfg, nil
+}
+
+// readCpuSample reads the aggregate CPU line from /proc/stat
+func readCpuSample(procPath string) (*cpuSample, error) {
+	file, err := os.Open(filepath.Join(procPath, "stat"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 9 || fields[0] != "cpu" {
+			continue
+		}
+
+		sample := &cpuSample{}
+		// user nice system idle iowait irq softirq steal - guest time is already included in user
+		for i, field := range fields[1:9] {
+			value, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cpu counter %q: %w", field, err)
+			}
+			sample.total += value
+			if i == 7 {
+				sample.steal = value
+			}
+		}
+		return sample, nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("cpu line not found in %s", file.Name())
+}
+
+// readIOPressure returns the 10s "some" and "full" averages from /proc/pressure/io
+func readIOPressure(procPath string) (some float64, full float64, err error) {
+	content, err := os.ReadFile(filepath.Join(procPath, "pressure", "io"))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		avg10, found := strings.CutPrefix(fields[1], "avg10=")
+		if !found {
+			continue
+		}
+		value, err := strconv.ParseFloat(avg10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid IO pressure value %q: %w", avg10, err)
+		}
+		switch fields[0] {
+		case "some":
+			some = value
+		case "full":
+			full = value
+		}
+	}
+
+	return some, full, nil
+}
+
+// listCachedImages lists the tagged images in the local Docker image cache
+func listCachedImages(dockerClient *http.Client) ([]string, error) {
+	resp, err := dockerClient.Get("http://docker/images/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("docker returned status %d", resp.StatusCode)
+	}
+
+	var images []struct {
+		RepoTags []string `json:"RepoTags"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&images); err != nil {
+		return nil, err
+	}
+
+	var refs []string
+	for _, image := range images {
+		for _, tag := range image.RepoTags {
+			if tag != "<none>:<none>" {
+				refs = append(refs, tag)
+			}
+		}
+	}
+	return refs, nil
+}
+
+// sendReport posts the report to runner-manager
+func sendReport(httpClient *http.Client, cfg *Config, report *hostreport.Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, cfg.RunnerManagerURL+hostreport.ReportPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.ReportToken != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.ReportToken)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("runner-manager returned status %d", resp.StatusCode)
+	}
+	return nil
+}
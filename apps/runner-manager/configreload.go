@@ -0,0 +1,227 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// reloadableSetting is a threshold that can be changed at runtime through a file of CONFIG_DIR named after its
+// environment variable, as the keys of a ConfigMap mounted as a volume
+type reloadableSetting struct {
+	key   string
+	value func(cfg *Config) *int
+	min   int // Inclusive lower bound
+	max   int // Inclusive upper bound, unbounded when zero
+}
+
+var reloadableSettings = []reloadableSetting{
+	{"MIN_IDLE_RUNNERS", func(cfg *Config) *int { return &cfg.MinIdleRunners }, 0, 0},
+	{"MIN_IDLE_CPU", func(cfg *Config) *int { return &cfg.MinIdleCpu }, 0, 0},
+	{"MIN_IDLE_MEMORY", func(cfg *Config) *int { return &cfg.MinIdleMemory }, 0, 0},
+	{"MIN_IDLE_CPU_PERCENT", func(cfg *Config) *int { return &cfg.MinIdleCpuPercent }, 0, 100},
+	{"MIN_IDLE_MEMORY_PERCENT", func(cfg *Config) *int { return &cfg.MinIdleMemoryPercent }, 0, 100},
+	{"MIN_IDLE_GPU", func(cfg *Config) *int { return &cfg.MinIdleGpu }, 0, 0},
+	{"MIN_IDLE_DISK", func(cfg *Config) *int { return &cfg.MinIdleDisk }, 0, 0},
+	{"MAX_RESOURCE_UTILIZATION_PERCENT", func(cfg *Config) *int { return &cfg.MaxResourceUtilizationPercent }, 1, 100},
+	{"MAX_DISK_UTILIZATION_PERCENT", func(cfg *Config) *int { return &cfg.MaxDiskUtilizationPercent }, 1, 100},
+}
+
+// configReloader reads the thresholds of a mounted ConfigMap. The kubelet updates the mounted files in place when the
+// ConfigMap changes, so the files are read again every cycle and a change is applied by the next one without a
+// restart. A key missing from the ConfigMap falls back to its environment variable.
+type configReloader struct {
+	dir string
+
+	mu sync.Mutex
+	// base is the configuration loaded from the environment, before any reload
+	base Config
+	// contents is the raw content of the files last read, to only parse and apply them when they change
+	contents string
+	values   map[string]int
+	// generation is incremented on every change of the values, pools apply the values when their generation is behind
+	generation int
+}
+
+// newConfigReloader reads the thresholds of CONFIG_DIR, failing on invalid values so a broken ConfigMap is noticed
+// at startup rather than ignored
+func newConfigReloader(cfg *Config) (*configReloader, error) {
+	r := &configReloader{dir: cfg.ConfigDir, base: *cfg}
+
+	contents, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	values, err := parseReloadableSettings(contents)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.validate(values); err != nil {
+		return nil, err
+	}
+	r.contents, r.values, r.generation = joinContents(contents), values, 1
+	log.Infof("Loaded %d thresholds from %s.", len(values), r.dir)
+
+	return r, nil
+}
+
+// read returns the content of the files of the reloadable settings, missing files being left out
+func (r *configReloader) read() (map[string]string, error) {
+	contents := make(map[string]string)
+	for _, setting := range reloadableSettings {
+		data, err := os.ReadFile(filepath.Join(r.dir, setting.key))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", setting.key, err)
+		}
+		contents[setting.key] = strings.TrimSpace(string(data))
+	}
+	return contents, nil
+}
+
+// parseReloadableSettings validates the bounds of the settings read from the files, the rules relating them to other
+// settings are checked by validate
+func parseReloadableSettings(contents map[string]string) (map[string]int, error) {
+	values := make(map[string]int)
+	for _, setting := range reloadableSettings {
+		valueStr, found := contents[setting.key]
+		if !found || valueStr == "" {
+			continue
+		}
+		value, err := strconv.Atoi(valueStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", setting.key, err)
+		}
+		if setting.max == 0 && value < setting.min {
+			return nil, fmt.Errorf("%s cannot be negative", setting.key)
+		}
+		if setting.max > 0 && (value < setting.min || value > setting.max) {
+			return nil, fmt.Errorf("%s must be between %d and %d", setting.key, setting.min, setting.max)
+		}
+		values[setting.key] = value
+	}
+	return values, nil
+}
+
+// validate checks the values against the settings they relate to, as loadConfig does, for the top-level configuration
+// and each pool definition's
+func (r *configReloader) validate(values map[string]int) error {
+	top := r.base
+	for _, setting := range reloadableSettings {
+		if value, found := values[setting.key]; found {
+			*setting.value(&top) = value
+		}
+	}
+
+	configs := []*Config{&top}
+	for _, definition := range r.base.Pools {
+		configs = append(configs, definition.apply(&top))
+	}
+	for _, cfg := range configs {
+		prefix := ""
+		if cfg.PoolName != r.base.PoolName {
+			prefix = fmt.Sprintf("pool %s: ", cfg.PoolName)
+		}
+		if cfg.ScaleDownUtilizationPercent > 0 && cfg.ScaleDownUtilizationPercent >= cfg.MaxResourceUtilizationPercent {
+			return fmt.Errorf("%sMAX_RESOURCE_UTILIZATION_PERCENT (%d) must be above SCALE_DOWN_UTILIZATION_PERCENT (%d)",
+				prefix, cfg.MaxResourceUtilizationPercent, cfg.ScaleDownUtilizationPercent)
+		}
+		for _, bound := range []struct {
+			env      string
+			min, max int
+		}{
+			{"MIN_IDLE_RUNNERS", cfg.MinIdleRunners, cfg.IdleTuningMaxRunners},
+			{"MIN_IDLE_CPU", cfg.MinIdleCpu, cfg.IdleTuningMaxCpu},
+			{"MIN_IDLE_MEMORY", cfg.MinIdleMemory, cfg.IdleTuningMaxMemory},
+		} {
+			if bound.max != 0 && bound.min > bound.max {
+				return fmt.Errorf("%s%s (%d) cannot be above its idle tuning upper bound %d", prefix, bound.env, bound.min, bound.max)
+			}
+		}
+	}
+	return nil
+}
+
+// joinContents returns a canonical representation of the files read, to detect changes
+func joinContents(contents map[string]string) string {
+	var b strings.Builder
+	for _, setting := range reloadableSettings {
+		if value, found := contents[setting.key]; found {
+			fmt.Fprintf(&b, "%s=%s\n", setting.key, value)
+		}
+	}
+	return b.String()
+}
+
+// reload reads the files again and updates the values when they changed. Invalid values are logged and the previous
+// ones kept, so a bad edit of the ConfigMap never stops the controller.
+func (r *configReloader) reload() {
+	contents, err := r.read()
+	if err != nil {
+		log.Errorf("Error reading the configuration of %s: %v", r.dir, err)
+		configReloads.WithLabelValues("error").Inc()
+		return
+	}
+	joined := joinContents(contents)
+	if joined == r.contents {
+		return
+	}
+	r.contents = joined
+
+	values, err := parseReloadableSettings(contents)
+	if err == nil {
+		err = r.validate(values)
+	}
+	if err != nil {
+		log.Errorf("Ignoring the changed configuration of %s, keeping the previous thresholds: %v", r.dir, err)
+		configReloads.WithLabelValues("error").Inc()
+		return
+	}
+	r.values = values
+	r.generation++
+	log.Infof("Configuration of %s changed, reloaded %d thresholds.", r.dir, len(values))
+	configReloads.WithLabelValues("success").Inc()
+}
+
+// apply reloads the files and sets the thresholds of the pool when they changed since the given generation, with the
+// pool definition's own thresholds taking precedence. It returns the generation applied. Thresholds are only written
+// on a change, so values adopted at runtime, e.g. from the region configuration, stand until the ConfigMap is edited.
+func (r *configReloader) apply(cfg *Config, definition poolDefinition, applied int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reload()
+	if applied == r.generation {
+		return applied
+	}
+
+	top := r.base
+	for _, setting := range reloadableSettings {
+		if value, found := r.values[setting.key]; found {
+			*setting.value(&top) = value
+		}
+	}
+	poolCfg := definition.apply(&top)
+
+	for _, setting := range reloadableSettings {
+		if previous, value := *setting.value(cfg), *setting.value(poolCfg); previous != value {
+			log.WithField("pool", cfg.PoolName).Infof("%s changed from %d to %d.", setting.key, previous, value)
+			*setting.value(cfg) = value
+		}
+	}
+	if cfg.PlaceholderGpus == 0 {
+		cfg.PlaceholderGpus = poolCfg.PlaceholderGpus
+	}
+
+	return r.generation
+}
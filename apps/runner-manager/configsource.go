@@ -0,0 +1,167 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ConfigFileSetting selects the optional YAML file of settings, set as a flag or in the environment
+const ConfigFileSetting = "CONFIG_FILE"
+
+// configLoader reads the settings of loadConfig from its layered sources: the built-in defaults are overridden by the
+// config file, the config file by the environment and the environment by the command-line flags. Every setting is
+// named after its environment variable; in the config file it is a top-level key and as a flag it is lower-cased
+// with dashes, e.g. MIN_IDLE_RUNNERS is --min-idle-runners. Validation errors are collected so all of them are
+// reported at once.
+type configLoader struct {
+	flags map[string]string
+	file  map[string]string
+	errs  []error
+
+	// used are the settings read, to warn about flags and config file settings that are never read
+	used map[string]bool
+}
+
+// newConfigLoader parses the command-line flags and reads the config file they or the environment select
+func newConfigLoader(args []string) (*configLoader, error) {
+	flags, err := parseFlags(args)
+	if err != nil {
+		return nil, err
+	}
+	l := &configLoader{flags: flags, used: make(map[string]bool)}
+
+	if path := l.get(ConfigFileSetting); path != "" {
+		l.file, err = readConfigFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", ConfigFileSetting, err)
+		}
+	}
+	return l, nil
+}
+
+// parseFlags parses arguments in the forms --name=value and --name value into settings. A flag without a value,
+// e.g. --predictive-scaling-enabled, is set to "true".
+func parseFlags(args []string) (map[string]string, error) {
+	flags := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || strings.Trim(arg, "-") == "" {
+			return nil, fmt.Errorf("unexpected argument %q, settings are passed as --name=value", arg)
+		}
+
+		name, value, found := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !found {
+			value = "true"
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				value = args[i+1]
+				i++
+			}
+		}
+		flags[flagSetting(name)] = value
+	}
+	return flags, nil
+}
+
+// flagSetting returns the setting of a flag name, e.g. MIN_IDLE_RUNNERS for min-idle-runners
+func flagSetting(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// settingFlag returns the flag name of a setting, e.g. --min-idle-runners for MIN_IDLE_RUNNERS
+func settingFlag(setting string) string {
+	return "--" + strings.ToLower(strings.ReplaceAll(setting, "_", "-"))
+}
+
+// readConfigFile reads a YAML mapping of settings to values. Lists and objects, e.g. of RUNNER_POOLS, are passed on
+// as JSON, the format of their environment variables.
+func readConfigFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Numbers are kept as written, so large integers are not turned into floats
+	var values map[string]any
+	useNumber := func(d *json.Decoder) *json.Decoder {
+		d.UseNumber()
+		return d
+	}
+	if err := yaml.Unmarshal(content, &values, useNumber); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	settings := make(map[string]string, len(values))
+	for key, value := range values {
+		switch value := value.(type) {
+		case nil:
+		case string:
+			settings[key] = value
+		case map[string]any, []any:
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s in %s: %v", key, path, err)
+			}
+			settings[key] = string(raw)
+		default:
+			settings[key] = fmt.Sprint(value)
+		}
+	}
+	return settings, nil
+}
+
+// lookup returns the value of the setting from the source of highest precedence and whether any source sets it
+func (l *configLoader) lookup(setting string) (string, bool) {
+	l.used[setting] = true
+	if value, found := l.flags[setting]; found {
+		return value, true
+	}
+	if value, found := os.LookupEnv(setting); found {
+		return value, true
+	}
+	value, found := l.file[setting]
+	return value, found
+}
+
+// get returns the value of the setting, empty when unset
+func (l *configLoader) get(setting string) string {
+	value, _ := l.lookup(setting)
+	return value
+}
+
+// errorf records a validation error
+func (l *configLoader) errorf(format string, args ...any) {
+	l.errs = append(l.errs, fmt.Errorf(format, args...))
+}
+
+// err returns the validation errors recorded, nil if there are none, and warns about the flags and config file
+// settings that were never read, e.g. misspelled ones
+func (l *configLoader) err() error {
+	var unused []string
+	for setting := range l.flags {
+		if !l.used[setting] {
+			unused = append(unused, settingFlag(setting))
+		}
+	}
+	for setting := range l.file {
+		if !l.used[setting] {
+			unused = append(unused, setting)
+		}
+	}
+	sort.Strings(unused)
+	for _, setting := range unused {
+		log.Warnf("Setting %s is not used by this configuration.", setting)
+	}
+
+	return errors.Join(l.errs...)
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import "time"
+
+// scaleCooldown keeps scale operations apart so bursty demand does not make the pool flap. A scale-up waits for the
+// scale-up cooldown since the previous scale-up. A scale-down, which includes deleting placeholders that are still
+// pending, waits for the scale-down cooldown since the previous scale-up or scale-down.
+type scaleCooldown struct {
+	scaleUp   time.Duration
+	scaleDown time.Duration
+
+	lastScaleUp   time.Time
+	lastScaleDown time.Time
+}
+
+func newScaleCooldown(cfg *Config) *scaleCooldown {
+	return &scaleCooldown{
+		scaleUp:   cfg.ScaleUpCooldown,
+		scaleDown: cfg.ScaleDownCooldown,
+	}
+}
+
+// scaleUpRemaining returns how long scale-ups are still held back, zero when they are allowed
+func (c *scaleCooldown) scaleUpRemaining() time.Duration {
+	return remainingCooldown(c.scaleUp, c.lastScaleUp)
+}
+
+// scaleDownRemaining returns how long scale-downs are still held back, zero when they are allowed
+func (c *scaleCooldown) scaleDownRemaining() time.Duration {
+	lastScale := c.lastScaleUp
+	if c.lastScaleDown.After(lastScale) {
+		lastScale = c.lastScaleDown
+	}
+	return remainingCooldown(c.scaleDown, lastScale)
+}
+
+func (c *scaleCooldown) recordScaleUp() {
+	c.lastScaleUp = time.Now()
+}
+
+func (c *scaleCooldown) recordScaleDown() {
+	c.lastScaleDown = time.Now()
+}
+
+func remainingCooldown(cooldown time.Duration, since time.Time) time.Duration {
+	if remaining := cooldown - time.Since(since); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
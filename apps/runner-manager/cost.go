@@ -0,0 +1,179 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/daytona/apps/runner-manager/pkg/status"
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// parseNodeHourlyPrices parses NODE_HOURLY_PRICES in the format "m5.xlarge=0.192,m5.2xlarge=0.384", the hourly price
+// of each instance type
+func parseNodeHourlyPrices(value string) (map[string]float64, error) {
+	prices := make(map[string]float64)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		instanceType, priceStr, found := strings.Cut(entry, "=")
+		instanceType = strings.TrimSpace(instanceType)
+		if !found || instanceType == "" {
+			return nil, fmt.Errorf("entry %q must be in the format instance-type=price", entry)
+		}
+		price, err := strconv.ParseFloat(strings.TrimSpace(priceStr), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price for instance type %s: %v", instanceType, err)
+		}
+		if price < 0 {
+			return nil, fmt.Errorf("price for instance type %s cannot be negative", instanceType)
+		}
+		prices[instanceType] = price
+	}
+	return prices, nil
+}
+
+// isCostAware reports whether node prices are configured
+func isCostAware(cfg *Config) bool {
+	return cfg.NodeHourlyPriceLabel != "" || len(cfg.NodeHourlyPrices) > 0
+}
+
+// nodeInstanceType returns the instance type the node is labeled with by its cloud provider
+func nodeInstanceType(node *corev1.Node) string {
+	if instanceType := node.Labels[corev1.LabelInstanceTypeStable]; instanceType != "" {
+		return instanceType
+	}
+	return node.Labels[corev1.LabelInstanceType]
+}
+
+// nodeHourlyPrice returns the hourly price of the node from its NODE_HOURLY_PRICE_LABEL label, or else from the
+// NODE_HOURLY_PRICES entry of its instance type. It returns false if the node has no known price.
+func nodeHourlyPrice(cfg *Config, node *corev1.Node) (float64, bool) {
+	if cfg.NodeHourlyPriceLabel != "" {
+		if priceStr, found := node.Labels[cfg.NodeHourlyPriceLabel]; found {
+			if price, err := strconv.ParseFloat(priceStr, 64); err == nil && price >= 0 {
+				return price, true
+			}
+		}
+	}
+	price, found := cfg.NodeHourlyPrices[nodeInstanceType(node)]
+	return price, found
+}
+
+// costTracker estimates the compute cost of the pool's running nodes every cycle, hibernated nodes being stopped, and
+// accumulates it over the current month. The month-to-date cost is kept in memory, it restarts with the process.
+type costTracker struct {
+	mu       sync.Mutex
+	cost     status.FleetCost
+	observed time.Time
+}
+
+func newCostTracker(cfg *Config) *costTracker {
+	return &costTracker{cost: status.FleetCost{RegionID: cfg.RegionID, Pool: cfg.PoolName}}
+}
+
+// observe records the cost of the cycle's nodes, adding the hourly cost of the previous cycle for the time elapsed
+// since then to the month-to-date cost
+func (t *costTracker) observe(cfg *Config, state *ClusterState) {
+	now := time.Now().UTC()
+	cost := status.FleetCost{RegionID: cfg.RegionID, Pool: cfg.PoolName, InstanceTypes: make(map[string]status.InstanceTypeCost), UpdatedAt: now}
+	for i := range state.Nodes {
+		node := &state.Nodes[i]
+		if _, hibernated := node.Annotations[HibernatedAtAnnotation]; hibernated {
+			continue
+		}
+		price, found := nodeHourlyPrice(cfg, node)
+		if !found {
+			cost.UnpricedNodes++
+			continue
+		}
+		instanceType := nodeInstanceType(node)
+		if instanceType == "" {
+			instanceType = "unknown"
+		}
+		typeCost := cost.InstanceTypes[instanceType]
+		typeCost.Nodes++
+		typeCost.HourlyCost += price
+		cost.InstanceTypes[instanceType] = typeCost
+		cost.HourlyCost += price
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	switch {
+	case t.observed.IsZero():
+		cost.MonthToDateSince = now
+	case t.observed.Before(monthStart):
+		// The previous cycle's cost is split at the start of the month
+		cost.MonthToDateSince = monthStart
+		cost.MonthToDateCost = t.cost.HourlyCost * now.Sub(monthStart).Hours()
+	default:
+		cost.MonthToDateSince = t.cost.MonthToDateSince
+		cost.MonthToDateCost = t.cost.MonthToDateCost + t.cost.HourlyCost*now.Sub(t.observed).Hours()
+	}
+	t.cost = cost
+	t.observed = now
+
+	fleetHourlyCost.WithLabelValues(cfg.RegionID, cfg.PoolName).Set(cost.HourlyCost)
+	fleetMonthToDateCost.WithLabelValues(cfg.RegionID, cfg.PoolName).Set(cost.MonthToDateCost)
+	unpricedNodes.WithLabelValues(cfg.RegionID, cfg.PoolName).Set(float64(cost.UnpricedNodes))
+}
+
+// sortByNodeCost orders runners by the hourly price of their node, the most expensive first, so scale-down removes
+// the costliest surplus capacity first. Runners on nodes without a known price come last.
+func sortByNodeCost(cfg *Config, state *ClusterState, runners []daytona.RunnerFull) {
+	price := func(runner daytona.RunnerFull) float64 {
+		node, found := state.NodeByIP[runner.GetDomain()]
+		if !found {
+			return -1
+		}
+		if price, found := nodeHourlyPrice(cfg, node); found {
+			return price
+		}
+		return -1
+	}
+	sort.SliceStable(runners, func(i, j int) bool { return price(runners[i]) > price(runners[j]) })
+}
+
+// costHandler serves the estimated compute cost of the selected pool
+func costHandler(pools []*runnerPool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		pool := selectPool(w, r, pools)
+		if pool == nil {
+			return
+		}
+		if pool.cost == nil {
+			http.Error(w, fmt.Sprintf("pool %s has no node prices, NODE_HOURLY_PRICES or NODE_HOURLY_PRICE_LABEL is not set", pool.cfg.PoolName), http.StatusNotFound)
+			return
+		}
+
+		pool.cost.mu.Lock()
+		cost := pool.cost.cost
+		pool.cost.mu.Unlock()
+		if cost.UpdatedAt.IsZero() {
+			http.Error(w, "no controller cycle completed yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(cost)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// PprofPath is the admin endpoint serving the pprof profiles of the runner-manager process
+const PprofPath = "/debug/pprof/"
+
+// registerPprofHandlers serves the pprof profiles on the mux behind the admin authentication, so memory growth and
+// goroutine leaks of a long-running runner-manager can be profiled without restarting it
+func registerPprofHandlers(mux *http.ServeMux, admin func(http.Handler) http.Handler) {
+	mux.Handle(PprofPath, admin(http.HandlerFunc(pprof.Index)))
+	mux.Handle(PprofPath+"cmdline", admin(http.HandlerFunc(pprof.Cmdline)))
+	mux.Handle(PprofPath+"profile", admin(http.HandlerFunc(pprof.Profile)))
+	mux.Handle(PprofPath+"symbol", admin(http.HandlerFunc(pprof.Symbol)))
+	mux.Handle(PprofPath+"trace", admin(http.HandlerFunc(pprof.Trace)))
+	log.Infof("Serving pprof profiles on %s.", PprofPath)
+}
+
+// enableRuntimeMetrics replaces the default Go collector, which only exports the basic memory and goroutine
+// statistics, with one exporting every Go runtime metric: heap classes, GC pauses, scheduler latencies and more
+func enableRuntimeMetrics() {
+	prometheus.Unregister(collectors.NewGoCollector())
+	prometheus.MustRegister(collectors.NewGoCollector(collectors.WithGoCollectorRuntimeMetrics(collectors.MetricsAll)))
+}
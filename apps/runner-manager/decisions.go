@@ -0,0 +1,250 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/daytona/apps/runner-manager/pkg/status"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultDecisionHistoryRetention is how long scaling decisions are kept in the decision history
+	DefaultDecisionHistoryRetention = 90 * 24 * time.Hour
+
+	// DefaultDecisionsLimit and MaxDecisionsLimit bound the number of decisions returned by a query
+	DefaultDecisionsLimit = 100
+	MaxDecisionsLimit     = 10000
+)
+
+// decisionHistory appends every scaling decision of the pools as JSON lines to a local file, which survives restarts
+// and is queried by the decisions endpoint for post-incident review
+type decisionHistory struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// openDecisionHistory opens the history file, dropping the decisions older than the retention period
+func openDecisionHistory(path string, retention time.Duration) (*decisionHistory, error) {
+	h := &decisionHistory{path: path}
+
+	decisions, err := h.read(decisionQuery{since: time.Now().Add(-retention)})
+	if err != nil {
+		return nil, err
+	}
+	if err := h.rewrite(decisions); err != nil {
+		return nil, fmt.Errorf("failed to compact decision history: %w", err)
+	}
+
+	h.file, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// recordDecision records a scaling action of the pool in its status and, when enabled, in the decision history with
+// the thresholds, metrics and state of the cycle it was made in
+func recordDecision(pool *runnerPool, history *decisionHistory, cfg *Config, state *ClusterState, metrics *ResourceMetrics, action string) {
+	pool.statuses.recordDecision(action)
+	state.Why.actions = append(state.Why.actions, action)
+	if history == nil {
+		return
+	}
+
+	decision := status.Decision{
+		Time:     time.Now().UTC(),
+		RegionID: cfg.RegionID,
+		Pool:     cfg.PoolName,
+		Action:   action,
+		Inputs: status.DecisionInputs{
+			MinIdleRunners:                cfg.MinIdleRunners,
+			MinIdleCpu:                    cfg.MinIdleCpu,
+			MinIdleMemory:                 cfg.MinIdleMemory,
+			MaxResourceUtilizationPercent: cfg.MaxResourceUtilizationPercent,
+			MaxNodes:                      cfg.MaxNodes,
+			ScaleDownUtilizationPercent:   cfg.ScaleDownUtilizationPercent,
+		},
+		Metrics: status.DecisionMetrics{
+			Capacity: status.Capacity{
+				TotalCPU:           metrics.TotalCPUCapacity,
+				TotalMemoryGiB:     metrics.TotalMemoryGiBCapacity,
+				AllocatedCPU:       metrics.TotalAllocatedCPU,
+				AllocatedMemoryGiB: metrics.TotalAllocatedMemoryGiB,
+				AvailableCPU:       metrics.TotalAvailableCPU,
+				AvailableMemoryGiB: metrics.TotalAvailableMemoryGiB,
+			},
+			Runners: status.RunnerCounts{
+				Total:     len(state.Runners),
+				Active:    len(state.ActiveRunners),
+				Idle:      len(state.IdleRunners),
+				Deletable: len(state.DeletableRunners),
+			},
+			Nodes:               len(state.Nodes),
+			NascentNodes:        len(state.NascentNodes),
+			PendingPlaceholders: len(state.PendingPlaceholders),
+			QueuedSandboxes:     state.QueuedSandboxes,
+		},
+		Outcome: status.DecisionOutcome{
+			PlaceholdersCreated: state.PlaceholdersCreated,
+		},
+	}
+	if err := history.record(decision); err != nil {
+		log.Errorf("Error recording scaling decision: %v", err)
+	}
+}
+
+// record appends a decision to the history
+func (h *decisionHistory) record(decision status.Decision) error {
+	line, err := json.Marshal(decision)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err = h.file.Write(append(line, '\n'))
+	return err
+}
+
+// decisionQuery filters the decision history, zero fields match every decision
+type decisionQuery struct {
+	pool   string
+	action string
+	since  time.Time
+	until  time.Time
+}
+
+func (q decisionQuery) matches(decision status.Decision) bool {
+	return (q.pool == "" || decision.Pool == q.pool) &&
+		(q.action == "" || decision.Action == q.action) &&
+		(q.since.IsZero() || !decision.Time.Before(q.since)) &&
+		(q.until.IsZero() || !decision.Time.After(q.until))
+}
+
+// read returns the decisions matching the query in the order they were made. Lines that cannot be parsed, such as one
+// truncated by a crash, are skipped.
+func (h *decisionHistory) read(query decisionQuery) ([]status.Decision, error) {
+	file, err := os.Open(h.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var decisions []status.Decision
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var decision status.Decision
+		if err := json.Unmarshal(scanner.Bytes(), &decision); err != nil {
+			continue
+		}
+		if query.matches(decision) {
+			decisions = append(decisions, decision)
+		}
+	}
+	return decisions, scanner.Err()
+}
+
+// rewrite replaces the history file with the given decisions
+func (h *decisionHistory) rewrite(decisions []status.Decision) error {
+	tmp, err := os.CreateTemp(filepath.Dir(h.path), filepath.Base(h.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	for _, decision := range decisions {
+		if err = encoder.Encode(decision); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), h.path)
+}
+
+// decisionsHandler returns the selected pool's decisions within the since and until RFC 3339 bounds, optionally of a
+// single action, the latest ones up to the limit in the order they were made
+func decisionsHandler(pools []*runnerPool, history *decisionHistory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		pool := selectPool(w, r, pools)
+		if pool == nil {
+			return
+		}
+
+		params := r.URL.Query()
+		query := decisionQuery{pool: pool.cfg.PoolName, action: params.Get("action")}
+		for _, bound := range []struct {
+			param  string
+			target *time.Time
+		}{
+			{"since", &query.since},
+			{"until", &query.until},
+		} {
+			if value := params.Get(bound.param); value != "" {
+				t, err := time.Parse(time.RFC3339, value)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("invalid %s: %v", bound.param, err), http.StatusBadRequest)
+					return
+				}
+				*bound.target = t
+			}
+		}
+		limit := DefaultDecisionsLimit
+		if value := params.Get("limit"); value != "" {
+			var err error
+			limit, err = strconv.Atoi(value)
+			if err != nil || limit <= 0 || limit > MaxDecisionsLimit {
+				http.Error(w, fmt.Sprintf("limit must be between 1 and %d", MaxDecisionsLimit), http.StatusBadRequest)
+				return
+			}
+		}
+
+		decisions, err := history.read(query)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read decision history: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if len(decisions) > limit {
+			decisions = decisions[len(decisions)-limit:]
+		}
+		if decisions == nil {
+			decisions = []status.Decision{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(decisions)
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// getNodeAllocatableDiskGiB returns the ephemeral storage of the node available to pods in GiB, the disk capacity of
+// nodes whose runner has not reported its own yet
+func getNodeAllocatableDiskGiB(node *corev1.Node) float32 {
+	diskAllocatable := node.Status.Allocatable[corev1.ResourceEphemeralStorage]
+	return float32(diskAllocatable.Value()) / (1024 * 1024 * 1024)
+}
+
+// isDiskUtilizationTooHigh reports whether the allocated disk exceeds MAX_DISK_UTILIZATION_PERCENT, never when the
+// threshold is unset
+func isDiskUtilizationTooHigh(metrics *ResourceMetrics, cfg *Config) bool {
+	if cfg.MaxDiskUtilizationPercent == 0 || metrics.TotalDiskGiBCapacity <= 0 {
+		return false
+	}
+	return (metrics.TotalAllocatedDiskGiB/metrics.TotalDiskGiBCapacity)*100 > float32(cfg.MaxDiskUtilizationPercent)
+}
@@ -0,0 +1,218 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/daytona/apps/runner-manager/pkg/status"
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DrainStartedAtAnnotation records when runner-manager first saw the node's runner draining
+const DrainStartedAtAnnotation = "daytona.io/drain-started-at"
+
+// drainStore keeps the progress of draining nodes between controller cycles
+type drainStore struct {
+	mu     sync.Mutex
+	drains map[string]status.NodeDrain // By node name
+}
+
+func newDrainStore() *drainStore {
+	return &drainStore{
+		drains: make(map[string]status.NodeDrain),
+	}
+}
+
+// list returns the drains in progress, longest running first
+func (s *drainStore) list() []status.NodeDrain {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	drains := make([]status.NodeDrain, 0, len(s.drains))
+	for _, drain := range s.drains {
+		drains = append(drains, drain)
+	}
+	sort.Slice(drains, func(i, j int) bool { return drains[i].Since.Before(drains[j].Since) })
+	return drains
+}
+
+// trackDrains refreshes the progress of nodes whose runner is draining and records node events when a drain
+// starts, makes progress, completes or is cancelled
+func trackDrains(backend clusterBackend, apiClient *daytona.APIClient, regionID string, state *ClusterState, drains *drainStore) {
+	draining := make(map[string]daytona.RunnerFull)
+	runnerIDs := make(map[string]bool)
+	for _, runner := range state.ActiveRunners {
+		if _, found := state.NodeByIP[runner.GetDomain()]; found && runner.GetUnschedulable() {
+			draining[runner.GetId()] = runner
+			runnerIDs[runner.GetId()] = true
+		}
+	}
+
+	var sandboxesByRunner map[string][]daytona.Sandbox
+	if len(draining) > 0 {
+		var err error
+		sandboxesByRunner, err = listRunnerSandboxes(apiClient, regionID, runnerIDs)
+		if err != nil {
+			log.Warnf("Could not list sandboxes of draining runners, keeping the previous drain progress: %v", err)
+			return
+		}
+	}
+
+	drains.mu.Lock()
+	defer drains.mu.Unlock()
+
+	now := time.Now()
+	current := make(map[string]status.NodeDrain)
+	for _, runner := range draining {
+		node := state.NodeByIP[runner.GetDomain()]
+		previous, tracked := drains.drains[node.Name]
+
+		drain := buildNodeDrain(node, runner, sandboxesByRunner[runner.GetId()], now)
+		drain.Since = drainStartedAt(backend, node, now)
+		drain.InitialSandboxes = drain.SandboxesRemaining
+		if tracked {
+			drain.InitialSandboxes = previous.InitialSandboxes
+		}
+		current[node.Name] = drain
+		drainRemainingSandboxes.WithLabelValues(node.Name).Set(float64(drain.SandboxesRemaining))
+
+		switch {
+		case !tracked:
+			recordNodeEvent(backend, node, corev1.EventTypeNormal, "DrainStarted", fmt.Sprintf("Runner %s is draining, %s", runner.GetId(), describeDrain(drain)))
+		case previous.SandboxesRemaining != drain.SandboxesRemaining:
+			recordNodeEvent(backend, node, corev1.EventTypeNormal, "DrainProgress", describeDrain(drain))
+		}
+	}
+
+	for nodeName, previous := range drains.drains {
+		if _, found := current[nodeName]; found {
+			continue
+		}
+		drainRemainingSandboxes.DeleteLabelValues(nodeName)
+
+		node, found := state.NodeByIP[previous.Domain]
+		if !found || node.Name != nodeName {
+			// The node was removed, there is nothing left to record the outcome on
+			continue
+		}
+
+		if runner, found := state.RunnerByDomain[previous.Domain]; found && runner.GetUnschedulable() {
+			recordNodeEvent(backend, node, corev1.EventTypeNormal, "DrainCompleted", fmt.Sprintf("Runner %s has no sandboxes left after draining for %s", previous.RunnerID, now.Sub(previous.Since).Round(time.Minute)))
+		} else if found {
+			recordNodeEvent(backend, node, corev1.EventTypeNormal, "DrainCancelled", fmt.Sprintf("Runner %s is schedulable again", previous.RunnerID))
+		}
+		if err := setNodeTimeAnnotation(backend, node.Name, DrainStartedAtAnnotation, nil); err != nil {
+			log.Errorf("Error clearing drain start of node %s: %v", node.Name, err)
+		}
+	}
+
+	drains.drains = current
+}
+
+// buildNodeDrain computes the progress of a draining node from the sandboxes still hosted on its runner
+func buildNodeDrain(node *corev1.Node, runner daytona.RunnerFull, sandboxes []daytona.Sandbox, now time.Time) status.NodeDrain {
+	drain := status.NodeDrain{
+		NodeName:  node.Name,
+		RunnerID:  runner.GetId(),
+		Domain:    runner.GetDomain(),
+		Sandboxes: []status.DrainSandbox{},
+	}
+
+	if scheduled, found := node.Annotations[EvictionAtAnnotation]; found {
+		if evictionAt, err := time.Parse(time.RFC3339, scheduled); err == nil {
+			drain.EvictionAt = &evictionAt
+		}
+	}
+
+	estimatedCompletion := now
+	for _, sandbox := range sandboxes {
+		state := sandbox.GetState()
+		if state == daytona.SANDBOXSTATE_DESTROYED || state == daytona.SANDBOXSTATE_ARCHIVED {
+			continue
+		}
+
+		drainSandbox := status.DrainSandbox{ID: sandbox.GetId(), State: string(state)}
+		if state == daytona.SANDBOXSTATE_STARTED {
+			drain.RunningSandboxes++
+			drainSandbox.AutoStopAt = estimateAutoStop(sandbox)
+			if drainSandbox.AutoStopAt == nil {
+				drain.BlockingSandboxes++
+			} else if drainSandbox.AutoStopAt.After(estimatedCompletion) {
+				estimatedCompletion = *drainSandbox.AutoStopAt
+			}
+		}
+		drain.Sandboxes = append(drain.Sandboxes, drainSandbox)
+	}
+	drain.SandboxesRemaining = len(drain.Sandboxes)
+
+	if drain.BlockingSandboxes == 0 {
+		drain.EstimatedCompletion = &estimatedCompletion
+	}
+
+	return drain
+}
+
+// estimateAutoStop returns when a running sandbox is expected to auto-stop, or nil if auto-stop is disabled.
+// The API does not expose the last activity, so the sandbox's last update stands in for it and the
+// estimate is a lower bound for sandboxes that are still in use.
+func estimateAutoStop(sandbox daytona.Sandbox) *time.Time {
+	interval := sandbox.GetAutoStopInterval()
+	if interval <= 0 {
+		return nil
+	}
+
+	updatedAt, err := time.Parse(time.RFC3339, sandbox.GetUpdatedAt())
+	if err != nil {
+		return nil
+	}
+
+	autoStopAt := updatedAt.Add(time.Duration(interval) * time.Minute)
+	return &autoStopAt
+}
+
+// drainStartedAt returns when the node started draining, annotating it the first time the drain is seen
+// so the start survives runner-manager restarts
+func drainStartedAt(backend clusterBackend, node *corev1.Node, now time.Time) time.Time {
+	if annotation, found := node.Annotations[DrainStartedAtAnnotation]; found {
+		if startedAt, err := time.Parse(time.RFC3339, annotation); err == nil {
+			return startedAt
+		}
+	}
+
+	startedAt := now.UTC().Truncate(time.Second)
+	if err := setNodeTimeAnnotation(backend, node.Name, DrainStartedAtAnnotation, &startedAt); err != nil {
+		log.Errorf("Error recording drain start of node %s: %v", node.Name, err)
+	}
+	return startedAt
+}
+
+func describeDrain(drain status.NodeDrain) string {
+	description := fmt.Sprintf("%d of %d sandboxes remaining (%d running)", drain.SandboxesRemaining, drain.InitialSandboxes, drain.RunningSandboxes)
+	if drain.EstimatedCompletion != nil {
+		return fmt.Sprintf("%s, running sandboxes expected to auto-stop by %s", description, drain.EstimatedCompletion.UTC().Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s, %d running sandboxes have auto-stop disabled", description, drain.BlockingSandboxes)
+}
+
+// drainsHandler serves the progress of the nodes whose runner is draining
+func drainsHandler(drains *drainStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(drains.list())
+	}
+}
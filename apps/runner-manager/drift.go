@@ -0,0 +1,165 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultConfigDriftCheckInterval defines how often the region configuration is compared with the local one
+	DefaultConfigDriftCheckInterval = 5 * time.Minute
+)
+
+// regionPoolConfig is the runner pool configuration stored on the region in the Daytona API.
+// Unset fields are not managed by the control plane and never reported as drift.
+type regionPoolConfig struct {
+	MinIdleRunners                *int
+	MinIdleCpu                    *int
+	MinIdleMemory                 *int
+	MaxResourceUtilizationPercent *int
+	NodeSelectorKey               *string
+	TaintKey                      *string
+
+	// RunnerVersion is the version runners are replaced with when RUNNER_ROLLOUT_ENABLED is set, not a drift
+	RunnerVersion *string
+}
+
+// configDrift is a single discrepancy between the region configuration and the local one
+type configDrift struct {
+	Field    string
+	Expected string
+	Actual   string
+	// invalid is why the expected value cannot be adopted, nil when it is valid
+	invalid error
+	// adopt applies the expected value to the local configuration, nil when the field cannot be changed at runtime
+	adopt func()
+}
+
+// fetchRegionPoolConfig reads the expected runner pool configuration from the region, or nil if none is stored
+func fetchRegionPoolConfig(apiClient *daytona.APIClient, regionID string) (*regionPoolConfig, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	region, _, err := apiClient.OrganizationsAPI.GetRegionById(ctx, regionID).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get region %s from Daytona API: %w", regionID, err)
+	}
+
+	pool := region.RunnerPool.Get()
+	if pool == nil {
+		return nil, nil
+	}
+
+	optionalInt := func(value *int32) *int {
+		if value == nil {
+			return nil
+		}
+		v := int(*value)
+		return &v
+	}
+	return &regionPoolConfig{
+		MinIdleRunners:                optionalInt(pool.MinIdleRunners),
+		MinIdleCpu:                    optionalInt(pool.MinIdleCpu),
+		MinIdleMemory:                 optionalInt(pool.MinIdleMemory),
+		MaxResourceUtilizationPercent: optionalInt(pool.MaxResourceUtilizationPercent),
+		NodeSelectorKey:               pool.NodeSelectorKey,
+		TaintKey:                      pool.TaintKey,
+		RunnerVersion:                 pool.RunnerVersion,
+	}, nil
+}
+
+// detectConfigDrift compares the expected pool configuration with the local one. Expected values are held to the
+// same bounds loadConfig holds the environment to, an out of bounds one is reported but never adopted.
+func detectConfigDrift(expected *regionPoolConfig, cfg *Config) []configDrift {
+	var drifts []configDrift
+
+	compareInt := func(field string, expected *int, actual *int, validate func(int) error) {
+		if expected == nil || *expected == *actual {
+			return
+		}
+		value := *expected
+		drift := configDrift{
+			Field:    field,
+			Expected: fmt.Sprint(value),
+			Actual:   fmt.Sprint(*actual),
+			invalid:  validate(value),
+		}
+		if drift.invalid == nil {
+			drift.adopt = func() { *actual = value }
+		}
+		drifts = append(drifts, drift)
+	}
+	nonNegative := func(value int) error {
+		if value < 0 {
+			return fmt.Errorf("cannot be negative")
+		}
+		return nil
+	}
+	utilizationPercent := func(value int) error {
+		if value < 1 || value > 100 {
+			return fmt.Errorf("must be between 1 and 100")
+		}
+		if cfg.ScaleDownUtilizationPercent != 0 && value <= cfg.ScaleDownUtilizationPercent {
+			return fmt.Errorf("must be above SCALE_DOWN_UTILIZATION_PERCENT (%d)", cfg.ScaleDownUtilizationPercent)
+		}
+		return nil
+	}
+	compareConst := func(field string, expected *string, actual string) {
+		if expected == nil || *expected == actual {
+			return
+		}
+		drifts = append(drifts, configDrift{
+			Field:    field,
+			Expected: *expected,
+			Actual:   actual,
+		})
+	}
+
+	compareInt("minIdleRunners", expected.MinIdleRunners, &cfg.MinIdleRunners, nonNegative)
+	compareInt("minIdleCpu", expected.MinIdleCpu, &cfg.MinIdleCpu, nonNegative)
+	compareInt("minIdleMemory", expected.MinIdleMemory, &cfg.MinIdleMemory, nonNegative)
+	compareInt("maxResourceUtilizationPercent", expected.MaxResourceUtilizationPercent, &cfg.MaxResourceUtilizationPercent, utilizationPercent)
+	compareConst("nodeSelectorKey", expected.NodeSelectorKey, cfg.NodeSelectorKey)
+	compareConst("taintKey", expected.TaintKey, cfg.TaintKey)
+
+	return drifts
+}
+
+// checkConfigDrift reports the drift between the region and local configuration, adopting the region's values when enabled
+func checkConfigDrift(apiClient *daytona.APIClient, cfg *Config) {
+	expected, err := fetchRegionPoolConfig(apiClient, cfg.RegionID)
+	if err != nil {
+		log.Warnf("Could not check configuration drift: %v", err)
+		return
+	}
+
+	configDriftDetected.Reset()
+	if expected == nil {
+		return
+	}
+
+	drifts := detectConfigDrift(expected, cfg)
+	for _, drift := range drifts {
+		configDriftDetected.WithLabelValues(drift.Field).Set(1)
+
+		if drift.invalid != nil {
+			log.Warnf("Configuration drift on %s: region value %s is invalid (%v), runner-manager keeps %s", drift.Field, drift.Expected, drift.invalid, drift.Actual)
+			continue
+		}
+		if cfg.ConfigDriftAdopt && drift.adopt != nil {
+			drift.adopt()
+			log.Infof("Configuration drift on %s: adopted region value %s (was %s)", drift.Field, drift.Expected, drift.Actual)
+			configDriftDetected.WithLabelValues(drift.Field).Set(0)
+			continue
+		}
+		log.Warnf("Configuration drift on %s: region expects %s, runner-manager uses %s", drift.Field, drift.Expected, drift.Actual)
+	}
+}
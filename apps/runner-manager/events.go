@@ -0,0 +1,154 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// EventComponent is the source component of the Kubernetes events emitted by runner-manager
+	EventComponent = "runner-manager"
+
+	// Reasons of the events explaining scaling decisions
+	EventReasonScaleUp          = "ScaleUp"
+	EventReasonScaleUpCancelled = "ScaleUpCancelled"
+	EventReasonScaleDown        = "ScaleDown"
+	EventReasonScaleDownSkipped = "ScaleDownSkipped"
+
+	// nodeEventNamespace is where events about cluster-scoped nodes are recorded, matching the kubelet
+	nodeEventNamespace = metav1.NamespaceDefault
+
+	// eventSeriesTTL is how long a repeated event updates the count of the previous one instead of creating another,
+	// below the API server's default event TTL of an hour
+	eventSeriesTTL = 50 * time.Minute
+)
+
+// eventSeries is an event recorded by runner-manager, updated when the same event repeats like kubelet events
+type eventSeries struct {
+	name     string
+	count    int32
+	lastSeen time.Time
+}
+
+// eventSeriesCache keeps the events recorded recently, so decisions repeated every cycle show up as a single event
+// with a count in kubectl describe
+type eventSeriesCache struct {
+	mu     sync.Mutex
+	series map[string]*eventSeries
+}
+
+// RecordNodeEvent emits a Kubernetes event about the node so it shows up in kubectl describe and event exporters
+func (b *kubernetesBackend) RecordNodeEvent(ctx context.Context, node *corev1.Node, eventType, reason, message string) error {
+	return b.recordEvent(ctx, nodeEventNamespace, corev1.ObjectReference{
+		Kind:       "Node",
+		APIVersion: "v1",
+		Name:       node.Name,
+		UID:        node.UID,
+	}, eventType, reason, message)
+}
+
+// RecordPlaceholderEvent emits a Kubernetes event about the placeholder pod
+func (b *kubernetesBackend) RecordPlaceholderEvent(ctx context.Context, pod *corev1.Pod, eventType, reason, message string) error {
+	return b.recordEvent(ctx, pod.Namespace, corev1.ObjectReference{
+		Kind:       "Pod",
+		APIVersion: "v1",
+		Namespace:  pod.Namespace,
+		Name:       pod.Name,
+		UID:        pod.UID,
+	}, eventType, reason, message)
+}
+
+// recordEvent creates the event, or increments the count of the same event recorded within the series TTL
+func (b *kubernetesBackend) recordEvent(ctx context.Context, namespace string, object corev1.ObjectReference, eventType, reason, message string) error {
+	b.events.mu.Lock()
+	defer b.events.mu.Unlock()
+
+	now := metav1.Now()
+	if b.events.series == nil {
+		b.events.series = make(map[string]*eventSeries)
+	}
+	for key, series := range b.events.series {
+		if now.Sub(series.lastSeen) > eventSeriesTTL {
+			delete(b.events.series, key)
+		}
+	}
+
+	key := fmt.Sprintf("%s/%s/%s/%s/%s/%s", object.Kind, object.Namespace, object.Name, object.UID, reason, message)
+	if series, found := b.events.series[key]; found {
+		patch, err := json.Marshal(map[string]any{"count": series.count + 1, "lastTimestamp": now})
+		if err != nil {
+			return err
+		}
+		_, err = b.clientset.CoreV1().Events(namespace).Patch(ctx, series.name, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err == nil {
+			series.count++
+			series.lastSeen = now.Time
+			return nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		// The event expired, a new series starts
+		delete(b.events.series, key)
+	}
+
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", object.Name, now.UnixNano()),
+			Namespace: namespace,
+		},
+		InvolvedObject: object,
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		Source:         corev1.EventSource{Component: EventComponent},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+
+	created, err := b.clientset.CoreV1().Events(namespace).Create(ctx, event, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	b.events.series[key] = &eventSeries{name: created.Name, count: 1, lastSeen: now.Time}
+	return nil
+}
+
+// recordNodeEvent emits an event about the node, logging failures as events are informational
+func recordNodeEvent(backend clusterBackend, node *corev1.Node, eventType, reason, message string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := backend.RecordNodeEvent(ctx, node, eventType, reason, message); err != nil {
+		log.Errorf("Error recording %s event for node %s: %v", reason, node.Name, err)
+	}
+}
+
+// recordPlaceholderEvent emits an event about the placeholder pod, logging failures as events are informational
+func recordPlaceholderEvent(backend clusterBackend, pod *corev1.Pod, eventType, reason, message string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := backend.RecordPlaceholderEvent(ctx, pod, eventType, reason, message); err != nil {
+		log.Errorf("Error recording %s event for placeholder pod %s: %v", reason, pod.Name, err)
+	}
+}
+
+// recordScaleDownSkipped emits the reason a deletable runner's node is kept
+func recordScaleDownSkipped(backend clusterBackend, node *corev1.Node, reason string) {
+	recordNodeEvent(backend, node, corev1.EventTypeNormal, EventReasonScaleDownSkipped, reason)
+}
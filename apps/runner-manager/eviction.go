@@ -0,0 +1,211 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/daytonaio/common-go/pkg/eviction"
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// EvictionAtAnnotation records when a node whose runner is being drained is expected to be removed
+	EvictionAtAnnotation = "daytona.io/eviction-at"
+
+	// DefaultEvictionNoticeLeadTime is the advance warning given when EVICTION_NOTICE_LEAD_TIME is not set
+	DefaultEvictionNoticeLeadTime = time.Hour
+)
+
+// setNodeEvictionAt annotates the node with its scheduled removal time, or clears the annotation when evictionAt is nil
+func setNodeEvictionAt(backend clusterBackend, nodeName string, evictionAt *time.Time) error {
+	return setNodeTimeAnnotation(backend, nodeName, EvictionAtAnnotation, evictionAt)
+}
+
+// setNodeTimeAnnotation sets a timestamp annotation on the node, or clears it when at is nil
+func setNodeTimeAnnotation(backend clusterBackend, nodeName string, annotation string, at *time.Time) error {
+	var value *string
+	if at != nil {
+		formatted := at.UTC().Format(time.RFC3339)
+		value = &formatted
+	}
+
+	return backend.PatchNode(context.Background(), nodeName, map[string]*string{annotation: value}, nil)
+}
+
+// scheduleEvictions schedules the removal of nodes whose runner was made unschedulable while still hosting sandboxes
+// and cancels it for runners that became schedulable again. It returns the eviction time per runner ID.
+func scheduleEvictions(backend clusterBackend, state *ClusterState, leadTime time.Duration) map[string]time.Time {
+	evictions := make(map[string]time.Time)
+
+	for _, runner := range state.ActiveRunners {
+		node, found := state.NodeByIP[runner.GetDomain()]
+		if !found {
+			continue
+		}
+
+		scheduled, hasAnnotation := node.Annotations[EvictionAtAnnotation]
+		if !runner.GetUnschedulable() {
+			if hasAnnotation {
+				log.Infof("Runner on node %s is schedulable again. Cancelling its scheduled eviction.", node.Name)
+				if err := setNodeEvictionAt(backend, node.Name, nil); err != nil {
+					log.Errorf("Error clearing eviction time of node %s: %v", node.Name, err)
+				}
+			}
+			continue
+		}
+
+		if hasAnnotation {
+			evictionAt, err := time.Parse(time.RFC3339, scheduled)
+			if err == nil {
+				evictions[runner.GetId()] = evictionAt
+				continue
+			}
+			log.Warnf("Node %s has an invalid %s annotation %q, rescheduling its eviction.", node.Name, EvictionAtAnnotation, scheduled)
+		}
+
+		evictionAt := time.Now().Add(leadTime).UTC().Truncate(time.Second)
+		if err := setNodeEvictionAt(backend, node.Name, &evictionAt); err != nil {
+			log.Errorf("Error scheduling eviction of node %s: %v", node.Name, err)
+			continue
+		}
+		log.Infof("Runner on node %s is draining. Scheduled its eviction at %s.", node.Name, evictionAt.Format(time.RFC3339))
+		evictions[runner.GetId()] = evictionAt
+	}
+
+	return evictions
+}
+
+// gatherEvictionNotices lists the sandboxes hosted on runners scheduled for eviction
+func gatherEvictionNotices(apiClient *daytona.APIClient, regionID string, evictions map[string]time.Time) ([]eviction.Notice, error) {
+	runnerIDs := make(map[string]bool, len(evictions))
+	for runnerID := range evictions {
+		runnerIDs[runnerID] = true
+	}
+
+	sandboxesByRunner, err := listRunnerSandboxes(apiClient, regionID, runnerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var notices []eviction.Notice
+	for runnerID, sandboxes := range sandboxesByRunner {
+		for _, sandbox := range sandboxes {
+			notices = append(notices, eviction.Notice{SandboxId: sandbox.GetId(), EvictionAt: evictions[runnerID]})
+		}
+	}
+
+	return notices, nil
+}
+
+// listRunnerSandboxes lists the sandboxes of the region hosted on the given runners, grouped by runner ID
+func listRunnerSandboxes(apiClient *daytona.APIClient, regionID string, runnerIDs map[string]bool) (map[string][]daytona.Sandbox, error) {
+	sandboxes, err := listRegionSandboxes(apiClient, regionID)
+	if err != nil {
+		return nil, err
+	}
+
+	sandboxesByRunner := make(map[string][]daytona.Sandbox)
+	for _, sandbox := range sandboxes {
+		if runnerIDs[sandbox.GetRunnerId()] {
+			sandboxesByRunner[sandbox.GetRunnerId()] = append(sandboxesByRunner[sandbox.GetRunnerId()], sandbox)
+		}
+	}
+
+	return sandboxesByRunner, nil
+}
+
+// listRegionSandboxes lists all sandboxes of the region
+func listRegionSandboxes(apiClient *daytona.APIClient, regionID string) ([]daytona.Sandbox, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var all []daytona.Sandbox
+
+	for page := 1; ; page++ {
+		sandboxes, _, err := apiClient.SandboxAPI.ListSandboxesPaginated(ctx).
+			Regions([]string{regionID}).
+			Page(float32(page)).
+			Limit(SandboxListPageSize).
+			Execute()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list sandboxes from Daytona API: %w", err)
+		}
+
+		all = append(all, sandboxes.Items...)
+
+		if float32(page) >= sandboxes.TotalPages || len(sandboxes.Items) == 0 {
+			break
+		}
+	}
+
+	return all, nil
+}
+
+// notifyEvictions schedules node evictions and sends the resulting per-sandbox notices to the configured proxies
+func notifyEvictions(backend clusterBackend, apiClient *daytona.APIClient, cfg *Config, state *ClusterState) {
+	evictions := scheduleEvictions(backend, state, cfg.EvictionNoticeLeadTime)
+	if len(evictions) == 0 {
+		return
+	}
+
+	notices, err := gatherEvictionNotices(apiClient, cfg.RegionID, evictions)
+	if err != nil {
+		log.Errorf("Error gathering eviction notices: %v", err)
+		return
+	}
+	if len(notices) == 0 {
+		return
+	}
+
+	source, err := os.Hostname()
+	if err != nil {
+		source = "runner-manager"
+	}
+	batch := &eviction.NoticeBatch{Source: source, Notices: notices}
+
+	for _, proxyURL := range cfg.EvictionNoticeProxyURLs {
+		if err := sendEvictionNotices(proxyURL, cfg.EvictionNoticeToken, batch); err != nil {
+			log.Errorf("Error sending eviction notices to proxy %s: %v", proxyURL, err)
+		}
+	}
+}
+
+func sendEvictionNotices(proxyURL string, token string, batch *eviction.NoticeBatch) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, proxyURL+eviction.NoticePath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("proxy returned status %d", resp.StatusCode)
+	}
+	return nil
+}
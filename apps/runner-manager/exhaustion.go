@@ -0,0 +1,56 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultCapacityExhaustionThreshold is how long a placeholder may stay pending before the cluster is considered
+	// out of capacity when CAPACITY_EXHAUSTION_THRESHOLD is not set
+	DefaultCapacityExhaustionThreshold = 15 * time.Minute
+
+	// EventReasonCapacityExhausted is the reason of the event recorded on a placeholder pending past the threshold
+	EventReasonCapacityExhausted = "CapacityExhausted"
+)
+
+// detectCapacityExhaustion reports the placeholders pending past CAPACITY_EXHAUSTION_THRESHOLD. Their node was never
+// added, most likely because the cloud provider's quota is reached or the instance type is out of stock, which
+// scaling up further cannot fix. The placeholders are left in place so they are scheduled once capacity returns.
+func detectCapacityExhaustion(backend clusterBackend, cfg *Config, state *ClusterState) {
+	for _, pod := range state.PendingPlaceholders {
+		pending := time.Since(pod.CreationTimestamp.Time)
+		if pending <= cfg.CapacityExhaustionThreshold {
+			continue
+		}
+		state.ExhaustedPlaceholders++
+
+		message := fmt.Sprintf("Pending for %s without a node being added, the cloud provider's quota may be reached or the instance type out of stock", pending.Round(time.Second))
+		if reason := unschedulableReason(pod); reason != "" {
+			message += ": " + reason
+		}
+		recordPlaceholderEvent(backend, pod, corev1.EventTypeWarning, EventReasonCapacityExhausted, message)
+	}
+
+	capacityExhaustedPlaceholders.WithLabelValues(cfg.RegionID, cfg.PoolName).Set(float64(state.ExhaustedPlaceholders))
+	if state.ExhaustedPlaceholders > 0 {
+		log.WithField("reason", "capacity-exhausted").Warnf("%d placeholder pods pending for more than %s, the cluster cannot add nodes.", state.ExhaustedPlaceholders, cfg.CapacityExhaustionThreshold)
+	}
+}
+
+// unschedulableReason returns the scheduler's message for a pod it could not place, empty if it has none
+func unschedulableReason(pod *corev1.Pod) string {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+			return condition.Message
+		}
+	}
+	return ""
+}
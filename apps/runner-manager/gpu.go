@@ -0,0 +1,61 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GPUResourceName is the extended resource the NVIDIA device plugin advertises GPUs as, requested by the
+// placeholders of GPU pools so the infrastructure autoscaler provisions GPU nodes
+const GPUResourceName corev1.ResourceName = "nvidia.com/gpu"
+
+// isGPUPool reports whether the pool's placeholders request GPUs, GPUs are then tracked as a resource dimension
+func isGPUPool(cfg *Config) bool {
+	return cfg.PlaceholderGpus > 0
+}
+
+// getNodeAllocatableGPUs returns the GPUs of the node available to pods, zero on nodes without the device plugin
+func getNodeAllocatableGPUs(node *corev1.Node) float32 {
+	gpuAllocatable := node.Status.Allocatable[GPUResourceName]
+	return float32(gpuAllocatable.Value())
+}
+
+// gatherAllocatedGPUs returns the GPUs allocated to started sandboxes by runner ID, as runners only report the GPUs
+// they have
+func gatherAllocatedGPUs(apiClient *daytona.APIClient, regionID string) (map[string]float32, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	allocated := make(map[string]float32)
+
+	for page := 1; ; page++ {
+		sandboxes, _, err := apiClient.SandboxAPI.ListSandboxesPaginated(ctx).
+			Regions([]string{regionID}).
+			States([]string{string(daytona.SANDBOXSTATE_STARTED)}).
+			Page(float32(page)).
+			Limit(SandboxListPageSize).
+			Execute()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list started sandboxes from Daytona API: %w", err)
+		}
+
+		for _, sandbox := range sandboxes.Items {
+			if runnerId := sandbox.GetRunnerId(); runnerId != "" && sandbox.GetGpu() > 0 {
+				allocated[runnerId] += sandbox.GetGpu()
+			}
+		}
+
+		if float32(page) >= sandboxes.TotalPages || len(sandboxes.Items) == 0 {
+			break
+		}
+	}
+
+	return allocated, nil
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/daytonaio/common-go/pkg/protection"
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// HealthRemediationStartedAtAnnotation records when runner-manager cordoned the node of an unhealthy runner and
+	// requested its restart, so the node is uncordoned if the runner recovers and replaced if it does not
+	HealthRemediationStartedAtAnnotation = "daytona.io/health-remediation-started-at"
+
+	// HealthRemediationReplacedAtAnnotation records when the placeholder of a node whose runner did not recover was
+	// deleted, the node is then left for the cluster autoscaler to remove
+	HealthRemediationReplacedAtAnnotation = "daytona.io/health-remediation-replaced-at"
+
+	// DefaultHealthRemediationRecoveryTimeout is how long a restarted runner has to recover when
+	// HEALTH_REMEDIATION_RECOVERY_TIMEOUT is not set
+	DefaultHealthRemediationRecoveryTimeout = 10 * time.Minute
+
+	// EventReasonHealthRemediation is the reason of the events recorded on a node while its runner is remediated
+	EventReasonHealthRemediation = "HealthRemediation"
+)
+
+// isRunnerUnhealthy reports whether the Daytona API reports the runner as failing its health checks
+func isRunnerUnhealthy(runner daytona.RunnerFull) bool {
+	return runner.GetState() == daytona.RUNNERSTATE_UNRESPONSIVE
+}
+
+// healthRemediator remediates runners the Daytona API reports unhealthy for HEALTH_REMEDIATION_UNHEALTHY_CYCLES
+// consecutive cycles. Their node is cordoned and the runner made unschedulable, so its capacity is no longer counted
+// and the regular scale-up adds a node meanwhile, and a restart of the runner is requested. A runner that recovers is
+// made schedulable again and its node uncordoned. One that is still unhealthy after
+// HEALTH_REMEDIATION_RECOVERY_TIMEOUT is replaced: its placeholder is deleted so the cluster autoscaler removes the
+// node. The progress is recorded on the nodes, so it survives runner-manager restarts.
+type healthRemediator struct {
+	cycles          int
+	recoveryTimeout time.Duration
+
+	// unhealthy counts the consecutive cycles each runner was reported unhealthy, by runner ID
+	unhealthy map[string]int
+}
+
+func newHealthRemediator(cfg *Config) *healthRemediator {
+	return &healthRemediator{
+		cycles:          cfg.HealthRemediationUnhealthyCycles,
+		recoveryTimeout: cfg.HealthRemediationRecoveryTimeout,
+		unhealthy:       make(map[string]int),
+	}
+}
+
+// reconcile counts the unhealthy cycles of the pool's runners and advances the remediation of each node. It runs
+// before the resource metrics are calculated so they account for the change.
+func (r *healthRemediator) reconcile(backend clusterBackend, apiClient *daytona.APIClient, state *ClusterState) {
+	seen := make(map[string]bool, len(state.Runners))
+	remediating := 0
+	for _, runner := range state.Runners {
+		seen[runner.GetId()] = true
+		if isRunnerUnhealthy(runner) {
+			r.unhealthy[runner.GetId()]++
+		} else {
+			delete(r.unhealthy, runner.GetId())
+		}
+
+		node, found := state.NodeByIP[runner.GetDomain()]
+		if !found || isInMaintenance(node) {
+			continue
+		}
+		if _, hibernated := node.Annotations[HibernatedAtAnnotation]; hibernated {
+			continue
+		}
+		if _, replaced := node.Annotations[HealthRemediationReplacedAtAnnotation]; replaced {
+			continue
+		}
+
+		startedAt, started := node.Annotations[HealthRemediationStartedAtAnnotation]
+		switch {
+		case !started && r.unhealthy[runner.GetId()] >= r.cycles:
+			if r.start(backend, apiClient, state, runner, node) {
+				remediating++
+			}
+		case started && !isRunnerUnhealthy(runner):
+			r.restore(backend, apiClient, runner, node)
+		case started:
+			since, err := time.Parse(time.RFC3339, startedAt)
+			if err != nil || time.Since(since) <= r.recoveryTimeout {
+				// A runner made schedulable again by hand while still unhealthy is drained again
+				if !runner.GetUnschedulable() && updateRunnerScheduling(apiClient, runner.GetId(), true) == nil {
+					markRunnerUnschedulable(state, runner.GetId())
+				}
+				remediating++
+				continue
+			}
+			if !r.replace(backend, state, runner, node, time.Since(since)) {
+				remediating++
+			}
+		}
+	}
+
+	// Runners deregistered since are forgotten
+	for runnerID := range r.unhealthy {
+		if !seen[runnerID] {
+			delete(r.unhealthy, runnerID)
+		}
+	}
+	unhealthyRunners.Set(float64(len(r.unhealthy)))
+	remediatingRunners.Set(float64(remediating))
+}
+
+// start cordons the node of the unhealthy runner, makes the runner unschedulable and requests its restart. It returns
+// false if the node could not be cordoned, the remediation is then retried next cycle.
+func (r *healthRemediator) start(backend clusterBackend, apiClient *daytona.APIClient, state *ClusterState, runner daytona.RunnerFull, node *corev1.Node) bool {
+	runnerLog := log.WithFields(log.Fields{"node": node.Name, "domain": runner.GetDomain(), "runner": runner.GetId()})
+	runnerLog.Warnf("Runner %s on node %s has been unhealthy for %d consecutive cycles. Cordoning the node and requesting a restart of the runner.",
+		runner.GetId(), node.Name, r.unhealthy[runner.GetId()])
+
+	startedAt := time.Now().UTC().Format(time.RFC3339)
+	unschedulable := true
+	if err := backend.PatchNode(context.Background(), node.Name, map[string]*string{HealthRemediationStartedAtAnnotation: &startedAt}, &unschedulable); err != nil {
+		runnerLog.Errorf("Error cordoning node %s of unhealthy runner %s, retrying next cycle: %v", node.Name, runner.GetId(), err)
+		return false
+	}
+	node.Spec.Unschedulable = true
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[HealthRemediationStartedAtAnnotation] = startedAt
+	healthRemediations.WithLabelValues("cordoned").Inc()
+
+	message := fmt.Sprintf("Cordoned: runner %s unhealthy for %d consecutive cycles", runner.GetId(), r.unhealthy[runner.GetId()])
+	if !runner.GetUnschedulable() {
+		if err := updateRunnerScheduling(apiClient, runner.GetId(), true); err != nil {
+			runnerLog.Errorf("Error marking unhealthy runner %s unschedulable, retrying next cycle: %v", runner.GetId(), err)
+		} else {
+			markRunnerUnschedulable(state, runner.GetId())
+			message += ", runner is unschedulable"
+		}
+	}
+
+	switch err := requestRunnerRestart(apiClient, runner.GetId()); {
+	case errors.Is(err, errRestartUnsupported):
+		runnerLog.Warnf("Could not restart runner %s: %v. It is replaced unless it recovers within %s.", runner.GetId(), err, r.recoveryTimeout)
+		message += ", restart not supported by the Daytona API"
+	case err != nil:
+		runnerLog.Errorf("Error requesting restart of runner %s, it is replaced unless it recovers within %s: %v", runner.GetId(), r.recoveryTimeout, err)
+		message += ", restart request failed"
+	default:
+		healthRemediations.WithLabelValues("restart_requested").Inc()
+		message += ", restart requested"
+	}
+	recordNodeEvent(backend, node, corev1.EventTypeWarning, EventReasonHealthRemediation, message)
+	return true
+}
+
+// restore uncordons the node of a runner healthy again and makes the runner schedulable
+func (r *healthRemediator) restore(backend clusterBackend, apiClient *daytona.APIClient, runner daytona.RunnerFull, node *corev1.Node) {
+	runnerLog := log.WithFields(log.Fields{"node": node.Name, "domain": runner.GetDomain(), "runner": runner.GetId()})
+
+	unschedulable := false
+	if err := backend.PatchNode(context.Background(), node.Name, map[string]*string{HealthRemediationStartedAtAnnotation: nil}, &unschedulable); err != nil {
+		runnerLog.Errorf("Error uncordoning node %s of recovered runner %s: %v", node.Name, runner.GetId(), err)
+		return
+	}
+	node.Spec.Unschedulable = false
+	delete(node.Annotations, HealthRemediationStartedAtAnnotation)
+	healthRemediations.WithLabelValues("recovered").Inc()
+
+	message := fmt.Sprintf("Runner %s recovered, uncordoned", runner.GetId())
+	if runner.GetUnschedulable() {
+		if err := updateRunnerScheduling(apiClient, runner.GetId(), false); err != nil {
+			runnerLog.Errorf("Error making recovered runner %s schedulable: %v", runner.GetId(), err)
+		} else {
+			message += ", runner is schedulable again"
+		}
+	}
+	runnerLog.Infof("Runner %s on node %s is healthy again. %s.", runner.GetId(), node.Name, message)
+	recordNodeEvent(backend, node, corev1.EventTypeNormal, EventReasonHealthRemediation, message)
+}
+
+// replace deletes the placeholder of the node of a runner that did not recover, so the cluster autoscaler removes the
+// node. Nodes protected from disturbance are kept. It returns true if the placeholder was deleted.
+func (r *healthRemediator) replace(backend clusterBackend, state *ClusterState, runner daytona.RunnerFull, node *corev1.Node, unhealthyFor time.Duration) bool {
+	runnerLog := log.WithFields(log.Fields{"node": node.Name, "domain": runner.GetDomain(), "runner": runner.GetId()})
+	if protection.IsDoNotDisturb(node.Annotations) || state.ProtectedRunnerIDs[runner.GetId()] {
+		runnerLog.Warnf("Runner %s did not recover within %s, but node %s is protected from disturbance. Keeping it cordoned.", runner.GetId(), r.recoveryTimeout, node.Name)
+		return false
+	}
+
+	var placeholder *corev1.Pod
+	index := 0
+	for i, pod := range state.ScheduledPlaceholders {
+		if pod.Spec.NodeName == node.Name {
+			placeholder, index = pod, i
+			break
+		}
+	}
+	if placeholder == nil {
+		runnerLog.Warnf("Runner %s did not recover, but no scheduled placeholder pod was found on node %s. It cannot be replaced.", runner.GetId(), node.Name)
+		return false
+	}
+
+	runnerLog.Errorf("Runner %s on node %s did not recover within %s of its restart. Deleting placeholder pod %s to replace the node.",
+		runner.GetId(), node.Name, unhealthyFor.Round(time.Second), placeholder.Name)
+	if err := backend.DeletePlaceholder(context.Background(), placeholder.Name); err != nil {
+		runnerLog.Errorf("Error deleting placeholder pod %s of node %s, retrying next cycle: %v", placeholder.Name, node.Name, err)
+		return false
+	}
+	state.ScheduledPlaceholders = append(state.ScheduledPlaceholders[:index], state.ScheduledPlaceholders[index+1:]...)
+	replacedAt := time.Now().UTC().Format(time.RFC3339)
+	if err := backend.PatchNode(context.Background(), node.Name, map[string]*string{HealthRemediationReplacedAtAnnotation: &replacedAt}, nil); err != nil {
+		runnerLog.Errorf("Error recording the replacement of node %s: %v", node.Name, err)
+	}
+	node.Annotations[HealthRemediationReplacedAtAnnotation] = replacedAt
+	healthRemediations.WithLabelValues("replaced").Inc()
+
+	message := fmt.Sprintf("Runner %s did not recover within %s, deleted placeholder pod %s to replace the node", runner.GetId(), r.recoveryTimeout, placeholder.Name)
+	recordNodeEvent(backend, node, corev1.EventTypeWarning, EventReasonHealthRemediation, message)
+	recordPlaceholderEvent(backend, placeholder, corev1.EventTypeWarning, EventReasonHealthRemediation, message)
+	return true
+}
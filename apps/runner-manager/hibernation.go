@@ -0,0 +1,352 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// HibernatedAtAnnotation marks a pool node that was hibernated instead of removed
+	HibernatedAtAnnotation = "daytona.io/hibernated-at"
+
+	// ResumedAtAnnotation marks a hibernated node that was resumed, until it is ready and its runner reports again
+	ResumedAtAnnotation = "daytona.io/resumed-at"
+
+	// HibernationResumeTimeout is how long a resumed node has to become ready and its runner to report before the
+	// node is marked hibernated again, for a later scale-up to retry
+	HibernationResumeTimeout = 15 * time.Minute
+
+	// HibernationBackendAWS and HibernationBackendWebhook are the supported hibernation backends
+	HibernationBackendAWS     = "aws"
+	HibernationBackendWebhook = "webhook"
+)
+
+// nodeHibernator stops and starts the machine backing a pool node
+type nodeHibernator interface {
+	Hibernate(ctx context.Context, node *corev1.Node) error
+	Resume(ctx context.Context, node *corev1.Node) error
+}
+
+// newNodeHibernator creates the configured hibernation backend, or nil when hibernation is disabled
+func newNodeHibernator(cfg *Config) (nodeHibernator, error) {
+	switch cfg.HibernationBackend {
+	case "":
+		return nil, nil
+	case HibernationBackendAWS:
+		awsCfg, err := awsconfig.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+		}
+		return &awsHibernator{
+			ec2:         ec2.NewFromConfig(awsCfg),
+			autoscaling: autoscaling.NewFromConfig(awsCfg),
+			useStandby:  cfg.HibernationAWSStandby,
+		}, nil
+	case HibernationBackendWebhook:
+		return &webhookHibernator{
+			url:        cfg.HibernationWebhookURL,
+			token:      cfg.HibernationWebhookToken,
+			httpClient: &http.Client{Timeout: 30 * time.Second},
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported hibernation backend %q", cfg.HibernationBackend)
+}
+
+// awsHibernator hibernates EC2 instances, optionally moving them to standby in their Auto Scaling group
+// so the group neither replaces them nor counts them towards its desired capacity
+type awsHibernator struct {
+	ec2         *ec2.Client
+	autoscaling *autoscaling.Client
+	useStandby  bool
+}
+
+// instanceID extracts the EC2 instance ID from a provider ID of the form aws:///<zone>/<instance-id>
+func (h *awsHibernator) instanceID(node *corev1.Node) (string, error) {
+	providerID := node.Spec.ProviderID
+	if !strings.HasPrefix(providerID, "aws://") {
+		return "", fmt.Errorf("node %s has no AWS provider ID", node.Name)
+	}
+	return providerID[strings.LastIndex(providerID, "/")+1:], nil
+}
+
+func (h *awsHibernator) autoScalingGroup(ctx context.Context, instanceID string) (string, error) {
+	out, err := h.autoscaling.DescribeAutoScalingInstances(ctx, &autoscaling.DescribeAutoScalingInstancesInput{
+		InstanceIds: []string{instanceID},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(out.AutoScalingInstances) == 0 {
+		return "", fmt.Errorf("instance %s is not part of an Auto Scaling group", instanceID)
+	}
+	return aws.ToString(out.AutoScalingInstances[0].AutoScalingGroupName), nil
+}
+
+func (h *awsHibernator) Hibernate(ctx context.Context, node *corev1.Node) error {
+	instanceID, err := h.instanceID(node)
+	if err != nil {
+		return err
+	}
+
+	if h.useStandby {
+		group, err := h.autoScalingGroup(ctx, instanceID)
+		if err != nil {
+			return err
+		}
+		_, err = h.autoscaling.EnterStandby(ctx, &autoscaling.EnterStandbyInput{
+			AutoScalingGroupName:           aws.String(group),
+			InstanceIds:                    []string{instanceID},
+			ShouldDecrementDesiredCapacity: aws.Bool(true),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to move instance %s to standby: %w", instanceID, err)
+		}
+	}
+
+	_, err = h.ec2.StopInstances(ctx, &ec2.StopInstancesInput{
+		InstanceIds: []string{instanceID},
+		Hibernate:   aws.Bool(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to hibernate instance %s: %w", instanceID, err)
+	}
+	return nil
+}
+
+func (h *awsHibernator) Resume(ctx context.Context, node *corev1.Node) error {
+	instanceID, err := h.instanceID(node)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.ec2.StartInstances(ctx, &ec2.StartInstancesInput{
+		InstanceIds: []string{instanceID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start instance %s: %w", instanceID, err)
+	}
+
+	if h.useStandby {
+		group, err := h.autoScalingGroup(ctx, instanceID)
+		if err != nil {
+			return err
+		}
+		_, err = h.autoscaling.ExitStandby(ctx, &autoscaling.ExitStandbyInput{
+			AutoScalingGroupName: aws.String(group),
+			InstanceIds:          []string{instanceID},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to move instance %s out of standby: %w", instanceID, err)
+		}
+	}
+	return nil
+}
+
+// webhookHibernator delegates hibernation to an operator-provided HTTP endpoint
+type webhookHibernator struct {
+	url        string
+	token      string
+	httpClient *http.Client
+}
+
+func (h *webhookHibernator) Hibernate(ctx context.Context, node *corev1.Node) error {
+	return h.call(ctx, "hibernate", node)
+}
+
+func (h *webhookHibernator) Resume(ctx context.Context, node *corev1.Node) error {
+	return h.call(ctx, "resume", node)
+}
+
+func (h *webhookHibernator) call(ctx context.Context, action string, node *corev1.Node) error {
+	body, err := json.Marshal(map[string]string{
+		"action":     action,
+		"nodeName":   node.Name,
+		"providerId": node.Spec.ProviderID,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("hibernation webhook returned status %d for %s of node %s", resp.StatusCode, action, node.Name)
+	}
+	return nil
+}
+
+// setNodeHibernated cordons and annotates a node being hibernated, or reverts both when it is resumed
+func setNodeHibernated(backend clusterBackend, nodeName string, hibernated bool) error {
+	var annotation *string
+	if hibernated {
+		hibernatedAt := time.Now().UTC().Format(time.RFC3339)
+		annotation = &hibernatedAt
+	}
+
+	return backend.PatchNode(context.Background(), nodeName, map[string]*string{HibernatedAtAnnotation: annotation}, &hibernated)
+}
+
+// runnerIDOnNode returns the ID of the runner hosted on the node, or an empty string if there is none
+func runnerIDOnNode(state *ClusterState, node *corev1.Node) string {
+	if runner, found := runnerOnNode(state, node); found {
+		return runner.GetId()
+	}
+	return ""
+}
+
+// runnerOnNode returns the runner hosted on the node, false if there is none
+func runnerOnNode(state *ClusterState, node *corev1.Node) (daytona.RunnerFull, bool) {
+	for _, ip := range extractNodeIPs(node) {
+		if runner, found := state.RunnerByDomain[ip]; found {
+			return runner, true
+		}
+	}
+	return daytona.RunnerFull{}, false
+}
+
+// hibernateNode hibernates a node instead of removing it. Its runner is made unschedulable first and its placeholder
+// pod is kept so the cluster autoscaler does not remove the stopped node.
+func hibernateNode(backend clusterBackend, apiClient *daytona.APIClient, hibernator nodeHibernator, state *ClusterState, node *corev1.Node) error {
+	runnerID := runnerIDOnNode(state, node)
+	if runnerID != "" {
+		if err := updateRunnerScheduling(apiClient, runnerID, true); err != nil {
+			return err
+		}
+	}
+
+	if err := setNodeHibernated(backend, node.Name, true); err != nil {
+		return fmt.Errorf("failed to mark node %s as hibernated: %w", node.Name, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := hibernator.Hibernate(ctx, node); err != nil {
+		if revertErr := setNodeHibernated(backend, node.Name, false); revertErr != nil {
+			log.Errorf("Error reverting hibernation mark of node %s: %v", node.Name, revertErr)
+		}
+		if runnerID != "" {
+			if revertErr := updateRunnerScheduling(apiClient, runnerID, false); revertErr != nil {
+				log.Errorf("Error making runner %s schedulable again: %v", runnerID, revertErr)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// resumeHibernatedNodes resumes up to count hibernated nodes, returning the number of nodes resumed. Their runners
+// stay unschedulable until completeResumedNodes sees the node ready and the runner reporting again.
+func resumeHibernatedNodes(backend clusterBackend, hibernator nodeHibernator, state *ClusterState, count int) int {
+	resumed := 0
+	for _, node := range state.HibernatedNodes {
+		if resumed >= count {
+			break
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err := hibernator.Resume(ctx, node)
+		cancel()
+		if err != nil {
+			log.Errorf("Error resuming hibernated node %s: %v", node.Name, err)
+			continue
+		}
+
+		resumedAt := time.Now().UTC().Format(time.RFC3339)
+		unschedulable := false
+		err = backend.PatchNode(context.Background(), node.Name, map[string]*string{HibernatedAtAnnotation: nil, ResumedAtAnnotation: &resumedAt}, &unschedulable)
+		if err != nil {
+			log.Errorf("Error clearing hibernation mark of node %s: %v", node.Name, err)
+			continue
+		}
+
+		log.Infof("Resumed hibernated node %s, its runner is made schedulable once the node is ready.", node.Name)
+		resumed++
+	}
+	return resumed
+}
+
+// completeResumedNodes makes the runners of resumed nodes schedulable once the node became ready and the runner
+// reported a heartbeat since the resume, so no sandbox is placed on a node still booting. A node not back within
+// HibernationResumeTimeout is marked hibernated again.
+func completeResumedNodes(backend clusterBackend, apiClient *daytona.APIClient, state *ClusterState) {
+	remaining := state.ResumingNodes[:0]
+	for _, node := range state.ResumingNodes {
+		resumedAt, err := time.Parse(time.RFC3339, node.Annotations[ResumedAtAnnotation])
+		if err != nil {
+			log.Warnf("Invalid %s annotation on node %s: %v", ResumedAtAnnotation, node.Name, err)
+		}
+
+		readyAt, ready := nodeReadyAt(node)
+		ready = ready && !readyAt.Before(resumedAt)
+
+		runner, found := runnerOnNode(state, node)
+		runnerID := runner.GetId()
+		reporting := false
+		if found {
+			lastChecked, err := time.Parse(time.RFC3339Nano, runner.GetLastChecked())
+			reporting = err == nil && !lastChecked.Before(resumedAt)
+		}
+
+		if ready && reporting {
+			if err := updateRunnerScheduling(apiClient, runnerID, false); err != nil {
+				log.Errorf("Error making runner on resumed node %s schedulable: %v", node.Name, err)
+				remaining = append(remaining, node)
+				continue
+			}
+			if err := backend.PatchNode(context.Background(), node.Name, map[string]*string{ResumedAtAnnotation: nil}, nil); err != nil {
+				log.Errorf("Error clearing resume mark of node %s: %v", node.Name, err)
+			}
+			log.Infof("Resumed node %s is ready and its runner %s is reporting, made the runner schedulable.", node.Name, runnerID)
+			continue
+		}
+
+		if time.Since(resumedAt) > HibernationResumeTimeout {
+			hibernatedAt := time.Now().UTC().Format(time.RFC3339)
+			unschedulable := true
+			err := backend.PatchNode(context.Background(), node.Name, map[string]*string{HibernatedAtAnnotation: &hibernatedAt, ResumedAtAnnotation: nil}, &unschedulable)
+			if err != nil {
+				log.Errorf("Error marking node %s as hibernated again: %v", node.Name, err)
+				remaining = append(remaining, node)
+				continue
+			}
+			log.Warnf("Resumed node %s did not become ready with a reporting runner within %s (ready: %t, runner reporting: %t), marked it hibernated again.",
+				node.Name, HibernationResumeTimeout, ready, reporting)
+			continue
+		}
+
+		log.Debugf("Waiting for resumed node %s (ready: %t, runner reporting: %t).", node.Name, ready, reporting)
+		remaining = append(remaining, node)
+	}
+	state.ResumingNodes = remaining
+}
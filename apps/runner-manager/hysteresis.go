@@ -0,0 +1,28 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+// isAboveScaleDownUtilization reports whether the CPU, memory or GPU utilization reaches SCALE_DOWN_UTILIZATION_PERCENT,
+// never when the threshold is unset. Nodes are only removed while the pool stays below it, so a pool hovering around
+// MAX_RESOURCE_UTILIZATION_PERCENT does not remove a node and add it back in the next cycles.
+func isAboveScaleDownUtilization(cfg *Config, metrics *ResourceMetrics) bool {
+	if cfg.ScaleDownUtilizationPercent == 0 {
+		return false
+	}
+	threshold := float32(cfg.ScaleDownUtilizationPercent)
+	for _, resource := range []struct{ allocated, total float32 }{
+		{metrics.TotalAllocatedCPU, metrics.TotalCPUCapacity},
+		{metrics.TotalAllocatedMemoryGiB, metrics.TotalMemoryGiBCapacity},
+		{metrics.TotalAllocatedGPU, metrics.TotalGPUCapacity},
+	} {
+		if resource.allocated <= 0 {
+			continue
+		}
+		// Removing the last capacity of an allocated resource leaves it fully utilized
+		if resource.total <= 0 || resource.allocated/resource.total*100 >= threshold {
+			return true
+		}
+	}
+	return false
+}
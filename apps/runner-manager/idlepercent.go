@@ -0,0 +1,28 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"math"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// applyIdlePercentages raises the idle CPU and memory requirements to MIN_IDLE_CPU_PERCENT and
+// MIN_IDLE_MEMORY_PERCENT of the pool's capacity, so the idle buffer grows with the fleet. MIN_IDLE_CPU and
+// MIN_IDLE_MEMORY remain the requirements of pools too small for the percentages to exceed them.
+func applyIdlePercentages(cfg *Config, metrics *ResourceMetrics) *Config {
+	minIdleCpu := int(math.Ceil(float64(metrics.TotalCPUCapacity) * float64(cfg.MinIdleCpuPercent) / 100))
+	minIdleMemory := int(math.Ceil(float64(metrics.TotalMemoryGiBCapacity) * float64(cfg.MinIdleMemoryPercent) / 100))
+	if minIdleCpu <= cfg.MinIdleCpu && minIdleMemory <= cfg.MinIdleMemory {
+		return cfg
+	}
+
+	scaled := *cfg
+	scaled.MinIdleCpu = max(cfg.MinIdleCpu, minIdleCpu)
+	scaled.MinIdleMemory = max(cfg.MinIdleMemory, minIdleMemory)
+	log.Debugf("Idle buffer of %d%% CPU and %d%% memory of the pool's capacity: %d CPU, %d GiB memory.",
+		cfg.MinIdleCpuPercent, cfg.MinIdleMemoryPercent, scaled.MinIdleCpu, scaled.MinIdleMemory)
+	return &scaled
+}
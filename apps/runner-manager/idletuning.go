@@ -0,0 +1,188 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultIdleTuningQuietPeriod is how long without missed-capacity incidents before the idle buffer is lowered a step
+	DefaultIdleTuningQuietPeriod = time.Hour
+
+	// IdleTuningRaiseCooldown is the minimum time between two raises so one incident is not counted every cycle
+	IdleTuningRaiseCooldown = 10 * time.Minute
+
+	// IdleTuningResetPath is the admin endpoint reverting the idle buffer to its configured values
+	IdleTuningResetPath = "/idle-tuning/reset"
+
+	// idleTuningSteps is the number of steps between the configured and the maximum CPU and memory buffer
+	idleTuningSteps = 4
+)
+
+// idleBuffer is a set of MIN_IDLE_* values
+type idleBuffer struct {
+	Runners int
+	Cpu     int
+	Memory  int
+}
+
+// idleTuner raises the idle buffer after missed-capacity incidents and lowers it back during quiet periods,
+// staying between the configured MIN_IDLE_* values and the IDLE_TUNING_MAX_* bounds.
+//
+// An incident is a cycle finding sandboxes of the pool's backlog: pending a build for lack of a runner, or being
+// created without a runner assigned yet. A regular create the API rejects with "No available runners" leaves no
+// sandbox behind and is not seen, so the tuner reacts to the queued sandboxes only, not to every rejected create.
+type idleTuner struct {
+	mu             sync.Mutex
+	resetRequested bool
+
+	base           idleBuffer
+	max            idleBuffer
+	applied        idleBuffer
+	quietPeriod    time.Duration
+	lastIncident   time.Time
+	lastAdjustment time.Time
+}
+
+func newIdleTuner(cfg *Config) *idleTuner {
+	base := idleBuffer{Runners: cfg.MinIdleRunners, Cpu: cfg.MinIdleCpu, Memory: cfg.MinIdleMemory}
+	return &idleTuner{
+		base:         base,
+		max:          idleBuffer{Runners: cfg.IdleTuningMaxRunners, Cpu: cfg.IdleTuningMaxCpu, Memory: cfg.IdleTuningMaxMemory},
+		applied:      base,
+		quietPeriod:  cfg.IdleTuningQuietPeriod,
+		lastIncident: time.Now(),
+	}
+}
+
+// requestReset makes the next observation revert the idle buffer to the configured values
+func (t *idleTuner) requestReset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.resetRequested = true
+}
+
+// observe records the number of queued sandboxes in this cycle and adjusts the MIN_IDLE_* values in cfg
+func (t *idleTuner) observe(cfg *Config, queued int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+
+	if t.resetRequested {
+		t.resetRequested = false
+		t.adjust(cfg, t.base, "reset requested")
+		t.lastAdjustment = now
+	}
+
+	if queued > 0 {
+		missedCapacityIncidents.Inc()
+		t.lastIncident = now
+		if now.Sub(t.lastAdjustment) >= IdleTuningRaiseCooldown {
+			raised := idleBuffer{
+				Runners: min(t.applied.Runners+1, max(t.max.Runners, t.base.Runners)),
+				Cpu:     min(t.applied.Cpu+t.step(t.base.Cpu, t.max.Cpu), max(t.max.Cpu, t.base.Cpu)),
+				Memory:  min(t.applied.Memory+t.step(t.base.Memory, t.max.Memory), max(t.max.Memory, t.base.Memory)),
+			}
+			if t.adjust(cfg, raised, fmt.Sprintf("%d sandboxes queued for capacity", queued)) {
+				t.lastAdjustment = now
+			}
+		}
+	} else if now.Sub(t.lastIncident) >= t.quietPeriod && now.Sub(t.lastAdjustment) >= t.quietPeriod {
+		lowered := idleBuffer{
+			Runners: max(t.applied.Runners-1, t.base.Runners),
+			Cpu:     max(t.applied.Cpu-t.step(t.base.Cpu, t.max.Cpu), t.base.Cpu),
+			Memory:  max(t.applied.Memory-t.step(t.base.Memory, t.max.Memory), t.base.Memory),
+		}
+		if t.adjust(cfg, lowered, fmt.Sprintf("no missed capacity for %s", t.quietPeriod)) {
+			t.lastAdjustment = now
+		}
+	}
+
+	effectiveMinIdle.WithLabelValues("runners").Set(float64(cfg.MinIdleRunners))
+	effectiveMinIdle.WithLabelValues("cpu").Set(float64(cfg.MinIdleCpu))
+	effectiveMinIdle.WithLabelValues("memory").Set(float64(cfg.MinIdleMemory))
+}
+
+// withBase runs fn with the configured MIN_IDLE_* values in cfg, so the configuration drift check compares and adopts
+// the base instead of the tuned values, and then re-applies the tuned values on top of the possibly adopted base
+func (t *idleTuner) withBase(cfg *Config, fn func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cfg.MinIdleRunners, cfg.MinIdleCpu, cfg.MinIdleMemory = t.base.Runners, t.base.Cpu, t.base.Memory
+	fn()
+
+	base := idleBuffer{Runners: cfg.MinIdleRunners, Cpu: cfg.MinIdleCpu, Memory: cfg.MinIdleMemory}
+	if base != t.base {
+		log.Infof("Idle tuning: Configured idle buffer changed to runners=%d, cpu=%d, memory=%d.", base.Runners, base.Cpu, base.Memory)
+		t.base = base
+		t.applied = idleBuffer{
+			Runners: min(max(t.applied.Runners, base.Runners), max(t.max.Runners, base.Runners)),
+			Cpu:     min(max(t.applied.Cpu, base.Cpu), max(t.max.Cpu, base.Cpu)),
+			Memory:  min(max(t.applied.Memory, base.Memory), max(t.max.Memory, base.Memory)),
+		}
+	}
+	cfg.MinIdleRunners, cfg.MinIdleCpu, cfg.MinIdleMemory = t.applied.Runners, t.applied.Cpu, t.applied.Memory
+}
+
+// step returns how much a CPU or memory buffer moves per adjustment
+func (t *idleTuner) step(base, maximum int) int {
+	if maximum <= base {
+		return 0
+	}
+	return max(1, int(math.Ceil(float64(maximum-base)/idleTuningSteps)))
+}
+
+// adjust applies the buffer to cfg and logs the change, returning false if nothing changed
+func (t *idleTuner) adjust(cfg *Config, buffer idleBuffer, reason string) bool {
+	if buffer == t.applied {
+		return false
+	}
+
+	log.Infof("Idle tuning: Changing idle buffer from runners=%d, cpu=%d, memory=%d to runners=%d, cpu=%d, memory=%d (%s). Configured base is runners=%d, cpu=%d, memory=%d.",
+		t.applied.Runners, t.applied.Cpu, t.applied.Memory, buffer.Runners, buffer.Cpu, buffer.Memory, reason,
+		t.base.Runners, t.base.Cpu, t.base.Memory)
+
+	t.applied = buffer
+	cfg.MinIdleRunners = buffer.Runners
+	cfg.MinIdleCpu = buffer.Cpu
+	cfg.MinIdleMemory = buffer.Memory
+	return true
+}
+
+// idleTuningResetHandler reverts the idle buffer to the configured values on the next cycle, in the pool selected by
+// the request or in all pools
+func idleTuningResetHandler(pools []*runnerPool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		selected := pools
+		if r.URL.Query().Has(PoolQueryParam) {
+			pool := selectPool(w, r, pools)
+			if pool == nil {
+				return
+			}
+			selected = []*runnerPool{pool}
+		}
+
+		for _, pool := range selected {
+			if pool.tuner != nil {
+				pool.tuner.requestReset()
+				log.WithField("pool", pool.cfg.PoolName).Info("Idle tuning: Reset to the configured idle buffer requested.")
+			}
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// controllerQueueKey is the single work item of the controller queue, every trigger coalesces into one cycle
+	controllerQueueKey = "reconcile"
+
+	// EventDebounce is how long a cycle triggered by a change waits, so a burst of changes runs a single cycle
+	EventDebounce = 2 * time.Second
+
+	// MinCycleInterval is the minimum time between two cycles, bounding the Daytona API calls under constant change
+	MinCycleInterval = 5 * time.Second
+)
+
+// clusterWatcher is implemented by the backends able to notify the controller of changes to nodes and placeholders
+type clusterWatcher interface {
+	// Watch starts watching the nodes and placeholders, adding the controller key to the queue on relevant changes,
+	// and returns once the initial state is known
+	Watch(ctx context.Context, queue workqueue.TypedDelayingInterface[string]) error
+}
+
+// Watch serves ListNodes and ListPlaceholders from shared informers from now on, and triggers a cycle when a node
+// is added, removed, becomes ready or is cordoned, and when a placeholder is added, removed, scheduled or changes
+// phase. Other updates, such as node heartbeats, are ignored.
+func (b *kubernetesBackend) Watch(ctx context.Context, queue workqueue.TypedDelayingInterface[string]) (err error) {
+	// The informers are stopped if they cannot sync, the backend then keeps listing from the API
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		if err != nil {
+			cancel()
+		}
+	}()
+
+	nodeFactory := informers.NewSharedInformerFactoryWithOptions(b.clientset, 0,
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = b.cfg.NodeSelectorKey + "=true"
+		}),
+	)
+	podFactory := informers.NewSharedInformerFactoryWithOptions(b.clientset, 0,
+		informers.WithNamespace(b.cfg.ProviderNamespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = "app=" + PlaceholderPodLabel
+		}),
+	)
+
+	trigger := func(reason string) {
+		log.WithField("reason", reason).Debug("Triggering controller cycle")
+		controllerTriggers.WithLabelValues("event").Inc()
+		queue.AddAfter(controllerQueueKey, EventDebounce)
+	}
+
+	nodeInformer := nodeFactory.Core().V1().Nodes()
+	_, err = nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			if node, ok := obj.(*corev1.Node); ok {
+				trigger("node " + node.Name + " added")
+			}
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			oldNode, ok := oldObj.(*corev1.Node)
+			if !ok {
+				return
+			}
+			newNode, ok := newObj.(*corev1.Node)
+			if !ok {
+				return
+			}
+			if isNodeReady(oldNode) != isNodeReady(newNode) || oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable {
+				trigger("node " + newNode.Name + " changed readiness or schedulability")
+			}
+		},
+		DeleteFunc: func(obj any) {
+			trigger("node removed")
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	podInformer := podFactory.Core().V1().Pods()
+	_, err = podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				trigger("placeholder " + pod.Name + " added")
+			}
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			oldPod, ok := oldObj.(*corev1.Pod)
+			if !ok {
+				return
+			}
+			newPod, ok := newObj.(*corev1.Pod)
+			if !ok {
+				return
+			}
+			if oldPod.Spec.NodeName != newPod.Spec.NodeName || oldPod.Status.Phase != newPod.Status.Phase {
+				trigger("placeholder " + newPod.Name + " scheduled or changed phase")
+			}
+		},
+		DeleteFunc: func(obj any) {
+			trigger("placeholder removed")
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	nodeFactory.Start(ctx.Done())
+	podFactory.Start(ctx.Done())
+
+	syncCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	for informerType, synced := range nodeFactory.WaitForCacheSync(syncCtx.Done()) {
+		if !synced {
+			return fmt.Errorf("failed to sync %v informer", informerType)
+		}
+	}
+	for informerType, synced := range podFactory.WaitForCacheSync(syncCtx.Done()) {
+		if !synced {
+			return fmt.Errorf("failed to sync %v informer", informerType)
+		}
+	}
+
+	b.nodeLister = nodeInformer.Lister()
+	b.placeholderLister = podInformer.Lister().Pods(b.cfg.ProviderNamespace)
+
+	log.Info("Watching pool nodes and placeholder pods")
+	return nil
+}
+
+// listCachedNodes returns copies of the informer's nodes, so callers may modify them like listed ones
+func (b *kubernetesBackend) listCachedNodes() ([]corev1.Node, error) {
+	nodes, err := b.nodeLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]corev1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		items = append(items, *node.DeepCopy())
+	}
+	return items, nil
+}
+
+func (b *kubernetesBackend) listCachedPlaceholders() ([]*corev1.Pod, error) {
+	pods, err := b.placeholderLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	placeholders := make([]*corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		placeholders = append(placeholders, pod.DeepCopy())
+	}
+	return placeholders, nil
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// startControllerQueue returns the queue driving the controller loop. A cycle is queued at startup and every check
+// interval, as runner and sandbox changes are only seen by polling the Daytona API, and on cluster changes when the
+// backend can be watched.
+func startControllerQueue(ctx context.Context, backend clusterBackend, cfg *Config) workqueue.TypedDelayingInterface[string] {
+	queue := workqueue.NewTypedDelayingQueueWithConfig(workqueue.TypedDelayingQueueConfig[string]{Name: "controller"})
+
+	if watcher, ok := backend.(clusterWatcher); ok {
+		if err := watcher.Watch(ctx, queue); err != nil {
+			log.Warnf("Could not watch the cluster, falling back to polling every %s: %v", cfg.CheckInterval, err)
+		}
+	}
+
+	queue.Add(controllerQueueKey)
+	go func() {
+		ticker := time.NewTicker(cfg.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				controllerTriggers.WithLabelValues("interval").Inc()
+				queue.Add(controllerQueueKey)
+			}
+		}
+	}()
+
+	return queue
+}
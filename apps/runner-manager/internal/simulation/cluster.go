@@ -0,0 +1,52 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package simulation
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// Cluster is a fake Kubernetes cluster holding the fleet's nodes and placeholder pods
+type Cluster struct {
+	*fake.Clientset
+	fleet *Fleet
+}
+
+// NewCluster returns a fake cluster seeded with the fleet's nodes and placeholders
+func NewCluster(fleet *Fleet) *Cluster {
+	objects := make([]runtime.Object, 0, len(fleet.Nodes)+len(fleet.Placeholders))
+	for i := range fleet.Nodes {
+		objects = append(objects, fleet.Nodes[i].DeepCopy())
+	}
+	for i := range fleet.Placeholders {
+		objects = append(objects, fleet.Placeholders[i].DeepCopy())
+	}
+	return &Cluster{Clientset: fake.NewClientset(objects...), fleet: fleet}
+}
+
+// Placeholders returns the placeholder pods currently in the cluster, split into pending and scheduled ones
+func (c *Cluster) Placeholders(ctx context.Context) (pending, scheduled []corev1.Pod, err error) {
+	pods, err := c.CoreV1().Pods(c.fleet.Namespace).List(ctx, metav1.ListOptions{LabelSelector: "app=" + c.fleet.PlaceholderApp})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" {
+			pending = append(pending, pod)
+		} else {
+			scheduled = append(scheduled, pod)
+		}
+	}
+	return pending, scheduled, nil
+}
+
+// Node returns the current version of the node
+func (c *Cluster) Node(ctx context.Context, name string) (*corev1.Node, error) {
+	return c.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package simulation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+)
+
+// DaytonaAPI is an in-memory Daytona API serving the fleet's runners and sandboxes, with the endpoints runner-manager
+// calls during a scaling cycle. Requests to other endpoints are answered with 404 and recorded in Unhandled.
+type DaytonaAPI struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	region    daytona.Region
+	runners   map[string]*daytona.RunnerFull
+	order     []string
+	sandboxes []daytona.Sandbox
+	// SchedulingUpdates is the schedulability each runner was last set to, by runner ID
+	SchedulingUpdates map[string]bool
+	// Restarts are the IDs of the runners a restart was requested for
+	Restarts []string
+	// Unhandled are the method and path of the requests to endpoints the API does not serve
+	Unhandled []string
+}
+
+// NewDaytonaAPI starts serving the fleet's runners and sandboxes, stopped by Close
+func NewDaytonaAPI(fleet *Fleet) *DaytonaAPI {
+	now := fleet.created.UTC().Format(time.RFC3339)
+	region := daytona.NewRegion(fleet.RegionID, fleet.RegionID, daytona.REGIONTYPE_SHARED, now, now)
+	region.AdditionalProperties = fleet.RegionProperties
+	api := &DaytonaAPI{
+		region:            *region,
+		runners:           make(map[string]*daytona.RunnerFull, len(fleet.Runners)),
+		sandboxes:         append([]daytona.Sandbox(nil), fleet.Sandboxes...),
+		SchedulingUpdates: make(map[string]bool),
+	}
+	for i := range fleet.Runners {
+		runner := fleet.Runners[i]
+		api.runners[runner.GetId()] = &runner
+		api.order = append(api.order, runner.GetId())
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/runners", api.listRunners)
+	mux.HandleFunc("PATCH /admin/runners/{id}/scheduling", api.updateScheduling)
+	mux.HandleFunc("POST /admin/runners/{id}/restart", api.restartRunner)
+	mux.HandleFunc("GET /runners/{id}/full", api.getRunner)
+	mux.HandleFunc("GET /sandbox/paginated", api.listSandboxes)
+	mux.HandleFunc("GET /regions/{id}", api.getRegion)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		api.Unhandled = append(api.Unhandled, r.Method+" "+r.URL.Path)
+		api.mu.Unlock()
+		http.NotFound(w, r)
+	})
+	api.server = httptest.NewServer(mux)
+	return api
+}
+
+// Client returns a Daytona API client of the API
+func (a *DaytonaAPI) Client() *daytona.APIClient {
+	cfg := daytona.NewConfiguration()
+	cfg.Servers = daytona.ServerConfigurations{{URL: a.server.URL}}
+	cfg.HTTPClient = a.server.Client()
+	return daytona.NewAPIClient(cfg)
+}
+
+// Close stops serving the API
+func (a *DaytonaAPI) Close() {
+	a.server.Close()
+}
+
+// Runner returns the current state of the runner, false if it does not exist
+func (a *DaytonaAPI) Runner(id string) (daytona.RunnerFull, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	runner, found := a.runners[id]
+	if !found {
+		return daytona.RunnerFull{}, false
+	}
+	return *runner, true
+}
+
+func (a *DaytonaAPI) listRunners(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	regionID := r.URL.Query().Get("regionId")
+	runners := make([]daytona.RunnerFull, 0, len(a.order))
+	for _, id := range a.order {
+		if runner := a.runners[id]; regionID == "" || runner.GetRegion() == regionID {
+			runners = append(runners, *runner)
+		}
+	}
+	writeJSON(w, runners)
+}
+
+func (a *DaytonaAPI) updateScheduling(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Unschedulable bool `json:"unschedulable"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	runner, found := a.runners[r.PathValue("id")]
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	runner.SetUnschedulable(body.Unschedulable)
+	a.SchedulingUpdates[runner.GetId()] = body.Unschedulable
+	writeJSON(w, runner)
+}
+
+func (a *DaytonaAPI) restartRunner(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, found := a.runners[r.PathValue("id")]; !found {
+		http.NotFound(w, r)
+		return
+	}
+	a.Restarts = append(a.Restarts, r.PathValue("id"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *DaytonaAPI) getRunner(w http.ResponseWriter, r *http.Request) {
+	runner, found := a.Runner(r.PathValue("id"))
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, runner)
+}
+
+func (a *DaytonaAPI) getRegion(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("id") != a.region.GetId() {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, a.region)
+}
+
+// listSandboxes serves the sandboxes filtered by region, state and labels, paginated as the Daytona API does
+func (a *DaytonaAPI) listSandboxes(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	regions := splitValues(query["regions"])
+	states := splitValues(query["states"])
+	var labels map[string]string
+	if filter := query.Get("labels"); filter != "" {
+		if err := json.Unmarshal([]byte(filter), &labels); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	page, _ := strconv.Atoi(query.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit < 1 {
+		limit = 100
+	}
+
+	a.mu.Lock()
+	var matching []daytona.Sandbox
+	for _, sandbox := range a.sandboxes {
+		if len(regions) > 0 && !regions[sandbox.GetTarget()] {
+			continue
+		}
+		if len(states) > 0 && !states[string(sandbox.GetState())] {
+			continue
+		}
+		if !hasLabels(sandbox.GetLabels(), labels) {
+			continue
+		}
+		matching = append(matching, sandbox)
+	}
+	a.mu.Unlock()
+
+	totalPages := (len(matching) + limit - 1) / limit
+	start := min((page-1)*limit, len(matching))
+	end := min(start+limit, len(matching))
+	items := append([]daytona.Sandbox{}, matching[start:end]...)
+	writeJSON(w, daytona.NewPaginatedSandboxes(items, float32(len(matching)), float32(page), float32(totalPages)))
+}
+
+// splitValues returns the set of values of a query parameter, given repeated or comma-separated
+func splitValues(values []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item != "" {
+				set[item] = true
+			}
+		}
+	}
+	return set
+}
+
+func hasLabels(labels, required map[string]string) bool {
+	for key, value := range required {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
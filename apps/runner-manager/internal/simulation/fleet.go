@@ -0,0 +1,192 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+// Package simulation builds synthetic runner pools for exercising runner-manager's scaling logic without a cluster or
+// a Daytona API: a Fleet of nodes, placeholder pods, runners and sandboxes is served by a fake Kubernetes clientset
+// and an in-memory Daytona API.
+package simulation
+
+import (
+	"fmt"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FleetOptions identify the pool the fleet's nodes, placeholders and runners belong to
+type FleetOptions struct {
+	RegionID        string
+	Namespace       string
+	NodeSelectorKey string
+	// PlaceholderApp is the app label of placeholder pods
+	PlaceholderApp string
+}
+
+// RunnerSpec is the capacity and allocation of runners added to a fleet
+type RunnerSpec struct {
+	Cpu       float32
+	MemoryGiB float32
+	DiskGiB   float32
+
+	AllocatedCpu       float32
+	AllocatedMemoryGiB float32
+	AllocatedDiskGiB   float32
+	StartedSandboxes   float32
+
+	Unschedulable bool
+	// ReportedCpu is the CPU the runner reports instead of its node's when set
+	ReportedCpu float32
+	// Unresponsive runners fail the health checks of the Daytona API
+	Unresponsive bool
+	// Zone is the topology.kubernetes.io/zone label of the runner's node, none when empty
+	Zone string
+	// NodeAge backdates the creation of the runner's node
+	NodeAge time.Duration
+}
+
+// Fleet is a synthetic runner pool: nodes with their placeholder pods, the runners registered from them and the
+// sandboxes of the region
+type Fleet struct {
+	FleetOptions
+
+	Nodes        []corev1.Node
+	Placeholders []corev1.Pod
+	Runners      []daytona.RunnerFull
+	Sandboxes    []daytona.Sandbox
+	// RegionProperties are the additional properties of the fleet's region
+	RegionProperties map[string]any
+
+	created time.Time
+}
+
+// NewFleet returns an empty fleet of the pool
+func NewFleet(options FleetOptions) *Fleet {
+	return &Fleet{FleetOptions: options, created: time.Now()}
+}
+
+// AddRunners adds nodes hosting a scheduled placeholder and a runner of the spec, and returns the runners' IDs
+func (f *Fleet) AddRunners(count int, spec RunnerSpec) []string {
+	ids := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		node := f.addNode(spec)
+		ip := node.Status.Addresses[0].Address
+
+		id := fmt.Sprintf("runner-%d", len(f.Runners)+1)
+		now := f.created.UTC().Format(time.RFC3339)
+		runner := daytona.NewRunnerFull(id, spec.Cpu, spec.MemoryGiB, spec.DiskGiB, daytona.SANDBOXCLASS_SMALL, f.RegionID, id,
+			daytona.RUNNERSTATE_READY, spec.Unschedulable, now, now, "v0.0.0", "2", "key-"+id)
+		runner.SetDomain(ip)
+		runner.SetApiUrl("http://" + ip + ":3003")
+		runner.SetCurrentAllocatedCpu(spec.AllocatedCpu)
+		runner.SetCurrentAllocatedMemoryGiB(spec.AllocatedMemoryGiB)
+		runner.SetCurrentAllocatedDiskGiB(spec.AllocatedDiskGiB)
+		runner.SetCurrentStartedSandboxes(spec.StartedSandboxes)
+		if spec.ReportedCpu > 0 {
+			runner.SetCpu(spec.ReportedCpu)
+		}
+		if spec.Unresponsive {
+			runner.SetState(daytona.RUNNERSTATE_UNRESPONSIVE)
+		}
+		f.Runners = append(f.Runners, *runner)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// AnnotateRunnerNodes sets the annotations on the nodes of the runners
+func (f *Fleet) AnnotateRunnerNodes(runnerIDs []string, annotations map[string]string) {
+	domains := make(map[string]bool, len(runnerIDs))
+	for _, runner := range f.Runners {
+		for _, id := range runnerIDs {
+			if runner.GetId() == id {
+				domains[runner.GetDomain()] = true
+			}
+		}
+	}
+	for i := range f.Nodes {
+		if domains[f.Nodes[i].Status.Addresses[0].Address] {
+			for key, value := range annotations {
+				f.Nodes[i].Annotations[key] = value
+			}
+		}
+	}
+}
+
+// AddNascentNodes adds nodes hosting a scheduled placeholder whose runner has not registered yet
+func (f *Fleet) AddNascentNodes(count int, spec RunnerSpec) {
+	for i := 0; i < count; i++ {
+		f.addNode(spec)
+	}
+}
+
+// AddPendingPlaceholders adds placeholders waiting for a node
+func (f *Fleet) AddPendingPlaceholders(count int) {
+	for i := 0; i < count; i++ {
+		f.addPlaceholder("")
+	}
+}
+
+// AddSandboxes adds sandboxes of the region in the given state, on the runner unless runnerID is empty
+func (f *Fleet) AddSandboxes(count int, state daytona.SandboxState, runnerID string, labels map[string]string) {
+	for i := 0; i < count; i++ {
+		id := fmt.Sprintf("sandbox-%d", len(f.Sandboxes)+1)
+		sandbox := daytona.NewSandbox(id, "organization", id, "daytona", map[string]string{}, labels, false, false, f.RegionID, 1, 0, 1, 3)
+		sandbox.SetState(state)
+		if runnerID != "" {
+			sandbox.SetRunnerId(runnerID)
+		}
+		f.Sandboxes = append(f.Sandboxes, *sandbox)
+	}
+}
+
+// addNode adds a pool node with the runner spec's capacity and a placeholder scheduled on it
+func (f *Fleet) addNode(spec RunnerSpec) *corev1.Node {
+	index := len(f.Nodes) + 1
+	labels := map[string]string{
+		f.NodeSelectorKey:    "true",
+		corev1.LabelOSStable: "linux",
+	}
+	if spec.Zone != "" {
+		labels[corev1.LabelTopologyZone] = spec.Zone
+	}
+
+	f.Nodes = append(f.Nodes, corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              fmt.Sprintf("node-%d", index),
+			Labels:            labels,
+			Annotations:       map[string]string{},
+			CreationTimestamp: metav1.NewTime(f.created.Add(-spec.NodeAge)),
+		},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: fmt.Sprintf("10.%d.%d.%d", index/65536%256, index/256%256, index%256)},
+			},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    *resource.NewQuantity(int64(spec.Cpu), resource.DecimalSI),
+				corev1.ResourceMemory: *resource.NewQuantity(int64(spec.MemoryGiB)*1024*1024*1024, resource.BinarySI),
+			},
+		},
+	})
+	node := &f.Nodes[len(f.Nodes)-1]
+	f.addPlaceholder(node.Name)
+	return node
+}
+
+// addPlaceholder adds a placeholder pod, scheduled on the node unless nodeName is empty
+func (f *Fleet) addPlaceholder(nodeName string) {
+	f.Placeholders = append(f.Placeholders, corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              fmt.Sprintf("%s-%d", f.PlaceholderApp, len(f.Placeholders)+1),
+			Namespace:         f.Namespace,
+			Labels:            map[string]string{"app": f.PlaceholderApp},
+			CreationTimestamp: metav1.NewTime(f.created),
+		},
+		Spec: corev1.PodSpec{
+			NodeName:   nodeName,
+			Containers: []corev1.Container{{Name: "pause", Image: "pause"}},
+		},
+	})
+}
@@ -0,0 +1,252 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Lifecycle event types sent to the lifecycle webhooks
+	LifecycleNodeProvisioned  = "node.provisioned"
+	LifecycleRunnerRegistered = "runner.registered"
+	LifecycleNodeTeardown     = "node.teardown"
+
+	// LifecycleNodeBootstrapFailed is sent when a node is given up on because its runner never registered
+	LifecycleNodeBootstrapFailed = "node.bootstrap_failed"
+
+	// DefaultLifecycleWebhookMaxAttempts is how often delivering an event to a webhook is attempted
+	DefaultLifecycleWebhookMaxAttempts = 5
+
+	// lifecycleWebhookQueueSize bounds the events waiting for delivery to a webhook that is failing
+	lifecycleWebhookQueueSize = 1000
+
+	lifecycleWebhookMaxBackoff = time.Minute
+)
+
+// lifecycleEvent is the payload posted to the lifecycle webhooks. Receivers should deduplicate on the ID since a
+// delivery is retried when it is not acknowledged.
+type lifecycleEvent struct {
+	Id       string           `json:"id"`
+	Type     string           `json:"type"`
+	Time     time.Time        `json:"time"`
+	RegionId string           `json:"regionId"`
+	Node     *lifecycleNode   `json:"node,omitempty"`
+	Runner   *lifecycleRunner `json:"runner,omitempty"`
+}
+
+type lifecycleNode struct {
+	Name       string            `json:"name"`
+	ProviderId string            `json:"providerId,omitempty"`
+	Zone       string            `json:"zone,omitempty"`
+	Addresses  []string          `json:"addresses,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	CreatedAt  time.Time         `json:"createdAt"`
+}
+
+type lifecycleRunner struct {
+	Id        string  `json:"id"`
+	Domain    string  `json:"domain"`
+	Version   string  `json:"version,omitempty"`
+	Class     string  `json:"class,omitempty"`
+	Cpu       float32 `json:"cpu"`
+	MemoryGiB float32 `json:"memoryGiB"`
+	DiskGiB   float32 `json:"diskGiB"`
+	CreatedAt string  `json:"createdAt,omitempty"`
+}
+
+func newLifecycleNode(node *corev1.Node) *lifecycleNode {
+	return &lifecycleNode{
+		Name:       node.Name,
+		ProviderId: node.Spec.ProviderID,
+		Zone:       node.Labels[ZoneLabel],
+		Addresses:  extractNodeIPs(node),
+		Labels:     node.Labels,
+		CreatedAt:  node.CreationTimestamp.Time,
+	}
+}
+
+func newLifecycleRunner(runner daytona.RunnerFull) *lifecycleRunner {
+	return &lifecycleRunner{
+		Id:        runner.GetId(),
+		Domain:    runner.GetDomain(),
+		Version:   runner.GetVersion(),
+		Class:     string(runner.GetClass()),
+		Cpu:       runner.GetCpu(),
+		MemoryGiB: runner.GetMemory(),
+		DiskGiB:   runner.GetDisk(),
+		CreatedAt: runner.GetCreatedAt(),
+	}
+}
+
+// lifecycleNotifier delivers lifecycle events to the configured webhooks in the background. Each webhook has its own
+// queue, so a failing receiver delays neither the controller loop nor the other receivers.
+type lifecycleNotifier struct {
+	queues map[string]chan lifecycleEvent
+}
+
+func newLifecycleNotifier(cfg *Config) *lifecycleNotifier {
+	n := &lifecycleNotifier{
+		queues: make(map[string]chan lifecycleEvent, len(cfg.LifecycleWebhookURLs)),
+	}
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	for _, url := range cfg.LifecycleWebhookURLs {
+		queue := make(chan lifecycleEvent, lifecycleWebhookQueueSize)
+		n.queues[url] = queue
+		go deliverLifecycleEvents(httpClient, url, cfg.LifecycleWebhookSecret, cfg.LifecycleWebhookMaxAttempts, queue)
+	}
+
+	return n
+}
+
+// notifyChanges sends the lifecycle events of the region between two consecutive cycles. Nothing is sent for the
+// first cycle, as the nodes and runners found at startup are not new.
+func (n *lifecycleNotifier) notifyChanges(regionID string, previous, current *ClusterState) {
+	if previous == nil {
+		return
+	}
+
+	previousNodes := make(map[string]bool, len(previous.Nodes))
+	for _, node := range previous.Nodes {
+		previousNodes[node.Name] = true
+	}
+	currentNodes := make(map[string]bool, len(current.Nodes))
+	for i := range current.Nodes {
+		node := &current.Nodes[i]
+		currentNodes[node.Name] = true
+		if !previousNodes[node.Name] {
+			n.send(regionID, LifecycleNodeProvisioned, node, nil)
+		}
+	}
+
+	previousRunners := make(map[string]bool, len(previous.Runners))
+	for _, runner := range previous.Runners {
+		previousRunners[runner.GetId()] = true
+	}
+	for _, runner := range current.Runners {
+		if !previousRunners[runner.GetId()] {
+			n.send(regionID, LifecycleRunnerRegistered, current.NodeByIP[runner.GetDomain()], &runner)
+		}
+	}
+
+	// Torn down nodes are described as last seen, with the runner they hosted
+	runnersByNode := make(map[string]daytona.RunnerFull, len(previous.Runners))
+	for _, runner := range previous.Runners {
+		if node, found := previous.NodeByIP[runner.GetDomain()]; found {
+			runnersByNode[node.Name] = runner
+		}
+	}
+	for i := range previous.Nodes {
+		node := &previous.Nodes[i]
+		if currentNodes[node.Name] {
+			continue
+		}
+		if runner, found := runnersByNode[node.Name]; found {
+			n.send(regionID, LifecycleNodeTeardown, node, &runner)
+		} else {
+			n.send(regionID, LifecycleNodeTeardown, node, nil)
+		}
+	}
+}
+
+func (n *lifecycleNotifier) send(regionID, eventType string, node *corev1.Node, runner *daytona.RunnerFull) {
+	event := lifecycleEvent{
+		Id:       generateRandomString(16),
+		Type:     eventType,
+		Time:     time.Now().UTC(),
+		RegionId: regionID,
+	}
+	if node != nil {
+		event.Node = newLifecycleNode(node)
+	}
+	if runner != nil {
+		event.Runner = newLifecycleRunner(*runner)
+	}
+
+	for url, queue := range n.queues {
+		select {
+		case queue <- event:
+		default:
+			log.Warnf("Lifecycle webhook %s is backed up, dropping %s event %s", url, eventType, event.Id)
+			lifecycleWebhookDeliveries.WithLabelValues(eventType, "dropped").Inc()
+		}
+	}
+}
+
+// deliverLifecycleEvents posts the queued events in order, retrying each with exponential backoff
+func deliverLifecycleEvents(httpClient *http.Client, url, secret string, maxAttempts int, queue <-chan lifecycleEvent) {
+	for event := range queue {
+		backoff := time.Second
+		for attempt := 1; ; attempt++ {
+			err := postLifecycleEvent(httpClient, url, secret, event)
+			if err == nil {
+				lifecycleWebhookDeliveries.WithLabelValues(event.Type, "delivered").Inc()
+				break
+			}
+			if attempt >= maxAttempts {
+				log.Errorf("Error delivering %s event %s to lifecycle webhook %s after %d attempts: %v", event.Type, event.Id, url, attempt, err)
+				lifecycleWebhookDeliveries.WithLabelValues(event.Type, "failed").Inc()
+				break
+			}
+
+			time.Sleep(backoff)
+			backoff = min(backoff*2, lifecycleWebhookMaxBackoff)
+		}
+	}
+}
+
+// postLifecycleEvent posts the event, signed with an HMAC-SHA256 of the timestamp and body when a secret is set so
+// receivers can verify its origin and reject replays
+func postLifecycleEvent(httpClient *http.Client, url, secret string, event lifecycleEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Daytona-Event", event.Type)
+	req.Header.Set("X-Daytona-Event-Id", event.Id)
+
+	if secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(body)
+		req.Header.Set("X-Daytona-Timestamp", timestamp)
+		req.Header.Set("X-Daytona-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
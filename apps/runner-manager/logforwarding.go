@@ -0,0 +1,285 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// LogForwarderName is the name of the log forwarder DaemonSet and its ConfigMap
+	LogForwarderName = "daytona-log-forwarder"
+
+	// LogForwarderConfigHashAnnotation holds the hash of the rendered forwarder configuration
+	LogForwarderConfigHashAnnotation = "daytona.io/log-forwarder-config-hash"
+
+	// DefaultLogForwarderImage is the Vector image used when LOG_FORWARDER_IMAGE is not set
+	DefaultLogForwarderImage = "timberio/vector:0.46.1-distroless-libc"
+
+	// LogForwardingSinkLoki and LogForwardingSinkS3 are the supported log destinations
+	LogForwardingSinkLoki = "loki"
+	LogForwardingSinkS3   = "s3"
+
+	// SandboxOrganizationLabel is the Docker label the runner sets on sandbox containers
+	SandboxOrganizationLabel = "daytona.organization_id"
+)
+
+// renderLogForwarderConfig renders the Vector configuration shipping runner and sandbox container logs,
+// keyed by sandbox and organization, to the configured sink
+func renderLogForwarderConfig(cfg *Config) (string, error) {
+	sinks := map[string]any{}
+
+	switch cfg.LogForwardingSink {
+	case LogForwardingSinkLoki:
+		sinks["loki"] = map[string]any{
+			"type":     "loki",
+			"inputs":   []string{"keyed"},
+			"endpoint": cfg.LogForwardingLokiURL,
+			"encoding": map[string]any{"codec": "json"},
+			"labels": map[string]string{
+				"region":          cfg.RegionID,
+				"node":            "{{ node }}",
+				"organization_id": "{{ organization_id }}",
+				"sandbox_id":      "{{ sandbox_id }}",
+				"source":          "{{ source }}",
+			},
+		}
+	case LogForwardingSinkS3:
+		sinks["s3"] = map[string]any{
+			"type":        "aws_s3",
+			"inputs":      []string{"keyed"},
+			"bucket":      cfg.LogForwardingS3Bucket,
+			"region":      cfg.LogForwardingS3Region,
+			"key_prefix":  cfg.RegionID + "/{{ organization_id }}/{{ sandbox_id }}/%F/",
+			"compression": "gzip",
+			"encoding":    map[string]any{"codec": "json"},
+		}
+	default:
+		return "", fmt.Errorf("unsupported log forwarding sink %q", cfg.LogForwardingSink)
+	}
+
+	sources := map[string]any{
+		"containers": map[string]any{
+			"type":           "docker_logs",
+			"docker_host":    "unix:///var/run/docker.sock",
+			"include_labels": []string{},
+		},
+	}
+	inputs := []string{"containers"}
+	if cfg.LogForwardingRunnerLogPath != "" {
+		sources["runner_file"] = map[string]any{
+			"type":    "file",
+			"include": []string{cfg.LogForwardingRunnerLogPath},
+		}
+		inputs = append(inputs, "runner_file")
+	}
+
+	// Sandbox containers carry the organization label and are named after the sandbox, everything else is runner output
+	remap := fmt.Sprintf(`.node = get_env_var("VECTOR_SELF_NODE_NAME") ?? "unknown"
+org = .label.%q
+if is_string(org) && org != "" {
+  .source = "sandbox"
+  .organization_id = org
+  .sandbox_id = .container_name
+} else {
+  .source = "runner"
+  .organization_id = "none"
+  .sandbox_id = "none"
+}`, SandboxOrganizationLabel)
+
+	config := map[string]any{
+		"data_dir": "/var/lib/vector",
+		"sources":  sources,
+		"transforms": map[string]any{
+			"keyed": map[string]any{
+				"type":   "remap",
+				"inputs": inputs,
+				"source": remap,
+			},
+		},
+		"sinks": sinks,
+	}
+
+	rendered, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(rendered), nil
+}
+
+// reconcileLogForwarding makes sure the log forwarder ConfigMap and DaemonSet in the pool's namespace match the
+// configuration. The DaemonSet targets the same nodes as the pool's placeholder pods so new pool nodes get log
+// shipping automatically. The LOG_FORWARDING_S3_SECRET secret is looked up in the pool's namespace.
+func reconcileLogForwarding(clientset *kubernetes.Clientset, cfg *Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rendered, err := renderLogForwarderConfig(cfg)
+	if err != nil {
+		return err
+	}
+	hashBytes := sha256.Sum256([]byte(rendered + cfg.LogForwarderImage + cfg.LogForwardingS3Secret))
+	configHash := hex.EncodeToString(hashBytes[:])
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        LogForwarderName,
+			Namespace:   cfg.ProviderNamespace,
+			Labels:      map[string]string{"app": LogForwarderName},
+			Annotations: map[string]string{LogForwarderConfigHashAnnotation: configHash},
+		},
+		Data: map[string]string{
+			"vector.yaml": rendered,
+		},
+	}
+
+	configMaps := clientset.CoreV1().ConfigMaps(cfg.ProviderNamespace)
+	existingConfigMap, err := configMaps.Get(ctx, LogForwarderName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		if _, err := configMaps.Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create log forwarder config map: %w", err)
+		}
+		log.Infof("Created log forwarder config map %s/%s", cfg.ProviderNamespace, LogForwarderName)
+	case err != nil:
+		return fmt.Errorf("failed to get log forwarder config map: %w", err)
+	case existingConfigMap.Annotations[LogForwarderConfigHashAnnotation] != configHash:
+		configMap.ResourceVersion = existingConfigMap.ResourceVersion
+		if _, err := configMaps.Update(ctx, configMap, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update log forwarder config map: %w", err)
+		}
+		log.Infof("Updated log forwarder config map %s/%s", cfg.ProviderNamespace, LogForwarderName)
+	}
+
+	daemonSet := buildLogForwarderDaemonSet(cfg, configHash)
+
+	daemonSets := clientset.AppsV1().DaemonSets(cfg.ProviderNamespace)
+	existingDaemonSet, err := daemonSets.Get(ctx, LogForwarderName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		if _, err := daemonSets.Create(ctx, daemonSet, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create log forwarder daemon set: %w", err)
+		}
+		log.Infof("Created log forwarder daemon set %s/%s", cfg.ProviderNamespace, LogForwarderName)
+	case err != nil:
+		return fmt.Errorf("failed to get log forwarder daemon set: %w", err)
+	case existingDaemonSet.Annotations[LogForwarderConfigHashAnnotation] != configHash:
+		daemonSet.ResourceVersion = existingDaemonSet.ResourceVersion
+		if _, err := daemonSets.Update(ctx, daemonSet, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update log forwarder daemon set: %w", err)
+		}
+		log.Infof("Updated log forwarder daemon set %s/%s", cfg.ProviderNamespace, LogForwarderName)
+	}
+
+	return nil
+}
+
+// buildLogForwarderDaemonSet builds the DaemonSet running the log forwarder on every pool node
+func buildLogForwarderDaemonSet(cfg *Config, configHash string) *appsv1.DaemonSet {
+	labels := map[string]string{"app": LogForwarderName}
+	hostPathSocket := corev1.HostPathSocket
+	hostPathDirectoryOrCreate := corev1.HostPathDirectoryOrCreate
+
+	container := corev1.Container{
+		Name:  "vector",
+		Image: cfg.LogForwarderImage,
+		Args:  []string{"--config", "/etc/vector/vector.yaml"},
+		Env: []corev1.EnvVar{
+			{
+				Name: "VECTOR_SELF_NODE_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+				},
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "config", MountPath: "/etc/vector", ReadOnly: true},
+			{Name: "docker-socket", MountPath: "/var/run/docker.sock"},
+			{Name: "data", MountPath: "/var/lib/vector"},
+		},
+	}
+	if cfg.LogForwardingS3Secret != "" {
+		container.EnvFrom = []corev1.EnvFromSource{
+			{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: cfg.LogForwardingS3Secret}}},
+		}
+	}
+
+	volumes := []corev1.Volume{
+		{
+			Name: "config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: LogForwarderName}},
+			},
+		},
+		{
+			Name: "docker-socket",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{Path: "/var/run/docker.sock", Type: &hostPathSocket},
+			},
+		},
+		{
+			Name: "data",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{Path: "/var/lib/daytona-log-forwarder", Type: &hostPathDirectoryOrCreate},
+			},
+		},
+	}
+
+	if cfg.LogForwardingRunnerLogPath != "" {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: "runner-logs", MountPath: cfg.LogForwardingRunnerLogPath, ReadOnly: true})
+		volumes = append(volumes, corev1.Volume{
+			Name: "runner-logs",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{Path: cfg.LogForwardingRunnerLogPath},
+			},
+		})
+	}
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        LogForwarderName,
+			Namespace:   cfg.ProviderNamespace,
+			Labels:      labels,
+			Annotations: map[string]string{LogForwarderConfigHashAnnotation: configHash},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					// Roll the pods whenever the configuration changes
+					Annotations: map[string]string{LogForwarderConfigHashAnnotation: configHash},
+				},
+				Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{
+						cfg.NodeSelectorKey: "true",
+					},
+					Tolerations: []corev1.Toleration{
+						{
+							Key:      cfg.TaintKey,
+							Operator: corev1.TolerationOpEqual,
+							Value:    "true",
+							Effect:   cfg.TaintEffect,
+						},
+					},
+					Containers: []corev1.Container{container},
+					Volumes:    volumes,
+				},
+			},
+		},
+	}
+}
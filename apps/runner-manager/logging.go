@@ -0,0 +1,56 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// LogLevelInfo logs only the changes between cycles, LogLevelDebug also logs the full state every cycle
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+
+	// LogFormatJSON writes one JSON object per entry, for log stores such as Loki or Elasticsearch
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// configureLogging applies the configured level and format. Every entry carries the region, so the logs of the
+// runner-managers of several regions can be queried together.
+func configureLogging(cfg *Config) {
+	level, err := log.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		level = log.InfoLevel
+	}
+	log.SetLevel(level)
+
+	if cfg.LogFormat == LogFormatJSON {
+		log.SetFormatter(&log.JSONFormatter{})
+	} else {
+		log.SetFormatter(&log.TextFormatter{FullTimestamp: true})
+	}
+
+	log.AddHook(&fieldsHook{fields: log.Fields{"region": cfg.RegionID}})
+}
+
+// fieldsHook adds static fields to every entry, without overriding fields set on the entry itself
+type fieldsHook struct {
+	fields log.Fields
+}
+
+func (h *fieldsHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h *fieldsHook) Fire(entry *log.Entry) error {
+	for key, value := range h.fields {
+		if _, found := entry.Data[key]; !found {
+			entry.Data[key] = value
+		}
+	}
+	return nil
+}
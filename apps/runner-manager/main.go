@@ -2,36 +2,226 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
-	"log"
 	"math"
 	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
+	"text/template"
 	"time"
 
+	"github.com/daytonaio/common-go/pkg/adminauth"
+	"github.com/daytonaio/common-go/pkg/directive"
+	"github.com/daytonaio/common-go/pkg/protection"
+	"github.com/daytonaio/common-go/pkg/quota"
+	"github.com/daytonaio/common-go/pkg/ratelimit"
+	"github.com/daytonaio/common-go/pkg/traffic"
+	"github.com/daytonaio/common-go/pkg/tunnel"
+	"github.com/daytonaio/daytona/apps/runner-manager/pkg/hostreport"
+	"github.com/daytonaio/daytona/apps/runner-manager/pkg/policy"
+	"github.com/daytonaio/daytona/apps/runner-manager/pkg/status"
 	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+
+	log "github.com/sirupsen/logrus"
 )
 
 // Config holds the configuration for the runner-manager
 type Config struct {
-	APIPort                       string
-	DaytonaAPIURL                 string
-	DaytonaAPIKey                 string
-	ProviderNamespace             string
-	RegionID                      string
-	MaxResourceUtilizationPercent int
-	MinIdleRunners                int
-	MinIdleCpu                    int
-	MinIdleMemory                 int
+	APIPort                         string
+	DaytonaAPIURL                   string
+	DaytonaAPIKey                   string
+	ProviderNamespace               string
+	RegionID                        string
+	KubeContext                     string
+	RegionThresholds                map[string]regionThresholds
+	MaxResourceUtilizationPercent   int
+	ScaleDownUtilizationPercent     int
+	MinIdleRunners                  int
+	MinIdleCpu                      int
+	MinIdleMemory                   int
+	MinIdleCpuPercent               int
+	MinIdleMemoryPercent            int
+	MinIdleGpu                      int
+	MinIdleDisk                     int
+	MaxDiskUtilizationPercent       int
+	PlaceholderGpus                 int
+	SpotNodeSelector                map[string]string
+	OnDemandNodeSelector            map[string]string
+	SpotPendingTimeout              time.Duration
+	SpotRetryInterval               time.Duration
+	SpotInterruptionTaints          []string
+	MinIdleRunnersPerZone           map[string]int
+	ZoneBalancingEnabled            bool
+	MaxNodes                        int
+	MaxRunners                      int
+	MaxScaleUpPerCycle              int
+	IdleTuningMaxRunners            int
+	IdleTuningMaxCpu                int
+	IdleTuningMaxMemory             int
+	IdleTuningQuietPeriod           time.Duration
+	DaytonaAPIRateLimit             float64
+	DaytonaAPIRateLimitBurst        int
+	DaytonaAPIRetryAttempts         int
+	DaytonaAPIRetryBaseDelay        time.Duration
+	DaytonaAPIRetryMaxDelay         time.Duration
+	DaytonaAPIRetryBudget           int
+	TracingEndpoint                 string
+	TracingHeaders                  map[string]string
+	TracingServiceName              string
+	NodeReportToken                 string
+	TrafficReportToken              string
+	HighTrafficBytesPerSecond       float64
+	BacklogScalingEnabled           bool
+	NodeProfiles                    []nodeProfile
+	ReservedSandboxClasses          []sandboxClass
+	ScaleDownChecks                 []string
+	DrainTimeout                    time.Duration
+	NascentNodeTimeout              time.Duration
+	CapacityExhaustionThreshold     time.Duration
+	SnapshotPrepullCount            int
+	SnapshotPrepullTimeout          time.Duration
+	SnapshotPrepullRegistryURL      string
+	SnapshotPrepullRegistryUsername string
+	SnapshotPrepullRegistryPassword string
+	MissingNodeDeregisterCycles     int
+	RolloutEnabled                  bool
+	RolloutMaxSurge                 int
+	MigrationNodeSelector           map[string]string
+	MigrationMaxSurge               int
+	NodeProvisioner                 string
+	NodeProvisionerGroup            string
+	NodeHourlyPrices                map[string]float64
+	NodeHourlyPriceLabel            string
+	ScaleDownWeights                map[string]float64
+	CpuOvercommitRatio              float64
+	MemoryOvercommitRatio           float64
+	UsageSource                     string
+	UsageWeight                     float64
+	UsagePrometheusURL              string
+	UsagePrometheusCpuQuery         string
+	UsagePrometheusMemoryQuery      string
+	TLSCertFile                     string
+	TLSKeyFile                      string
+	AdminAuth                       adminauth.Config
+	ConfigDriftCheckInterval        time.Duration
+	ConfigDriftAdopt                bool
+	ScalingPolicyPluginPath         string
+	ScalingPolicyGRPCAddress        string
+	ScalingPolicyTimeout            time.Duration
+	HibernationBackend              string
+	HibernationMaxNodes             int
+	HibernationAWSStandby           bool
+	HibernationWebhookURL           string
+	HibernationWebhookToken         string
+	EvictionNoticeProxyURLs         []string
+	EvictionNoticeToken             string
+	EvictionNoticeLeadTime          time.Duration
+	LifecycleWebhookURLs            []string
+	LifecycleWebhookSecret          string
+	LifecycleWebhookMaxAttempts     int
+	NotificationWebhookURL          string
+	NotificationMinInterval         time.Duration
+	DirectivesURL                   string
+	DirectivesToken                 string
+	DirectivesPollInterval          time.Duration
+	DirectivesAuditLogFile          string
+	PlaceholderPodTemplate          *template.Template
+	PlaceholderImage                string
+	PlaceholderImagePullSecrets     []string
+	PlaceholderTolerations          []corev1.Toleration
+	PlaceholderPriorityClassName    string
+	PlaceholderCpuRequest           resource.Quantity
+	PlaceholderCpuRequestAuto       bool
+	PlaceholderMemoryRequest        resource.Quantity
+	PlaceholderMemoryRequestAuto    bool
+	PoolOS                          string
+	PoolName                        string
+	NodeSelectorKey                 string
+	TaintKey                        string
+	TaintEffect                     corev1.TaintEffect
+	Pools                           []poolDefinition
+	RunnerPoolPoliciesEnabled       bool
+	ConfigDir                       string
+	ClusterBackend                  string
+	NomadAddr                       string
+	NomadToken                      string
+	NomadNamespace                  string
+	NomadRegion                     string
+	NomadNodeClass                  string
+	NomadDatacenters                []string
+	LogLevel                        string
+	LogFormat                       string
+	ScalingHistoryFile              string
+	ScalingHistoryRetention         time.Duration
+	DecisionHistoryFile             string
+	DecisionHistoryRetention        time.Duration
+	ScalingSchedules                []scalingScheduleEntry
+	ScalingScheduleLocation         *time.Location
+	PredictiveScalingEnabled        bool
+	PredictiveSeasonality           string
+	PredictiveLeadTime              time.Duration
+	PredictiveWindow                time.Duration
+	PredictivePeriods               int
+	CheckInterval                   time.Duration
+	ScaleUpCooldown                 time.Duration
+	ScaleDownCooldown               time.Duration
+
+	QuotaEnforcementEnabled            bool
+	QuotaDefaultMaxConcurrentSandboxes int
+
+	CapacityReservationsEnabled bool
+
+	LogForwardingSink          string
+	LogForwarderImage          string
+	LogForwardingLokiURL       string
+	LogForwardingS3Bucket      string
+	LogForwardingS3Region      string
+	LogForwardingS3Secret      string
+	LogForwardingRunnerLogPath string
+
+	AdmissionWebhookServiceName      string
+	AdmissionWebhookServiceNamespace string
+	AdmissionWebhookServicePort      int
+	AdmissionWebhookCAFile           string
+	AdmissionExemptUserPrefixes      []string
+
+	PlaceholderDisruptionBudgetEnabled bool
+
+	HealthRemediationUnhealthyCycles int
+	HealthRemediationRecoveryTimeout time.Duration
+
+	CapacityDriftThresholdPercent int
+	CapacityDriftSource           string
+
+	PprofEnabled          bool
+	RuntimeMetricsEnabled bool
+
+	CircuitBreakerErrorCycles int
+
+	ScaleDownGracePeriod time.Duration
+
+	WarmStandbyNodes int
+
+	ScaleDownBlackoutWindows []blackoutWindow
+
+	VelocityScalingEnabled bool
+	VelocityLeadTime       time.Duration
 }
 
 // ClusterState represents the current state of the cluster
@@ -43,12 +233,64 @@ type ClusterState struct {
 
 	RunnerByDomain map[string]daytona.RunnerFull // Maps runner domain (IP) to runner
 
+	NodelessRunners []daytona.RunnerFull // Runners of the region whose domain matches none of the pool's nodes
+
 	PendingPlaceholders   []*corev1.Pod
 	ScheduledPlaceholders []*corev1.Pod
 
 	Nodes        []corev1.Node           // All nodes
 	NodeByIP     map[string]*corev1.Node // Maps node IP to node
 	NascentNodes []*corev1.Node          // Nodes with scheduled placeholders but no runner yet
+
+	HibernatedNodes []*corev1.Node // Nodes stopped instead of removed, resumable on scale-up
+	ResumingNodes   []*corev1.Node // Resumed nodes whose runner is kept unschedulable until the node is back
+
+	StandbyRunners []daytona.RunnerFull // Unschedulable runners of the warm buffer, promoted on scale-up, empty unless WARM_STANDBY_NODES is set
+
+	UnreachableRunnerIDs map[string]bool // Idle runners behind NAT whose relayed heartbeat stopped
+	TunneledDomains      map[string]bool // Domains of the runners behind NAT, matched to their node by a relayed heartbeat
+
+	CapacityType string // Capacity type of the placeholders created this cycle, empty unless the pool uses spot capacity
+
+	PlaceholdersCreated int // Placeholders created for scale-ups this cycle, not yet in PendingPlaceholders
+
+	ScaleUpCappedBy      string   // Limit that capped a scale-up this cycle, MAX_NODES or MAX_RUNNERS, empty if none did
+	ScaleDownFailures    int      // Placeholders scale-down failed to delete this cycle
+	TimedOutNascentNodes []string // Nodes given up on this cycle as their runner never registered
+
+	ExhaustedPlaceholders int // Placeholders pending past CAPACITY_EXHAUSTION_THRESHOLD
+
+	Degraded bool // Control-plane calls failed in too many consecutive cycles, scale-down is stopped and scale-up limited
+
+	PrewarmingRunnerIDs map[string]bool // New runners still pre-pulling snapshots, empty unless SNAPSHOT_PREPULL_COUNT is set
+
+	AllocatedGPUs map[string]float32 // GPUs allocated to started sandboxes by runner ID, empty unless the pool is a GPU pool
+
+	QueuedSandboxes int // Sandboxes of the pool's operating system waiting for capacity
+
+	Backlog sandboxBacklog // Queued sandboxes of the pool's operating system and the resources they requested
+
+	ZoneIdle map[string]*zoneIdleStatus // Per-zone idle requirements by zone, empty unless MIN_IDLE_RUNNERS_PER_ZONE is set
+
+	CapacityDrift map[string]*capacityDrift // Runners whose reported capacity diverges from their node's by runner ID, empty unless CAPACITY_DRIFT_THRESHOLD_PERCENT is set
+
+	ClassRoom map[string]*classRoomStatus // Room of the reserved sandbox classes by name, empty unless RESERVED_SANDBOX_CLASSES is set
+
+	NodeReports map[string]*hostreport.Report // Fresh capacity reporter data by node name
+
+	NodeUsage map[string]nodeUsage // Actual usage by node name, empty unless USAGE_SOURCE is set and was readable
+
+	ProtectedRunnerIDs map[string]bool // Runners hosting do-not-disturb sandboxes
+
+	ScaleDownFreeze *directive.Directive // Control plane directive freezing scale-down in the region, nil unless active
+
+	ScaleDownBlackout *scaleDownBlackout // Blackout window disallowing scale-down this cycle, nil unless one is active
+
+	RunnerTraffic map[string]*runnerTraffic // Preview traffic reported by proxies by runner ID
+
+	Packing status.PackingReport // Bin-packing assessment of the schedulable runners
+
+	Why *cycleExplanation `json:"-"` // Why the cycle scaled as it did, published to the why endpoint when the cycle ends
 }
 
 // ResourceMetrics holds aggregated resource metrics
@@ -61,238 +303,2225 @@ type ResourceMetrics struct {
 	TotalAvailableMemoryGiB float32
 	AvgCpuPerNode           float32
 	AvgMemPerNode           float32
+
+	// GPUs are a third resource dimension, zero outside of GPU pools
+	TotalGPUCapacity  float32
+	TotalAllocatedGPU float32
+	TotalAvailableGPU float32
+	AvgGpuPerNode     float32
+
+	// Disk only triggers scaling when MIN_IDLE_DISK or MAX_DISK_UTILIZATION_PERCENT is set
+	TotalDiskGiBCapacity  float32
+	TotalAllocatedDiskGiB float32
+	TotalAvailableDiskGiB float32
+	AvgDiskPerNode        float32
 }
 
 const (
-	// CheckInterval defines how often the controller loop runs
-	CheckInterval = 30 * time.Second
+	// DefaultCheckInterval defines how often the controller loop runs when no cluster change triggers it earlier
+	DefaultCheckInterval = 30 * time.Second
+
+	// ShutdownTimeout bounds how long the health check server waits for in-flight requests on shutdown
+	ShutdownTimeout = 10 * time.Second
 
 	// PlaceholderPodLabel is the label for naming placeholder pods
 	PlaceholderPodLabel = "daytona-runner-placeholder"
 
-	// NodeSelectorKey and TaintKey are constants for Kubernetes node selection
-	NodeSelectorKey = "daytona-sandbox-c"
-	TaintKey        = "sandbox"
+	// DefaultNodeSelectorKey and DefaultTaintKey are the node label and taint selecting the pool's nodes
+	DefaultNodeSelectorKey = "daytona-sandbox-c"
+	DefaultTaintKey        = "sandbox"
 )
 
 // main function to start the runner-manager
 func main() {
-	log.Println("Starting runner-manager...")
+	log.Info("Starting runner-manager...")
 
-	cfg, err := loadConfig()
+	cfg, err := loadConfig(os.Args[1:])
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
+	configureLogging(cfg)
+	stopTracing := startTracing(cfg)
+
+	// Cancelled on SIGTERM, e.g. on pod eviction, or SIGINT; the cycle in progress is completed before exiting
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	// Also cancelled when the pools must be rebuilt, the exit then restarts the container with the new pools
+	ctx, restart := context.WithCancel(ctx)
+	defer restart()
 
 	apiClient, err := initializeDaytonaClient(cfg)
 	if err != nil {
 		log.Fatalf("Failed to initialize Daytona API client: %v", err)
 	}
 
-	clientset, err := initializeKubernetesClient()
+	var poolPolicies *poolPolicyOperator
+	if cfg.RunnerPoolPoliciesEnabled {
+		poolPolicies, err = newPoolPolicyOperator(cfg)
+		if err != nil {
+			log.Fatalf("Failed to initialize the %s operator: %v", RunnerPoolPolicyKind, err)
+		}
+		cfg.Pools, err = poolPolicies.start(ctx)
+		if err != nil {
+			log.Fatalf("Failed to load %s resources: %v", RunnerPoolPolicyKind, err)
+		}
+	}
+
+	var reloader *configReloader
+	if cfg.ConfigDir != "" {
+		reloader, err = newConfigReloader(cfg)
+		if err != nil {
+			log.Fatalf("Failed to load the configuration of %s: %v", cfg.ConfigDir, err)
+		}
+	}
+
+	pools, err := newRunnerPools(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize %s backend: %v", cfg.ClusterBackend, err)
+	}
+	for _, pool := range pools {
+		pool.reloader = reloader
+	}
+	if poolPolicies != nil {
+		for _, pool := range pools {
+			pool.policies = poolPolicies
+		}
+		if err := poolPolicies.watch(ctx, restart); err != nil {
+			log.Fatalf("Failed to watch %s resources: %v", RunnerPoolPolicyKind, err)
+		}
+	}
+
+	nodeReports := newNodeReportStore()
+	drains := newDrainStore()
+	trafficReports := newTrafficStore()
+	tunnels := newTunnelStore()
+
+	adminAuth, err := adminauth.NewAuthenticator(ctx, cfg.AdminAuth)
+	if err != nil {
+		log.Fatalf("Failed to initialize admin authentication: %v", err)
+	}
+	if !adminAuth.Enabled() {
+		log.Warn("No admin authentication configured, admin endpoints reject every request")
+	}
+	if cfg.NodeReportToken == "" {
+		log.Warn("NODE_REPORT_TOKEN not set, capacity reports are rejected")
+	}
+	if cfg.TrafficReportToken == "" {
+		log.Warn("TRAFFIC_REPORT_TOKEN not set, traffic and tunnel reports are rejected")
+	}
+
+	var directiveAudit *directive.AuditLog
+	if cfg.DirectivesAuditLogFile != "" {
+		directiveAudit, err = directive.OpenAuditLog(cfg.DirectivesAuditLogFile)
+		if err != nil {
+			log.Fatalf("Failed to open directive audit log: %v", err)
+		}
+	}
+	directives := directive.NewStore(directiveAudit)
+	if cfg.DirectivesURL != "" {
+		go directives.Poll(ctx, cfg.DirectivesURL, cfg.DirectivesToken, cfg.DirectivesPollInterval)
+	}
+
+	var history *scalingHistory
+	if cfg.ScalingHistoryFile != "" {
+		history, err = openScalingHistory(cfg.ScalingHistoryFile, cfg.ScalingHistoryRetention)
+		if err != nil {
+			log.Fatalf("Failed to open scaling history: %v", err)
+		}
+	}
+
+	var decisions *decisionHistory
+	if cfg.DecisionHistoryFile != "" {
+		decisions, err = openDecisionHistory(cfg.DecisionHistoryFile, cfg.DecisionHistoryRetention)
+		if err != nil {
+			log.Fatalf("Failed to open decision history: %v", err)
+		}
+	}
+
+	server := startHealthCheckServer(cfg, adminAuth, pools, nodeReports, drains, trafficReports, tunnels, directives, history, decisions)
+
+	var quotaEnforcer *quota.Enforcer
+	if cfg.QuotaEnforcementEnabled {
+		quotaEnforcer = newQuotaEnforcer(cfg, apiClient)
+	}
+
+	hibernator, err := newNodeHibernator(cfg)
 	if err != nil {
-		log.Fatalf("Failed to initialize Kubernetes client: %v", err)
+		log.Fatalf("Failed to initialize node hibernation: %v", err)
+	}
+
+	scalingPolicy, stopScalingPolicy, err := loadScalingPolicy(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load scaling policy: %v", err)
+	}
+	defer stopScalingPolicy()
+
+	var lifecycle *lifecycleNotifier
+	if len(cfg.LifecycleWebhookURLs) > 0 {
+		lifecycle = newLifecycleNotifier(cfg)
+	}
+	var notifier *scalingNotifier
+	if cfg.NotificationWebhookURL != "" {
+		notifier = newScalingNotifier(cfg)
 	}
 
-	startHealthCheckServer(cfg.APIPort)
+	// Each pool gathers its state and makes its scaling decisions in its own loop
+	var loops sync.WaitGroup
+	for _, pool := range pools {
+		loops.Add(1)
+		go func() {
+			defer loops.Done()
+			runControllerLoop(ctx, pool, apiClient, nodeReports, drains, trafficReports, tunnels, directives, quotaEnforcer, scalingPolicy, hibernator, lifecycle, notifier, history, decisions)
+		}()
+	}
+	loops.Wait()
 
-	runControllerLoop(cfg, apiClient, clientset)
+	log.Info("Shutting down runner-manager...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Warnf("Health check server did not shut down gracefully: %v", err)
+	}
+	stopTracing(shutdownCtx)
 }
 
-// loadConfig reads and validates configuration from environment variables
-func loadConfig() (*Config, error) {
+// loadConfig reads and validates the configuration from the config file, the environment and the command-line
+// flags, see configLoader, reporting all invalid and missing settings at once
+func loadConfig(args []string) (*Config, error) {
+	l, err := newConfigLoader(args)
+	if err != nil {
+		return nil, err
+	}
 	cfg := &Config{}
 
-	cfg.APIPort = os.Getenv("API_PORT")
+	cfg.APIPort = l.get("API_PORT")
 	if cfg.APIPort == "" {
-		return nil, fmt.Errorf("environment variable API_PORT not set")
+		l.errorf("API_PORT not set")
 	}
 
-	cfg.DaytonaAPIURL = os.Getenv("DAYTONA_API_URL")
+	cfg.DaytonaAPIURL = l.get("DAYTONA_API_URL")
 	if cfg.DaytonaAPIURL == "" {
-		return nil, fmt.Errorf("environment variable DAYTONA_API_URL not set")
+		l.errorf("DAYTONA_API_URL not set")
+	}
+
+	cfg.DaytonaAPIKey = l.get("DAYTONA_API_KEY")
+	if cfg.DaytonaAPIKey == "" {
+		l.errorf("DAYTONA_API_KEY not set")
+	}
+
+	cfg.ProviderNamespace = l.get("PROVIDER_NAMESPACE")
+	if cfg.ProviderNamespace == "" {
+		l.errorf("PROVIDER_NAMESPACE not set")
+	}
+
+	cfg.RegionID = l.get("REGION_ID")
+	if cfg.RegionID == "" {
+		l.errorf("REGION_ID not set")
+	}
+
+	// Optional kubeconfig context of the cluster, the in-cluster configuration or the current context when unset
+	cfg.KubeContext = l.get("KUBE_CONTEXT")
+
+	maxResourceUtilizationPercentStr := l.get("MAX_RESOURCE_UTILIZATION_PERCENT")
+	if maxResourceUtilizationPercentStr == "" {
+		l.errorf("MAX_RESOURCE_UTILIZATION_PERCENT not set")
+	} else if cfg.MaxResourceUtilizationPercent, err = strconv.Atoi(maxResourceUtilizationPercentStr); err != nil {
+		l.errorf("invalid MAX_RESOURCE_UTILIZATION_PERCENT: %v", err)
+	} else if cfg.MaxResourceUtilizationPercent < 0 || cfg.MaxResourceUtilizationPercent > 100 {
+		l.errorf("MAX_RESOURCE_UTILIZATION_PERCENT must be between 0 and 100")
+	}
+
+	// Optional low watermark of the utilization, nodes are then only removed while the pool stays below it rather than
+	// up to MAX_RESOURCE_UTILIZATION_PERCENT, which only triggers scale-up
+	if scaleDownUtilizationStr := l.get("SCALE_DOWN_UTILIZATION_PERCENT"); scaleDownUtilizationStr != "" {
+		cfg.ScaleDownUtilizationPercent, err = strconv.Atoi(scaleDownUtilizationStr)
+		if err != nil {
+			l.errorf("invalid SCALE_DOWN_UTILIZATION_PERCENT: %v", err)
+		} else if cfg.ScaleDownUtilizationPercent < 1 || cfg.ScaleDownUtilizationPercent >= cfg.MaxResourceUtilizationPercent {
+			l.errorf("SCALE_DOWN_UTILIZATION_PERCENT must be between 1 and MAX_RESOURCE_UTILIZATION_PERCENT (%d), exclusive", cfg.MaxResourceUtilizationPercent)
+		}
+	}
+
+	minIdleRunnersStr := l.get("MIN_IDLE_RUNNERS")
+	if minIdleRunnersStr == "" {
+		l.errorf("MIN_IDLE_RUNNERS not set")
+	} else if cfg.MinIdleRunners, err = strconv.Atoi(minIdleRunnersStr); err != nil {
+		l.errorf("invalid MIN_IDLE_RUNNERS: %v", err)
+	} else if cfg.MinIdleRunners < 0 {
+		l.errorf("MIN_IDLE_RUNNERS cannot be negative")
+	}
+
+	minIdleCpuStr := l.get("MIN_IDLE_CPU")
+	if minIdleCpuStr == "" {
+		l.errorf("MIN_IDLE_CPU not set")
+	} else if cfg.MinIdleCpu, err = strconv.Atoi(minIdleCpuStr); err != nil {
+		l.errorf("invalid MIN_IDLE_CPU: %v", err)
+	} else if cfg.MinIdleCpu < 0 {
+		l.errorf("MIN_IDLE_CPU cannot be negative")
+	}
+
+	minIdleMemoryStr := l.get("MIN_IDLE_MEMORY")
+	if minIdleMemoryStr == "" {
+		l.errorf("MIN_IDLE_MEMORY not set")
+	} else if cfg.MinIdleMemory, err = strconv.Atoi(minIdleMemoryStr); err != nil {
+		l.errorf("invalid MIN_IDLE_MEMORY: %v", err)
+	} else if cfg.MinIdleMemory < 0 {
+		l.errorf("MIN_IDLE_MEMORY cannot be negative")
+	}
+
+	// Optional idle requirements as a percentage of the pool's capacity, raising MIN_IDLE_CPU and MIN_IDLE_MEMORY as
+	// the pool grows
+	for _, setting := range []struct {
+		env   string
+		value *int
+	}{
+		{"MIN_IDLE_CPU_PERCENT", &cfg.MinIdleCpuPercent},
+		{"MIN_IDLE_MEMORY_PERCENT", &cfg.MinIdleMemoryPercent},
+	} {
+		valueStr := l.get(setting.env)
+		if valueStr == "" {
+			continue
+		}
+		*setting.value, err = strconv.Atoi(valueStr)
+		if err != nil {
+			l.errorf("invalid %s: %v", setting.env, err)
+		} else if *setting.value < 0 || *setting.value > 100 {
+			l.errorf("%s must be between 0 and 100", setting.env)
+		}
+	}
+
+	// Optional caps on the pool size and on the nodes added per cycle, unlimited when unset
+	for _, limit := range []struct {
+		env   string
+		value *int
+	}{
+		{"MAX_NODES", &cfg.MaxNodes},
+		{"MAX_RUNNERS", &cfg.MaxRunners},
+		{"MAX_SCALE_UP_PER_CYCLE", &cfg.MaxScaleUpPerCycle},
+	} {
+		valueStr := l.get(limit.env)
+		if valueStr == "" {
+			continue
+		}
+		*limit.value, err = strconv.Atoi(valueStr)
+		if err != nil {
+			l.errorf("invalid %s: %v", limit.env, err)
+		} else if *limit.value < 0 {
+			l.errorf("%s cannot be negative", limit.env)
+		}
+	}
+
+	// Optional GPU requirements making the pool a GPU pool, whose placeholders request GPUs
+	for _, setting := range []struct {
+		env   string
+		value *int
+	}{
+		{"MIN_IDLE_GPU", &cfg.MinIdleGpu},
+		{"PLACEHOLDER_GPUS", &cfg.PlaceholderGpus},
+	} {
+		valueStr := l.get(setting.env)
+		if valueStr == "" {
+			continue
+		}
+		*setting.value, err = strconv.Atoi(valueStr)
+		if err != nil {
+			l.errorf("invalid %s: %v", setting.env, err)
+		} else if *setting.value < 0 {
+			l.errorf("%s cannot be negative", setting.env)
+		}
+	}
+	if cfg.MinIdleGpu > 0 && cfg.PlaceholderGpus == 0 {
+		cfg.PlaceholderGpus = 1
+	}
+
+	// Optional disk requirements for snapshot-heavy regions, disk does not trigger scaling when unset
+	if minIdleDiskStr := l.get("MIN_IDLE_DISK"); minIdleDiskStr != "" {
+		cfg.MinIdleDisk, err = strconv.Atoi(minIdleDiskStr)
+		if err != nil {
+			l.errorf("invalid MIN_IDLE_DISK: %v", err)
+		} else if cfg.MinIdleDisk < 0 {
+			l.errorf("MIN_IDLE_DISK cannot be negative")
+		}
+	}
+	if maxDiskUtilizationStr := l.get("MAX_DISK_UTILIZATION_PERCENT"); maxDiskUtilizationStr != "" {
+		cfg.MaxDiskUtilizationPercent, err = strconv.Atoi(maxDiskUtilizationStr)
+		if err != nil {
+			l.errorf("invalid MAX_DISK_UTILIZATION_PERCENT: %v", err)
+		} else if cfg.MaxDiskUtilizationPercent < 1 || cfg.MaxDiskUtilizationPercent > 100 {
+			l.errorf("MAX_DISK_UTILIZATION_PERCENT must be between 1 and 100")
+		}
+	}
+
+	// Optional idle runner requirements per availability zone on top of the pool-wide ones
+	if perZoneStr := l.get("MIN_IDLE_RUNNERS_PER_ZONE"); perZoneStr != "" {
+		cfg.MinIdleRunnersPerZone, err = parseZoneIdleRequirements(perZoneStr)
+		if err != nil {
+			l.errorf("invalid MIN_IDLE_RUNNERS_PER_ZONE: %v", err)
+		}
+	}
+
+	// Optional spreading of the pool across zones, keeping a runner free of sandboxes in each zone on scale-down
+	if zoneBalancingStr := l.get("ZONE_BALANCING_ENABLED"); zoneBalancingStr != "" {
+		cfg.ZoneBalancingEnabled, err = strconv.ParseBool(zoneBalancingStr)
+		if err != nil {
+			l.errorf("invalid ZONE_BALANCING_ENABLED: %v", err)
+		}
+	}
+
+	// Optional upper bounds the idle buffer is raised to after missed-capacity incidents, disabled when unset. Only
+	// sandboxes queued in the backlog count as incidents, creates the API rejects for lack of runners are not seen.
+	idleTuningBounds := []struct {
+		env   string
+		base  int
+		value *int
+	}{
+		{"IDLE_TUNING_MAX_RUNNERS", cfg.MinIdleRunners, &cfg.IdleTuningMaxRunners},
+		{"IDLE_TUNING_MAX_CPU", cfg.MinIdleCpu, &cfg.IdleTuningMaxCpu},
+		{"IDLE_TUNING_MAX_MEMORY", cfg.MinIdleMemory, &cfg.IdleTuningMaxMemory},
+	}
+	for _, bound := range idleTuningBounds {
+		valueStr := l.get(bound.env)
+		if valueStr == "" {
+			continue
+		}
+		*bound.value, err = strconv.Atoi(valueStr)
+		if err != nil {
+			l.errorf("invalid %s: %v", bound.env, err)
+		} else if *bound.value != 0 && *bound.value < bound.base {
+			l.errorf("%s cannot be lower than the corresponding MIN_IDLE value %d", bound.env, bound.base)
+		}
+	}
+
+	cfg.IdleTuningQuietPeriod = DefaultIdleTuningQuietPeriod
+	if quietPeriodStr := l.get("IDLE_TUNING_QUIET_PERIOD"); quietPeriodStr != "" {
+		cfg.IdleTuningQuietPeriod, err = time.ParseDuration(quietPeriodStr)
+		if err != nil {
+			l.errorf("invalid IDLE_TUNING_QUIET_PERIOD: %v", err)
+		} else if cfg.IdleTuningQuietPeriod <= 0 {
+			l.errorf("IDLE_TUNING_QUIET_PERIOD must be positive")
+		}
+	}
+
+	// Optional overcommit of the runners' capacity, sandbox allocations being frequently far above their usage
+	cfg.CpuOvercommitRatio = DefaultOvercommitRatio
+	if ratioStr := l.get("CPU_OVERCOMMIT_RATIO"); ratioStr != "" {
+		cfg.CpuOvercommitRatio, err = strconv.ParseFloat(ratioStr, 64)
+		if err != nil {
+			l.errorf("invalid CPU_OVERCOMMIT_RATIO: %v", err)
+		} else if cfg.CpuOvercommitRatio <= 0 {
+			l.errorf("CPU_OVERCOMMIT_RATIO must be positive")
+		}
+	}
+	cfg.MemoryOvercommitRatio = DefaultOvercommitRatio
+	if ratioStr := l.get("MEMORY_OVERCOMMIT_RATIO"); ratioStr != "" {
+		cfg.MemoryOvercommitRatio, err = strconv.ParseFloat(ratioStr, 64)
+		if err != nil {
+			l.errorf("invalid MEMORY_OVERCOMMIT_RATIO: %v", err)
+		} else if cfg.MemoryOvercommitRatio <= 0 {
+			l.errorf("MEMORY_OVERCOMMIT_RATIO must be positive")
+		}
+	}
+
+	// Optional actual usage blended with allocations, so runners whose sandboxes are idle count as having capacity left
+	cfg.UsageSource = l.get("USAGE_SOURCE")
+	switch cfg.UsageSource {
+	case "":
+	case UsageSourceMetricsServer:
+	case UsageSourcePrometheus:
+		cfg.UsagePrometheusURL = l.get("USAGE_PROMETHEUS_URL")
+		if cfg.UsagePrometheusURL == "" {
+			l.errorf("USAGE_PROMETHEUS_URL not set")
+		}
+		cfg.UsagePrometheusCpuQuery = l.get("USAGE_PROMETHEUS_CPU_QUERY")
+		if cfg.UsagePrometheusCpuQuery == "" {
+			cfg.UsagePrometheusCpuQuery = DefaultUsagePrometheusCpuQuery
+		}
+		cfg.UsagePrometheusMemoryQuery = l.get("USAGE_PROMETHEUS_MEMORY_QUERY")
+		if cfg.UsagePrometheusMemoryQuery == "" {
+			cfg.UsagePrometheusMemoryQuery = DefaultUsagePrometheusMemoryQuery
+		}
+	default:
+		l.errorf("USAGE_SOURCE must be one of %q or %q", UsageSourceMetricsServer, UsageSourcePrometheus)
+	}
+	if cfg.UsageSource != "" {
+		usageWeightStr := l.get("USAGE_WEIGHT")
+		if usageWeightStr == "" {
+			l.errorf("USAGE_WEIGHT not set")
+		} else if cfg.UsageWeight, err = strconv.ParseFloat(usageWeightStr, 64); err != nil {
+			l.errorf("invalid USAGE_WEIGHT: %v", err)
+		} else if cfg.UsageWeight < 0 || cfg.UsageWeight > 1 {
+			l.errorf("USAGE_WEIGHT must be between 0 and 1")
+		}
+	}
+
+	// Optional pacing of Daytona API calls, disabled when unset
+	if apiRateLimitStr := l.get("DAYTONA_API_RATE_LIMIT"); apiRateLimitStr != "" {
+		cfg.DaytonaAPIRateLimit, err = strconv.ParseFloat(apiRateLimitStr, 64)
+		if err != nil {
+			l.errorf("invalid DAYTONA_API_RATE_LIMIT: %v", err)
+		} else if cfg.DaytonaAPIRateLimit < 0 {
+			l.errorf("DAYTONA_API_RATE_LIMIT cannot be negative")
+		}
+	}
+
+	cfg.DaytonaAPIRateLimitBurst = int(math.Ceil(cfg.DaytonaAPIRateLimit))
+	if apiRateLimitBurstStr := l.get("DAYTONA_API_RATE_LIMIT_BURST"); apiRateLimitBurstStr != "" {
+		cfg.DaytonaAPIRateLimitBurst, err = strconv.Atoi(apiRateLimitBurstStr)
+		if err != nil {
+			l.errorf("invalid DAYTONA_API_RATE_LIMIT_BURST: %v", err)
+		} else if cfg.DaytonaAPIRateLimitBurst < 1 {
+			l.errorf("DAYTONA_API_RATE_LIMIT_BURST must be at least 1")
+		}
+	}
+
+	// Retries of Daytona API calls failing transiently, disabled with DAYTONA_API_RETRY_ATTEMPTS=0
+	cfg.DaytonaAPIRetryAttempts = DefaultDaytonaAPIRetryAttempts
+	if retryAttemptsStr := l.get("DAYTONA_API_RETRY_ATTEMPTS"); retryAttemptsStr != "" {
+		cfg.DaytonaAPIRetryAttempts, err = strconv.Atoi(retryAttemptsStr)
+		if err != nil {
+			l.errorf("invalid DAYTONA_API_RETRY_ATTEMPTS: %v", err)
+		} else if cfg.DaytonaAPIRetryAttempts < 0 {
+			l.errorf("DAYTONA_API_RETRY_ATTEMPTS cannot be negative")
+		}
+	}
+	cfg.DaytonaAPIRetryBaseDelay = DefaultDaytonaAPIRetryBaseDelay
+	if retryBaseDelayStr := l.get("DAYTONA_API_RETRY_BASE_DELAY"); retryBaseDelayStr != "" {
+		cfg.DaytonaAPIRetryBaseDelay, err = time.ParseDuration(retryBaseDelayStr)
+		if err != nil {
+			l.errorf("invalid DAYTONA_API_RETRY_BASE_DELAY: %v", err)
+		} else if cfg.DaytonaAPIRetryBaseDelay <= 0 {
+			l.errorf("DAYTONA_API_RETRY_BASE_DELAY must be positive")
+		}
+	}
+	cfg.DaytonaAPIRetryMaxDelay = DefaultDaytonaAPIRetryMaxDelay
+	if retryMaxDelayStr := l.get("DAYTONA_API_RETRY_MAX_DELAY"); retryMaxDelayStr != "" {
+		cfg.DaytonaAPIRetryMaxDelay, err = time.ParseDuration(retryMaxDelayStr)
+		if err != nil {
+			l.errorf("invalid DAYTONA_API_RETRY_MAX_DELAY: %v", err)
+		} else if cfg.DaytonaAPIRetryMaxDelay < cfg.DaytonaAPIRetryBaseDelay {
+			l.errorf("DAYTONA_API_RETRY_MAX_DELAY cannot be less than DAYTONA_API_RETRY_BASE_DELAY")
+		}
+	}
+	cfg.DaytonaAPIRetryBudget = DefaultDaytonaAPIRetryBudget
+	if retryBudgetStr := l.get("DAYTONA_API_RETRY_BUDGET"); retryBudgetStr != "" {
+		cfg.DaytonaAPIRetryBudget, err = strconv.Atoi(retryBudgetStr)
+		if err != nil {
+			l.errorf("invalid DAYTONA_API_RETRY_BUDGET: %v", err)
+		} else if cfg.DaytonaAPIRetryBudget < 0 {
+			l.errorf("DAYTONA_API_RETRY_BUDGET cannot be negative")
+		}
+	}
+
+	// Optional export of controller loop traces over OTLP/HTTP, with the standard OpenTelemetry settings
+	cfg.TracingEndpoint = l.get("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if endpoint := l.get("OTEL_EXPORTER_OTLP_ENDPOINT"); cfg.TracingEndpoint == "" && endpoint != "" {
+		cfg.TracingEndpoint = strings.TrimSuffix(endpoint, "/") + "/v1/traces"
+	}
+	if cfg.TracingEndpoint != "" {
+		if _, err := url.ParseRequestURI(cfg.TracingEndpoint); err != nil {
+			l.errorf("invalid OTEL_EXPORTER_OTLP_TRACES_ENDPOINT: %v", err)
+		}
+	}
+	if headersStr := l.get("OTEL_EXPORTER_OTLP_HEADERS"); headersStr != "" {
+		cfg.TracingHeaders, err = parseTracingHeaders(headersStr)
+		if err != nil {
+			l.errorf("invalid OTEL_EXPORTER_OTLP_HEADERS: %v", err)
+		}
+	}
+	cfg.TracingServiceName = l.get("OTEL_SERVICE_NAME")
+	if cfg.TracingServiceName == "" {
+		cfg.TracingServiceName = DefaultTracingServiceName
+	}
+
+	// Checks verifying a node holds no data still needed before removing it, all enabled by default
+	cfg.ScaleDownChecks = DefaultScaleDownChecks
+	if checksStr := l.get("SCALE_DOWN_CHECKS"); checksStr != "" {
+		cfg.ScaleDownChecks = nil
+		if checksStr != ScaleDownChecksNone {
+			for _, name := range strings.Split(checksStr, ",") {
+				name = strings.TrimSpace(name)
+				if _, found := scaleDownChecks[name]; !found {
+					l.errorf("invalid SCALE_DOWN_CHECKS: unknown check %q", name)
+				}
+				cfg.ScaleDownChecks = append(cfg.ScaleDownChecks, name)
+			}
+		}
+	}
+
+	// Optional drain of surplus runners before their removal, disabled when no timeout is set
+	if drainTimeoutStr := l.get("DRAIN_TIMEOUT"); drainTimeoutStr != "" {
+		cfg.DrainTimeout, err = time.ParseDuration(drainTimeoutStr)
+		if err != nil {
+			l.errorf("invalid DRAIN_TIMEOUT: %v", err)
+		} else if cfg.DrainTimeout < 0 {
+			l.errorf("DRAIN_TIMEOUT cannot be negative")
+		}
+	}
+
+	// Optional remediation of nodes whose runner never registers, disabled when no timeout is set
+	if nascentTimeoutStr := l.get("NASCENT_NODE_TIMEOUT"); nascentTimeoutStr != "" {
+		cfg.NascentNodeTimeout, err = time.ParseDuration(nascentTimeoutStr)
+		if err != nil {
+			l.errorf("invalid NASCENT_NODE_TIMEOUT: %v", err)
+		} else if cfg.NascentNodeTimeout < 0 {
+			l.errorf("NASCENT_NODE_TIMEOUT cannot be negative")
+		}
+	}
+
+	// Detection of placeholders the cluster cannot add nodes for, disabled with a zero threshold
+	cfg.CapacityExhaustionThreshold = DefaultCapacityExhaustionThreshold
+	if thresholdStr := l.get("CAPACITY_EXHAUSTION_THRESHOLD"); thresholdStr != "" {
+		cfg.CapacityExhaustionThreshold, err = time.ParseDuration(thresholdStr)
+		if err != nil {
+			l.errorf("invalid CAPACITY_EXHAUSTION_THRESHOLD: %v", err)
+		} else if cfg.CapacityExhaustionThreshold < 0 {
+			l.errorf("CAPACITY_EXHAUSTION_THRESHOLD cannot be negative")
+		}
+	}
+
+	// Optional pre-pull of the region's most-used snapshots on new runners, disabled when unset
+	if countStr := l.get("SNAPSHOT_PREPULL_COUNT"); countStr != "" {
+		cfg.SnapshotPrepullCount, err = strconv.Atoi(countStr)
+		if err != nil {
+			l.errorf("invalid SNAPSHOT_PREPULL_COUNT: %v", err)
+		} else if cfg.SnapshotPrepullCount < 0 {
+			l.errorf("SNAPSHOT_PREPULL_COUNT cannot be negative")
+		}
+	}
+	cfg.SnapshotPrepullTimeout = DefaultSnapshotPrepullTimeout
+	if timeoutStr := l.get("SNAPSHOT_PREPULL_TIMEOUT"); timeoutStr != "" {
+		cfg.SnapshotPrepullTimeout, err = time.ParseDuration(timeoutStr)
+		if err != nil {
+			l.errorf("invalid SNAPSHOT_PREPULL_TIMEOUT: %v", err)
+		} else if cfg.SnapshotPrepullTimeout <= 0 {
+			l.errorf("SNAPSHOT_PREPULL_TIMEOUT must be positive")
+		}
+	}
+	// Snapshots are pulled anonymously unless the registry holding them is set
+	cfg.SnapshotPrepullRegistryURL = l.get("SNAPSHOT_PREPULL_REGISTRY_URL")
+	cfg.SnapshotPrepullRegistryUsername = l.get("SNAPSHOT_PREPULL_REGISTRY_USERNAME")
+	cfg.SnapshotPrepullRegistryPassword = l.get("SNAPSHOT_PREPULL_REGISTRY_PASSWORD")
+
+	// Optional deregistration of runners whose node is gone, disabled when unset
+	if cyclesStr := l.get("MISSING_NODE_DEREGISTER_CYCLES"); cyclesStr != "" {
+		cfg.MissingNodeDeregisterCycles, err = strconv.Atoi(cyclesStr)
+		if err != nil {
+			l.errorf("invalid MISSING_NODE_DEREGISTER_CYCLES: %v", err)
+		} else if cfg.MissingNodeDeregisterCycles < 0 {
+			l.errorf("MISSING_NODE_DEREGISTER_CYCLES cannot be negative")
+		}
+	}
+
+	// Optional remediation of runners the Daytona API reports unhealthy, disabled when unset
+	if cyclesStr := l.get("HEALTH_REMEDIATION_UNHEALTHY_CYCLES"); cyclesStr != "" {
+		cfg.HealthRemediationUnhealthyCycles, err = strconv.Atoi(cyclesStr)
+		if err != nil {
+			l.errorf("invalid HEALTH_REMEDIATION_UNHEALTHY_CYCLES: %v", err)
+		} else if cfg.HealthRemediationUnhealthyCycles < 0 {
+			l.errorf("HEALTH_REMEDIATION_UNHEALTHY_CYCLES cannot be negative")
+		}
+	}
+	cfg.HealthRemediationRecoveryTimeout = DefaultHealthRemediationRecoveryTimeout
+	if recoveryTimeoutStr := l.get("HEALTH_REMEDIATION_RECOVERY_TIMEOUT"); recoveryTimeoutStr != "" {
+		cfg.HealthRemediationRecoveryTimeout, err = time.ParseDuration(recoveryTimeoutStr)
+		if err != nil {
+			l.errorf("invalid HEALTH_REMEDIATION_RECOVERY_TIMEOUT: %v", err)
+		} else if cfg.HealthRemediationRecoveryTimeout <= 0 {
+			l.errorf("HEALTH_REMEDIATION_RECOVERY_TIMEOUT must be positive")
+		}
+	}
+
+	// Detection of runners reporting a capacity diverging from their node's, disabled with a zero threshold
+	cfg.CapacityDriftThresholdPercent = DefaultCapacityDriftThresholdPercent
+	if driftThresholdStr := l.get("CAPACITY_DRIFT_THRESHOLD_PERCENT"); driftThresholdStr != "" {
+		cfg.CapacityDriftThresholdPercent, err = strconv.Atoi(driftThresholdStr)
+		if err != nil {
+			l.errorf("invalid CAPACITY_DRIFT_THRESHOLD_PERCENT: %v", err)
+		} else if cfg.CapacityDriftThresholdPercent < 0 {
+			l.errorf("CAPACITY_DRIFT_THRESHOLD_PERCENT cannot be negative")
+		}
+	}
+	cfg.CapacityDriftSource = CapacitySourceRunner
+	if driftSourceStr := l.get("CAPACITY_DRIFT_SOURCE"); driftSourceStr != "" {
+		if !isCapacitySource(driftSourceStr) {
+			l.errorf("CAPACITY_DRIFT_SOURCE must be one of %q, %q or %q", CapacitySourceRunner, CapacitySourceNode, CapacitySourceLower)
+		}
+		cfg.CapacityDriftSource = driftSourceStr
+	}
+
+	// Optional rollout of the runner version of the region's pool configuration
+	if rolloutEnabledStr := l.get("RUNNER_ROLLOUT_ENABLED"); rolloutEnabledStr != "" {
+		cfg.RolloutEnabled, err = strconv.ParseBool(rolloutEnabledStr)
+		if err != nil {
+			l.errorf("invalid RUNNER_ROLLOUT_ENABLED: %v", err)
+		}
+	}
+	cfg.RolloutMaxSurge = DefaultRolloutMaxSurge
+	if maxSurgeStr := l.get("RUNNER_ROLLOUT_MAX_SURGE"); maxSurgeStr != "" {
+		cfg.RolloutMaxSurge, err = strconv.Atoi(maxSurgeStr)
+		if err != nil {
+			l.errorf("invalid RUNNER_ROLLOUT_MAX_SURGE: %v", err)
+		} else if cfg.RolloutMaxSurge < 1 {
+			l.errorf("RUNNER_ROLLOUT_MAX_SURGE must be at least 1")
+		}
+	}
+
+	// Optional node prices, the most expensive surplus nodes are removed first and the fleet cost is reported
+	if pricesStr := l.get("NODE_HOURLY_PRICES"); pricesStr != "" {
+		cfg.NodeHourlyPrices, err = parseNodeHourlyPrices(pricesStr)
+		if err != nil {
+			l.errorf("invalid NODE_HOURLY_PRICES: %v", err)
+		}
+	}
+	cfg.NodeHourlyPriceLabel = l.get("NODE_HOURLY_PRICE_LABEL")
+
+	// Optional weighting of the runners to remove first on scale-down, replacing the cost ordering when set
+	if weightsStr := l.get("SCALE_DOWN_WEIGHTS"); weightsStr != "" {
+		cfg.ScaleDownWeights, err = parseScaleDownWeights(weightsStr)
+		if err != nil {
+			l.errorf("invalid SCALE_DOWN_WEIGHTS: %v", err)
+		} else if cfg.ScaleDownWeights[ScaleDownFactorCost] > 0 && !isCostAware(cfg) {
+			l.errorf("SCALE_DOWN_WEIGHTS weighs cost, which requires NODE_HOURLY_PRICES or NODE_HOURLY_PRICE_LABEL")
+		}
+	}
+
+	// Reports posted by the capacity reporters and the proxies are rejected when unset
+	cfg.NodeReportToken = l.get("NODE_REPORT_TOKEN")
+	cfg.TrafficReportToken = l.get("TRAFFIC_REPORT_TOKEN")
+
+	// Optional eviction notices sent to the proxies, disabled when no proxy URL is set
+	for _, proxyURL := range strings.Split(l.get("EVICTION_NOTICE_PROXY_URLS"), ",") {
+		proxyURL = strings.TrimSuffix(strings.TrimSpace(proxyURL), "/")
+		if proxyURL != "" {
+			cfg.EvictionNoticeProxyURLs = append(cfg.EvictionNoticeProxyURLs, proxyURL)
+		}
+	}
+	cfg.EvictionNoticeToken = l.get("EVICTION_NOTICE_TOKEN")
+	cfg.EvictionNoticeLeadTime = DefaultEvictionNoticeLeadTime
+	if leadTimeStr := l.get("EVICTION_NOTICE_LEAD_TIME"); leadTimeStr != "" {
+		cfg.EvictionNoticeLeadTime, err = time.ParseDuration(leadTimeStr)
+		if err != nil {
+			l.errorf("invalid EVICTION_NOTICE_LEAD_TIME: %v", err)
+		} else if cfg.EvictionNoticeLeadTime < 0 {
+			l.errorf("EVICTION_NOTICE_LEAD_TIME cannot be negative")
+		}
+	}
+
+	// Optional webhooks notified of node provisioning, runner registration and node teardown, e.g. for inventory systems
+	for _, webhookURL := range strings.Split(l.get("LIFECYCLE_WEBHOOK_URLS"), ",") {
+		webhookURL = strings.TrimSpace(webhookURL)
+		if webhookURL != "" {
+			cfg.LifecycleWebhookURLs = append(cfg.LifecycleWebhookURLs, webhookURL)
+		}
+	}
+	cfg.LifecycleWebhookSecret = l.get("LIFECYCLE_WEBHOOK_SECRET")
+	cfg.LifecycleWebhookMaxAttempts = DefaultLifecycleWebhookMaxAttempts
+	if maxAttemptsStr := l.get("LIFECYCLE_WEBHOOK_MAX_ATTEMPTS"); maxAttemptsStr != "" {
+		cfg.LifecycleWebhookMaxAttempts, err = strconv.Atoi(maxAttemptsStr)
+		if err != nil {
+			l.errorf("invalid LIFECYCLE_WEBHOOK_MAX_ATTEMPTS: %v", err)
+		} else if cfg.LifecycleWebhookMaxAttempts < 1 {
+			l.errorf("LIFECYCLE_WEBHOOK_MAX_ATTEMPTS must be at least 1")
+		}
+	}
+
+	// Optional webhook notified of scale-ups and anomalies, e.g. a Slack incoming webhook
+	cfg.NotificationWebhookURL = l.get("NOTIFICATION_WEBHOOK_URL")
+	cfg.NotificationMinInterval = DefaultNotificationMinInterval
+	if minIntervalStr := l.get("NOTIFICATION_MIN_INTERVAL"); minIntervalStr != "" {
+		cfg.NotificationMinInterval, err = time.ParseDuration(minIntervalStr)
+		if err != nil {
+			l.errorf("invalid NOTIFICATION_MIN_INTERVAL: %v", err)
+		} else if cfg.NotificationMinInterval < 0 {
+			l.errorf("NOTIFICATION_MIN_INTERVAL cannot be negative")
+		}
+	}
+
+	// Optional polling of the control plane for emergency directives, which can also be pushed to the admin endpoint
+	cfg.DirectivesURL = l.get("DIRECTIVES_URL")
+	cfg.DirectivesToken = l.get("DIRECTIVES_TOKEN")
+	cfg.DirectivesAuditLogFile = l.get("DIRECTIVES_AUDIT_LOG_FILE")
+	cfg.DirectivesPollInterval = directive.DefaultPollInterval
+	if pollIntervalStr := l.get("DIRECTIVES_POLL_INTERVAL"); pollIntervalStr != "" {
+		cfg.DirectivesPollInterval, err = time.ParseDuration(pollIntervalStr)
+		if err != nil {
+			l.errorf("invalid DIRECTIVES_POLL_INTERVAL: %v", err)
+		} else if cfg.DirectivesPollInterval <= 0 {
+			l.errorf("DIRECTIVES_POLL_INTERVAL must be positive")
+		}
+	}
+
+	// Controller loop cadence and the minimum time between scale operations, cooldowns are disabled by default
+	cfg.CheckInterval = DefaultCheckInterval
+	if checkIntervalStr := l.get("CHECK_INTERVAL"); checkIntervalStr != "" {
+		cfg.CheckInterval, err = time.ParseDuration(checkIntervalStr)
+		if err != nil {
+			l.errorf("invalid CHECK_INTERVAL: %v", err)
+		} else if cfg.CheckInterval < MinCycleInterval {
+			l.errorf("CHECK_INTERVAL must be at least %s", MinCycleInterval)
+		}
+	}
+	for _, cooldown := range []struct {
+		env    string
+		target *time.Duration
+	}{
+		{"SCALE_UP_COOLDOWN", &cfg.ScaleUpCooldown},
+		{"SCALE_DOWN_COOLDOWN", &cfg.ScaleDownCooldown},
+	} {
+		cooldownStr := l.get(cooldown.env)
+		if cooldownStr == "" {
+			continue
+		}
+		*cooldown.target, err = time.ParseDuration(cooldownStr)
+		if err != nil {
+			l.errorf("invalid %s: %v", cooldown.env, err)
+		} else if *cooldown.target < 0 {
+			l.errorf("%s cannot be negative", cooldown.env)
+		}
+	}
+
+	// Optional local history of the controller cycles, replayed by the what-if endpoint
+	cfg.ScalingHistoryFile = l.get("SCALING_HISTORY_FILE")
+	cfg.ScalingHistoryRetention = DefaultScalingHistoryRetention
+	if retentionStr := l.get("SCALING_HISTORY_RETENTION"); retentionStr != "" {
+		cfg.ScalingHistoryRetention, err = time.ParseDuration(retentionStr)
+		if err != nil {
+			l.errorf("invalid SCALING_HISTORY_RETENTION: %v", err)
+		} else if cfg.ScalingHistoryRetention <= 0 {
+			l.errorf("SCALING_HISTORY_RETENTION must be positive")
+		}
+	}
+
+	// Optional local history of the scaling decisions, queried by the decisions endpoint
+	cfg.DecisionHistoryFile = l.get("DECISION_HISTORY_FILE")
+	cfg.DecisionHistoryRetention = DefaultDecisionHistoryRetention
+	if retentionStr := l.get("DECISION_HISTORY_RETENTION"); retentionStr != "" {
+		cfg.DecisionHistoryRetention, err = time.ParseDuration(retentionStr)
+		if err != nil {
+			l.errorf("invalid DECISION_HISTORY_RETENTION: %v", err)
+		} else if cfg.DecisionHistoryRetention <= 0 {
+			l.errorf("DECISION_HISTORY_RETENTION must be positive")
+		}
+	}
+
+	// Optional cron schedules overriding the idle thresholds, e.g. warming up before business hours. The active entry
+	// replaces the configured and the tuned values.
+	if schedulesStr := l.get("SCALING_SCHEDULES"); schedulesStr != "" {
+		cfg.ScalingSchedules, err = parseScalingSchedules(schedulesStr)
+		if err != nil {
+			l.errorf("invalid SCALING_SCHEDULES: %v", err)
+		}
+	}
+	cfg.ScalingScheduleLocation = time.UTC
+	if timezone := l.get("SCALING_SCHEDULE_TIMEZONE"); timezone != "" {
+		cfg.ScalingScheduleLocation, err = time.LoadLocation(timezone)
+		if err != nil {
+			l.errorf("invalid SCALING_SCHEDULE_TIMEZONE: %v", err)
+		}
+	}
+
+	// Optional cron windows disallowing scale-down ahead of predictable load, e.g. nightly CI surges. They are
+	// evaluated in SCALING_SCHEDULE_TIMEZONE.
+	if windowsStr := l.get("SCALE_DOWN_BLACKOUT_WINDOWS"); windowsStr != "" {
+		cfg.ScaleDownBlackoutWindows, err = parseBlackoutWindows(windowsStr)
+		if err != nil {
+			l.errorf("invalid SCALE_DOWN_BLACKOUT_WINDOWS: %v", err)
+		}
+	}
+
+	// Optional pre-provisioning ahead of recurring demand peaks, forecast from the scaling history
+	if predictiveStr := l.get("PREDICTIVE_SCALING_ENABLED"); predictiveStr != "" {
+		cfg.PredictiveScalingEnabled, err = strconv.ParseBool(predictiveStr)
+		if err != nil {
+			l.errorf("invalid PREDICTIVE_SCALING_ENABLED: %v", err)
+		}
+	}
+	if cfg.PredictiveScalingEnabled {
+		if cfg.ScalingHistoryFile == "" {
+			l.errorf("PREDICTIVE_SCALING_ENABLED requires SCALING_HISTORY_FILE")
+		}
+
+		cfg.PredictiveSeasonality = l.get("PREDICTIVE_SEASONALITY")
+		switch cfg.PredictiveSeasonality {
+		case "":
+			cfg.PredictiveSeasonality = PredictiveSeasonalityDaily
+		case PredictiveSeasonalityDaily, PredictiveSeasonalityWeekly:
+		default:
+			l.errorf("PREDICTIVE_SEASONALITY must be one of %q or %q", PredictiveSeasonalityDaily, PredictiveSeasonalityWeekly)
+		}
+
+		cfg.PredictiveLeadTime = DefaultPredictiveLeadTime
+		cfg.PredictiveWindow = DefaultPredictiveWindow
+		for _, setting := range []struct {
+			env   string
+			value *time.Duration
+		}{
+			{"PREDICTIVE_LEAD_TIME", &cfg.PredictiveLeadTime},
+			{"PREDICTIVE_WINDOW", &cfg.PredictiveWindow},
+		} {
+			valueStr := l.get(setting.env)
+			if valueStr == "" {
+				continue
+			}
+			*setting.value, err = time.ParseDuration(valueStr)
+			if err != nil {
+				l.errorf("invalid %s: %v", setting.env, err)
+			} else if *setting.value <= 0 {
+				l.errorf("%s must be positive", setting.env)
+			}
+		}
+
+		cfg.PredictivePeriods = DefaultPredictivePeriods
+		if periodsStr := l.get("PREDICTIVE_PERIODS"); periodsStr != "" {
+			cfg.PredictivePeriods, err = strconv.Atoi(periodsStr)
+			if err != nil {
+				l.errorf("invalid PREDICTIVE_PERIODS: %v", err)
+			} else if cfg.PredictivePeriods <= 0 {
+				l.errorf("PREDICTIVE_PERIODS must be positive")
+			}
+		}
+
+		if lookback := predictiveLookback(cfg); cfg.ScalingHistoryRetention < lookback {
+			l.errorf("SCALING_HISTORY_RETENTION must be at least %s to forecast from %d %s periods", lookback, cfg.PredictivePeriods, cfg.PredictiveSeasonality)
+		}
+	}
+
+	// Optional scale-up ahead of a demand surge, extrapolating the growth between cycles over the lead time
+	if velocityStr := l.get("VELOCITY_SCALING_ENABLED"); velocityStr != "" {
+		cfg.VelocityScalingEnabled, err = strconv.ParseBool(velocityStr)
+		if err != nil {
+			l.errorf("invalid VELOCITY_SCALING_ENABLED: %v", err)
+		}
+	}
+	cfg.VelocityLeadTime = DefaultVelocityLeadTime
+	if leadTimeStr := l.get("VELOCITY_LEAD_TIME"); leadTimeStr != "" {
+		cfg.VelocityLeadTime, err = time.ParseDuration(leadTimeStr)
+		if err != nil {
+			l.errorf("invalid VELOCITY_LEAD_TIME: %v", err)
+		} else if cfg.VelocityLeadTime <= 0 {
+			l.errorf("VELOCITY_LEAD_TIME must be positive")
+		}
+	}
+
+	cfg.TLSCertFile = l.get("TLS_CERT_FILE")
+	cfg.TLSKeyFile = l.get("TLS_KEY_FILE")
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		l.errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	cfg.AdminAuth = adminauth.Config{
+		Token:        l.get("ADMIN_AUTH_TOKEN"),
+		ClientCAFile: l.get("ADMIN_AUTH_CLIENT_CA_FILE"),
+		JWKSUrl:      l.get("ADMIN_AUTH_JWKS_URL"),
+		Issuer:       l.get("ADMIN_AUTH_JWT_ISSUER"),
+		Audience:     l.get("ADMIN_AUTH_JWT_AUDIENCE"),
+	}
+	if cfg.AdminAuth.ClientCAFile != "" && cfg.TLSCertFile == "" {
+		l.errorf("ADMIN_AUTH_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+
+	cfg.ConfigDriftCheckInterval = DefaultConfigDriftCheckInterval
+	if driftCheckIntervalStr := l.get("CONFIG_DRIFT_CHECK_INTERVAL"); driftCheckIntervalStr != "" {
+		cfg.ConfigDriftCheckInterval, err = time.ParseDuration(driftCheckIntervalStr)
+		if err != nil {
+			l.errorf("invalid CONFIG_DRIFT_CHECK_INTERVAL: %v", err)
+		} else if cfg.ConfigDriftCheckInterval < 0 {
+			l.errorf("CONFIG_DRIFT_CHECK_INTERVAL cannot be negative")
+		}
+	}
+
+	if driftAdoptStr := l.get("CONFIG_DRIFT_ADOPT"); driftAdoptStr != "" {
+		cfg.ConfigDriftAdopt, err = strconv.ParseBool(driftAdoptStr)
+		if err != nil {
+			l.errorf("invalid CONFIG_DRIFT_ADOPT: %v", err)
+		}
+	}
+
+	cfg.ScalingPolicyPluginPath = l.get("SCALING_POLICY_PLUGIN_PATH")
+	cfg.ScalingPolicyGRPCAddress = l.get("SCALING_POLICY_GRPC_ADDRESS")
+	if cfg.ScalingPolicyPluginPath != "" && cfg.ScalingPolicyGRPCAddress != "" {
+		l.errorf("SCALING_POLICY_PLUGIN_PATH and SCALING_POLICY_GRPC_ADDRESS are mutually exclusive")
+	}
+	// The node delta of a custom policy is not trusted, its scale-ups are capped by the pool size
+	if (cfg.ScalingPolicyPluginPath != "" || cfg.ScalingPolicyGRPCAddress != "") && cfg.MaxNodes == 0 {
+		l.errorf("a custom scaling policy requires MAX_NODES to bound its scale-ups")
+	}
+
+	cfg.ScalingPolicyTimeout = DefaultScalingPolicyTimeout
+	if scalingPolicyTimeoutStr := l.get("SCALING_POLICY_TIMEOUT"); scalingPolicyTimeoutStr != "" {
+		cfg.ScalingPolicyTimeout, err = time.ParseDuration(scalingPolicyTimeoutStr)
+		if err != nil {
+			l.errorf("invalid SCALING_POLICY_TIMEOUT: %v", err)
+		} else if cfg.ScalingPolicyTimeout <= 0 {
+			l.errorf("SCALING_POLICY_TIMEOUT must be positive")
+		}
+	}
+
+	// Optional hibernation of scaled-down nodes instead of removal, disabled when no backend is set
+	cfg.HibernationBackend = l.get("HIBERNATION_BACKEND")
+	switch cfg.HibernationBackend {
+	case "":
+	case HibernationBackendAWS:
+		if standbyStr := l.get("HIBERNATION_AWS_ASG_STANDBY"); standbyStr != "" {
+			cfg.HibernationAWSStandby, err = strconv.ParseBool(standbyStr)
+			if err != nil {
+				l.errorf("invalid HIBERNATION_AWS_ASG_STANDBY: %v", err)
+			}
+		}
+	case HibernationBackendWebhook:
+		cfg.HibernationWebhookURL = l.get("HIBERNATION_WEBHOOK_URL")
+		if cfg.HibernationWebhookURL == "" {
+			l.errorf("HIBERNATION_WEBHOOK_URL not set")
+		}
+		cfg.HibernationWebhookToken = l.get("HIBERNATION_WEBHOOK_TOKEN")
+	default:
+		l.errorf("HIBERNATION_BACKEND must be one of %q or %q", HibernationBackendAWS, HibernationBackendWebhook)
+	}
+
+	if cfg.HibernationBackend != "" {
+		hibernationMaxNodesStr := l.get("HIBERNATION_MAX_NODES")
+		if hibernationMaxNodesStr == "" {
+			l.errorf("HIBERNATION_MAX_NODES not set")
+		}
+		cfg.HibernationMaxNodes, err = strconv.Atoi(hibernationMaxNodesStr)
+		if err != nil {
+			l.errorf("invalid HIBERNATION_MAX_NODES: %v", err)
+		} else if cfg.HibernationMaxNodes < 0 {
+			l.errorf("HIBERNATION_MAX_NODES cannot be negative")
+		}
+	}
+
+	// Optional protection of runners serving heavy preview traffic from scale-down, disabled when unset
+	if highTrafficStr := l.get("HIGH_TRAFFIC_BYTES_PER_SECOND"); highTrafficStr != "" {
+		cfg.HighTrafficBytesPerSecond, err = strconv.ParseFloat(highTrafficStr, 64)
+		if err != nil {
+			l.errorf("invalid HIGH_TRAFFIC_BYTES_PER_SECOND: %v", err)
+		} else if cfg.HighTrafficBytesPerSecond < 0 {
+			l.errorf("HIGH_TRAFFIC_BYTES_PER_SECOND cannot be negative")
+		}
+	}
+
+	// Optional scale-up for the resources requested by queued sandboxes, before they land on runners
+	if backlogScalingStr := l.get("BACKLOG_SCALING_ENABLED"); backlogScalingStr != "" {
+		cfg.BacklogScalingEnabled, err = strconv.ParseBool(backlogScalingStr)
+		if err != nil {
+			l.errorf("invalid BACKLOG_SCALING_ENABLED: %v", err)
+		}
+	}
+
+	if quotaEnforcementEnabledStr := l.get("QUOTA_ENFORCEMENT_ENABLED"); quotaEnforcementEnabledStr != "" {
+		cfg.QuotaEnforcementEnabled, err = strconv.ParseBool(quotaEnforcementEnabledStr)
+		if err != nil {
+			l.errorf("invalid QUOTA_ENFORCEMENT_ENABLED: %v", err)
+		}
+	}
+
+	if maxConcurrentSandboxesStr := l.get("QUOTA_DEFAULT_MAX_CONCURRENT_SANDBOXES"); maxConcurrentSandboxesStr != "" {
+		cfg.QuotaDefaultMaxConcurrentSandboxes, err = strconv.Atoi(maxConcurrentSandboxesStr)
+		if err != nil {
+			l.errorf("invalid QUOTA_DEFAULT_MAX_CONCURRENT_SANDBOXES: %v", err)
+		} else if cfg.QuotaDefaultMaxConcurrentSandboxes < 0 {
+			l.errorf("QUOTA_DEFAULT_MAX_CONCURRENT_SANDBOXES cannot be negative")
+		}
+	}
+
+	// Optional idle capacity reserved for organizations, read from the region every cycle
+	if capacityReservationsStr := l.get("CAPACITY_RESERVATIONS_ENABLED"); capacityReservationsStr != "" {
+		cfg.CapacityReservationsEnabled, err = strconv.ParseBool(capacityReservationsStr)
+		if err != nil {
+			l.errorf("invalid CAPACITY_RESERVATIONS_ENABLED: %v", err)
+		}
+	}
+
+	// Optional per-node log forwarding, disabled when no sink is configured
+	cfg.LogForwardingSink = l.get("LOG_FORWARDING_SINK")
+	cfg.LogForwarderImage = l.get("LOG_FORWARDER_IMAGE")
+	if cfg.LogForwarderImage == "" {
+		cfg.LogForwarderImage = DefaultLogForwarderImage
+	}
+	cfg.LogForwardingRunnerLogPath = l.get("LOG_FORWARDING_RUNNER_LOG_PATH")
+	switch cfg.LogForwardingSink {
+	case "":
+	case LogForwardingSinkLoki:
+		cfg.LogForwardingLokiURL = l.get("LOG_FORWARDING_LOKI_URL")
+		if cfg.LogForwardingLokiURL == "" {
+			l.errorf("LOG_FORWARDING_LOKI_URL not set")
+		}
+	case LogForwardingSinkS3:
+		cfg.LogForwardingS3Bucket = l.get("LOG_FORWARDING_S3_BUCKET")
+		if cfg.LogForwardingS3Bucket == "" {
+			l.errorf("LOG_FORWARDING_S3_BUCKET not set")
+		}
+		cfg.LogForwardingS3Region = l.get("LOG_FORWARDING_S3_REGION")
+		if cfg.LogForwardingS3Region == "" {
+			l.errorf("LOG_FORWARDING_S3_REGION not set")
+		}
+		cfg.LogForwardingS3Secret = l.get("LOG_FORWARDING_S3_SECRET")
+	default:
+		l.errorf("LOG_FORWARDING_SINK must be one of %q or %q", LogForwardingSinkLoki, LogForwardingSinkS3)
+	}
+
+	// Optional validating webhook blocking manual deletion and cordoning of pool nodes and placeholder pods, served
+	// on the API port through the given service
+	cfg.AdmissionWebhookServiceName = l.get("ADMISSION_WEBHOOK_SERVICE_NAME")
+	if cfg.AdmissionWebhookServiceName != "" {
+		if cfg.TLSCertFile == "" {
+			l.errorf("ADMISSION_WEBHOOK_SERVICE_NAME requires TLS_CERT_FILE and TLS_KEY_FILE")
+		}
+		cfg.AdmissionWebhookCAFile = l.get("ADMISSION_WEBHOOK_CA_FILE")
+		if cfg.AdmissionWebhookCAFile == "" {
+			l.errorf("ADMISSION_WEBHOOK_CA_FILE not set")
+		}
+		cfg.AdmissionWebhookServiceNamespace = l.get("ADMISSION_WEBHOOK_SERVICE_NAMESPACE")
+		if cfg.AdmissionWebhookServiceNamespace == "" {
+			cfg.AdmissionWebhookServiceNamespace = cfg.ProviderNamespace
+		}
+		cfg.AdmissionWebhookServicePort = DefaultAdmissionWebhookServicePort
+		if portStr := l.get("ADMISSION_WEBHOOK_SERVICE_PORT"); portStr != "" {
+			cfg.AdmissionWebhookServicePort, err = strconv.Atoi(portStr)
+			if err != nil || cfg.AdmissionWebhookServicePort < 1 || cfg.AdmissionWebhookServicePort > 65535 {
+				l.errorf("invalid ADMISSION_WEBHOOK_SERVICE_PORT: %s", portStr)
+			}
+		}
+		prefixesStr, found := l.lookup("ADMISSION_WEBHOOK_EXEMPT_USER_PREFIXES")
+		if !found {
+			prefixesStr = DefaultAdmissionExemptUserPrefixes
+		}
+		for _, prefix := range strings.Split(prefixesStr, ",") {
+			if prefix = strings.TrimSpace(prefix); prefix != "" {
+				cfg.AdmissionExemptUserPrefixes = append(cfg.AdmissionExemptUserPrefixes, prefix)
+			}
+		}
+	}
+
+	// Optional PodDisruptionBudget refusing the eviction of placeholder pods by node drains and cluster upgrades
+	if disruptionBudgetStr := l.get("PLACEHOLDER_DISRUPTION_BUDGET_ENABLED"); disruptionBudgetStr != "" {
+		cfg.PlaceholderDisruptionBudgetEnabled, err = strconv.ParseBool(disruptionBudgetStr)
+		if err != nil {
+			l.errorf("invalid PLACEHOLDER_DISRUPTION_BUDGET_ENABLED: %v", err)
+		}
+	}
+
+	// Optional grace period between scheduling a node for removal and removing it
+	if gracePeriodStr := l.get("SCALE_DOWN_GRACE_PERIOD"); gracePeriodStr != "" {
+		cfg.ScaleDownGracePeriod, err = time.ParseDuration(gracePeriodStr)
+		if err != nil {
+			l.errorf("invalid SCALE_DOWN_GRACE_PERIOD: %v", err)
+		} else if cfg.ScaleDownGracePeriod < 0 {
+			l.errorf("SCALE_DOWN_GRACE_PERIOD cannot be negative")
+		}
+	}
+
+	// Degraded mode after repeated control-plane errors, disabled with zero cycles
+	cfg.CircuitBreakerErrorCycles = DefaultCircuitBreakerErrorCycles
+	if errorCyclesStr := l.get("CIRCUIT_BREAKER_ERROR_CYCLES"); errorCyclesStr != "" {
+		cfg.CircuitBreakerErrorCycles, err = strconv.Atoi(errorCyclesStr)
+		if err != nil {
+			l.errorf("invalid CIRCUIT_BREAKER_ERROR_CYCLES: %v", err)
+		} else if cfg.CircuitBreakerErrorCycles < 0 {
+			l.errorf("CIRCUIT_BREAKER_ERROR_CYCLES cannot be negative")
+		}
+	}
+
+	// Optional profiling of the runner-manager process on the admin port
+	if pprofStr := l.get("PPROF_ENABLED"); pprofStr != "" {
+		cfg.PprofEnabled, err = strconv.ParseBool(pprofStr)
+		if err != nil {
+			l.errorf("invalid PPROF_ENABLED: %v", err)
+		}
+	}
+	if runtimeMetricsStr := l.get("RUNTIME_METRICS_ENABLED"); runtimeMetricsStr != "" {
+		cfg.RuntimeMetricsEnabled, err = strconv.ParseBool(runtimeMetricsStr)
+		if err != nil {
+			l.errorf("invalid RUNTIME_METRICS_ENABLED: %v", err)
+		}
+	}
+
+	cfg.LogLevel = l.get("LOG_LEVEL")
+	switch cfg.LogLevel {
+	case "":
+		cfg.LogLevel = LogLevelInfo
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+	default:
+		l.errorf("LOG_LEVEL must be one of %q, %q, %q or %q", LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError)
+	}
+
+	cfg.LogFormat = l.get("LOG_FORMAT")
+	switch cfg.LogFormat {
+	case "":
+		cfg.LogFormat = LogFormatText
+	case LogFormatText, LogFormatJSON:
+	default:
+		l.errorf("LOG_FORMAT must be one of %q or %q", LogFormatText, LogFormatJSON)
+	}
+
+	// Operating system of the pool's runners, selecting their nodes and the capacity semantics applied to them
+	cfg.PoolOS = l.get("POOL_OS")
+	if cfg.PoolOS == "" {
+		cfg.PoolOS = OSLinux
+	} else if !isSupportedOS(cfg.PoolOS) {
+		l.errorf("POOL_OS must be one of %q, %q or %q", OSLinux, OSWindows, OSDarwin)
+	}
+
+	// Optional placeholder pod template replacing the built-in spec
+	cfg.PlaceholderPodTemplate, err = loadPlaceholderPodTemplate(l, cfg)
+	if err != nil {
+		l.errorf("%v", err)
+	}
+
+	cfg.PlaceholderImage = l.get("PLACEHOLDER_IMAGE")
+	if cfg.PlaceholderImage == "" {
+		cfg.PlaceholderImage = defaultPlaceholderImages[cfg.PoolOS]
+	}
+	if cfg.PlaceholderImage == "" && cfg.PlaceholderPodTemplate == nil {
+		l.errorf("PLACEHOLDER_IMAGE or a placeholder pod template must be set for %s pools", cfg.PoolOS)
+	}
+
+	// Optional settings of the built-in placeholder spec for clusters with their own taints, priorities and registries
+	for _, secret := range strings.Split(l.get("PLACEHOLDER_IMAGE_PULL_SECRETS"), ",") {
+		if secret = strings.TrimSpace(secret); secret != "" {
+			cfg.PlaceholderImagePullSecrets = append(cfg.PlaceholderImagePullSecrets, secret)
+		}
+	}
+	if tolerationsStr := l.get("PLACEHOLDER_TOLERATIONS"); tolerationsStr != "" {
+		cfg.PlaceholderTolerations, err = parsePlaceholderTolerations(tolerationsStr)
+		if err != nil {
+			l.errorf("invalid PLACEHOLDER_TOLERATIONS: %v", err)
+		}
+	}
+	cfg.PlaceholderPriorityClassName = l.get("PLACEHOLDER_PRIORITY_CLASS_NAME")
+
+	// Optional placeholder requests, so the nodes added are sized for the sandboxes rather than for a pause pod
+	if cpuRequestStr := l.get("PLACEHOLDER_CPU_REQUEST"); cpuRequestStr != "" {
+		cfg.PlaceholderCpuRequest, cfg.PlaceholderCpuRequestAuto, err = parsePlaceholderRequest(cpuRequestStr)
+		if err != nil {
+			l.errorf("invalid PLACEHOLDER_CPU_REQUEST: %v", err)
+		}
+	}
+	if memoryRequestStr := l.get("PLACEHOLDER_MEMORY_REQUEST"); memoryRequestStr != "" {
+		cfg.PlaceholderMemoryRequest, cfg.PlaceholderMemoryRequestAuto, err = parsePlaceholderRequest(memoryRequestStr)
+		if err != nil {
+			l.errorf("invalid PLACEHOLDER_MEMORY_REQUEST: %v", err)
+		}
+	}
+
+	// Platform hosting the runner nodes, Kubernetes unless the runners run on Nomad clients
+	cfg.ClusterBackend = l.get("CLUSTER_BACKEND")
+	switch cfg.ClusterBackend {
+	case "":
+		cfg.ClusterBackend = ClusterBackendKubernetes
+	case ClusterBackendKubernetes:
+	case ClusterBackendNomad:
+		cfg.NomadAddr = strings.TrimSuffix(l.get("NOMAD_ADDR"), "/")
+		if cfg.NomadAddr == "" {
+			cfg.NomadAddr = DefaultNomadAddr
+		}
+		cfg.NomadToken = l.get("NOMAD_TOKEN")
+		cfg.NomadNamespace = l.get("NOMAD_NAMESPACE")
+		cfg.NomadRegion = l.get("NOMAD_REGION")
+		cfg.NomadNodeClass = l.get("NOMAD_NODE_CLASS")
+		if cfg.NomadNodeClass == "" {
+			l.errorf("NOMAD_NODE_CLASS not set")
+		}
+		cfg.NomadDatacenters = []string{"*"}
+		if datacentersStr := l.get("NOMAD_DATACENTERS"); datacentersStr != "" {
+			cfg.NomadDatacenters = nil
+			for _, datacenter := range strings.Split(datacentersStr, ",") {
+				if datacenter = strings.TrimSpace(datacenter); datacenter != "" {
+					cfg.NomadDatacenters = append(cfg.NomadDatacenters, datacenter)
+				}
+			}
+		}
+
+		if cfg.PlaceholderPodTemplate != nil {
+			l.errorf("placeholder pod templates are not supported with the %s cluster backend", ClusterBackendNomad)
+		}
+		if cfg.LogForwardingSink != "" {
+			l.errorf("LOG_FORWARDING_SINK is not supported with the %s cluster backend", ClusterBackendNomad)
+		}
+		if cfg.AdmissionWebhookServiceName != "" {
+			l.errorf("ADMISSION_WEBHOOK_SERVICE_NAME is not supported with the %s cluster backend", ClusterBackendNomad)
+		}
+		if len(cfg.PlaceholderImagePullSecrets) > 0 || len(cfg.PlaceholderTolerations) > 0 || cfg.PlaceholderPriorityClassName != "" {
+			l.errorf("PLACEHOLDER_IMAGE_PULL_SECRETS, PLACEHOLDER_TOLERATIONS and PLACEHOLDER_PRIORITY_CLASS_NAME are not supported with the %s cluster backend", ClusterBackendNomad)
+		}
+		if l.get("PLACEHOLDER_CPU_REQUEST") != "" || l.get("PLACEHOLDER_MEMORY_REQUEST") != "" {
+			l.errorf("PLACEHOLDER_CPU_REQUEST and PLACEHOLDER_MEMORY_REQUEST are not supported with the %s cluster backend", ClusterBackendNomad)
+		}
+		if cfg.PlaceholderDisruptionBudgetEnabled {
+			l.errorf("PLACEHOLDER_DISRUPTION_BUDGET_ENABLED is not supported with the %s cluster backend", ClusterBackendNomad)
+		}
+	default:
+		l.errorf("CLUSTER_BACKEND must be one of %q or %q", ClusterBackendKubernetes, ClusterBackendNomad)
+	}
+
+	// Node label and taint of the pool's nodes, the defaults of the pools defined in RUNNER_POOLS
+	cfg.PoolName = DefaultPoolName
+	cfg.NodeSelectorKey = l.get("NODE_SELECTOR_KEY")
+	if cfg.NodeSelectorKey == "" {
+		cfg.NodeSelectorKey = DefaultNodeSelectorKey
+	}
+	cfg.TaintKey = l.get("TAINT_KEY")
+	if cfg.TaintKey == "" {
+		cfg.TaintKey = DefaultTaintKey
+	}
+	cfg.TaintEffect = corev1.TaintEffectNoExecute
+	if taintEffectStr := l.get("TAINT_EFFECT"); taintEffectStr != "" {
+		cfg.TaintEffect, err = parseTaintEffect(taintEffectStr)
+		if err != nil {
+			l.errorf("invalid TAINT_EFFECT: %v", err)
+		}
+	}
+
+	// Optional spot capacity, scaled first with a fallback to on-demand nodes when spot nodes cannot be provisioned
+	if spotSelectorStr := l.get("SPOT_NODE_SELECTOR"); spotSelectorStr != "" {
+		if cfg.ClusterBackend != ClusterBackendKubernetes {
+			l.errorf("SPOT_NODE_SELECTOR is only supported with the %s cluster backend", ClusterBackendKubernetes)
+		}
+		cfg.SpotNodeSelector, err = parseNodeSelector(spotSelectorStr)
+		if err != nil {
+			l.errorf("invalid SPOT_NODE_SELECTOR: %v", err)
+		}
+		onDemandSelectorStr := l.get("ON_DEMAND_NODE_SELECTOR")
+		if onDemandSelectorStr == "" {
+			l.errorf("ON_DEMAND_NODE_SELECTOR must be set with SPOT_NODE_SELECTOR")
+		}
+		cfg.OnDemandNodeSelector, err = parseNodeSelector(onDemandSelectorStr)
+		if err != nil {
+			l.errorf("invalid ON_DEMAND_NODE_SELECTOR: %v", err)
+		}
+
+		cfg.SpotPendingTimeout = DefaultSpotPendingTimeout
+		cfg.SpotRetryInterval = DefaultSpotRetryInterval
+		for _, setting := range []struct {
+			env   string
+			value *time.Duration
+		}{
+			{"SPOT_PENDING_TIMEOUT", &cfg.SpotPendingTimeout},
+			{"SPOT_RETRY_INTERVAL", &cfg.SpotRetryInterval},
+		} {
+			valueStr := l.get(setting.env)
+			if valueStr == "" {
+				continue
+			}
+			*setting.value, err = time.ParseDuration(valueStr)
+			if err != nil {
+				l.errorf("invalid %s: %v", setting.env, err)
+			} else if *setting.value <= 0 {
+				l.errorf("%s must be positive", setting.env)
+			}
+		}
+
+		cfg.SpotInterruptionTaints = defaultSpotInterruptionTaints
+		if taintsStr := l.get("SPOT_INTERRUPTION_TAINTS"); taintsStr != "" {
+			cfg.SpotInterruptionTaints = nil
+			for _, taint := range strings.Split(taintsStr, ",") {
+				if taint = strings.TrimSpace(taint); taint != "" {
+					cfg.SpotInterruptionTaints = append(cfg.SpotInterruptionTaints, taint)
+				}
+			}
+		}
+	}
+
+	// Optional node sizes the pool is scaled with, the placeholders of sandboxes too large for the smallest one request
+	// a larger one
+	if profilesStr := l.get("NODE_PROFILES"); profilesStr != "" {
+		if cfg.ClusterBackend != ClusterBackendKubernetes {
+			l.errorf("NODE_PROFILES is only supported with the %s cluster backend", ClusterBackendKubernetes)
+		}
+		cfg.NodeProfiles, err = parseNodeProfiles(profilesStr)
+		if err != nil {
+			l.errorf("invalid NODE_PROFILES: %v", err)
+		}
+	}
+
+	// Optional room kept for sandbox classes larger than the average sandbox, e.g. two 16-core sandboxes
+	if classesStr := l.get("RESERVED_SANDBOX_CLASSES"); classesStr != "" {
+		if cfg.ClusterBackend != ClusterBackendKubernetes {
+			l.errorf("RESERVED_SANDBOX_CLASSES is only supported with the %s cluster backend", ClusterBackendKubernetes)
+		}
+		cfg.ReservedSandboxClasses, err = parseSandboxClasses(classesStr)
+		if err != nil {
+			l.errorf("invalid RESERVED_SANDBOX_CLASSES: %v", err)
+		}
+	}
+
+	// Optional warm buffer of nodes whose runners are registered but unschedulable until a scale-up needs them
+	if standbyStr := l.get("WARM_STANDBY_NODES"); standbyStr != "" {
+		if cfg.ClusterBackend != ClusterBackendKubernetes {
+			l.errorf("WARM_STANDBY_NODES is only supported with the %s cluster backend", ClusterBackendKubernetes)
+		}
+		cfg.WarmStandbyNodes, err = strconv.Atoi(standbyStr)
+		if err != nil {
+			l.errorf("invalid WARM_STANDBY_NODES: %v", err)
+		} else if cfg.WarmStandbyNodes < 0 {
+			l.errorf("WARM_STANDBY_NODES cannot be negative")
+		}
+	}
+
+	// Optional migration of the pool to the nodes of a new node pool
+	if migrationSelectorStr := l.get("MIGRATION_NODE_SELECTOR"); migrationSelectorStr != "" {
+		if cfg.ClusterBackend != ClusterBackendKubernetes {
+			l.errorf("MIGRATION_NODE_SELECTOR is only supported with the %s cluster backend", ClusterBackendKubernetes)
+		}
+		cfg.MigrationNodeSelector, err = parseNodeSelector(migrationSelectorStr)
+		if err != nil {
+			l.errorf("invalid MIGRATION_NODE_SELECTOR: %v", err)
+		}
+	}
+	cfg.MigrationMaxSurge = DefaultMigrationMaxSurge
+	if maxSurgeStr := l.get("MIGRATION_MAX_SURGE"); maxSurgeStr != "" {
+		cfg.MigrationMaxSurge, err = strconv.Atoi(maxSurgeStr)
+		if err != nil {
+			l.errorf("invalid MIGRATION_MAX_SURGE: %v", err)
+		} else if cfg.MigrationMaxSurge < 1 {
+			l.errorf("MIGRATION_MAX_SURGE must be at least 1")
+		}
+	}
+
+	// Optional node provisioner resizing the node group directly instead of the cluster autoscaler
+	cfg.NodeProvisioner = l.get("NODE_PROVISIONER")
+	if cfg.NodeProvisioner != "" {
+		switch cfg.NodeProvisioner {
+		case NodeProvisionerAWS, NodeProvisionerGCP, NodeProvisionerAzure:
+		default:
+			l.errorf("NODE_PROVISIONER must be one of %q, %q or %q", NodeProvisionerAWS, NodeProvisionerGCP, NodeProvisionerAzure)
+		}
+		if cfg.ClusterBackend != ClusterBackendKubernetes {
+			l.errorf("NODE_PROVISIONER is only supported with the %s cluster backend", ClusterBackendKubernetes)
+		}
+		// A single node group cannot provide other node sizes, zones or capacity types
+		if len(cfg.NodeProfiles) > 0 || len(cfg.SpotNodeSelector) > 0 || len(cfg.MinIdleRunnersPerZone) > 0 || len(cfg.MigrationNodeSelector) > 0 {
+			l.errorf("NODE_PROVISIONER cannot be set with NODE_PROFILES, SPOT_NODE_SELECTOR, MIN_IDLE_RUNNERS_PER_ZONE or MIGRATION_NODE_SELECTOR")
+		}
+		cfg.NodeProvisionerGroup = l.get("NODE_PROVISIONER_GROUP")
+		if cfg.NodeProvisionerGroup == "" {
+			l.errorf("NODE_PROVISIONER_GROUP not set")
+		}
+	}
+
+	// Optional thresholds of the regions managed by this process, overriding the top-level ones
+	if regionThresholdsStr := l.get("REGION_THRESHOLDS"); regionThresholdsStr != "" {
+		cfg.RegionThresholds, err = parseRegionThresholds(regionThresholdsStr)
+		if err != nil {
+			l.errorf("invalid REGION_THRESHOLDS: %v", err)
+		}
+	}
+
+	// Optional independent pools managed by this process, a single pool from the settings above when unset
+	if poolsStr := l.get("RUNNER_POOLS"); poolsStr != "" {
+		if cfg.ClusterBackend != ClusterBackendKubernetes {
+			l.errorf("RUNNER_POOLS is only supported with the %s cluster backend", ClusterBackendKubernetes)
+		}
+		cfg.Pools, err = parsePoolDefinitions(poolsStr, cfg)
+		if err != nil {
+			l.errorf("invalid RUNNER_POOLS: %v", err)
+		}
+	}
+
+	// Optional pools defined by RunnerPoolPolicy resources, reconciled by runner-manager as an operator
+	if poolPoliciesStr := l.get("RUNNER_POOL_POLICIES_ENABLED"); poolPoliciesStr != "" {
+		cfg.RunnerPoolPoliciesEnabled, err = strconv.ParseBool(poolPoliciesStr)
+		if err != nil {
+			l.errorf("invalid RUNNER_POOL_POLICIES_ENABLED: %v", err)
+		}
+	}
+	if cfg.RunnerPoolPoliciesEnabled {
+		if cfg.ClusterBackend != ClusterBackendKubernetes {
+			l.errorf("RUNNER_POOL_POLICIES_ENABLED is only supported with the %s cluster backend", ClusterBackendKubernetes)
+		}
+		if len(cfg.Pools) > 0 {
+			l.errorf("RUNNER_POOL_POLICIES_ENABLED and RUNNER_POOLS cannot be set together")
+		}
+	}
+	if cfg.NodeProvisioner != "" && (len(cfg.Pools) > 0 || cfg.RunnerPoolPoliciesEnabled) {
+		l.errorf("NODE_PROVISIONER resizes a single node group and cannot be set with RUNNER_POOLS or RUNNER_POOL_POLICIES_ENABLED")
+	}
+
+	// Optional directory of a mounted ConfigMap whose thresholds override the environment and are reloaded on change
+	cfg.ConfigDir = l.get("CONFIG_DIR")
+	if cfg.ConfigDir != "" {
+		if cfg.RunnerPoolPoliciesEnabled {
+			l.errorf("CONFIG_DIR and RUNNER_POOL_POLICIES_ENABLED cannot be set together, policies set the thresholds of their pools")
+		}
+		info, err := os.Stat(cfg.ConfigDir)
+		if err != nil {
+			l.errorf("invalid CONFIG_DIR: %v", err)
+		} else if !info.IsDir() {
+			l.errorf("invalid CONFIG_DIR: %s is not a directory", cfg.ConfigDir)
+		}
+	}
+
+	if err := l.err(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// initializeDaytonaClient creates and configures the Daytona API client
+func initializeDaytonaClient(cfg *Config) (*daytona.APIClient, error) {
+	apiCfg := daytona.NewConfiguration()
+	apiCfg.DefaultHeader = map[string]string{
+		"Authorization": "Bearer " + cfg.DaytonaAPIKey,
+	}
+	apiCfg.Servers = daytona.ServerConfigurations{
+		{
+			URL: cfg.DaytonaAPIURL,
+		},
+	}
+
+	transport := http.DefaultTransport
+	if cfg.DaytonaAPIRateLimit > 0 {
+		limiter, err := ratelimit.NewTokenBucketLimiter("runner-manager-daytona-api", ratelimit.NewMemoryStore(), cfg.DaytonaAPIRateLimit, cfg.DaytonaAPIRateLimitBurst)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Daytona API rate limiter: %w", err)
+		}
+		transport = &rateLimitedTransport{
+			limiter: limiter,
+			next:    transport,
+		}
+		log.Infof("Pacing Daytona API calls to %.2f requests/s (burst %d)", cfg.DaytonaAPIRateLimit, cfg.DaytonaAPIRateLimitBurst)
+	}
+	// Retries are paced too, as they go through the rate limiter
+	if cfg.DaytonaAPIRetryAttempts > 0 {
+		transport = newRetryingTransport(cfg, transport)
+	}
+	if transport != http.DefaultTransport {
+		apiCfg.HTTPClient = &http.Client{Transport: transport}
+	}
+
+	return daytona.NewAPIClient(apiCfg), nil
+}
+
+// initializeKubernetesClient creates and configures the Kubernetes client of the cluster of the kubeconfig context
+func initializeKubernetesClient(kubeContext string) (*kubernetes.Clientset, error) {
+	config, err := loadKubernetesConfig(kubeContext)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Kubernetes clientset: %w", err)
+	}
+
+	return clientset, nil
+}
+
+// loadKubernetesConfig returns the in-cluster configuration, or the kubeconfig one when running outside the cluster.
+// A kubeconfig context selects another cluster, whose configuration is always read from the kubeconfig.
+func loadKubernetesConfig(kubeContext string) (*rest.Config, error) {
+	if kubeContext == "" {
+		config, err := rest.InClusterConfig()
+		if err == nil {
+			return config, nil
+		}
+		log.Info("Falling back to kubeconfig due to error:", err)
+	}
+
+	kubeconfig := os.Getenv("KUBECONFIG")
+	if kubeconfig == "" {
+		kubeconfig = filepath.Join(os.Getenv("HOME"), ".kube", "config")
+	}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+	).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error building kubeconfig: %w", err)
+	}
+	return config, nil
+}
+
+// startHealthCheckServer starts the health check HTTP server and returns it for shutdown
+func startHealthCheckServer(cfg *Config, adminAuth *adminauth.Authenticator, pools []*runnerPool, nodeReports *nodeReportStore, drains *drainStore, trafficReports *trafficStore, tunnels *tunnelStore, directives *directive.Store, history *scalingHistory, decisions *decisionHistory) *http.Server {
+	// Admin endpoints reject every request when no authentication method is configured
+	admin := adminAuth.Middleware
+
+	// A mux of its own rather than the default one, which net/http/pprof registers its handlers on without
+	// authentication
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc(ReadinessPath, readinessHandler(pools))
+	if cfg.RuntimeMetricsEnabled {
+		enableRuntimeMetrics()
+	}
+	mux.Handle("/metrics", admin(promhttp.Handler()))
+	mux.HandleFunc(hostreport.ReportPath, nodeReportHandler(nodeReports, cfg.NodeReportToken))
+	mux.HandleFunc(traffic.ReportPath, trafficReportHandler(trafficReports, cfg.TrafficReportToken))
+	// Proxies send tunnel reports alongside traffic reports with the same token, without which they are rejected
+	mux.HandleFunc(tunnel.ReportPath, tunnelReportHandler(tunnels, cfg.TrafficReportToken))
+	mux.Handle(status.StatusPath, admin(statusHandler(pools)))
+	mux.Handle(status.DrainsPath, admin(drainsHandler(drains)))
+	mux.Handle(directive.Path, admin(directives.Handler()))
+	for _, pool := range pools {
+		if pool.tuner != nil {
+			mux.Handle(IdleTuningResetPath, admin(idleTuningResetHandler(pools)))
+			break
+		}
+	}
+	if history != nil {
+		mux.Handle(WhatIfPath, admin(whatIfHandler(pools, history)))
+	}
+	if decisions != nil {
+		mux.Handle(status.DecisionsPath, admin(decisionsHandler(pools, decisions)))
+	}
+	mux.Handle(StatePath, admin(stateHandler(pools)))
+	mux.Handle(status.WhyPath, admin(whyHandler(pools)))
+	mux.Handle(status.MigrationPath, admin(migrationHandler(pools)))
+	mux.Handle(status.CostPath, admin(costHandler(pools)))
+	mux.Handle(ScaleUpPath, admin(scaleUpHandler(pools)))
+	mux.Handle(ScaleDownPath, admin(scaleDownHandler(pools)))
+	// Called by the API server, which the TLS certificate and the webhook's CA bundle authenticate
+	if cfg.AdmissionWebhookServiceName != "" {
+		mux.HandleFunc(AdmissionPath, admissionHandler(pools[0].cfg))
+	}
+	if cfg.PprofEnabled {
+		registerPprofHandlers(mux, admin)
+	}
+
+	server := &http.Server{
+		Addr:      ":" + cfg.APIPort,
+		Handler:   mux,
+		TLSConfig: &tls.Config{},
+	}
+	adminAuth.ConfigureTLS(server.TLSConfig)
+
+	go func() {
+		log.Infof("Health check server listening on :%s", cfg.APIPort)
+		var err error
+		if cfg.TLSCertFile != "" {
+			err = server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Could not start health check server: %v", err)
+		}
+	}()
+
+	return server
+}
+
+// controller holds the dependencies of a pool's controller loop and the state it carries from one cycle to the next
+type controller struct {
+	pool           *runnerPool
+	apiClient      *daytona.APIClient
+	nodeReports    *nodeReportStore
+	drains         *drainStore
+	trafficReports *trafficStore
+	tunnels        *tunnelStore
+	directives     *directive.Store
+	quotaEnforcer  *quota.Enforcer
+	scalingPolicy  policy.IScalingPolicy
+	hibernator     nodeHibernator
+	lifecycle      *lifecycleNotifier
+	history        *scalingHistory
+	decisions      *decisionHistory
+
+	scheduler    *scalingScheduler
+	predictor    *demandPredictor
+	velocity     *velocityScaler
+	spot         *spotFallback
+	missingNodes *missingNodeTracker
+	remediator   *healthRemediator
+	prepuller    *snapshotPrepuller
+	sizer        *placeholderSizer
+	usage        usageSource
+	cooldown     *scaleCooldown
+
+	lastConfigDriftCheck time.Time
+	previousState        *ClusterState
+}
+
+// newController creates the controller of the pool, with the trackers of the features its configuration enables
+func newController(pool *runnerPool, apiClient *daytona.APIClient, nodeReports *nodeReportStore, drains *drainStore, trafficReports *trafficStore, tunnels *tunnelStore, directives *directive.Store, quotaEnforcer *quota.Enforcer, scalingPolicy policy.IScalingPolicy, hibernator nodeHibernator, lifecycle *lifecycleNotifier, history *scalingHistory, decisions *decisionHistory) *controller {
+	cfg := pool.cfg
+	c := &controller{
+		pool:           pool,
+		apiClient:      apiClient,
+		nodeReports:    nodeReports,
+		drains:         drains,
+		trafficReports: trafficReports,
+		tunnels:        tunnels,
+		directives:     directives,
+		quotaEnforcer:  quotaEnforcer,
+		scalingPolicy:  scalingPolicy,
+		hibernator:     hibernator,
+		lifecycle:      lifecycle,
+		history:        history,
+		decisions:      decisions,
+		cooldown:       newScaleCooldown(cfg),
+	}
+
+	if len(cfg.ScalingSchedules) > 0 {
+		c.scheduler = newScalingScheduler(cfg)
+	}
+	if cfg.PredictiveScalingEnabled && history != nil {
+		c.predictor = newDemandPredictor(cfg, history)
+	}
+	if cfg.VelocityScalingEnabled {
+		c.velocity = newVelocityScaler(cfg)
+	}
+
+	if len(cfg.SpotNodeSelector) > 0 {
+		c.spot = newSpotFallback(cfg)
+	}
+	if cfg.MissingNodeDeregisterCycles > 0 {
+		c.missingNodes = newMissingNodeTracker(cfg)
+	}
+	if cfg.HealthRemediationUnhealthyCycles > 0 {
+		c.remediator = newHealthRemediator(cfg)
+	}
+	if cfg.SnapshotPrepullCount > 0 {
+		c.prepuller = newSnapshotPrepuller(cfg, apiClient)
+	}
+	if cfg.PlaceholderCpuRequestAuto || cfg.PlaceholderMemoryRequestAuto {
+		c.sizer = newPlaceholderSizer(apiClient)
+	}
+	usage, err := newUsageSource(cfg, pool.clientset)
+	if err != nil {
+		log.Errorf("Could not create the usage source, accounting for allocations only: %v", err)
+	}
+	c.usage = usage
+
+	return c
+}
+
+// recordControlPlaneError counts the error towards the pool's circuit breaker, if it has one
+func (c *controller) recordControlPlaneError(err error) {
+	if c.pool.breaker != nil {
+		c.pool.breaker.recordError(err)
+	}
+}
+
+// runControllerLoop runs the controller loop of the pool until the context is cancelled. A cycle is never interrupted: its
+// Kubernetes and Daytona API calls do not use the context, so placeholders are not left half-created or
+// half-deleted, and the loop returns once the cycle in progress completes.
+func runControllerLoop(ctx context.Context, pool *runnerPool, apiClient *daytona.APIClient, nodeReports *nodeReportStore, drains *drainStore, trafficReports *trafficStore, tunnels *tunnelStore, directives *directive.Store, quotaEnforcer *quota.Enforcer, scalingPolicy policy.IScalingPolicy, hibernator nodeHibernator, lifecycle *lifecycleNotifier, notifier *scalingNotifier, history *scalingHistory, decisions *decisionHistory) {
+	cfg, backend := pool.cfg, pool.backend
+	c := newController(pool, apiClient, nodeReports, drains, trafficReports, tunnels, directives, quotaEnforcer, scalingPolicy, hibernator, lifecycle, history, decisions)
+
+	queue := startControllerQueue(ctx, backend, cfg)
+	go func() {
+		<-ctx.Done()
+		queue.ShutDown()
+	}()
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-pool.manual.notify:
+				controllerTriggers.WithLabelValues("manual").Inc()
+				queue.Add(controllerQueueKey)
+			}
+		}
+	}()
+
+	var lastCycle time.Time
+
+	// Each cycle is a trace, ended by the loop's post statement so cycles stopping early are ended too. The anomalies
+	// the cycle recorded in its state are notified there as well, and why it scaled is published.
+	cycleSpan := trace.SpanFromContext(context.Background())
+	var cycleState *ClusterState
+	endCycle := func() {
+		cycleSpan.End()
+		if pool.breaker != nil && pool.breaker.endCycle(cfg) && notifier != nil {
+			notifier.notify(cfg, NotificationDegraded, "Control-plane calls failed in %d consecutive cycles, scale-down is stopped and scale-up limited to %d node per cycle until a cycle succeeds.",
+				cfg.CircuitBreakerErrorCycles, DegradedMaxScaleUpPerCycle)
+		}
+		if cycleState != nil {
+			pool.statuses.recordExplanation(cfg.RegionID, cfg.PoolName, cycleState)
+		}
+		if notifier != nil && cycleState != nil {
+			notifier.notifyCycle(cfg, cycleState)
+		}
+		cycleState = nil
+	}
+	for ; ; endCycle() {
+		key, shutdown := queue.Get()
+		if shutdown || ctx.Err() != nil {
+			return
+		}
+		// Marked done right away, so a change seen during the cycle queues another one
+		queue.Done(key)
+
+		if wait := MinCycleInterval - time.Since(lastCycle); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+		lastCycle = time.Now()
+
+		var cycleCtx context.Context
+		cycleCtx, cycleSpan = tracer.Start(context.Background(), "controller.cycle",
+			trace.WithAttributes(attribute.String("region", cfg.RegionID), attribute.String("pool", cfg.PoolName)))
+		cycleState = c.runCycle(cycleCtx)
+	}
+}
+
+// runCycle runs a cycle of the controller loop, traced by the span of the context, and returns the state it gathered,
+// nil if it could not gather one
+func (c *controller) runCycle(ctx context.Context) *ClusterState {
+	cfg, backend, tuner := c.pool.cfg, c.pool.backend, c.pool.tuner
+
+	log.Debug("Running controller loop...")
+
+	// Checked within the loop so adopted values never change mid-cycle; a zero interval disables the check
+	if c.pool.primary && cfg.ConfigDriftCheckInterval > 0 && time.Since(c.lastConfigDriftCheck) >= cfg.ConfigDriftCheckInterval {
+		if tuner != nil {
+			tuner.withBase(cfg, func() { checkConfigDrift(c.apiClient, cfg) })
+		} else {
+			checkConfigDrift(c.apiClient, cfg)
+		}
+		c.lastConfigDriftCheck = time.Now()
+	}
+
+	// Thresholds changed in the mounted ConfigMap are applied before anything reads them, on the base idle buffer
+	// when tuned
+	if c.pool.reloader != nil {
+		reload := func() { c.pool.reloaded = c.pool.reloader.apply(cfg, c.pool.definition, c.pool.reloaded) }
+		if tuner != nil {
+			tuner.withBase(cfg, reload)
+		} else {
+			reload()
+		}
+	}
+
+	// The pool's RunnerPoolPolicy is applied before anything reads the thresholds, on the base idle buffer when tuned
+	if c.pool.policies != nil {
+		if tuner != nil {
+			tuner.withBase(cfg, func() { c.pool.policies.reconcile(cfg) })
+		} else {
+			c.pool.policies.reconcile(cfg)
+		}
+	}
+
+	// Reconciled every cycle so deleted or edited forwarder resources are restored. Every pool has its own namespace
+	// and nodes, so each runs its own forwarder, in its own cluster when it has a kubeContext.
+	if cfg.LogForwardingSink != "" {
+		if err := reconcileLogForwarding(c.pool.clientset, cfg); err != nil {
+			log.Errorf("Error reconciling log forwarding: %v", err)
+		}
+	}
+
+	if c.pool.primary && cfg.AdmissionWebhookServiceName != "" {
+		if err := reconcileAdmissionWebhook(c.pool.clientset, cfg); err != nil {
+			log.Errorf("Error reconciling admission webhook: %v", err)
+		}
+	}
+
+	// Every pool has its own namespace, so each keeps the budget of its placeholders
+	if cfg.PlaceholderDisruptionBudgetEnabled {
+		if err := reconcilePlaceholderDisruptionBudget(c.pool.clientset, cfg); err != nil {
+			log.Errorf("Error reconciling placeholder disruption budget: %v", err)
+		}
+	}
+
+	gatherCtx, gatherSpan := tracer.Start(ctx, "gather_cluster_state")
+	state, err := gatherClusterState(gatherCtx, c.apiClient, backend, cfg.RegionID, c.tunnels.byDomain(), c.pool.shared)
+	endSpan(gatherSpan, err)
+	if err != nil {
+		log.Errorf("Error gathering cluster state: %v", err)
+		c.pool.health.recordFailure(err)
+		c.recordControlPlaneError(err)
+		trace.SpanFromContext(ctx).SetStatus(codes.Error, err.Error())
+		return nil
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("nodes", len(state.Nodes)), attribute.Int("runners", len(state.Runners)))
+	c.pool.health.recordSuccess()
+	c.nodeReports.observeNodes(cfg.PoolName, state.Nodes)
+	state.NodeReports = c.nodeReports.fresh()
+	state.ScaleDownFreeze = c.directives.Active(directive.KindFreezeScaleDown, cfg.RegionID)
+	state.Degraded = c.pool.breaker != nil && c.pool.breaker.degraded()
+	if len(cfg.ScaleDownBlackoutWindows) > 0 {
+		state.ScaleDownBlackout = activeBlackout(cfg, time.Now())
+	}
+
+	// Runners left behind by deleted nodes would otherwise count as capacity
+	if c.missingNodes != nil {
+		c.missingNodes.reconcile(c.apiClient, state)
+	}
+
+	// Runs before any scaling decision so interrupted spot nodes no longer count as capacity
+	if c.spot != nil {
+		c.spot.reconcile(backend, c.apiClient, cfg, state)
+	}
+
+	if c.prepuller != nil {
+		c.prepuller.reconcile(state)
+	}
+	if c.sizer != nil {
+		c.sizer.reconcile(cfg)
+	}
+
+	// Runs after the spot fallback, which replaces the stuck spot placeholders
+	if cfg.CapacityExhaustionThreshold > 0 {
+		detectCapacityExhaustion(backend, cfg, state)
+	}
+
+	state.RunnerTraffic = make(map[string]*runnerTraffic)
+	trafficByProxyUrl := c.trafficReports.byRunner()
+	for _, runner := range state.Runners {
+		if runnerTraffic, found := trafficByProxyUrl[runner.GetProxyUrl()]; found {
+			state.RunnerTraffic[runner.GetId()] = runnerTraffic
+		}
+	}
+
+	state.ProtectedRunnerIDs, err = gatherProtectedRunners(c.apiClient, cfg.RegionID)
+	if err != nil {
+		// Without knowing which sandboxes are protected no runner is safe to remove
+		log.Warnf("Could not gather do-not-disturb sandboxes, protecting all runners this cycle: %v", err)
+		c.recordControlPlaneError(err)
+		state.ProtectedRunnerIDs = make(map[string]bool)
+		for _, runner := range state.Runners {
+			state.ProtectedRunnerIDs[runner.GetId()] = true
+		}
+	}
+
+	if isGPUPool(cfg) {
+		state.AllocatedGPUs, err = gatherAllocatedGPUs(c.apiClient, cfg.RegionID)
+		if err != nil {
+			// Counting allocated GPUs as free could remove GPU nodes in use, so the cycle is skipped
+			log.Errorf("Error gathering allocated GPUs: %v", err)
+			c.recordControlPlaneError(err)
+			return state
+		}
 	}
 
-	cfg.DaytonaAPIKey = os.Getenv("DAYTONA_API_KEY")
-	if cfg.DaytonaAPIKey == "" {
-		return nil, fmt.Errorf("environment variable DAYTONA_API_KEY not set")
+	// Runs before the drains are tracked so a runner drained for maintenance is tracked from its first cycle
+	reconcileMaintenance(backend, c.apiClient, state)
+	if c.remediator != nil {
+		c.remediator.reconcile(backend, c.apiClient, state)
 	}
 
-	cfg.ProviderNamespace = os.Getenv("PROVIDER_NAMESPACE")
-	if cfg.ProviderNamespace == "" {
-		return nil, fmt.Errorf("environment variable PROVIDER_NAMESPACE not set")
+	if len(cfg.EvictionNoticeProxyURLs) > 0 {
+		notifyEvictions(backend, c.apiClient, cfg, state)
+	}
+	trackDrains(backend, c.apiClient, cfg.RegionID, state, c.drains)
+	if cfg.ScaleDownGracePeriod > 0 {
+		reconcileScheduledRemovals(backend, state)
+	}
+	// Runs before the resource metrics, which leave the warm buffer out of the capacity
+	if cfg.WarmStandbyNodes > 0 {
+		reconcileWarmStandby(backend, c.apiClient, cfg, state)
+	}
+	if cfg.NascentNodeTimeout > 0 {
+		remediateNascentNodes(backend, cfg, state, c.lifecycle)
+	}
+	if len(state.ResumingNodes) > 0 {
+		completeResumedNodes(backend, c.apiClient, state)
+	}
+	if cfg.RolloutEnabled {
+		reconcileRollout(backend, c.apiClient, cfg, state)
+	}
+	if c.pool.migration != nil {
+		c.pool.migration.reconcile(backend, c.apiClient, cfg, state)
 	}
 
-	cfg.RegionID = os.Getenv("REGION_ID")
-	if cfg.RegionID == "" {
-		return nil, fmt.Errorf("environment variable REGION_ID not set")
+	// Only sandboxes of the pool's operating system are demand for this pool, the others wait for another pool
+	backlogs, err := gatherSandboxBacklog(c.apiClient, cfg.RegionID)
+	if err != nil {
+		log.Warnf("Could not count queued sandboxes, skipping idle tuning this cycle: %v", err)
+		c.recordControlPlaneError(err)
+	} else {
+		queuedSandboxes.Reset()
+		backlogResources.Reset()
+		for osName, backlog := range backlogs {
+			queuedSandboxes.WithLabelValues(osName).Set(float64(backlog.Sandboxes))
+			backlogResources.WithLabelValues(osName, "cpu").Set(float64(backlog.Cpu))
+			backlogResources.WithLabelValues(osName, "memory").Set(float64(backlog.MemoryGiB))
+			backlogResources.WithLabelValues(osName, "disk").Set(float64(backlog.DiskGiB))
+			backlogResources.WithLabelValues(osName, "gpu").Set(float64(backlog.Gpu))
+		}
+		if backlog, found := backlogs[cfg.PoolOS]; found {
+			state.Backlog = *backlog
+		}
+		state.QueuedSandboxes = state.Backlog.Sandboxes
+
+		// Sandboxes queued for capacity mean the idle buffer was too small, checked before any scaling decision
+		if tuner != nil {
+			tuner.observe(cfg, state.QueuedSandboxes)
+		}
 	}
 
-	maxResourceUtilizationPercentStr := os.Getenv("MAX_RESOURCE_UTILIZATION_PERCENT")
-	if maxResourceUtilizationPercentStr == "" {
-		return nil, fmt.Errorf("environment variable MAX_RESOURCE_UTILIZATION_PERCENT not set")
+	if c.usage != nil {
+		_, usageSpan := tracer.Start(ctx, "gather_node_usage")
+		gatherNodeUsage(c.usage, state)
+		usageSpan.End()
 	}
-	var err error
-	cfg.MaxResourceUtilizationPercent, err = strconv.Atoi(maxResourceUtilizationPercentStr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid MAX_RESOURCE_UTILIZATION_PERCENT: %v", err)
+	// Runners whose reported capacity drifts from their node's are counted with the capacity of the selected source
+	if cfg.CapacityDriftThresholdPercent > 0 {
+		state.CapacityDrift = detectCapacityDrift(backend, cfg, state, c.previousState)
 	}
-	if cfg.MaxResourceUtilizationPercent < 0 || cfg.MaxResourceUtilizationPercent > 100 {
-		return nil, fmt.Errorf("MAX_RESOURCE_UTILIZATION_PERCENT must be between 0 and 100")
+	_, metricsSpan := tracer.Start(ctx, "calculate_resource_metrics")
+	metrics := calculateResourceMetrics(cfg, state)
+	state.Packing = analyzePacking(state)
+	metricsSpan.End()
+	if c.pool.cost != nil {
+		c.pool.cost.observe(cfg, state)
 	}
 
-	minIdleRunnersStr := os.Getenv("MIN_IDLE_RUNNERS")
-	if minIdleRunnersStr == "" {
-		return nil, fmt.Errorf("environment variable MIN_IDLE_RUNNERS not set")
+	logClusterStateChanges(c.previousState, state, metrics)
+	observeProvisioningLatency(cfg, c.previousState, state)
+	if c.lifecycle != nil {
+		c.lifecycle.notifyChanges(cfg.RegionID, c.previousState, state)
 	}
-	cfg.MinIdleRunners, err = strconv.Atoi(minIdleRunnersStr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid MIN_IDLE_RUNNERS: %v", err)
+	c.previousState = state
+	c.pool.publishConfig()
+	c.pool.statuses.update(cfg.RegionID, cfg.PoolName, state, metrics)
+	if c.pool.policies != nil {
+		c.pool.policies.writeStatus(cfg.PoolName, c.pool.statuses)
 	}
-	if cfg.MinIdleRunners < 0 {
-		return nil, fmt.Errorf("MIN_IDLE_RUNNERS cannot be negative")
+	if c.history != nil {
+		if err := c.history.record(cfg.PoolName, state, metrics); err != nil {
+			log.Errorf("Error recording scaling history: %v", err)
+		}
 	}
 
-	minIdleCpuStr := os.Getenv("MIN_IDLE_CPU")
-	if minIdleCpuStr == "" {
-		return nil, fmt.Errorf("environment variable MIN_IDLE_CPU not set")
-	}
-	cfg.MinIdleCpu, err = strconv.Atoi(minIdleCpuStr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid MIN_IDLE_CPU: %v", err)
+	// Over-quota demand is excluded from scale-up decisions only, scale-down safety checks use the real allocation
+	scaleUpMetrics := metrics
+	if c.quotaEnforcer != nil {
+		overCpu, overMemoryGiB, err := calculateOverQuotaDemand(c.apiClient, c.quotaEnforcer, cfg.RegionID)
+		if err != nil {
+			log.Warnf("Could not calculate over-quota demand, counting all demand: %v", err)
+		} else if overCpu > 0 || overMemoryGiB > 0 {
+			scaleUpMetrics = excludeDemand(metrics, overCpu, overMemoryGiB)
+		}
 	}
-	if cfg.MinIdleCpu < 0 {
-		return nil, fmt.Errorf("MIN_IDLE_CPU cannot be negative")
+	if cfg.BacklogScalingEnabled {
+		scaleUpMetrics = includeBacklog(scaleUpMetrics, standardBacklog(cfg, state.Backlog))
 	}
 
-	minIdleMemoryStr := os.Getenv("MIN_IDLE_MEMORY")
-	if minIdleMemoryStr == "" {
-		return nil, fmt.Errorf("environment variable MIN_IDLE_MEMORY not set")
+	// Actions requested through the admin API come before this cycle's own decisions, which account for them
+	_, manualSpan := tracer.Start(ctx, "manual_actions")
+	if manual := handleManualActions(backend, c.apiClient, cfg, state, c.pool.manual); manual != "" {
+		manualSpan.SetAttributes(attribute.String("action", manual))
+		recordDecision(c.pool, c.decisions, cfg, state, metrics, manual)
 	}
-	cfg.MinIdleMemory, err = strconv.Atoi(minIdleMemoryStr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid MIN_IDLE_MEMORY: %v", err)
+	manualSpan.End()
+
+	// Zone requirements are satisfied independently of the pool-wide buffer and the scaling policy
+	if len(cfg.MinIdleRunnersPerZone) > 0 {
+		state.ZoneIdle = gatherZoneIdle(cfg, state)
+		if traceAction(ctx, "zone_scale_up", func() bool { return handleZoneScaleUp(backend, cfg, state) }) {
+			c.cooldown.recordScaleUp()
+			recordDecision(c.pool, c.decisions, cfg, state, metrics, "zone scale-up")
+		}
 	}
-	if cfg.MinIdleMemory < 0 {
-		return nil, fmt.Errorf("MIN_IDLE_MEMORY cannot be negative")
+
+	// Sandboxes too large for the default node profile get nodes of a larger one
+	if len(cfg.NodeProfiles) > 0 && traceAction(ctx, "profile_scale_up", func() bool { return handleProfileScaleUp(backend, cfg, state) }) {
+		c.cooldown.recordScaleUp()
+		recordDecision(c.pool, c.decisions, cfg, state, metrics, "node profile scale-up")
 	}
 
-	return cfg, nil
-}
+	// Reserved sandbox classes need room on a single runner, which the pool-wide idle buffer does not guarantee
+	if len(cfg.ReservedSandboxClasses) > 0 {
+		state.ClassRoom = gatherClassRoom(cfg, state, metrics)
+		if traceAction(ctx, "class_scale_up", func() bool { return handleClassScaleUp(backend, cfg, state) }) {
+			c.cooldown.recordScaleUp()
+			recordDecision(c.pool, c.decisions, cfg, state, metrics, "sandbox class scale-up")
+		}
+	}
 
-// initializeDaytonaClient creates and configures the Daytona API client
-func initializeDaytonaClient(cfg *Config) (*daytona.APIClient, error) {
-	apiCfg := daytona.NewConfiguration()
-	apiCfg.DefaultHeader = map[string]string{
-		"Authorization": "Bearer " + cfg.DaytonaAPIKey,
+	// The active schedule entry sets the idle buffer of this cycle's decisions, the idle percentages scale it with the
+	// pool, capacity reserved for organizations is added to it, and forecast demand and the current growth raise it
+	// to keep capacity ahead of recurring peaks and surges
+	decisionCfg := cfg
+	if c.scheduler != nil {
+		decisionCfg = c.scheduler.apply(decisionCfg)
 	}
-	apiCfg.Servers = daytona.ServerConfigurations{
-		{
-			URL: cfg.DaytonaAPIURL,
-		},
+	if cfg.MinIdleCpuPercent > 0 || cfg.MinIdleMemoryPercent > 0 {
+		decisionCfg = applyIdlePercentages(decisionCfg, metrics)
 	}
-	return daytona.NewAPIClient(apiCfg), nil
-}
+	if cfg.CapacityReservationsEnabled {
+		decisionCfg = reserveCapacity(c.apiClient, decisionCfg)
+	}
+	if c.predictor != nil {
+		decisionCfg = c.predictor.boost(decisionCfg, state, metrics)
+	}
+	if c.velocity != nil {
+		decisionCfg = c.velocity.boost(decisionCfg, state, metrics)
+	}
+	state.Why.evaluateScaleUp(decisionCfg, state, scaleUpMetrics)
 
-// initializeKubernetesClient creates and configures the Kubernetes client
-func initializeKubernetesClient() (*kubernetes.Clientset, error) {
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		log.Println("Falling back to kubeconfig due to error:", err)
-		kubeconfig := os.Getenv("KUBECONFIG")
-		if kubeconfig == "" {
-			kubeconfig = filepath.Join(os.Getenv("HOME"), ".kube", "config")
-		}
-		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	// A degraded loop only uses the built-in policy, whose scale-up is limited and scale-down stopped
+	if c.scalingPolicy != nil && !state.Degraded {
+		decision, err := c.scalingPolicy.Evaluate(buildPolicyInput(decisionCfg, state, scaleUpMetrics))
 		if err != nil {
-			return nil, fmt.Errorf("error building kubeconfig: %w", err)
+			log.Errorf("Error evaluating scaling policy, falling back to the built-in policy: %v", err)
+		} else if !decision.Defer {
+			state.Why.skipScaleUp("decided by the scaling policy: " + decision.Reason)
+			state.Why.skipScaleDown("decided by the scaling policy: " + decision.Reason)
+			_, policySpan := tracer.Start(ctx, "scaling_policy", trace.WithAttributes(attribute.Int("node_delta", decision.NodeDelta)))
+			applyPolicyDecision(backend, c.apiClient, c.hibernator, decisionCfg, state, metrics, decision, c.cooldown)
+			policySpan.End()
+			if decision.NodeDelta != 0 {
+				recordDecision(c.pool, c.decisions, decisionCfg, state, metrics, fmt.Sprintf("scaling policy node delta of %d: %s", decision.NodeDelta, decision.Reason))
+			}
+			return state
 		}
 	}
 
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return nil, fmt.Errorf("error creating Kubernetes clientset: %w", err)
+	needsScaleUp := shouldScaleUp(scaleUpMetrics, decisionCfg, len(state.IdleRunners), len(state.NascentNodes)+len(state.ResumingNodes), state.QueuedSandboxes)
+	if needsScaleUp {
+		if remaining := c.cooldown.scaleUpRemaining(); remaining > 0 {
+			log.Infof("Scale-up conditions met, but in scale-up cooldown for another %s.", remaining.Round(time.Second))
+			state.Why.skipScaleUp(fmt.Sprintf("in scale-up cooldown for another %s", remaining.Round(time.Second)))
+		} else if traceAction(ctx, "scale_up", func() bool {
+			return handleScaleUp(backend, c.apiClient, c.hibernator, decisionCfg, state, scaleUpMetrics)
+		}) {
+			c.cooldown.recordScaleUp()
+			recordDecision(c.pool, c.decisions, decisionCfg, state, scaleUpMetrics, "scale-up")
+			state.Why.skipScaleDown("scaled up this cycle")
+			return state // Skip scale-down logic for this cycle
+		}
 	}
 
-	return clientset, nil
+	if state.Degraded {
+		log.Warn("Degraded by repeated control-plane errors, skipping scale-down.")
+		state.Why.skipScaleDown("degraded by repeated control-plane errors")
+		return state
+	}
+	if remaining := c.cooldown.scaleDownRemaining(); remaining > 0 {
+		log.Debugf("In scale-down cooldown for another %s, skipping scale-down.", remaining.Round(time.Second))
+		state.Why.skipScaleDown(fmt.Sprintf("in scale-down cooldown for another %s", remaining.Round(time.Second)))
+		return state
+	}
+	if traceAction(ctx, "scale_down", func() bool {
+		return handleScaleDown(backend, c.apiClient, c.hibernator, decisionCfg, state, metrics, needsScaleUp, 0)
+	}) {
+		c.cooldown.recordScaleDown()
+		recordDecision(c.pool, c.decisions, decisionCfg, state, metrics, "scale-down")
+	}
+
+	return state
 }
 
-// startHealthCheckServer starts the health check HTTP server
-func startHealthCheckServer(apiPort string) {
-	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+// gatherClusterState collects all cluster state information from various sources. When the region's runners are
+// shared between several pools, only the runners of the pool's nodes are part of its state.
+func gatherClusterState(ctx context.Context, apiClient *daytona.APIClient, backend clusterBackend, regionID string, tunnels map[string]tunnel.RunnerTunnel, shared bool) (*ClusterState, error) {
+	state := &ClusterState{
+		RunnerByDomain:       make(map[string]daytona.RunnerFull),
+		NodeByIP:             make(map[string]*corev1.Node),
+		UnreachableRunnerIDs: make(map[string]bool),
+		TunneledDomains:      make(map[string]bool),
+		Why:                  newCycleExplanation(),
+	}
+
+	// Runners, placeholders and nodes are fetched concurrently, each call with its own timeout, as on large clusters
+	// each can take seconds
+	var (
+		wg                                    sync.WaitGroup
+		runners                               []daytona.RunnerFull
+		allPlaceholders                       []*corev1.Pod
+		runnersErr, placeholdersErr, nodesErr error
+	)
+	wg.Add(3)
 	go func() {
-		log.Printf("Health check server listening on :%s", apiPort)
-		if err := http.ListenAndServe(":"+apiPort, nil); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Could not start health check server: %v", err)
-		}
+		defer wg.Done()
+		ctx, span := tracer.Start(ctx, "daytona.list_runners")
+		// The admin endpoint is not paginated, it returns every runner of the region in a single response, so large
+		// fleets are fully accounted for without a page loop
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		runners, _, runnersErr = apiClient.AdminAPI.AdminListRunners(ctx).RegionId(regionID).Execute()
+		endSpan(span, runnersErr)
 	}()
-}
-
-// runControllerLoop runs the main controller loop
-func runControllerLoop(cfg *Config, apiClient *daytona.APIClient, clientset *kubernetes.Clientset) {
-	ticker := time.NewTicker(CheckInterval)
-	defer ticker.Stop()
+	go func() {
+		defer wg.Done()
+		ctx, span := tracer.Start(ctx, "cluster.list_placeholders")
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		allPlaceholders, placeholdersErr = backend.ListPlaceholders(ctx)
+		endSpan(span, placeholdersErr)
+	}()
+	go func() {
+		defer wg.Done()
+		ctx, span := tracer.Start(ctx, "cluster.list_nodes")
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		state.Nodes, nodesErr = backend.ListNodes(ctx)
+		endSpan(span, nodesErr)
+	}()
+	wg.Wait()
 
-	for range ticker.C {
-		log.Println("Running controller loop...")
+	if runnersErr != nil {
+		return nil, fmt.Errorf("failed to list runners from Daytona API: %w", runnersErr)
+	}
+	if placeholdersErr != nil {
+		return nil, fmt.Errorf("error listing placeholder pods: %w", placeholdersErr)
+	}
+	if nodesErr != nil {
+		return nil, fmt.Errorf("error listing K8s nodes: %w", nodesErr)
+	}
 
-		state, err := gatherClusterState(apiClient, clientset, cfg.RegionID, cfg.ProviderNamespace)
-		if err != nil {
-			log.Printf("Error gathering cluster state: %v", err)
-			continue
+	// Categorize placeholders
+	for _, pod := range allPlaceholders {
+		if pod.Spec.NodeName == "" {
+			state.PendingPlaceholders = append(state.PendingPlaceholders, pod)
+		} else {
+			state.ScheduledPlaceholders = append(state.ScheduledPlaceholders, pod)
 		}
+	}
 
-		metrics := calculateResourceMetrics(state)
-
-		logClusterState(state, metrics)
-
-		needsScaleUp := shouldScaleUp(metrics, cfg, len(state.IdleRunners), len(state.NascentNodes))
-		if needsScaleUp {
-			if handleScaleUp(clientset, cfg, state, metrics) {
-				continue // Skip scale-down logic for this cycle
-			}
+	// Build node IP mapping
+	for i := range state.Nodes {
+		node := &state.Nodes[i]
+		nodeIPs := extractNodeIPs(node)
+		for _, ip := range nodeIPs {
+			state.NodeByIP[ip] = node
 		}
-
-		handleScaleDown(clientset, cfg, state, metrics, needsScaleUp)
 	}
-}
 
-// gatherClusterState collects all cluster state information from various sources
-func gatherClusterState(apiClient *daytona.APIClient, clientset *kubernetes.Clientset, regionID, providerNamespace string) (*ClusterState, error) {
-	state := &ClusterState{
-		RunnerByDomain: make(map[string]daytona.RunnerFull),
-		NodeByIP:       make(map[string]*corev1.Node),
+	// Runners behind NAT report a domain unknown to their node, so they are mapped by the node name relayed with their
+	// heartbeat. A domain matching a node IP is a direct runner, which a tunnel report can neither move to another node
+	// nor mark unreachable.
+	runnerDomains := make(map[string]bool)
+	for _, runner := range runners {
+		runnerDomains[runner.GetDomain()] = true
 	}
-
-	// Fetch runners from Daytona API
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	req := apiClient.AdminAPI.AdminListRunners(ctx).RegionId(regionID)
-	runners, _, err := req.Execute()
-	if err != nil {
-		return nil, fmt.Errorf("failed to list runners from Daytona API: %w", err)
+	for domain, tunneled := range tunnels {
+		if _, direct := state.NodeByIP[domain]; direct || !runnerDomains[domain] {
+			continue
+		}
+		state.TunneledDomains[domain] = true
+		if tunneled.NodeName == "" {
+			continue
+		}
+		if node := findNodeByName(state, tunneled.NodeName); node != nil {
+			state.NodeByIP[domain] = node
+		}
 	}
-	state.Runners = runners
 
 	// Categorize runners and build domain-based mapping
-	for _, runner := range state.Runners {
+	for _, runner := range runners {
 		domain := runner.GetDomain()
+		if _, onPoolNode := state.NodeByIP[domain]; !onPoolNode {
+			state.NodelessRunners = append(state.NodelessRunners, runner)
+			if shared {
+				continue
+			}
+		}
+		state.Runners = append(state.Runners, runner)
 		if domain != "" {
 			state.RunnerByDomain[domain] = runner
 		}
 
-		isAllocated := (runner.GetCurrentAllocatedCpu() > 0) ||
-			(runner.GetCurrentAllocatedMemoryGiB() > 0) ||
-			(runner.GetCurrentAllocatedDiskGiB() > 0) ||
-			(runner.GetCurrentStartedSandboxes() > 0) ||
-			(runner.GetCurrentSnapshotCount() > 0)
+		// A runner whose relayed heartbeat stopped cannot take sandboxes, so it does not count as idle capacity
+		isUnreachable := state.TunneledDomains[domain] && time.Since(tunnels[domain].LastHeartbeat) > TunnelHeartbeatMaxAge
 
-		if isAllocated {
+		if isRunnerAllocated(runner) {
 			state.ActiveRunners = append(state.ActiveRunners, runner)
+		} else if isUnreachable && !runner.GetUnschedulable() {
+			state.UnreachableRunnerIDs[runner.GetId()] = true
 		} else if runner.GetUnschedulable() {
 			state.DeletableRunners = append(state.DeletableRunners, runner)
 		} else {
@@ -300,58 +2529,29 @@ func gatherClusterState(apiClient *daytona.APIClient, clientset *kubernetes.Clie
 		}
 	}
 
-	// Fetch placeholder pods
-	allPlaceholders, err := clientset.CoreV1().Pods(providerNamespace).List(context.Background(), metav1.ListOptions{
-		LabelSelector: "app=" + PlaceholderPodLabel,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("error listing placeholder pods: %w", err)
-	}
-
-	// Categorize placeholders
-	for i := range allPlaceholders.Items {
-		pod := &allPlaceholders.Items[i]
-		if pod.Spec.NodeName == "" {
-			state.PendingPlaceholders = append(state.PendingPlaceholders, pod)
-		} else {
-			state.ScheduledPlaceholders = append(state.ScheduledPlaceholders, pod)
-		}
-	}
-
-	// Fetch K8s nodes
-	nodes, err := clientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{
-		LabelSelector: NodeSelectorKey + "=true",
-	})
-	if err != nil {
-		return nil, fmt.Errorf("error listing K8s nodes: %w", err)
-	}
-	state.Nodes = nodes.Items
-
-	// Build node IP mapping
+	// Identify hibernated nodes
 	for i := range state.Nodes {
-		node := &state.Nodes[i]
-		nodeIPs := extractNodeIPs(node)
-		for _, ip := range nodeIPs {
-			state.NodeByIP[ip] = node
+		if _, hibernated := state.Nodes[i].Annotations[HibernatedAtAnnotation]; hibernated {
+			state.HibernatedNodes = append(state.HibernatedNodes, &state.Nodes[i])
+		} else if _, resumed := state.Nodes[i].Annotations[ResumedAtAnnotation]; resumed {
+			state.ResumingNodes = append(state.ResumingNodes, &state.Nodes[i])
 		}
 	}
+	hibernatedNodes.Set(float64(len(state.HibernatedNodes)))
 
 	// Identify nascent nodes (nodes with scheduled placeholders but no runner yet)
+	nodesWithRunner := make(map[string]bool)
+	for domain := range state.RunnerByDomain {
+		if node, found := state.NodeByIP[domain]; found {
+			nodesWithRunner[node.Name] = true
+		}
+	}
 	for _, node := range state.Nodes {
 		if node.Spec.Unschedulable {
 			continue
 		}
-		// Check if node has a runner
-		hasRunner := false
-		nodeIPs := extractNodeIPs(&node)
-		for _, ip := range nodeIPs {
-			if _, found := state.RunnerByDomain[ip]; found {
-				hasRunner = true
-				break
-			}
-		}
 		// If no runner but has scheduled placeholder, it's nascent
-		if !hasRunner {
+		if !nodesWithRunner[node.Name] {
 			for _, pod := range state.ScheduledPlaceholders {
 				if pod.Spec.NodeName == node.Name {
 					state.NascentNodes = append(state.NascentNodes, &node)
@@ -366,7 +2566,7 @@ func gatherClusterState(apiClient *daytona.APIClient, clientset *kubernetes.Clie
 
 // calculateResourceMetrics calculates aggregated resource metrics
 // Priority: Use runner-reported capacity when available, fallback to K8s node capacity for nodes without runners
-func calculateResourceMetrics(state *ClusterState) *ResourceMetrics {
+func calculateResourceMetrics(cfg *Config, state *ClusterState) *ResourceMetrics {
 	metrics := &ResourceMetrics{}
 
 	// Track which nodes have runners (by node name)
@@ -375,16 +2575,28 @@ func calculateResourceMetrics(state *ClusterState) *ResourceMetrics {
 	// Calculate total capacity: prioritize runner-reported capacity (from Docker, more accurate)
 	for _, runner := range state.Runners {
 		if !runner.GetUnschedulable() {
-			// Use runner-reported capacity (from Docker, more accurate)
-			metrics.TotalCPUCapacity += runner.GetCpu()
-			metrics.TotalMemoryGiBCapacity += runner.GetMemory()
+			// Use runner-reported capacity (from Docker, more accurate), unless CAPACITY_DRIFT_SOURCE selects the node's
+			runnerCpu, runnerMemory := runnerCapacity(state, runner)
 			// Track which nodes have runners
 			domain := runner.GetDomain()
 			if domain != "" {
 				if node, found := state.NodeByIP[domain]; found {
 					nodesWithRunners[node.Name] = true
+					// CPU stolen by the hypervisor is not usable by sandboxes even though Docker reports it
+					if report, found := state.NodeReports[node.Name]; found && report.CpuStealPercent > 0 {
+						runnerCpu *= float32(1 - math.Min(report.CpuStealPercent, 100)/100)
+					}
 				}
 			}
+			// The node is still accounted as having a runner so its allocatable resources are not counted instead
+			if state.UnreachableRunnerIDs[runner.GetId()] {
+				continue
+			}
+			runnerCpu, runnerMem := effectiveCapacity(cfg, runnerCpu, runnerMemory)
+			metrics.TotalCPUCapacity += runnerCpu
+			metrics.TotalMemoryGiBCapacity += runnerMem
+			metrics.TotalGPUCapacity += runner.GetGpu()
+			metrics.TotalDiskGiBCapacity += runner.GetDisk()
 		}
 	}
 
@@ -398,29 +2610,60 @@ func calculateResourceMetrics(state *ClusterState) *ResourceMetrics {
 		if nodesWithRunners[node.Name] {
 			continue
 		}
+		// Warm standby nodes only become capacity once promoted
+		if isStandbyNode(&node) {
+			continue
+		}
 		// Use K8s allocatable resources as fallback
 		nodeCpu, nodeMem, err := getNodeAllocatableResources(&node)
 		if err != nil {
-			log.Printf("Warning: Could not get allocatable resources for node %s: %v", node.Name, err)
+			log.Warnf("Could not get allocatable resources for node %s: %v", node.Name, err)
 			continue
 		}
+		nodeCpu, nodeMem = effectiveCapacity(cfg, nodeCpu, nodeMem)
 		metrics.TotalCPUCapacity += nodeCpu
 		metrics.TotalMemoryGiBCapacity += nodeMem
+		metrics.TotalGPUCapacity += getNodeAllocatableGPUs(&node)
+		metrics.TotalDiskGiBCapacity += getNodeAllocatableDiskGiB(&node)
 	}
 
 	// Calculate allocated resources from runners (always from runner data)
 	for _, runner := range state.ActiveRunners {
+		// A runner at its operating system's sandbox limit has no usable capacity left, so all of it counts as allocated
+		if node, found := state.NodeByIP[runner.GetDomain()]; found && !runner.GetUnschedulable() && !state.UnreachableRunnerIDs[runner.GetId()] {
+			if limit := runnerSandboxLimit(nodeOS(node)); limit > 0 && int(runner.GetCurrentStartedSandboxes()) >= limit {
+				runnerCpu, runnerMem := runnerCapacity(state, runner)
+				runnerCpu, runnerMem = effectiveCapacity(cfg, runnerCpu, runnerMem)
+				metrics.TotalAllocatedCPU += runnerCpu
+				metrics.TotalAllocatedMemoryGiB += runnerMem
+				metrics.TotalAllocatedGPU += runner.GetGpu()
+				metrics.TotalAllocatedDiskGiB += runner.GetDisk()
+				continue
+			}
+		}
+		var runnerAllocatedCpu, runnerAllocatedMemoryGiB float32
 		if allocatedCPU, ok := runner.GetCurrentAllocatedCpuOk(); ok && allocatedCPU != nil {
-			metrics.TotalAllocatedCPU += *allocatedCPU
+			runnerAllocatedCpu = *allocatedCPU
 		}
 		if allocatedMemory, ok := runner.GetCurrentAllocatedMemoryGiBOk(); ok && allocatedMemory != nil {
-			metrics.TotalAllocatedMemoryGiB += *allocatedMemory
+			runnerAllocatedMemoryGiB = *allocatedMemory
 		}
+		if node, found := state.NodeByIP[runner.GetDomain()]; found {
+			runnerAllocatedCpu, runnerAllocatedMemoryGiB = blendUsage(cfg, state, node.Name, runnerAllocatedCpu, runnerAllocatedMemoryGiB)
+		}
+		metrics.TotalAllocatedCPU += runnerAllocatedCpu
+		metrics.TotalAllocatedMemoryGiB += runnerAllocatedMemoryGiB
+		if allocatedDisk, ok := runner.GetCurrentAllocatedDiskGiBOk(); ok && allocatedDisk != nil {
+			metrics.TotalAllocatedDiskGiB += *allocatedDisk
+		}
+		metrics.TotalAllocatedGPU += state.AllocatedGPUs[runner.GetId()]
 	}
 
 	// Calculate available resources
 	metrics.TotalAvailableCPU = metrics.TotalCPUCapacity - metrics.TotalAllocatedCPU
 	metrics.TotalAvailableMemoryGiB = metrics.TotalMemoryGiBCapacity - metrics.TotalAllocatedMemoryGiB
+	metrics.TotalAvailableGPU = metrics.TotalGPUCapacity - metrics.TotalAllocatedGPU
+	metrics.TotalAvailableDiskGiB = metrics.TotalDiskGiBCapacity - metrics.TotalAllocatedDiskGiB
 
 	// Calculate average node capacity based on all schedulable nodes
 	schedulableNodeCount := 0
@@ -432,6 +2675,8 @@ func calculateResourceMetrics(state *ClusterState) *ResourceMetrics {
 	if schedulableNodeCount > 0 {
 		metrics.AvgCpuPerNode = metrics.TotalCPUCapacity / float32(schedulableNodeCount)
 		metrics.AvgMemPerNode = metrics.TotalMemoryGiBCapacity / float32(schedulableNodeCount)
+		metrics.AvgGpuPerNode = metrics.TotalGPUCapacity / float32(schedulableNodeCount)
+		metrics.AvgDiskPerNode = metrics.TotalDiskGiBCapacity / float32(schedulableNodeCount)
 	}
 
 	return metrics
@@ -439,18 +2684,37 @@ func calculateResourceMetrics(state *ClusterState) *ResourceMetrics {
 
 // logClusterState logs the current cluster state
 func logClusterState(state *ClusterState, metrics *ResourceMetrics) {
-	log.Printf("Current state: DaytonaRunners: %d (Active: %d, Idle: %d, Deletable: %d). Nodes in pool: %d. NascentNodes: %d. Placeholders: %d (Pending: %d, Scheduled: %d).",
+	log.Infof("Current state: DaytonaRunners: %d (Active: %d, Idle: %d, Deletable: %d). Nodes in pool: %d. NascentNodes: %d. Placeholders: %d (Pending: %d, Scheduled: %d).",
 		len(state.Runners), len(state.ActiveRunners), len(state.IdleRunners), len(state.DeletableRunners),
 		len(state.Nodes), len(state.NascentNodes), len(state.PendingPlaceholders)+len(state.ScheduledPlaceholders),
 		len(state.PendingPlaceholders), len(state.ScheduledPlaceholders))
-	log.Printf("Aggregated Capacity: CPU=%.2f, Mem=%.2fGiB. Aggregated Allocated: CPU=%.2f, Mem=%.2fGiB. Aggregated Available: CPU=%.2f, Mem=%.2fGiB.",
+	log.Infof("Aggregated Capacity: CPU=%.2f, Mem=%.2fGiB. Aggregated Allocated: CPU=%.2f, Mem=%.2fGiB. Aggregated Available: CPU=%.2f, Mem=%.2fGiB.",
 		metrics.TotalCPUCapacity, metrics.TotalMemoryGiBCapacity, metrics.TotalAllocatedCPU, metrics.TotalAllocatedMemoryGiB,
 		metrics.TotalAvailableCPU, metrics.TotalAvailableMemoryGiB)
-	log.Printf("Average node capacity: CPU=%.2f, Mem=%.2fGiB", metrics.AvgCpuPerNode, metrics.AvgMemPerNode)
+	log.Infof("Average node capacity: CPU=%.2f, Mem=%.2fGiB", metrics.AvgCpuPerNode, metrics.AvgMemPerNode)
+	if metrics.TotalDiskGiBCapacity > 0 {
+		log.Infof("Disk: Capacity=%.2fGiB, Allocated=%.2fGiB, Available=%.2fGiB.", metrics.TotalDiskGiBCapacity, metrics.TotalAllocatedDiskGiB, metrics.TotalAvailableDiskGiB)
+	}
+	if len(state.NodeReports) > 0 {
+		log.Infof("Capacity reports: %d of %d nodes reporting.", len(state.NodeReports), len(state.Nodes))
+	}
+	if len(state.HibernatedNodes) > 0 {
+		log.Infof("Hibernated nodes: %d.", len(state.HibernatedNodes))
+	}
+	if len(state.ResumingNodes) > 0 {
+		log.Infof("Nodes resuming from hibernation: %d.", len(state.ResumingNodes))
+	}
+	if len(state.UnreachableRunnerIDs) > 0 {
+		log.Infof("Unreachable tunneled runners: %d.", len(state.UnreachableRunnerIDs))
+	}
+	if state.Packing.DefragmentationOpportunityNodes > 0 {
+		log.Infof("Packing: %d active runners could be freed by consolidation. Stranded: CPU=%.2f, Mem=%.2fGiB.",
+			state.Packing.DefragmentationOpportunityNodes, state.Packing.StrandedCpu, state.Packing.StrandedMemoryGiB)
+	}
 }
 
 // shouldScaleUp determines if scale-up conditions are met
-func shouldScaleUp(metrics *ResourceMetrics, cfg *Config, idleRunnersCount, nascentNodesCount int) bool {
+func shouldScaleUp(metrics *ResourceMetrics, cfg *Config, idleRunnersCount, nascentNodesCount, queuedSandboxes int) bool {
 	isCpuUtilizationTooHigh := false
 	if metrics.TotalCPUCapacity > 0 {
 		isCpuUtilizationTooHigh = (metrics.TotalAllocatedCPU/metrics.TotalCPUCapacity)*100 > float32(cfg.MaxResourceUtilizationPercent)
@@ -459,19 +2723,28 @@ func shouldScaleUp(metrics *ResourceMetrics, cfg *Config, idleRunnersCount, nasc
 	if metrics.TotalMemoryGiBCapacity > 0 {
 		isMemUtilizationTooHigh = (metrics.TotalAllocatedMemoryGiB/metrics.TotalMemoryGiBCapacity)*100 > float32(cfg.MaxResourceUtilizationPercent)
 	}
-	isUtilizationTooHigh := isCpuUtilizationTooHigh || isMemUtilizationTooHigh
+	isGpuUtilizationTooHigh := false
+	if metrics.TotalGPUCapacity > 0 {
+		isGpuUtilizationTooHigh = (metrics.TotalAllocatedGPU/metrics.TotalGPUCapacity)*100 > float32(cfg.MaxResourceUtilizationPercent)
+	}
+	isUtilizationTooHigh := isCpuUtilizationTooHigh || isMemUtilizationTooHigh || isGpuUtilizationTooHigh || isDiskUtilizationTooHigh(metrics, cfg)
 
 	totalIdleRunnersIncludingNascent := idleRunnersCount + nascentNodesCount
 	isIdleRunnerBufferTooLow := totalIdleRunnersIncludingNascent < cfg.MinIdleRunners
 
 	isCpuIdleTooLow := metrics.TotalAvailableCPU < float32(cfg.MinIdleCpu)
 	isMemIdleTooLow := metrics.TotalAvailableMemoryGiB < float32(cfg.MinIdleMemory)
+	isGpuIdleTooLow := metrics.TotalAvailableGPU < float32(cfg.MinIdleGpu)
+	isDiskIdleTooLow := metrics.TotalAvailableDiskGiB < float32(cfg.MinIdleDisk)
+
+	// Queued sandboxes with no runner on the way can only be placed once a node is added
+	isQueueStarved := queuedSandboxes > 0 && totalIdleRunnersIncludingNascent == 0
 
-	return isUtilizationTooHigh || isIdleRunnerBufferTooLow || isCpuIdleTooLow || isMemIdleTooLow
+	return isUtilizationTooHigh || isIdleRunnerBufferTooLow || isCpuIdleTooLow || isMemIdleTooLow || isGpuIdleTooLow || isDiskIdleTooLow || isQueueStarved
 }
 
 // handleScaleUp handles scale-up logic and returns true if scale-up was triggered
-func handleScaleUp(clientset *kubernetes.Clientset, cfg *Config, state *ClusterState, metrics *ResourceMetrics) bool {
+func handleScaleUp(backend clusterBackend, apiClient *daytona.APIClient, hibernator nodeHibernator, cfg *Config, state *ClusterState, metrics *ResourceMetrics) bool {
 	isCpuUtilizationTooHigh := false
 	if metrics.TotalCPUCapacity > 0 {
 		isCpuUtilizationTooHigh = (metrics.TotalAllocatedCPU/metrics.TotalCPUCapacity)*100 > float32(cfg.MaxResourceUtilizationPercent)
@@ -480,83 +2753,261 @@ func handleScaleUp(clientset *kubernetes.Clientset, cfg *Config, state *ClusterS
 	if metrics.TotalMemoryGiBCapacity > 0 {
 		isMemUtilizationTooHigh = (metrics.TotalAllocatedMemoryGiB/metrics.TotalMemoryGiBCapacity)*100 > float32(cfg.MaxResourceUtilizationPercent)
 	}
-	isUtilizationTooHigh := isCpuUtilizationTooHigh || isMemUtilizationTooHigh
+	isGpuUtilizationTooHigh := false
+	if metrics.TotalGPUCapacity > 0 {
+		isGpuUtilizationTooHigh = (metrics.TotalAllocatedGPU/metrics.TotalGPUCapacity)*100 > float32(cfg.MaxResourceUtilizationPercent)
+	}
+	isDiskUtilizationTooHigh := isDiskUtilizationTooHigh(metrics, cfg)
+	isUtilizationTooHigh := isCpuUtilizationTooHigh || isMemUtilizationTooHigh || isGpuUtilizationTooHigh || isDiskUtilizationTooHigh
 
-	totalIdleRunnersIncludingNascent := len(state.IdleRunners) + len(state.NascentNodes)
+	// A resumed node not back yet becomes an idle runner shortly, like a nascent one
+	totalIdleRunnersIncludingNascent := len(state.IdleRunners) + len(state.NascentNodes) + len(state.ResumingNodes)
 	isIdleRunnerBufferTooLow := totalIdleRunnersIncludingNascent < cfg.MinIdleRunners
 	isCpuIdleTooLow := metrics.TotalAvailableCPU < float32(cfg.MinIdleCpu)
 	isMemIdleTooLow := metrics.TotalAvailableMemoryGiB < float32(cfg.MinIdleMemory)
-
-	log.Printf("Scale-up conditions met: UtilizationTooHigh: %t (CPU: %.2f%%, Mem: %.2f%%), IdleBufferTooLow: %t (%d < %d), CpuIdleTooLow: %t (%.2f < %d), MemIdleTooLow: %t (%.2f < %d)",
+	isGpuIdleTooLow := metrics.TotalAvailableGPU < float32(cfg.MinIdleGpu)
+	isDiskIdleTooLow := metrics.TotalAvailableDiskGiB < float32(cfg.MinIdleDisk)
+	isQueueStarved := state.QueuedSandboxes > 0 && totalIdleRunnersIncludingNascent == 0
+
+	log.WithFields(log.Fields{
+		"utilizationTooHigh": isUtilizationTooHigh,
+		"idleBufferTooLow":   isIdleRunnerBufferTooLow,
+		"cpuIdleTooLow":      isCpuIdleTooLow,
+		"memIdleTooLow":      isMemIdleTooLow,
+		"gpuIdleTooLow":      isGpuIdleTooLow,
+		"diskIdleTooLow":     isDiskIdleTooLow,
+		"queueStarved":       isQueueStarved,
+	}).Infof("Scale-up conditions met: UtilizationTooHigh: %t (CPU: %.2f%%, Mem: %.2f%%), IdleBufferTooLow: %t (%d < %d), CpuIdleTooLow: %t (%.2f < %d), MemIdleTooLow: %t (%.2f < %d), GpuIdleTooLow: %t (%.0f < %d), DiskIdleTooLow: %t (%.2f < %d), QueueStarved: %t (%d %s sandboxes queued)",
 		isUtilizationTooHigh, (metrics.TotalAllocatedCPU/metrics.TotalCPUCapacity)*100, (metrics.TotalAllocatedMemoryGiB/metrics.TotalMemoryGiBCapacity)*100,
 		isIdleRunnerBufferTooLow, totalIdleRunnersIncludingNascent, cfg.MinIdleRunners,
 		isCpuIdleTooLow, metrics.TotalAvailableCPU, cfg.MinIdleCpu,
-		isMemIdleTooLow, metrics.TotalAvailableMemoryGiB, cfg.MinIdleMemory)
+		isMemIdleTooLow, metrics.TotalAvailableMemoryGiB, cfg.MinIdleMemory,
+		isGpuIdleTooLow, metrics.TotalAvailableGPU, cfg.MinIdleGpu,
+		isDiskIdleTooLow, metrics.TotalAvailableDiskGiB, cfg.MinIdleDisk,
+		isQueueStarved, state.QueuedSandboxes, cfg.PoolOS)
+
+	// The trigger is recorded on the placeholders so kubectl describe shows why a node was added
+	var triggers []string
+	if isCpuUtilizationTooHigh {
+		triggers = append(triggers, fmt.Sprintf("CPU utilization above %d%%", cfg.MaxResourceUtilizationPercent))
+	}
+	if isMemUtilizationTooHigh {
+		triggers = append(triggers, fmt.Sprintf("memory utilization above %d%%", cfg.MaxResourceUtilizationPercent))
+	}
+	if isGpuUtilizationTooHigh {
+		triggers = append(triggers, fmt.Sprintf("GPU utilization above %d%%", cfg.MaxResourceUtilizationPercent))
+	}
+	if isDiskUtilizationTooHigh {
+		triggers = append(triggers, fmt.Sprintf("disk utilization above %d%%", cfg.MaxDiskUtilizationPercent))
+	}
+	if isIdleRunnerBufferTooLow {
+		triggers = append(triggers, fmt.Sprintf("fewer idle runners than MIN_IDLE_RUNNERS (%d)", cfg.MinIdleRunners))
+	}
+	if isCpuIdleTooLow {
+		triggers = append(triggers, fmt.Sprintf("less idle CPU than MIN_IDLE_CPU (%d)", cfg.MinIdleCpu))
+	}
+	if isMemIdleTooLow {
+		triggers = append(triggers, fmt.Sprintf("less idle memory than MIN_IDLE_MEMORY (%d GiB)", cfg.MinIdleMemory))
+	}
+	if isGpuIdleTooLow {
+		triggers = append(triggers, fmt.Sprintf("fewer idle GPUs than MIN_IDLE_GPU (%d)", cfg.MinIdleGpu))
+	}
+	if isDiskIdleTooLow {
+		triggers = append(triggers, fmt.Sprintf("less idle disk than MIN_IDLE_DISK (%d GiB)", cfg.MinIdleDisk))
+	}
+	if isQueueStarved {
+		triggers = append(triggers, fmt.Sprintf("%s sandboxes queued with no idle runner", cfg.PoolOS))
+	}
+	trigger := strings.Join(triggers, ", ")
 
 	var nodesNeededFromDeficit int
 
-	if isCpuIdleTooLow && metrics.AvgCpuPerNode > 0 {
-		needed := int(math.Ceil(float64(float32(cfg.MinIdleCpu)-metrics.TotalAvailableCPU) / float64(metrics.AvgCpuPerNode)))
+	// New nodes are of the default profile when the pool has profiles, otherwise they are assumed to be average ones
+	nodeCpu, nodeMem := metrics.AvgCpuPerNode, metrics.AvgMemPerNode
+	if profile := defaultNodeProfile(cfg); profile != nil {
+		nodeCpu, nodeMem = effectiveCapacity(cfg, profile.Cpu, profile.MemoryGiB)
+	}
+	if isCpuIdleTooLow && nodeCpu > 0 {
+		needed := int(math.Ceil(float64(float32(cfg.MinIdleCpu)-metrics.TotalAvailableCPU) / float64(nodeCpu)))
+		state.Why.deficit(status.ConditionIdleCpu, needed)
+		nodesNeededFromDeficit = max(nodesNeededFromDeficit, needed)
+	}
+	if isMemIdleTooLow && nodeMem > 0 {
+		needed := int(math.Ceil(float64(float32(cfg.MinIdleMemory)-metrics.TotalAvailableMemoryGiB) / float64(nodeMem)))
+		state.Why.deficit(status.ConditionIdleMemory, needed)
+		nodesNeededFromDeficit = max(nodesNeededFromDeficit, needed)
+	}
+	if isGpuIdleTooLow && metrics.AvgGpuPerNode > 0 {
+		needed := int(math.Ceil(float64(float32(cfg.MinIdleGpu)-metrics.TotalAvailableGPU) / float64(metrics.AvgGpuPerNode)))
+		state.Why.deficit(status.ConditionIdleGpu, needed)
 		nodesNeededFromDeficit = max(nodesNeededFromDeficit, needed)
 	}
-	if isMemIdleTooLow && metrics.AvgMemPerNode > 0 {
-		needed := int(math.Ceil(float64(float32(cfg.MinIdleMemory)-metrics.TotalAvailableMemoryGiB) / float64(metrics.AvgMemPerNode)))
+	if isDiskIdleTooLow && metrics.AvgDiskPerNode > 0 {
+		needed := int(math.Ceil(float64(float32(cfg.MinIdleDisk)-metrics.TotalAvailableDiskGiB) / float64(metrics.AvgDiskPerNode)))
+		state.Why.deficit(status.ConditionIdleDisk, needed)
 		nodesNeededFromDeficit = max(nodesNeededFromDeficit, needed)
 	}
 	if isIdleRunnerBufferTooLow {
 		needed := cfg.MinIdleRunners - totalIdleRunnersIncludingNascent
+		state.Why.deficit(status.ConditionIdleRunners, needed)
 		nodesNeededFromDeficit = max(nodesNeededFromDeficit, needed)
 	}
 
-	if isUtilizationTooHigh && nodesNeededFromDeficit == 0 {
+	// Without GPU nodes yet their size is unknown, so GPU demand adds one node at a time
+	if (isUtilizationTooHigh || isQueueStarved || isGpuIdleTooLow) && nodesNeededFromDeficit == 0 {
 		nodesNeededFromDeficit = 1
 	}
 
-	nodesToCreate := nodesNeededFromDeficit - len(state.PendingPlaceholders)
+	inFlight := countDefaultProfilePending(cfg, state)
+	nodesToCreate := nodesNeededFromDeficit - inFlight
+	state.Why.scaleUp.NodesNeeded = nodesNeededFromDeficit
+	state.Why.scaleUp.InFlight = inFlight
+
+	// Warm standby runners are registered already, so they are promoted before anything else
+	promoted := 0
+	if nodesToCreate > 0 && len(state.StandbyRunners) > 0 {
+		promoted = promoteStandbyRunners(backend, apiClient, state, nodesToCreate)
+		if promoted > 0 {
+			log.Infof("Triggering scale-up: Promoted %d warm standby runners.", promoted)
+			nodesToCreate -= promoted
+			state.Why.scaleUp.NodesPromoted = promoted
+		}
+	}
+
+	// Hibernated nodes come back much faster than new ones, so they are resumed first
+	resumed := 0
+	if nodesToCreate > 0 && hibernator != nil && len(state.HibernatedNodes) > 0 {
+		resumed = resumeHibernatedNodes(backend, hibernator, state, nodesToCreate)
+		if resumed > 0 {
+			log.Infof("Triggering scale-up: Resumed %d hibernated nodes.", resumed)
+			nodesToCreate -= resumed
+			state.Why.scaleUp.NodesResumed = resumed
+		}
+	}
+
+	if nodesToCreate > 0 {
+		nodesToCreate = capScaleUp(cfg, state, nodesToCreate)
+	}
 
 	if nodesToCreate > 0 {
-		log.Printf("Triggering scale-up: Creating %d placeholder pods. (Calculated need: %d, In-flight: %d)",
-			nodesToCreate, nodesNeededFromDeficit, len(state.PendingPlaceholders))
+		log.Infof("Triggering scale-up: Creating %d placeholder pods. (Calculated need: %d, In-flight: %d)",
+			nodesToCreate, nodesNeededFromDeficit, inFlight)
 		for i := 0; i < nodesToCreate; i++ {
-			if _, err := createPlaceholderPod(clientset, cfg.ProviderNamespace, PlaceholderPodLabel); err != nil {
-				log.Printf("Error creating placeholder pod for scale-up: %v", err)
+			pod, err := createPlaceholderPod(backend, cfg, PlaceholderPodLabel, placeholderOptions{CapacityType: state.CapacityType})
+			if err != nil {
+				log.Errorf("Error creating placeholder pod for scale-up: %v", err)
+				continue
 			}
+			recordPlaceholderEvent(backend, pod, corev1.EventTypeNormal, EventReasonScaleUp, "Placeholder created to add a node: "+trigger)
+			state.PlaceholdersCreated++
+			state.Why.scaleUp.NodesCreated++
 		}
 		return true
 	}
+	if resumed > 0 || promoted > 0 {
+		return true
+	}
 
-	log.Printf("Scale-up conditions met, but no new pods to create (already %d in-flight). Waiting for nodes to provision.", len(state.PendingPlaceholders))
+	log.Infof("Scale-up conditions met, but no new pods to create (already %d in-flight). Waiting for nodes to provision.", inFlight)
 	return false
 }
 
-// handleScaleDown handles scale-down logic
-func handleScaleDown(clientset *kubernetes.Clientset, cfg *Config, state *ClusterState, metrics *ResourceMetrics, needsScaleUp bool) {
+// handleScaleDown handles scale-down logic, removing at most limit nodes when limit is positive. It returns true if
+// any placeholder pod was deleted or node hibernated.
+func handleScaleDown(backend clusterBackend, apiClient *daytona.APIClient, hibernator nodeHibernator, cfg *Config, state *ClusterState, metrics *ResourceMetrics, needsScaleUp bool, limit int) bool {
+	scaled := false
+
 	// First, handle pending placeholders based on resource conditions
 	// If we don't need to scale up and there are pending placeholders, delete them
 	// to prevent unnecessary node provisioning
 	if !needsScaleUp && len(state.PendingPlaceholders) > 0 {
-		log.Printf("No scale-up needed but found %d pending placeholder pods. Deleting them to prevent unnecessary node provisioning.", len(state.PendingPlaceholders))
+		log.Infof("No scale-up needed but found %d pending placeholder pods. Deleting them to prevent unnecessary node provisioning.", len(state.PendingPlaceholders))
 		for _, pendingPod := range state.PendingPlaceholders {
-			log.Printf("Deleting pending placeholder pod %s since scale-up is not needed.", pendingPod.Name)
-			err := clientset.CoreV1().Pods(cfg.ProviderNamespace).Delete(context.Background(), pendingPod.Name, metav1.DeleteOptions{})
+			if zone, found := state.ZoneIdle[pendingPod.Labels[PlaceholderZoneLabel]]; found && !zone.covered() {
+				log.Infof("Keeping pending placeholder pod %s, its zone is still below its idle requirement.", pendingPod.Name)
+				continue
+			}
+			if room, found := state.ClassRoom[pendingPod.Labels[PlaceholderClassLabel]]; found && !room.covered() {
+				log.Infof("Keeping pending placeholder pod %s, sandbox class %s still lacks room.", pendingPod.Name, pendingPod.Labels[PlaceholderClassLabel])
+				continue
+			}
+			if isStandbyPlaceholder(pendingPod) {
+				log.Debugf("Keeping pending placeholder pod %s of the warm buffer.", pendingPod.Name)
+				continue
+			}
+			if profileStillNeeded(cfg, state, pendingPod) {
+				log.Infof("Keeping pending placeholder pod %s, queued sandboxes still need a %s node.", pendingPod.Name, pendingPod.Labels[PlaceholderProfileLabel])
+				continue
+			}
+			log.Infof("Deleting pending placeholder pod %s since scale-up is not needed.", pendingPod.Name)
+			err := backend.DeletePlaceholder(context.Background(), pendingPod.Name)
 			if err != nil {
-				log.Printf("Error deleting pending placeholder pod %s: %v", pendingPod.Name, err)
+				log.Errorf("Error deleting pending placeholder pod %s: %v", pendingPod.Name, err)
+				state.ScaleDownFailures++
+				continue
 			}
+			recordPlaceholderEvent(backend, pendingPod, corev1.EventTypeNormal, EventReasonScaleUpCancelled, "Deleted before its node was added: scale-up is no longer needed")
+			scaled = true
 		}
 	}
 
+	// With a drain timeout, surplus runners are drained here rather than waiting for them to be made unschedulable
+	if state.ScaleDownFreeze == nil && state.ScaleDownBlackout == nil {
+		if cfg.DrainTimeout > 0 && !needsScaleUp && drainSurplusRunner(backend, apiClient, cfg, state, metrics) {
+			scaled = true
+		}
+		finishScaleDownDrains(backend, cfg, state)
+	}
+
 	if len(state.DeletableRunners) == 0 {
-		log.Println("No deletable runners found for scale-down.")
-		return
+		log.Info("No deletable runners found for scale-down.")
+		return scaled
+	}
+
+	if state.ScaleDownFreeze != nil {
+		log.Infof("Scale-down is frozen by directive %s (%s). Keeping %d deletable runners.", state.ScaleDownFreeze.Id, state.ScaleDownFreeze.Reason, len(state.DeletableRunners))
+		message := fmt.Sprintf("Scale-down frozen by directive %s: %s", state.ScaleDownFreeze.Id, state.ScaleDownFreeze.Reason)
+		for _, runner := range state.DeletableRunners {
+			if node, found := state.NodeByIP[runner.GetDomain()]; found {
+				recordScaleDownSkipped(backend, node, message)
+				state.Why.consider(runner, node.Name, status.ScaleDownKept, "frozen", message)
+			}
+		}
+		return scaled
+	}
+
+	if state.ScaleDownBlackout != nil {
+		reason := state.ScaleDownBlackout.reason()
+		log.Infof("Scale-down is disallowed %s. Keeping %d deletable runners.", reason, len(state.DeletableRunners))
+		message := "Scale-down disallowed " + reason
+		for _, runner := range state.DeletableRunners {
+			if node, found := state.NodeByIP[runner.GetDomain()]; found {
+				recordScaleDownSkipped(backend, node, message)
+				state.Why.consider(runner, node.Name, status.ScaleDownKept, "blackout", message)
+			}
+		}
+		return scaled
 	}
 
 	var placeholdersToDeleteInBatch []*corev1.Pod
-	log.Printf("Considering scale-down for %d deletable runners.", len(state.DeletableRunners))
+	runnerByPlaceholder := make(map[string]daytona.RunnerFull)
+	checkEnv := newScaleDownCheckEnv(apiClient, cfg.RegionID)
+	if len(cfg.ScaleDownWeights) > 0 {
+		sortByKeepValue(cfg, state, state.DeletableRunners)
+	} else if isCostAware(cfg) {
+		sortByNodeCost(cfg, state, state.DeletableRunners)
+	}
+	log.Infof("Considering scale-down for %d deletable runners.", len(state.DeletableRunners))
+	zoneSpare := zoneSpareRunners(state)
+	// remaining is the pool without the nodes selected so far, so the nodes removed together stay below
+	// SCALE_DOWN_UTILIZATION_PERCENT
+	remaining := *metrics
 
 	for _, runnerToScaleDown := range state.DeletableRunners {
 		domainToScaleDown := runnerToScaleDown.GetDomain()
 		if domainToScaleDown == "" {
-			log.Printf("Warning: Deletable runner %s has no domain, skipping.", runnerToScaleDown.GetName())
+			log.Warnf("Deletable runner %s has no domain, skipping.", runnerToScaleDown.GetName())
+			state.Why.consider(runnerToScaleDown, "", status.ScaleDownKept, "no-domain", "Runner has no domain")
 			continue
 		}
 
@@ -567,31 +3018,109 @@ func handleScaleDown(clientset *kubernetes.Clientset, cfg *Config, state *Cluste
 			k8sNode = node
 			nodeName = node.Name
 		} else {
-			log.Printf("Warning: Could not find K8s Node for deletable runner with domain %s. Skipping.", domainToScaleDown)
+			log.WithField("domain", domainToScaleDown).Warnf("Could not find K8s Node for deletable runner with domain %s. Skipping.", domainToScaleDown)
+			state.Why.consider(runnerToScaleDown, "", status.ScaleDownKept, "no-node", "No node of the pool has the runner's domain")
+			continue
+		}
+		runnerLog := log.WithFields(log.Fields{"node": nodeName, "domain": domainToScaleDown, "runner": runnerToScaleDown.GetId()})
+
+		// keep records on the node and in the cycle's explanation why the runner is kept
+		keep := func(reason, message string) {
+			recordScaleDownSkipped(backend, k8sNode, message)
+			state.Why.consider(runnerToScaleDown, nodeName, status.ScaleDownKept, reason, message)
+			if cfg.ScaleDownGracePeriod > 0 {
+				cancelScheduledRemoval(backend, k8sNode, message)
+			}
+		}
+
+		if _, hibernated := k8sNode.Annotations[HibernatedAtAnnotation]; hibernated {
+			state.Why.consider(runnerToScaleDown, nodeName, status.ScaleDownKept, "hibernated", "Node is hibernated")
+			continue
+		}
+		if _, resumed := k8sNode.Annotations[ResumedAtAnnotation]; resumed {
+			state.Why.consider(runnerToScaleDown, nodeName, status.ScaleDownKept, "resuming", "Node is resuming from hibernation")
+			continue
+		}
+		if isInMaintenance(k8sNode) {
+			runnerLog.WithField("reason", "maintenance").Debugf("Node %s is in maintenance. Skipping scale-down.", nodeName)
+			state.Why.consider(runnerToScaleDown, nodeName, status.ScaleDownKept, "maintenance", "Node is in maintenance")
+			continue
+		}
+
+		if protection.IsDoNotDisturb(k8sNode.Annotations) {
+			runnerLog.WithField("reason", "do-not-disturb-node").Infof("Node %s (%s) is annotated %s. Skipping scale-down.", nodeName, domainToScaleDown, protection.DoNotDisturbKey)
+			keep("do-not-disturb-node", fmt.Sprintf("Node is annotated %s", protection.DoNotDisturbKey))
+			continue
+		}
+		if state.ProtectedRunnerIDs[runnerToScaleDown.GetId()] {
+			runnerLog.WithField("reason", "do-not-disturb-sandbox").Infof("Runner on node %s (%s) hosts a do-not-disturb sandbox. Skipping scale-down.", nodeName, domainToScaleDown)
+			keep("do-not-disturb-sandbox", "Runner hosts a do-not-disturb sandbox")
+			continue
+		}
+		if runnerTraffic, found := state.RunnerTraffic[runnerToScaleDown.GetId()]; found && cfg.HighTrafficBytesPerSecond > 0 && runnerTraffic.BytesPerSecond >= cfg.HighTrafficBytesPerSecond {
+			runnerLog.WithField("reason", "high-traffic").Infof("Runner on node %s (%s) serves high preview traffic (%.0f B/s). Skipping scale-down.", nodeName, domainToScaleDown, runnerTraffic.BytesPerSecond)
+			keep("high-traffic", fmt.Sprintf("Runner serves more preview traffic than HIGH_TRAFFIC_BYTES_PER_SECOND (%.0f)", cfg.HighTrafficBytesPerSecond))
 			continue
 		}
 
 		nodeCpuCapacity, nodeMemCapacity, err := getNodeAllocatableResources(k8sNode)
 		if err != nil {
-			log.Printf("Warning: Could not get allocatable resources for K8s Node %s: %v. Skipping scale-down check.", nodeName, err)
+			runnerLog.Warnf("Could not get allocatable resources for K8s Node %s: %v. Skipping scale-down check.", nodeName, err)
+			state.Why.consider(runnerToScaleDown, nodeName, status.ScaleDownKept, "allocatable", fmt.Sprintf("Could not get the node's allocatable resources: %v", err))
 			continue
 		}
+		nodeCpuCapacity, nodeMemCapacity = effectiveCapacity(cfg, nodeCpuCapacity, nodeMemCapacity)
 
 		// Scale-down safety check
 		hypotheticalAvailableCpu := metrics.TotalAvailableCPU - nodeCpuCapacity
 		hypotheticalAvailableMemoryGiB := metrics.TotalAvailableMemoryGiB - nodeMemCapacity
+		hypotheticalAvailableGpu := metrics.TotalAvailableGPU - getNodeAllocatableGPUs(k8sNode)
+		hypotheticalAvailableDiskGiB := metrics.TotalAvailableDiskGiB - runnerToScaleDown.GetDisk()
 
-		isSafeToDelete := true
+		var violations []string
 		if hypotheticalAvailableCpu < float32(cfg.MinIdleCpu) {
-			log.Printf("Scale-down of %s (%s) would violate MIN_IDLE_CPU (would be %.2f, min is %d). Skipping.", nodeName, domainToScaleDown, hypotheticalAvailableCpu, cfg.MinIdleCpu)
-			isSafeToDelete = false
+			runnerLog.WithField("reason", "min-idle-cpu").Infof("Scale-down of %s (%s) would violate MIN_IDLE_CPU (would be %.2f, min is %d). Skipping.", nodeName, domainToScaleDown, hypotheticalAvailableCpu, cfg.MinIdleCpu)
+			violations = append(violations, fmt.Sprintf("MIN_IDLE_CPU (%d)", cfg.MinIdleCpu))
 		}
 		if hypotheticalAvailableMemoryGiB < float32(cfg.MinIdleMemory) {
-			log.Printf("Scale-down of %s (%s) would violate MIN_IDLE_MEMORY (would be %.2f, min is %d). Skipping.", nodeName, domainToScaleDown, hypotheticalAvailableMemoryGiB, cfg.MinIdleMemory)
-			isSafeToDelete = false
+			runnerLog.WithField("reason", "min-idle-memory").Infof("Scale-down of %s (%s) would violate MIN_IDLE_MEMORY (would be %.2f, min is %d). Skipping.", nodeName, domainToScaleDown, hypotheticalAvailableMemoryGiB, cfg.MinIdleMemory)
+			violations = append(violations, fmt.Sprintf("MIN_IDLE_MEMORY (%d GiB)", cfg.MinIdleMemory))
+		}
+		if cfg.MinIdleGpu > 0 && hypotheticalAvailableGpu < float32(cfg.MinIdleGpu) {
+			runnerLog.WithField("reason", "min-idle-gpu").Infof("Scale-down of %s (%s) would violate MIN_IDLE_GPU (would be %.0f, min is %d). Skipping.", nodeName, domainToScaleDown, hypotheticalAvailableGpu, cfg.MinIdleGpu)
+			violations = append(violations, fmt.Sprintf("MIN_IDLE_GPU (%d)", cfg.MinIdleGpu))
+		}
+		if cfg.MinIdleDisk > 0 && hypotheticalAvailableDiskGiB < float32(cfg.MinIdleDisk) {
+			runnerLog.WithField("reason", "min-idle-disk").Infof("Scale-down of %s (%s) would violate MIN_IDLE_DISK (would be %.2f, min is %d). Skipping.", nodeName, domainToScaleDown, hypotheticalAvailableDiskGiB, cfg.MinIdleDisk)
+			violations = append(violations, fmt.Sprintf("MIN_IDLE_DISK (%d GiB)", cfg.MinIdleDisk))
+		}
+
+		if len(violations) > 0 {
+			keep("min-idle", "Removing the node would leave the pool below "+strings.Join(violations, ", "))
+			continue
+		}
+
+		withoutNode := remaining
+		withoutNode.TotalCPUCapacity -= nodeCpuCapacity
+		withoutNode.TotalMemoryGiBCapacity -= nodeMemCapacity
+		withoutNode.TotalGPUCapacity -= getNodeAllocatableGPUs(k8sNode)
+		if isAboveScaleDownUtilization(cfg, &withoutNode) {
+			runnerLog.WithField("reason", "scale-down-utilization").Infof("Scale-down of %s (%s) would raise utilization to SCALE_DOWN_UTILIZATION_PERCENT (%d%%). Skipping.", nodeName, domainToScaleDown, cfg.ScaleDownUtilizationPercent)
+			keep("scale-down-utilization", fmt.Sprintf("Removing the node would raise utilization to SCALE_DOWN_UTILIZATION_PERCENT (%d%%)", cfg.ScaleDownUtilizationPercent))
+			continue
 		}
 
-		if !isSafeToDelete {
+		if isLastSpareInZone(cfg, zoneSpare, k8sNode) {
+			runnerLog.WithField("reason", "zone-balance").Infof("Runner on node %s (%s) is the last one without sandboxes in zone %s. Skipping scale-down.", nodeName, domainToScaleDown, k8sNode.Labels[ZoneLabel])
+			keep("zone-balance", fmt.Sprintf("Runner is the last one without sandboxes in zone %s", k8sNode.Labels[ZoneLabel]))
+			continue
+		}
+
+		// Checked last as the checks call the Daytona and runner APIs
+		if check, reason := runScaleDownChecks(cfg, checkEnv, runnerToScaleDown); check != "" {
+			runnerLog.WithField("reason", "check-"+check).Infof("Scale-down of %s (%s) blocked by the %s check: %s. Retrying next cycle.", nodeName, domainToScaleDown, check, reason)
+			scaleDownBlocked.WithLabelValues(check).Inc()
+			keep("check-"+check, fmt.Sprintf("Blocked by the %s check: %s", check, reason))
 			continue
 		}
 
@@ -606,25 +3135,126 @@ func handleScaleDown(clientset *kubernetes.Clientset, cfg *Config, state *Cluste
 
 		if placeholderFound != nil {
 			placeholdersToDeleteInBatch = append(placeholdersToDeleteInBatch, placeholderFound)
-			log.Printf("Identified placeholder pod %s on node %s for deletion (runner domain %s). Safe to delete.", placeholderFound.Name, nodeName, domainToScaleDown)
+			runnerByPlaceholder[placeholderFound.Name] = runnerToScaleDown
+			zoneSpare[k8sNode.Labels[ZoneLabel]]--
+			remaining = withoutNode
+			state.Why.consider(runnerToScaleDown, nodeName, status.ScaleDownRemoved, "", "")
+			runnerLog.WithField("placeholder", placeholderFound.Name).Infof("Identified placeholder pod %s on node %s for deletion (runner domain %s). Safe to delete.", placeholderFound.Name, nodeName, domainToScaleDown)
 		} else {
-			log.Printf("Warning: Could not find a scheduled placeholder pod on node %s for deletable runner with domain %s. It might have been manually removed or never properly created. Skipping deletion of Daytona runner.", nodeName, domainToScaleDown)
+			runnerLog.Warnf("Could not find a scheduled placeholder pod on node %s for deletable runner with domain %s. It might have been manually removed or never properly created. Skipping deletion of Daytona runner.", nodeName, domainToScaleDown)
+			recordNodeEvent(backend, k8sNode, corev1.EventTypeWarning, EventReasonScaleDownSkipped, "No scheduled placeholder pod found on the node, it cannot be removed")
+			state.Why.consider(runnerToScaleDown, nodeName, status.ScaleDownKept, "no-placeholder", "No scheduled placeholder pod found on the node")
+		}
+	}
+
+	if limit > 0 && len(placeholdersToDeleteInBatch) > limit {
+		log.Infof("Limiting scale-down to %d of %d safe-to-delete nodes.", limit, len(placeholdersToDeleteInBatch))
+		message := fmt.Sprintf("Scale-down limited to %d nodes this cycle", limit)
+		for _, pod := range placeholdersToDeleteInBatch[limit:] {
+			if node := findNodeByName(state, pod.Spec.NodeName); node != nil {
+				recordScaleDownSkipped(backend, node, message)
+			}
+			state.Why.consider(runnerByPlaceholder[pod.Name], pod.Spec.NodeName, status.ScaleDownKept, "limit", message)
+		}
+		placeholdersToDeleteInBatch = placeholdersToDeleteInBatch[:limit]
+	}
+
+	// Right before removal the runners are made unschedulable again and their allocation compared to the gathered one,
+	// so a sandbox placed on a runner since the state was gathered is not lost with its node
+	confirmed := placeholdersToDeleteInBatch[:0]
+	for _, pod := range placeholdersToDeleteInBatch {
+		runner := runnerByPlaceholder[pod.Name]
+		runnerLog := log.WithFields(log.Fields{"node": pod.Spec.NodeName, "runner": runner.GetId()})
+		reason, err := confirmRunnerUnschedulable(apiClient, runner)
+		if err != nil {
+			runnerLog.Errorf("Error confirming runner %s is unschedulable, keeping node %s: %v", runner.GetId(), pod.Spec.NodeName, err)
+			state.Why.consider(runner, pod.Spec.NodeName, status.ScaleDownKept, "confirm-failed", fmt.Sprintf("Could not confirm the runner is unschedulable: %v", err))
+			continue
+		}
+		if reason != "" {
+			runnerLog.WithField("reason", "allocation-changed").Infof("Runner %s on node %s changed since the cluster state was gathered: %s. Retrying next cycle.", runner.GetId(), pod.Spec.NodeName, reason)
+			scaleDownBlocked.WithLabelValues("allocation-changed").Inc()
+			if node := findNodeByName(state, pod.Spec.NodeName); node != nil {
+				recordScaleDownSkipped(backend, node, "Runner changed since the cluster state was gathered: "+reason)
+				if cfg.ScaleDownGracePeriod > 0 {
+					cancelScheduledRemoval(backend, node, "runner changed since the cluster state was gathered: "+reason)
+				}
+			}
+			state.Why.consider(runner, pod.Spec.NodeName, status.ScaleDownKept, "allocation-changed", "Runner changed since the cluster state was gathered: "+reason)
+			continue
+		}
+		confirmed = append(confirmed, pod)
+	}
+	placeholdersToDeleteInBatch = confirmed
+
+	// With a grace period, the nodes are first scheduled for removal and only removed once their runner stayed
+	// deletable for the whole period, a last safety window beyond the allocations confirmed above
+	if cfg.ScaleDownGracePeriod > 0 {
+		graced := placeholdersToDeleteInBatch[:0]
+		for _, pod := range placeholdersToDeleteInBatch {
+			node := findNodeByName(state, pod.Spec.NodeName)
+			if node == nil {
+				continue
+			}
+			runner := runnerByPlaceholder[pod.Name]
+			remaining, err := awaitRemovalGrace(backend, cfg, node)
+			if err != nil {
+				log.WithField("node", node.Name).Errorf("Error scheduling node %s for removal, retrying next cycle: %v", node.Name, err)
+				state.Why.consider(runner, node.Name, status.ScaleDownKept, "schedule-failed", fmt.Sprintf("Could not schedule the node for removal: %v", err))
+				continue
+			}
+			if remaining > 0 {
+				log.WithFields(log.Fields{"node": node.Name, "runner": runner.GetId()}).Infof("Node %s is scheduled for removal in %s.", node.Name, remaining.Round(time.Second))
+				state.Why.consider(runner, node.Name, status.ScaleDownKept, "grace-period", fmt.Sprintf("Scheduled for removal in %s", remaining.Round(time.Second)))
+				continue
+			}
+			graced = append(graced, pod)
 		}
+		placeholdersToDeleteInBatch = graced
 	}
 
-	// Execute batch deletion
+	// Execute batch deletion, hibernating nodes instead while below the hibernation limit
+	hibernatedCount := len(state.HibernatedNodes)
 	for _, pod := range placeholdersToDeleteInBatch {
-		log.Printf("Deleting placeholder pod %s for scale-down.", pod.Name)
-		err := clientset.CoreV1().Pods(cfg.ProviderNamespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
+		if hibernator != nil && hibernatedCount < cfg.HibernationMaxNodes {
+			if node := findNodeByName(state, pod.Spec.NodeName); node != nil {
+				if err := hibernateNode(backend, apiClient, hibernator, state, node); err != nil {
+					log.WithField("node", node.Name).Errorf("Error hibernating node %s, deleting it instead: %v", node.Name, err)
+				} else {
+					log.WithFields(log.Fields{"node": node.Name, "placeholder": pod.Name}).Infof("Hibernated node %s instead of deleting placeholder pod %s.", node.Name, pod.Name)
+					recordNodeEvent(backend, node, corev1.EventTypeNormal, EventReasonScaleDown, "Selected for scale-down: runner is unschedulable and idle, hibernating the node")
+					// A hibernated node is resumed rather than removed, so it is no longer scheduled for removal
+					if cfg.ScaleDownGracePeriod > 0 {
+						if err := markScheduledForRemoval(backend, node, false); err != nil {
+							log.WithField("node", node.Name).Errorf("Error clearing the scheduled removal of hibernated node %s: %v", node.Name, err)
+						}
+					}
+					state.Why.consider(runnerByPlaceholder[pod.Name], node.Name, status.ScaleDownHibernated, "", "")
+					hibernatedCount++
+					continue
+				}
+			}
+		}
+
+		log.WithFields(log.Fields{"node": pod.Spec.NodeName, "placeholder": pod.Name}).Infof("Deleting placeholder pod %s for scale-down.", pod.Name)
+		message := fmt.Sprintf("Selected for scale-down: runner is unschedulable and idle, deleting placeholder pod %s", pod.Name)
+		if node := findNodeByName(state, pod.Spec.NodeName); node != nil {
+			recordNodeEvent(backend, node, corev1.EventTypeNormal, EventReasonScaleDown, message)
+		}
+		recordPlaceholderEvent(backend, pod, corev1.EventTypeNormal, EventReasonScaleDown, message)
+		err := backend.DeletePlaceholder(context.Background(), pod.Name)
 		if err != nil {
-			log.Printf("Error deleting placeholder pod %s: %v", pod.Name, err)
+			log.WithField("placeholder", pod.Name).Errorf("Error deleting placeholder pod %s: %v", pod.Name, err)
+			state.ScaleDownFailures++
+			state.Why.consider(runnerByPlaceholder[pod.Name], pod.Spec.NodeName, status.ScaleDownKept, "delete-failed", fmt.Sprintf("Could not delete placeholder pod %s: %v", pod.Name, err))
 		}
 	}
 	if len(placeholdersToDeleteInBatch) > 0 {
-		log.Printf("Successfully initiated deletion of %d placeholder pods for scale-down.", len(placeholdersToDeleteInBatch))
+		log.Infof("Successfully initiated scale-down of %d nodes.", len(placeholdersToDeleteInBatch))
 	} else {
-		log.Println("No safe-to-delete placeholder pods identified for scale-down in this cycle.")
+		log.Info("No safe-to-delete placeholder pods identified for scale-down in this cycle.")
 	}
+	return scaled || len(placeholdersToDeleteInBatch) > 0
 }
 
 // getNodeAllocatableResources queries a Kubernetes Node object and returns its allocatable CPU (in cores) and Memory (in GiB).
@@ -645,6 +3275,16 @@ func getNodeAllocatableResources(node *corev1.Node) (cpuCores float32, memoryGiB
 	return cpuCores, memoryGiB, nil
 }
 
+// findNodeByName returns the pool node with the given name, or nil if it is not part of the pool
+func findNodeByName(state *ClusterState, nodeName string) *corev1.Node {
+	for i := range state.Nodes {
+		if state.Nodes[i].Name == nodeName {
+			return &state.Nodes[i]
+		}
+	}
+	return nil
+}
+
 // extractNodeIPs extracts IP addresses directly from a node object
 func extractNodeIPs(node *corev1.Node) []string {
 	var ips []string
@@ -654,65 +3294,19 @@ func extractNodeIPs(node *corev1.Node) []string {
 	return ips
 }
 
-// createPlaceholderPod creates a Kubernetes Pod that acts as a placeholder to trigger cluster autoscaling.
-func createPlaceholderPod(clientset *kubernetes.Clientset, namespace, appName string) (*corev1.Pod, error) {
+// createPlaceholderPod creates a placeholder on the cluster backend to trigger autoscaling of the infrastructure.
+// A zone pins the placeholder, and so the node it brings up, to that availability zone, and a capacity type to spot
+// or on-demand nodes.
+func createPlaceholderPod(backend clusterBackend, cfg *Config, appName string, options placeholderOptions) (*corev1.Pod, error) {
 	podName := fmt.Sprintf("%s-%s", appName, strings.ToLower(generateRandomString(8))) // Unique name
-	log.Printf("Creating placeholder pod %s in namespace %s", podName, namespace)
-
-	pod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      podName,
-			Namespace: namespace,
-			Labels: map[string]string{
-				"app": appName, // Label to easily find these pods later
-			},
-		},
-		Spec: corev1.PodSpec{
-			Affinity: &corev1.Affinity{
-				PodAntiAffinity: &corev1.PodAntiAffinity{
-					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
-						{
-							LabelSelector: &metav1.LabelSelector{
-								MatchExpressions: []metav1.LabelSelectorRequirement{
-									{
-										Key:      "app",
-										Operator: metav1.LabelSelectorOpIn,
-										Values:   []string{appName},
-									},
-								},
-							},
-							TopologyKey: "kubernetes.io/hostname",
-						},
-					},
-				},
-			},
-			NodeSelector: map[string]string{
-				NodeSelectorKey: "true",
-			},
-			Tolerations: []corev1.Toleration{
-				{
-					Key:      TaintKey,
-					Operator: corev1.TolerationOpEqual,
-					Value:    "true",
-					Effect:   corev1.TaintEffectNoExecute,
-				},
-			},
-			Containers: []corev1.Container{
-				{
-					Name:  "pause",
-					Image: "rancher/pause:3.6", // A very small, stable image
-				},
-			},
-			RestartPolicy: corev1.RestartPolicyNever, // Don't restart if it completes
-		},
-	}
+	log.Infof("Creating placeholder pod %s in namespace %s", podName, cfg.ProviderNamespace)
 
-	createdPod, err := clientset.CoreV1().Pods(namespace).Create(context.Background(), pod, metav1.CreateOptions{})
+	createdPod, err := backend.CreatePlaceholder(context.Background(), podName, appName, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create placeholder pod %s: %w", podName, err)
 	}
 
-	log.Printf("Successfully created placeholder pod %s", createdPod.Name)
+	log.Infof("Successfully created placeholder pod %s", createdPod.Name)
 	return createdPod, nil
 }
 
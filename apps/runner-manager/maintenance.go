@@ -0,0 +1,120 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// MaintenanceAnnotation on a pool node requests its planned maintenance, its value is an optional reason
+	MaintenanceAnnotation = "daytona.io/maintenance"
+
+	// MaintenanceStartedAtAnnotation records when runner-manager cordoned the node and drained its runner for
+	// maintenance, so both are undone once the maintenance annotation is removed
+	MaintenanceStartedAtAnnotation = "daytona.io/maintenance-started-at"
+
+	// EventReasonMaintenance is the reason of the events recorded when a node enters or leaves maintenance
+	EventReasonMaintenance = "Maintenance"
+)
+
+// reconcileMaintenance coordinates planned node maintenance through MaintenanceAnnotation. An annotated node is
+// cordoned so no placeholder lands on it, and its runner is made unschedulable so its sandboxes drain and its
+// capacity is no longer counted, which lets the regular scale-up replace it. The node is never removed by the
+// scale-down meanwhile. Once the annotation is removed, the node is uncordoned and its runner made schedulable again.
+// It runs before the resource metrics are calculated so they account for the change.
+func reconcileMaintenance(backend clusterBackend, apiClient *daytona.APIClient, state *ClusterState) {
+	runnerByNode := make(map[string]daytona.RunnerFull)
+	for _, runner := range state.Runners {
+		if node, found := state.NodeByIP[runner.GetDomain()]; found {
+			runnerByNode[node.Name] = runner
+		}
+	}
+
+	inMaintenance := 0
+	for i := range state.Nodes {
+		node := &state.Nodes[i]
+		if _, hibernated := node.Annotations[HibernatedAtAnnotation]; hibernated {
+			continue
+		}
+		reason, requested := node.Annotations[MaintenanceAnnotation]
+		_, started := node.Annotations[MaintenanceStartedAtAnnotation]
+		runner, hasRunner := runnerByNode[node.Name]
+		nodeLog := log.WithField("node", node.Name)
+
+		switch {
+		case requested && !started:
+			startedAt := time.Now().UTC().Format(time.RFC3339)
+			unschedulable := true
+			if err := backend.PatchNode(context.Background(), node.Name, map[string]*string{MaintenanceStartedAtAnnotation: &startedAt}, &unschedulable); err != nil {
+				nodeLog.Errorf("Error cordoning node %s for maintenance: %v", node.Name, err)
+				continue
+			}
+			node.Spec.Unschedulable = true
+			node.Annotations[MaintenanceStartedAtAnnotation] = startedAt
+
+			message := "Cordoned for maintenance"
+			if reason != "" {
+				message += ": " + reason
+			}
+			if hasRunner && !runner.GetUnschedulable() {
+				if err := updateRunnerScheduling(apiClient, runner.GetId(), true); err != nil {
+					nodeLog.Errorf("Error marking runner %s unschedulable for the maintenance of node %s, retrying next cycle: %v", runner.GetId(), node.Name, err)
+				} else {
+					markRunnerUnschedulable(state, runner.GetId())
+					message += fmt.Sprintf(", runner %s is unschedulable and draining %.0f started sandboxes", runner.GetId(), runner.GetCurrentStartedSandboxes())
+				}
+			}
+			nodeLog.Infof("Node %s is annotated %s. %s.", node.Name, MaintenanceAnnotation, message)
+			recordNodeEvent(backend, node, corev1.EventTypeNormal, EventReasonMaintenance, message)
+			inMaintenance++
+
+		case requested:
+			// A runner made schedulable again by hand, or that failed to be marked, is drained again
+			if hasRunner && !runner.GetUnschedulable() {
+				if err := updateRunnerScheduling(apiClient, runner.GetId(), true); err != nil {
+					nodeLog.Errorf("Error marking runner %s unschedulable for the maintenance of node %s: %v", runner.GetId(), node.Name, err)
+				} else {
+					markRunnerUnschedulable(state, runner.GetId())
+				}
+			}
+			inMaintenance++
+
+		case started:
+			unschedulable := false
+			if err := backend.PatchNode(context.Background(), node.Name, map[string]*string{MaintenanceStartedAtAnnotation: nil}, &unschedulable); err != nil {
+				nodeLog.Errorf("Error uncordoning node %s after maintenance: %v", node.Name, err)
+				inMaintenance++
+				continue
+			}
+			node.Spec.Unschedulable = false
+			delete(node.Annotations, MaintenanceStartedAtAnnotation)
+
+			message := "Maintenance finished, uncordoned"
+			if hasRunner && runner.GetUnschedulable() {
+				if err := updateRunnerScheduling(apiClient, runner.GetId(), false); err != nil {
+					nodeLog.Errorf("Error making runner %s schedulable after the maintenance of node %s: %v", runner.GetId(), node.Name, err)
+				} else {
+					message += fmt.Sprintf(", runner %s is schedulable again", runner.GetId())
+				}
+			}
+			nodeLog.Infof("Node %s is no longer annotated %s. %s.", node.Name, MaintenanceAnnotation, message)
+			recordNodeEvent(backend, node, corev1.EventTypeNormal, EventReasonMaintenance, message)
+		}
+	}
+	maintenanceNodes.Set(float64(inMaintenance))
+}
+
+// isInMaintenance reports whether the node is annotated for maintenance, which keeps the scale-down from removing it
+func isInMaintenance(node *corev1.Node) bool {
+	_, requested := node.Annotations[MaintenanceAnnotation]
+	return requested
+}
@@ -0,0 +1,547 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Gauge tracking the CPU steal reported by each node's capacity reporter
+	nodeCpuStealPercent = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_node_cpu_steal_percent",
+			Help: "CPU time stolen by the hypervisor as reported by the node's capacity reporter",
+		},
+		[]string{"node"},
+	)
+
+	// Gauge tracking the IO pressure reported by each node's capacity reporter
+	nodeIOPressurePercent = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_node_io_pressure_percent",
+			Help: "10s average of the share of time tasks were stalled on IO as reported by the node's capacity reporter",
+		},
+		[]string{"node"},
+	)
+
+	// Gauge flagging configuration fields that differ from the region configuration in the Daytona API
+	configDriftDetected = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_config_drift",
+			Help: "Set to 1 for each configuration field that differs from the region configuration stored in the Daytona API",
+		},
+		[]string{"field"},
+	)
+
+	// Gauge tracking how many active runners could be freed by consolidating sandboxes
+	defragmentationOpportunityNodes = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_defragmentation_opportunity_nodes",
+			Help: "Number of active runners that could be freed if sandboxes were perfectly packed",
+		},
+	)
+
+	// Counter of state changes observed between controller cycles
+	stateChanges = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "runner_manager_state_changes_total",
+			Help: "Number of runner and node changes observed between controller cycles",
+		},
+		[]string{"kind"},
+	)
+
+	// Counter of controller cycles that found sandboxes queued for capacity
+	missedCapacityIncidents = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "runner_manager_missed_capacity_incidents_total",
+			Help: "Number of controller cycles that found sandboxes queued due to insufficient runner capacity, not counting creates the API rejected",
+		},
+	)
+
+	// Gauge tracking the idle buffer in effect after idle tuning
+	effectiveMinIdle = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_effective_min_idle",
+			Help: "Idle buffer currently in effect by resource, including idle tuning adjustments",
+		},
+		[]string{"resource"},
+	)
+
+	// Gauge tracking the reserved capacity organizations do not use, kept idle on top of the idle buffer
+	reservedIdle = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_reserved_idle_capacity",
+			Help: "Capacity reserved for an organization and not used by its started sandboxes, by resource",
+		},
+		[]string{"organization", "resource"},
+	)
+
+	// Gauges tracking the idle runners and requirement of each zone with a per-zone idle requirement
+	zoneIdleRunners = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_zone_idle_runners",
+			Help: "Number of idle runners per availability zone",
+		},
+		[]string{"zone"},
+	)
+	zoneMinIdleRunners = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_zone_min_idle_runners",
+			Help: "Required number of idle runners per availability zone",
+		},
+		[]string{"zone"},
+	)
+
+	// Gauges tracking the room and requirement of each reserved sandbox class
+	sandboxClassRoom = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_sandbox_class_room",
+			Help: "Number of sandboxes of a reserved class fitting on the schedulable runners",
+		},
+		[]string{"class"},
+	)
+	sandboxClassRequired = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_sandbox_class_required",
+			Help: "Required number of sandboxes of a reserved class fitting on the schedulable runners",
+		},
+		[]string{"class"},
+	)
+
+	// Gauge tracking the sandboxes waiting for capacity by requested operating system
+	queuedSandboxes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_queued_sandboxes",
+			Help: "Number of sandboxes in the region waiting for capacity by requested operating system",
+		},
+		[]string{"os"},
+	)
+
+	// Gauge tracking the resources requested by the sandboxes waiting for capacity
+	backlogResources = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_sandbox_backlog_resources",
+			Help: "Resources requested by the sandboxes in the region waiting for capacity, in CPU, memory GiB, disk GiB or GPUs, by requested operating system",
+		},
+		[]string{"os", "resource"},
+	)
+
+	// Gauge tracking the sandboxes still hosted on each node whose runner is draining
+	drainRemainingSandboxes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_drain_remaining_sandboxes",
+			Help: "Number of sandboxes still hosted on a draining node",
+		},
+		[]string{"node"},
+	)
+
+	// Gauge tracking nodes that were hibernated instead of removed on scale-down
+	hibernatedNodes = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_hibernated_nodes",
+			Help: "Number of pool nodes currently hibernated and available for resume on scale-up",
+		},
+	)
+
+	// Gauge tracking nodes held out of the pool for planned maintenance
+	maintenanceNodes = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_maintenance_nodes",
+			Help: "Number of pool nodes annotated daytona.io/maintenance and held out of the pool's capacity",
+		},
+	)
+
+	// Gauges tracking free resources that cannot be used because the other dimension is exhausted
+	strandedCpu = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_stranded_cpu",
+			Help: "Free CPUs across schedulable runners that cannot host the average sandbox shape",
+		},
+	)
+	strandedMemoryGiB = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_stranded_memory_gib",
+			Help: "Free memory in GiB across schedulable runners that cannot host the average sandbox shape",
+		},
+	)
+
+	// Counter of controller cycle triggers, by interval, by observed cluster change and by manual action
+	controllerTriggers = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "runner_manager_controller_triggers_total",
+			Help: "Total number of times a controller cycle was queued, by trigger",
+		},
+		[]string{"trigger"},
+	)
+
+	// Counter of scale-down candidates kept because a scale-down check blocked their removal
+	scaleDownBlocked = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "runner_manager_scale_down_blocked_total",
+			Help: "Total number of times a scale-down check blocked removing a node, by check (allocation-changed when the runner changed right before its removal)",
+		},
+		[]string{"check"},
+	)
+
+	// Counter of manual changes to managed nodes and placeholder pods denied by the admission webhook
+	admissionDenials = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "runner_manager_admission_denials_total",
+			Help: "Total number of manual deletions, cordons and evictions denied by the admission webhook, by resource",
+		},
+		[]string{"resource"},
+	)
+
+	// Counter of lifecycle webhook deliveries by event type and result
+	lifecycleWebhookDeliveries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "runner_manager_lifecycle_webhook_deliveries_total",
+			Help: "Total number of lifecycle events delivered, failed after all attempts or dropped, by event type",
+		},
+		[]string{"event", "result"},
+	)
+
+	// Gauge of the schedule entry overriding the idle thresholds, 1 for the active entry
+	scalingScheduleActive = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_scaling_schedule_active",
+			Help: "Whether the scaling schedule entry is the one overriding the idle thresholds",
+		},
+		[]string{"schedule"},
+	)
+
+	// Gauge of the demand forecast by predictive scaling, by resource
+	predictedDemand = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_predicted_demand",
+			Help: "Demand forecast at the end of the predictive lead time, in CPU, memory GiB or active runners",
+		},
+		[]string{"resource"},
+	)
+
+	// Gauge of the demand growth tracked by velocity scaling, by resource
+	demandGrowthRate = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_demand_growth_per_minute",
+			Help: "Smoothed growth of the allocated CPU, memory GiB or started sandboxes per minute, tracked by velocity scaling",
+		},
+		[]string{"resource"},
+	)
+
+	// Counter of scale-ups reduced or blocked by the cluster size caps
+	scaleUpCapped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "runner_manager_scale_up_capped_total",
+			Help: "Total number of times MAX_NODES or MAX_RUNNERS reduced or blocked a needed scale-up, by limit",
+		},
+		[]string{"limit"},
+	)
+
+	// Counter of reloads of the mounted ConfigMap thresholds, by result
+	configReloads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "runner_manager_config_reloads_total",
+			Help: "Total number of changes of the CONFIG_DIR thresholds detected, by result (success or error)",
+		},
+		[]string{"result"},
+	)
+
+	// Counter of drains of surplus runners started by the scale-down, by outcome
+	scaleDownDrains = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "runner_manager_scale_down_drains_total",
+			Help: "Total number of scale-down drains by result (started, drained, timed_out or cancelled)",
+		},
+		[]string{"result"},
+	)
+
+	// Gauge tracking the runners drained by the scale-down that still host started sandboxes
+	scaleDownDrainsInProgress = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_scale_down_drains_in_progress",
+			Help: "Number of runners made unschedulable by the scale-down that still host started sandboxes",
+		},
+	)
+
+	// Gauge tracking how long each nascent node has hosted a placeholder without a registered runner
+	nascentNodeAge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_nascent_node_age_seconds",
+			Help: "Time since the node was first seen hosting a placeholder without a registered runner",
+		},
+		[]string{"node"},
+	)
+
+	// Gauges tracking the runners whose reported capacity drifts from their node's allocatable resources
+	capacityDriftNodes = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_capacity_drift_nodes",
+			Help: "Number of nodes whose runner reports a capacity diverging from the node's allocatable resources",
+		},
+	)
+	capacityDriftPercent = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_capacity_drift_percent",
+			Help: "Divergence of the runner-reported capacity from the node's allocatable resources, by node and resource",
+		},
+		[]string{"node", "resource"},
+	)
+
+	// Gauges tracking the runners reported unhealthy and the ones being remediated
+	unhealthyRunners = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_unhealthy_runners",
+			Help: "Number of runners the Daytona API reports unhealthy",
+		},
+	)
+	remediatingRunners = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_remediating_runners",
+			Help: "Number of unhealthy runners whose node is cordoned while they are restarted",
+		},
+	)
+
+	// Counter of health remediation steps by action
+	healthRemediations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "runner_manager_health_remediations_total",
+			Help: "Total number of health remediation steps taken on unhealthy runners, by action",
+		},
+		[]string{"action"},
+	)
+
+	// Counter of nascent nodes cordoned because their runner did not register within NASCENT_NODE_TIMEOUT
+	nascentNodeTimeouts = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "runner_manager_nascent_node_timeouts_total",
+			Help: "Total number of nodes cordoned and released because no runner registered from them within NASCENT_NODE_TIMEOUT",
+		},
+	)
+
+	// Counter of runners deregistered because their node was gone
+	missingNodeDeregistrations = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "runner_manager_missing_node_deregistrations_total",
+			Help: "Total number of runners deregistered from the Daytona API after matching no node for MISSING_NODE_DEREGISTER_CYCLES cycles",
+		},
+	)
+
+	// Gauge tracking the runners whose version differs from the rollout's target version
+	rolloutOutdatedRunners = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_rollout_outdated_runners",
+			Help: "Number of runners not running the target version of the rollout, including the ones being replaced",
+		},
+	)
+
+	// Counter of runners made unschedulable to be replaced by the rollout
+	rolloutReplacements = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "runner_manager_rollout_replacements_total",
+			Help: "Total number of outdated runners the rollout started replacing",
+		},
+	)
+
+	// Gauge tracking the nodes of a migrating pool on the old and the new node pool
+	migrationNodes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_migration_nodes",
+			Help: "Number of nodes of a migrating pool by generation (old or new)",
+		},
+		[]string{"pool", "generation"},
+	)
+
+	// Counter of runners of old nodes made unschedulable to be replaced on the new node pool
+	migrationReplacements = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "runner_manager_migration_replacements_total",
+			Help: "Total number of runners of old nodes the migration started replacing",
+		},
+	)
+
+	// Counter of node group resizes by the node provisioner
+	nodeProvisionerCalls = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "runner_manager_node_provisioner_calls_total",
+			Help: "Total number of node group resizes by the node provisioner by operation (add or remove) and result",
+		},
+		[]string{"operation", "result"},
+	)
+
+	// Gauge for the estimated hourly cost of the running nodes by region and pool
+	fleetHourlyCost = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_fleet_hourly_cost",
+			Help: "Estimated hourly cost of the running nodes of the pool, from NODE_HOURLY_PRICE_LABEL or NODE_HOURLY_PRICES",
+		},
+		[]string{"region", "pool"},
+	)
+
+	// Gauge for the estimated cost of the running nodes since the start of the month by region and pool
+	fleetMonthToDateCost = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_fleet_month_to_date_cost",
+			Help: "Estimated cost of the running nodes of the pool since the start of the month, or of the process if it started later",
+		},
+		[]string{"region", "pool"},
+	)
+
+	// Gauge for the number of running nodes without a known price by region and pool
+	unpricedNodes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_unpriced_nodes",
+			Help: "Number of running nodes of the pool left out of the fleet cost as their price is unknown",
+		},
+		[]string{"region", "pool"},
+	)
+
+	// Gauge for the resources actually used on the pool's nodes by resource (cpu in cores, memory in GiB)
+	actualUsage = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_actual_usage",
+			Help: "Resources actually used on the pool's nodes according to USAGE_SOURCE, by resource (cpu in cores, memory in GiB)",
+		},
+		[]string{"resource"},
+	)
+
+	// Counter of retries of failed Daytona API calls by result (retried or budget_exhausted)
+	daytonaAPIRetries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "runner_manager_daytona_api_retries_total",
+			Help: "Total number of transient Daytona API call failures by result (retried, or budget_exhausted when the retry budget was used up)",
+		},
+		[]string{"result"},
+	)
+
+	// Counter of controller loop spans dropped instead of exported
+	tracingDroppedSpans = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "runner_manager_tracing_dropped_spans_total",
+			Help: "Total number of controller loop spans dropped as the export queue was full or the export failed",
+		},
+	)
+
+	// Gauge for the placeholders pending past the capacity exhaustion threshold by region and pool
+	capacityExhaustedPlaceholders = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_capacity_exhausted_placeholders",
+			Help: "Number of placeholder pods pending for longer than CAPACITY_EXHAUSTION_THRESHOLD, the cluster cannot add nodes",
+		},
+		[]string{"region", "pool"},
+	)
+
+	// Gauge for the new runners still pre-pulling snapshots
+	prewarmingRunners = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_prewarming_runners",
+			Help: "Number of new runners still pre-pulling the region's most-used snapshots",
+		},
+	)
+
+	// Counter of snapshot pre-pulls on new runners by result
+	snapshotPrepulls = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "runner_manager_snapshot_prepulls_total",
+			Help: "Total number of snapshots pre-pulled on new runners or failed to, by result",
+		},
+		[]string{"result"},
+	)
+
+	// Counter of scaling notifications by kind and result
+	notificationsSent = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "runner_manager_notifications_total",
+			Help: "Total number of scaling notifications delivered, failed, dropped or held back by the rate limit, by kind",
+		},
+		[]string{"kind", "result"},
+	)
+
+	// Counter of fallbacks to on-demand nodes after spot placeholders stayed pending
+	spotFallbacks = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "runner_manager_spot_fallbacks_total",
+			Help: "Total number of times spot capacity was unavailable and placeholders fell back to on-demand nodes",
+		},
+	)
+
+	// Counter of spot node interruption notices handled
+	spotInterruptions = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "runner_manager_spot_interruptions_total",
+			Help: "Total number of spot nodes that received an interruption notice",
+		},
+	)
+
+	// Gauge set to 1 while the pool's controller loop is degraded by repeated control-plane errors
+	controlPlaneDegraded = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_degraded",
+			Help: "Set to 1 while the pool's controller loop is degraded by repeated control-plane errors: no scale-down and limited scale-up",
+		},
+		[]string{"region", "pool"},
+	)
+
+	// Counter of controller cycles with control-plane errors by region and pool
+	controlPlaneErrorCycles = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "runner_manager_control_plane_error_cycles_total",
+			Help: "Total number of controller cycles in which calls to the Daytona API or the cluster failed",
+		},
+		[]string{"region", "pool"},
+	)
+
+	// Gauge for the nodes scheduled for removal and waiting for their grace period to pass
+	nodesScheduledForRemoval = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_nodes_scheduled_for_removal",
+			Help: "Number of nodes labeled daytona.io/scheduled-for-removal waiting for SCALE_DOWN_GRACE_PERIOD to pass",
+		},
+	)
+
+	// Counter of nodes scheduled for removal and of scheduled removals cancelled
+	scheduledRemovals = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "runner_manager_scheduled_removals_total",
+			Help: "Total number of nodes scheduled for removal and of scheduled removals cancelled, by action",
+		},
+		[]string{"action"},
+	)
+
+	// Gauge for the unschedulable runners kept in the warm buffer
+	warmStandbyNodes = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_warm_standby_runners",
+			Help: "Number of registered but unschedulable runners kept in the warm buffer for demand spikes",
+		},
+	)
+
+	// Counter of warm standby runners made schedulable by a scale-up
+	warmStandbyPromotions = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "runner_manager_warm_standby_promotions_total",
+			Help: "Total number of warm standby runners made schedulable by a scale-up",
+		},
+	)
+
+	// Gauge of the scale-down blackout windows, 1 for the active ones
+	scaleDownBlackoutActive = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "runner_manager_scale_down_blackout_active",
+			Help: "Whether the scale-down blackout window is active, disallowing scale-down",
+		},
+		[]string{"window"},
+	)
+
+	// Histogram of the provisioning latency of new nodes by stage, from 15 seconds up to an hour
+	nodeProvisioningLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "runner_manager_node_provisioning_seconds",
+			Help:    "Time from placeholder creation to node readiness (node_ready), from node readiness to runner registration (runner_registration) and in total, by region, pool and zone",
+			Buckets: prometheus.ExponentialBuckets(15, 2, 9),
+		},
+		[]string{"region", "pool", "zone", "stage"},
+	)
+)
@@ -0,0 +1,174 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/daytona/apps/runner-manager/pkg/status"
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// MigrationAnnotation marks old nodes whose runner is being replaced by the migration to the new node pool
+	MigrationAnnotation = "daytona.io/migrating"
+
+	// DefaultMigrationMaxSurge is the number of runners replaced at once when MIGRATION_MAX_SURGE is not set
+	DefaultMigrationMaxSurge = 1
+
+	// EventReasonMigrationReplace is the reason of the event recorded on an old node whose runner is replaced
+	EventReasonMigrationReplace = "MigrationReplace"
+)
+
+// nodePoolMigration moves a pool from its current nodes to the ones matching MIGRATION_NODE_SELECTOR, e.g. a node
+// group with a new kernel or machine image. Both node groups carry the pool's node label, new placeholders only land
+// on the new nodes and the runners of the old nodes are replaced MIGRATION_MAX_SURGE at a time: made unschedulable,
+// they stop counting as capacity so the regular scale-up adds new nodes, and their old nodes are removed by the
+// regular scale-down once drained.
+type nodePoolMigration struct {
+	selector labels.Selector
+
+	mu       sync.Mutex
+	progress status.PoolMigration
+}
+
+func newNodePoolMigration(cfg *Config) *nodePoolMigration {
+	return &nodePoolMigration{
+		selector: labels.SelectorFromSet(cfg.MigrationNodeSelector),
+		progress: status.PoolMigration{Pool: cfg.PoolName, NodeSelector: cfg.MigrationNodeSelector},
+	}
+}
+
+// isNew reports whether the node belongs to the node pool migrated to
+func (m *nodePoolMigration) isNew(node *corev1.Node) bool {
+	return m.selector.Matches(labels.Set(node.Labels))
+}
+
+// reconcile advances the migration by one cycle and records its progress. It runs before the resource metrics are
+// calculated so they account for the runners made unschedulable.
+func (m *nodePoolMigration) reconcile(backend clusterBackend, apiClient *daytona.APIClient, cfg *Config, state *ClusterState) {
+	progress := status.PoolMigration{Pool: cfg.PoolName, NodeSelector: cfg.MigrationNodeSelector, UpdatedAt: time.Now().UTC()}
+	for i := range state.Nodes {
+		if m.isNew(&state.Nodes[i]) {
+			progress.NewNodes++
+		} else {
+			progress.OldNodes++
+		}
+	}
+
+	// Placeholders created before the migration started would add old nodes
+	pending := state.PendingPlaceholders[:0]
+	for _, pod := range state.PendingPlaceholders {
+		if m.selector.Matches(labels.Set(pod.Spec.NodeSelector)) {
+			pending = append(pending, pod)
+			continue
+		}
+		log.WithField("placeholder", pod.Name).Infof("Deleting pending placeholder pod %s, it would add a node of the old node pool.", pod.Name)
+		if err := backend.DeletePlaceholder(context.Background(), pod.Name); err != nil {
+			log.Errorf("Error deleting pending placeholder pod %s: %v", pod.Name, err)
+			pending = append(pending, pod)
+			continue
+		}
+		recordPlaceholderEvent(backend, pod, corev1.EventTypeNormal, EventReasonScaleUpCancelled, "Deleted before its node was added: the pool is migrating to a new node pool")
+	}
+	state.PendingPlaceholders = pending
+
+	var old []daytona.RunnerFull
+	for _, runner := range state.Runners {
+		node, found := state.NodeByIP[runner.GetDomain()]
+		if !found {
+			continue
+		}
+		if m.isNew(node) {
+			progress.NewRunners++
+			continue
+		}
+		progress.OldRunners++
+		if node.Annotations[MigrationAnnotation] != "" {
+			// A runner made schedulable again while being replaced is left on its old node
+			if runner.GetUnschedulable() {
+				progress.DrainingRunners++
+			}
+			continue
+		}
+		old = append(old, runner)
+	}
+	progress.Completed = progress.OldNodes == 0
+
+	m.mu.Lock()
+	wasCompleted := m.progress.Completed
+	m.mu.Unlock()
+	if progress.Completed && !wasCompleted {
+		log.WithField("pool", cfg.PoolName).Infof("Migration to the node pool %s completed, no old nodes are left.", m.selector)
+	}
+
+	slots := cfg.MigrationMaxSurge - progress.DrainingRunners
+	sortForReplacement(old)
+	for _, runner := range old {
+		if slots <= 0 {
+			break
+		}
+		message := fmt.Sprintf("Runner %s is replaced by one on the new node pool %s: it is unschedulable and the node is removed once drained", runner.GetId(), m.selector)
+		if replaceRunner(backend, apiClient, state, runner, MigrationAnnotation, "true", EventReasonMigrationReplace, message) {
+			progress.DrainingRunners++
+			migrationReplacements.Inc()
+			slots--
+		}
+	}
+
+	migrationNodes.WithLabelValues(cfg.PoolName, "old").Set(float64(progress.OldNodes))
+	migrationNodes.WithLabelValues(cfg.PoolName, "new").Set(float64(progress.NewNodes))
+
+	m.mu.Lock()
+	m.progress = progress
+	m.mu.Unlock()
+}
+
+// selectPlaceholderMigration restricts the placeholder to the nodes of the node pool migrated to
+func selectPlaceholderMigration(pod *corev1.Pod, cfg *Config) {
+	if len(cfg.MigrationNodeSelector) == 0 {
+		return
+	}
+	if pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = map[string]string{}
+	}
+	for key, value := range cfg.MigrationNodeSelector {
+		pod.Spec.NodeSelector[key] = value
+	}
+}
+
+// migrationHandler serves the migration progress of the selected pool
+func migrationHandler(pools []*runnerPool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		pool := selectPool(w, r, pools)
+		if pool == nil {
+			return
+		}
+		if pool.migration == nil {
+			http.Error(w, fmt.Sprintf("pool %s is not migrating, MIGRATION_NODE_SELECTOR is not set", pool.cfg.PoolName), http.StatusNotFound)
+			return
+		}
+
+		pool.migration.mu.Lock()
+		progress := pool.migration.progress
+		pool.migration.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(progress)
+	}
+}
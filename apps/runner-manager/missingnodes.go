@@ -0,0 +1,135 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// missingNodeTracker deregisters runners whose node is gone, e.g. after it was deleted by hand, so they stop counting
+// as capacity and the fleet view of the Daytona API stays accurate. Only runners seen on one of the pool's nodes by
+// this process are considered, so runners never managed by the pool, e.g. of another pool or outside Kubernetes, are
+// left alone. Runners behind NAT are matched to their node by relayed heartbeats only, which a proxy restart or a
+// tunnel forgotten after tunnel.ForgetAfter lose, so they are never considered either. Runners with allocations
+// are kept too, their records are needed until their sandboxes and snapshots are moved or deleted.
+type missingNodeTracker struct {
+	cycles int
+
+	// seen are the IDs of the runners matched to a node of the pool
+	seen map[string]bool
+	// missing counts the consecutive cycles each seen runner was not matched to any node, by runner ID
+	missing map[string]int
+}
+
+func newMissingNodeTracker(cfg *Config) *missingNodeTracker {
+	return &missingNodeTracker{
+		cycles:  cfg.MissingNodeDeregisterCycles,
+		seen:    make(map[string]bool),
+		missing: make(map[string]int),
+	}
+}
+
+// reconcile counts the cycles runners have been without a node and deregisters the ones missing theirs for
+// MISSING_NODE_DEREGISTER_CYCLES consecutive cycles, removing them from the state
+func (t *missingNodeTracker) reconcile(apiClient *daytona.APIClient, state *ClusterState) {
+	for _, runner := range state.Runners {
+		if _, found := state.NodeByIP[runner.GetDomain()]; found && !state.TunneledDomains[runner.GetDomain()] {
+			t.seen[runner.GetId()] = true
+			delete(t.missing, runner.GetId())
+		}
+	}
+
+	nodeless := make(map[string]bool, len(state.NodelessRunners))
+	var deregistered []string
+	for _, runner := range state.NodelessRunners {
+		if !t.seen[runner.GetId()] || state.TunneledDomains[runner.GetDomain()] {
+			continue
+		}
+		nodeless[runner.GetId()] = true
+		t.missing[runner.GetId()]++
+
+		runnerLog := log.WithFields(log.Fields{"runner": runner.GetId(), "domain": runner.GetDomain()})
+		if t.missing[runner.GetId()] < t.cycles {
+			runnerLog.Warnf("Runner %s (%s) matches no node for %d consecutive cycles.", runner.GetId(), runner.GetDomain(), t.missing[runner.GetId()])
+			continue
+		}
+
+		if isRunnerAllocated(runner) {
+			runnerLog.Warnf("Runner %s (%s) matched no node for %d consecutive cycles, but still has allocations. Keeping it.", runner.GetId(), runner.GetDomain(), t.missing[runner.GetId()])
+			continue
+		}
+		runnerLog.Warnf("Runner %s (%s) matched no node for %d consecutive cycles, its node is gone. Deregistering it.", runner.GetId(), runner.GetDomain(), t.missing[runner.GetId()])
+		if err := deregisterRunner(apiClient, runner.GetId()); err != nil {
+			runnerLog.Errorf("Error deregistering runner %s: %v", runner.GetId(), err)
+			continue
+		}
+		missingNodeDeregistrations.Inc()
+		delete(t.seen, runner.GetId())
+		delete(t.missing, runner.GetId())
+		deregistered = append(deregistered, runner.GetId())
+	}
+	for _, runnerID := range deregistered {
+		removeRunner(state, runnerID)
+	}
+
+	// Runners deregistered by others, or back on a node, are forgotten
+	for runnerID := range t.missing {
+		if !nodeless[runnerID] {
+			delete(t.missing, runnerID)
+		}
+	}
+	current := make(map[string]bool, len(state.Runners)+len(state.NodelessRunners))
+	for _, runners := range [][]daytona.RunnerFull{state.Runners, state.NodelessRunners} {
+		for _, runner := range runners {
+			current[runner.GetId()] = true
+		}
+	}
+	for runnerID := range t.seen {
+		if !current[runnerID] {
+			delete(t.seen, runnerID)
+		}
+	}
+}
+
+// deregisterRunner deletes the runner record from the Daytona API
+func deregisterRunner(apiClient *daytona.APIClient, runnerID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := apiClient.AdminAPI.AdminDeleteRunner(ctx, runnerID).Execute(); err != nil {
+		return fmt.Errorf("failed to delete runner %s from Daytona API: %w", runnerID, err)
+	}
+	return nil
+}
+
+// removeRunner removes a runner deregistered this cycle from the state
+func removeRunner(state *ClusterState, runnerID string) {
+	without := func(runners []daytona.RunnerFull) []daytona.RunnerFull {
+		kept := runners[:0]
+		for _, runner := range runners {
+			if runner.GetId() != runnerID {
+				kept = append(kept, runner)
+			}
+		}
+		return kept
+	}
+	state.Runners = without(state.Runners)
+	state.ActiveRunners = without(state.ActiveRunners)
+	state.DeletableRunners = without(state.DeletableRunners)
+	state.IdleRunners = without(state.IdleRunners)
+	state.NodelessRunners = without(state.NodelessRunners)
+	delete(state.UnreachableRunnerIDs, runnerID)
+
+	for domain, runner := range state.RunnerByDomain {
+		if runner.GetId() == runnerID {
+			delete(state.RunnerByDomain, domain)
+		}
+	}
+}
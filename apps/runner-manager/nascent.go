@@ -0,0 +1,109 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// NascentSinceAnnotation records when runner-manager first saw the node hosting a placeholder without a runner
+	NascentSinceAnnotation = "daytona.io/nascent-since"
+
+	// NascentTimedOutAtAnnotation records when the node was cordoned because its runner never registered
+	NascentTimedOutAtAnnotation = "daytona.io/nascent-timed-out-at"
+
+	// EventReasonNascentTimeout is the reason of the event recorded on a node whose runner never registered
+	EventReasonNascentTimeout = "NascentTimeout"
+)
+
+// remediateNascentNodes tracks how long each node has been nascent and gives up on the ones past NASCENT_NODE_TIMEOUT,
+// whose runner bootstrap is most likely broken: their placeholder is deleted and the node cordoned, so it stops
+// counting towards the idle buffer and a replacement is scaled up. The node is left for inspection, it is removed by
+// the cluster autoscaler once empty.
+func remediateNascentNodes(backend clusterBackend, cfg *Config, state *ClusterState, lifecycle *lifecycleNotifier) {
+	nascentNodeAge.Reset()
+	nascent := make(map[string]bool, len(state.NascentNodes))
+	remaining := state.NascentNodes[:0]
+	for _, node := range state.NascentNodes {
+		nascent[node.Name] = true
+		since := nascentSince(backend, node)
+		age := time.Since(since)
+		nascentNodeAge.WithLabelValues(node.Name).Set(age.Seconds())
+		if age <= cfg.NascentNodeTimeout {
+			remaining = append(remaining, node)
+			continue
+		}
+
+		nodeLog := log.WithField("node", node.Name)
+		nodeLog.Errorf("Node %s has hosted a placeholder without a registered runner for %s, exceeding NASCENT_NODE_TIMEOUT. Cordoning it and deleting its placeholder.", node.Name, age.Round(time.Second))
+
+		now := time.Now().UTC().Format(time.RFC3339)
+		unschedulable := true
+		if err := backend.PatchNode(context.Background(), node.Name, map[string]*string{NascentTimedOutAtAnnotation: &now, NascentSinceAnnotation: nil}, &unschedulable); err != nil {
+			nodeLog.Errorf("Error cordoning nascent node %s, keeping its placeholder: %v", node.Name, err)
+			remaining = append(remaining, node)
+			continue
+		}
+		node.Spec.Unschedulable = true
+		if stateNode := findNodeByName(state, node.Name); stateNode != nil {
+			stateNode.Spec.Unschedulable = true
+		}
+		nascentNodeAge.DeleteLabelValues(node.Name)
+		delete(node.Annotations, NascentSinceAnnotation)
+		nascentNodeTimeouts.Inc()
+		state.TimedOutNascentNodes = append(state.TimedOutNascentNodes, node.Name)
+		recordNodeEvent(backend, node, corev1.EventTypeWarning, EventReasonNascentTimeout,
+			fmt.Sprintf("No runner registered from the node within %s, cordoned the node and deleted its placeholder", cfg.NascentNodeTimeout))
+		if lifecycle != nil {
+			lifecycle.send(cfg.RegionID, LifecycleNodeBootstrapFailed, node, nil)
+		}
+
+		scheduled := state.ScheduledPlaceholders[:0]
+		for _, pod := range state.ScheduledPlaceholders {
+			if pod.Spec.NodeName != node.Name {
+				scheduled = append(scheduled, pod)
+				continue
+			}
+			if err := backend.DeletePlaceholder(context.Background(), pod.Name); err != nil {
+				nodeLog.WithField("placeholder", pod.Name).Errorf("Error deleting placeholder pod %s of nascent node %s: %v", pod.Name, node.Name, err)
+			}
+		}
+		state.ScheduledPlaceholders = scheduled
+	}
+	state.NascentNodes = remaining
+
+	// Nodes that registered a runner or were cordoned no longer need their nascent start
+	for i := range state.Nodes {
+		node := &state.Nodes[i]
+		if _, found := node.Annotations[NascentSinceAnnotation]; !found || nascent[node.Name] {
+			continue
+		}
+		if err := setNodeTimeAnnotation(backend, node.Name, NascentSinceAnnotation, nil); err != nil {
+			log.Errorf("Error clearing nascent start of node %s: %v", node.Name, err)
+		}
+	}
+}
+
+// nascentSince returns when the node was first seen nascent, annotating it the first time so the start survives
+// runner-manager restarts
+func nascentSince(backend clusterBackend, node *corev1.Node) time.Time {
+	if annotation, found := node.Annotations[NascentSinceAnnotation]; found {
+		if since, err := time.Parse(time.RFC3339, annotation); err == nil {
+			return since
+		}
+	}
+
+	since := time.Now().UTC().Truncate(time.Second)
+	if err := setNodeTimeAnnotation(backend, node.Name, NascentSinceAnnotation, &since); err != nil {
+		log.Errorf("Error recording nascent start of node %s: %v", node.Name, err)
+	}
+	return since
+}
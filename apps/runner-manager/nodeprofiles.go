@@ -0,0 +1,289 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// PlaceholderProfileLabel marks placeholder pods with the node profile they request
+const PlaceholderProfileLabel = "daytona.io/placeholder-profile"
+
+// nodeProfile is a node size the pool can be scaled with, selected through its node labels, e.g. an instance type
+type nodeProfile struct {
+	Name         string            `json:"name"`
+	Cpu          float32           `json:"cpu"`
+	MemoryGiB    float32           `json:"memoryGiB"`
+	NodeSelector map[string]string `json:"nodeSelector"`
+}
+
+// fits reports whether a sandbox of the given shape fits on a node of the profile
+func (p *nodeProfile) fits(shape sandboxShape) bool {
+	return shape.Cpu <= p.Cpu && shape.MemoryGiB <= p.MemoryGiB
+}
+
+// parseNodeProfiles parses NODE_PROFILES, a JSON array of profiles, and sorts them from the smallest to the largest.
+// The smallest profile is the default one, used for all scale-ups except for sandboxes too large for it.
+func parseNodeProfiles(value string) ([]nodeProfile, error) {
+	var profiles []nodeProfile
+	if err := json.Unmarshal([]byte(value), &profiles); err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("no profiles defined")
+	}
+
+	names := make(map[string]bool)
+	for _, profile := range profiles {
+		if profile.Name == "" {
+			return nil, fmt.Errorf("every profile needs a name")
+		}
+		if names[profile.Name] {
+			return nil, fmt.Errorf("profile %q is defined more than once", profile.Name)
+		}
+		names[profile.Name] = true
+		if profile.Cpu <= 0 || profile.MemoryGiB <= 0 {
+			return nil, fmt.Errorf("profile %q needs a positive cpu and memoryGiB", profile.Name)
+		}
+		if len(profile.NodeSelector) == 0 {
+			return nil, fmt.Errorf("profile %q needs a nodeSelector", profile.Name)
+		}
+	}
+
+	sort.SliceStable(profiles, func(i, j int) bool {
+		if profiles[i].Cpu != profiles[j].Cpu {
+			return profiles[i].Cpu < profiles[j].Cpu
+		}
+		return profiles[i].MemoryGiB < profiles[j].MemoryGiB
+	})
+	return profiles, nil
+}
+
+// defaultNodeProfile returns the profile of regular scale-ups, nil when the pool has no profiles
+func defaultNodeProfile(cfg *Config) *nodeProfile {
+	if len(cfg.NodeProfiles) == 0 {
+		return nil
+	}
+	return &cfg.NodeProfiles[0]
+}
+
+// findNodeProfile returns the profile with the given name, nil if there is none
+func findNodeProfile(cfg *Config, name string) *nodeProfile {
+	for i := range cfg.NodeProfiles {
+		if cfg.NodeProfiles[i].Name == name {
+			return &cfg.NodeProfiles[i]
+		}
+	}
+	return nil
+}
+
+// selectPlaceholderProfile labels the placeholder with its profile and restricts it to nodes of that profile, the
+// default profile when none is given
+func selectPlaceholderProfile(pod *corev1.Pod, cfg *Config, name string) {
+	profile := findNodeProfile(cfg, profileName(cfg, name))
+	if profile == nil {
+		return
+	}
+
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[PlaceholderProfileLabel] = profile.Name
+	if pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = map[string]string{}
+	}
+	for key, value := range profile.NodeSelector {
+		pod.Spec.NodeSelector[key] = value
+	}
+}
+
+// profileName returns the name of the profile a placeholder created with the given option requests
+func profileName(cfg *Config, name string) string {
+	if name == "" {
+		if profile := defaultNodeProfile(cfg); profile != nil {
+			return profile.Name
+		}
+	}
+	return name
+}
+
+// placeholderProfile returns the profile a placeholder requests, the default one for placeholders created without
+func placeholderProfile(cfg *Config, pod *corev1.Pod) string {
+	return profileName(cfg, pod.Labels[PlaceholderProfileLabel])
+}
+
+// countDefaultProfilePending returns the pending placeholders adding nodes of the default profile, the ones the
+// regular scale-up accounts for. Placeholders of reserved sandbox classes are accounted for by handleClassScaleUp.
+func countDefaultProfilePending(cfg *Config, state *ClusterState) int {
+	profile := defaultNodeProfile(cfg)
+	count := 0
+	for _, pod := range state.PendingPlaceholders {
+		if pod.Labels[PlaceholderClassLabel] != "" || isStandbyPlaceholder(pod) {
+			continue
+		}
+		if profile == nil || placeholderProfile(cfg, pod) == profile.Name {
+			count++
+		}
+	}
+	return count
+}
+
+// standardBacklog returns the part of the backlog that fits on nodes of the default profile, the sandboxes larger
+// than that are scaled for by handleProfileScaleUp
+func standardBacklog(cfg *Config, backlog sandboxBacklog) sandboxBacklog {
+	profile := defaultNodeProfile(cfg)
+	if profile == nil {
+		return backlog
+	}
+	standard := sandboxBacklog{}
+	for _, shape := range backlog.Shapes {
+		if profile.fits(shape) {
+			standard.add(shape)
+		}
+	}
+	return standard
+}
+
+// handleProfileScaleUp adds nodes of larger profiles for the queued sandboxes too large for the default profile that
+// no runner has room for. Each sandbox gets the smallest profile it fits in, and sandboxes of the same profile share
+// nodes. It returns true if any placeholders were created.
+func handleProfileScaleUp(backend clusterBackend, cfg *Config, state *ClusterState) bool {
+	defaultProfile := defaultNodeProfile(cfg)
+	if defaultProfile == nil {
+		return false
+	}
+
+	var oversized []sandboxShape
+	for _, shape := range state.Backlog.Shapes {
+		if !defaultProfile.fits(shape) {
+			oversized = append(oversized, shape)
+		}
+	}
+	if len(oversized) == 0 {
+		return false
+	}
+	sort.Slice(oversized, func(i, j int) bool { return oversized[i].Cpu > oversized[j].Cpu })
+
+	// Room left on the schedulable runners, a large sandbox is placed there when it fits
+	var free []sandboxShape
+	for _, runners := range [][]daytona.RunnerFull{state.IdleRunners, state.ActiveRunners} {
+		for _, runner := range runners {
+			if runner.GetUnschedulable() {
+				continue
+			}
+			free = append(free, sandboxShape{
+				Cpu:       runner.GetCpu() - runner.GetCurrentAllocatedCpu(),
+				MemoryGiB: runner.GetMemory() - runner.GetCurrentAllocatedMemoryGiB(),
+			})
+		}
+	}
+
+	// First-fit decreasing onto the runners, then onto new nodes of the smallest fitting profile
+	needed := make(map[string][]sandboxShape)
+	for _, shape := range oversized {
+		if placeShape(free, shape) {
+			continue
+		}
+		profile := smallestFittingProfile(cfg, shape)
+		if profile == nil {
+			log.Warnf("Queued sandbox with CPU=%.2f, Mem=%.2fGiB is larger than every node profile. It cannot be scaled for.", shape.Cpu, shape.MemoryGiB)
+			continue
+		}
+		nodes := needed[profile.Name]
+		if !placeShape(nodes, shape) {
+			nodes = append(nodes, sandboxShape{Cpu: profile.Cpu - shape.Cpu, MemoryGiB: profile.MemoryGiB - shape.MemoryGiB})
+		}
+		needed[profile.Name] = nodes
+	}
+
+	createdCount := 0
+	for _, profile := range cfg.NodeProfiles {
+		nodes := len(needed[profile.Name])
+		if nodes == 0 {
+			continue
+		}
+
+		// Nodes of the profile on their way cover part of the need
+		inFlight := 0
+		for _, pod := range state.PendingPlaceholders {
+			if placeholderProfile(cfg, pod) == profile.Name {
+				inFlight++
+			}
+		}
+		selector := labels.SelectorFromSet(profile.NodeSelector)
+		for _, node := range state.NascentNodes {
+			if selector.Matches(labels.Set(node.Labels)) {
+				inFlight++
+			}
+		}
+
+		deficit := nodes - inFlight
+		if deficit <= 0 {
+			continue
+		}
+		if deficit = capScaleUp(cfg, state, deficit); deficit == 0 {
+			continue
+		}
+
+		log.WithField("profile", profile.Name).Infof("%d queued sandboxes need %d %s nodes (%d in-flight). Creating %d placeholder pods.",
+			len(oversized), nodes, profile.Name, inFlight, deficit)
+		for i := 0; i < deficit; i++ {
+			pod, err := createPlaceholderPod(backend, cfg, PlaceholderPodLabel, placeholderOptions{Profile: profile.Name, CapacityType: state.CapacityType})
+			if err != nil {
+				log.Errorf("Error creating placeholder pod for node profile %s: %v", profile.Name, err)
+				continue
+			}
+			recordPlaceholderEvent(backend, pod, corev1.EventTypeNormal, EventReasonScaleUp,
+				fmt.Sprintf("Placeholder created to add a %s node: queued sandboxes are too large for the %s profile", profile.Name, defaultProfile.Name))
+			state.PlaceholdersCreated++
+			createdCount++
+		}
+	}
+	return createdCount > 0
+}
+
+// profileStillNeeded reports whether a pending placeholder of a larger profile is still needed by a queued sandbox
+// too large for the default profile
+func profileStillNeeded(cfg *Config, state *ClusterState, pod *corev1.Pod) bool {
+	name := placeholderProfile(cfg, pod)
+	if name == "" || name == profileName(cfg, "") {
+		return false
+	}
+	for _, shape := range state.Backlog.Shapes {
+		if profile := smallestFittingProfile(cfg, shape); profile != nil && profile.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// smallestFittingProfile returns the smallest profile a sandbox of the given shape fits in, nil if it fits in none
+func smallestFittingProfile(cfg *Config, shape sandboxShape) *nodeProfile {
+	for i := range cfg.NodeProfiles {
+		if cfg.NodeProfiles[i].fits(shape) {
+			return &cfg.NodeProfiles[i]
+		}
+	}
+	return nil
+}
+
+// placeShape reserves room for the shape in the first of the free slots it fits in and reports whether it did
+func placeShape(free []sandboxShape, shape sandboxShape) bool {
+	for i := range free {
+		if shape.Cpu <= free[i].Cpu && shape.MemoryGiB <= free[i].MemoryGiB {
+			free[i].Cpu -= shape.Cpu
+			free[i].MemoryGiB -= shape.MemoryGiB
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/daytona/apps/runner-manager/pkg/hostreport"
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// NodeReportMaxAge is how long a capacity report is trusted after it was received
+	NodeReportMaxAge = 2 * time.Minute
+
+	// HighIOPressureThreshold is the IO "some" pressure percentage above which a node is logged as IO constrained
+	HighIOPressureThreshold = 40
+)
+
+type receivedReport struct {
+	report     hostreport.Report
+	receivedAt time.Time
+}
+
+// nodeReportStore keeps the latest capacity report per node of the pools
+type nodeReportStore struct {
+	mu      sync.Mutex
+	reports map[string]receivedReport
+	// nodes are the node names of each pool's latest cycle, reports of other nodes are ignored
+	nodes map[string]map[string]bool
+}
+
+func newNodeReportStore() *nodeReportStore {
+	return &nodeReportStore{
+		reports: make(map[string]receivedReport),
+		nodes:   make(map[string]map[string]bool),
+	}
+}
+
+// observeNodes records the nodes of the pool gathered by its latest cycle
+func (s *nodeReportStore) observeNodes(poolName string, nodes []corev1.Node) {
+	names := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		names[node.Name] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nodes[poolName] = names
+}
+
+// known returns whether the node is in one of the pools, to be called with the lock held
+func (s *nodeReportStore) known(nodeName string) bool {
+	for _, names := range s.nodes {
+		if names[nodeName] {
+			return true
+		}
+	}
+	return false
+}
+
+// put records the report and returns true, or false without recording it if its node is in none of the pools
+func (s *nodeReportStore) put(report hostreport.Report) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.known(report.NodeName) {
+		return false
+	}
+	s.reports[report.NodeName] = receivedReport{report: report, receivedAt: time.Now()}
+	return true
+}
+
+// fresh returns the reports received within NodeReportMaxAge, keyed by node name, and drops stale ones and those of
+// nodes no longer in a pool
+func (s *nodeReportStore) fresh() map[string]*hostreport.Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[string]*hostreport.Report)
+	for nodeName, received := range s.reports {
+		if time.Since(received.receivedAt) > NodeReportMaxAge || !s.known(nodeName) {
+			delete(s.reports, nodeName)
+			nodeCpuStealPercent.DeleteLabelValues(nodeName)
+			nodeIOPressurePercent.DeleteLabelValues(nodeName)
+			continue
+		}
+		report := received.report
+		result[nodeName] = &report
+	}
+	return result
+}
+
+// nodeReportHandler accepts capacity reports posted by the capacity-reporter sidecar. Reports shrink the counted
+// capacity, so they are rejected unless NODE_REPORT_TOKEN is set, and those of nodes in no pool are ignored.
+func nodeReportHandler(reports *nodeReportStore, token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var report hostreport.Report
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&report); err != nil {
+			http.Error(w, "invalid report: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if report.NodeName == "" {
+			http.Error(w, "invalid report: nodeName is required", http.StatusBadRequest)
+			return
+		}
+
+		if !reports.put(report) {
+			http.Error(w, "invalid report: node "+report.NodeName+" is not in a pool", http.StatusNotFound)
+			return
+		}
+		nodeCpuStealPercent.WithLabelValues(report.NodeName).Set(report.CpuStealPercent)
+		nodeIOPressurePercent.WithLabelValues(report.NodeName).Set(report.IOPressureSome)
+
+		if report.IOPressureSome > HighIOPressureThreshold {
+			log.Infof("Node %s reports high IO pressure (some avg10: %.2f%%, full avg10: %.2f%%)", report.NodeName, report.IOPressureSome, report.IOPressureFull)
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
@@ -0,0 +1,409 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultNomadAddr is the Nomad API address used when NOMAD_ADDR is not set, as in the Nomad CLI
+	DefaultNomadAddr = "http://127.0.0.1:4646"
+
+	// nomadPlaceholderCpuMHz and nomadPlaceholderMemoryMB are the resources a placeholder job reserves, kept minimal
+	// since the placeholder only has to occupy a slot on a node of the pool
+	nomadPlaceholderCpuMHz   = 10
+	nomadPlaceholderMemoryMB = 16
+
+	// nomadAnnotationPrefix is the prefix of the annotations stored as Nomad node metadata. Metadata keys cannot
+	// contain a slash, so the one after the prefix is stored as an underscore.
+	nomadAnnotationPrefix = "daytona.io/"
+	nomadMetaPrefix       = "daytona.io_"
+)
+
+// nomadBackend runs placeholders as Nomad jobs constrained to the pool's node class, which the Nomad autoscaler
+// brings up clients for. Annotations are stored as dynamic node metadata and schedulability as node eligibility.
+type nomadBackend struct {
+	cfg        *Config
+	httpClient *http.Client
+
+	// nodeIDs maps node names to Nomad node IDs, refreshed whenever the nodes are listed
+	nodeIDs map[string]string
+	// placeholderNodes are the nodes hosting a placeholder, which new placeholders must avoid
+	placeholderNodes []string
+}
+
+func newNomadBackend(cfg *Config) *nomadBackend {
+	return &nomadBackend{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		nodeIDs:    make(map[string]string),
+	}
+}
+
+type nomadNodeStub struct {
+	ID string
+}
+
+type nomadNode struct {
+	ID                    string
+	Name                  string
+	Datacenter            string
+	Status                string
+	SchedulingEligibility string
+	Attributes            map[string]string
+	Meta                  map[string]string
+	NodeResources         struct {
+		Cpu struct {
+			TotalCpuCores int64
+		}
+		Memory struct {
+			MemoryMB int64
+		}
+	}
+}
+
+type nomadJobStub struct {
+	ID     string
+	Status string
+}
+
+type nomadJob struct {
+	ID         string
+	Status     string
+	SubmitTime int64
+	Meta       map[string]string
+}
+
+type nomadAllocation struct {
+	NodeName      string
+	DesiredStatus string
+	ClientStatus  string
+}
+
+func (b *nomadBackend) ListNodes(ctx context.Context) ([]corev1.Node, error) {
+	var stubs []nomadNodeStub
+	query := url.Values{"filter": []string{fmt.Sprintf("NodeClass == %q", b.cfg.NomadNodeClass)}}
+	if err := b.do(ctx, http.MethodGet, "/v1/nodes", query, nil, &stubs); err != nil {
+		return nil, err
+	}
+
+	nodes := make([]corev1.Node, 0, len(stubs))
+	nodeIDs := make(map[string]string, len(stubs))
+	for _, stub := range stubs {
+		var node nomadNode
+		if err := b.do(ctx, http.MethodGet, "/v1/node/"+url.PathEscape(stub.ID), nil, nil, &node); err != nil {
+			return nil, err
+		}
+		nodeIDs[node.Name] = node.ID
+		nodes = append(nodes, b.toKubernetesNode(node))
+	}
+
+	b.nodeIDs = nodeIDs
+	return nodes, nil
+}
+
+// toKubernetesNode represents the Nomad client as a node of the pool
+func (b *nomadBackend) toKubernetesNode(node nomadNode) corev1.Node {
+	osName := node.Attributes["kernel.name"]
+	if osName == "" {
+		osName = OSLinux
+	}
+
+	cpuCores := node.NodeResources.Cpu.TotalCpuCores
+	if cpuCores == 0 {
+		cpuCores, _ = strconv.ParseInt(node.Attributes["cpu.numcores"], 10, 64)
+	}
+
+	annotations := make(map[string]string)
+	for key, value := range node.Meta {
+		if strings.HasPrefix(key, nomadMetaPrefix) {
+			annotations[nomadAnnotationPrefix+strings.TrimPrefix(key, nomadMetaPrefix)] = value
+		}
+	}
+
+	ready := corev1.ConditionFalse
+	if node.Status == "ready" {
+		ready = corev1.ConditionTrue
+	}
+
+	k8sNode := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: node.Name,
+			UID:  types.UID(node.ID),
+			Labels: map[string]string{
+				b.cfg.NodeSelectorKey: "true",
+				corev1.LabelOSStable:  osName,
+				ZoneLabel:             node.Datacenter,
+			},
+			Annotations: annotations,
+		},
+		Spec: corev1.NodeSpec{
+			ProviderID:    "nomad://" + node.ID,
+			Unschedulable: node.SchedulingEligibility == "ineligible",
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    *resource.NewQuantity(cpuCores, resource.DecimalSI),
+				corev1.ResourceMemory: *resource.NewQuantity(node.NodeResources.Memory.MemoryMB*1024*1024, resource.BinarySI),
+			},
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: ready},
+			},
+		},
+	}
+	if ip := node.Attributes["unique.network.ip-address"]; ip != "" {
+		k8sNode.Status.Addresses = []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: ip}}
+	}
+
+	return k8sNode
+}
+
+func (b *nomadBackend) ListPlaceholders(ctx context.Context) ([]*corev1.Pod, error) {
+	var stubs []nomadJobStub
+	if err := b.do(ctx, http.MethodGet, "/v1/jobs", url.Values{"prefix": []string{PlaceholderPodLabel}}, nil, &stubs); err != nil {
+		return nil, err
+	}
+
+	var placeholders []*corev1.Pod
+	var placeholderNodes []string
+	for _, stub := range stubs {
+		if stub.Status == "dead" {
+			continue
+		}
+
+		var job nomadJob
+		if err := b.do(ctx, http.MethodGet, "/v1/job/"+url.PathEscape(stub.ID), nil, nil, &job); err != nil {
+			return nil, err
+		}
+		var allocations []nomadAllocation
+		if err := b.do(ctx, http.MethodGet, "/v1/job/"+url.PathEscape(stub.ID)+"/allocations", nil, nil, &allocations); err != nil {
+			return nil, err
+		}
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              job.ID,
+				Namespace:         b.cfg.ProviderNamespace,
+				Labels:            map[string]string{"app": job.Meta["app"]},
+				CreationTimestamp: metav1.NewTime(time.Unix(0, job.SubmitTime)),
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodPending},
+		}
+		if zone := job.Meta["zone"]; zone != "" {
+			pod.Labels[PlaceholderZoneLabel] = zone
+		}
+
+		// A placed allocation counts as scheduled even while its task starts, like a bound pod
+		for _, allocation := range allocations {
+			if allocation.DesiredStatus != "run" || allocation.NodeName == "" {
+				continue
+			}
+			if allocation.ClientStatus == "running" || allocation.ClientStatus == "pending" {
+				pod.Spec.NodeName = allocation.NodeName
+				if allocation.ClientStatus == "running" {
+					pod.Status.Phase = corev1.PodRunning
+				}
+				placeholderNodes = append(placeholderNodes, allocation.NodeName)
+				break
+			}
+		}
+
+		placeholders = append(placeholders, pod)
+	}
+
+	b.placeholderNodes = placeholderNodes
+	return placeholders, nil
+}
+
+// CreatePlaceholder pins the placeholder to the zone as a datacenter, spot capacity is not supported on Nomad
+func (b *nomadBackend) CreatePlaceholder(ctx context.Context, name, appName string, options placeholderOptions) (*corev1.Pod, error) {
+	zone := options.Zone
+	constraints := []map[string]string{
+		{"LTarget": "${node.class}", "Operand": "=", "RTarget": b.cfg.NomadNodeClass},
+		{"LTarget": "${attr.kernel.name}", "Operand": "=", "RTarget": b.cfg.PoolOS},
+	}
+	if zone != "" {
+		constraints = append(constraints, map[string]string{"LTarget": "${node.datacenter}", "Operand": "=", "RTarget": zone})
+	}
+	// Nomad has no anti-affinity across jobs, so every node already hosting a placeholder is excluded explicitly
+	for _, nodeName := range b.placeholderNodes {
+		constraints = append(constraints, map[string]string{"LTarget": "${node.unique.name}", "Operand": "!=", "RTarget": nodeName})
+	}
+
+	meta := map[string]string{"app": appName}
+	if zone != "" {
+		meta["zone"] = zone
+	}
+
+	job := map[string]any{
+		"ID":          name,
+		"Name":        name,
+		"Type":        "service",
+		"Datacenters": b.cfg.NomadDatacenters,
+		"Meta":        meta,
+		"Constraints": constraints,
+		"TaskGroups": []map[string]any{
+			{
+				"Name":  "placeholder",
+				"Count": 1,
+				"Tasks": []map[string]any{
+					{
+						"Name":   "pause",
+						"Driver": "docker",
+						"Config": map[string]any{"image": b.cfg.PlaceholderImage},
+						"Resources": map[string]any{
+							"CPU":      nomadPlaceholderCpuMHz,
+							"MemoryMB": nomadPlaceholderMemoryMB,
+						},
+					},
+				},
+			},
+		},
+	}
+	if b.cfg.NomadNamespace != "" {
+		job["Namespace"] = b.cfg.NomadNamespace
+	}
+	if b.cfg.NomadRegion != "" {
+		job["Region"] = b.cfg.NomadRegion
+	}
+
+	if err := b.do(ctx, http.MethodPost, "/v1/jobs", nil, map[string]any{"Job": job}, nil); err != nil {
+		return nil, err
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         b.cfg.ProviderNamespace,
+			Labels:            map[string]string{"app": appName},
+			CreationTimestamp: metav1.Now(),
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending},
+	}
+	if zone != "" {
+		pod.Labels[PlaceholderZoneLabel] = zone
+	}
+	return pod, nil
+}
+
+func (b *nomadBackend) DeletePlaceholder(ctx context.Context, name string) error {
+	return b.do(ctx, http.MethodDelete, "/v1/job/"+url.PathEscape(name), url.Values{"purge": []string{"true"}}, nil, nil)
+}
+
+func (b *nomadBackend) PatchNode(ctx context.Context, nodeName string, annotations map[string]*string, unschedulable *bool) error {
+	nodeID, found := b.nodeIDs[nodeName]
+	if !found {
+		return fmt.Errorf("unknown Nomad node %s", nodeName)
+	}
+
+	meta := make(map[string]*string, len(annotations))
+	for key, value := range annotations {
+		if !strings.HasPrefix(key, nomadAnnotationPrefix) {
+			return fmt.Errorf("annotation %s cannot be stored as Nomad node metadata", key)
+		}
+		meta[nomadMetaPrefix+strings.TrimPrefix(key, nomadAnnotationPrefix)] = value
+	}
+	if len(meta) > 0 {
+		query := url.Values{"node_id": []string{nodeID}}
+		if err := b.do(ctx, http.MethodPost, "/v1/client/metadata", query, map[string]any{"Meta": meta}, nil); err != nil {
+			return err
+		}
+	}
+
+	if unschedulable != nil {
+		eligibility := "eligible"
+		if *unschedulable {
+			eligibility = "ineligible"
+		}
+		body := map[string]any{"NodeID": nodeID, "Eligibility": eligibility}
+		if err := b.do(ctx, http.MethodPost, "/v1/node/"+url.PathEscape(nodeID)+"/eligibility", nil, body, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LabelNode stores the labels as dynamic node metadata like annotations, Nomad nodes have no labels
+func (b *nomadBackend) LabelNode(ctx context.Context, nodeName string, labels map[string]*string) error {
+	return b.PatchNode(ctx, nodeName, labels, nil)
+}
+
+// RecordNodeEvent logs the event, Nomad has no API to add events to a node
+func (b *nomadBackend) RecordNodeEvent(ctx context.Context, node *corev1.Node, eventType, reason, message string) error {
+	log.Infof("Node %s: %s %s: %s", node.Name, eventType, reason, message)
+	return nil
+}
+
+// RecordPlaceholderEvent logs the event, Nomad allocations have no events
+func (b *nomadBackend) RecordPlaceholderEvent(ctx context.Context, pod *corev1.Pod, eventType, reason, message string) error {
+	log.Infof("Placeholder %s: %s %s: %s", pod.Name, eventType, reason, message)
+	return nil
+}
+
+// do calls the Nomad API in the configured namespace and region and decodes the JSON response into out if not nil
+func (b *nomadBackend) do(ctx context.Context, method, path string, query url.Values, body any, out any) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	if b.cfg.NomadNamespace != "" {
+		query.Set("namespace", b.cfg.NomadNamespace)
+	}
+	if b.cfg.NomadRegion != "" {
+		query.Set("region", b.cfg.NomadRegion)
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, b.cfg.NomadAddr+path+"?"+query.Encode(), reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if b.cfg.NomadToken != "" {
+		req.Header.Set("X-Nomad-Token", b.cfg.NomadToken)
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Nomad API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("Nomad API returned %s for %s %s: %s", resp.Status, method, path, strings.TrimSpace(string(message)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
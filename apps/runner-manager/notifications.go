@@ -0,0 +1,169 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Notification kinds posted to the notification webhook
+	NotificationScaleUp           = "scale_up"
+	NotificationScaleDownFailed   = "scale_down_failed"
+	NotificationStuckNascentNode  = "stuck_nascent_node"
+	NotificationCapacityCap       = "capacity_cap"
+	NotificationCapacityExhausted = "capacity_exhausted"
+	NotificationCapacityDrift     = "capacity_drift"
+	NotificationDegraded          = "degraded"
+
+	// DefaultNotificationMinInterval is how long notifications of the same kind and pool are held back after one is
+	// sent when NOTIFICATION_MIN_INTERVAL is not set
+	DefaultNotificationMinInterval = 15 * time.Minute
+
+	// notificationQueueSize bounds the notifications waiting for delivery to a webhook that is slow or failing
+	notificationQueueSize = 100
+)
+
+// notification is the payload posted to the notification webhook. Text makes it a valid Slack incoming webhook
+// message, the other fields are for receivers routing on them.
+type notification struct {
+	Text     string    `json:"text"`
+	Kind     string    `json:"kind"`
+	RegionId string    `json:"regionId"`
+	Pool     string    `json:"pool"`
+	Time     time.Time `json:"time"`
+}
+
+// scalingNotifier posts scaling anomalies to the notification webhook in the background. Notifications of a kind are
+// sent at most once per minimum interval and pool, the ones held back in between are counted in the next one.
+type scalingNotifier struct {
+	webhookURL  string
+	minInterval time.Duration
+	queue       chan notification
+
+	mu         sync.Mutex
+	lastSent   map[string]time.Time
+	suppressed map[string]int
+}
+
+func newScalingNotifier(cfg *Config) *scalingNotifier {
+	n := &scalingNotifier{
+		webhookURL:  cfg.NotificationWebhookURL,
+		minInterval: cfg.NotificationMinInterval,
+		queue:       make(chan notification, notificationQueueSize),
+		lastSent:    make(map[string]time.Time),
+		suppressed:  make(map[string]int),
+	}
+	go n.deliver(&http.Client{Timeout: 10 * time.Second})
+	return n
+}
+
+// notifyCycle sends the notifications of the anomalies recorded in the state by a controller cycle
+func (n *scalingNotifier) notifyCycle(cfg *Config, state *ClusterState) {
+	if state.PlaceholdersCreated > 0 {
+		n.notify(cfg, NotificationScaleUp, "Scaled up by %d nodes: %d nodes, %d idle runners and %d queued sandboxes before the scale-up.",
+			state.PlaceholdersCreated, len(state.Nodes), len(state.IdleRunners), state.QueuedSandboxes)
+	}
+	if state.ScaleDownFailures > 0 {
+		n.notify(cfg, NotificationScaleDownFailed, "Failed to remove %d nodes during scale-down, see the runner-manager logs.", state.ScaleDownFailures)
+	}
+	if len(state.TimedOutNascentNodes) > 0 {
+		n.notify(cfg, NotificationStuckNascentNode, "No runner registered within %s from nodes %s, cordoned them and deleted their placeholders.",
+			cfg.NascentNodeTimeout, strings.Join(state.TimedOutNascentNodes, ", "))
+	}
+	if state.ExhaustedPlaceholders > 0 {
+		n.notify(cfg, NotificationCapacityExhausted, "%d placeholder pods pending for more than %s, the cluster cannot add nodes: the cloud provider's quota may be reached or the instance type out of stock.",
+			state.ExhaustedPlaceholders, cfg.CapacityExhaustionThreshold)
+	}
+	if len(state.CapacityDrift) > 0 {
+		n.notify(cfg, NotificationCapacityDrift, "Runners report a capacity more than %d%% apart from the allocatable resources of nodes %s, counting the %s capacity unless overridden by %s.",
+			cfg.CapacityDriftThresholdPercent, strings.Join(driftingNodes(state), ", "), describeCapacitySource(cfg.CapacityDriftSource), CapacitySourceAnnotation)
+	}
+	if state.ScaleUpCappedBy != "" {
+		n.notify(cfg, NotificationCapacityCap, "Scale-up capped by %s, the pool is at its maximum size.", state.ScaleUpCappedBy)
+	}
+}
+
+// notify queues a notification of the pool unless one of the same kind was sent within the minimum interval
+func (n *scalingNotifier) notify(cfg *Config, kind, format string, args ...any) {
+	key := cfg.PoolName + "/" + kind
+	text := fmt.Sprintf(format, args...)
+
+	n.mu.Lock()
+	if time.Since(n.lastSent[key]) < n.minInterval {
+		n.suppressed[key]++
+		n.mu.Unlock()
+		notificationsSent.WithLabelValues(kind, "rate_limited").Inc()
+		return
+	}
+	if suppressed := n.suppressed[key]; suppressed > 0 {
+		text += fmt.Sprintf(" %d similar notifications were held back since the last one.", suppressed)
+	}
+	n.lastSent[key] = time.Now()
+	delete(n.suppressed, key)
+	n.mu.Unlock()
+
+	event := notification{
+		Text:     fmt.Sprintf("[%s/%s] %s", cfg.RegionID, cfg.PoolName, text),
+		Kind:     kind,
+		RegionId: cfg.RegionID,
+		Pool:     cfg.PoolName,
+		Time:     time.Now().UTC(),
+	}
+	select {
+	case n.queue <- event:
+	default:
+		log.Warnf("Notification webhook is backed up, dropping %s notification", kind)
+		notificationsSent.WithLabelValues(kind, "dropped").Inc()
+	}
+}
+
+// deliver posts the queued notifications in order. Failed deliveries are not retried, a later notification of the same
+// anomaly follows once the minimum interval has passed.
+func (n *scalingNotifier) deliver(httpClient *http.Client) {
+	for event := range n.queue {
+		if err := postNotification(httpClient, n.webhookURL, event); err != nil {
+			log.Errorf("Error delivering %s notification to the notification webhook: %v", event.Kind, err)
+			notificationsSent.WithLabelValues(event.Kind, "failed").Inc()
+			continue
+		}
+		notificationsSent.WithLabelValues(event.Kind, "delivered").Inc()
+	}
+}
+
+func postNotification(httpClient *http.Client, url string, event notification) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
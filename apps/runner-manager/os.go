@@ -0,0 +1,58 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	OSLinux   = "linux"
+	OSWindows = "windows"
+	OSDarwin  = "darwin"
+
+	// SandboxOSProperty is the sandbox property the Daytona API reports the requested operating system in
+	SandboxOSProperty = "os"
+
+	// MacOSMaxSandboxesPerRunner is the number of macOS virtual machines a Mac host may run under the macOS license,
+	// so a macOS runner is full at that many started sandboxes regardless of its free CPU and memory
+	MacOSMaxSandboxesPerRunner = 2
+)
+
+// defaultPlaceholderImages are the pause images used by the built-in placeholder spec. macOS nodes cannot run
+// container images, so darwin pools must set PLACEHOLDER_IMAGE or a placeholder pod template.
+var defaultPlaceholderImages = map[string]string{
+	OSLinux:   "rancher/pause:3.6", // A very small, stable image
+	OSWindows: "mcr.microsoft.com/oss/kubernetes/pause:3.9",
+}
+
+func isSupportedOS(os string) bool {
+	return os == OSLinux || os == OSWindows || os == OSDarwin
+}
+
+// nodeOS returns the operating system of the node as reported by the kubelet, defaulting to Linux
+func nodeOS(node *corev1.Node) string {
+	if os := node.Labels[corev1.LabelOSStable]; os != "" {
+		return os
+	}
+	return OSLinux
+}
+
+// sandboxOS returns the operating system the sandbox requested, defaulting to Linux when the API does not report one
+func sandboxOS(sandbox daytona.Sandbox) string {
+	if os, ok := sandbox.AdditionalProperties[SandboxOSProperty].(string); ok && os != "" {
+		return os
+	}
+	return OSLinux
+}
+
+// runnerSandboxLimit returns the maximum number of started sandboxes of a runner on a node of the given
+// operating system, or 0 if only CPU and memory limit it
+func runnerSandboxLimit(os string) int {
+	if os == OSDarwin {
+		return MacOSMaxSandboxesPerRunner
+	}
+	return 0
+}
@@ -0,0 +1,13 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+// DefaultOvercommitRatio accounts sandbox allocations one to one against the capacity of the runners
+const DefaultOvercommitRatio = 1.0
+
+// effectiveCapacity returns the CPU and memory sandboxes can be allocated on a runner or node of the given capacity,
+// scaled by CPU_OVERCOMMIT_RATIO and MEMORY_OVERCOMMIT_RATIO as sandboxes rarely use all they are allocated
+func effectiveCapacity(cfg *Config, cpu, memoryGiB float32) (float32, float32) {
+	return cpu * float32(cfg.CpuOvercommitRatio), memoryGiB * float32(cfg.MemoryOvercommitRatio)
+}
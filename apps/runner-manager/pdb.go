@@ -0,0 +1,77 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// PlaceholderDisruptionBudgetName is the name of the PodDisruptionBudget reconciled in the pool's namespace
+const PlaceholderDisruptionBudgetName = "daytona-runner-placeholders"
+
+// reconcilePlaceholderDisruptionBudget makes sure a PodDisruptionBudget allowing no disruption covers the pool's
+// placeholder pods. Node drains and cluster upgrades evict pods, which the budget then refuses for placeholders, so a
+// node hosting sandboxes is not emptied and removed by the cluster autoscaler behind runner-manager's back.
+// runner-manager itself deletes placeholders rather than evicting them, which budgets do not apply to.
+func reconcilePlaceholderDisruptionBudget(clientset *kubernetes.Clientset, cfg *Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	maxUnavailable := intstr.FromInt32(0)
+	spec := policyv1.PodDisruptionBudgetSpec{
+		MaxUnavailable: &maxUnavailable,
+		Selector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": PlaceholderPodLabel},
+		},
+	}
+
+	budgets := clientset.PolicyV1().PodDisruptionBudgets(cfg.ProviderNamespace)
+	existing, err := budgets.Get(ctx, PlaceholderDisruptionBudgetName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		budget := &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      PlaceholderDisruptionBudgetName,
+				Namespace: cfg.ProviderNamespace,
+			},
+			Spec: spec,
+		}
+		if _, err := budgets.Create(ctx, budget, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create placeholder disruption budget: %w", err)
+		}
+		log.Infof("Created placeholder disruption budget %s/%s", cfg.ProviderNamespace, PlaceholderDisruptionBudgetName)
+	case err != nil:
+		return fmt.Errorf("failed to get placeholder disruption budget: %w", err)
+	case !isPlaceholderDisruptionBudget(existing.Spec):
+		existing.Spec = spec
+		if _, err := budgets.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update placeholder disruption budget: %w", err)
+		}
+		log.Infof("Updated placeholder disruption budget %s/%s", cfg.ProviderNamespace, PlaceholderDisruptionBudgetName)
+	}
+
+	return nil
+}
+
+// isPlaceholderDisruptionBudget reports whether the budget still selects exactly the placeholders and allows none of
+// them to be evicted
+func isPlaceholderDisruptionBudget(spec policyv1.PodDisruptionBudgetSpec) bool {
+	if spec.MinAvailable != nil || spec.MaxUnavailable == nil || spec.MaxUnavailable.String() != "0" {
+		return false
+	}
+	if spec.Selector == nil || len(spec.Selector.MatchExpressions) > 0 || len(spec.Selector.MatchLabels) != 1 {
+		return false
+	}
+	return spec.Selector.MatchLabels["app"] == PlaceholderPodLabel
+}
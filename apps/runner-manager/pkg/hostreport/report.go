@@ -0,0 +1,22 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package hostreport
+
+import "time"
+
+// ReportPath is the runner-manager endpoint capacity reporters post to
+const ReportPath = "/node-reports"
+
+// Report holds host-level metrics of a pool node that the runner record does not expose
+type Report struct {
+	NodeName  string    `json:"nodeName"`
+	Timestamp time.Time `json:"timestamp"`
+	// CpuStealPercent is the share of CPU time stolen by the hypervisor since the previous sample
+	CpuStealPercent float64 `json:"cpuStealPercent"`
+	// IOPressureSome and IOPressureFull are the 10s averages from /proc/pressure/io
+	IOPressureSome float64 `json:"ioPressureSome"`
+	IOPressureFull float64 `json:"ioPressureFull"`
+	// CachedImages lists the image references present in the node's local image cache
+	CachedImages []string `json:"cachedImages"`
+}
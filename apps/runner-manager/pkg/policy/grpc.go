@@ -0,0 +1,92 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+)
+
+// The external evaluator speaks gRPC with JSON encoded messages (content-type application/grpc+json),
+// so evaluators can be written in any language without sharing generated protobuf code.
+const (
+	GRPCServiceName    = "daytona.runnermanager.ScalingPolicy"
+	GRPCEvaluateMethod = "/" + GRPCServiceName + "/Evaluate"
+	GRPCCodecName      = "json"
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                       { return GRPCCodecName }
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// GRPCEvaluator evaluates the scaling policy on an external gRPC service
+type GRPCEvaluator struct {
+	conn    *grpc.ClientConn
+	timeout time.Duration
+}
+
+// Type check
+var _ IScalingPolicy = &GRPCEvaluator{}
+
+func NewGRPCEvaluator(address string, timeout time.Duration) (*GRPCEvaluator, error) {
+	conn, err := grpc.NewClient(address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(GRPCCodecName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GRPCEvaluator{
+		conn:    conn,
+		timeout: timeout,
+	}, nil
+}
+
+func (e *GRPCEvaluator) Evaluate(input *Input) (*Decision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	var decision Decision
+	if err := e.conn.Invoke(ctx, GRPCEvaluateMethod, input, &decision); err != nil {
+		return nil, err
+	}
+	return &decision, nil
+}
+
+func (e *GRPCEvaluator) Close() error {
+	return e.conn.Close()
+}
+
+// RegisterGRPCServer serves a Go scaling policy implementation as an external evaluator
+func RegisterGRPCServer(server *grpc.Server, impl IScalingPolicy) {
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: GRPCServiceName,
+		HandlerType: (*IScalingPolicy)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "Evaluate",
+				Handler: func(srv any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+					var input Input
+					if err := dec(&input); err != nil {
+						return nil, err
+					}
+					return srv.(IScalingPolicy).Evaluate(&input)
+				},
+			},
+		},
+		Metadata: "scaling_policy",
+	}, impl)
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package policy
+
+import (
+	"net/rpc"
+	"time"
+
+	"github.com/hashicorp/go-plugin"
+)
+
+// IScalingPolicy decides how many nodes the pool should gain or lose in a controller cycle
+type IScalingPolicy interface {
+	Evaluate(input *Input) (*Decision, error)
+}
+
+// Input is the cluster snapshot a policy evaluates
+type Input struct {
+	RegionID            string       `json:"regionId"`
+	Runners             []Runner     `json:"runners"`
+	Nodes               []Node       `json:"nodes"`
+	PendingPlaceholders int          `json:"pendingPlaceholders"`
+	Metrics             Metrics      `json:"metrics"`
+	Config              ConfigLimits `json:"config"`
+}
+
+// RunnerCategory is the controller's classification of a runner
+type RunnerCategory string
+
+const (
+	RunnerCategoryActive    RunnerCategory = "active"
+	RunnerCategoryIdle      RunnerCategory = "idle"
+	RunnerCategoryDeletable RunnerCategory = "deletable"
+)
+
+// Runner is a Daytona runner in the pool
+type Runner struct {
+	ID                 string         `json:"id"`
+	Domain             string         `json:"domain"`
+	NodeName           string         `json:"nodeName,omitempty"`
+	Category           RunnerCategory `json:"category"`
+	Cpu                float32        `json:"cpu"`
+	MemoryGiB          float32        `json:"memoryGiB"`
+	AllocatedCpu       float32        `json:"allocatedCpu"`
+	AllocatedMemoryGiB float32        `json:"allocatedMemoryGiB"`
+	DiskGiB            float32        `json:"diskGiB,omitempty"`
+	AllocatedDiskGiB   float32        `json:"allocatedDiskGiB,omitempty"`
+	StartedSandboxes   float32        `json:"startedSandboxes"`
+}
+
+// Node is a Kubernetes node in the pool
+type Node struct {
+	Name                 string            `json:"name"`
+	Labels               map[string]string `json:"labels,omitempty"`
+	Unschedulable        bool              `json:"unschedulable"`
+	Nascent              bool              `json:"nascent"`
+	AllocatableCpu       float32           `json:"allocatableCpu"`
+	AllocatableMemoryGiB float32           `json:"allocatableMemoryGiB"`
+}
+
+// Metrics holds the aggregated pool resources
+type Metrics struct {
+	TotalCPUCapacity        float32 `json:"totalCpuCapacity"`
+	TotalMemoryGiBCapacity  float32 `json:"totalMemoryGiBCapacity"`
+	TotalAllocatedCPU       float32 `json:"totalAllocatedCpu"`
+	TotalAllocatedMemoryGiB float32 `json:"totalAllocatedMemoryGiB"`
+	TotalAvailableCPU       float32 `json:"totalAvailableCpu"`
+	TotalAvailableMemoryGiB float32 `json:"totalAvailableMemoryGiB"`
+	AvgCpuPerNode           float32 `json:"avgCpuPerNode"`
+	AvgMemPerNode           float32 `json:"avgMemPerNode"`
+	TotalGPUCapacity        float32 `json:"totalGpuCapacity,omitempty"`
+	TotalAvailableGPU       float32 `json:"totalAvailableGpu,omitempty"`
+	AvgGpuPerNode           float32 `json:"avgGpuPerNode,omitempty"`
+	TotalDiskGiBCapacity    float32 `json:"totalDiskGiBCapacity,omitempty"`
+	TotalAvailableDiskGiB   float32 `json:"totalAvailableDiskGiB,omitempty"`
+	AvgDiskPerNode          float32 `json:"avgDiskPerNode,omitempty"`
+}
+
+// ConfigLimits holds the runner-manager thresholds so policies can build on them
+type ConfigLimits struct {
+	MaxResourceUtilizationPercent int `json:"maxResourceUtilizationPercent"`
+	MinIdleRunners                int `json:"minIdleRunners"`
+	MinIdleCpu                    int `json:"minIdleCpu"`
+	MinIdleMemory                 int `json:"minIdleMemory"`
+	MinIdleGpu                    int `json:"minIdleGpu,omitempty"`
+	MinIdleDisk                   int `json:"minIdleDisk,omitempty"`
+	MaxDiskUtilizationPercent     int `json:"maxDiskUtilizationPercent,omitempty"`
+	ScaleDownUtilizationPercent   int `json:"scaleDownUtilizationPercent,omitempty"`
+}
+
+// Decision is the outcome of a policy evaluation
+type Decision struct {
+	// NodeDelta is the number of nodes to add (positive) or remove (negative). Removals only
+	// target deletable runners and still pass the controller's safety checks.
+	NodeDelta int `json:"nodeDelta"`
+	// Defer hands the cycle back to the built-in policy
+	Defer  bool   `json:"defer"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// HandshakeConfig is shared by runner-manager and scaling policy plugins
+var HandshakeConfig = plugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "DAYTONA_SCALING_POLICY_PLUGIN",
+	MagicCookieValue: "daytona_scaling_policy",
+}
+
+// PluginName is the name a scaling policy plugin binary must serve its implementation under
+const PluginName = "daytona-scaling-policy"
+
+type ScalingPolicyPlugin struct {
+	Impl IScalingPolicy
+	// Timeout bounds each evaluation through the plugin client, unbounded when zero
+	Timeout time.Duration
+}
+
+func (p *ScalingPolicyPlugin) Server(*plugin.MuxBroker) (any, error) {
+	return &ScalingPolicyRPCServer{Impl: p.Impl}, nil
+}
+
+func (p *ScalingPolicyPlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (any, error) {
+	return &ScalingPolicyRPCClient{client: c, timeout: p.Timeout}, nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package policy
+
+import (
+	"fmt"
+	"net/rpc"
+	"time"
+)
+
+type ScalingPolicyRPCClient struct {
+	client  *rpc.Client
+	timeout time.Duration
+}
+
+// Type check
+var _ IScalingPolicy = &ScalingPolicyRPCClient{}
+
+// Evaluate calls the plugin, giving up after the timeout so a hung plugin does not block the controller loop. The
+// abandoned call completes in the background if the plugin ever answers.
+func (m *ScalingPolicyRPCClient) Evaluate(input *Input) (*Decision, error) {
+	var resp Decision
+	call := m.client.Go("Plugin.Evaluate", input, &resp, nil)
+	if m.timeout <= 0 {
+		<-call.Done
+		return &resp, call.Error
+	}
+
+	timer := time.NewTimer(m.timeout)
+	defer timer.Stop()
+	select {
+	case <-call.Done:
+		return &resp, call.Error
+	case <-timer.C:
+		return nil, fmt.Errorf("scaling policy plugin did not answer within %s", m.timeout)
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package policy
+
+type ScalingPolicyRPCServer struct {
+	Impl IScalingPolicy
+}
+
+func (m *ScalingPolicyRPCServer) Evaluate(arg *Input, resp *Decision) error {
+	decision, err := m.Impl.Evaluate(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *decision
+	return nil
+}
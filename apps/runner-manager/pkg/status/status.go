@@ -0,0 +1,311 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package status
+
+import "time"
+
+// StatusPath is the runner-manager endpoint exposing the latest controller cycle snapshot
+const StatusPath = "/status"
+
+// Status is the snapshot of a region's pool as seen by runner-manager in its latest cycle
+type Status struct {
+	RegionID  string    `json:"regionId"`
+	Pool      string    `json:"pool,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+
+	Capacity            Capacity            `json:"capacity"`
+	Runners             RunnerCounts        `json:"runners"`
+	Nodes               int                 `json:"nodes"`
+	InFlight            []ScaleOperation    `json:"inFlight"`
+	ProvisioningLatency ProvisioningLatency `json:"provisioningLatency"`
+	// Traffic lists runners serving preview traffic, busiest first
+	Traffic []RunnerTraffic `json:"traffic"`
+	Packing PackingReport   `json:"packing"`
+}
+
+// Capacity holds aggregated pool resources
+type Capacity struct {
+	TotalCPU           float32 `json:"totalCpu"`
+	TotalMemoryGiB     float32 `json:"totalMemoryGiB"`
+	AllocatedCPU       float32 `json:"allocatedCpu"`
+	AllocatedMemoryGiB float32 `json:"allocatedMemoryGiB"`
+	AvailableCPU       float32 `json:"availableCpu"`
+	AvailableMemoryGiB float32 `json:"availableMemoryGiB"`
+	TotalGPU           float32 `json:"totalGpu,omitempty"`
+	AllocatedGPU       float32 `json:"allocatedGpu,omitempty"`
+	AvailableGPU       float32 `json:"availableGpu,omitempty"`
+	TotalDiskGiB       float32 `json:"totalDiskGiB,omitempty"`
+	AllocatedDiskGiB   float32 `json:"allocatedDiskGiB,omitempty"`
+	AvailableDiskGiB   float32 `json:"availableDiskGiB,omitempty"`
+}
+
+// RunnerCounts holds the number of runners per category
+type RunnerCounts struct {
+	Total     int `json:"total"`
+	Active    int `json:"active"`
+	Idle      int `json:"idle"`
+	Deletable int `json:"deletable"`
+	// Prewarming runners are new runners still pre-pulling the region's most-used snapshots
+	Prewarming int `json:"prewarming,omitempty"`
+}
+
+// ScaleOperationKind is the kind of an in-flight scale operation
+type ScaleOperationKind string
+
+const (
+	// ScaleOperationPendingNode is a placeholder waiting for a node to be provisioned
+	ScaleOperationPendingNode ScaleOperationKind = "pending-node"
+	// ScaleOperationNascentRunner is a provisioned node waiting for its runner to register
+	ScaleOperationNascentRunner ScaleOperationKind = "nascent-runner"
+)
+
+// ScaleOperation is a scale-up step that has been started but not completed
+type ScaleOperation struct {
+	Kind      ScaleOperationKind `json:"kind"`
+	Name      string             `json:"name"`
+	NodeName  string             `json:"nodeName,omitempty"`
+	StartedAt time.Time          `json:"startedAt"`
+}
+
+// ProvisioningLatency summarizes the time from placeholder creation to runner registration for recent scale-ups
+type ProvisioningLatency struct {
+	Samples     int     `json:"samples"`
+	P50Seconds  float64 `json:"p50Seconds"`
+	P95Seconds  float64 `json:"p95Seconds"`
+	LastSeconds float64 `json:"lastSeconds"`
+}
+
+// RunnerTraffic is the preview traffic served by a runner, used as a placement hint for traffic-heavy sandboxes
+type RunnerTraffic struct {
+	RunnerID          string  `json:"runnerId"`
+	Domain            string  `json:"domain"`
+	BytesPerSecond    float64 `json:"bytesPerSecond"`
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	Sandboxes         int     `json:"sandboxes"`
+}
+
+// PackingReport summarizes how well sandboxes are packed onto runners
+type PackingReport struct {
+	// SandboxCpu and SandboxMemoryGiB are the average sandbox shape the scores are computed for
+	SandboxCpu       float32 `json:"sandboxCpu"`
+	SandboxMemoryGiB float32 `json:"sandboxMemoryGiB"`
+	// DefragmentationOpportunityNodes is the number of active runners that could be freed by consolidating sandboxes
+	DefragmentationOpportunityNodes int     `json:"defragmentationOpportunityNodes"`
+	StrandedCpu                     float32 `json:"strandedCpu"`
+	StrandedMemoryGiB               float32 `json:"strandedMemoryGiB"`
+	// Runners are ordered by placement preference, best fit first
+	Runners []RunnerPacking `json:"runners"`
+}
+
+// RunnerPacking is the packing assessment of a single runner
+type RunnerPacking struct {
+	RunnerID string `json:"runnerId"`
+	Domain   string `json:"domain"`
+	// PackingScore ranks runners for new placements, higher is a better fit, 0 means the average sandbox does not fit
+	PackingScore float32 `json:"packingScore"`
+	// FragmentationScore is the share of the runner's capacity that is free but stranded by the other dimension being
+	// exhausted, averaged over CPU and memory
+	FragmentationScore float32 `json:"fragmentationScore"`
+	FreeCpu            float32 `json:"freeCpu"`
+	FreeMemoryGiB      float32 `json:"freeMemoryGiB"`
+}
+
+// DrainsPath is the runner-manager endpoint exposing the progress of nodes whose runner is draining
+const DrainsPath = "/drains"
+
+// NodeDrain is the progress of a node whose runner was made unschedulable and is waiting for its sandboxes to leave
+type NodeDrain struct {
+	NodeName string    `json:"nodeName"`
+	RunnerID string    `json:"runnerId"`
+	Domain   string    `json:"domain"`
+	Since    time.Time `json:"since"`
+	// EvictionAt is when the node is scheduled for removal, set only when eviction notices are enabled
+	EvictionAt *time.Time `json:"evictionAt,omitempty"`
+	// InitialSandboxes is the number of sandboxes hosted when runner-manager first saw the drain
+	InitialSandboxes   int `json:"initialSandboxes"`
+	SandboxesRemaining int `json:"sandboxesRemaining"`
+	RunningSandboxes   int `json:"runningSandboxes"`
+	// BlockingSandboxes are running sandboxes with auto-stop disabled, which keep the node until stopped by hand
+	BlockingSandboxes int `json:"blockingSandboxes"`
+	// EstimatedCompletion is when the last running sandbox auto-stops, nil while any running sandbox is blocking
+	EstimatedCompletion *time.Time     `json:"estimatedCompletion,omitempty"`
+	Sandboxes           []DrainSandbox `json:"sandboxes"`
+}
+
+// DrainSandbox is a sandbox keeping a draining node alive
+type DrainSandbox struct {
+	ID    string `json:"id"`
+	State string `json:"state"`
+	// AutoStopAt is estimated from the sandbox's last update and auto-stop interval
+	AutoStopAt *time.Time `json:"autoStopAt,omitempty"`
+}
+
+// MigrationPath is the runner-manager endpoint exposing the progress of a pool's migration to a new node pool
+const MigrationPath = "/migration"
+
+// PoolMigration is the progress of moving a pool's runners from its old nodes to the nodes of MIGRATION_NODE_SELECTOR
+type PoolMigration struct {
+	Pool         string            `json:"pool"`
+	NodeSelector map[string]string `json:"nodeSelector"`
+	OldNodes     int               `json:"oldNodes"`
+	NewNodes     int               `json:"newNodes"`
+	// OldRunners are the runners still on old nodes, DrainingRunners the part of them being replaced
+	OldRunners      int       `json:"oldRunners"`
+	DrainingRunners int       `json:"drainingRunners"`
+	NewRunners      int       `json:"newRunners"`
+	Completed       bool      `json:"completed"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// CostPath is the runner-manager endpoint exposing the estimated compute cost of a pool's nodes
+const CostPath = "/cost"
+
+// FleetCost is the estimated compute cost of a pool's running nodes from their instance types and configured prices
+type FleetCost struct {
+	RegionID   string  `json:"regionId"`
+	Pool       string  `json:"pool"`
+	HourlyCost float64 `json:"hourlyCost"`
+	// MonthToDateCost accumulates the hourly cost of every cycle since MonthToDateSince, the start of the month or of
+	// the process if it started later
+	MonthToDateCost  float64                     `json:"monthToDateCost"`
+	MonthToDateSince time.Time                   `json:"monthToDateSince"`
+	InstanceTypes    map[string]InstanceTypeCost `json:"instanceTypes"`
+	// UnpricedNodes are the running nodes left out of the cost as their price is unknown
+	UnpricedNodes int       `json:"unpricedNodes"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// InstanceTypeCost is the share of the fleet cost of the nodes of an instance type
+type InstanceTypeCost struct {
+	Nodes      int     `json:"nodes"`
+	HourlyCost float64 `json:"hourlyCost"`
+}
+
+// DecisionsPath is the runner-manager endpoint querying the persisted history of a pool's scaling decisions
+const DecisionsPath = "/decisions"
+
+// Decision is a scaling action taken by the controller loop, with what it was based on
+type Decision struct {
+	Time     time.Time `json:"time"`
+	RegionID string    `json:"regionId"`
+	Pool     string    `json:"pool"`
+	// Action is the scaling action, e.g. scale-up, scale-down or a manual action requested through the admin API
+	Action  string          `json:"action"`
+	Inputs  DecisionInputs  `json:"inputs"`
+	Metrics DecisionMetrics `json:"metrics"`
+	Outcome DecisionOutcome `json:"outcome"`
+}
+
+// DecisionInputs are the thresholds the decision was made with, including schedule and forecast adjustments
+type DecisionInputs struct {
+	MinIdleRunners                int `json:"minIdleRunners"`
+	MinIdleCpu                    int `json:"minIdleCpu"`
+	MinIdleMemory                 int `json:"minIdleMemory"`
+	MaxResourceUtilizationPercent int `json:"maxResourceUtilizationPercent"`
+	MaxNodes                      int `json:"maxNodes"`
+	ScaleDownUtilizationPercent   int `json:"scaleDownUtilizationPercent,omitempty"`
+}
+
+// DecisionMetrics is the pool as seen by the cycle that made the decision
+type DecisionMetrics struct {
+	Capacity            Capacity     `json:"capacity"`
+	Runners             RunnerCounts `json:"runners"`
+	Nodes               int          `json:"nodes"`
+	NascentNodes        int          `json:"nascentNodes"`
+	PendingPlaceholders int          `json:"pendingPlaceholders"`
+	QueuedSandboxes     int          `json:"queuedSandboxes"`
+}
+
+// DecisionOutcome is what the cycle did up to and including the decision
+type DecisionOutcome struct {
+	// PlaceholdersCreated counts the placeholders created by the cycle's scale-ups so far
+	PlaceholdersCreated int `json:"placeholdersCreated"`
+}
+
+// WhyPath is the runner-manager endpoint explaining the scaling decisions of the latest controller cycle
+const WhyPath = "/why"
+
+// Explanation is why the latest controller cycle of a pool scaled as it did, or did not scale
+type Explanation struct {
+	RegionID  string    `json:"regionId"`
+	Pool      string    `json:"pool,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	// Actions are the scaling actions the cycle took, as recorded in the decision history
+	Actions   []string             `json:"actions"`
+	ScaleUp   ScaleUpExplanation   `json:"scaleUp"`
+	ScaleDown ScaleDownExplanation `json:"scaleDown"`
+}
+
+// ScaleUpConditionName identifies a scale-up condition
+type ScaleUpConditionName string
+
+// Scale-up conditions: utilization above its maximum, idle resources below their MIN_IDLE_* setting and sandboxes
+// queued with no idle runner
+const (
+	ConditionCpuUtilization    ScaleUpConditionName = "cpu-utilization"
+	ConditionMemoryUtilization ScaleUpConditionName = "memory-utilization"
+	ConditionGpuUtilization    ScaleUpConditionName = "gpu-utilization"
+	ConditionDiskUtilization   ScaleUpConditionName = "disk-utilization"
+	ConditionIdleRunners       ScaleUpConditionName = "idle-runners"
+	ConditionIdleCpu           ScaleUpConditionName = "idle-cpu"
+	ConditionIdleMemory        ScaleUpConditionName = "idle-memory"
+	ConditionIdleGpu           ScaleUpConditionName = "idle-gpu"
+	ConditionIdleDisk          ScaleUpConditionName = "idle-disk"
+	ConditionQueueStarved      ScaleUpConditionName = "queue-starved"
+)
+
+// ScaleUpExplanation is the evaluation of the scale-up conditions and the nodes added when any fired
+type ScaleUpExplanation struct {
+	Conditions []ScaleUpCondition `json:"conditions"`
+	// NodesNeeded is the largest node deficit of the fired conditions, of which InFlight placeholders were already
+	// waiting for a node
+	NodesNeeded   int `json:"nodesNeeded"`
+	InFlight      int `json:"inFlight"`
+	NodesPromoted int `json:"nodesPromoted,omitempty"`
+	NodesResumed  int `json:"nodesResumed,omitempty"`
+	NodesCreated  int `json:"nodesCreated"`
+	// CappedBy is the limit that capped the scale-up, MAX_NODES or MAX_RUNNERS
+	CappedBy string `json:"cappedBy,omitempty"`
+	// Skipped is why no scale-up was attempted although a condition fired, e.g. the scale-up cooldown
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// ScaleUpCondition is a scale-up condition with the value and threshold it was evaluated with. Utilization conditions
+// fire above their threshold, the others below it.
+type ScaleUpCondition struct {
+	Name      ScaleUpConditionName `json:"name"`
+	Fired     bool                 `json:"fired"`
+	Value     float32              `json:"value"`
+	Threshold float32              `json:"threshold"`
+	// Deficit is the number of nodes the condition needed, set on fired conditions sized in nodes
+	Deficit int `json:"deficit,omitempty"`
+}
+
+// ScaleDownOutcome is what scale-down did with a deletable runner
+type ScaleDownOutcome string
+
+// Outcomes of a deletable runner: kept, or its node removed or hibernated
+const (
+	ScaleDownKept       ScaleDownOutcome = "kept"
+	ScaleDownRemoved    ScaleDownOutcome = "removed"
+	ScaleDownHibernated ScaleDownOutcome = "hibernated"
+)
+
+// ScaleDownExplanation lists the deletable runners scale-down considered and what it did with each
+type ScaleDownExplanation struct {
+	// Skipped is why scale-down was not evaluated, e.g. a scale-up in the same cycle or the scale-down cooldown
+	Skipped string               `json:"skipped,omitempty"`
+	Runners []ScaleDownCandidate `json:"runners"`
+}
+
+// ScaleDownCandidate is a deletable runner considered for removal
+type ScaleDownCandidate struct {
+	RunnerID string           `json:"runnerId"`
+	Domain   string           `json:"domain"`
+	NodeName string           `json:"nodeName,omitempty"`
+	Outcome  ScaleDownOutcome `json:"outcome"`
+	// Reason is the reason logged when the runner was kept, and Message the explanation recorded on its node
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,296 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultPoolName is the name of the pool when RUNNER_POOLS is not set
+const DefaultPoolName = "default"
+
+// placeholderTemplateData holds the variables available to the placeholder pod template
+type placeholderTemplateData struct {
+	Name      string
+	Namespace string
+	App       string
+	Region    string
+	Pool      string
+	// OS is the operating system of the pool's nodes, as in the kubernetes.io/os node label
+	OS string
+	// Zone is empty unless the placeholder is created for a zone's idle requirement
+	Zone string
+	// Profile is the node profile the placeholder requests, empty when the pool has no NODE_PROFILES
+	Profile string
+	// Class is the reserved sandbox class the placeholder makes room for, empty for other placeholders
+	Class string
+	// GPUs is the number of GPUs the placeholder requests, zero outside of GPU pools
+	GPUs int
+	// CapacityType is "spot" or "on-demand" in pools using spot capacity, empty otherwise
+	CapacityType string
+}
+
+// placeholderOptions are the settings of a single placeholder on top of the pool's configuration
+type placeholderOptions struct {
+	// Zone pins the placeholder to an availability zone, any zone when empty
+	Zone string
+	// CapacityType selects spot or on-demand nodes, empty when the pool does not use spot capacity
+	CapacityType string
+	// Profile selects the node profile, the default one when empty; unused when the pool has no NODE_PROFILES
+	Profile string
+	// Class sizes the placeholder for a reserved sandbox class, none when empty
+	Class string
+	// Standby marks the placeholder as adding a warm standby node
+	Standby bool
+}
+
+// parseTaintEffect parses the effect of the pool's taint, TAINT_EFFECT or a pool's taintEffect
+func parseTaintEffect(value string) (corev1.TaintEffect, error) {
+	switch effect := corev1.TaintEffect(value); effect {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		return effect, nil
+	}
+	return "", fmt.Errorf("taint effect must be one of %q, %q or %q", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute)
+}
+
+// parsePlaceholderTolerations parses PLACEHOLDER_TOLERATIONS, a JSON list of Kubernetes tolerations, e.g.
+// [{"key": "dedicated", "operator": "Equal", "value": "sandboxes", "effect": "NoSchedule"}]
+func parsePlaceholderTolerations(value string) ([]corev1.Toleration, error) {
+	var tolerations []corev1.Toleration
+	if err := yaml.UnmarshalStrict([]byte(value), &tolerations); err != nil {
+		return nil, err
+	}
+	if err := validateTolerations(tolerations); err != nil {
+		return nil, err
+	}
+	return tolerations, nil
+}
+
+// validateTolerations checks the operators and effects of placeholder tolerations, which the API server would
+// otherwise only reject when a placeholder is created
+func validateTolerations(tolerations []corev1.Toleration) error {
+	for _, toleration := range tolerations {
+		switch toleration.Operator {
+		case "", corev1.TolerationOpEqual:
+		case corev1.TolerationOpExists:
+			if toleration.Value != "" {
+				return fmt.Errorf("toleration of %q cannot have a value with the %s operator", toleration.Key, corev1.TolerationOpExists)
+			}
+		default:
+			return fmt.Errorf("toleration of %q has unknown operator %q", toleration.Key, toleration.Operator)
+		}
+		if toleration.Effect != "" {
+			if _, err := parseTaintEffect(string(toleration.Effect)); err != nil {
+				return fmt.Errorf("toleration of %q: %w", toleration.Key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// loadPlaceholderPodTemplate parses the placeholder pod template from PLACEHOLDER_POD_TEMPLATE_FILE or
+// PLACEHOLDER_POD_TEMPLATE and validates it by rendering it once. It returns nil if no template is configured.
+func loadPlaceholderPodTemplate(l *configLoader, cfg *Config) (*template.Template, error) {
+	source := l.get("PLACEHOLDER_POD_TEMPLATE")
+	if templateFile := l.get("PLACEHOLDER_POD_TEMPLATE_FILE"); templateFile != "" {
+		if source != "" {
+			return nil, fmt.Errorf("PLACEHOLDER_POD_TEMPLATE and PLACEHOLDER_POD_TEMPLATE_FILE cannot be set together")
+		}
+		content, err := os.ReadFile(templateFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read PLACEHOLDER_POD_TEMPLATE_FILE: %w", err)
+		}
+		source = string(content)
+	}
+	if source == "" {
+		return nil, nil
+	}
+	return parsePlaceholderPodTemplate(source, cfg)
+}
+
+// parsePlaceholderPodTemplate parses a placeholder pod template and validates it by rendering it once
+func parsePlaceholderPodTemplate(source string, cfg *Config) (*template.Template, error) {
+	tmpl, err := template.New("placeholder").Option("missingkey=error").Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid placeholder pod template: %w", err)
+	}
+
+	_, err = renderPlaceholderPod(tmpl, placeholderTemplateData{
+		Name:      PlaceholderPodLabel + "-validation",
+		Namespace: cfg.ProviderNamespace,
+		App:       PlaceholderPodLabel,
+		Region:    cfg.RegionID,
+		Pool:      DefaultPoolName,
+		OS:        cfg.PoolOS,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid placeholder pod template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// renderPlaceholderPod renders the template into a pod. The name, namespace, app label and zone are always
+// enforced since placeholder tracking relies on them.
+func renderPlaceholderPod(tmpl *template.Template, data placeholderTemplateData) (*corev1.Pod, error) {
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return nil, err
+	}
+
+	pod := &corev1.Pod{}
+	if err := yaml.UnmarshalStrict(rendered.Bytes(), pod); err != nil {
+		return nil, fmt.Errorf("rendered template is not a valid pod: %w", err)
+	}
+	if pod.Kind != "" && pod.Kind != "Pod" {
+		return nil, fmt.Errorf("rendered template has kind %q, expected Pod", pod.Kind)
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("rendered template has no containers")
+	}
+
+	pod.Name = data.Name
+	pod.GenerateName = ""
+	pod.Namespace = data.Namespace
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels["app"] = data.App
+	pinPlaceholderToZone(pod, data.Zone)
+
+	return pod, nil
+}
+
+// pinPlaceholderToZone labels the placeholder with its zone and restricts it to nodes in that zone
+func pinPlaceholderToZone(pod *corev1.Pod, zone string) {
+	if zone == "" {
+		return
+	}
+	pod.Labels[PlaceholderZoneLabel] = zone
+	if pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = map[string]string{}
+	}
+	pod.Spec.NodeSelector[ZoneLabel] = zone
+}
+
+// requestPlaceholderGPUs makes the placeholder request the GPUs, tolerating the taint GPU node groups usually carry
+func requestPlaceholderGPUs(pod *corev1.Pod, gpus int) {
+	if gpus <= 0 {
+		return
+	}
+	quantity := *resource.NewQuantity(int64(gpus), resource.DecimalSI)
+	// Extended resources cannot be overcommitted, so the request must equal the limit
+	pod.Spec.Containers[0].Resources = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{GPUResourceName: quantity},
+		Limits:   corev1.ResourceList{GPUResourceName: quantity},
+	}
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, corev1.Toleration{
+		Key:      string(GPUResourceName),
+		Operator: corev1.TolerationOpExists,
+		Effect:   corev1.TaintEffectNoSchedule,
+	})
+}
+
+// buildPlaceholderPod builds the placeholder pod from the configured template, or the built-in spec if there is none
+func buildPlaceholderPod(cfg *Config, podName, appName string, options placeholderOptions) (*corev1.Pod, error) {
+	if cfg.PlaceholderPodTemplate != nil {
+		pod, err := renderPlaceholderPod(cfg.PlaceholderPodTemplate, placeholderTemplateData{
+			Name:         podName,
+			Namespace:    cfg.ProviderNamespace,
+			App:          appName,
+			Region:       cfg.RegionID,
+			Pool:         cfg.PoolName,
+			OS:           cfg.PoolOS,
+			Zone:         options.Zone,
+			Profile:      profileName(cfg, options.Profile),
+			Class:        options.Class,
+			GPUs:         cfg.PlaceholderGpus,
+			CapacityType: options.CapacityType,
+		})
+		if err != nil {
+			return nil, err
+		}
+		selectPlaceholderCapacity(pod, cfg, options.CapacityType)
+		selectPlaceholderProfile(pod, cfg, options.Profile)
+		selectPlaceholderClass(pod, cfg, options.Class)
+		markPlaceholderStandby(pod, options.Standby)
+		selectPlaceholderMigration(pod, cfg)
+		spreadPlaceholderAcrossZones(pod, cfg, appName)
+		return pod, nil
+	}
+	zone := options.Zone
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podName,
+			Namespace: cfg.ProviderNamespace,
+			Labels: map[string]string{
+				"app": appName, // Label to easily find these pods later
+			},
+		},
+		Spec: corev1.PodSpec{
+			Affinity: &corev1.Affinity{
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+						{
+							LabelSelector: &metav1.LabelSelector{
+								MatchExpressions: []metav1.LabelSelectorRequirement{
+									{
+										Key:      "app",
+										Operator: metav1.LabelSelectorOpIn,
+										Values:   []string{appName},
+									},
+								},
+							},
+							TopologyKey: "kubernetes.io/hostname",
+						},
+					},
+				},
+			},
+			NodeSelector: map[string]string{
+				cfg.NodeSelectorKey:  "true",
+				corev1.LabelOSStable: cfg.PoolOS,
+			},
+			Tolerations: []corev1.Toleration{
+				{
+					Key:      cfg.TaintKey,
+					Operator: corev1.TolerationOpEqual,
+					Value:    "true",
+					Effect:   cfg.TaintEffect,
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:  "pause",
+					Image: cfg.PlaceholderImage,
+				},
+			},
+			PriorityClassName: cfg.PlaceholderPriorityClassName,
+			RestartPolicy:     corev1.RestartPolicyNever, // Don't restart if it completes
+		},
+	}
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, cfg.PlaceholderTolerations...)
+	for _, secret := range cfg.PlaceholderImagePullSecrets {
+		pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: secret})
+	}
+	requestPlaceholderGPUs(pod, cfg.PlaceholderGpus)
+	requestPlaceholderResources(pod, cfg)
+	pinPlaceholderToZone(pod, zone)
+	selectPlaceholderCapacity(pod, cfg, options.CapacityType)
+	selectPlaceholderProfile(pod, cfg, options.Profile)
+	selectPlaceholderClass(pod, cfg, options.Class)
+	markPlaceholderStandby(pod, options.Standby)
+	selectPlaceholderMigration(pod, cfg)
+	spreadPlaceholderAcrossZones(pod, cfg, appName)
+
+	return pod, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// PlaceholderRequestAuto sizes a placeholder request after the average started sandbox of the region
+	PlaceholderRequestAuto = "auto"
+
+	// PlaceholderSizingInterval is how often the average sandbox footprint of automatic requests is recomputed
+	PlaceholderSizingInterval = 15 * time.Minute
+)
+
+// parsePlaceholderRequest parses PLACEHOLDER_CPU_REQUEST or PLACEHOLDER_MEMORY_REQUEST, a Kubernetes quantity or
+// PlaceholderRequestAuto
+func parsePlaceholderRequest(value string) (quantity resource.Quantity, auto bool, err error) {
+	if value == PlaceholderRequestAuto {
+		return resource.Quantity{}, true, nil
+	}
+	quantity, err = resource.ParseQuantity(value)
+	if err != nil {
+		return resource.Quantity{}, false, err
+	}
+	if quantity.Sign() <= 0 {
+		return resource.Quantity{}, false, fmt.Errorf("must be positive")
+	}
+	return quantity, false, nil
+}
+
+// requestPlaceholderResources makes the placeholder request the configured CPU and memory. Without requests the
+// cluster autoscaler adds whichever node group fits a pause pod, with them it picks one sized for the sandboxes.
+func requestPlaceholderResources(pod *corev1.Pod, cfg *Config) {
+	requests := corev1.ResourceList{}
+	if !cfg.PlaceholderCpuRequest.IsZero() {
+		requests[corev1.ResourceCPU] = cfg.PlaceholderCpuRequest
+	}
+	if !cfg.PlaceholderMemoryRequest.IsZero() {
+		requests[corev1.ResourceMemory] = cfg.PlaceholderMemoryRequest
+	}
+	if len(requests) == 0 {
+		return
+	}
+
+	resources := &pod.Spec.Containers[0].Resources
+	if resources.Requests == nil {
+		resources.Requests = corev1.ResourceList{}
+	}
+	for name, quantity := range requests {
+		resources.Requests[name] = quantity
+	}
+}
+
+// placeholderSizer sets the automatic placeholder requests to the average footprint of the region's started
+// sandboxes, recomputed every PlaceholderSizingInterval. Until the first sandboxes start the requests stay unset.
+type placeholderSizer struct {
+	apiClient *daytona.APIClient
+	sized     time.Time
+}
+
+func newPlaceholderSizer(apiClient *daytona.APIClient) *placeholderSizer {
+	return &placeholderSizer{apiClient: apiClient}
+}
+
+// reconcile recomputes the automatic requests of the pool's placeholders when they are due
+func (s *placeholderSizer) reconcile(cfg *Config) {
+	if time.Since(s.sized) < PlaceholderSizingInterval {
+		return
+	}
+
+	sandboxes, err := listRegionSandboxes(s.apiClient, cfg.RegionID)
+	if err != nil {
+		log.Warnf("Could not size placeholders after the region's sandboxes, keeping the current requests: %v", err)
+		return
+	}
+	s.sized = time.Now()
+
+	var started int
+	var cpu, memoryGiB float64
+	for _, sandbox := range sandboxes {
+		if sandbox.GetState() != daytona.SANDBOXSTATE_STARTED || sandboxOS(sandbox) != cfg.PoolOS {
+			continue
+		}
+		started++
+		cpu += float64(sandbox.GetCpu())
+		memoryGiB += float64(sandbox.GetMemory())
+	}
+	if started == 0 {
+		log.Debug("No started sandboxes to size placeholders after, keeping the current requests.")
+		return
+	}
+
+	if cfg.PlaceholderCpuRequestAuto {
+		cfg.PlaceholderCpuRequest = *resource.NewMilliQuantity(int64(cpu/float64(started)*1000), resource.DecimalSI)
+	}
+	if cfg.PlaceholderMemoryRequestAuto {
+		cfg.PlaceholderMemoryRequest = *resource.NewQuantity(int64(memoryGiB/float64(started)*1024)*1024*1024, resource.BinarySI)
+	}
+	log.Infof("Sized placeholders after the average of %d started sandboxes: CPU=%s, Mem=%s.",
+		started, cfg.PlaceholderCpuRequest.String(), cfg.PlaceholderMemoryRequest.String())
+}
@@ -0,0 +1,570 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/daytonaio/daytona/apps/runner-manager/pkg/status"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// RunnerPoolPolicyGroup and RunnerPoolPolicyVersion are the API group and version of the RunnerPoolPolicy CRD
+	RunnerPoolPolicyGroup   = "daytona.io"
+	RunnerPoolPolicyVersion = "v1alpha1"
+	RunnerPoolPolicyKind    = "RunnerPoolPolicy"
+
+	runnerPoolPolicyResource = "runnerpoolpolicies"
+	runnerPoolPolicyCRDName  = runnerPoolPolicyResource + "." + RunnerPoolPolicyGroup
+
+	// RunnerPoolPolicyCRDHashAnnotation records the hash of the CRD spec to skip no-op updates
+	RunnerPoolPolicyCRDHashAnnotation = "daytona.io/crd-hash"
+)
+
+var (
+	runnerPoolPolicyGVR = schema.GroupVersionResource{Group: RunnerPoolPolicyGroup, Version: RunnerPoolPolicyVersion, Resource: runnerPoolPolicyResource}
+	crdGVR              = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+)
+
+// runnerPoolPolicySpec is the desired configuration of the pool named after the RunnerPoolPolicy. Unset fields take
+// the value of the top-level configuration.
+type runnerPoolPolicySpec struct {
+	NodeSelectorKey               string                       `json:"nodeSelectorKey,omitempty"`
+	TaintKey                      string                       `json:"taintKey,omitempty"`
+	TaintEffect                   string                       `json:"taintEffect,omitempty"`
+	Namespace                     string                       `json:"namespace,omitempty"`
+	MinIdleRunners                *int                         `json:"minIdleRunners,omitempty"`
+	MinIdleCpu                    *int                         `json:"minIdleCpu,omitempty"`
+	MinIdleMemory                 *int                         `json:"minIdleMemory,omitempty"`
+	MinIdleGpu                    *int                         `json:"minIdleGpu,omitempty"`
+	MinIdleDisk                   *int                         `json:"minIdleDisk,omitempty"`
+	MaxResourceUtilizationPercent *int                         `json:"maxResourceUtilizationPercent,omitempty"`
+	Placeholder                   *runnerPoolPolicyPlaceholder `json:"placeholder,omitempty"`
+}
+
+// runnerPoolPolicyPlaceholder is the placeholder spec of a RunnerPoolPolicy
+type runnerPoolPolicyPlaceholder struct {
+	Image string `json:"image,omitempty"`
+	// PodTemplate is a placeholder pod template, as in PLACEHOLDER_POD_TEMPLATE
+	PodTemplate string `json:"podTemplate,omitempty"`
+	Gpus        *int   `json:"gpus,omitempty"`
+
+	PriorityClassName string              `json:"priorityClassName,omitempty"`
+	Tolerations       []corev1.Toleration `json:"tolerations,omitempty"`
+	ImagePullSecrets  []string            `json:"imagePullSecrets,omitempty"`
+}
+
+// runnerPoolPolicyStatus is written back to the RunnerPoolPolicy by the pool's controller loop
+type runnerPoolPolicyStatus struct {
+	ObservedGeneration  int64               `json:"observedGeneration"`
+	Runners             status.RunnerCounts `json:"runners"`
+	Nodes               int                 `json:"nodes"`
+	PendingPlaceholders int                 `json:"pendingPlaceholders"`
+	LastDecision        string              `json:"lastDecision,omitempty"`
+	LastDecisionTime    *metav1.Time        `json:"lastDecisionTime,omitempty"`
+	// Error reports a spec that could not be applied, the previous values are kept meanwhile
+	Error string `json:"error,omitempty"`
+}
+
+// runnerPoolPolicy is a RunnerPoolPolicy as observed by the operator
+type runnerPoolPolicy struct {
+	Name       string
+	Generation int64
+	Spec       runnerPoolPolicySpec
+
+	placeholderTemplate *template.Template
+	err                 error
+}
+
+// structural returns the part of the spec that defines the pool's nodes and placeholders, which cannot change while
+// the pool's loop runs
+func (p *runnerPoolPolicy) structural() runnerPoolPolicySpec {
+	spec := runnerPoolPolicySpec{
+		NodeSelectorKey: p.Spec.NodeSelectorKey,
+		TaintKey:        p.Spec.TaintKey,
+		TaintEffect:     p.Spec.TaintEffect,
+		Namespace:       p.Spec.Namespace,
+	}
+	if p.Spec.Placeholder != nil && p.Spec.Placeholder.Gpus != nil {
+		spec.Placeholder = &runnerPoolPolicyPlaceholder{Gpus: p.Spec.Placeholder.Gpus}
+	}
+	return spec
+}
+
+// definition returns the pool definition of the policy
+func (p *runnerPoolPolicy) definition() poolDefinition {
+	definition := poolDefinition{
+		Name:            p.Name,
+		NodeSelectorKey: p.Spec.NodeSelectorKey,
+		TaintKey:        p.Spec.TaintKey,
+		TaintEffect:     p.Spec.TaintEffect,
+		Namespace:       p.Spec.Namespace,
+		MinIdleRunners:  p.Spec.MinIdleRunners,
+		MinIdleCpu:      p.Spec.MinIdleCpu,
+		MinIdleMemory:   p.Spec.MinIdleMemory,
+		MinIdleGpu:      p.Spec.MinIdleGpu,
+		MinIdleDisk:     p.Spec.MinIdleDisk,
+	}
+	if p.Spec.Placeholder != nil {
+		definition.PlaceholderGpus = p.Spec.Placeholder.Gpus
+	}
+	return definition
+}
+
+// poolPolicyOperator reconciles RunnerPoolPolicy resources. Pools are created from the policies at startup; the
+// thresholds and the placeholder settings of a policy, but for its GPUs, are applied by its pool's next cycle, while
+// adding or removing a policy or changing the nodes or placeholders it selects restarts runner-manager to rebuild its
+// pools. Each pool's loop writes its counts and last decision to the status of its policy.
+type poolPolicyOperator struct {
+	client   dynamic.Interface
+	defaults Config
+
+	mu       sync.Mutex
+	policies map[string]*runnerPoolPolicy
+	// started holds the structural spec of the policies the pools were created from
+	started map[string]runnerPoolPolicySpec
+	written map[string]runnerPoolPolicyStatus
+	restart func()
+}
+
+func newPoolPolicyOperator(cfg *Config) (*poolPolicyOperator, error) {
+	config, err := loadKubernetesConfig(cfg.KubeContext)
+	if err != nil {
+		return nil, err
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Kubernetes dynamic client: %w", err)
+	}
+	return &poolPolicyOperator{
+		client:   client,
+		defaults: *cfg,
+		policies: make(map[string]*runnerPoolPolicy),
+		started:  make(map[string]runnerPoolPolicySpec),
+		written:  make(map[string]runnerPoolPolicyStatus),
+	}, nil
+}
+
+// start installs the CRD and returns the pool definitions of the existing policies. Without any policy, the pool of
+// the top-level configuration is used until one is added.
+func (o *poolPolicyOperator) start(ctx context.Context) ([]poolDefinition, error) {
+	if err := o.reconcileCRD(ctx); err != nil {
+		return nil, err
+	}
+
+	list, err := o.client.Resource(runnerPoolPolicyGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s resources: %w", RunnerPoolPolicyKind, err)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var definitions []poolDefinition
+	for i := range list.Items {
+		policy, err := o.decode(&list.Items[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", RunnerPoolPolicyKind, list.Items[i].GetName(), err)
+		}
+		o.policies[policy.Name] = policy
+		o.started[policy.Name] = policy.structural()
+		definitions = append(definitions, policy.definition())
+	}
+	sort.Slice(definitions, func(i, j int) bool { return definitions[i].Name < definitions[j].Name })
+
+	if len(definitions) == 0 {
+		return nil, nil
+	}
+	if err := validatePoolDefinitions(definitions, &o.defaults); err != nil {
+		return nil, err
+	}
+	return definitions, nil
+}
+
+// watch follows the policies until the context is cancelled, calling restart when the pools must be rebuilt
+func (o *poolPolicyOperator) watch(ctx context.Context, restart func()) error {
+	o.mu.Lock()
+	o.restart = restart
+	o.mu.Unlock()
+
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(o.client, 0)
+	informer := factory.ForResource(runnerPoolPolicyGVR).Informer()
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj any) { o.observe(obj) },
+		UpdateFunc: func(_, obj any) { o.observe(obj) },
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if object, ok := obj.(*unstructured.Unstructured); ok {
+				o.remove(object.GetName())
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return fmt.Errorf("failed to sync the %s informer", RunnerPoolPolicyKind)
+	}
+	return nil
+}
+
+func (o *poolPolicyOperator) observe(obj any) {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	policy, err := o.decode(object)
+	if err != nil {
+		log.WithField("policy", object.GetName()).Errorf("Invalid %s %s: %v", RunnerPoolPolicyKind, object.GetName(), err)
+		return
+	}
+	if previous, found := o.policies[policy.Name]; found && policy.err != nil {
+		// The last valid template is kept until the policy is fixed
+		policy.placeholderTemplate = previous.placeholderTemplate
+	}
+	o.policies[policy.Name] = policy
+
+	started, found := o.started[policy.Name]
+	switch {
+	case !found:
+		o.requestRestart(fmt.Sprintf("%s %s was added", RunnerPoolPolicyKind, policy.Name))
+	case !reflect.DeepEqual(started, policy.structural()):
+		o.requestRestart(fmt.Sprintf("%s %s changed the nodes or placeholders of its pool", RunnerPoolPolicyKind, policy.Name))
+	}
+}
+
+func (o *poolPolicyOperator) remove(name string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.policies, name)
+	delete(o.written, name)
+	if _, found := o.started[name]; found {
+		o.requestRestart(fmt.Sprintf("%s %s was deleted", RunnerPoolPolicyKind, name))
+	}
+}
+
+func (o *poolPolicyOperator) requestRestart(reason string) {
+	if o.restart == nil {
+		return
+	}
+	log.Warnf("%s, restarting runner-manager to rebuild its pools.", reason)
+	o.restart()
+	o.restart = nil
+}
+
+// decode converts the resource into a policy, preparing its placeholder template. An invalid template is recorded
+// on the policy rather than returned, so it is reported in the policy's status.
+func (o *poolPolicyOperator) decode(object *unstructured.Unstructured) (*runnerPoolPolicy, error) {
+	policy := &runnerPoolPolicy{Name: object.GetName(), Generation: object.GetGeneration()}
+
+	if spec, found := object.Object["spec"]; found {
+		encoded, err := json.Marshal(spec)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(encoded, &policy.Spec); err != nil {
+			return nil, fmt.Errorf("invalid spec: %w", err)
+		}
+	}
+	if policy.Spec.TaintEffect != "" {
+		if _, err := parseTaintEffect(policy.Spec.TaintEffect); err != nil {
+			return nil, fmt.Errorf("invalid spec: %w", err)
+		}
+	}
+	// Checked here as well as by the schema, which cannot relate the threshold to SCALE_DOWN_UTILIZATION_PERCENT
+	if maxUtilization := policy.Spec.MaxResourceUtilizationPercent; maxUtilization != nil {
+		if *maxUtilization < 1 || *maxUtilization > 100 {
+			return nil, fmt.Errorf("invalid spec: maxResourceUtilizationPercent must be between 1 and 100")
+		}
+		if *maxUtilization <= o.defaults.ScaleDownUtilizationPercent {
+			return nil, fmt.Errorf("invalid spec: maxResourceUtilizationPercent must be above SCALE_DOWN_UTILIZATION_PERCENT (%d)", o.defaults.ScaleDownUtilizationPercent)
+		}
+	}
+	if placeholder := policy.Spec.Placeholder; placeholder != nil {
+		if err := validateTolerations(placeholder.Tolerations); err != nil {
+			return nil, fmt.Errorf("invalid placeholder tolerations: %w", err)
+		}
+	}
+
+	if placeholder := policy.Spec.Placeholder; placeholder != nil && placeholder.PodTemplate != "" {
+		poolCfg := policy.definition().apply(&o.defaults)
+		policy.placeholderTemplate, policy.err = parsePlaceholderPodTemplate(placeholder.PodTemplate, poolCfg)
+	}
+	return policy, nil
+}
+
+// reconcile applies the pool's policy to its configuration. The thresholds of the top-level configuration, with those
+// of the pool's region, are restored when the policy unsets them.
+func (o *poolPolicyOperator) reconcile(cfg *Config) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	policy, found := o.policies[cfg.PoolName]
+	if !found {
+		return
+	}
+	spec := policy.Spec
+	defaults := o.defaults
+	defaults.RegionThresholds[cfg.RegionID].apply(&defaults)
+
+	valueOr := func(value *int, fallback int) int {
+		if value != nil {
+			return *value
+		}
+		return fallback
+	}
+	cfg.MinIdleRunners = valueOr(spec.MinIdleRunners, defaults.MinIdleRunners)
+	cfg.MinIdleCpu = valueOr(spec.MinIdleCpu, defaults.MinIdleCpu)
+	cfg.MinIdleMemory = valueOr(spec.MinIdleMemory, defaults.MinIdleMemory)
+	cfg.MinIdleGpu = valueOr(spec.MinIdleGpu, defaults.MinIdleGpu)
+	cfg.MinIdleDisk = valueOr(spec.MinIdleDisk, defaults.MinIdleDisk)
+	cfg.MaxResourceUtilizationPercent = valueOr(spec.MaxResourceUtilizationPercent, defaults.MaxResourceUtilizationPercent)
+
+	cfg.PlaceholderImage = o.defaults.PlaceholderImage
+	cfg.PlaceholderPodTemplate = o.defaults.PlaceholderPodTemplate
+	cfg.PlaceholderPriorityClassName = o.defaults.PlaceholderPriorityClassName
+	cfg.PlaceholderTolerations = o.defaults.PlaceholderTolerations
+	cfg.PlaceholderImagePullSecrets = o.defaults.PlaceholderImagePullSecrets
+	if spec.Placeholder != nil {
+		if spec.Placeholder.Image != "" {
+			cfg.PlaceholderImage = spec.Placeholder.Image
+		}
+		if spec.Placeholder.PriorityClassName != "" {
+			cfg.PlaceholderPriorityClassName = spec.Placeholder.PriorityClassName
+		}
+		if len(spec.Placeholder.Tolerations) > 0 {
+			cfg.PlaceholderTolerations = spec.Placeholder.Tolerations
+		}
+		if len(spec.Placeholder.ImagePullSecrets) > 0 {
+			cfg.PlaceholderImagePullSecrets = spec.Placeholder.ImagePullSecrets
+		}
+		if policy.placeholderTemplate != nil {
+			cfg.PlaceholderPodTemplate = policy.placeholderTemplate
+		}
+	}
+}
+
+// writeStatus writes the pool's latest counts and decision to the status of its policy, if they changed
+func (o *poolPolicyOperator) writeStatus(poolName string, statuses *statusStore) {
+	o.mu.Lock()
+	policy, found := o.policies[poolName]
+	if !found {
+		o.mu.Unlock()
+		return
+	}
+
+	policyStatus := runnerPoolPolicyStatus{ObservedGeneration: policy.Generation}
+	if snapshot := statuses.get(); snapshot != nil {
+		policyStatus.Runners = snapshot.Runners
+		policyStatus.Nodes = snapshot.Nodes
+		for _, operation := range snapshot.InFlight {
+			if operation.Kind == status.ScaleOperationPendingNode {
+				policyStatus.PendingPlaceholders++
+			}
+		}
+	}
+	if decision, decisionAt := statuses.lastDecision(); decision != "" {
+		policyStatus.LastDecision = decision
+		policyStatus.LastDecisionTime = &metav1.Time{Time: decisionAt.Truncate(time.Second)}
+	}
+	if policy.err != nil {
+		policyStatus.Error = policy.err.Error()
+	}
+
+	written, found := o.written[poolName]
+	o.mu.Unlock()
+	if found && reflect.DeepEqual(written, policyStatus) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	patch, err := json.Marshal(map[string]any{"status": policyStatus})
+	if err != nil {
+		log.Errorf("Error encoding the status of %s %s: %v", RunnerPoolPolicyKind, poolName, err)
+		return
+	}
+	_, err = o.client.Resource(runnerPoolPolicyGVR).Patch(ctx, poolName, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	if err != nil {
+		log.WithField("policy", poolName).Errorf("Error writing the status of %s %s: %v", RunnerPoolPolicyKind, poolName, err)
+		return
+	}
+
+	o.mu.Lock()
+	o.written[poolName] = policyStatus
+	o.mu.Unlock()
+}
+
+// reconcileCRD creates or updates the RunnerPoolPolicy CRD
+func (o *poolPolicyOperator) reconcileCRD(ctx context.Context) error {
+	spec := runnerPoolPolicyCRDSpec()
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	hashBytes := sha256.Sum256(specJSON)
+	hash := hex.EncodeToString(hashBytes[:])
+
+	crd := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata": map[string]any{
+			"name":        runnerPoolPolicyCRDName,
+			"annotations": map[string]any{RunnerPoolPolicyCRDHashAnnotation: hash},
+		},
+		"spec": spec,
+	}}
+
+	crds := o.client.Resource(crdGVR)
+	existing, err := crds.Get(ctx, runnerPoolPolicyCRDName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		if _, err := crds.Create(ctx, crd, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create %s CRD: %w", RunnerPoolPolicyKind, err)
+		}
+		log.Infof("Created CRD %s", runnerPoolPolicyCRDName)
+		return o.waitForCRD(ctx)
+	case err != nil:
+		return fmt.Errorf("failed to get %s CRD: %w", RunnerPoolPolicyKind, err)
+	case existing.GetAnnotations()[RunnerPoolPolicyCRDHashAnnotation] != hash:
+		crd.SetResourceVersion(existing.GetResourceVersion())
+		if _, err := crds.Update(ctx, crd, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update %s CRD: %w", RunnerPoolPolicyKind, err)
+		}
+		log.Infof("Updated CRD %s", runnerPoolPolicyCRDName)
+	}
+	return nil
+}
+
+// waitForCRD waits until a new CRD is served, listing its resources fails until then
+func (o *poolPolicyOperator) waitForCRD(ctx context.Context) error {
+	for {
+		_, err := o.client.Resource(runnerPoolPolicyGVR).List(ctx, metav1.ListOptions{Limit: 1})
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s CRD not served: %w", RunnerPoolPolicyKind, err)
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// runnerPoolPolicyCRDSpec returns the spec of the cluster-scoped RunnerPoolPolicy CRD, with a status subresource
+func runnerPoolPolicyCRDSpec() map[string]any {
+	integer := func(minimum int) map[string]any {
+		return map[string]any{"type": "integer", "minimum": minimum}
+	}
+	str := map[string]any{"type": "string"}
+	taintEffect := map[string]any{
+		"type": "string",
+		"enum": []any{string(corev1.TaintEffectNoSchedule), string(corev1.TaintEffectPreferNoSchedule), string(corev1.TaintEffectNoExecute)},
+	}
+
+	return map[string]any{
+		"group": RunnerPoolPolicyGroup,
+		"scope": "Cluster",
+		"names": map[string]any{
+			"kind":       RunnerPoolPolicyKind,
+			"listKind":   RunnerPoolPolicyKind + "List",
+			"plural":     runnerPoolPolicyResource,
+			"singular":   "runnerpoolpolicy",
+			"shortNames": []any{"rpp"},
+		},
+		"versions": []any{map[string]any{
+			"name":    RunnerPoolPolicyVersion,
+			"served":  true,
+			"storage": true,
+			"subresources": map[string]any{
+				"status": map[string]any{},
+			},
+			"additionalPrinterColumns": []any{
+				map[string]any{"name": "Idle", "type": "integer", "jsonPath": ".status.runners.idle"},
+				map[string]any{"name": "Active", "type": "integer", "jsonPath": ".status.runners.active"},
+				map[string]any{"name": "Nodes", "type": "integer", "jsonPath": ".status.nodes"},
+				map[string]any{"name": "Last Decision", "type": "string", "jsonPath": ".status.lastDecision"},
+			},
+			"schema": map[string]any{
+				"openAPIV3Schema": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"spec": map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"nodeSelectorKey":               str,
+								"taintKey":                      str,
+								"taintEffect":                   taintEffect,
+								"namespace":                     str,
+								"minIdleRunners":                integer(0),
+								"minIdleCpu":                    integer(0),
+								"minIdleMemory":                 integer(0),
+								"minIdleGpu":                    integer(0),
+								"minIdleDisk":                   integer(0),
+								"maxResourceUtilizationPercent": map[string]any{"type": "integer", "minimum": 1, "maximum": 100},
+								"placeholder": map[string]any{
+									"type": "object",
+									"properties": map[string]any{
+										"image":             str,
+										"podTemplate":       str,
+										"gpus":              integer(0),
+										"priorityClassName": str,
+										"tolerations": map[string]any{
+											"type": "array",
+											"items": map[string]any{
+												"type": "object",
+												"properties": map[string]any{
+													"key":               str,
+													"operator":          str,
+													"value":             str,
+													"effect":            str,
+													"tolerationSeconds": map[string]any{"type": "integer"},
+												},
+											},
+										},
+										"imagePullSecrets": map[string]any{"type": "array", "items": str},
+									},
+								},
+							},
+						},
+						"status": map[string]any{
+							"type":                                 "object",
+							"x-kubernetes-preserve-unknown-fields": true,
+						},
+					},
+				},
+			},
+		}},
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PoolQueryParam selects the pool of the admin endpoints serving a single pool, the primary pool when unset
+const PoolQueryParam = "pool"
+
+// poolDefinition is a pool of RUNNER_POOLS, in the format
+// [{"name": "general"}, {"name": "high-memory", "nodeSelectorKey": "daytona-sandbox-m", "namespace": "runners-m", "minIdleCpu": 64}].
+// Unset fields take the value of the top-level configuration. A pool with its own regionId and kubeContext manages
+// another region from its cluster, so a single deployment can serve several small regions.
+type poolDefinition struct {
+	Name            string `json:"name"`
+	RegionID        string `json:"regionId"`
+	KubeContext     string `json:"kubeContext"`
+	NodeSelectorKey string `json:"nodeSelectorKey"`
+	TaintKey        string `json:"taintKey"`
+	TaintEffect     string `json:"taintEffect"`
+	Namespace       string `json:"namespace"`
+	MinIdleRunners  *int   `json:"minIdleRunners"`
+	MinIdleCpu      *int   `json:"minIdleCpu"`
+	MinIdleMemory   *int   `json:"minIdleMemory"`
+	MinIdleGpu      *int   `json:"minIdleGpu"`
+	MinIdleDisk     *int   `json:"minIdleDisk"`
+	PlaceholderGpus *int   `json:"placeholderGpus"`
+}
+
+// parsePoolDefinitions parses and validates RUNNER_POOLS. Pools of a cluster are told apart by their nodes and
+// placeholders, so each needs its own node selector key and namespace.
+func parsePoolDefinitions(value string, cfg *Config) ([]poolDefinition, error) {
+	var definitions []poolDefinition
+	if err := json.Unmarshal([]byte(value), &definitions); err != nil {
+		return nil, err
+	}
+	if len(definitions) == 0 {
+		return nil, fmt.Errorf("no pools defined")
+	}
+	if err := validatePoolDefinitions(definitions, cfg); err != nil {
+		return nil, err
+	}
+	return definitions, nil
+}
+
+// validatePoolDefinitions checks that pool names are unique, and namespaces and node selector keys unique within a
+// cluster
+func validatePoolDefinitions(definitions []poolDefinition, cfg *Config) error {
+	names := make(map[string]bool)
+	namespaces := make(map[[2]string]string)
+	nodeSelectorKeys := make(map[[2]string]string)
+	for _, definition := range definitions {
+		if definition.Name == "" {
+			return fmt.Errorf("every pool needs a name")
+		}
+		if names[definition.Name] {
+			return fmt.Errorf("pool %q is defined more than once", definition.Name)
+		}
+		names[definition.Name] = true
+
+		poolCfg := definition.apply(cfg)
+		namespace := [2]string{poolCfg.KubeContext, poolCfg.ProviderNamespace}
+		if other, found := namespaces[namespace]; found {
+			return fmt.Errorf("pools %q and %q share namespace %q", other, definition.Name, poolCfg.ProviderNamespace)
+		}
+		namespaces[namespace] = definition.Name
+		nodeSelectorKey := [2]string{poolCfg.KubeContext, poolCfg.NodeSelectorKey}
+		if other, found := nodeSelectorKeys[nodeSelectorKey]; found {
+			return fmt.Errorf("pools %q and %q share node selector key %q", other, definition.Name, poolCfg.NodeSelectorKey)
+		}
+		nodeSelectorKeys[nodeSelectorKey] = definition.Name
+
+		if definition.TaintEffect != "" {
+			if _, err := parseTaintEffect(definition.TaintEffect); err != nil {
+				return fmt.Errorf("pool %q: %w", definition.Name, err)
+			}
+		}
+		if poolCfg.MinIdleRunners < 0 || poolCfg.MinIdleCpu < 0 || poolCfg.MinIdleMemory < 0 || poolCfg.MinIdleGpu < 0 || poolCfg.MinIdleDisk < 0 || poolCfg.PlaceholderGpus < 0 {
+			return fmt.Errorf("pool %q has a negative idle threshold or GPU count", definition.Name)
+		}
+	}
+
+	return nil
+}
+
+// apply returns the configuration of the pool, a copy of the top-level configuration with the pool's settings
+func (d poolDefinition) apply(cfg *Config) *Config {
+	poolCfg := *cfg
+	poolCfg.Pools = nil
+	poolCfg.PoolName = d.Name
+
+	if d.RegionID != "" {
+		poolCfg.RegionID = d.RegionID
+	}
+	if d.KubeContext != "" {
+		poolCfg.KubeContext = d.KubeContext
+	}
+	// The region's thresholds take precedence over the top-level ones, the pool's own over both
+	cfg.RegionThresholds[poolCfg.RegionID].apply(&poolCfg)
+	if d.NodeSelectorKey != "" {
+		poolCfg.NodeSelectorKey = d.NodeSelectorKey
+	}
+	if d.TaintKey != "" {
+		poolCfg.TaintKey = d.TaintKey
+	}
+	if d.TaintEffect != "" {
+		poolCfg.TaintEffect = corev1.TaintEffect(d.TaintEffect)
+	}
+	if d.Namespace != "" {
+		poolCfg.ProviderNamespace = d.Namespace
+	}
+	if d.MinIdleRunners != nil {
+		poolCfg.MinIdleRunners = *d.MinIdleRunners
+	}
+	if d.MinIdleCpu != nil {
+		poolCfg.MinIdleCpu = *d.MinIdleCpu
+	}
+	if d.MinIdleMemory != nil {
+		poolCfg.MinIdleMemory = *d.MinIdleMemory
+	}
+	if d.MinIdleGpu != nil {
+		poolCfg.MinIdleGpu = *d.MinIdleGpu
+	}
+	if d.MinIdleDisk != nil {
+		poolCfg.MinIdleDisk = *d.MinIdleDisk
+	}
+	if d.PlaceholderGpus != nil {
+		poolCfg.PlaceholderGpus = *d.PlaceholderGpus
+	}
+	if poolCfg.MinIdleGpu > 0 && poolCfg.PlaceholderGpus == 0 {
+		poolCfg.PlaceholderGpus = 1
+	}
+
+	return &poolCfg
+}
+
+// runnerPool is a pool managed by its own controller loop, with its own configuration, backend and status
+type runnerPool struct {
+	cfg       *Config
+	backend   clusterBackend
+	clientset *kubernetes.Clientset
+	statuses  *statusStore
+	tuner     *idleTuner
+	manual    *manualActions
+	health    *loopHealth
+	// breaker degrades the controller loop after repeated control-plane errors, nil if CIRCUIT_BREAKER_ERROR_CYCLES is 0
+	breaker *controlPlaneBreaker
+	// policies applies the pool's RunnerPoolPolicy, nil unless RUNNER_POOL_POLICIES_ENABLED is set
+	policies *poolPolicyOperator
+	// definition is the pool's entry of RUNNER_POOLS, empty for the single default pool
+	definition poolDefinition
+	// reloader applies the thresholds of CONFIG_DIR, nil unless set; reloaded is the generation last applied
+	reloader *configReloader
+	reloaded int
+	// migration moves the pool to the nodes of MIGRATION_NODE_SELECTOR, nil unless set
+	migration *nodePoolMigration
+	// cost estimates the cost of the pool's nodes, nil unless node prices are configured
+	cost *costTracker
+
+	// primary is set on the first pool, whose loop also reconciles the resources shared by all pools: log forwarding
+	// and the admission webhook of its cluster, and the pool configuration of its region
+	primary bool
+	// shared is set when the region's runners are split between several pools, each pool then only counts the runners
+	// of its own nodes
+	shared bool
+
+	// published is a copy of cfg as of the latest cycle, for the HTTP handlers: the loop adopts drift, reloads and
+	// applies policies to cfg in place, which the handlers must not read concurrently
+	mu        sync.Mutex
+	published *Config
+}
+
+// publishConfig makes a copy of the configuration of the running cycle available to the HTTP handlers, to be called
+// from the pool's loop
+func (p *runnerPool) publishConfig() {
+	published := *p.cfg
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.published = &published
+}
+
+// config returns the configuration of the pool's latest cycle, the loaded one until a cycle ran. It must not be
+// modified.
+func (p *runnerPool) config() *Config {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.published
+}
+
+// newRunnerPools creates the pools of the configuration, a single one named after DefaultPoolName unless
+// RUNNER_POOLS is set
+func newRunnerPools(cfg *Config) ([]*runnerPool, error) {
+	poolCfgs := []*Config{cfg}
+	definitions := []poolDefinition{{}}
+	if len(cfg.Pools) > 0 {
+		poolCfgs, definitions = nil, cfg.Pools
+		for _, definition := range cfg.Pools {
+			poolCfgs = append(poolCfgs, definition.apply(cfg))
+		}
+	} else {
+		cfg.RegionThresholds[cfg.RegionID].apply(cfg)
+	}
+
+	regionPools := make(map[string]int, len(poolCfgs))
+	for _, poolCfg := range poolCfgs {
+		regionPools[poolCfg.RegionID]++
+	}
+
+	pools := make([]*runnerPool, 0, len(poolCfgs))
+	for i, poolCfg := range poolCfgs {
+		backend, clientset, err := newClusterBackend(poolCfg)
+		if err != nil {
+			return nil, fmt.Errorf("pool %s: %w", poolCfg.PoolName, err)
+		}
+		provisioner, err := newNodeProvisioner(poolCfg)
+		if err != nil {
+			return nil, fmt.Errorf("pool %s: %w", poolCfg.PoolName, err)
+		}
+		if provisioner != nil {
+			backend = &provisionedBackend{clusterBackend: backend, provisioner: provisioner}
+		}
+
+		pool := newRunnerPool(poolCfg, backend, clientset)
+		pool.definition = definitions[i]
+		pool.primary = i == 0
+		pool.shared = regionPools[poolCfg.RegionID] > 1
+		pools = append(pools, pool)
+	}
+
+	return pools, nil
+}
+
+// newRunnerPool creates a pool of the configuration on the backend, clientset is nil unless the backend is Kubernetes
+func newRunnerPool(cfg *Config, backend clusterBackend, clientset *kubernetes.Clientset) *runnerPool {
+	pool := &runnerPool{
+		cfg:       cfg,
+		backend:   backend,
+		clientset: clientset,
+		statuses:  newStatusStore(),
+		manual:    newManualActions(),
+		health:    newLoopHealth(cfg),
+	}
+	if cfg.IdleTuningMaxRunners > 0 || cfg.IdleTuningMaxCpu > 0 || cfg.IdleTuningMaxMemory > 0 {
+		pool.tuner = newIdleTuner(cfg)
+	}
+	if len(cfg.MigrationNodeSelector) > 0 {
+		pool.migration = newNodePoolMigration(cfg)
+	}
+	if isCostAware(cfg) {
+		pool.cost = newCostTracker(cfg)
+	}
+	if cfg.CircuitBreakerErrorCycles > 0 {
+		pool.breaker = newControlPlaneBreaker(cfg)
+	}
+	pool.publishConfig()
+	return pool
+}
+
+// selectPool returns the pool selected by the request, or nil after replying with an error if there is none
+func selectPool(w http.ResponseWriter, r *http.Request, pools []*runnerPool) *runnerPool {
+	name := r.URL.Query().Get(PoolQueryParam)
+	if name == "" {
+		return pools[0]
+	}
+	for _, pool := range pools {
+		if pool.cfg.PoolName == name {
+			return pool
+		}
+	}
+	http.Error(w, fmt.Sprintf("unknown pool %q", name), http.StatusNotFound)
+	return nil
+}
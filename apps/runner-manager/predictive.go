@@ -0,0 +1,185 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"math"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	PredictiveSeasonalityDaily  = "daily"
+	PredictiveSeasonalityWeekly = "weekly"
+
+	// DefaultPredictiveLeadTime is how far ahead demand is forecast, about the time a new node takes to serve sandboxes
+	DefaultPredictiveLeadTime = 15 * time.Minute
+
+	// DefaultPredictiveWindow is the width of the window around the same time in past periods the forecast averages
+	DefaultPredictiveWindow = 30 * time.Minute
+
+	// DefaultPredictivePeriods is the number of past days or weeks the forecast is based on
+	DefaultPredictivePeriods = 4
+
+	// predictiveRefreshInterval is how often the forecast is recomputed from the scaling history
+	predictiveRefreshInterval = 5 * time.Minute
+)
+
+// demandForecast is the demand expected at the end of the lead time
+type demandForecast struct {
+	AllocatedCpu       float32
+	AllocatedMemoryGiB float32
+	ActiveRunners      float64
+	// Periods is the number of past periods with samples in the window, zero when there is no forecast
+	Periods int
+}
+
+// demandPredictor pre-provisions capacity ahead of recurring daily or weekly peaks. For the time at the end of the
+// lead time, it takes the peak demand within a window around the same time in each of the past periods recorded in
+// the scaling history, and forecasts their average. Demand above the current allocation is added to the idle buffer
+// of the cycle's scaling decisions.
+type demandPredictor struct {
+	history  *scalingHistory
+	poolName string
+
+	seasonality string
+	leadTime    time.Duration
+	window      time.Duration
+	periods     int
+
+	forecast     demandForecast
+	forecastedAt time.Time
+}
+
+func newDemandPredictor(cfg *Config, history *scalingHistory) *demandPredictor {
+	return &demandPredictor{
+		history:     history,
+		poolName:    cfg.PoolName,
+		seasonality: cfg.PredictiveSeasonality,
+		leadTime:    cfg.PredictiveLeadTime,
+		window:      cfg.PredictiveWindow,
+		periods:     cfg.PredictivePeriods,
+	}
+}
+
+// predictiveLookback returns how far back the forecast reads the scaling history
+func predictiveLookback(cfg *Config) time.Duration {
+	return time.Duration(cfg.PredictivePeriods)*seasonalityPeriod(cfg.PredictiveSeasonality) + cfg.PredictiveWindow
+}
+
+func seasonalityPeriod(seasonality string) time.Duration {
+	if seasonality == PredictiveSeasonalityWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// boost returns the configuration for the cycle's scaling decisions, with the idle buffer raised by the forecast
+// demand above the current allocation
+func (p *demandPredictor) boost(cfg *Config, state *ClusterState, metrics *ResourceMetrics) *Config {
+	if time.Since(p.forecastedAt) >= predictiveRefreshInterval {
+		if err := p.refresh(); err != nil {
+			log.Warnf("Could not forecast demand, keeping the previous forecast: %v", err)
+		}
+	}
+
+	forecast := p.forecast
+	if forecast.Periods == 0 {
+		return cfg
+	}
+	predictedDemand.WithLabelValues("cpu").Set(float64(forecast.AllocatedCpu))
+	predictedDemand.WithLabelValues("memory").Set(float64(forecast.AllocatedMemoryGiB))
+	predictedDemand.WithLabelValues("runners").Set(forecast.ActiveRunners)
+
+	extraCpu := int(math.Ceil(float64(forecast.AllocatedCpu - metrics.TotalAllocatedCPU)))
+	extraMemory := int(math.Ceil(float64(forecast.AllocatedMemoryGiB - metrics.TotalAllocatedMemoryGiB)))
+	extraRunners := int(math.Ceil(forecast.ActiveRunners)) - len(state.ActiveRunners)
+	if extraCpu <= 0 && extraMemory <= 0 && extraRunners <= 0 {
+		return cfg
+	}
+
+	boosted := *cfg
+	boosted.MinIdleCpu += max(extraCpu, 0)
+	boosted.MinIdleMemory += max(extraMemory, 0)
+	boosted.MinIdleRunners += max(extraRunners, 0)
+
+	log.WithFields(log.Fields{
+		"extraCpu":     max(extraCpu, 0),
+		"extraMemory":  max(extraMemory, 0),
+		"extraRunners": max(extraRunners, 0),
+	}).Infof("Forecast %s demand in %s is above the current allocation: raising the idle buffer to %d runners, %d CPU, %d GiB memory.",
+		p.seasonality, p.leadTime, boosted.MinIdleRunners, boosted.MinIdleCpu, boosted.MinIdleMemory)
+	return &boosted
+}
+
+// refresh recomputes the forecast from the scaling history
+func (p *demandPredictor) refresh() error {
+	at := time.Now().Add(p.leadTime)
+	period := seasonalityPeriod(p.seasonality)
+
+	recorded, err := p.history.read(at.Add(-time.Duration(p.periods)*period-p.window/2), time.Time{})
+	if err != nil {
+		return err
+	}
+	p.forecast = forecastDemand(samplesOfPool(recorded, p.poolName), at, period, p.periods, p.window)
+	p.forecastedAt = time.Now()
+	return nil
+}
+
+// forecastDemand averages the peak demand within the window around the given time in each past period
+func forecastDemand(samples []historySample, at time.Time, period time.Duration, periods int, window time.Duration) demandForecast {
+	var forecast demandForecast
+	var totalCpu, totalMemory float32
+	var totalRunners float64
+
+	for k := 1; k <= periods; k++ {
+		center := at.Add(-time.Duration(k) * period)
+		from, until := center.Add(-window/2), center.Add(window/2)
+
+		found := false
+		var peakCpu, peakMemory float32
+		peakRunners := 0
+		for _, sample := range samples {
+			if sample.Time.Before(from) || sample.Time.After(until) {
+				continue
+			}
+			found = true
+			if sample.AllocatedCpu > peakCpu {
+				peakCpu = sample.AllocatedCpu
+			}
+			if sample.AllocatedMemoryGiB > peakMemory {
+				peakMemory = sample.AllocatedMemoryGiB
+			}
+			peakRunners = max(peakRunners, sample.ActiveRunners)
+		}
+		if !found {
+			continue
+		}
+
+		forecast.Periods++
+		totalCpu += peakCpu
+		totalMemory += peakMemory
+		totalRunners += float64(peakRunners)
+	}
+
+	if forecast.Periods == 0 {
+		return forecast
+	}
+	forecast.AllocatedCpu = totalCpu / float32(forecast.Periods)
+	forecast.AllocatedMemoryGiB = totalMemory / float32(forecast.Periods)
+	forecast.ActiveRunners = totalRunners / float64(forecast.Periods)
+	return forecast
+}
+
+// samplesOfPool returns the samples of the pool. Samples recorded before pools were named belong to DefaultPoolName.
+func samplesOfPool(samples []historySample, poolName string) []historySample {
+	var poolSamples []historySample
+	for _, sample := range samples {
+		if sample.Pool == poolName || (sample.Pool == "" && poolName == DefaultPoolName) {
+			poolSamples = append(poolSamples, sample)
+		}
+	}
+	return poolSamples
+}
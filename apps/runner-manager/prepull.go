@@ -0,0 +1,218 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultSnapshotPrepullTimeout bounds the pre-pull of a single snapshot when SNAPSHOT_PREPULL_TIMEOUT is not set
+const DefaultSnapshotPrepullTimeout = 15 * time.Minute
+
+// prepullRegistry is the registry the runner pulls snapshots from, as expected by the runner API
+type prepullRegistry struct {
+	Url      string  `json:"url"`
+	Username *string `json:"username,omitempty"`
+	Password *string `json:"password,omitempty"`
+}
+
+// prepullRequest is the body of the runner API's snapshot pull
+type prepullRequest struct {
+	Snapshot string           `json:"snapshot"`
+	Registry *prepullRegistry `json:"registry,omitempty"`
+}
+
+// snapshotPrepuller has runners registered since the previous cycle pull the region's most-used snapshots, so the
+// first sandboxes placed on a new node do not wait for their image. The Daytona API propagates snapshots to only part
+// of the shared runners, and only once they are ready, so a new runner would otherwise pull on the first sandbox
+// start. Pulls run in the background through the runner's API, a runner is prewarming until they all complete.
+type snapshotPrepuller struct {
+	apiClient  *daytona.APIClient
+	regionID   string
+	count      int
+	timeout    time.Duration
+	registry   *prepullRegistry
+	httpClient *http.Client
+
+	// known holds the runners seen so far, nil until the first cycle whose runners are not new
+	known map[string]bool
+
+	mu         sync.Mutex
+	prewarming map[string]time.Time
+}
+
+func newSnapshotPrepuller(cfg *Config, apiClient *daytona.APIClient) *snapshotPrepuller {
+	p := &snapshotPrepuller{
+		apiClient:  apiClient,
+		regionID:   cfg.RegionID,
+		count:      cfg.SnapshotPrepullCount,
+		timeout:    cfg.SnapshotPrepullTimeout,
+		httpClient: &http.Client{},
+		prewarming: make(map[string]time.Time),
+	}
+	if cfg.SnapshotPrepullRegistryURL != "" {
+		p.registry = &prepullRegistry{Url: cfg.SnapshotPrepullRegistryURL}
+		if cfg.SnapshotPrepullRegistryUsername != "" {
+			p.registry.Username = &cfg.SnapshotPrepullRegistryUsername
+			p.registry.Password = &cfg.SnapshotPrepullRegistryPassword
+		}
+	}
+	return p
+}
+
+// reconcile starts the pre-pulls of the runners registered since the previous cycle and records the runners still
+// prewarming in the state
+func (p *snapshotPrepuller) reconcile(state *ClusterState) {
+	var registered []daytona.RunnerFull
+	current := make(map[string]bool, len(state.Runners))
+	for _, runner := range state.Runners {
+		current[runner.GetId()] = true
+		if p.known != nil && !p.known[runner.GetId()] {
+			registered = append(registered, runner)
+		}
+	}
+	p.known = current
+
+	if len(registered) > 0 {
+		refs, err := p.mostUsedSnapshots()
+		if err != nil {
+			log.Warnf("Could not determine the snapshots to pre-pull on %d new runners: %v", len(registered), err)
+		} else if len(refs) > 0 {
+			for _, runner := range registered {
+				p.start(runner, refs)
+			}
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	state.PrewarmingRunnerIDs = make(map[string]bool, len(p.prewarming))
+	for runnerID := range p.prewarming {
+		state.PrewarmingRunnerIDs[runnerID] = true
+	}
+	prewarmingRunners.Set(float64(len(p.prewarming)))
+}
+
+// mostUsedSnapshots returns the registry references of the snapshots of the most sandboxes in the region
+func (p *snapshotPrepuller) mostUsedSnapshots() ([]string, error) {
+	sandboxes, err := listRegionSandboxes(p.apiClient, p.regionID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]int)
+	for _, sandbox := range sandboxes {
+		if snapshot := sandbox.GetSnapshot(); snapshot != "" {
+			usage[snapshot]++
+		}
+	}
+	names := make([]string, 0, len(usage))
+	for name := range usage {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if usage[names[i]] != usage[names[j]] {
+			return usage[names[i]] > usage[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > p.count {
+		names = names[:p.count]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	refs := make([]string, 0, len(names))
+	for _, name := range names {
+		snapshot, _, err := p.apiClient.SnapshotsAPI.GetSnapshot(ctx, name).Execute()
+		if err != nil {
+			log.Debugf("Not pre-pulling snapshot %s, it could not be resolved: %v", name, err)
+			continue
+		}
+		if ref := snapshot.GetRef(); ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
+
+// start pulls the snapshots on the runner in the background, one at a time so the pulls do not compete for the
+// node's bandwidth with each other
+func (p *snapshotPrepuller) start(runner daytona.RunnerFull, refs []string) {
+	p.mu.Lock()
+	if _, found := p.prewarming[runner.GetId()]; found {
+		p.mu.Unlock()
+		return
+	}
+	started := time.Now()
+	p.prewarming[runner.GetId()] = started
+	p.mu.Unlock()
+
+	runnerLog := log.WithField("runner", runner.GetId())
+	runnerLog.Infof("Pre-pulling %d snapshots on new runner %s.", len(refs), runner.GetId())
+
+	go func() {
+		pulled := 0
+		for _, ref := range refs {
+			if err := p.pull(runner, ref); err != nil {
+				runnerLog.Warnf("Error pre-pulling snapshot %s on runner %s: %v", ref, runner.GetId(), err)
+				snapshotPrepulls.WithLabelValues("failed").Inc()
+				continue
+			}
+			snapshotPrepulls.WithLabelValues("pulled").Inc()
+			pulled++
+		}
+		runnerLog.Infof("Runner %s prewarmed in %s, %d of %d snapshots pulled.", runner.GetId(), time.Since(started).Round(time.Second), pulled, len(refs))
+
+		p.mu.Lock()
+		delete(p.prewarming, runner.GetId())
+		p.mu.Unlock()
+	}()
+}
+
+// pull has the runner pull the snapshot, returning once it is pulled
+func (p *snapshotPrepuller) pull(runner daytona.RunnerFull, ref string) error {
+	apiUrl := strings.TrimSuffix(runner.GetApiUrl(), "/")
+	if apiUrl == "" {
+		return fmt.Errorf("runner %s has no API URL", runner.GetId())
+	}
+	body, err := json.Marshal(prepullRequest{Snapshot: ref, Registry: p.registry})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiUrl+"/snapshots/pull", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+runner.GetApiKey())
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("runner returned %s", resp.Status)
+	}
+	return nil
+}
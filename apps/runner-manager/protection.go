@@ -0,0 +1,45 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/daytonaio/common-go/pkg/protection"
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+)
+
+// gatherProtectedRunners returns the IDs of runners hosting sandboxes labeled as do-not-disturb
+func gatherProtectedRunners(apiClient *daytona.APIClient, regionID string) (map[string]bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	protectedRunners := make(map[string]bool)
+
+	for page := 1; ; page++ {
+		sandboxes, _, err := apiClient.SandboxAPI.ListSandboxesPaginated(ctx).
+			Regions([]string{regionID}).
+			Labels(protection.DoNotDisturbLabelFilter()).
+			Page(float32(page)).
+			Limit(SandboxListPageSize).
+			Execute()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list do-not-disturb sandboxes from Daytona API: %w", err)
+		}
+
+		for _, sandbox := range sandboxes.Items {
+			if runnerId := sandbox.GetRunnerId(); runnerId != "" {
+				protectedRunners[runnerId] = true
+			}
+		}
+
+		if float32(page) >= sandboxes.TotalPages || len(sandboxes.Items) == 0 {
+			break
+		}
+	}
+
+	return protectedRunners, nil
+}
@@ -0,0 +1,354 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// NodeProvisionerAWS, NodeProvisionerGCP and NodeProvisionerAzure are the supported node provisioners
+	NodeProvisionerAWS   = "aws-asg"
+	NodeProvisionerGCP   = "gcp-mig"
+	NodeProvisionerAzure = "azure-vmss"
+
+	gcpComputeURL        = "https://compute.googleapis.com/compute/v1/"
+	gcpMetadataTokenURL  = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+	azureManagementURL   = "https://management.azure.com"
+	azureMetadataURL     = "http://169.254.169.254/metadata/identity/oauth2/token?api-version=2018-02-01&resource=" + azureManagementURL + "/"
+	azureComputeVersion  = "2024-07-01"
+	provisionerCallLimit = 30 * time.Second
+)
+
+// nodeProvisioner resizes the cloud node group backing the pool directly, rather than leaving it to the cluster
+// autoscaler to react to placeholders
+type nodeProvisioner interface {
+	// AddNodes raises the desired size of the node group by count
+	AddNodes(ctx context.Context, count int) error
+	// RemoveNode deletes the machine of the node from the node group, lowering its desired size
+	RemoveNode(ctx context.Context, node *corev1.Node) error
+}
+
+// newNodeProvisioner creates the configured node provisioner, or nil when the cluster autoscaler sizes the pool
+func newNodeProvisioner(cfg *Config) (nodeProvisioner, error) {
+	httpClient := &http.Client{Timeout: provisionerCallLimit}
+	switch cfg.NodeProvisioner {
+	case "":
+		return nil, nil
+	case NodeProvisionerAWS:
+		awsCfg, err := awsconfig.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+		}
+		return &awsProvisioner{autoscaling: autoscaling.NewFromConfig(awsCfg), group: cfg.NodeProvisionerGroup}, nil
+	case NodeProvisionerGCP:
+		return &gcpProvisioner{
+			group:      strings.Trim(cfg.NodeProvisionerGroup, "/"),
+			httpClient: httpClient,
+			token:      &metadataToken{url: gcpMetadataTokenURL, header: "Metadata-Flavor", value: "Google", httpClient: httpClient},
+		}, nil
+	case NodeProvisionerAzure:
+		return &azureProvisioner{
+			group:      "/" + strings.Trim(cfg.NodeProvisionerGroup, "/"),
+			httpClient: httpClient,
+			token:      &metadataToken{url: azureMetadataURL, header: "Metadata", value: "true", httpClient: httpClient},
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported node provisioner %q", cfg.NodeProvisioner)
+}
+
+// provisionedBackend resizes the node group along with the placeholders of the backend it wraps: every placeholder
+// created adds a node for it to land on, and the node of every scheduled placeholder deleted is removed. A node added
+// for a placeholder deleted while still pending joins the pool all the same, as lowering the desired size would let
+// the cloud provider pick any node to remove, including busy ones.
+type provisionedBackend struct {
+	clusterBackend
+	provisioner nodeProvisioner
+}
+
+func (b *provisionedBackend) CreatePlaceholder(ctx context.Context, name, appName string, options placeholderOptions) (*corev1.Pod, error) {
+	pod, err := b.clusterBackend.CreatePlaceholder(ctx, name, appName, options)
+	if err != nil {
+		return nil, err
+	}
+
+	providerCtx, cancel := context.WithTimeout(context.Background(), provisionerCallLimit)
+	defer cancel()
+	if err := b.provisioner.AddNodes(providerCtx, 1); err != nil {
+		nodeProvisionerCalls.WithLabelValues("add", "error").Inc()
+		// Without a node the placeholder would stay pending forever, so it is rolled back
+		if deleteErr := b.clusterBackend.DeletePlaceholder(ctx, name); deleteErr != nil {
+			log.Errorf("Error deleting placeholder pod %s after failing to add its node: %v", name, deleteErr)
+		}
+		return nil, fmt.Errorf("failed to add a node for placeholder %s: %w", name, err)
+	}
+	nodeProvisionerCalls.WithLabelValues("add", "success").Inc()
+	return pod, nil
+}
+
+func (b *provisionedBackend) DeletePlaceholder(ctx context.Context, name string) error {
+	var node *corev1.Node
+	placeholders, err := b.clusterBackend.ListPlaceholders(ctx)
+	if err != nil {
+		return err
+	}
+	for _, pod := range placeholders {
+		if pod.Name != name || pod.Spec.NodeName == "" {
+			continue
+		}
+		nodes, err := b.clusterBackend.ListNodes(ctx)
+		if err != nil {
+			return err
+		}
+		for i := range nodes {
+			if nodes[i].Name == pod.Spec.NodeName {
+				node = &nodes[i]
+			}
+		}
+	}
+
+	if err := b.clusterBackend.DeletePlaceholder(ctx, name); err != nil {
+		return err
+	}
+	if node == nil {
+		return nil
+	}
+
+	providerCtx, cancel := context.WithTimeout(context.Background(), provisionerCallLimit)
+	defer cancel()
+	if err := b.provisioner.RemoveNode(providerCtx, node); err != nil {
+		nodeProvisionerCalls.WithLabelValues("remove", "error").Inc()
+		return fmt.Errorf("failed to remove node %s of placeholder %s: %w", node.Name, name, err)
+	}
+	nodeProvisionerCalls.WithLabelValues("remove", "success").Inc()
+	return nil
+}
+
+// Watch watches the wrapped backend, which is always Kubernetes
+func (b *provisionedBackend) Watch(ctx context.Context, queue workqueue.TypedDelayingInterface[string]) error {
+	watcher, ok := b.clusterBackend.(clusterWatcher)
+	if !ok {
+		return fmt.Errorf("the cluster backend cannot be watched")
+	}
+	return watcher.Watch(ctx, queue)
+}
+
+// awsProvisioner sizes an EC2 Auto Scaling group
+type awsProvisioner struct {
+	autoscaling *autoscaling.Client
+	group       string
+}
+
+func (p *awsProvisioner) AddNodes(ctx context.Context, count int) error {
+	out, err := p.autoscaling.DescribeAutoScalingGroups(ctx, &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []string{p.group},
+	})
+	if err != nil {
+		return err
+	}
+	if len(out.AutoScalingGroups) == 0 {
+		return fmt.Errorf("auto scaling group %s not found", p.group)
+	}
+	group := out.AutoScalingGroups[0]
+
+	desired := aws.ToInt32(group.DesiredCapacity) + int32(count)
+	if desired > aws.ToInt32(group.MaxSize) {
+		return fmt.Errorf("auto scaling group %s is at its maximum size of %d", p.group, aws.ToInt32(group.MaxSize))
+	}
+	_, err = p.autoscaling.SetDesiredCapacity(ctx, &autoscaling.SetDesiredCapacityInput{
+		AutoScalingGroupName: aws.String(p.group),
+		DesiredCapacity:      aws.Int32(desired),
+		HonorCooldown:        aws.Bool(false),
+	})
+	return err
+}
+
+func (p *awsProvisioner) RemoveNode(ctx context.Context, node *corev1.Node) error {
+	providerID := node.Spec.ProviderID
+	if !strings.HasPrefix(providerID, "aws://") {
+		return fmt.Errorf("node %s has no AWS provider ID", node.Name)
+	}
+	_, err := p.autoscaling.TerminateInstanceInAutoScalingGroup(ctx, &autoscaling.TerminateInstanceInAutoScalingGroupInput{
+		InstanceId:                     aws.String(providerID[strings.LastIndex(providerID, "/")+1:]),
+		ShouldDecrementDesiredCapacity: aws.Bool(true),
+	})
+	return err
+}
+
+// gcpProvisioner sizes a Compute Engine managed instance group, set as its path, e.g.
+// projects/<project>/zones/<zone>/instanceGroupManagers/<name> or the regions/<region> equivalent
+type gcpProvisioner struct {
+	group      string
+	httpClient *http.Client
+	token      *metadataToken
+}
+
+func (p *gcpProvisioner) AddNodes(ctx context.Context, count int) error {
+	var group struct {
+		TargetSize int `json:"targetSize"`
+	}
+	if err := cloudRequest(ctx, p.httpClient, p.token, http.MethodGet, gcpComputeURL+p.group, nil, &group); err != nil {
+		return err
+	}
+	resizeURL := fmt.Sprintf("%s%s/resize?size=%d", gcpComputeURL, p.group, group.TargetSize+count)
+	return cloudRequest(ctx, p.httpClient, p.token, http.MethodPost, resizeURL, nil, nil)
+}
+
+func (p *gcpProvisioner) RemoveNode(ctx context.Context, node *corev1.Node) error {
+	// Provider IDs have the form gce://<project>/<zone>/<instance>
+	parts := strings.Split(strings.TrimPrefix(node.Spec.ProviderID, "gce://"), "/")
+	if !strings.HasPrefix(node.Spec.ProviderID, "gce://") || len(parts) != 3 {
+		return fmt.Errorf("node %s has no GCE provider ID", node.Name)
+	}
+	body := map[string][]string{
+		"instances": {fmt.Sprintf("projects/%s/zones/%s/instances/%s", parts[0], parts[1], parts[2])},
+	}
+	return cloudRequest(ctx, p.httpClient, p.token, http.MethodPost, gcpComputeURL+p.group+"/deleteInstances", body, nil)
+}
+
+// azureProvisioner sizes a virtual machine scale set, set as its resource ID, e.g.
+// /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.Compute/virtualMachineScaleSets/<name>
+type azureProvisioner struct {
+	group      string
+	httpClient *http.Client
+	token      *metadataToken
+}
+
+func (p *azureProvisioner) AddNodes(ctx context.Context, count int) error {
+	var scaleSet struct {
+		Sku struct {
+			Capacity int `json:"capacity"`
+		} `json:"sku"`
+	}
+	scaleSetURL := azureManagementURL + p.group + "?api-version=" + azureComputeVersion
+	if err := cloudRequest(ctx, p.httpClient, p.token, http.MethodGet, scaleSetURL, nil, &scaleSet); err != nil {
+		return err
+	}
+	body := map[string]any{"sku": map[string]int{"capacity": scaleSet.Sku.Capacity + count}}
+	return cloudRequest(ctx, p.httpClient, p.token, http.MethodPatch, scaleSetURL, body, nil)
+}
+
+func (p *azureProvisioner) RemoveNode(ctx context.Context, node *corev1.Node) error {
+	// Provider IDs have the form azure:///subscriptions/.../virtualMachineScaleSets/<name>/virtualMachines/<instance>
+	providerID := strings.TrimPrefix(node.Spec.ProviderID, "azure://")
+	prefix := strings.ToLower(p.group + "/virtualMachines/")
+	if !strings.HasPrefix(strings.ToLower(providerID), prefix) {
+		return fmt.Errorf("node %s is not an instance of scale set %s", node.Name, p.group)
+	}
+	body := map[string][]string{"instanceIds": {providerID[len(prefix):]}}
+	deleteURL := azureManagementURL + p.group + "/delete?api-version=" + azureComputeVersion
+	return cloudRequest(ctx, p.httpClient, p.token, http.MethodPost, deleteURL, body, nil)
+}
+
+// metadataToken is an access token of the machine's identity obtained from the cloud provider's metadata server,
+// refreshed shortly before it expires
+type metadataToken struct {
+	url        string
+	header     string
+	value      string
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func (t *metadataToken) get(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && time.Until(t.expiresAt) > time.Minute {
+		return t.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(t.header, t.value)
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get an access token from the metadata server: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata server returned status %d for an access token", resp.StatusCode)
+	}
+
+	// Azure returns the lifetime as a string, GCP as a number
+	var token struct {
+		AccessToken string      `json:"access_token"`
+		ExpiresIn   json.Number `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("invalid access token from the metadata server: %w", err)
+	}
+	expiresIn, err := token.ExpiresIn.Int64()
+	if err != nil {
+		return "", fmt.Errorf("invalid access token lifetime from the metadata server: %w", err)
+	}
+
+	t.token, t.expiresAt = token.AccessToken, time.Now().Add(time.Duration(expiresIn)*time.Second)
+	return t.token, nil
+}
+
+// cloudRequest calls a cloud provider REST API with the machine's identity, decoding the response into out if set
+func cloudRequest(ctx context.Context, httpClient *http.Client, token *metadataToken, method, requestURL string, body, out any) error {
+	accessToken, err := token.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		endpoint, _ := url.Parse(requestURL)
+		return fmt.Errorf("%s %s returned status %d: %s", method, endpoint.Path, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// ProvisioningStageNodeReady is the stage from the placeholder's creation to its node being ready
+	ProvisioningStageNodeReady = "node_ready"
+	// ProvisioningStageRunnerRegistration is the stage from the node being ready to its runner registering
+	ProvisioningStageRunnerRegistration = "runner_registration"
+	// ProvisioningStageTotal is the whole provisioning, from the placeholder's creation to the runner registering
+	ProvisioningStageTotal = "total"
+)
+
+// observeProvisioningLatency records how long the nodes whose runner registered since the previous cycle took to
+// provision, so a slow machine image or a bootstrap regression shows up in the latency of its stage. Runners that
+// registered on a node not added for a placeholder, or before the placeholder was created, are not provisioning
+// latencies and are left out. The first cycle has no previous one to tell new runners apart and records nothing.
+func observeProvisioningLatency(cfg *Config, previous, current *ClusterState) {
+	if previous == nil {
+		return
+	}
+
+	previousRunners := make(map[string]bool, len(previous.Runners))
+	for _, runner := range previous.Runners {
+		previousRunners[runner.GetId()] = true
+	}
+	for _, runner := range current.Runners {
+		if previousRunners[runner.GetId()] {
+			continue
+		}
+		node, found := current.NodeByIP[runner.GetDomain()]
+		if !found {
+			continue
+		}
+		placeholder := placeholderOnNode(current, node.Name)
+		if placeholder == nil {
+			continue
+		}
+		createdAt := placeholder.CreationTimestamp.Time
+		registeredAt, err := time.Parse(time.RFC3339Nano, runner.GetCreatedAt())
+		if err != nil || registeredAt.Before(createdAt) {
+			continue
+		}
+
+		zone := node.Labels[ZoneLabel]
+		total := registeredAt.Sub(createdAt)
+		nodeProvisioningLatency.WithLabelValues(cfg.RegionID, cfg.PoolName, zone, ProvisioningStageTotal).Observe(total.Seconds())
+		nodeLog := log.WithFields(log.Fields{"node": node.Name, "runner": runner.GetId()})
+
+		// The ready transition of a node that flapped since is no longer its first one
+		readyAt, ready := nodeReadyAt(node)
+		if !ready || readyAt.Before(createdAt) || readyAt.After(registeredAt) {
+			nodeLog.Debugf("Node %s was provisioned in %s.", node.Name, total.Round(time.Second))
+			continue
+		}
+		nodeProvisioningLatency.WithLabelValues(cfg.RegionID, cfg.PoolName, zone, ProvisioningStageNodeReady).Observe(readyAt.Sub(createdAt).Seconds())
+		nodeProvisioningLatency.WithLabelValues(cfg.RegionID, cfg.PoolName, zone, ProvisioningStageRunnerRegistration).Observe(registeredAt.Sub(readyAt).Seconds())
+		nodeLog.Debugf("Node %s was provisioned in %s: ready after %s, its runner registered %s later.", node.Name,
+			total.Round(time.Second), readyAt.Sub(createdAt).Round(time.Second), registeredAt.Sub(readyAt).Round(time.Second))
+	}
+}
+
+// placeholderOnNode returns the scheduled placeholder on the node, nil if there is none
+func placeholderOnNode(state *ClusterState, nodeName string) *corev1.Pod {
+	for _, pod := range state.ScheduledPlaceholders {
+		if pod.Spec.NodeName == nodeName {
+			return pod
+		}
+	}
+	return nil
+}
+
+// nodeReadyAt returns when the node last became ready, false if it is not ready or the transition is not recorded
+func nodeReadyAt(node *corev1.Node) (time.Time, bool) {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.LastTransitionTime.Time, condition.Status == corev1.ConditionTrue && !condition.LastTransitionTime.IsZero()
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/daytonaio/common-go/pkg/cache"
+	"github.com/daytonaio/common-go/pkg/quota"
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// QuotaCacheTTL defines how long organization quotas are reused before being fetched again
+	QuotaCacheTTL = 5 * time.Minute
+
+	// SandboxListPageSize is the page size used when listing sandboxes from the Daytona API
+	SandboxListPageSize = 100
+)
+
+// orgUsage holds the allocation of an organization's started sandboxes in the region
+type orgUsage struct {
+	sandboxes int
+	cpu       float32
+	memoryGiB float32
+}
+
+// newQuotaEnforcer creates the organization quota enforcer backed by the Daytona API
+func newQuotaEnforcer(cfg *Config, apiClient *daytona.APIClient) *quota.Enforcer {
+	source := quota.NewAPIQuotaSource(apiClient, cfg.RegionID, quota.OrgQuota{
+		MaxConcurrentSandboxes: cfg.QuotaDefaultMaxConcurrentSandboxes,
+	})
+	return quota.NewEnforcer(source, cache.NewMapCache[quota.OrgQuota](), QuotaCacheTTL)
+}
+
+// calculateOverQuotaDemand sums the allocation of started sandboxes that exceeds their organization's quota
+func calculateOverQuotaDemand(apiClient *daytona.APIClient, enforcer *quota.Enforcer, regionID string) (overCpu float32, overMemoryGiB float32, err error) {
+	usageByOrg, err := gatherOrgUsage(apiClient, regionID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for organizationId, usage := range usageByOrg {
+		orgQuota, err := enforcer.GetOrgQuota(ctx, organizationId)
+		if err != nil {
+			log.Warnf("Could not get quota for organization %s, counting its demand in full: %v", organizationId, err)
+			continue
+		}
+
+		fraction := orgQuota.CountableFraction(quota.Usage{
+			ConcurrentSandboxes: usage.sandboxes,
+			ReservedCpu:         usage.cpu,
+		})
+		if fraction >= 1 {
+			continue
+		}
+
+		excessCpu := usage.cpu * (1 - fraction)
+		excessMemoryGiB := usage.memoryGiB * (1 - fraction)
+		log.Infof("Organization %s is over quota (%d sandboxes, CPU=%.2f). Not counting CPU=%.2f, Mem=%.2fGiB toward scale-up.",
+			organizationId, usage.sandboxes, usage.cpu, excessCpu, excessMemoryGiB)
+		overCpu += excessCpu
+		overMemoryGiB += excessMemoryGiB
+	}
+
+	return overCpu, overMemoryGiB, nil
+}
+
+// gatherOrgUsage sums the allocation of the started sandboxes of the region by organization ID
+func gatherOrgUsage(apiClient *daytona.APIClient, regionID string) (map[string]*orgUsage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	usageByOrg := make(map[string]*orgUsage)
+
+	for page := 1; ; page++ {
+		sandboxes, _, err := apiClient.SandboxAPI.ListSandboxesPaginated(ctx).
+			Regions([]string{regionID}).
+			States([]string{string(daytona.SANDBOXSTATE_STARTED)}).
+			Page(float32(page)).
+			Limit(SandboxListPageSize).
+			Execute()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list started sandboxes from Daytona API: %w", err)
+		}
+
+		for _, sandbox := range sandboxes.Items {
+			usage, found := usageByOrg[sandbox.OrganizationId]
+			if !found {
+				usage = &orgUsage{}
+				usageByOrg[sandbox.OrganizationId] = usage
+			}
+			usage.sandboxes++
+			usage.cpu += sandbox.Cpu
+			usage.memoryGiB += sandbox.Memory
+		}
+
+		if float32(page) >= sandboxes.TotalPages || len(sandboxes.Items) == 0 {
+			break
+		}
+	}
+
+	return usageByOrg, nil
+}
+
+// excludeDemand returns a copy of the metrics with the given allocation treated as available
+func excludeDemand(metrics *ResourceMetrics, cpu, memoryGiB float32) *ResourceMetrics {
+	adjusted := *metrics
+	cpu = min(cpu, adjusted.TotalAllocatedCPU)
+	memoryGiB = min(memoryGiB, adjusted.TotalAllocatedMemoryGiB)
+
+	adjusted.TotalAllocatedCPU -= cpu
+	adjusted.TotalAllocatedMemoryGiB -= memoryGiB
+	adjusted.TotalAvailableCPU += cpu
+	adjusted.TotalAvailableMemoryGiB += memoryGiB
+	return &adjusted
+}
@@ -0,0 +1,23 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/daytonaio/common-go/pkg/ratelimit"
+)
+
+// rateLimitedTransport paces outgoing requests through a shared limiter before handing them to the next transport
+type rateLimitedTransport struct {
+	limiter ratelimit.ILimiter
+	next    http.RoundTripper
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context(), req.URL.Host); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ReadinessPath is the readiness probe endpoint reflecting the health of the controller loops
+	ReadinessPath = "/readyz"
+
+	// ReadinessStaleIntervals is the number of check intervals without a successful state gathering after which the
+	// controller loop is considered stuck
+	ReadinessStaleIntervals = 3
+)
+
+// loopHealth tracks the outcome of a pool's controller cycles for the readiness probe
+type loopHealth struct {
+	mu          sync.Mutex
+	interval    time.Duration
+	started     time.Time
+	lastSuccess time.Time
+	lastErr     error
+}
+
+func newLoopHealth(cfg *Config) *loopHealth {
+	return &loopHealth{
+		interval: cfg.CheckInterval,
+		started:  time.Now(),
+	}
+}
+
+// recordSuccess records a cycle that gathered the cluster state
+func (h *loopHealth) recordSuccess() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastSuccess = time.Now()
+	h.lastErr = nil
+}
+
+// recordFailure records a cycle that failed
+func (h *loopHealth) recordFailure(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastErr = err
+}
+
+// check returns why the controller loop is unhealthy, nil when it is healthy. A loop that has not completed a cycle
+// yet is healthy until it has been running for the staleness period.
+func (h *loopHealth) check() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.lastErr != nil {
+		return fmt.Errorf("last controller cycle failed: %v", h.lastErr)
+	}
+
+	since := h.lastSuccess
+	if since.IsZero() {
+		since = h.started
+	}
+	if stale := time.Duration(ReadinessStaleIntervals) * h.interval; time.Since(since) > stale {
+		if h.lastSuccess.IsZero() {
+			return fmt.Errorf("cluster state not gathered since startup %s ago", time.Since(h.started).Round(time.Second))
+		}
+		return fmt.Errorf("cluster state last gathered %s ago", time.Since(h.lastSuccess).Round(time.Second))
+	}
+	return nil
+}
+
+// readinessHandler reports ready when the controller loops of all pools are healthy and none is degraded
+func readinessHandler(pools []*runnerPool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var failures []string
+		for _, pool := range pools {
+			if err := pool.health.check(); err != nil {
+				failures = append(failures, fmt.Sprintf("pool %s: %v", pool.cfg.PoolName, err))
+			}
+			if pool.breaker != nil {
+				if err := pool.breaker.check(); err != nil {
+					failures = append(failures, fmt.Sprintf("pool %s: %v", pool.cfg.PoolName, err))
+				}
+			}
+		}
+
+		if len(failures) > 0 {
+			http.Error(w, strings.Join(failures, "\n"), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}
+}
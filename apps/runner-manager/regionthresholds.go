@@ -0,0 +1,55 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// regionThresholds overrides the idle buffer and utilization threshold of the pools of a region, so a busy region
+// keeps a larger buffer than the others managed by the same deployment. REGION_THRESHOLDS maps region IDs to them, in
+// the format {"us-east": {"minIdleRunners": 4, "minIdleCpu": 64, "maxResourceUtilizationPercent": 70}}. Unset fields
+// take the value of the top-level configuration.
+type regionThresholds struct {
+	MinIdleRunners                *int `json:"minIdleRunners"`
+	MinIdleCpu                    *int `json:"minIdleCpu"`
+	MinIdleMemory                 *int `json:"minIdleMemory"`
+	MaxResourceUtilizationPercent *int `json:"maxResourceUtilizationPercent"`
+}
+
+// parseRegionThresholds parses and validates REGION_THRESHOLDS
+func parseRegionThresholds(value string) (map[string]regionThresholds, error) {
+	var thresholds map[string]regionThresholds
+	if err := json.Unmarshal([]byte(value), &thresholds); err != nil {
+		return nil, err
+	}
+	for regionID, region := range thresholds {
+		for _, threshold := range []*int{region.MinIdleRunners, region.MinIdleCpu, region.MinIdleMemory, region.MaxResourceUtilizationPercent} {
+			if threshold != nil && *threshold < 0 {
+				return nil, fmt.Errorf("region %q has a negative threshold", regionID)
+			}
+		}
+		if region.MaxResourceUtilizationPercent != nil && *region.MaxResourceUtilizationPercent > 100 {
+			return nil, fmt.Errorf("region %q has a maxResourceUtilizationPercent above 100", regionID)
+		}
+	}
+	return thresholds, nil
+}
+
+// apply sets the region's thresholds on the configuration
+func (t regionThresholds) apply(cfg *Config) {
+	if t.MinIdleRunners != nil {
+		cfg.MinIdleRunners = *t.MinIdleRunners
+	}
+	if t.MinIdleCpu != nil {
+		cfg.MinIdleCpu = *t.MinIdleCpu
+	}
+	if t.MinIdleMemory != nil {
+		cfg.MinIdleMemory = *t.MinIdleMemory
+	}
+	if t.MaxResourceUtilizationPercent != nil {
+		cfg.MaxResourceUtilizationPercent = *t.MaxResourceUtilizationPercent
+	}
+}
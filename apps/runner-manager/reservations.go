@@ -0,0 +1,114 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RegionCapacityReservationsProperty is the region property holding the capacity reserved for organizations
+const RegionCapacityReservationsProperty = "capacityReservations"
+
+// capacityReservation is capacity of the region guaranteed to an organization, stored on the region in the Daytona
+// API. The part its started sandboxes do not use is kept idle on top of the shared idle buffer, so other
+// organizations cannot consume it.
+type capacityReservation struct {
+	OrganizationID string  `json:"organizationId"`
+	Cpu            float32 `json:"cpu"`
+	MemoryGiB      float32 `json:"memoryGiB"`
+	// OS is the operating system of the pool holding the reservation, linux when unset
+	OS string `json:"os"`
+}
+
+// fetchCapacityReservations reads the capacity reservations of the pool's operating system from the region, none if
+// the region has none
+func fetchCapacityReservations(apiClient *daytona.APIClient, regionID, poolOS string) ([]capacityReservation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	region, _, err := apiClient.OrganizationsAPI.GetRegionById(ctx, regionID).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get region %s from Daytona API: %w", regionID, err)
+	}
+
+	property, found := region.AdditionalProperties[RegionCapacityReservationsProperty]
+	if !found || property == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(property)
+	if err != nil {
+		return nil, err
+	}
+	var all []capacityReservation
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return nil, fmt.Errorf("invalid %s on region %s: %w", RegionCapacityReservationsProperty, regionID, err)
+	}
+
+	var reservations []capacityReservation
+	for _, reservation := range all {
+		if reservation.OrganizationID == "" || reservation.Cpu < 0 || reservation.MemoryGiB < 0 {
+			return nil, fmt.Errorf("invalid %s on region %s: every reservation needs an organization and non-negative resources", RegionCapacityReservationsProperty, regionID)
+		}
+		if reservation.OS == "" {
+			reservation.OS = OSLinux
+		}
+		if reservation.OS == poolOS {
+			reservations = append(reservations, reservation)
+		}
+	}
+	return reservations, nil
+}
+
+// reserveCapacity raises the idle CPU and memory requirements by the reserved capacity the organizations' started
+// sandboxes do not use. The requirements are left unchanged when the reservations cannot be read, as guessing them
+// would either scale up for nothing or drop the reservations.
+func reserveCapacity(apiClient *daytona.APIClient, cfg *Config) *Config {
+	reservations, err := fetchCapacityReservations(apiClient, cfg.RegionID, cfg.PoolOS)
+	if err != nil {
+		log.Warnf("Could not read capacity reservations, not reserving capacity this cycle: %v", err)
+		return cfg
+	}
+	reservedIdle.Reset()
+	if len(reservations) == 0 {
+		return cfg
+	}
+
+	usageByOrg, err := gatherOrgUsage(apiClient, cfg.RegionID)
+	if err != nil {
+		log.Warnf("Could not gather organization usage, not reserving capacity this cycle: %v", err)
+		return cfg
+	}
+
+	var reservedCpu, reservedMemoryGiB float32
+	for _, reservation := range reservations {
+		unusedCpu, unusedMemoryGiB := reservation.Cpu, reservation.MemoryGiB
+		if usage, found := usageByOrg[reservation.OrganizationID]; found {
+			unusedCpu = float32(math.Max(float64(unusedCpu-usage.cpu), 0))
+			unusedMemoryGiB = float32(math.Max(float64(unusedMemoryGiB-usage.memoryGiB), 0))
+		}
+		reservedIdle.WithLabelValues(reservation.OrganizationID, "cpu").Set(float64(unusedCpu))
+		reservedIdle.WithLabelValues(reservation.OrganizationID, "memory").Set(float64(unusedMemoryGiB))
+		reservedCpu += unusedCpu
+		reservedMemoryGiB += unusedMemoryGiB
+	}
+	if reservedCpu == 0 && reservedMemoryGiB == 0 {
+		return cfg
+	}
+
+	reserved := *cfg
+	reserved.MinIdleCpu += int(math.Ceil(float64(reservedCpu)))
+	reserved.MinIdleMemory += int(math.Ceil(float64(reservedMemoryGiB)))
+	log.Debugf("Reserving CPU=%.2f, Mem=%.2fGiB of idle capacity for %d organizations: idle buffer raised to %d CPU, %d GiB memory.",
+		reservedCpu, reservedMemoryGiB, len(reservations), reserved.MinIdleCpu, reserved.MinIdleMemory)
+	return &reserved
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultDaytonaAPIRetryAttempts is the number of retries of a failed Daytona API call when
+	// DAYTONA_API_RETRY_ATTEMPTS is not set
+	DefaultDaytonaAPIRetryAttempts = 3
+
+	// DefaultDaytonaAPIRetryBaseDelay and DefaultDaytonaAPIRetryMaxDelay bound the exponential backoff between retries
+	DefaultDaytonaAPIRetryBaseDelay = 200 * time.Millisecond
+	DefaultDaytonaAPIRetryMaxDelay  = 5 * time.Second
+
+	// DefaultDaytonaAPIRetryBudget is the number of retries allowed per check interval when DAYTONA_API_RETRY_BUDGET
+	// is not set
+	DefaultDaytonaAPIRetryBudget = 20
+)
+
+// retryingTransport retries Daytona API calls failing with a network error or a transient status, with exponential
+// backoff and full jitter, so a short API outage does not skip a whole controller cycle. Creations are not retried as
+// a request that timed out may have succeeded. Retries are limited by a budget refilled every check interval, so a
+// longer outage fails the cycle quickly instead of stretching it.
+type retryingTransport struct {
+	attempts  int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	next      http.RoundTripper
+
+	mu           sync.Mutex
+	budget       int
+	remaining    int
+	window       time.Duration
+	windowStart  time.Time
+	budgetWarned bool
+}
+
+func newRetryingTransport(cfg *Config, next http.RoundTripper) *retryingTransport {
+	return &retryingTransport{
+		attempts:  cfg.DaytonaAPIRetryAttempts,
+		baseDelay: cfg.DaytonaAPIRetryBaseDelay,
+		maxDelay:  cfg.DaytonaAPIRetryMaxDelay,
+		next:      next,
+		budget:    cfg.DaytonaAPIRetryBudget,
+		window:    cfg.CheckInterval,
+	}
+}
+
+func (t *retryingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retryable := req.Method != http.MethodPost && (req.Body == nil || req.GetBody != nil)
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(req)
+		if !retryable || attempt >= t.attempts || !isTransientFailure(resp, err) {
+			return resp, err
+		}
+		if !t.takeRetry() {
+			daytonaAPIRetries.WithLabelValues("budget_exhausted").Inc()
+			return resp, err
+		}
+
+		delay := t.backoff(attempt, resp)
+		if err != nil {
+			log.Debugf("Daytona API call %s %s failed: %v. Retrying in %s.", req.Method, req.URL.Path, err, delay)
+		} else {
+			log.Debugf("Daytona API call %s %s returned status %d. Retrying in %s.", req.Method, req.URL.Path, resp.StatusCode, delay)
+			// The body is drained so the connection is reused
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		daytonaAPIRetries.WithLabelValues("retried").Inc()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// isTransientFailure reports whether the call failed in a way a retry may not
+func isTransientFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns a random delay up to the exponential backoff of the attempt, or the delay the API asked for with
+// Retry-After, at most the maximum delay
+func (t *retryingTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, t.maxDelay)
+		}
+	}
+	delay := min(t.baseDelay<<attempt, t.maxDelay)
+	return rand.N(delay) + 1
+}
+
+// takeRetry consumes a retry of the current check interval's budget, returning false if none is left
+func (t *retryingTransport) takeRetry() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now := time.Now(); now.Sub(t.windowStart) >= t.window {
+		t.windowStart = now
+		t.remaining = t.budget
+		t.budgetWarned = false
+	}
+	if t.remaining == 0 {
+		if !t.budgetWarned {
+			log.Warnf("Daytona API retry budget of %d per %s exhausted, failing calls without retrying until it refills.", t.budget, t.window)
+			t.budgetWarned = true
+		}
+		return false
+	}
+	t.remaining--
+	return true
+}
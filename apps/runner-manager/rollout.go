@@ -0,0 +1,148 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/daytonaio/common-go/pkg/protection"
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// RolloutVersionAnnotation records the runner version the node's runner is being replaced for
+	RolloutVersionAnnotation = "daytona.io/rollout-version"
+
+	// DefaultRolloutMaxSurge is the number of runners replaced at once when RUNNER_ROLLOUT_MAX_SURGE is not set
+	DefaultRolloutMaxSurge = 1
+
+	// EventReasonRolloutReplace is the reason of the event recorded on a node whose runner is replaced by the rollout
+	EventReasonRolloutReplace = "RolloutReplace"
+)
+
+// runnerVersion returns the version the runner reports, its deprecated version field for runners predating the app
+// version
+func runnerVersion(runner daytona.RunnerFull) string {
+	if version := runner.GetAppVersion(); version != "" {
+		return version
+	}
+	return runner.GetVersion()
+}
+
+// reconcileRollout replaces the runners whose version differs from the runnerVersion of the region's runner pool
+// configuration, at most RUNNER_ROLLOUT_MAX_SURGE at a time. An outdated runner is made unschedulable, which removes
+// its capacity from the idle buffer so the regular scale-up adds a node with the current version, and the regular
+// scale-down removes its node once it is idle. Runners hosting started sandboxes are drained first, within
+// DRAIN_TIMEOUT when it is set. It runs before the resource metrics are calculated so they account for the change.
+func reconcileRollout(backend clusterBackend, apiClient *daytona.APIClient, cfg *Config, state *ClusterState) {
+	expected, err := fetchRegionPoolConfig(apiClient, cfg.RegionID)
+	if err != nil {
+		log.Warnf("Could not get the target runner version, skipping the rollout this cycle: %v", err)
+		return
+	}
+	if expected == nil || expected.RunnerVersion == nil || *expected.RunnerVersion == "" {
+		rolloutOutdatedRunners.Set(0)
+		return
+	}
+	target := *expected.RunnerVersion
+
+	var outdated []daytona.RunnerFull
+	inProgress := 0
+	for _, runner := range state.Runners {
+		node, found := state.NodeByIP[runner.GetDomain()]
+		if !found || runnerVersion(runner) == target {
+			continue
+		}
+		if node.Annotations[RolloutVersionAnnotation] == target {
+			// A runner made schedulable again while being replaced is left out of the rollout
+			if runner.GetUnschedulable() {
+				inProgress++
+			}
+			continue
+		}
+		outdated = append(outdated, runner)
+	}
+	rolloutOutdatedRunners.Set(float64(len(outdated) + inProgress))
+
+	slots := cfg.RolloutMaxSurge - inProgress
+	if len(outdated) == 0 || slots <= 0 {
+		if len(outdated) > 0 {
+			log.Debugf("Rollout to runner version %s has %d replacements in progress, %d runners waiting.", target, inProgress, len(outdated))
+		}
+		return
+	}
+
+	sortForReplacement(outdated)
+
+	for _, runner := range outdated {
+		if slots == 0 {
+			break
+		}
+		message := fmt.Sprintf("Runner %s of version %s is replaced by one of version %s: it is unschedulable and the node is removed once drained", runner.GetId(), runnerVersion(runner), target)
+		if replaceRunner(backend, apiClient, state, runner, RolloutVersionAnnotation, target, EventReasonRolloutReplace, message) {
+			rolloutReplacements.Inc()
+			slots--
+		}
+	}
+}
+
+// sortForReplacement orders runners to replace with the idle ones first, then the ones with the fewest started
+// sandboxes to drain
+func sortForReplacement(runners []daytona.RunnerFull) {
+	sort.SliceStable(runners, func(i, j int) bool {
+		if isRunnerAllocated(runners[i]) != isRunnerAllocated(runners[j]) {
+			return !isRunnerAllocated(runners[i])
+		}
+		return runners[i].GetCurrentStartedSandboxes() < runners[j].GetCurrentStartedSandboxes()
+	})
+}
+
+// replaceRunner makes the runner unschedulable and annotates its node with the given marker, so the regular scale-up
+// replaces its capacity and the regular scale-down removes the node once drained. A runner hosting sandboxes is
+// drained, within DRAIN_TIMEOUT when it is set. It returns false if the runner is protected or could not be changed.
+func replaceRunner(backend clusterBackend, apiClient *daytona.APIClient, state *ClusterState, runner daytona.RunnerFull, annotation, value, reason, message string) bool {
+	node := state.NodeByIP[runner.GetDomain()]
+	runnerLog := log.WithFields(log.Fields{"node": node.Name, "domain": runner.GetDomain(), "runner": runner.GetId()})
+	if protection.IsDoNotDisturb(node.Annotations) || state.ProtectedRunnerIDs[runner.GetId()] {
+		runnerLog.Debugf("Runner on node %s is protected as do-not-disturb, not replacing it.", node.Name)
+		return false
+	}
+	if _, hibernated := node.Annotations[HibernatedAtAnnotation]; hibernated {
+		return false
+	}
+
+	if !runner.GetUnschedulable() {
+		if err := updateRunnerScheduling(apiClient, runner.GetId(), true); err != nil {
+			runnerLog.Errorf("Error marking runner %s unschedulable for its replacement: %v", runner.GetId(), err)
+			return false
+		}
+		markRunnerUnschedulable(state, runner.GetId())
+	}
+
+	annotations := map[string]*string{annotation: &value}
+	if isRunnerAllocated(runner) {
+		startedAt := time.Now().UTC().Format(time.RFC3339)
+		annotations[ScaleDownDrainAnnotation] = &startedAt
+	}
+	if err := backend.PatchNode(context.Background(), node.Name, annotations, nil); err != nil {
+		runnerLog.Errorf("Error recording the replacement of the runner on node %s: %v", node.Name, err)
+	} else {
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		for key, value := range annotations {
+			node.Annotations[key] = *value
+		}
+	}
+
+	runnerLog.Infof("Replacing runner on node %s, draining its %.0f started sandboxes: %s", node.Name, runner.GetCurrentStartedSandboxes(), message)
+	recordNodeEvent(backend, node, corev1.EventTypeNormal, reason, message)
+	return true
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+)
+
+// updateRunnerScheduling marks a runner schedulable or unschedulable in the Daytona API.
+// The generated client does not send the request body for this endpoint, so the request is built here.
+func updateRunnerScheduling(apiClient *daytona.APIClient, runnerID string, unschedulable bool) error {
+	body, err := json.Marshal(map[string]bool{"unschedulable": unschedulable})
+	if err != nil {
+		return err
+	}
+
+	status, err := sendRunnerAdminRequest(apiClient, http.MethodPatch, runnerID, "/scheduling", body)
+	if err != nil {
+		return fmt.Errorf("failed to update scheduling of runner %s: %w", runnerID, err)
+	}
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("failed to update scheduling of runner %s: Daytona API returned status %d", runnerID, status)
+	}
+	return nil
+}
+
+// requestRunnerRestart asks the Daytona API to restart a runner. The endpoint is not part of the generated client, so
+// the request is built here; errRestartUnsupported is returned when the Daytona API does not serve it.
+func requestRunnerRestart(apiClient *daytona.APIClient, runnerID string) error {
+	status, err := sendRunnerAdminRequest(apiClient, http.MethodPost, runnerID, "/restart", nil)
+	if err != nil {
+		return fmt.Errorf("failed to request restart of runner %s: %w", runnerID, err)
+	}
+	if status == http.StatusNotFound || status == http.StatusMethodNotAllowed {
+		return errRestartUnsupported
+	}
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("failed to request restart of runner %s: Daytona API returned status %d", runnerID, status)
+	}
+	return nil
+}
+
+// errRestartUnsupported is returned by requestRunnerRestart when the Daytona API cannot restart runners
+var errRestartUnsupported = errors.New("the Daytona API does not support restarting runners")
+
+// sendRunnerAdminRequest sends a request to the admin endpoint of the runner under path and returns the status code
+func sendRunnerAdminRequest(apiClient *daytona.APIClient, method, runnerID, path string, body []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	apiCfg := apiClient.GetConfig()
+	if len(apiCfg.Servers) == 0 {
+		return 0, fmt.Errorf("no Daytona API server configured")
+	}
+
+	endpoint := apiCfg.Servers[0].URL + "/admin/runners/" + url.PathEscape(runnerID) + path
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	for key, value := range apiCfg.DefaultHeader {
+		req.Header.Set(key, value)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := apiCfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// isRunnerAllocated reports whether the runner holds any resources of sandboxes or snapshots
+func isRunnerAllocated(runner daytona.RunnerFull) bool {
+	return runner.GetCurrentAllocatedCpu() > 0 ||
+		runner.GetCurrentAllocatedMemoryGiB() > 0 ||
+		runner.GetCurrentAllocatedDiskGiB() > 0 ||
+		runner.GetCurrentStartedSandboxes() > 0 ||
+		runner.GetCurrentSnapshotCount() > 0
+}
+
+// confirmRunnerUnschedulable marks the runner unschedulable in the Daytona API, even if it already was as no sandbox
+// must be placed on it while its node is removed, and returns why it cannot be removed when its allocation changed
+// since the cluster state was gathered, e.g. a sandbox created on it just before it was made unschedulable
+func confirmRunnerUnschedulable(apiClient *daytona.APIClient, runner daytona.RunnerFull) (string, error) {
+	if err := updateRunnerScheduling(apiClient, runner.GetId(), true); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	current, _, err := apiClient.RunnersAPI.GetRunnerFullById(ctx, runner.GetId()).Execute()
+	if err != nil {
+		return "", fmt.Errorf("failed to get runner %s from Daytona API: %w", runner.GetId(), err)
+	}
+
+	switch {
+	case current.GetCurrentStartedSandboxes() != runner.GetCurrentStartedSandboxes():
+		return fmt.Sprintf("started sandboxes changed from %.0f to %.0f", runner.GetCurrentStartedSandboxes(), current.GetCurrentStartedSandboxes()), nil
+	case current.GetCurrentAllocatedCpu() != runner.GetCurrentAllocatedCpu(),
+		current.GetCurrentAllocatedMemoryGiB() != runner.GetCurrentAllocatedMemoryGiB(),
+		current.GetCurrentAllocatedDiskGiB() != runner.GetCurrentAllocatedDiskGiB():
+		return fmt.Sprintf("allocation changed from CPU=%.2f, Mem=%.2fGiB, Disk=%.2fGiB to CPU=%.2f, Mem=%.2fGiB, Disk=%.2fGiB",
+			runner.GetCurrentAllocatedCpu(), runner.GetCurrentAllocatedMemoryGiB(), runner.GetCurrentAllocatedDiskGiB(),
+			current.GetCurrentAllocatedCpu(), current.GetCurrentAllocatedMemoryGiB(), current.GetCurrentAllocatedDiskGiB()), nil
+	case current.GetCurrentSnapshotCount() != runner.GetCurrentSnapshotCount():
+		return fmt.Sprintf("snapshots changed from %.0f to %.0f", runner.GetCurrentSnapshotCount(), current.GetCurrentSnapshotCount()), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,271 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// PlaceholderClassLabel marks placeholder pods created to make room for a reserved sandbox class
+const PlaceholderClassLabel = "daytona.io/placeholder-class"
+
+// sandboxClass is a sandbox size the pool always keeps room for, e.g. two 16-core sandboxes
+type sandboxClass struct {
+	Name      string  `json:"name"`
+	Cpu       float32 `json:"cpu"`
+	MemoryGiB float32 `json:"memoryGiB"`
+	// Count is the number of sandboxes of the class that must fit on the schedulable runners at all times
+	Count int `json:"count"`
+}
+
+func (c *sandboxClass) shape() sandboxShape {
+	return sandboxShape{Cpu: c.Cpu, MemoryGiB: c.MemoryGiB}
+}
+
+// parseSandboxClasses parses RESERVED_SANDBOX_CLASSES, a JSON array of classes
+func parseSandboxClasses(value string) ([]sandboxClass, error) {
+	var classes []sandboxClass
+	if err := json.Unmarshal([]byte(value), &classes); err != nil {
+		return nil, err
+	}
+	if len(classes) == 0 {
+		return nil, fmt.Errorf("no classes defined")
+	}
+
+	names := make(map[string]bool)
+	for _, class := range classes {
+		if class.Name == "" {
+			return nil, fmt.Errorf("every class needs a name")
+		}
+		if names[class.Name] {
+			return nil, fmt.Errorf("class %q is defined more than once", class.Name)
+		}
+		names[class.Name] = true
+		if class.Cpu <= 0 || class.MemoryGiB <= 0 {
+			return nil, fmt.Errorf("class %q needs a positive cpu and memoryGiB", class.Name)
+		}
+		if class.Count <= 0 {
+			return nil, fmt.Errorf("class %q needs a positive count", class.Name)
+		}
+	}
+	return classes, nil
+}
+
+// findSandboxClass returns the class with the given name, nil if there is none
+func findSandboxClass(cfg *Config, name string) *sandboxClass {
+	for i := range cfg.ReservedSandboxClasses {
+		if cfg.ReservedSandboxClasses[i].Name == name {
+			return &cfg.ReservedSandboxClasses[i]
+		}
+	}
+	return nil
+}
+
+// classRoomStatus is the room a reserved sandbox class requires and what currently covers it, in sandboxes of the class
+// except for Pending
+type classRoomStatus struct {
+	Required int
+	Free     int // Sandboxes of the class fitting on the schedulable runners
+	Nascent  int // Sandboxes of the class fitting on nodes with a placeholder but no runner yet
+	Pending  int // Class placeholders waiting for a node
+	// PerNode is the number of sandboxes of the class a new node is expected to fit
+	PerNode int
+}
+
+// deficit returns how many more class placeholders are needed to cover the requirement
+func (s *classRoomStatus) deficit() int {
+	missing := s.Required - s.Free - s.Nascent
+	if missing <= 0 {
+		return 0
+	}
+	return (missing+s.PerNode-1)/s.PerNode - s.Pending
+}
+
+// covered reports whether the class has room for its required sandboxes without any pending placeholders
+func (s *classRoomStatus) covered() bool {
+	return s.Free+s.Nascent >= s.Required
+}
+
+// packSandboxClasses places the sandboxes of the classes still missing from placed into the free slots, the largest
+// classes first as they are the hardest to fit, and returns the sandboxes placed by class name
+func packSandboxClasses(classes []sandboxClass, free []sandboxShape, placed map[string]int) map[string]int {
+	sorted := append([]sandboxClass(nil), classes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Cpu != sorted[j].Cpu {
+			return sorted[i].Cpu > sorted[j].Cpu
+		}
+		return sorted[i].MemoryGiB > sorted[j].MemoryGiB
+	})
+
+	packed := make(map[string]int, len(sorted))
+	for _, class := range sorted {
+		for packed[class.Name]+placed[class.Name] < class.Count && placeShape(free, class.shape()) {
+			packed[class.Name]++
+		}
+	}
+	return packed
+}
+
+// runnerRoom returns the room left on the schedulable runners other than the excluded one
+func runnerRoom(state *ClusterState, excludedRunnerID string) []sandboxShape {
+	var free []sandboxShape
+	for _, runners := range [][]daytona.RunnerFull{state.IdleRunners, state.ActiveRunners} {
+		for _, runner := range runners {
+			if runner.GetUnschedulable() || runner.GetId() == excludedRunnerID {
+				continue
+			}
+			free = append(free, sandboxShape{
+				Cpu:       runner.GetCpu() - runner.GetCurrentAllocatedCpu(),
+				MemoryGiB: runner.GetMemory() - runner.GetCurrentAllocatedMemoryGiB(),
+			})
+		}
+	}
+	return free
+}
+
+// sandboxesPerNode returns how many sandboxes of the class a new node fits: a node of the smallest profile fitting the
+// class when the pool has profiles, otherwise an average node of the pool. Placeholders of the class request its size,
+// so a new node fits at least one.
+func sandboxesPerNode(cfg *Config, metrics *ResourceMetrics, class *sandboxClass) int {
+	nodeCpu, nodeMem := metrics.AvgCpuPerNode, metrics.AvgMemPerNode
+	if profile := smallestFittingProfile(cfg, class.shape()); profile != nil {
+		nodeCpu, nodeMem = profile.Cpu, profile.MemoryGiB
+	}
+	perNode := int(math.Min(math.Floor(float64(nodeCpu/class.Cpu)), math.Floor(float64(nodeMem/class.MemoryGiB))))
+	return max(perNode, 1)
+}
+
+// gatherClassRoom counts the room of each reserved sandbox class on the schedulable runners and the nascent nodes,
+// and the pending placeholders created for it
+func gatherClassRoom(cfg *Config, state *ClusterState, metrics *ResourceMetrics) map[string]*classRoomStatus {
+	free := packSandboxClasses(cfg.ReservedSandboxClasses, runnerRoom(state, ""), nil)
+
+	var nascentRoom []sandboxShape
+	for _, node := range state.NascentNodes {
+		cpu, memoryGiB, err := getNodeAllocatableResources(node)
+		if err != nil {
+			continue
+		}
+		nascentRoom = append(nascentRoom, sandboxShape{Cpu: cpu, MemoryGiB: memoryGiB})
+	}
+	nascent := packSandboxClasses(cfg.ReservedSandboxClasses, nascentRoom, free)
+
+	rooms := make(map[string]*classRoomStatus, len(cfg.ReservedSandboxClasses))
+	for i := range cfg.ReservedSandboxClasses {
+		class := &cfg.ReservedSandboxClasses[i]
+		rooms[class.Name] = &classRoomStatus{
+			Required: class.Count,
+			Free:     free[class.Name],
+			Nascent:  nascent[class.Name],
+			PerNode:  sandboxesPerNode(cfg, metrics, class),
+		}
+	}
+	for _, pod := range state.PendingPlaceholders {
+		if room, found := rooms[pod.Labels[PlaceholderClassLabel]]; found {
+			room.Pending++
+		}
+	}
+
+	sandboxClassRoom.Reset()
+	sandboxClassRequired.Reset()
+	for name, room := range rooms {
+		sandboxClassRoom.WithLabelValues(name).Set(float64(room.Free))
+		sandboxClassRequired.WithLabelValues(name).Set(float64(room.Required))
+	}
+
+	return rooms
+}
+
+// leavesClassUncovered reports whether draining the runner would leave a reserved sandbox class without room for its
+// required sandboxes
+func leavesClassUncovered(cfg *Config, state *ClusterState, runnerID string) bool {
+	if len(cfg.ReservedSandboxClasses) == 0 {
+		return false
+	}
+	free := packSandboxClasses(cfg.ReservedSandboxClasses, runnerRoom(state, runnerID), nil)
+	for _, class := range cfg.ReservedSandboxClasses {
+		if free[class.Name] < class.Count {
+			return true
+		}
+	}
+	return false
+}
+
+// handleClassScaleUp creates placeholders sized for the reserved sandbox classes without enough room and returns true
+// if any were created. With node profiles, the placeholders request the smallest profile fitting the class.
+func handleClassScaleUp(backend clusterBackend, cfg *Config, state *ClusterState) bool {
+	createdCount := 0
+	for _, class := range cfg.ReservedSandboxClasses {
+		room := state.ClassRoom[class.Name]
+		deficit := room.deficit()
+		if deficit <= 0 {
+			continue
+		}
+
+		profile := ""
+		if len(cfg.NodeProfiles) > 0 {
+			fitting := smallestFittingProfile(cfg, class.shape())
+			if fitting == nil {
+				log.Warnf("Sandbox class %s with CPU=%.2f, Mem=%.2fGiB is larger than every node profile. It cannot be scaled for.", class.Name, class.Cpu, class.MemoryGiB)
+				continue
+			}
+			profile = fitting.Name
+		}
+		if deficit = capScaleUp(cfg, state, deficit); deficit == 0 {
+			continue
+		}
+
+		log.WithField("class", class.Name).Infof("Sandbox class %s has room for %d sandboxes (%d on nascent nodes, %d placeholders in-flight), requires %d. Creating %d placeholder pods.",
+			class.Name, room.Free+room.Nascent, room.Nascent, room.Pending, room.Required, deficit)
+		for i := 0; i < deficit; i++ {
+			pod, err := createPlaceholderPod(backend, cfg, PlaceholderPodLabel, placeholderOptions{Class: class.Name, Profile: profile, CapacityType: state.CapacityType})
+			if err != nil {
+				log.Errorf("Error creating placeholder pod for sandbox class %s: %v", class.Name, err)
+				continue
+			}
+			recordPlaceholderEvent(backend, pod, corev1.EventTypeNormal, EventReasonScaleUp,
+				fmt.Sprintf("Placeholder created to add a node: sandbox class %s has room for fewer than %d sandboxes", class.Name, class.Count))
+			room.Pending++
+			state.PlaceholdersCreated++
+			createdCount++
+		}
+	}
+	return createdCount > 0
+}
+
+// selectPlaceholderClass labels the placeholder with its sandbox class and makes it request at least the class's size,
+// so the node added for it fits a sandbox of the class
+func selectPlaceholderClass(pod *corev1.Pod, cfg *Config, name string) {
+	class := findSandboxClass(cfg, name)
+	if class == nil {
+		return
+	}
+
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[PlaceholderClassLabel] = class.Name
+
+	resources := &pod.Spec.Containers[0].Resources
+	if resources.Requests == nil {
+		resources.Requests = corev1.ResourceList{}
+	}
+	for name, quantity := range map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceCPU:    *resource.NewMilliQuantity(int64(class.Cpu*1000), resource.DecimalSI),
+		corev1.ResourceMemory: *resource.NewQuantity(int64(class.MemoryGiB*1024)*1024*1024, resource.BinarySI),
+	} {
+		if current, found := resources.Requests[name]; !found || current.Cmp(quantity) < 0 {
+			resources.Requests[name] = quantity
+		}
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+)
+
+const (
+	// ScaleDownCheckBackups blocks removing a node while sandbox backups or snapshot transfers run on its runner
+	ScaleDownCheckBackups = "backups"
+	// ScaleDownCheckSnapshots blocks removing a node whose runner holds the only copy of a snapshot pending sandboxes need
+	ScaleDownCheckSnapshots = "snapshots"
+
+	// ScaleDownChecksNone disables all scale-down checks
+	ScaleDownChecksNone = "none"
+
+	// runnerAPITimeout bounds each call to a runner's API
+	runnerAPITimeout = 10 * time.Second
+)
+
+// DefaultScaleDownChecks are the checks run when SCALE_DOWN_CHECKS is not set
+var DefaultScaleDownChecks = []string{ScaleDownCheckBackups, ScaleDownCheckSnapshots}
+
+// scaleDownCheck decides whether the node of a deletable runner may be removed. Checks run after the capacity
+// checks, a blocked runner stays deletable and is considered again in the next cycle.
+type scaleDownCheck interface {
+	// Check returns why the runner's node must not be removed yet, or an empty string if it may be
+	Check(ctx context.Context, env *scaleDownCheckEnv, runner daytona.RunnerFull) (string, error)
+}
+
+// scaleDownChecks are the available checks by name, selectable with SCALE_DOWN_CHECKS
+var scaleDownChecks = map[string]scaleDownCheck{
+	ScaleDownCheckBackups:   backupCheck{},
+	ScaleDownCheckSnapshots: snapshotCopyCheck{},
+}
+
+// scaleDownCheckEnv is shared by the checks of one scale-down pass, so the region's sandboxes and snapshot
+// locations are fetched at most once however many candidates are checked
+type scaleDownCheckEnv struct {
+	apiClient  *daytona.APIClient
+	regionID   string
+	httpClient *http.Client
+
+	sandboxes     []daytona.Sandbox
+	sandboxesErr  error
+	sandboxesDone bool
+
+	snapshotRefs    map[string]string
+	snapshotHolders map[string][]daytona.RunnerSnapshotDto
+}
+
+func newScaleDownCheckEnv(apiClient *daytona.APIClient, regionID string) *scaleDownCheckEnv {
+	return &scaleDownCheckEnv{
+		apiClient:       apiClient,
+		regionID:        regionID,
+		httpClient:      &http.Client{Timeout: runnerAPITimeout},
+		snapshotRefs:    make(map[string]string),
+		snapshotHolders: make(map[string][]daytona.RunnerSnapshotDto),
+	}
+}
+
+// regionSandboxes returns all sandboxes of the region, listed on first use
+func (e *scaleDownCheckEnv) regionSandboxes() ([]daytona.Sandbox, error) {
+	if !e.sandboxesDone {
+		e.sandboxes, e.sandboxesErr = listRegionSandboxes(e.apiClient, e.regionID)
+		e.sandboxesDone = true
+	}
+	return e.sandboxes, e.sandboxesErr
+}
+
+// snapshotRef resolves the registry reference of a snapshot name, which runners store snapshots under
+func (e *scaleDownCheckEnv) snapshotRef(ctx context.Context, name string) (string, error) {
+	if ref, found := e.snapshotRefs[name]; found {
+		return ref, nil
+	}
+
+	snapshot, _, err := e.apiClient.SnapshotsAPI.GetSnapshot(ctx, name).Execute()
+	if err != nil {
+		return "", fmt.Errorf("failed to get snapshot %s from Daytona API: %w", name, err)
+	}
+	e.snapshotRefs[name] = snapshot.GetRef()
+	return snapshot.GetRef(), nil
+}
+
+// holders returns the runners the Daytona API reports the snapshot on
+func (e *scaleDownCheckEnv) holders(ctx context.Context, ref string) ([]daytona.RunnerSnapshotDto, error) {
+	if holders, found := e.snapshotHolders[ref]; found {
+		return holders, nil
+	}
+
+	holders, _, err := e.apiClient.RunnersAPI.GetRunnersBySnapshotRef(ctx).Ref(ref).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get runners of snapshot %s from Daytona API: %w", ref, err)
+	}
+	e.snapshotHolders[ref] = holders
+	return holders, nil
+}
+
+// runnerGet calls a runner's API with its token and decodes the JSON response into out. It returns false if
+// the runner reports the resource as not found.
+func (e *scaleDownCheckEnv) runnerGet(ctx context.Context, runner daytona.RunnerFull, path string, query url.Values, out any) (bool, error) {
+	apiUrl := strings.TrimSuffix(runner.GetApiUrl(), "/")
+	if apiUrl == "" {
+		return false, fmt.Errorf("runner %s has no API URL", runner.GetId())
+	}
+
+	reqUrl := apiUrl + path
+	if len(query) > 0 {
+		reqUrl += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqUrl, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+runner.GetApiKey())
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to call runner %s: %w", runner.GetId(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("runner %s returned %s for %s", runner.GetId(), resp.Status, path)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("failed to decode response of runner %s for %s: %w", runner.GetId(), path, err)
+	}
+	return true, nil
+}
+
+// runScaleDownChecks runs the configured checks against the runner in order and returns the name of the first
+// blocking check with its reason. A failing check blocks the removal, since the node's data cannot be verified safe.
+func runScaleDownChecks(cfg *Config, env *scaleDownCheckEnv, runner daytona.RunnerFull) (string, string) {
+	for _, name := range cfg.ScaleDownChecks {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		reason, err := scaleDownChecks[name].Check(ctx, env, runner)
+		cancel()
+
+		if err != nil {
+			return name, fmt.Sprintf("check failed: %v", err)
+		}
+		if reason != "" {
+			return name, reason
+		}
+	}
+	return "", ""
+}
+
+// backupCheck blocks while a sandbox on the runner is being backed up or has its snapshot transferred. The
+// runner's own backup state is authoritative, the Daytona API only learns about it on the runner's next sync.
+type backupCheck struct{}
+
+// runnerSandboxInfo is the sandbox info returned by the runner API
+type runnerSandboxInfo struct {
+	State       string `json:"state"`
+	BackupState string `json:"backupState"`
+}
+
+func (backupCheck) Check(ctx context.Context, env *scaleDownCheckEnv, runner daytona.RunnerFull) (string, error) {
+	sandboxes, err := env.regionSandboxes()
+	if err != nil {
+		return "", err
+	}
+
+	for _, sandbox := range sandboxes {
+		if sandbox.GetRunnerId() != runner.GetId() {
+			continue
+		}
+
+		switch sandbox.GetState() {
+		case daytona.SANDBOXSTATE_ARCHIVING, daytona.SANDBOXSTATE_BUILDING_SNAPSHOT, daytona.SANDBOXSTATE_PULLING_SNAPSHOT:
+			return fmt.Sprintf("sandbox %s is %s", sandbox.GetId(), sandbox.GetState()), nil
+		}
+
+		var info runnerSandboxInfo
+		found, err := env.runnerGet(ctx, runner, "/sandboxes/"+url.PathEscape(sandbox.GetId()), nil, &info)
+		if err != nil {
+			return "", err
+		}
+		if found && (info.BackupState == "PENDING" || info.BackupState == "IN_PROGRESS") {
+			return fmt.Sprintf("backup of sandbox %s is %s", sandbox.GetId(), strings.ToLower(info.BackupState)), nil
+		}
+	}
+
+	return "", nil
+}
+
+// snapshotCopyCheck blocks while the runner holds the only copy of a snapshot that sandboxes waiting to be created,
+// started or restored need, since removing the node would make them pull or rebuild it
+type snapshotCopyCheck struct{}
+
+// runnerSnapshotExists is the snapshot existence response of the runner API
+type runnerSnapshotExists struct {
+	Exists bool `json:"exists"`
+}
+
+func (snapshotCopyCheck) Check(ctx context.Context, env *scaleDownCheckEnv, runner daytona.RunnerFull) (string, error) {
+	sandboxes, err := env.regionSandboxes()
+	if err != nil {
+		return "", err
+	}
+
+	checked := make(map[string]bool)
+	for _, sandbox := range sandboxes {
+		switch sandbox.GetState() {
+		case daytona.SANDBOXSTATE_CREATING, daytona.SANDBOXSTATE_STARTING, daytona.SANDBOXSTATE_RESTORING, daytona.SANDBOXSTATE_PULLING_SNAPSHOT:
+		default:
+			continue
+		}
+
+		name := sandbox.GetSnapshot()
+		if name == "" || checked[name] {
+			continue
+		}
+		checked[name] = true
+
+		ref, err := env.snapshotRef(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		if ref == "" {
+			continue
+		}
+
+		holders, err := env.holders(ctx, ref)
+		if err != nil {
+			return "", err
+		}
+		if len(holders) != 1 || holders[0].RunnerId != runner.GetId() {
+			continue
+		}
+
+		// The Daytona API may still list a snapshot the runner already removed
+		var exists runnerSnapshotExists
+		found, err := env.runnerGet(ctx, runner, "/snapshots/exists", url.Values{"snapshot": []string{ref}}, &exists)
+		if err != nil {
+			return "", err
+		}
+		if found && exists.Exists {
+			return fmt.Sprintf("runner holds the only copy of snapshot %s needed by sandbox %s", name, sandbox.GetId()), nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/daytonaio/common-go/pkg/protection"
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ScaleDownDrainAnnotation records when the scale-down made the node's runner unschedulable to remove the node
+const ScaleDownDrainAnnotation = "daytona.io/scale-down-drain-started-at"
+
+// drainSurplusRunner starts draining one schedulable runner when the pool has more capacity than it needs, by marking
+// it unschedulable in the Daytona API so no new sandboxes land on it. Only one drain runs at a time, so the sandboxes
+// moving off a runner never land on another one about to be drained. An idle runner has nothing to drain and becomes
+// deletable in the same cycle. It returns true if a drain was started.
+func drainSurplusRunner(backend clusterBackend, apiClient *daytona.APIClient, cfg *Config, state *ClusterState, metrics *ResourceMetrics) bool {
+	for _, runner := range state.ActiveRunners {
+		if node, found := state.NodeByIP[runner.GetDomain()]; found && runner.GetUnschedulable() && node.Annotations[ScaleDownDrainAnnotation] != "" {
+			log.Debugf("Runner %s is still draining, not draining another one.", runner.GetId())
+			return false
+		}
+	}
+
+	runner, node := selectDrainCandidate(cfg, state, metrics)
+	if node == nil {
+		return false
+	}
+	runnerLog := log.WithFields(log.Fields{"node": node.Name, "domain": runner.GetDomain(), "runner": runner.GetId()})
+
+	if err := updateRunnerScheduling(apiClient, runner.GetId(), true); err != nil {
+		runnerLog.Errorf("Error marking surplus runner %s unschedulable: %v", runner.GetId(), err)
+		return false
+	}
+	markRunnerUnschedulable(state, runner.GetId())
+	scaleDownDrains.WithLabelValues("started").Inc()
+
+	if !isRunnerAllocated(runner) {
+		runnerLog.Infof("Runner on node %s (%s) is surplus capacity and idle. It is now unschedulable and deletable.", node.Name, runner.GetDomain())
+		scaleDownDrains.WithLabelValues("drained").Inc()
+		return true
+	}
+
+	// The drain is finished by finishScaleDownDrains, which only considers the nodes annotated here
+	startedAt := time.Now().UTC().Truncate(time.Second)
+	if err := setNodeTimeAnnotation(backend, node.Name, ScaleDownDrainAnnotation, &startedAt); err != nil {
+		runnerLog.Errorf("Error recording scale-down drain start of node %s, it is drained until made schedulable again: %v", node.Name, err)
+		return true
+	}
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[ScaleDownDrainAnnotation] = startedAt.Format(time.RFC3339)
+	runnerLog.Infof("Runner on node %s (%s) is surplus capacity. Draining its %.0f started sandboxes for up to %s before removing the node.",
+		node.Name, runner.GetDomain(), runner.GetCurrentStartedSandboxes(), cfg.DrainTimeout)
+	recordNodeEvent(backend, node, corev1.EventTypeNormal, EventReasonScaleDown,
+		fmt.Sprintf("Selected for scale-down: runner %s is unschedulable and draining %.0f started sandboxes for up to %s", runner.GetId(), runner.GetCurrentStartedSandboxes(), cfg.DrainTimeout))
+	return true
+}
+
+// selectDrainCandidate returns the schedulable runner hosting the fewest started sandboxes whose removal leaves the
+// pool above its idle requirements, with room for its reserved sandbox classes and below its utilization limits, nil
+// if there is none
+func selectDrainCandidate(cfg *Config, state *ClusterState, metrics *ResourceMetrics) (daytona.RunnerFull, *corev1.Node) {
+	var candidates []daytona.RunnerFull
+	for _, runners := range [][]daytona.RunnerFull{state.IdleRunners, state.ActiveRunners} {
+		for _, runner := range runners {
+			if !runner.GetUnschedulable() && !state.ProtectedRunnerIDs[runner.GetId()] {
+				candidates = append(candidates, runner)
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].GetCurrentStartedSandboxes() != candidates[j].GetCurrentStartedSandboxes() {
+			return candidates[i].GetCurrentStartedSandboxes() < candidates[j].GetCurrentStartedSandboxes()
+		}
+		return candidates[i].GetCurrentAllocatedCpu() < candidates[j].GetCurrentAllocatedCpu()
+	})
+
+	zoneSpare := zoneSpareRunners(state)
+	for _, runner := range candidates {
+		node, found := state.NodeByIP[runner.GetDomain()]
+		if !found || protection.IsDoNotDisturb(node.Annotations) {
+			continue
+		}
+		if !isRunnerAllocated(runner) && isLastSpareInZone(cfg, zoneSpare, node) {
+			continue
+		}
+		if _, hibernated := node.Annotations[HibernatedAtAnnotation]; hibernated {
+			continue
+		}
+		if leavesClassUncovered(cfg, state, runner.GetId()) {
+			continue
+		}
+
+		// The sandboxes of the runner move to the others, so only its capacity leaves the pool
+		runnerCpu, runnerMem := effectiveCapacity(cfg, runner.GetCpu(), runner.GetMemory())
+		remaining := *metrics
+		remaining.TotalCPUCapacity -= runnerCpu
+		remaining.TotalMemoryGiBCapacity -= runnerMem
+		remaining.TotalDiskGiBCapacity -= runner.GetDisk()
+		remaining.TotalGPUCapacity -= getNodeAllocatableGPUs(node)
+		remaining.TotalAvailableCPU -= runnerCpu
+		remaining.TotalAvailableMemoryGiB -= runnerMem
+		remaining.TotalAvailableDiskGiB -= runner.GetDisk()
+		remaining.TotalAvailableGPU -= getNodeAllocatableGPUs(node)
+
+		idleRunners := len(state.IdleRunners)
+		if !isRunnerAllocated(runner) {
+			idleRunners--
+		}
+		if shouldScaleUp(&remaining, cfg, idleRunners, len(state.NascentNodes), state.QueuedSandboxes) || isAboveScaleDownUtilization(cfg, &remaining) {
+			continue
+		}
+		return runner, node
+	}
+	return daytona.RunnerFull{}, nil
+}
+
+// finishScaleDownDrains makes the runners drained by the scale-down or the rollout deletable once they host no started
+// sandboxes or their drain exceeded DRAIN_TIMEOUT, when set, and the remaining sandboxes are stopped with the node.
+// Drains of runners made schedulable again are cancelled.
+func finishScaleDownDrains(backend clusterBackend, cfg *Config, state *ClusterState) {
+	inProgress := 0
+	active := state.ActiveRunners[:0]
+	for _, runner := range state.ActiveRunners {
+		node, found := state.NodeByIP[runner.GetDomain()]
+		annotation := ""
+		if found {
+			annotation = node.Annotations[ScaleDownDrainAnnotation]
+		}
+		if annotation == "" {
+			active = append(active, runner)
+			continue
+		}
+		runnerLog := log.WithFields(log.Fields{"node": node.Name, "domain": runner.GetDomain(), "runner": runner.GetId()})
+
+		if !runner.GetUnschedulable() {
+			runnerLog.Infof("Runner on node %s is schedulable again. Cancelling its scale-down drain.", node.Name)
+			if err := setNodeTimeAnnotation(backend, node.Name, ScaleDownDrainAnnotation, nil); err != nil {
+				runnerLog.Errorf("Error clearing scale-down drain start of node %s: %v", node.Name, err)
+			}
+			scaleDownDrains.WithLabelValues("cancelled").Inc()
+			active = append(active, runner)
+			continue
+		}
+
+		startedAt, err := time.Parse(time.RFC3339, annotation)
+		if err != nil {
+			runnerLog.Warnf("Node %s has an invalid %s annotation %q, restarting its drain.", node.Name, ScaleDownDrainAnnotation, annotation)
+			startedAt = time.Now().UTC().Truncate(time.Second)
+			if err := setNodeTimeAnnotation(backend, node.Name, ScaleDownDrainAnnotation, &startedAt); err != nil {
+				runnerLog.Errorf("Error recording scale-down drain start of node %s: %v", node.Name, err)
+			}
+		}
+		elapsed := time.Since(startedAt)
+
+		switch {
+		case runner.GetCurrentStartedSandboxes() == 0:
+			runnerLog.Infof("Runner on node %s has no started sandboxes left after draining for %s. Removing the node.", node.Name, elapsed.Round(time.Second))
+			scaleDownDrains.WithLabelValues("drained").Inc()
+		case cfg.DrainTimeout > 0 && elapsed > cfg.DrainTimeout:
+			runnerLog.Warnf("Runner on node %s still hosts %.0f started sandboxes after draining for %s, exceeding DRAIN_TIMEOUT. Removing the node.",
+				node.Name, runner.GetCurrentStartedSandboxes(), elapsed.Round(time.Second))
+			recordNodeEvent(backend, node, corev1.EventTypeWarning, EventReasonScaleDown,
+				fmt.Sprintf("Drain timed out after %s with %.0f started sandboxes remaining, removing the node", cfg.DrainTimeout, runner.GetCurrentStartedSandboxes()))
+			scaleDownDrains.WithLabelValues("timed_out").Inc()
+		default:
+			inProgress++
+			active = append(active, runner)
+			continue
+		}
+		state.DeletableRunners = append(state.DeletableRunners, runner)
+	}
+	state.ActiveRunners = active
+	scaleDownDrainsInProgress.Set(float64(inProgress))
+}
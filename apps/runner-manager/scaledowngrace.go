@@ -0,0 +1,144 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// ScheduledForRemovalLabel marks the nodes selected for scale-down that are in their grace period, so they can be
+	// listed with a label selector
+	ScheduledForRemovalLabel = "daytona.io/scheduled-for-removal"
+
+	// ScheduledForRemovalAtAnnotation records when the node was selected for scale-down, its grace period runs from
+	// then
+	ScheduledForRemovalAtAnnotation = "daytona.io/scheduled-for-removal-at"
+
+	// EventReasonScaleDownCancelled is the reason of the event recorded on a node whose scheduled removal is cancelled
+	EventReasonScaleDownCancelled = "ScaleDownCancelled"
+)
+
+// scheduledForRemovalAt returns when the node was scheduled for removal, false if it is not
+func scheduledForRemovalAt(node *corev1.Node) (time.Time, bool) {
+	value, found := node.Annotations[ScheduledForRemovalAtAnnotation]
+	if !found {
+		return time.Time{}, false
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		// An unreadable mark restarts the grace period rather than removing the node right away
+		return time.Now(), true
+	}
+	return at, true
+}
+
+// markScheduledForRemoval labels the node with ScheduledForRemovalLabel and records when, or removes both
+func markScheduledForRemoval(backend clusterBackend, node *corev1.Node, scheduled bool) error {
+	var label, at *string
+	if scheduled {
+		value, now := "true", time.Now().UTC().Format(time.RFC3339)
+		label, at = &value, &now
+	}
+
+	if err := backend.PatchNode(context.Background(), node.Name, map[string]*string{ScheduledForRemovalAtAnnotation: at}, nil); err != nil {
+		return err
+	}
+	if err := backend.LabelNode(context.Background(), node.Name, map[string]*string{ScheduledForRemovalLabel: label}); err != nil {
+		return err
+	}
+
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	if node.Labels == nil {
+		node.Labels = map[string]string{}
+	}
+	if scheduled {
+		node.Annotations[ScheduledForRemovalAtAnnotation] = *at
+		node.Labels[ScheduledForRemovalLabel] = *label
+	} else {
+		delete(node.Annotations, ScheduledForRemovalAtAnnotation)
+		delete(node.Labels, ScheduledForRemovalLabel)
+	}
+	return nil
+}
+
+// awaitRemovalGrace returns the time left of the node's grace period before scale-down removes it, scheduling the node
+// for removal if it is not yet. Removal proceeds once nothing is left.
+func awaitRemovalGrace(backend clusterBackend, cfg *Config, node *corev1.Node) (time.Duration, error) {
+	at, scheduled := scheduledForRemovalAt(node)
+	if !scheduled {
+		if err := markScheduledForRemoval(backend, node, true); err != nil {
+			return 0, err
+		}
+		recordNodeEvent(backend, node, corev1.EventTypeNormal, EventReasonScaleDown,
+			fmt.Sprintf("Scheduled for removal in %s unless sandboxes are allocated on its runner meanwhile", cfg.ScaleDownGracePeriod))
+		scheduledRemovals.WithLabelValues("scheduled").Inc()
+		return cfg.ScaleDownGracePeriod, nil
+	}
+	if remaining := cfg.ScaleDownGracePeriod - time.Since(at); remaining > 0 {
+		return remaining, nil
+	}
+	return 0, nil
+}
+
+// cancelScheduledRemoval removes the scheduled-for-removal mark of the node, if any, when scale-down keeps it
+func cancelScheduledRemoval(backend clusterBackend, node *corev1.Node, reason string) {
+	if _, scheduled := scheduledForRemovalAt(node); !scheduled {
+		return
+	}
+	if err := markScheduledForRemoval(backend, node, false); err != nil {
+		log.WithField("node", node.Name).Errorf("Error cancelling the scheduled removal of node %s: %v", node.Name, err)
+		return
+	}
+	log.WithField("node", node.Name).Infof("Cancelled the scheduled removal of node %s: %s.", node.Name, reason)
+	recordNodeEvent(backend, node, corev1.EventTypeNormal, EventReasonScaleDownCancelled, "Scheduled removal cancelled: "+reason)
+	scheduledRemovals.WithLabelValues("cancelled").Inc()
+}
+
+// reconcileScheduledRemovals cancels the scheduled removal of the nodes whose runner is no longer deletable: sandboxes
+// were allocated on it or it was made schedulable again during the grace period
+func reconcileScheduledRemovals(backend clusterBackend, state *ClusterState) {
+	deletable := make(map[string]bool, len(state.DeletableRunners))
+	for _, runner := range state.DeletableRunners {
+		deletable[runner.GetId()] = true
+	}
+	runnerByNode := make(map[string]daytona.RunnerFull, len(state.Runners))
+	for _, runner := range state.Runners {
+		if node, found := state.NodeByIP[runner.GetDomain()]; found {
+			runnerByNode[node.Name] = runner
+		}
+	}
+
+	scheduled := 0
+	for i := range state.Nodes {
+		node := &state.Nodes[i]
+		if _, found := scheduledForRemovalAt(node); !found {
+			continue
+		}
+		runner, found := runnerByNode[node.Name]
+		if found && deletable[runner.GetId()] {
+			scheduled++
+			continue
+		}
+
+		reason := "its runner is no longer registered"
+		switch {
+		case found && isRunnerAllocated(runner):
+			reason = "sandboxes were allocated on its runner"
+		case found:
+			reason = "its runner is schedulable again"
+		}
+		cancelScheduledRemoval(backend, node, reason)
+	}
+	nodesScheduledForRemoval.Set(float64(scheduled))
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+)
+
+// Factors of the value of keeping a deletable runner, weighted by SCALE_DOWN_WEIGHTS
+const (
+	// ScaleDownFactorAge values younger nodes, so the oldest are replaced first as the pool scales
+	ScaleDownFactorAge = "age"
+	// ScaleDownFactorZone values nodes of the zones with the fewest nodes, so scale-down evens out the zones
+	ScaleDownFactorZone = "zone"
+	// ScaleDownFactorSnapshots values runners caching the most snapshots, which new sandboxes start from faster
+	ScaleDownFactorSnapshots = "snapshots"
+	// ScaleDownFactorCost values cheaper nodes, from NODE_HOURLY_PRICES or NODE_HOURLY_PRICE_LABEL
+	ScaleDownFactorCost = "cost"
+)
+
+// parseScaleDownWeights parses SCALE_DOWN_WEIGHTS in the format "age=1,zone=2,snapshots=1,cost=3". Unlisted factors
+// have no weight.
+func parseScaleDownWeights(value string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		factor, weightStr, found := strings.Cut(entry, "=")
+		factor = strings.TrimSpace(factor)
+		if !found || factor == "" {
+			return nil, fmt.Errorf("entry %q must be in the format factor=weight", entry)
+		}
+		switch factor {
+		case ScaleDownFactorAge, ScaleDownFactorZone, ScaleDownFactorSnapshots, ScaleDownFactorCost:
+		default:
+			return nil, fmt.Errorf("unknown factor %q, expected %s, %s, %s or %s", factor, ScaleDownFactorAge, ScaleDownFactorZone, ScaleDownFactorSnapshots, ScaleDownFactorCost)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(weightStr), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight for %s: %v", factor, err)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("weight for %s cannot be negative", factor)
+		}
+		weights[factor] = weight
+	}
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("no factor weighted")
+	}
+	return weights, nil
+}
+
+// sortByKeepValue orders runners by the weighted value of keeping them, the lowest first, so scale-down removes the
+// least valuable capacity first. Each factor is scaled to between 0 and 1 relative to the other runners, and runners
+// whose node is unknown are valued highest as scale-down skips them anyway.
+func sortByKeepValue(cfg *Config, state *ClusterState, runners []daytona.RunnerFull) {
+	zoneNodes := make(map[string]int)
+	maxZoneNodes := 0
+	for _, node := range state.Nodes {
+		zone := node.Labels[ZoneLabel]
+		zoneNodes[zone]++
+		maxZoneNodes = max(maxZoneNodes, zoneNodes[zone])
+	}
+
+	var maxAge time.Duration
+	var maxSnapshots float32
+	var maxPrice float64
+	for _, runner := range runners {
+		if snapshots := runner.GetCurrentSnapshotCount(); snapshots > maxSnapshots {
+			maxSnapshots = snapshots
+		}
+		node, found := state.NodeByIP[runner.GetDomain()]
+		if !found {
+			continue
+		}
+		if age := time.Since(node.CreationTimestamp.Time); age > maxAge {
+			maxAge = age
+		}
+		if price, found := nodeHourlyPrice(cfg, node); found && price > maxPrice {
+			maxPrice = price
+		}
+	}
+
+	var maxValue float64
+	for _, weight := range cfg.ScaleDownWeights {
+		maxValue += weight
+	}
+
+	values := make(map[string]float64, len(runners))
+	for _, runner := range runners {
+		node, found := state.NodeByIP[runner.GetDomain()]
+		if !found {
+			values[runner.GetId()] = maxValue
+			continue
+		}
+
+		factors := map[string]float64{ScaleDownFactorAge: 1, ScaleDownFactorZone: 1, ScaleDownFactorSnapshots: 0, ScaleDownFactorCost: 1}
+		if maxAge > 0 {
+			factors[ScaleDownFactorAge] = 1 - float64(time.Since(node.CreationTimestamp.Time))/float64(maxAge)
+		}
+		if maxZoneNodes > 0 {
+			factors[ScaleDownFactorZone] = 1 - float64(zoneNodes[node.Labels[ZoneLabel]])/float64(maxZoneNodes)
+		}
+		if maxSnapshots > 0 {
+			factors[ScaleDownFactorSnapshots] = float64(runner.GetCurrentSnapshotCount() / maxSnapshots)
+		}
+		// Nodes without a known price are kept longest, as with the cost ordering
+		if price, found := nodeHourlyPrice(cfg, node); found && maxPrice > 0 {
+			factors[ScaleDownFactorCost] = 1 - price/maxPrice
+		}
+
+		var value float64
+		for factor, weight := range cfg.ScaleDownWeights {
+			value += weight * factors[factor]
+		}
+		values[runner.GetId()] = value
+	}
+
+	sort.SliceStable(runners, func(i, j int) bool { return values[runners[i].GetId()] < values[runners[j].GetId()] })
+}
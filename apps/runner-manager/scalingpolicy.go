@@ -0,0 +1,183 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/daytonaio/daytona/apps/runner-manager/pkg/policy"
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	"github.com/hashicorp/go-plugin"
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultScalingPolicyTimeout bounds a single policy evaluation, by a plugin or an external evaluator
+const DefaultScalingPolicyTimeout = 10 * time.Second
+
+// loadScalingPolicy starts the configured custom scaling policy, returning nil when the built-in policy is used
+func loadScalingPolicy(cfg *Config) (policy.IScalingPolicy, func(), error) {
+	switch {
+	case cfg.ScalingPolicyPluginPath != "":
+		client := plugin.NewClient(&plugin.ClientConfig{
+			HandshakeConfig: policy.HandshakeConfig,
+			Plugins: map[string]plugin.Plugin{
+				policy.PluginName: &policy.ScalingPolicyPlugin{Timeout: cfg.ScalingPolicyTimeout},
+			},
+			Cmd: exec.Command(cfg.ScalingPolicyPluginPath),
+		})
+
+		rpcClient, err := client.Client()
+		if err != nil {
+			client.Kill()
+			return nil, nil, fmt.Errorf("failed to start scaling policy plugin: %w", err)
+		}
+
+		raw, err := rpcClient.Dispense(policy.PluginName)
+		if err != nil {
+			client.Kill()
+			return nil, nil, fmt.Errorf("failed to dispense scaling policy plugin: %w", err)
+		}
+
+		scalingPolicy, ok := raw.(policy.IScalingPolicy)
+		if !ok {
+			client.Kill()
+			return nil, nil, fmt.Errorf("unexpected scaling policy plugin type %T", raw)
+		}
+
+		log.Infof("Using scaling policy plugin %s", cfg.ScalingPolicyPluginPath)
+		return scalingPolicy, client.Kill, nil
+	case cfg.ScalingPolicyGRPCAddress != "":
+		evaluator, err := policy.NewGRPCEvaluator(cfg.ScalingPolicyGRPCAddress, cfg.ScalingPolicyTimeout)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to scaling policy evaluator: %w", err)
+		}
+
+		log.Infof("Using external scaling policy evaluator at %s", cfg.ScalingPolicyGRPCAddress)
+		return evaluator, func() { evaluator.Close() }, nil
+	}
+
+	return nil, func() {}, nil
+}
+
+// buildPolicyInput converts the cluster state into the snapshot handed to custom policies
+func buildPolicyInput(cfg *Config, state *ClusterState, metrics *ResourceMetrics) *policy.Input {
+	input := &policy.Input{
+		RegionID:            cfg.RegionID,
+		Runners:             []policy.Runner{},
+		Nodes:               []policy.Node{},
+		PendingPlaceholders: len(state.PendingPlaceholders),
+		Metrics: policy.Metrics{
+			TotalCPUCapacity:        metrics.TotalCPUCapacity,
+			TotalMemoryGiBCapacity:  metrics.TotalMemoryGiBCapacity,
+			TotalAllocatedCPU:       metrics.TotalAllocatedCPU,
+			TotalAllocatedMemoryGiB: metrics.TotalAllocatedMemoryGiB,
+			TotalAvailableCPU:       metrics.TotalAvailableCPU,
+			TotalAvailableMemoryGiB: metrics.TotalAvailableMemoryGiB,
+			AvgCpuPerNode:           metrics.AvgCpuPerNode,
+			AvgMemPerNode:           metrics.AvgMemPerNode,
+			TotalGPUCapacity:        metrics.TotalGPUCapacity,
+			TotalAvailableGPU:       metrics.TotalAvailableGPU,
+			AvgGpuPerNode:           metrics.AvgGpuPerNode,
+			TotalDiskGiBCapacity:    metrics.TotalDiskGiBCapacity,
+			TotalAvailableDiskGiB:   metrics.TotalAvailableDiskGiB,
+			AvgDiskPerNode:          metrics.AvgDiskPerNode,
+		},
+		Config: policy.ConfigLimits{
+			MaxResourceUtilizationPercent: cfg.MaxResourceUtilizationPercent,
+			MinIdleRunners:                cfg.MinIdleRunners,
+			MinIdleCpu:                    cfg.MinIdleCpu,
+			MinIdleMemory:                 cfg.MinIdleMemory,
+			MinIdleGpu:                    cfg.MinIdleGpu,
+			MinIdleDisk:                   cfg.MinIdleDisk,
+			MaxDiskUtilizationPercent:     cfg.MaxDiskUtilizationPercent,
+			ScaleDownUtilizationPercent:   cfg.ScaleDownUtilizationPercent,
+		},
+	}
+
+	addRunners := func(runners []daytona.RunnerFull, category policy.RunnerCategory) {
+		for _, runner := range runners {
+			policyRunner := policy.Runner{
+				ID:                 runner.GetId(),
+				Domain:             runner.GetDomain(),
+				Category:           category,
+				Cpu:                runner.GetCpu(),
+				MemoryGiB:          runner.GetMemory(),
+				AllocatedCpu:       runner.GetCurrentAllocatedCpu(),
+				AllocatedMemoryGiB: runner.GetCurrentAllocatedMemoryGiB(),
+				DiskGiB:            runner.GetDisk(),
+				AllocatedDiskGiB:   runner.GetCurrentAllocatedDiskGiB(),
+				StartedSandboxes:   runner.GetCurrentStartedSandboxes(),
+			}
+			if node, found := state.NodeByIP[runner.GetDomain()]; found {
+				policyRunner.NodeName = node.Name
+			}
+			input.Runners = append(input.Runners, policyRunner)
+		}
+	}
+	addRunners(state.ActiveRunners, policy.RunnerCategoryActive)
+	addRunners(state.IdleRunners, policy.RunnerCategoryIdle)
+	addRunners(state.DeletableRunners, policy.RunnerCategoryDeletable)
+
+	nascent := make(map[string]bool)
+	for _, node := range state.NascentNodes {
+		nascent[node.Name] = true
+	}
+	for i := range state.Nodes {
+		node := &state.Nodes[i]
+		nodeCpu, nodeMem, _ := getNodeAllocatableResources(node)
+		input.Nodes = append(input.Nodes, policy.Node{
+			Name:                 node.Name,
+			Labels:               node.Labels,
+			Unschedulable:        node.Spec.Unschedulable,
+			Nascent:              nascent[node.Name],
+			AllocatableCpu:       nodeCpu,
+			AllocatableMemoryGiB: nodeMem,
+		})
+	}
+
+	return input
+}
+
+// applyPolicyDecision carries out a custom policy's node delta
+func applyPolicyDecision(backend clusterBackend, apiClient *daytona.APIClient, hibernator nodeHibernator, cfg *Config, state *ClusterState, metrics *ResourceMetrics, decision *policy.Decision, cooldown *scaleCooldown) {
+	log.WithFields(log.Fields{"nodeDelta": decision.NodeDelta, "reason": decision.Reason}).Infof("Scaling policy decided a node delta of %d: %s", decision.NodeDelta, decision.Reason)
+
+	switch {
+	case decision.NodeDelta > 0 && cooldown.scaleUpRemaining() > 0:
+		log.Infof("In scale-up cooldown for another %s, skipping the scaling policy's scale-up.", cooldown.scaleUpRemaining().Round(time.Second))
+	case decision.NodeDelta < 0 && cooldown.scaleDownRemaining() > 0:
+		log.Infof("In scale-down cooldown for another %s, skipping the scaling policy's scale-down.", cooldown.scaleDownRemaining().Round(time.Second))
+	case decision.NodeDelta > 0:
+		cooldown.recordScaleUp()
+		// The delta is not trusted: it is clamped to MAX_NODES minus the nodes and the in-flight placeholders
+		nodesToCreate := decision.NodeDelta
+		if headroom := cfg.MaxNodes - len(state.Nodes) - len(state.PendingPlaceholders) - state.PlaceholdersCreated; nodesToCreate > headroom {
+			nodesToCreate = max(headroom, 0)
+			log.Warnf("Scaling policy's node delta of %d clamped to %d by MAX_NODES (%d).", decision.NodeDelta, nodesToCreate, cfg.MaxNodes)
+		}
+		if hibernator != nil && len(state.HibernatedNodes) > 0 {
+			nodesToCreate -= resumeHibernatedNodes(backend, hibernator, state, nodesToCreate)
+		}
+		if nodesToCreate > 0 {
+			nodesToCreate = capScaleUp(cfg, state, nodesToCreate)
+		}
+		for i := 0; i < nodesToCreate; i++ {
+			pod, err := createPlaceholderPod(backend, cfg, PlaceholderPodLabel, placeholderOptions{CapacityType: state.CapacityType})
+			if err != nil {
+				log.Errorf("Error creating placeholder pod for scale-up: %v", err)
+				continue
+			}
+			recordPlaceholderEvent(backend, pod, corev1.EventTypeNormal, EventReasonScaleUp, "Placeholder created to add a node: scaling policy decision: "+decision.Reason)
+			state.PlaceholdersCreated++
+		}
+	case decision.NodeDelta < 0:
+		if handleScaleDown(backend, apiClient, hibernator, cfg, state, metrics, false, -decision.NodeDelta) {
+			cooldown.recordScaleDown()
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// cronLookbackDays bounds the search for the last time a schedule entry started, a year covers every expression
+const cronLookbackDays = 366
+
+// scalingScheduleEntry is an entry of SCALING_SCHEDULES, in the format
+// [{"name": "business-hours", "cron": "30 7 * * 1-5", "minIdleCpu": 64}, {"name": "night", "cron": "0 20 * * *", "minIdleCpu": 8}].
+// An entry applies from its cron expression's start times until another entry starts. Unset fields keep the
+// configured value.
+type scalingScheduleEntry struct {
+	Name           string `json:"name"`
+	Cron           string `json:"cron"`
+	MinIdleRunners *int   `json:"minIdleRunners"`
+	MinIdleCpu     *int   `json:"minIdleCpu"`
+	MinIdleMemory  *int   `json:"minIdleMemory"`
+
+	schedule *cronSchedule
+}
+
+// parseScalingSchedules parses and validates SCALING_SCHEDULES
+func parseScalingSchedules(value string) ([]scalingScheduleEntry, error) {
+	var entries []scalingScheduleEntry
+	if err := json.Unmarshal([]byte(value), &entries); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no schedule entries defined")
+	}
+
+	names := make(map[string]bool)
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Name == "" {
+			return nil, fmt.Errorf("every schedule entry needs a name")
+		}
+		if names[entry.Name] {
+			return nil, fmt.Errorf("schedule entry %q is defined more than once", entry.Name)
+		}
+		names[entry.Name] = true
+
+		schedule, err := parseCron(entry.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("schedule entry %q: invalid cron expression %q: %v", entry.Name, entry.Cron, err)
+		}
+		entry.schedule = schedule
+
+		for _, value := range []*int{entry.MinIdleRunners, entry.MinIdleCpu, entry.MinIdleMemory} {
+			if value != nil && *value < 0 {
+				return nil, fmt.Errorf("schedule entry %q has a negative idle threshold", entry.Name)
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// scalingScheduler overrides the idle thresholds with the schedule entry that started last
+type scalingScheduler struct {
+	entries  []scalingScheduleEntry
+	location *time.Location
+
+	active string
+}
+
+func newScalingScheduler(cfg *Config) *scalingScheduler {
+	return &scalingScheduler{
+		entries:  cfg.ScalingSchedules,
+		location: cfg.ScalingScheduleLocation,
+	}
+}
+
+// apply returns the configuration for the cycle's scaling decisions, with the thresholds of the active schedule entry
+func (s *scalingScheduler) apply(cfg *Config) *Config {
+	now := time.Now().In(s.location)
+
+	var active *scalingScheduleEntry
+	var activeSince time.Time
+	for i := range s.entries {
+		startedAt, found := s.entries[i].schedule.previous(now)
+		if found && startedAt.After(activeSince) {
+			active, activeSince = &s.entries[i], startedAt
+		}
+	}
+
+	activeName := ""
+	if active != nil {
+		activeName = active.Name
+	}
+	if activeName != s.active {
+		if s.active != "" {
+			scalingScheduleActive.WithLabelValues(s.active).Set(0)
+		}
+		if active != nil {
+			scalingScheduleActive.WithLabelValues(activeName).Set(1)
+			log.WithField("schedule", activeName).Infof("Schedule entry %s active since %s.", activeName, activeSince.Format(time.RFC3339))
+		}
+		s.active = activeName
+	}
+	if active == nil {
+		return cfg
+	}
+
+	scheduled := *cfg
+	if active.MinIdleRunners != nil {
+		scheduled.MinIdleRunners = *active.MinIdleRunners
+	}
+	if active.MinIdleCpu != nil {
+		scheduled.MinIdleCpu = *active.MinIdleCpu
+	}
+	if active.MinIdleMemory != nil {
+		scheduled.MinIdleMemory = *active.MinIdleMemory
+	}
+	return &scheduled
+}
+
+// cronSchedule is a standard five-field cron expression: minute, hour, day of month, month and day of week. Each
+// field is a bit set of its allowed values.
+type cronSchedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+
+	// A day matches either day field when both are restricted, as in cron
+	dayOfMonthAny, dayOfWeekAny bool
+}
+
+// parseCron parses a cron expression, supporting lists, ranges and steps
+func parseCron(expression string) (*cronSchedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+
+	schedule := &cronSchedule{
+		dayOfMonthAny: fields[2] == "*",
+		dayOfWeekAny:  fields[4] == "*",
+	}
+	for _, field := range []struct {
+		name     string
+		value    string
+		min, max int
+		target   *uint64
+	}{
+		{"minute", fields[0], 0, 59, &schedule.minute},
+		{"hour", fields[1], 0, 23, &schedule.hour},
+		{"day of month", fields[2], 1, 31, &schedule.dayOfMonth},
+		{"month", fields[3], 1, 12, &schedule.month},
+		{"day of week", fields[4], 0, 7, &schedule.dayOfWeek},
+	} {
+		bits, err := parseCronField(field.value, field.min, field.max)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", field.name, err)
+		}
+		*field.target = bits
+	}
+
+	// Sunday is both 0 and 7
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+
+	return schedule, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+		}
+
+		low, high := min, max
+		if rangePart != "*" {
+			lowStr, highStr, isRange := strings.Cut(rangePart, "-")
+			var err error
+			low, err = strconv.Atoi(lowStr)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", lowStr)
+			}
+			high = low
+			if isRange {
+				high, err = strconv.Atoi(highStr)
+				if err != nil {
+					return 0, fmt.Errorf("invalid value %q", highStr)
+				}
+			} else if hasStep {
+				// "5/15" starts at 5 and repeats until the end of the range
+				high = max
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("%q is out of the range %d-%d", rangePart, min, max)
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, nil
+}
+
+// matchesDay reports whether the schedule runs on the day
+func (c *cronSchedule) matchesDay(day time.Time) bool {
+	if c.month&(1<<int(day.Month())) == 0 {
+		return false
+	}
+	dayOfMonth := c.dayOfMonth&(1<<day.Day()) != 0
+	dayOfWeek := c.dayOfWeek&(1<<int(day.Weekday())) != 0
+	if c.dayOfMonthAny || c.dayOfWeekAny {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+// previous returns the last start time of the schedule at or before now, in the location of now
+func (c *cronSchedule) previous(now time.Time) (time.Time, bool) {
+	location := now.Location()
+	for daysBack := 0; daysBack <= cronLookbackDays; daysBack++ {
+		day := time.Date(now.Year(), now.Month(), now.Day()-daysBack, 0, 0, 0, 0, location)
+		if !c.matchesDay(day) {
+			continue
+		}
+		for hour := 23; hour >= 0; hour-- {
+			if c.hour&(1<<hour) == 0 {
+				continue
+			}
+			for minute := 59; minute >= 0; minute-- {
+				if c.minute&(1<<minute) == 0 {
+					continue
+				}
+				startedAt := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, location)
+				if !startedAt.After(now) {
+					return startedAt, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
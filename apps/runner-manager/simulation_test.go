@@ -0,0 +1,398 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/common-go/pkg/directive"
+	"github.com/daytonaio/common-go/pkg/protection"
+	"github.com/daytonaio/daytona/apps/runner-manager/internal/simulation"
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const simulatedRegionID = "region"
+
+var (
+	// allocatedRunner is a runner at 75% of its CPU and memory
+	allocatedRunner = simulation.RunnerSpec{Cpu: 16, MemoryGiB: 64, DiskGiB: 200, AllocatedCpu: 12, AllocatedMemoryGiB: 48, AllocatedDiskGiB: 100, StartedSandboxes: 4}
+	// saturatedRunner is a runner above MAX_RESOURCE_UTILIZATION_PERCENT
+	saturatedRunner = simulation.RunnerSpec{Cpu: 16, MemoryGiB: 64, DiskGiB: 200, AllocatedCpu: 15, AllocatedMemoryGiB: 60, AllocatedDiskGiB: 100, StartedSandboxes: 6}
+	idleRunner      = simulation.RunnerSpec{Cpu: 16, MemoryGiB: 64, DiskGiB: 200}
+	unhealthyRunner = simulation.RunnerSpec{Cpu: 16, MemoryGiB: 64, DiskGiB: 200, Unresponsive: true}
+	deletableRunner = simulation.RunnerSpec{Cpu: 16, MemoryGiB: 64, DiskGiB: 200, Unschedulable: true}
+	// overreportingRunner reports four times the CPU of its node
+	overreportingRunner = simulation.RunnerSpec{Cpu: 16, MemoryGiB: 64, DiskGiB: 200, ReportedCpu: 64}
+)
+
+func newSimulatedFleet() *simulation.Fleet {
+	return simulation.NewFleet(simulation.FleetOptions{
+		RegionID:        simulatedRegionID,
+		Namespace:       "runners",
+		NodeSelectorKey: DefaultNodeSelectorKey,
+		PlaceholderApp:  PlaceholderPodLabel,
+	})
+}
+
+// runSimulatedCycle runs a controller loop cycle against the fleet, after errorCycles cycles with control-plane errors
+func runSimulatedCycle(t *testing.T, fleet *simulation.Fleet, settings []string, errorCycles int) (*simulation.Cluster, *simulation.DaytonaAPI) {
+	t.Helper()
+
+	api := simulation.NewDaytonaAPI(fleet)
+	t.Cleanup(api.Close)
+	cluster := simulation.NewCluster(fleet)
+
+	args := append([]string{
+		"--api-port=0",
+		"--daytona-api-url=http://daytona",
+		"--daytona-api-key=key",
+		"--provider-namespace=" + fleet.Namespace,
+		"--region-id=" + fleet.RegionID,
+		"--max-resource-utilization-percent=90",
+		"--min-idle-cpu=0",
+		"--min-idle-memory=0",
+	}, settings...)
+	cfg, err := loadConfig(args)
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+
+	pool := newRunnerPool(cfg, &kubernetesBackend{clientset: cluster.Clientset, cfg: cfg}, nil)
+	pool.primary = true
+	if pool.breaker != nil {
+		for i := 0; i < errorCycles; i++ {
+			pool.breaker.recordError(errors.New("simulated control-plane error"))
+			pool.breaker.endCycle(cfg)
+		}
+	}
+
+	c := newController(pool, api.Client(), newNodeReportStore(), newDrainStore(), newTrafficStore(), newTunnelStore(), directive.NewStore(nil), nil, nil, nil, nil, nil, nil)
+	if c.runCycle(context.Background()) == nil {
+		t.Fatal("cycle could not gather the cluster state")
+	}
+	return cluster, api
+}
+
+func TestSimulatedScaling(t *testing.T) {
+	output := log.StandardLogger().Out
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(output) })
+
+	tests := []struct {
+		name     string
+		settings []string
+		build    func(fleet *simulation.Fleet)
+		// wantPending and wantScheduled are the placeholders waiting for a node and on a node after the cycle
+		wantPending   int
+		wantScheduled int
+		// wantRestarts is the number of runner restarts requested from the Daytona API
+		wantRestarts int
+		// wantStandby and wantPromoted are the nodes kept in the warm buffer and promoted out of it after the cycle
+		wantStandby  int
+		wantPromoted int
+		// errorCycles is the number of consecutive cycles with control-plane errors before the simulated one
+		errorCycles int
+	}{
+		{
+			name:          "allocated fleet adds the missing idle runners",
+			settings:      []string{"--min-idle-runners=10"},
+			build:         func(fleet *simulation.Fleet) { fleet.AddRunners(300, allocatedRunner) },
+			wantPending:   10,
+			wantScheduled: 300,
+		},
+		{
+			name:     "pending placeholders count as in flight",
+			settings: []string{"--min-idle-runners=10"},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(300, allocatedRunner)
+				fleet.AddPendingPlaceholders(4)
+			},
+			wantPending:   10,
+			wantScheduled: 300,
+		},
+		{
+			name:     "nascent nodes fill the idle buffer",
+			settings: []string{"--min-idle-runners=10"},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(300, allocatedRunner)
+				fleet.AddNascentNodes(10, idleRunner)
+			},
+			wantScheduled: 310,
+		},
+		{
+			name:     "high utilization adds a node",
+			settings: []string{"--min-idle-runners=10"},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(500, saturatedRunner)
+				fleet.AddRunners(10, idleRunner)
+			},
+			wantPending:   1,
+			wantScheduled: 510,
+		},
+		{
+			name: "idle CPU percentage scales the buffer with the pool",
+			// 30% of 4960 CPUs needs 1488 idle CPUs, 128 more than the 1360 idle ones
+			settings: []string{"--min-idle-runners=10", "--min-idle-cpu-percent=30"},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(300, allocatedRunner)
+				fleet.AddRunners(10, idleRunner)
+			},
+			wantPending:   8,
+			wantScheduled: 310,
+		},
+		{
+			name: "unused reserved capacity is kept idle",
+			// 64 of the 192 reserved CPUs are used by the organization's started sandboxes, the other 128 are added to the
+			// 1300 idle CPUs required, 68 more than the 1360 idle ones
+			settings: []string{"--min-idle-runners=10", "--min-idle-cpu=1300", "--capacity-reservations-enabled=true"},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(300, allocatedRunner)
+				fleet.AddRunners(10, idleRunner)
+				fleet.AddSandboxes(64, daytona.SANDBOXSTATE_STARTED, "", nil)
+				fleet.RegionProperties = map[string]any{
+					RegionCapacityReservationsProperty: []map[string]any{{"organizationId": "organization", "cpu": 192, "memoryGiB": 64}},
+				}
+			},
+			wantPending:   5,
+			wantScheduled: 310,
+		},
+		{
+			name: "reserved sandbox class adds room on new nodes",
+			// Only the 10 idle runners fit a 16-core sandbox, the 300 allocated ones have 4 free CPUs each
+			settings: []string{"--min-idle-runners=10", `--reserved-sandbox-classes=[{"name":"xlarge","cpu":16,"memoryGiB":32,"count":12}]`},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(300, allocatedRunner)
+				fleet.AddRunners(10, idleRunner)
+			},
+			wantPending:   2,
+			wantScheduled: 310,
+		},
+		{
+			name:     "unhealthy runners are cordoned and replaced",
+			settings: []string{"--min-idle-runners=10", "--health-remediation-unhealthy-cycles=1"},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(300, allocatedRunner)
+				fleet.AddRunners(8, idleRunner)
+				fleet.AddRunners(2, unhealthyRunner)
+			},
+			wantPending:   2,
+			wantScheduled: 310,
+			wantRestarts:  2,
+		},
+		{
+			name: "drifting runner capacity is counted from the node",
+			// The 10 idle runners report 640 CPUs, their nodes allocate 160, leaving 1360 idle CPUs of the 1400 required
+			settings: []string{"--min-idle-runners=10", "--min-idle-cpu=1400", "--capacity-drift-source=node"},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(300, allocatedRunner)
+				fleet.AddRunners(10, overreportingRunner)
+			},
+			wantPending:   3,
+			wantScheduled: 310,
+		},
+		{
+			name:          "degraded loop adds a single node",
+			settings:      []string{"--min-idle-runners=10", "--circuit-breaker-error-cycles=3"},
+			build:         func(fleet *simulation.Fleet) { fleet.AddRunners(300, allocatedRunner) },
+			errorCycles:   3,
+			wantPending:   1,
+			wantScheduled: 300,
+		},
+		{
+			name:          "degraded loop removes no runners",
+			settings:      []string{"--min-idle-runners=0", "--circuit-breaker-error-cycles=3"},
+			build:         func(fleet *simulation.Fleet) { fleet.AddRunners(5, deletableRunner) },
+			errorCycles:   3,
+			wantScheduled: 5,
+		},
+		{
+			name:          "grace period schedules nodes for removal first",
+			settings:      []string{"--min-idle-runners=0", "--scale-down-grace-period=10m"},
+			build:         func(fleet *simulation.Fleet) { fleet.AddRunners(5, deletableRunner) },
+			wantScheduled: 5,
+		},
+		{
+			name:     "nodes past their grace period are removed",
+			settings: []string{"--min-idle-runners=0", "--scale-down-grace-period=10m"},
+			build: func(fleet *simulation.Fleet) {
+				scheduled := fleet.AddRunners(5, deletableRunner)
+				fleet.AnnotateRunnerNodes(scheduled[:3], map[string]string{
+					ScheduledForRemovalAtAnnotation: time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+				})
+			},
+			wantScheduled: 2,
+		},
+		{
+			name:          "warm buffer adds standby nodes",
+			settings:      []string{"--min-idle-runners=10", "--warm-standby-nodes=3"},
+			build:         func(fleet *simulation.Fleet) { fleet.AddRunners(10, idleRunner) },
+			wantPending:   3,
+			wantScheduled: 10,
+		},
+		{
+			name:     "scale-up promotes warm standby runners",
+			settings: []string{"--min-idle-runners=10", "--warm-standby-nodes=2"},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(300, allocatedRunner)
+				fleet.AddRunners(8, idleRunner)
+				standby := fleet.AddRunners(2, deletableRunner)
+				fleet.AnnotateRunnerNodes(standby, map[string]string{WarmStandbyAnnotation: WarmStandbyActive})
+			},
+			wantScheduled: 310,
+			wantPromoted:  2,
+		},
+		{
+			name:     "pending placeholders are removed once not needed",
+			settings: []string{"--min-idle-runners=10"},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(300, allocatedRunner)
+				fleet.AddRunners(10, idleRunner)
+				fleet.AddPendingPlaceholders(5)
+			},
+			wantScheduled: 310,
+		},
+		{
+			name:     "blackout window keeps unschedulable idle runners",
+			settings: []string{"--min-idle-runners=10", `--scale-down-blackout-windows=[{"name": "always", "cron": "* * * * *", "duration": "1h"}]`},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(250, allocatedRunner)
+				fleet.AddRunners(20, idleRunner)
+				fleet.AddRunners(30, deletableRunner)
+			},
+			wantScheduled: 300,
+		},
+		{
+			name:     "unschedulable idle runners are removed",
+			settings: []string{"--min-idle-runners=10"},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(250, allocatedRunner)
+				fleet.AddRunners(20, idleRunner)
+				fleet.AddRunners(30, deletableRunner)
+			},
+			wantScheduled: 270,
+		},
+		{
+			name: "idle CPU floor keeps unschedulable runners",
+			// 250 allocated runners with 4 free CPUs, 20 idle ones and the nodes of the unschedulable ones, counted by their
+			// allocatable CPU, leave 1800 idle CPUs
+			settings: []string{"--min-idle-runners=10", "--min-idle-cpu=1790"},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(250, allocatedRunner)
+				fleet.AddRunners(20, idleRunner)
+				fleet.AddRunners(30, deletableRunner)
+			},
+			wantScheduled: 300,
+		},
+		{
+			name: "utilization low watermark limits removals",
+			// 3000 allocated CPUs of 4800 stay below 68% with up to 24 nodes removed
+			settings: []string{"--min-idle-runners=10", "--scale-down-utilization-percent=68"},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(250, allocatedRunner)
+				fleet.AddRunners(20, idleRunner)
+				fleet.AddRunners(30, deletableRunner)
+			},
+			wantScheduled: 276,
+		},
+		{
+			name:     "do-not-disturb sandboxes keep their runners",
+			settings: []string{"--min-idle-runners=10"},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(250, allocatedRunner)
+				fleet.AddRunners(20, idleRunner)
+				deletable := fleet.AddRunners(30, deletableRunner)
+				for _, runnerID := range deletable[:5] {
+					fleet.AddSandboxes(1, daytona.SANDBOXSTATE_STOPPED, runnerID, map[string]string{protection.DoNotDisturbKey: "true"})
+				}
+			},
+			wantScheduled: 275,
+		},
+		{
+			name:     "nodes in maintenance are kept",
+			settings: []string{"--min-idle-runners=10"},
+			build: func(fleet *simulation.Fleet) {
+				fleet.AddRunners(250, allocatedRunner)
+				fleet.AddRunners(20, idleRunner)
+				deletable := fleet.AddRunners(30, deletableRunner)
+				fleet.AnnotateRunnerNodes(deletable[:8], map[string]string{MaintenanceAnnotation: "kernel upgrade"})
+			},
+			wantScheduled: 278,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fleet := newSimulatedFleet()
+			tt.build(fleet)
+
+			cluster, api := runSimulatedCycle(t, fleet, tt.settings, tt.errorCycles)
+
+			pending, scheduled, err := cluster.Placeholders(context.Background())
+			if err != nil {
+				t.Fatalf("listing placeholders: %v", err)
+			}
+			if len(pending) != tt.wantPending {
+				t.Errorf("got %d pending placeholders, want %d", len(pending), tt.wantPending)
+			}
+			if len(scheduled) != tt.wantScheduled {
+				t.Errorf("got %d scheduled placeholders, want %d", len(scheduled), tt.wantScheduled)
+			}
+
+			if len(api.Restarts) != tt.wantRestarts {
+				t.Errorf("got %d runner restarts, want %d", len(api.Restarts), tt.wantRestarts)
+			}
+
+			// A node may only lose its placeholder once its runner was confirmed unschedulable, and only be promoted out of
+			// the warm buffer once its runner was made schedulable
+			kept := make(map[string]bool, len(scheduled))
+			for _, pod := range scheduled {
+				kept[pod.Spec.NodeName] = true
+			}
+			runnerByDomain := make(map[string]string, len(fleet.Runners))
+			for _, runner := range fleet.Runners {
+				runnerByDomain[runner.GetDomain()] = runner.GetId()
+			}
+			standby, promoted := 0, 0
+			for _, node := range fleet.Nodes {
+				runnerID, hasRunner := runnerByDomain[node.Status.Addresses[0].Address]
+				current, err := cluster.Node(context.Background(), node.Name)
+				if err != nil {
+					t.Fatalf("getting node %s: %v", node.Name, err)
+				}
+				switch current.Annotations[WarmStandbyAnnotation] {
+				case WarmStandbyActive:
+					standby++
+				case WarmStandbyPromoted:
+					promoted++
+					if runner, found := api.Runner(runnerID); !found || runner.GetUnschedulable() {
+						t.Errorf("node %s was promoted without making runner %s schedulable", node.Name, runnerID)
+					}
+				}
+
+				if kept[node.Name] || !hasRunner {
+					continue
+				}
+				if !api.SchedulingUpdates[runnerID] {
+					t.Errorf("node %s was removed without making runner %s unschedulable", node.Name, runnerID)
+				}
+			}
+			if standby != tt.wantStandby {
+				t.Errorf("got %d warm standby nodes, want %d", standby, tt.wantStandby)
+			}
+			if promoted != tt.wantPromoted {
+				t.Errorf("got %d promoted warm standby nodes, want %d", promoted, tt.wantPromoted)
+			}
+
+			if len(api.Unhandled) > 0 {
+				t.Errorf("cycle called endpoints the simulated API does not serve: %v", api.Unhandled)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	CapacityTypeSpot     = "spot"
+	CapacityTypeOnDemand = "on-demand"
+
+	// PlaceholderCapacityTypeLabel marks placeholder pods with the capacity type of the node they request
+	PlaceholderCapacityTypeLabel = "daytona.io/capacity-type"
+
+	// SpotInterruptedAtAnnotation records when the interruption notice of a spot node was handled
+	SpotInterruptedAtAnnotation = "daytona.io/spot-interrupted-at"
+
+	// DefaultSpotPendingTimeout is how long a spot placeholder may stay pending before spot capacity is considered
+	// unavailable
+	DefaultSpotPendingTimeout = 5 * time.Minute
+
+	// DefaultSpotRetryInterval is how long new placeholders request on-demand nodes after a fallback
+	DefaultSpotRetryInterval = 30 * time.Minute
+)
+
+// defaultSpotInterruptionTaints are the taints set on spot nodes about to be reclaimed by the AWS node termination
+// handler and by GKE
+var defaultSpotInterruptionTaints = []string{
+	"aws-node-termination-handler/spot-itn",
+	"cloud.google.com/impending-node-termination",
+}
+
+// parseNodeSelector parses a node selector in the format "key=value,key2=value2"
+func parseNodeSelector(value string) (map[string]string, error) {
+	selector := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, labelValue, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid label %q, expected key=value", pair)
+		}
+		selector[strings.TrimSpace(key)] = strings.TrimSpace(labelValue)
+	}
+	if len(selector) == 0 {
+		return nil, fmt.Errorf("no labels")
+	}
+	return selector, nil
+}
+
+// selectPlaceholderCapacity labels the placeholder with its capacity type and restricts it to nodes of that type
+func selectPlaceholderCapacity(pod *corev1.Pod, cfg *Config, capacityType string) {
+	selector := cfg.SpotNodeSelector
+	if capacityType == CapacityTypeOnDemand {
+		selector = cfg.OnDemandNodeSelector
+	}
+	if capacityType == "" || len(selector) == 0 {
+		return
+	}
+
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[PlaceholderCapacityTypeLabel] = capacityType
+	if pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = map[string]string{}
+	}
+	for key, value := range selector {
+		pod.Spec.NodeSelector[key] = value
+	}
+}
+
+// spotFallback scales the pool on spot nodes first. When a spot placeholder stays pending past the timeout, spot
+// capacity is considered unavailable: the placeholder is replaced by an on-demand one and new placeholders request
+// on-demand nodes until the retry interval passes.
+type spotFallback struct {
+	pendingTimeout     time.Duration
+	retryInterval      time.Duration
+	interruptionTaints []string
+
+	onDemandUntil time.Time
+}
+
+func newSpotFallback(cfg *Config) *spotFallback {
+	return &spotFallback{
+		pendingTimeout:     cfg.SpotPendingTimeout,
+		retryInterval:      cfg.SpotRetryInterval,
+		interruptionTaints: cfg.SpotInterruptionTaints,
+	}
+}
+
+// capacityType returns the capacity type new placeholders request
+func (f *spotFallback) capacityType() string {
+	if time.Now().Before(f.onDemandUntil) {
+		return CapacityTypeOnDemand
+	}
+	return CapacityTypeSpot
+}
+
+// reconcile falls back to on-demand capacity for spot placeholders stuck pending, handles the interruption notices
+// of spot nodes and sets the capacity type of the placeholders created this cycle
+func (f *spotFallback) reconcile(backend clusterBackend, apiClient *daytona.APIClient, cfg *Config, state *ClusterState) {
+	var pending []*corev1.Pod
+	var stuck []*corev1.Pod
+	for _, pod := range state.PendingPlaceholders {
+		if pod.Labels[PlaceholderCapacityTypeLabel] == CapacityTypeSpot && time.Since(pod.CreationTimestamp.Time) > f.pendingTimeout {
+			stuck = append(stuck, pod)
+		} else {
+			pending = append(pending, pod)
+		}
+	}
+
+	if len(stuck) > 0 {
+		f.onDemandUntil = time.Now().Add(f.retryInterval)
+		spotFallbacks.Inc()
+		log.Warnf("Spot capacity unavailable: %d spot placeholder pods pending for more than %s. Falling back to on-demand nodes until %s.",
+			len(stuck), f.pendingTimeout, f.onDemandUntil.Format(time.RFC3339))
+
+		for _, pod := range stuck {
+			if err := backend.DeletePlaceholder(context.Background(), pod.Name); err != nil {
+				log.WithField("placeholder", pod.Name).Errorf("Error deleting stuck spot placeholder pod %s: %v", pod.Name, err)
+				pending = append(pending, pod)
+				continue
+			}
+			replacement, err := createPlaceholderPod(backend, cfg, PlaceholderPodLabel, placeholderOptions{
+				Zone:         pod.Labels[PlaceholderZoneLabel],
+				CapacityType: CapacityTypeOnDemand,
+			})
+			if err != nil {
+				log.WithField("placeholder", pod.Name).Errorf("Error creating on-demand replacement for spot placeholder pod %s: %v", pod.Name, err)
+				continue
+			}
+			recordPlaceholderEvent(backend, replacement, corev1.EventTypeNormal, EventReasonScaleUp,
+				fmt.Sprintf("Placeholder created to add a node: replaces spot placeholder pod %s pending for more than %s", pod.Name, f.pendingTimeout))
+			pending = append(pending, replacement)
+		}
+		state.PendingPlaceholders = pending
+	}
+
+	state.CapacityType = f.capacityType()
+	f.handleInterruptions(backend, apiClient, state)
+}
+
+// handleInterruptions takes the runners of spot nodes about to be reclaimed out of the pool's capacity: the node is
+// cordoned and its runner made unschedulable, so this cycle's scale-up provisions a replacement before the node is gone
+func (f *spotFallback) handleInterruptions(backend clusterBackend, apiClient *daytona.APIClient, state *ClusterState) {
+	for i := range state.Nodes {
+		node := &state.Nodes[i]
+		taint := f.interruptionTaint(node)
+		if taint == "" {
+			continue
+		}
+
+		nodeLog := log.WithFields(log.Fields{"node": node.Name, "taint": taint})
+		if _, handled := node.Annotations[SpotInterruptedAtAnnotation]; !handled {
+			spotInterruptions.Inc()
+			nodeLog.Warnf("Spot node %s received an interruption notice. Replacing its capacity.", node.Name)
+
+			now := time.Now().UTC().Format(time.RFC3339)
+			unschedulable := true
+			if err := backend.PatchNode(context.Background(), node.Name, map[string]*string{SpotInterruptedAtAnnotation: &now}, &unschedulable); err != nil {
+				nodeLog.Errorf("Error cordoning interrupted spot node %s: %v", node.Name, err)
+			}
+		}
+		node.Spec.Unschedulable = true
+
+		for domain, runner := range state.RunnerByDomain {
+			if state.NodeByIP[domain] != node || runner.GetUnschedulable() {
+				continue
+			}
+			if err := updateRunnerScheduling(apiClient, runner.GetId(), true); err != nil {
+				nodeLog.Errorf("Error marking runner %s on interrupted spot node %s unschedulable: %v", runner.GetId(), node.Name, err)
+			}
+			markRunnerUnschedulable(state, runner.GetId())
+		}
+	}
+}
+
+// interruptionTaint returns the interruption taint of the node, empty if it has none
+func (f *spotFallback) interruptionTaint(node *corev1.Node) string {
+	for _, taint := range node.Spec.Taints {
+		for _, interruptionTaint := range f.interruptionTaints {
+			if taint.Key == interruptionTaint {
+				return taint.Key
+			}
+		}
+	}
+	return ""
+}
+
+// markRunnerUnschedulable applies a scheduling change made this cycle to the state, moving an idle runner to the
+// deletable ones
+func markRunnerUnschedulable(state *ClusterState, runnerID string) {
+	for i := range state.Runners {
+		if state.Runners[i].GetId() == runnerID {
+			state.Runners[i].SetUnschedulable(true)
+		}
+	}
+	for i := range state.ActiveRunners {
+		if state.ActiveRunners[i].GetId() == runnerID {
+			state.ActiveRunners[i].SetUnschedulable(true)
+		}
+	}
+
+	idle := state.IdleRunners[:0]
+	for _, runner := range state.IdleRunners {
+		if runner.GetId() == runnerID {
+			runner.SetUnschedulable(true)
+			state.DeletableRunners = append(state.DeletableRunners, runner)
+			continue
+		}
+		idle = append(idle, runner)
+	}
+	state.IdleRunners = idle
+
+	for domain, runner := range state.RunnerByDomain {
+		if runner.GetId() == runnerID {
+			runner.SetUnschedulable(true)
+			state.RunnerByDomain[domain] = runner
+		}
+	}
+}
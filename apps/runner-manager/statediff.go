@@ -0,0 +1,113 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// runnerClassification returns the scale-down category a runner was sorted into by gatherClusterState
+func runnerClassification(state *ClusterState) map[string]string {
+	classes := make(map[string]string, len(state.Runners))
+	for _, runner := range state.ActiveRunners {
+		classes[runner.GetId()] = "active"
+	}
+	for _, runner := range state.IdleRunners {
+		classes[runner.GetId()] = "idle"
+	}
+	for _, runner := range state.DeletableRunners {
+		classes[runner.GetId()] = "deletable"
+	}
+	for id := range state.UnreachableRunnerIDs {
+		classes[id] = "unreachable"
+	}
+	return classes
+}
+
+// diffClusterState describes what changed between two consecutive cycles, one line per change
+func diffClusterState(previous, current *ClusterState) []string {
+	var changes []string
+
+	previousClasses := runnerClassification(previous)
+	currentClasses := runnerClassification(current)
+	runnerNames := make(map[string]string, len(current.Runners))
+	for _, runner := range previous.Runners {
+		runnerNames[runner.GetId()] = runner.GetDomain()
+	}
+	for _, runner := range current.Runners {
+		runnerNames[runner.GetId()] = runner.GetDomain()
+	}
+
+	for id, class := range currentClasses {
+		previousClass, found := previousClasses[id]
+		switch {
+		case !found:
+			changes = append(changes, fmt.Sprintf("Runner %s (%s) added as %s.", id, runnerNames[id], class))
+			stateChanges.WithLabelValues("runner_added").Inc()
+		case previousClass != class:
+			changes = append(changes, fmt.Sprintf("Runner %s (%s) changed from %s to %s.", id, runnerNames[id], previousClass, class))
+			stateChanges.WithLabelValues("runner_reclassified").Inc()
+		}
+	}
+	for id := range previousClasses {
+		if _, found := currentClasses[id]; !found {
+			changes = append(changes, fmt.Sprintf("Runner %s (%s) removed.", id, runnerNames[id]))
+			stateChanges.WithLabelValues("runner_removed").Inc()
+		}
+	}
+
+	previousNodes := make(map[string]bool, len(previous.Nodes))
+	for _, node := range previous.Nodes {
+		previousNodes[node.Name] = true
+	}
+	currentNodes := make(map[string]bool, len(current.Nodes))
+	for _, node := range current.Nodes {
+		currentNodes[node.Name] = true
+		if !previousNodes[node.Name] {
+			changes = append(changes, fmt.Sprintf("Node %s joined the pool.", node.Name))
+			stateChanges.WithLabelValues("node_added").Inc()
+		}
+	}
+	for name := range previousNodes {
+		if !currentNodes[name] {
+			changes = append(changes, fmt.Sprintf("Node %s left the pool.", name))
+			stateChanges.WithLabelValues("node_removed").Inc()
+		}
+	}
+
+	if len(previous.PendingPlaceholders) != len(current.PendingPlaceholders) || len(previous.ScheduledPlaceholders) != len(current.ScheduledPlaceholders) {
+		changes = append(changes, fmt.Sprintf("Placeholders changed from %d pending, %d scheduled to %d pending, %d scheduled.",
+			len(previous.PendingPlaceholders), len(previous.ScheduledPlaceholders),
+			len(current.PendingPlaceholders), len(current.ScheduledPlaceholders)))
+	}
+
+	sort.Strings(changes)
+	return changes
+}
+
+// logClusterStateChanges logs the full state on the first cycle and at debug level, otherwise only what changed
+// since the previous cycle
+func logClusterStateChanges(previous, current *ClusterState, metrics *ResourceMetrics) {
+	if previous == nil || log.IsLevelEnabled(log.DebugLevel) {
+		logClusterState(current, metrics)
+	}
+	if previous == nil {
+		return
+	}
+
+	changes := diffClusterState(previous, current)
+	for _, change := range changes {
+		log.Info(change)
+	}
+
+	// The summary is only repeated at info level when something changed
+	if len(changes) > 0 && !log.IsLevelEnabled(log.DebugLevel) {
+		log.Infof("Current state: DaytonaRunners: %d (Active: %d, Idle: %d, Deletable: %d). Nodes in pool: %d. Available: CPU=%.2f, Mem=%.2fGiB.",
+			len(current.Runners), len(current.ActiveRunners), len(current.IdleRunners), len(current.DeletableRunners),
+			len(current.Nodes), metrics.TotalAvailableCPU, metrics.TotalAvailableMemoryGiB)
+	}
+}
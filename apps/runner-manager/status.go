@@ -0,0 +1,235 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/daytona/apps/runner-manager/pkg/status"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ProvisioningLatencySamples is the number of recent provisioning latencies kept for the status summary
+const ProvisioningLatencySamples = 50
+
+// statusStore keeps the snapshot of the latest controller cycle and recent provisioning latencies
+type statusStore struct {
+	mu        sync.Mutex
+	latest    *status.Status
+	latencies []time.Duration
+	// state is the cluster state and resource metrics of the latest cycle, encoded when recorded since the cycle's
+	// scaling decisions go on to modify them
+	state []byte
+	// decision is the last scaling action taken by the controller loop
+	decision   string
+	decisionAt time.Time
+	// explanation is why the latest completed cycle scaled as it did
+	explanation *status.Explanation
+	// completed holds the placeholder pods whose runner has registered, so each is only measured once
+	completed map[string]bool
+}
+
+func newStatusStore() *statusStore {
+	return &statusStore{
+		completed: make(map[string]bool),
+	}
+}
+
+// update records the snapshot of a controller cycle
+func (s *statusStore) update(regionID, poolName string, state *ClusterState, metrics *ResourceMetrics) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	snapshot := &status.Status{
+		RegionID:  regionID,
+		Pool:      poolName,
+		Timestamp: now,
+		Capacity: status.Capacity{
+			TotalCPU:           metrics.TotalCPUCapacity,
+			TotalMemoryGiB:     metrics.TotalMemoryGiBCapacity,
+			AllocatedCPU:       metrics.TotalAllocatedCPU,
+			AllocatedMemoryGiB: metrics.TotalAllocatedMemoryGiB,
+			AvailableCPU:       metrics.TotalAvailableCPU,
+			AvailableMemoryGiB: metrics.TotalAvailableMemoryGiB,
+			TotalGPU:           metrics.TotalGPUCapacity,
+			AllocatedGPU:       metrics.TotalAllocatedGPU,
+			AvailableGPU:       metrics.TotalAvailableGPU,
+			TotalDiskGiB:       metrics.TotalDiskGiBCapacity,
+			AllocatedDiskGiB:   metrics.TotalAllocatedDiskGiB,
+			AvailableDiskGiB:   metrics.TotalAvailableDiskGiB,
+		},
+		Runners: status.RunnerCounts{
+			Total:      len(state.Runners),
+			Active:     len(state.ActiveRunners),
+			Idle:       len(state.IdleRunners),
+			Deletable:  len(state.DeletableRunners),
+			Prewarming: len(state.PrewarmingRunnerIDs),
+		},
+		Nodes:    len(state.Nodes),
+		InFlight: []status.ScaleOperation{},
+	}
+
+	for _, pod := range state.PendingPlaceholders {
+		snapshot.InFlight = append(snapshot.InFlight, status.ScaleOperation{
+			Kind:      status.ScaleOperationPendingNode,
+			Name:      pod.Name,
+			StartedAt: pod.CreationTimestamp.Time,
+		})
+	}
+
+	nascent := make(map[string]bool)
+	for _, node := range state.NascentNodes {
+		nascent[node.Name] = true
+	}
+
+	current := make(map[string]bool)
+	for _, pod := range state.ScheduledPlaceholders {
+		current[pod.Name] = true
+		if nascent[pod.Spec.NodeName] {
+			snapshot.InFlight = append(snapshot.InFlight, status.ScaleOperation{
+				Kind:      status.ScaleOperationNascentRunner,
+				Name:      pod.Name,
+				NodeName:  pod.Spec.NodeName,
+				StartedAt: pod.CreationTimestamp.Time,
+			})
+			continue
+		}
+		if s.completed[pod.Name] {
+			continue
+		}
+		s.completed[pod.Name] = true
+		// Placeholders that were already serving a runner when runner-manager started are not measured
+		if s.latest == nil {
+			continue
+		}
+		// Measured at cycle granularity, so the latency is an upper bound within one check interval
+		s.latencies = append(s.latencies, now.Sub(pod.CreationTimestamp.Time))
+		if len(s.latencies) > ProvisioningLatencySamples {
+			s.latencies = s.latencies[len(s.latencies)-ProvisioningLatencySamples:]
+		}
+	}
+
+	for name := range s.completed {
+		if !current[name] {
+			delete(s.completed, name)
+		}
+	}
+
+	snapshot.ProvisioningLatency = summarizeLatencies(s.latencies)
+	snapshot.Packing = state.Packing
+
+	snapshot.Traffic = []status.RunnerTraffic{}
+	for _, runner := range state.Runners {
+		if runnerTraffic, found := state.RunnerTraffic[runner.GetId()]; found {
+			snapshot.Traffic = append(snapshot.Traffic, status.RunnerTraffic{
+				RunnerID:          runner.GetId(),
+				Domain:            runner.GetDomain(),
+				BytesPerSecond:    runnerTraffic.BytesPerSecond,
+				RequestsPerSecond: runnerTraffic.RequestsPerSecond,
+				Sandboxes:         runnerTraffic.Sandboxes,
+			})
+		}
+	}
+	sort.Slice(snapshot.Traffic, func(i, j int) bool {
+		return snapshot.Traffic[i].BytesPerSecond > snapshot.Traffic[j].BytesPerSecond
+	})
+	s.latest = snapshot
+
+	encodedState, err := json.Marshal(struct {
+		Timestamp time.Time        `json:"timestamp"`
+		State     *ClusterState    `json:"state"`
+		Metrics   *ResourceMetrics `json:"metrics"`
+	}{now, state, metrics})
+	if err != nil {
+		log.Errorf("Error encoding the cluster state: %v", err)
+		return
+	}
+	s.state = encodedState
+}
+
+// get returns the latest snapshot, or nil if no controller cycle completed yet
+func (s *statusStore) get() *status.Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest
+}
+
+// getState returns the encoded cluster state of the latest cycle, or nil if no controller cycle completed yet
+func (s *statusStore) getState() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// recordDecision records a scaling action taken by the controller loop
+func (s *statusStore) recordDecision(decision string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.decision = decision
+	s.decisionAt = time.Now()
+}
+
+// lastDecision returns the last scaling action taken and when, empty if none was taken yet
+func (s *statusStore) lastDecision() (string, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.decision, s.decisionAt
+}
+
+// summarizeLatencies computes the latency percentiles of the given samples
+func summarizeLatencies(latencies []time.Duration) status.ProvisioningLatency {
+	if len(latencies) == 0 {
+		return status.ProvisioningLatency{}
+	}
+
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	percentile := func(p float64) float64 {
+		index := int(math.Ceil(p*float64(len(sorted)))) - 1
+		if index < 0 {
+			index = 0
+		}
+		return sorted[index].Seconds()
+	}
+
+	return status.ProvisioningLatency{
+		Samples:     len(sorted),
+		P50Seconds:  percentile(0.5),
+		P95Seconds:  percentile(0.95),
+		LastSeconds: latencies[len(latencies)-1].Seconds(),
+	}
+}
+
+// statusHandler serves the latest controller cycle snapshot of the selected pool
+func statusHandler(pools []*runnerPool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		pool := selectPool(w, r, pools)
+		if pool == nil {
+			return
+		}
+
+		snapshot := pool.statuses.get()
+		if snapshot == nil {
+			http.Error(w, "no controller cycle completed yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(snapshot)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultTracingServiceName is the service name of the exported spans when OTEL_SERVICE_NAME is not set
+const DefaultTracingServiceName = "runner-manager"
+
+// tracer instruments the controller loop. It delegates to the provider registered by startTracing and records nothing
+// when tracing is disabled.
+var tracer = otel.Tracer("github.com/daytonaio/daytona/apps/runner-manager")
+
+// parseTracingHeaders parses OTEL_EXPORTER_OTLP_HEADERS in the format "key1=value1,key2=value2"
+func parseTracingHeaders(value string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, headerValue, found := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("entry %q must be in the format key=value", entry)
+		}
+		headers[key] = strings.TrimSpace(headerValue)
+	}
+	return headers, nil
+}
+
+// startTracing registers a tracer provider exporting the spans of the controller loop to the OTLP/HTTP endpoint of
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT, and returns the function flushing the spans left on shutdown. Spans are batched
+// and sampled by the OpenTelemetry SDK, which also reads the standard OTEL_TRACES_SAMPLER and OTEL_BSP_* variables.
+func startTracing(cfg *Config) func(ctx context.Context) {
+	if cfg.TracingEndpoint == "" {
+		return func(context.Context) {}
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(cfg.TracingEndpoint),
+		otlptracehttp.WithHeaders(cfg.TracingHeaders),
+	)
+	if err != nil {
+		log.Errorf("Could not create the trace exporter, tracing is disabled: %v", err)
+		return func(context.Context) {}
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(cfg.TracingServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	log.Infof("Exporting controller loop traces to %s", cfg.TracingEndpoint)
+
+	return func(ctx context.Context) {
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Errorf("Error flushing traces: %v", err)
+		}
+	}
+}
+
+// endSpan records the error, if any, as the status of the span and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// traceAction runs a scale action of the controller cycle in a span recording whether it scaled the pool
+func traceAction(ctx context.Context, name string, action func() bool) bool {
+	_, span := tracer.Start(ctx, name)
+	defer span.End()
+
+	scaled := action()
+	span.SetAttributes(attribute.Bool("scaled", scaled))
+	return scaled
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/common-go/pkg/traffic"
+)
+
+// TrafficWindow is the period over which proxy traffic reports are averaged
+const TrafficWindow = 5 * time.Minute
+
+type receivedTraffic struct {
+	report     traffic.Report
+	receivedAt time.Time
+}
+
+// runnerTraffic holds the averaged preview traffic served by a runner
+type runnerTraffic struct {
+	BytesPerSecond    float64
+	RequestsPerSecond float64
+	Sandboxes         int
+}
+
+// trafficStore keeps the proxy traffic reports received within TrafficWindow
+type trafficStore struct {
+	mu      sync.Mutex
+	reports []receivedTraffic
+}
+
+func newTrafficStore() *trafficStore {
+	return &trafficStore{}
+}
+
+func (s *trafficStore) put(report traffic.Report) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reports = append(s.reports, receivedTraffic{report: report, receivedAt: time.Now()})
+}
+
+// byRunner returns the traffic averaged over TrafficWindow keyed by runner proxy URL, and drops expired reports
+func (s *trafficStore) byRunner() map[string]*runnerTraffic {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fresh := s.reports[:0]
+	for _, received := range s.reports {
+		if time.Since(received.receivedAt) <= TrafficWindow {
+			fresh = append(fresh, received)
+		}
+	}
+	s.reports = fresh
+
+	windowSeconds := TrafficWindow.Seconds()
+	sandboxesByRunner := make(map[string]map[string]bool)
+	result := make(map[string]*runnerTraffic)
+	for _, received := range s.reports {
+		for _, sandbox := range received.report.Sandboxes {
+			runner, found := result[sandbox.RunnerProxyUrl]
+			if !found {
+				runner = &runnerTraffic{}
+				result[sandbox.RunnerProxyUrl] = runner
+				sandboxesByRunner[sandbox.RunnerProxyUrl] = make(map[string]bool)
+			}
+			runner.BytesPerSecond += float64(sandbox.BytesIn+sandbox.BytesOut) / windowSeconds
+			runner.RequestsPerSecond += float64(sandbox.Requests) / windowSeconds
+			sandboxesByRunner[sandbox.RunnerProxyUrl][sandbox.SandboxId] = true
+		}
+	}
+	for runnerProxyUrl, sandboxes := range sandboxesByRunner {
+		result[runnerProxyUrl].Sandboxes = len(sandboxes)
+	}
+	return result
+}
+
+// trafficReportHandler accepts per-sandbox traffic reports posted by proxies. Reports keep nodes on scale-down, so they
+// are rejected unless TRAFFIC_REPORT_TOKEN is set.
+func trafficReportHandler(reports *trafficStore, token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var report traffic.Report
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<20)).Decode(&report); err != nil {
+			http.Error(w, "invalid report: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		reports.put(report)
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
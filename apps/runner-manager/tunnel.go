@@ -0,0 +1,89 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/common-go/pkg/tunnel"
+)
+
+// TunnelHeartbeatMaxAge is how long after its last relayed heartbeat a tunneled runner is considered reachable
+const TunnelHeartbeatMaxAge = 2 * time.Minute
+
+// tunnelStore keeps the latest relayed heartbeat per tunneled runner domain across all reporting proxies
+type tunnelStore struct {
+	mu      sync.Mutex
+	runners map[string]tunnel.RunnerTunnel
+}
+
+func newTunnelStore() *tunnelStore {
+	return &tunnelStore{
+		runners: make(map[string]tunnel.RunnerTunnel),
+	}
+}
+
+// put records the report received at receivedAt. Heartbeats are stamped on the runner-manager's clock with the age
+// they had when the report was sent, so a report can neither date a heartbeat in the future nor keep it fresh.
+func (s *tunnelStore) put(report tunnel.Report, receivedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, runner := range report.Runners {
+		var age time.Duration
+		if !report.SentAt.IsZero() && report.SentAt.After(runner.LastHeartbeat) {
+			age = report.SentAt.Sub(runner.LastHeartbeat)
+		}
+		runner.LastHeartbeat = receivedAt.Add(-age)
+		if existing, found := s.runners[runner.Domain]; found && existing.LastHeartbeat.After(runner.LastHeartbeat) {
+			continue
+		}
+		s.runners[runner.Domain] = runner
+	}
+}
+
+// byDomain returns the tunneled runners keyed by domain and forgets those not seen within tunnel.ForgetAfter
+func (s *tunnelStore) byDomain() map[string]tunnel.RunnerTunnel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[string]tunnel.RunnerTunnel, len(s.runners))
+	for domain, runner := range s.runners {
+		if time.Since(runner.LastHeartbeat) > tunnel.ForgetAfter {
+			delete(s.runners, domain)
+			continue
+		}
+		result[domain] = runner
+	}
+	return result
+}
+
+// tunnelReportHandler accepts tunnel liveness reports posted by proxies relaying runner heartbeats. Reports map runners
+// to nodes, so they are rejected unless TRAFFIC_REPORT_TOKEN is set.
+func tunnelReportHandler(tunnels *tunnelStore, token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var report tunnel.Report
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<20)).Decode(&report); err != nil {
+			http.Error(w, "invalid report: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		tunnels.put(report, time.Now())
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
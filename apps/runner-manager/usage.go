@@ -0,0 +1,185 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	promapi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// UsageSourceMetricsServer and UsageSourcePrometheus are the supported sources of actual node usage
+	UsageSourceMetricsServer = "metrics-server"
+	UsageSourcePrometheus    = "prometheus"
+
+	// DefaultUsagePrometheusCpuQuery and DefaultUsagePrometheusMemoryQuery return the CPU cores and memory bytes used
+	// by the containers of each node from cAdvisor, labeled with the node name
+	DefaultUsagePrometheusCpuQuery    = `sum by (node) (rate(container_cpu_usage_seconds_total{container!=""}[5m]))`
+	DefaultUsagePrometheusMemoryQuery = `sum by (node) (container_memory_working_set_bytes{container!=""})`
+
+	metricsServerNodesPath = "/apis/metrics.k8s.io/v1beta1/nodes"
+	usageQueryTimeout      = 10 * time.Second
+)
+
+// nodeUsage is the CPU and memory actually used on a node
+type nodeUsage struct {
+	CpuCores  float32
+	MemoryGiB float32
+}
+
+// usageSource reports the actual usage of the pool's nodes by node name
+type usageSource interface {
+	nodeUsage(ctx context.Context) (map[string]nodeUsage, error)
+}
+
+// newUsageSource creates the configured usage source, or nil when scaling only accounts for allocations
+func newUsageSource(cfg *Config, clientset *kubernetes.Clientset) (usageSource, error) {
+	switch cfg.UsageSource {
+	case "":
+		return nil, nil
+	case UsageSourceMetricsServer:
+		if clientset == nil {
+			return nil, fmt.Errorf("%s requires the %s cluster backend", UsageSourceMetricsServer, ClusterBackendKubernetes)
+		}
+		return &metricsServerUsage{clientset: clientset}, nil
+	case UsageSourcePrometheus:
+		client, err := promapi.NewClient(promapi.Config{Address: cfg.UsagePrometheusURL})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Prometheus client: %w", err)
+		}
+		return &prometheusUsage{api: promv1.NewAPI(client), cpuQuery: cfg.UsagePrometheusCpuQuery, memoryQuery: cfg.UsagePrometheusMemoryQuery}, nil
+	}
+	return nil, fmt.Errorf("unsupported usage source %q", cfg.UsageSource)
+}
+
+// metricsServerUsage reads node usage from the resource metrics API served by metrics-server
+type metricsServerUsage struct {
+	clientset *kubernetes.Clientset
+}
+
+func (s *metricsServerUsage) nodeUsage(ctx context.Context) (map[string]nodeUsage, error) {
+	raw, err := s.clientset.RESTClient().Get().AbsPath(metricsServerNodesPath).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node metrics: %w", err)
+	}
+
+	var nodeMetrics struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+			Usage map[string]resource.Quantity `json:"usage"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(raw, &nodeMetrics); err != nil {
+		return nil, fmt.Errorf("invalid node metrics: %w", err)
+	}
+
+	usage := make(map[string]nodeUsage, len(nodeMetrics.Items))
+	for _, item := range nodeMetrics.Items {
+		cpu, mem := item.Usage["cpu"], item.Usage["memory"]
+		usage[item.Metadata.Name] = nodeUsage{
+			CpuCores:  float32(cpu.AsApproximateFloat64()),
+			MemoryGiB: float32(mem.AsApproximateFloat64() / (1024 * 1024 * 1024)),
+		}
+	}
+	return usage, nil
+}
+
+// prometheusUsage reads node usage from Prometheus queries returning a sample per node labeled with its name
+type prometheusUsage struct {
+	api         promv1.API
+	cpuQuery    string
+	memoryQuery string
+}
+
+func (s *prometheusUsage) nodeUsage(ctx context.Context) (map[string]nodeUsage, error) {
+	cpu, err := s.query(ctx, s.cpuQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CPU usage: %w", err)
+	}
+	memory, err := s.query(ctx, s.memoryQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query memory usage: %w", err)
+	}
+
+	// Only nodes with both samples are known, a partial usage would understate the node's
+	usage := make(map[string]nodeUsage, len(cpu))
+	for node, cores := range cpu {
+		if bytes, found := memory[node]; found {
+			usage[node] = nodeUsage{CpuCores: float32(cores), MemoryGiB: float32(bytes / (1024 * 1024 * 1024))}
+		}
+	}
+	return usage, nil
+}
+
+// query runs an instant query and returns its samples by the value of their node label
+func (s *prometheusUsage) query(ctx context.Context, query string) (map[string]float64, error) {
+	value, warnings, err := s.api.Query(ctx, query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range warnings {
+		log.Debugf("Prometheus usage query warning: %s", warning)
+	}
+
+	vector, ok := value.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("query returned a %s instead of a vector", value.Type())
+	}
+	samples := make(map[string]float64, len(vector))
+	for _, sample := range vector {
+		if node := string(sample.Metric["node"]); node != "" {
+			samples[node] = float64(sample.Value)
+		}
+	}
+	return samples, nil
+}
+
+// gatherNodeUsage records the actual usage of the pool's nodes in the state, leaving it empty if it could not be read
+// so the cycle only accounts for allocations
+func gatherNodeUsage(source usageSource, state *ClusterState) {
+	ctx, cancel := context.WithTimeout(context.Background(), usageQueryTimeout)
+	defer cancel()
+
+	usage, err := source.nodeUsage(ctx)
+	if err != nil {
+		log.Warnf("Could not get actual node usage, accounting for allocations only this cycle: %v", err)
+		return
+	}
+
+	state.NodeUsage = make(map[string]nodeUsage, len(state.Nodes))
+	var cpu, memory float32
+	for _, node := range state.Nodes {
+		if nodeUsage, found := usage[node.Name]; found {
+			state.NodeUsage[node.Name] = nodeUsage
+			cpu += nodeUsage.CpuCores
+			memory += nodeUsage.MemoryGiB
+		}
+	}
+	actualUsage.WithLabelValues("cpu").Set(float64(cpu))
+	actualUsage.WithLabelValues("memory").Set(float64(memory))
+}
+
+// blendUsage weighs the resources allocated to a runner's sandboxes with the resources actually used on its node by
+// USAGE_WEIGHT, so runners whose sandboxes use far less than they are allocated count as having capacity left. The
+// allocation is returned as is when the node's usage is unknown.
+func blendUsage(cfg *Config, state *ClusterState, nodeName string, allocatedCpu, allocatedMemoryGiB float32) (float32, float32) {
+	usage, found := state.NodeUsage[nodeName]
+	if !found || cfg.UsageWeight == 0 {
+		return allocatedCpu, allocatedMemoryGiB
+	}
+	weight := float32(cfg.UsageWeight)
+	return (1-weight)*allocatedCpu + weight*usage.CpuCores, (1-weight)*allocatedMemoryGiB + weight*usage.MemoryGiB
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"math"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultVelocityLeadTime is how far ahead the growth is extrapolated, about the time a new node takes to register
+	// its runner
+	DefaultVelocityLeadTime = 10 * time.Minute
+
+	// velocitySmoothing is the weight of the latest cycle in the growth rates, the remainder is the previous rates, so
+	// a single burst does not provision nodes on its own
+	velocitySmoothing = 0.5
+
+	// velocityMaxSampleGap is the longest gap between two cycles the growth rate is measured over, after a longer one
+	// the rates start over
+	velocityMaxSampleGap = 10 * time.Minute
+)
+
+// velocitySample is the demand of the pool at the end of a cycle
+type velocitySample struct {
+	At                 time.Time
+	AllocatedCpu       float32
+	AllocatedMemoryGiB float32
+	StartedSandboxes   float32
+}
+
+// velocityScaler scales up ahead of a demand surge. It tracks the growth rate of the allocated resources and of the
+// started sandboxes between cycles and raises the idle buffer of the cycle's scaling decisions by the growth expected
+// within the lead time, so a new node is added while the current idle buffer still absorbs the surge rather than
+// once it is exhausted.
+type velocityScaler struct {
+	leadTime time.Duration
+
+	previous *velocitySample
+	// The growth rates per second, smoothed over cycles
+	cpuRate, memoryRate, sandboxRate float64
+}
+
+func newVelocityScaler(cfg *Config) *velocityScaler {
+	return &velocityScaler{leadTime: cfg.VelocityLeadTime}
+}
+
+// observe updates the growth rates with the demand of the cycle
+func (v *velocityScaler) observe(state *ClusterState, metrics *ResourceMetrics, now time.Time) {
+	sample := &velocitySample{
+		At:                 now,
+		AllocatedCpu:       metrics.TotalAllocatedCPU,
+		AllocatedMemoryGiB: metrics.TotalAllocatedMemoryGiB,
+	}
+	for _, runner := range state.Runners {
+		sample.StartedSandboxes += runner.GetCurrentStartedSandboxes()
+	}
+
+	previous := v.previous
+	v.previous = sample
+	if previous == nil {
+		return
+	}
+	elapsed := sample.At.Sub(previous.At)
+	if elapsed <= 0 {
+		return
+	}
+	if elapsed > velocityMaxSampleGap {
+		v.cpuRate, v.memoryRate, v.sandboxRate = 0, 0, 0
+		return
+	}
+
+	seconds := elapsed.Seconds()
+	v.cpuRate = smoothRate(v.cpuRate, float64(sample.AllocatedCpu-previous.AllocatedCpu)/seconds)
+	v.memoryRate = smoothRate(v.memoryRate, float64(sample.AllocatedMemoryGiB-previous.AllocatedMemoryGiB)/seconds)
+	v.sandboxRate = smoothRate(v.sandboxRate, float64(sample.StartedSandboxes-previous.StartedSandboxes)/seconds)
+
+	demandGrowthRate.WithLabelValues("cpu").Set(v.cpuRate * 60)
+	demandGrowthRate.WithLabelValues("memory").Set(v.memoryRate * 60)
+	demandGrowthRate.WithLabelValues("sandboxes").Set(v.sandboxRate * 60)
+}
+
+func smoothRate(previous, latest float64) float64 {
+	return velocitySmoothing*latest + (1-velocitySmoothing)*previous
+}
+
+// boost returns the configuration for the cycle's scaling decisions, with the idle buffer raised by the CPU and memory
+// expected to be allocated within the lead time at the current growth rate
+func (v *velocityScaler) boost(cfg *Config, state *ClusterState, metrics *ResourceMetrics) *Config {
+	v.observe(state, metrics, time.Now())
+
+	lead := v.leadTime.Seconds()
+	extraCpu := int(math.Ceil(v.cpuRate * lead))
+	extraMemory := int(math.Ceil(v.memoryRate * lead))
+	if extraCpu <= 0 && extraMemory <= 0 {
+		return cfg
+	}
+
+	boosted := *cfg
+	boosted.MinIdleCpu += max(extraCpu, 0)
+	boosted.MinIdleMemory += max(extraMemory, 0)
+
+	log.WithFields(log.Fields{
+		"extraCpu":           max(extraCpu, 0),
+		"extraMemory":        max(extraMemory, 0),
+		"sandboxesPerMinute": math.Round(v.sandboxRate*60*10) / 10,
+	}).Infof("Demand is growing by %.1f CPU and %.1f GiB memory per minute: raising the idle buffer to %d CPU, %d GiB memory to cover the next %s.",
+		v.cpuRate*60, v.memoryRate*60, boosted.MinIdleCpu, boosted.MinIdleMemory, v.leadTime)
+	return &boosted
+}
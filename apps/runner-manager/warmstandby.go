@@ -0,0 +1,238 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// PlaceholderStandbyLabel marks placeholder pods created to add warm standby nodes
+	PlaceholderStandbyLabel = "daytona.io/placeholder-standby"
+
+	// WarmStandbyAnnotation records the warm standby state of a node added for the warm buffer: WarmStandbyActive while
+	// its runner is kept unschedulable, WarmStandbyPromoted or WarmStandbyReleased once it left the buffer for good
+	WarmStandbyAnnotation = "daytona.io/warm-standby"
+	WarmStandbyActive     = "standby"
+	WarmStandbyPromoted   = "promoted"
+	WarmStandbyReleased   = "released"
+
+	// EventReasonWarmStandby is the reason of the events recorded on a node entering or leaving the warm buffer
+	EventReasonWarmStandby = "WarmStandby"
+)
+
+// isStandbyNode reports whether the node is in the warm buffer
+func isStandbyNode(node *corev1.Node) bool {
+	return node.Annotations[WarmStandbyAnnotation] == WarmStandbyActive
+}
+
+// isStandbyPlaceholder reports whether the placeholder was created for a warm standby node
+func isStandbyPlaceholder(pod *corev1.Pod) bool {
+	return pod.Labels[PlaceholderStandbyLabel] != ""
+}
+
+// markPlaceholderStandby labels the placeholder of a warm standby node
+func markPlaceholderStandby(pod *corev1.Pod, standby bool) {
+	if !standby {
+		return
+	}
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[PlaceholderStandbyLabel] = "true"
+}
+
+// setWarmStandby records the warm standby state of the node
+func setWarmStandby(backend clusterBackend, node *corev1.Node, value string) error {
+	if err := backend.PatchNode(context.Background(), node.Name, map[string]*string{WarmStandbyAnnotation: &value}, nil); err != nil {
+		return err
+	}
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[WarmStandbyAnnotation] = value
+	return nil
+}
+
+// reconcileWarmStandby keeps WARM_STANDBY_NODES nodes provisioned with their runner registered but unschedulable, so a
+// demand spike is absorbed by making them schedulable instead of waiting for new nodes. The nodes of standby
+// placeholders join the buffer as soon as they are up, their runners are made unschedulable once registered and left
+// out of the capacity, the idle runners and the scale-down candidates. The buffer is refilled with new placeholders
+// when runners are promoted out of it.
+func reconcileWarmStandby(backend clusterBackend, apiClient *daytona.APIClient, cfg *Config, state *ClusterState) {
+	// Nodes of standby placeholders join the buffer unless they already left it
+	for _, pod := range state.ScheduledPlaceholders {
+		if !isStandbyPlaceholder(pod) {
+			continue
+		}
+		node := findNodeByName(state, pod.Spec.NodeName)
+		if node == nil {
+			continue
+		}
+		if _, recorded := node.Annotations[WarmStandbyAnnotation]; recorded {
+			continue
+		}
+		if err := setWarmStandby(backend, node, WarmStandbyActive); err != nil {
+			log.WithField("node", node.Name).Errorf("Error adding node %s to the warm buffer: %v", node.Name, err)
+			continue
+		}
+		log.WithField("node", node.Name).Infof("Node %s joined the warm buffer.", node.Name)
+		recordNodeEvent(backend, node, corev1.EventTypeNormal, EventReasonWarmStandby, "Joined the warm buffer: its runner is kept unschedulable until demand spikes")
+	}
+
+	standby := make(map[string]bool)
+	state.StandbyRunners = nil
+	for _, runner := range state.Runners {
+		node, found := state.NodeByIP[runner.GetDomain()]
+		if !found || !isStandbyNode(node) {
+			continue
+		}
+		runnerLog := log.WithFields(log.Fields{"node": node.Name, "runner": runner.GetId()})
+
+		// A runner that took sandboxes before it was made unschedulable is in use, the buffer is refilled instead
+		if isRunnerAllocated(runner) {
+			if err := setWarmStandby(backend, node, WarmStandbyPromoted); err != nil {
+				runnerLog.Errorf("Error removing node %s from the warm buffer: %v", node.Name, err)
+				continue
+			}
+			runnerLog.Infof("Runner %s of warm standby node %s has sandboxes, removing the node from the warm buffer.", runner.GetId(), node.Name)
+			continue
+		}
+		if !runner.GetUnschedulable() {
+			if err := updateRunnerScheduling(apiClient, runner.GetId(), true); err != nil {
+				runnerLog.Errorf("Error making warm standby runner %s unschedulable, retrying next cycle: %v", runner.GetId(), err)
+				continue
+			}
+			markRunnerUnschedulable(state, runner.GetId())
+			runner.SetUnschedulable(true)
+			runnerLog.Infof("Runner %s registered on warm standby node %s, made it unschedulable.", runner.GetId(), node.Name)
+		}
+		standby[runner.GetId()] = true
+		state.StandbyRunners = append(state.StandbyRunners, runner)
+	}
+
+	// Standby runners are kept for demand spikes, not removed
+	deletable := state.DeletableRunners[:0]
+	for _, runner := range state.DeletableRunners {
+		if !standby[runner.GetId()] {
+			deletable = append(deletable, runner)
+		}
+	}
+	state.DeletableRunners = deletable
+
+	// Nodes on their way to the buffer do not bring idle runners
+	nascent := state.NascentNodes[:0]
+	nascentStandby := 0
+	for _, node := range state.NascentNodes {
+		if current := findNodeByName(state, node.Name); current != nil && isStandbyNode(current) {
+			nascentStandby++
+			continue
+		}
+		nascent = append(nascent, node)
+	}
+	state.NascentNodes = nascent
+
+	var pendingStandby []*corev1.Pod
+	for _, pod := range state.PendingPlaceholders {
+		if isStandbyPlaceholder(pod) {
+			pendingStandby = append(pendingStandby, pod)
+		}
+	}
+
+	warmStandbyNodes.Set(float64(len(state.StandbyRunners)))
+	deficit := cfg.WarmStandbyNodes - len(state.StandbyRunners) - nascentStandby - len(pendingStandby)
+	if deficit < 0 {
+		releaseWarmStandby(backend, cfg, state, pendingStandby, -deficit)
+		return
+	}
+	if deficit = capScaleUp(cfg, state, deficit); deficit == 0 {
+		return
+	}
+
+	log.Infof("Warm buffer has %d standby runners (%d nodes nascent, %d placeholders in-flight), requires %d. Creating %d placeholder pods.",
+		len(state.StandbyRunners), nascentStandby, len(pendingStandby), cfg.WarmStandbyNodes, deficit)
+	for i := 0; i < deficit; i++ {
+		pod, err := createPlaceholderPod(backend, cfg, PlaceholderPodLabel, placeholderOptions{Standby: true, CapacityType: state.CapacityType})
+		if err != nil {
+			log.Errorf("Error creating placeholder pod for the warm buffer: %v", err)
+			continue
+		}
+		recordPlaceholderEvent(backend, pod, corev1.EventTypeNormal, EventReasonScaleUp,
+			fmt.Sprintf("Placeholder created to add a warm standby node: the warm buffer has fewer than %d nodes", cfg.WarmStandbyNodes))
+		state.PlaceholdersCreated++
+	}
+}
+
+// releaseWarmStandby shrinks the warm buffer by count nodes after WARM_STANDBY_NODES was lowered: pending standby
+// placeholders are deleted first, then standby nodes leave the buffer and their idle unschedulable runners are removed
+// by the regular scale-down
+func releaseWarmStandby(backend clusterBackend, cfg *Config, state *ClusterState, pendingStandby []*corev1.Pod, count int) {
+	for _, pod := range pendingStandby {
+		if count == 0 {
+			return
+		}
+		if err := backend.DeletePlaceholder(context.Background(), pod.Name); err != nil {
+			log.Errorf("Error deleting pending warm standby placeholder pod %s: %v", pod.Name, err)
+			continue
+		}
+		log.Infof("Deleted pending placeholder pod %s, the warm buffer requires %d nodes.", pod.Name, cfg.WarmStandbyNodes)
+		count--
+	}
+
+	var released, kept []daytona.RunnerFull
+	for _, runner := range state.StandbyRunners {
+		node := state.NodeByIP[runner.GetDomain()]
+		if count == 0 || setWarmStandby(backend, node, WarmStandbyReleased) != nil {
+			kept = append(kept, runner)
+			continue
+		}
+		log.WithField("node", node.Name).Infof("Released node %s from the warm buffer, which requires %d nodes.", node.Name, cfg.WarmStandbyNodes)
+		recordNodeEvent(backend, node, corev1.EventTypeNormal, EventReasonWarmStandby, "Released from the warm buffer: WARM_STANDBY_NODES was lowered")
+		released = append(released, runner)
+		count--
+	}
+	state.DeletableRunners = append(state.DeletableRunners, released...)
+	state.StandbyRunners = kept
+}
+
+// promoteStandbyRunners makes up to count standby runners schedulable for a scale-up and returns how many were
+// promoted. The warm buffer is refilled by the next cycles.
+func promoteStandbyRunners(backend clusterBackend, apiClient *daytona.APIClient, state *ClusterState, count int) int {
+	promoted := 0
+	remaining := state.StandbyRunners[:0]
+	for _, runner := range state.StandbyRunners {
+		if promoted >= count {
+			remaining = append(remaining, runner)
+			continue
+		}
+		node := state.NodeByIP[runner.GetDomain()]
+		runnerLog := log.WithFields(log.Fields{"node": node.Name, "runner": runner.GetId()})
+		if err := updateRunnerScheduling(apiClient, runner.GetId(), false); err != nil {
+			runnerLog.Errorf("Error making warm standby runner %s schedulable: %v", runner.GetId(), err)
+			remaining = append(remaining, runner)
+			continue
+		}
+		if err := setWarmStandby(backend, node, WarmStandbyPromoted); err != nil {
+			// Still marked as standby, the runner would be made unschedulable again next cycle
+			runnerLog.Errorf("Error removing node %s from the warm buffer, making its runner unschedulable again: %v", node.Name, err)
+			if err := updateRunnerScheduling(apiClient, runner.GetId(), true); err != nil {
+				runnerLog.Errorf("Error making warm standby runner %s unschedulable again: %v", runner.GetId(), err)
+			}
+			remaining = append(remaining, runner)
+			continue
+		}
+		runnerLog.Infof("Promoted warm standby runner %s on node %s, it is schedulable.", runner.GetId(), node.Name)
+		recordNodeEvent(backend, node, corev1.EventTypeNormal, EventReasonWarmStandby, "Promoted out of the warm buffer for a scale-up: runner is schedulable")
+		warmStandbyPromotions.Inc()
+		promoted++
+	}
+	state.StandbyRunners = remaining
+	return promoted
+}
@@ -0,0 +1,355 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// WhatIfPath is the admin endpoint replaying the scaling history with hypothetical thresholds
+	WhatIfPath = "/what-if"
+
+	// DefaultScalingHistoryRetention is how long controller cycle samples are kept in the scaling history
+	DefaultScalingHistoryRetention = 30 * 24 * time.Hour
+
+	// DefaultWhatIfProvisioningDelay is how long a projected node takes to serve sandboxes unless the request sets it
+	DefaultWhatIfProvisioningDelay = 5 * time.Minute
+)
+
+// historySample is the pool's demand and capacity in a controller cycle, as persisted in the scaling history
+type historySample struct {
+	Time               time.Time `json:"time"`
+	Pool               string    `json:"pool,omitempty"` // Empty in samples recorded before pools, which belong to DefaultPoolName
+	Nodes              int       `json:"nodes"`
+	ActiveRunners      int       `json:"activeRunners"`
+	IdleRunners        int       `json:"idleRunners"`
+	NascentNodes       int       `json:"nascentNodes"`
+	AllocatedCpu       float32   `json:"allocatedCpu"`
+	AllocatedMemoryGiB float32   `json:"allocatedMemoryGiB"`
+	AvgCpuPerNode      float32   `json:"avgCpuPerNode"`
+	AvgMemPerNode      float32   `json:"avgMemPerNode"`
+	QueuedSandboxes    int       `json:"queuedSandboxes"`
+}
+
+// scalingHistory appends a sample per controller cycle as JSON lines to a local file, which survives restarts and
+// is replayed by the what-if endpoint
+type scalingHistory struct {
+	mu        sync.Mutex
+	path      string
+	file      *os.File
+	retention time.Duration
+}
+
+// openScalingHistory opens the history file, dropping the samples older than the retention period
+func openScalingHistory(path string, retention time.Duration) (*scalingHistory, error) {
+	h := &scalingHistory{path: path, retention: retention}
+
+	samples, err := h.read(time.Now().Add(-retention), time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if err := h.rewrite(samples); err != nil {
+		return nil, fmt.Errorf("failed to compact scaling history: %w", err)
+	}
+
+	h.file, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// record appends the sample of a controller cycle of the pool
+func (h *scalingHistory) record(poolName string, state *ClusterState, metrics *ResourceMetrics) error {
+	line, err := json.Marshal(historySample{
+		Time:               time.Now().UTC(),
+		Pool:               poolName,
+		Nodes:              len(state.Nodes),
+		ActiveRunners:      len(state.ActiveRunners),
+		IdleRunners:        len(state.IdleRunners),
+		NascentNodes:       len(state.NascentNodes),
+		AllocatedCpu:       metrics.TotalAllocatedCPU,
+		AllocatedMemoryGiB: metrics.TotalAllocatedMemoryGiB,
+		AvgCpuPerNode:      metrics.AvgCpuPerNode,
+		AvgMemPerNode:      metrics.AvgMemPerNode,
+		QueuedSandboxes:    state.QueuedSandboxes,
+	})
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err = h.file.Write(append(line, '\n'))
+	return err
+}
+
+// read returns the samples recorded within the given bounds, a zero bound is open. Lines that cannot be parsed, such
+// as one truncated by a crash, are skipped.
+func (h *scalingHistory) read(since, until time.Time) ([]historySample, error) {
+	file, err := os.Open(h.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var samples []historySample
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var sample historySample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			continue
+		}
+		if (!since.IsZero() && sample.Time.Before(since)) || (!until.IsZero() && sample.Time.After(until)) {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	return samples, scanner.Err()
+}
+
+// rewrite replaces the history file with the given samples
+func (h *scalingHistory) rewrite(samples []historySample) error {
+	tmp, err := os.CreateTemp(filepath.Dir(h.path), filepath.Base(h.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	for _, sample := range samples {
+		if err = encoder.Encode(sample); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), h.path)
+}
+
+// whatIfRequest holds the hypothetical thresholds, unset ones default to the configured values
+type whatIfRequest struct {
+	MinIdleRunners                *int      `json:"minIdleRunners"`
+	MinIdleCpu                    *int      `json:"minIdleCpu"`
+	MinIdleMemory                 *int      `json:"minIdleMemory"`
+	MaxResourceUtilizationPercent *int      `json:"maxResourceUtilizationPercent"`
+	ProvisioningDelay             string    `json:"provisioningDelay"`
+	Since                         time.Time `json:"since"`
+	Until                         time.Time `json:"until"`
+}
+
+// whatIfThresholds are the thresholds a replay is run with
+type whatIfThresholds struct {
+	MinIdleRunners                int    `json:"minIdleRunners"`
+	MinIdleCpu                    int    `json:"minIdleCpu"`
+	MinIdleMemory                 int    `json:"minIdleMemory"`
+	MaxResourceUtilizationPercent int    `json:"maxResourceUtilizationPercent"`
+	ProvisioningDelay             string `json:"provisioningDelay"`
+}
+
+// whatIfOutcome summarizes a pool over the replayed period. A missed-capacity incident is a period during which
+// sandboxes could not be placed, counted once however many cycles it lasts.
+type whatIfOutcome struct {
+	NodeHours               float64 `json:"nodeHours"`
+	PeakNodes               int     `json:"peakNodes"`
+	MissedCapacityIncidents int     `json:"missedCapacityIncidents"`
+	MissedCapacityMinutes   float64 `json:"missedCapacityMinutes"`
+}
+
+type whatIfResponse struct {
+	Since      time.Time        `json:"since"`
+	Until      time.Time        `json:"until"`
+	Samples    int              `json:"samples"`
+	Thresholds whatIfThresholds `json:"thresholds"`
+	Actual     whatIfOutcome    `json:"actual"`
+	Projected  whatIfOutcome    `json:"projected"`
+}
+
+// replayScalingHistory projects the pool the thresholds would have kept over the samples. The recorded allocation
+// and queued sandboxes are the demand, every node has the pool's average size, nodes requested by a scale-up serve
+// sandboxes after the provisioning delay and idle nodes beyond the thresholds are removed right away. The demand
+// the real pool could not serve is unknown, so projected incidents are a lower bound when it was under-provisioned.
+// A sample accounts for the time until the next one, at most the max sample gap so downtime is not counted.
+func replayScalingHistory(samples []historySample, thresholds whatIfThresholds, provisioningDelay, maxSampleGap time.Duration) (actual, projected whatIfOutcome) {
+	var avgCpu, avgMem float32
+	for _, sample := range samples {
+		if sample.AvgCpuPerNode > 0 && sample.AvgMemPerNode > 0 {
+			avgCpu, avgMem = sample.AvgCpuPerNode, sample.AvgMemPerNode
+		}
+	}
+
+	var ready int
+	var provisioning []time.Time // Ready times of the nodes being provisioned, in order
+	actualMissing, projectedMissing := false, false
+
+	for i, sample := range samples {
+		duration := maxSampleGap
+		if i+1 < len(samples) {
+			duration = min(samples[i+1].Time.Sub(sample.Time), maxSampleGap)
+		}
+		hours := duration.Hours()
+
+		for len(provisioning) > 0 && !provisioning[0].After(sample.Time) {
+			provisioning = provisioning[1:]
+			ready++
+		}
+		if i == 0 {
+			// The projection starts from the real pool
+			ready = sample.ActiveRunners + sample.IdleRunners
+		}
+
+		required := sample.ActiveRunners + thresholds.MinIdleRunners
+		if sample.QueuedSandboxes > 0 {
+			required = max(required, sample.ActiveRunners+1)
+		}
+		if avgCpu > 0 && avgMem > 0 {
+			required = max(required, int(math.Ceil(float64((sample.AllocatedCpu+float32(thresholds.MinIdleCpu))/avgCpu))))
+			required = max(required, int(math.Ceil(float64((sample.AllocatedMemoryGiB+float32(thresholds.MinIdleMemory))/avgMem))))
+			if thresholds.MaxResourceUtilizationPercent > 0 {
+				utilization := float32(thresholds.MaxResourceUtilizationPercent) / 100
+				required = max(required, int(math.Ceil(float64(sample.AllocatedCpu/(avgCpu*utilization)))))
+				required = max(required, int(math.Ceil(float64(sample.AllocatedMemoryGiB/(avgMem*utilization)))))
+			}
+		}
+
+		// Busy nodes cannot be removed, in-flight ones count towards the requirement like nascent nodes do
+		if ready > required {
+			ready = max(required, sample.ActiveRunners)
+			provisioning = nil
+		}
+		for missing := required - ready - len(provisioning); missing > 0; missing-- {
+			provisioning = append(provisioning, sample.Time.Add(provisioningDelay))
+		}
+
+		// Sandboxes cannot be placed when the busy nodes take all the capacity, or queue while no idle node is left
+		projectedShort := ready < sample.ActiveRunners ||
+			(avgCpu > 0 && sample.AllocatedCpu > float32(ready)*avgCpu) ||
+			(avgMem > 0 && sample.AllocatedMemoryGiB > float32(ready)*avgMem) ||
+			(sample.QueuedSandboxes > 0 && ready <= sample.ActiveRunners)
+		actualShort := sample.QueuedSandboxes > 0
+
+		accumulateWhatIfOutcome(&actual, sample.Nodes, actualShort, &actualMissing, hours)
+		accumulateWhatIfOutcome(&projected, ready+len(provisioning), projectedShort, &projectedMissing, hours)
+	}
+
+	actual.NodeHours = math.Round(actual.NodeHours*100) / 100
+	projected.NodeHours = math.Round(projected.NodeHours*100) / 100
+	return actual, projected
+}
+
+// accumulateWhatIfOutcome accounts a sample to the outcome. Nodes being provisioned are billed like ready ones.
+func accumulateWhatIfOutcome(outcome *whatIfOutcome, nodes int, short bool, missing *bool, hours float64) {
+	outcome.NodeHours += float64(nodes) * hours
+	outcome.PeakNodes = max(outcome.PeakNodes, nodes)
+	if short {
+		if !*missing {
+			outcome.MissedCapacityIncidents++
+		}
+		outcome.MissedCapacityMinutes += hours * 60
+	}
+	*missing = short
+}
+
+// whatIfHandler replays the scaling history of the selected pool with the thresholds of the request
+func whatIfHandler(pools []*runnerPool, history *scalingHistory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		pool := selectPool(w, r, pools)
+		if pool == nil {
+			return
+		}
+		cfg := pool.config()
+
+		var req whatIfRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		thresholds := whatIfThresholds{
+			MinIdleRunners:                cfg.MinIdleRunners,
+			MinIdleCpu:                    cfg.MinIdleCpu,
+			MinIdleMemory:                 cfg.MinIdleMemory,
+			MaxResourceUtilizationPercent: cfg.MaxResourceUtilizationPercent,
+		}
+		for _, override := range []struct {
+			value  *int
+			target *int
+		}{
+			{req.MinIdleRunners, &thresholds.MinIdleRunners},
+			{req.MinIdleCpu, &thresholds.MinIdleCpu},
+			{req.MinIdleMemory, &thresholds.MinIdleMemory},
+			{req.MaxResourceUtilizationPercent, &thresholds.MaxResourceUtilizationPercent},
+		} {
+			if override.value == nil {
+				continue
+			}
+			if *override.value < 0 {
+				http.Error(w, "thresholds cannot be negative", http.StatusBadRequest)
+				return
+			}
+			*override.target = *override.value
+		}
+
+		provisioningDelay := DefaultWhatIfProvisioningDelay
+		if req.ProvisioningDelay != "" {
+			var err error
+			provisioningDelay, err = time.ParseDuration(req.ProvisioningDelay)
+			if err != nil || provisioningDelay < 0 {
+				http.Error(w, fmt.Sprintf("invalid provisioning delay: %s", req.ProvisioningDelay), http.StatusBadRequest)
+				return
+			}
+		}
+		thresholds.ProvisioningDelay = provisioningDelay.String()
+
+		recorded, err := history.read(req.Since, req.Until)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read scaling history: %v", err), http.StatusInternalServerError)
+			return
+		}
+		samples := samplesOfPool(recorded, cfg.PoolName)
+		if len(samples) == 0 {
+			http.Error(w, "no scaling history in the requested period", http.StatusNotFound)
+			return
+		}
+
+		response := whatIfResponse{
+			Since:      samples[0].Time,
+			Until:      samples[len(samples)-1].Time,
+			Samples:    len(samples),
+			Thresholds: thresholds,
+		}
+		response.Actual, response.Projected = replayScalingHistory(samples, thresholds, provisioningDelay, 2*cfg.CheckInterval)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
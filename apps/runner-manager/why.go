@@ -0,0 +1,164 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/daytonaio/daytona/apps/runner-manager/pkg/status"
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+)
+
+// cycleExplanation collects why a controller cycle scaled as it did. It is only written by the pool's loop and
+// published to the why endpoint once the cycle ends.
+type cycleExplanation struct {
+	actions   []string
+	scaleUp   status.ScaleUpExplanation
+	scaleDown status.ScaleDownExplanation
+	// candidates indexes scaleDown.Runners by runner ID
+	candidates map[string]int
+}
+
+func newCycleExplanation() *cycleExplanation {
+	return &cycleExplanation{
+		scaleUp:    status.ScaleUpExplanation{Conditions: []status.ScaleUpCondition{}},
+		scaleDown:  status.ScaleDownExplanation{Runners: []status.ScaleDownCandidate{}},
+		candidates: make(map[string]int),
+	}
+}
+
+// evaluateScaleUp records the scale-up conditions with the values shouldScaleUp compares to their thresholds
+func (e *cycleExplanation) evaluateScaleUp(cfg *Config, state *ClusterState, metrics *ResourceMetrics) {
+	utilization := func(allocated, total float32) float32 {
+		if total <= 0 {
+			return 0
+		}
+		return allocated / total * 100
+	}
+	above := func(name status.ScaleUpConditionName, value float32, threshold int) status.ScaleUpCondition {
+		return status.ScaleUpCondition{Name: name, Fired: value > float32(threshold), Value: value, Threshold: float32(threshold)}
+	}
+	below := func(name status.ScaleUpConditionName, value float32, threshold int) status.ScaleUpCondition {
+		return status.ScaleUpCondition{Name: name, Fired: value < float32(threshold), Value: value, Threshold: float32(threshold)}
+	}
+
+	idleRunners := len(state.IdleRunners) + len(state.NascentNodes)
+	conditions := []status.ScaleUpCondition{
+		above(status.ConditionCpuUtilization, utilization(metrics.TotalAllocatedCPU, metrics.TotalCPUCapacity), cfg.MaxResourceUtilizationPercent),
+		above(status.ConditionMemoryUtilization, utilization(metrics.TotalAllocatedMemoryGiB, metrics.TotalMemoryGiBCapacity), cfg.MaxResourceUtilizationPercent),
+	}
+	isGpuPool := metrics.TotalGPUCapacity > 0 || cfg.MinIdleGpu > 0
+	if isGpuPool {
+		conditions = append(conditions, above(status.ConditionGpuUtilization, utilization(metrics.TotalAllocatedGPU, metrics.TotalGPUCapacity), cfg.MaxResourceUtilizationPercent))
+	}
+	if cfg.MaxDiskUtilizationPercent > 0 {
+		conditions = append(conditions, above(status.ConditionDiskUtilization, utilization(metrics.TotalAllocatedDiskGiB, metrics.TotalDiskGiBCapacity), cfg.MaxDiskUtilizationPercent))
+	}
+	conditions = append(conditions,
+		below(status.ConditionIdleRunners, float32(idleRunners), cfg.MinIdleRunners),
+		below(status.ConditionIdleCpu, metrics.TotalAvailableCPU, cfg.MinIdleCpu),
+		below(status.ConditionIdleMemory, metrics.TotalAvailableMemoryGiB, cfg.MinIdleMemory),
+	)
+	if isGpuPool {
+		conditions = append(conditions, below(status.ConditionIdleGpu, metrics.TotalAvailableGPU, cfg.MinIdleGpu))
+	}
+	if cfg.MinIdleDisk > 0 {
+		conditions = append(conditions, below(status.ConditionIdleDisk, metrics.TotalAvailableDiskGiB, cfg.MinIdleDisk))
+	}
+	conditions = append(conditions, status.ScaleUpCondition{
+		Name:  status.ConditionQueueStarved,
+		Fired: state.QueuedSandboxes > 0 && idleRunners == 0,
+		Value: float32(state.QueuedSandboxes),
+	})
+	e.scaleUp.Conditions = conditions
+}
+
+// deficit records the nodes a fired scale-up condition needed
+func (e *cycleExplanation) deficit(name status.ScaleUpConditionName, nodes int) {
+	for i := range e.scaleUp.Conditions {
+		if e.scaleUp.Conditions[i].Name == name {
+			e.scaleUp.Conditions[i].Deficit = nodes
+		}
+	}
+}
+
+// skipScaleUp records why no scale-up was attempted although a condition fired
+func (e *cycleExplanation) skipScaleUp(reason string) {
+	e.scaleUp.Skipped = reason
+}
+
+// skipScaleDown records why scale-down was not evaluated
+func (e *cycleExplanation) skipScaleDown(reason string) {
+	e.scaleDown.Skipped = reason
+}
+
+// consider records what scale-down did with a deletable runner, replacing an earlier outcome of the same runner such as
+// its selection for removal
+func (e *cycleExplanation) consider(runner daytona.RunnerFull, nodeName string, outcome status.ScaleDownOutcome, reason, message string) {
+	candidate := status.ScaleDownCandidate{
+		RunnerID: runner.GetId(),
+		Domain:   runner.GetDomain(),
+		NodeName: nodeName,
+		Outcome:  outcome,
+		Reason:   reason,
+		Message:  message,
+	}
+	if i, found := e.candidates[runner.GetId()]; found {
+		e.scaleDown.Runners[i] = candidate
+		return
+	}
+	e.candidates[runner.GetId()] = len(e.scaleDown.Runners)
+	e.scaleDown.Runners = append(e.scaleDown.Runners, candidate)
+}
+
+// recordExplanation publishes the explanation of the cycle that gathered the state
+func (s *statusStore) recordExplanation(regionID, poolName string, state *ClusterState) {
+	why := state.Why
+	explanation := &status.Explanation{
+		RegionID:  regionID,
+		Pool:      poolName,
+		Timestamp: time.Now(),
+		Actions:   append([]string{}, why.actions...),
+		ScaleUp:   why.scaleUp,
+		ScaleDown: why.scaleDown,
+	}
+	explanation.ScaleUp.CappedBy = state.ScaleUpCappedBy
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.explanation = explanation
+}
+
+// getExplanation returns the explanation of the latest cycle, or nil if no controller cycle completed yet
+func (s *statusStore) getExplanation() *status.Explanation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.explanation
+}
+
+// whyHandler serves the explanation of the latest controller cycle of the selected pool
+func whyHandler(pools []*runnerPool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		pool := selectPool(w, r, pools)
+		if pool == nil {
+			return
+		}
+
+		explanation := pool.statuses.getExplanation()
+		if explanation == nil {
+			http.Error(w, "no controller cycle completed yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(explanation)
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	daytona "github.com/daytonaio/daytona/libs/api-client-go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// ZoneLabel is the node label the availability zone of a pool node is read from
+	ZoneLabel = corev1.LabelTopologyZone
+
+	// PlaceholderZoneLabel marks placeholder pods created for a zone's idle requirement
+	PlaceholderZoneLabel = "daytona.io/placeholder-zone"
+
+	// AllZones applies a per-zone idle requirement to every zone the pool has nodes in
+	AllZones = "*"
+)
+
+// zoneIdleStatus is the idle runner requirement of a zone and what currently covers it
+type zoneIdleStatus struct {
+	Required int
+	Idle     int
+	Nascent  int // Nodes in the zone with a placeholder but no runner yet
+	Pending  int // Zone-targeted placeholders waiting for a node
+}
+
+// deficit returns how many more zone-targeted placeholders are needed to cover the requirement
+func (s *zoneIdleStatus) deficit() int {
+	return s.Required - s.Idle - s.Nascent - s.Pending
+}
+
+// covered reports whether the zone meets its requirement without any pending placeholders
+func (s *zoneIdleStatus) covered() bool {
+	return s.Idle+s.Nascent >= s.Required
+}
+
+// parseZoneIdleRequirements parses MIN_IDLE_RUNNERS_PER_ZONE in the format "zone-a=1,zone-b=2".
+// The zone AllZones sets the requirement of every pool zone not listed explicitly.
+func parseZoneIdleRequirements(value string) (map[string]int, error) {
+	requirements := make(map[string]int)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		zone, countStr, found := strings.Cut(entry, "=")
+		zone = strings.TrimSpace(zone)
+		if !found || zone == "" {
+			return nil, fmt.Errorf("entry %q must be in the format zone=count", entry)
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(countStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid count for zone %s: %v", zone, err)
+		}
+		if count < 0 {
+			return nil, fmt.Errorf("count for zone %s cannot be negative", zone)
+		}
+		requirements[zone] = count
+	}
+	return requirements, nil
+}
+
+// gatherZoneIdle resolves the per-zone idle requirements against the pool nodes and counts the idle runners, nascent
+// nodes and pending zone-targeted placeholders of each zone
+func gatherZoneIdle(cfg *Config, state *ClusterState) map[string]*zoneIdleStatus {
+	zones := make(map[string]*zoneIdleStatus)
+	for zone, required := range cfg.MinIdleRunnersPerZone {
+		if zone != AllZones {
+			zones[zone] = &zoneIdleStatus{Required: required}
+		}
+	}
+	if required, found := cfg.MinIdleRunnersPerZone[AllZones]; found {
+		for _, node := range state.Nodes {
+			zone := node.Labels[ZoneLabel]
+			if _, listed := zones[zone]; zone != "" && !listed {
+				zones[zone] = &zoneIdleStatus{Required: required}
+			}
+		}
+	}
+
+	for _, runner := range state.IdleRunners {
+		if node, found := state.NodeByIP[runner.GetDomain()]; found {
+			if status, found := zones[node.Labels[ZoneLabel]]; found {
+				status.Idle++
+			}
+		}
+	}
+	for _, node := range state.NascentNodes {
+		if status, found := zones[node.Labels[ZoneLabel]]; found {
+			status.Nascent++
+		}
+	}
+	for _, pod := range state.PendingPlaceholders {
+		if status, found := zones[pod.Labels[PlaceholderZoneLabel]]; found {
+			status.Pending++
+		}
+	}
+
+	zoneIdleRunners.Reset()
+	zoneMinIdleRunners.Reset()
+	for zone, status := range zones {
+		zoneIdleRunners.WithLabelValues(zone).Set(float64(status.Idle))
+		zoneMinIdleRunners.WithLabelValues(zone).Set(float64(status.Required))
+	}
+
+	return zones
+}
+
+// handleZoneScaleUp creates zone-targeted placeholder pods for zones below their idle requirement and returns true
+// if any were created
+func handleZoneScaleUp(backend clusterBackend, cfg *Config, state *ClusterState) bool {
+	zoneNames := make([]string, 0, len(state.ZoneIdle))
+	for zone := range state.ZoneIdle {
+		zoneNames = append(zoneNames, zone)
+	}
+	sort.Strings(zoneNames)
+
+	createdCount := 0
+	for _, zone := range zoneNames {
+		status := state.ZoneIdle[zone]
+		deficit := status.deficit()
+		if deficit <= 0 {
+			continue
+		}
+		if deficit = capScaleUp(cfg, state, deficit); deficit == 0 {
+			continue
+		}
+
+		log.Infof("Zone %s has %d idle runners (%d nascent, %d in-flight), requires %d. Creating %d zone-targeted placeholder pods.",
+			zone, status.Idle, status.Nascent, status.Pending, status.Required, deficit)
+		for i := 0; i < deficit; i++ {
+			pod, err := createPlaceholderPod(backend, cfg, PlaceholderPodLabel, placeholderOptions{Zone: zone, CapacityType: state.CapacityType})
+			if err != nil {
+				log.Errorf("Error creating placeholder pod for zone %s: %v", zone, err)
+				continue
+			}
+			recordPlaceholderEvent(backend, pod, corev1.EventTypeNormal, EventReasonScaleUp,
+				fmt.Sprintf("Placeholder created to add a node: zone %s has fewer idle runners than its requirement of %d", zone, status.Required))
+			status.Pending++
+			state.PlaceholdersCreated++
+			createdCount++
+		}
+	}
+	return createdCount > 0
+}
+
+// spreadPlaceholderAcrossZones asks the scheduler to spread the placeholders of the app evenly across zones when
+// ZONE_BALANCING_ENABLED is set, so the nodes added for the pool do not all land in one zone. Zone-targeted
+// placeholders are left pinned to their zone. The spread is best-effort so a zone out of capacity does not block the
+// scale-up.
+func spreadPlaceholderAcrossZones(pod *corev1.Pod, cfg *Config, appName string) {
+	if !cfg.ZoneBalancingEnabled || pod.Labels[PlaceholderZoneLabel] != "" {
+		return
+	}
+	pod.Spec.TopologySpreadConstraints = append(pod.Spec.TopologySpreadConstraints, corev1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       ZoneLabel,
+		WhenUnsatisfiable: corev1.ScheduleAnyway,
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": appName},
+		},
+	})
+}
+
+// zoneSpareRunners counts the runners without sandboxes, schedulable or not, of each zone
+func zoneSpareRunners(state *ClusterState) map[string]int {
+	spare := make(map[string]int)
+	for _, runners := range [][]daytona.RunnerFull{state.IdleRunners, state.DeletableRunners} {
+		for _, runner := range runners {
+			if node, found := state.NodeByIP[runner.GetDomain()]; found {
+				if zone := node.Labels[ZoneLabel]; zone != "" {
+					spare[zone]++
+				}
+			}
+		}
+	}
+	return spare
+}
+
+// isLastSpareInZone reports whether removing the node would leave its zone without a runner free of sandboxes, so a
+// zone outage does not take all of the pool's spare capacity with it. It is always false unless ZONE_BALANCING_ENABLED
+// is set.
+func isLastSpareInZone(cfg *Config, spare map[string]int, node *corev1.Node) bool {
+	if !cfg.ZoneBalancingEnabled {
+		return false
+	}
+	zone := node.Labels[ZoneLabel]
+	return zone != "" && spare[zone] <= 1
+}
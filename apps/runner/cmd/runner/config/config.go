@@ -46,6 +46,8 @@ type Config struct {
 	HealthcheckTimeout       time.Duration `envconfig:"HEALTHCHECK_TIMEOUT" default:"10s"`
 	BackupTimeoutMin         int           `envconfig:"BACKUP_TIMEOUT_MIN" default:"60" validate:"min=1"`
 	ApiVersion               int           `envconfig:"API_VERSION" default:"2"`
+	HeartbeatProxyUrl        string        `envconfig:"HEARTBEAT_PROXY_URL" validate:"omitempty,url"`
+	NodeName                 string        `envconfig:"NODE_NAME"`
 }
 
 var DEFAULT_API_PORT int = 8080
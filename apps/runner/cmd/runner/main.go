@@ -168,6 +168,9 @@ func main() {
 			ApiPort:    cfg.ApiPort,
 			ProxyPort:  cfg.ApiPort,
 			TlsEnabled: cfg.EnableTLS,
+			// Runners behind NAT send their heartbeat through the proxy
+			HeartbeatProxyUrl: cfg.HeartbeatProxyUrl,
+			NodeName:          cfg.NodeName,
 		})
 		if err != nil {
 			log.Fatalf("Failed to create healthcheck service: %v", err)
@@ -0,0 +1,101 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/daytonaio/runner/pkg/runner"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	common_passthrough "github.com/daytonaio/common-go/pkg/passthrough"
+)
+
+// TcpTunnel godoc
+//
+//	@Tags			sandbox
+//	@Summary		Open a TCP tunnel to a sandbox port
+//	@Description	Upgrades the connection to a raw TCP stream to the sandbox port, used for TLS passthrough
+//	@Param			sandboxId	path	string	true	"Sandbox ID"
+//	@Param			port		path	int		true	"Sandbox port"
+//	@Success		101
+//	@Failure		400	{object}	string	"Bad request"
+//	@Failure		404	{object}	string	"Sandbox container not found"
+//	@Failure		502	{object}	string	"Sandbox port unreachable"
+//	@Router			/sandboxes/{sandboxId}/tcp/{port} [get]
+func TcpTunnel(ctx *gin.Context) {
+	sandboxId := ctx.Param("sandboxId")
+
+	port, err := strconv.Atoi(ctx.Param("port"))
+	if err != nil || port <= 0 || port > 65535 {
+		ctx.Error(common_errors.NewBadRequestError(errors.New("invalid port")))
+		return
+	}
+
+	if !strings.EqualFold(ctx.GetHeader("Upgrade"), common_passthrough.UpgradeProtocol) {
+		ctx.Error(common_errors.NewBadRequestError(fmt.Errorf("expected upgrade to %s", common_passthrough.UpgradeProtocol)))
+		return
+	}
+
+	container, err := runner.GetInstance(nil).Docker.ContainerInspect(ctx.Request.Context(), sandboxId)
+	if err != nil {
+		ctx.Error(common_errors.NewNotFoundError(fmt.Errorf("sandbox container not found: %w", err)))
+		return
+	}
+
+	var containerIP string
+	for _, network := range container.NetworkSettings.Networks {
+		containerIP = network.IPAddress
+		break
+	}
+	if containerIP == "" {
+		ctx.Error(common_errors.NewBadRequestError(errors.New("no IP address found. Is the Sandbox started?")))
+		return
+	}
+
+	upstream, err := net.DialTimeout("tcp", net.JoinHostPort(containerIP, strconv.Itoa(port)), 10*time.Second)
+	if err != nil {
+		ctx.Error(common_errors.NewCustomError(http.StatusBadGateway, fmt.Sprintf("sandbox port %d is unreachable: %v", port, err), "SANDBOX_PORT_UNREACHABLE"))
+		return
+	}
+
+	conn, buffered, err := ctx.Writer.Hijack()
+	if err != nil {
+		upstream.Close()
+		ctx.Error(fmt.Errorf("failed to hijack connection: %w", err))
+		return
+	}
+
+	_, err = fmt.Fprintf(buffered, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: %s\r\nConnection: Upgrade\r\n\r\n", common_passthrough.UpgradeProtocol)
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err != nil {
+		upstream.Close()
+		conn.Close()
+		log.Warnf("Failed to open TCP tunnel to sandbox %s port %d: %v", sandboxId, port, err)
+		return
+	}
+
+	// Bytes the client sent right after the upgrade request may already be buffered
+	if buffered.Reader.Buffered() > 0 {
+		pending, _ := buffered.Reader.Peek(buffered.Reader.Buffered())
+		if _, err := upstream.Write(pending); err != nil {
+			upstream.Close()
+			conn.Close()
+			return
+		}
+	}
+
+	log.Debugf("Opened TCP tunnel to sandbox %s port %d", sandboxId, port)
+	common_passthrough.Splice(conn, upstream)
+}
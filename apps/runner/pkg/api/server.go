@@ -123,6 +123,7 @@ func (a *ApiServer) Start() error {
 		sandboxController.POST("/:sandboxId/is-recoverable", controllers.IsRecoverable)
 		sandboxController.DELETE("/:sandboxId", controllers.RemoveDestroyed)
 		sandboxController.POST("/:sandboxId/network-settings", controllers.UpdateNetworkSettings)
+		sandboxController.GET("/:sandboxId/tcp/:port", controllers.TcpTunnel)
 
 		// Add proxy endpoint within the sandbox controller for toolbox
 		// Using Any() to handle all HTTP methods for the toolbox proxy
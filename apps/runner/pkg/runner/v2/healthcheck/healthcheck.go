@@ -9,8 +9,10 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
+	common_tunnel "github.com/daytonaio/common-go/pkg/tunnel"
 	apiclient "github.com/daytonaio/daytona/libs/api-client-go"
 	"github.com/daytonaio/runner/internal"
 	"github.com/daytonaio/runner/internal/metrics"
@@ -26,6 +28,9 @@ type HealthcheckServiceConfig struct {
 	ApiPort    int
 	ProxyPort  int
 	TlsEnabled bool
+	// HeartbeatProxyUrl routes healthchecks through the proxy's heartbeat relay when the API is not directly reachable
+	HeartbeatProxyUrl string
+	NodeName          string
 }
 
 // Service handles healthcheck reporting to the API
@@ -48,6 +53,10 @@ func NewService(cfg *HealthcheckServiceConfig) (*Service, error) {
 		return nil, fmt.Errorf("failed to create API client: %w", err)
 	}
 
+	if cfg.HeartbeatProxyUrl != "" {
+		apiClient = newHeartbeatRelayClient(apiClient, cfg.HeartbeatProxyUrl, cfg.NodeName)
+	}
+
 	return &Service{
 		log:        cfg.Logger.With(slog.String("component", "healthcheck")),
 		client:     apiClient,
@@ -61,6 +70,28 @@ func NewService(cfg *HealthcheckServiceConfig) (*Service, error) {
 	}, nil
 }
 
+// newHeartbeatRelayClient returns a client sending requests through the proxy's heartbeat relay, keeping the
+// runner's credentials and identifying the node the runner runs on
+func newHeartbeatRelayClient(apiClient *apiclient.APIClient, heartbeatProxyUrl string, nodeName string) *apiclient.APIClient {
+	apiConfig := apiClient.GetConfig()
+
+	relayConfig := apiclient.NewConfiguration()
+	relayConfig.Servers = apiclient.ServerConfigurations{
+		{
+			URL: strings.TrimSuffix(heartbeatProxyUrl, "/") + common_tunnel.HeartbeatPathPrefix,
+		},
+	}
+	for key, value := range apiConfig.DefaultHeader {
+		relayConfig.AddDefaultHeader(key, value)
+	}
+	if nodeName != "" {
+		relayConfig.AddDefaultHeader(common_tunnel.NodeNameHeader, nodeName)
+	}
+	relayConfig.HTTPClient = apiConfig.HTTPClient
+
+	return apiclient.NewAPIClient(relayConfig)
+}
+
 // Start begins the healthcheck loop
 func (s *Service) Start(ctx context.Context) {
 	ticker := time.NewTicker(s.interval)
@@ -60,7 +60,9 @@ type Organization struct {
 	SandboxCreateRateLimit NullableFloat32 `json:"sandboxCreateRateLimit"`
 	// Sandbox lifecycle rate limit per minute
 	SandboxLifecycleRateLimit NullableFloat32 `json:"sandboxLifecycleRateLimit"`
-	AdditionalProperties      map[string]interface{}
+	// Preview traffic limit in bytes per second
+	MaxPreviewBandwidth  NullableFloat32 `json:"maxPreviewBandwidth,omitempty"`
+	AdditionalProperties map[string]interface{}
 }
 
 type _Organization Organization
@@ -570,6 +572,49 @@ func (o *Organization) SetSandboxLifecycleRateLimit(v float32) {
 	o.SandboxLifecycleRateLimit.Set(&v)
 }
 
+// GetMaxPreviewBandwidth returns the MaxPreviewBandwidth field value if set, zero value otherwise (both if not set or set to explicit null).
+func (o *Organization) GetMaxPreviewBandwidth() float32 {
+	if o == nil || IsNil(o.MaxPreviewBandwidth.Get()) {
+		var ret float32
+		return ret
+	}
+	return *o.MaxPreviewBandwidth.Get()
+}
+
+// GetMaxPreviewBandwidthOk returns a tuple with the MaxPreviewBandwidth field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+// NOTE: If the value is an explicit nil, `nil, true` will be returned
+func (o *Organization) GetMaxPreviewBandwidthOk() (*float32, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return o.MaxPreviewBandwidth.Get(), o.MaxPreviewBandwidth.IsSet()
+}
+
+// HasMaxPreviewBandwidth returns a boolean if a field has been set.
+func (o *Organization) HasMaxPreviewBandwidth() bool {
+	if o != nil && o.MaxPreviewBandwidth.IsSet() {
+		return true
+	}
+
+	return false
+}
+
+// SetMaxPreviewBandwidth gets a reference to the given NullableFloat32 and assigns it to the MaxPreviewBandwidth field.
+func (o *Organization) SetMaxPreviewBandwidth(v float32) {
+	o.MaxPreviewBandwidth.Set(&v)
+}
+
+// SetMaxPreviewBandwidthNil sets the value for MaxPreviewBandwidth to be an explicit nil
+func (o *Organization) SetMaxPreviewBandwidthNil() {
+	o.MaxPreviewBandwidth.Set(nil)
+}
+
+// UnsetMaxPreviewBandwidth ensures that no value is present for MaxPreviewBandwidth, not even an explicit nil
+func (o *Organization) UnsetMaxPreviewBandwidth() {
+	o.MaxPreviewBandwidth.Unset()
+}
+
 func (o Organization) MarshalJSON() ([]byte, error) {
 	toSerialize, err := o.ToMap()
 	if err != nil {
@@ -601,6 +646,9 @@ func (o Organization) ToMap() (map[string]interface{}, error) {
 	toSerialize["authenticatedRateLimit"] = o.AuthenticatedRateLimit.Get()
 	toSerialize["sandboxCreateRateLimit"] = o.SandboxCreateRateLimit.Get()
 	toSerialize["sandboxLifecycleRateLimit"] = o.SandboxLifecycleRateLimit.Get()
+	if o.MaxPreviewBandwidth.IsSet() {
+		toSerialize["maxPreviewBandwidth"] = o.MaxPreviewBandwidth.Get()
+	}
 
 	for key, value := range o.AdditionalProperties {
 		toSerialize[key] = value
@@ -680,6 +728,7 @@ func (o *Organization) UnmarshalJSON(data []byte) (err error) {
 		delete(additionalProperties, "authenticatedRateLimit")
 		delete(additionalProperties, "sandboxCreateRateLimit")
 		delete(additionalProperties, "sandboxLifecycleRateLimit")
+		delete(additionalProperties, "maxPreviewBandwidth")
 		o.AdditionalProperties = additionalProperties
 	}
 
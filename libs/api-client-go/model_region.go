@@ -38,7 +38,9 @@ type Region struct {
 	// SSH Gateway URL for the region
 	SshGatewayUrl NullableString `json:"sshGatewayUrl,omitempty"`
 	// Snapshot Manager URL for the region
-	SnapshotManagerUrl   NullableString `json:"snapshotManagerUrl,omitempty"`
+	SnapshotManagerUrl NullableString `json:"snapshotManagerUrl,omitempty"`
+	// Runner pool configuration expected from the runner-managers of the region
+	RunnerPool           NullableRegionRunnerPool `json:"runnerPool,omitempty"`
 	AdditionalProperties map[string]interface{}
 }
 
@@ -358,6 +360,49 @@ func (o *Region) UnsetSnapshotManagerUrl() {
 	o.SnapshotManagerUrl.Unset()
 }
 
+// GetRunnerPool returns the RunnerPool field value if set, zero value otherwise (both if not set or set to explicit null).
+func (o *Region) GetRunnerPool() RegionRunnerPool {
+	if o == nil || IsNil(o.RunnerPool.Get()) {
+		var ret RegionRunnerPool
+		return ret
+	}
+	return *o.RunnerPool.Get()
+}
+
+// GetRunnerPoolOk returns a tuple with the RunnerPool field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+// NOTE: If the value is an explicit nil, `nil, true` will be returned
+func (o *Region) GetRunnerPoolOk() (*RegionRunnerPool, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return o.RunnerPool.Get(), o.RunnerPool.IsSet()
+}
+
+// HasRunnerPool returns a boolean if a field has been set.
+func (o *Region) HasRunnerPool() bool {
+	if o != nil && o.RunnerPool.IsSet() {
+		return true
+	}
+
+	return false
+}
+
+// SetRunnerPool gets a reference to the given NullableRegionRunnerPool and assigns it to the RunnerPool field.
+func (o *Region) SetRunnerPool(v RegionRunnerPool) {
+	o.RunnerPool.Set(&v)
+}
+
+// SetRunnerPoolNil sets the value for RunnerPool to be an explicit nil
+func (o *Region) SetRunnerPoolNil() {
+	o.RunnerPool.Set(nil)
+}
+
+// UnsetRunnerPool ensures that no value is present for RunnerPool, not even an explicit nil
+func (o *Region) UnsetRunnerPool() {
+	o.RunnerPool.Unset()
+}
+
 func (o Region) MarshalJSON() ([]byte, error) {
 	toSerialize, err := o.ToMap()
 	if err != nil {
@@ -385,6 +430,9 @@ func (o Region) ToMap() (map[string]interface{}, error) {
 	if o.SnapshotManagerUrl.IsSet() {
 		toSerialize["snapshotManagerUrl"] = o.SnapshotManagerUrl.Get()
 	}
+	if o.RunnerPool.IsSet() {
+		toSerialize["runnerPool"] = o.RunnerPool.Get()
+	}
 
 	for key, value := range o.AdditionalProperties {
 		toSerialize[key] = value
@@ -441,6 +489,7 @@ func (o *Region) UnmarshalJSON(data []byte) (err error) {
 		delete(additionalProperties, "proxyUrl")
 		delete(additionalProperties, "sshGatewayUrl")
 		delete(additionalProperties, "snapshotManagerUrl")
+		delete(additionalProperties, "runnerPool")
 		o.AdditionalProperties = additionalProperties
 	}
 
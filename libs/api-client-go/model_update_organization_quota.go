@@ -30,6 +30,7 @@ type UpdateOrganizationQuota struct {
 	AuthenticatedRateLimit    NullableFloat32 `json:"authenticatedRateLimit"`
 	SandboxCreateRateLimit    NullableFloat32 `json:"sandboxCreateRateLimit"`
 	SandboxLifecycleRateLimit NullableFloat32 `json:"sandboxLifecycleRateLimit"`
+	MaxPreviewBandwidth       NullableFloat32 `json:"maxPreviewBandwidth,omitempty"`
 	AdditionalProperties      map[string]interface{}
 }
 
@@ -295,6 +296,49 @@ func (o *UpdateOrganizationQuota) SetSandboxLifecycleRateLimit(v float32) {
 	o.SandboxLifecycleRateLimit.Set(&v)
 }
 
+// GetMaxPreviewBandwidth returns the MaxPreviewBandwidth field value if set, zero value otherwise (both if not set or set to explicit null).
+func (o *UpdateOrganizationQuota) GetMaxPreviewBandwidth() float32 {
+	if o == nil || IsNil(o.MaxPreviewBandwidth.Get()) {
+		var ret float32
+		return ret
+	}
+	return *o.MaxPreviewBandwidth.Get()
+}
+
+// GetMaxPreviewBandwidthOk returns a tuple with the MaxPreviewBandwidth field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+// NOTE: If the value is an explicit nil, `nil, true` will be returned
+func (o *UpdateOrganizationQuota) GetMaxPreviewBandwidthOk() (*float32, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return o.MaxPreviewBandwidth.Get(), o.MaxPreviewBandwidth.IsSet()
+}
+
+// HasMaxPreviewBandwidth returns a boolean if a field has been set.
+func (o *UpdateOrganizationQuota) HasMaxPreviewBandwidth() bool {
+	if o != nil && o.MaxPreviewBandwidth.IsSet() {
+		return true
+	}
+
+	return false
+}
+
+// SetMaxPreviewBandwidth gets a reference to the given NullableFloat32 and assigns it to the MaxPreviewBandwidth field.
+func (o *UpdateOrganizationQuota) SetMaxPreviewBandwidth(v float32) {
+	o.MaxPreviewBandwidth.Set(&v)
+}
+
+// SetMaxPreviewBandwidthNil sets the value for MaxPreviewBandwidth to be an explicit nil
+func (o *UpdateOrganizationQuota) SetMaxPreviewBandwidthNil() {
+	o.MaxPreviewBandwidth.Set(nil)
+}
+
+// UnsetMaxPreviewBandwidth ensures that no value is present for MaxPreviewBandwidth, not even an explicit nil
+func (o *UpdateOrganizationQuota) UnsetMaxPreviewBandwidth() {
+	o.MaxPreviewBandwidth.Unset()
+}
+
 func (o UpdateOrganizationQuota) MarshalJSON() ([]byte, error) {
 	toSerialize, err := o.ToMap()
 	if err != nil {
@@ -314,6 +358,9 @@ func (o UpdateOrganizationQuota) ToMap() (map[string]interface{}, error) {
 	toSerialize["authenticatedRateLimit"] = o.AuthenticatedRateLimit.Get()
 	toSerialize["sandboxCreateRateLimit"] = o.SandboxCreateRateLimit.Get()
 	toSerialize["sandboxLifecycleRateLimit"] = o.SandboxLifecycleRateLimit.Get()
+	if o.MaxPreviewBandwidth.IsSet() {
+		toSerialize["maxPreviewBandwidth"] = o.MaxPreviewBandwidth.Get()
+	}
 
 	for key, value := range o.AdditionalProperties {
 		toSerialize[key] = value
@@ -374,6 +421,7 @@ func (o *UpdateOrganizationQuota) UnmarshalJSON(data []byte) (err error) {
 		delete(additionalProperties, "authenticatedRateLimit")
 		delete(additionalProperties, "sandboxCreateRateLimit")
 		delete(additionalProperties, "sandboxLifecycleRateLimit")
+		delete(additionalProperties, "maxPreviewBandwidth")
 		o.AdditionalProperties = additionalProperties
 	}
 
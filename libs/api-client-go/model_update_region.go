@@ -25,7 +25,9 @@ type UpdateRegion struct {
 	// SSH Gateway URL for the region
 	SshGatewayUrl NullableString `json:"sshGatewayUrl,omitempty"`
 	// Snapshot Manager URL for the region
-	SnapshotManagerUrl   NullableString `json:"snapshotManagerUrl,omitempty"`
+	SnapshotManagerUrl NullableString `json:"snapshotManagerUrl,omitempty"`
+	// Runner pool configuration expected from the runner-managers of the region
+	RunnerPool           NullableRegionRunnerPool `json:"runnerPool,omitempty"`
 	AdditionalProperties map[string]interface{}
 }
 
@@ -177,6 +179,49 @@ func (o *UpdateRegion) UnsetSnapshotManagerUrl() {
 	o.SnapshotManagerUrl.Unset()
 }
 
+// GetRunnerPool returns the RunnerPool field value if set, zero value otherwise (both if not set or set to explicit null).
+func (o *UpdateRegion) GetRunnerPool() RegionRunnerPool {
+	if o == nil || IsNil(o.RunnerPool.Get()) {
+		var ret RegionRunnerPool
+		return ret
+	}
+	return *o.RunnerPool.Get()
+}
+
+// GetRunnerPoolOk returns a tuple with the RunnerPool field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+// NOTE: If the value is an explicit nil, `nil, true` will be returned
+func (o *UpdateRegion) GetRunnerPoolOk() (*RegionRunnerPool, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return o.RunnerPool.Get(), o.RunnerPool.IsSet()
+}
+
+// HasRunnerPool returns a boolean if a field has been set.
+func (o *UpdateRegion) HasRunnerPool() bool {
+	if o != nil && o.RunnerPool.IsSet() {
+		return true
+	}
+
+	return false
+}
+
+// SetRunnerPool gets a reference to the given NullableRegionRunnerPool and assigns it to the RunnerPool field.
+func (o *UpdateRegion) SetRunnerPool(v RegionRunnerPool) {
+	o.RunnerPool.Set(&v)
+}
+
+// SetRunnerPoolNil sets the value for RunnerPool to be an explicit nil
+func (o *UpdateRegion) SetRunnerPoolNil() {
+	o.RunnerPool.Set(nil)
+}
+
+// UnsetRunnerPool ensures that no value is present for RunnerPool, not even an explicit nil
+func (o *UpdateRegion) UnsetRunnerPool() {
+	o.RunnerPool.Unset()
+}
+
 func (o UpdateRegion) MarshalJSON() ([]byte, error) {
 	toSerialize, err := o.ToMap()
 	if err != nil {
@@ -196,6 +241,9 @@ func (o UpdateRegion) ToMap() (map[string]interface{}, error) {
 	if o.SnapshotManagerUrl.IsSet() {
 		toSerialize["snapshotManagerUrl"] = o.SnapshotManagerUrl.Get()
 	}
+	if o.RunnerPool.IsSet() {
+		toSerialize["runnerPool"] = o.RunnerPool.Get()
+	}
 
 	for key, value := range o.AdditionalProperties {
 		toSerialize[key] = value
@@ -221,6 +269,7 @@ func (o *UpdateRegion) UnmarshalJSON(data []byte) (err error) {
 		delete(additionalProperties, "proxyUrl")
 		delete(additionalProperties, "sshGatewayUrl")
 		delete(additionalProperties, "snapshotManagerUrl")
+		delete(additionalProperties, "runnerPool")
 		o.AdditionalProperties = additionalProperties
 	}
 
@@ -106,6 +106,7 @@ export * from './rate-limit-entry'
 export * from './regenerate-api-key-response'
 export * from './region'
 export * from './region-quota'
+export * from './region-runner-pool'
 export * from './region-screenshot-response'
 export * from './region-type'
 export * from './region-usage-overview'
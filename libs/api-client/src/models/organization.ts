@@ -132,4 +132,10 @@ export interface Organization {
    * @memberof Organization
    */
   sandboxLifecycleRateLimit: number | null
+  /**
+   * Preview traffic limit in bytes per second
+   * @type {number}
+   * @memberof Organization
+   */
+  maxPreviewBandwidth?: number | null
 }
@@ -0,0 +1,63 @@
+/* tslint:disable */
+
+/**
+ * Daytona
+ * Daytona AI platform API Docs
+ *
+ * The version of the OpenAPI document: 1.0
+ * Contact: support@daytona.com
+ *
+ * NOTE: This class is auto generated by OpenAPI Generator (https://openapi-generator.tech).
+ * https://openapi-generator.tech
+ * Do not edit the class manually.
+ */
+
+/**
+ *
+ * @export
+ * @interface RegionRunnerPool
+ */
+export interface RegionRunnerPool {
+  /**
+   * Minimum number of idle runners
+   * @type {number}
+   * @memberof RegionRunnerPool
+   */
+  minIdleRunners?: number
+  /**
+   * Minimum idle CPU across the runners
+   * @type {number}
+   * @memberof RegionRunnerPool
+   */
+  minIdleCpu?: number
+  /**
+   * Minimum idle memory across the runners in GiB
+   * @type {number}
+   * @memberof RegionRunnerPool
+   */
+  minIdleMemory?: number
+  /**
+   * Resource utilization percent above which the pool scales up
+   * @type {number}
+   * @memberof RegionRunnerPool
+   */
+  maxResourceUtilizationPercent?: number
+  /**
+   * Node label key selecting the runner nodes
+   * @type {string}
+   * @memberof RegionRunnerPool
+   */
+  nodeSelectorKey?: string
+  /**
+   * Taint key reserving the runner nodes
+   * @type {string}
+   * @memberof RegionRunnerPool
+   */
+  taintKey?: string
+  /**
+   * Runner version the runners are rolled out to
+   * @type {string}
+   * @memberof RegionRunnerPool
+   */
+  runnerVersion?: string
+}
@@ -12,6 +12,9 @@
  * Do not edit the class manually.
  */
 
+// May contain unused imports in some cases
+// @ts-ignore
+import type { RegionRunnerPool } from './region-runner-pool'
 // May contain unused imports in some cases
 // @ts-ignore
 import type { RegionType } from './region-type'
@@ -76,4 +79,10 @@ export interface Region {
    * @memberof Region
    */
   snapshotManagerUrl?: string | null
+  /**
+   * Runner pool configuration expected from the runner-managers of the region
+   * @type {RegionRunnerPool}
+   * @memberof Region
+   */
+  runnerPool?: RegionRunnerPool | null
 }
@@ -72,4 +72,10 @@ export interface UpdateOrganizationQuota {
    * @memberof UpdateOrganizationQuota
    */
   sandboxLifecycleRateLimit: number | null
+  /**
+   *
+   * @type {number}
+   * @memberof UpdateOrganizationQuota
+   */
+  maxPreviewBandwidth?: number | null
 }
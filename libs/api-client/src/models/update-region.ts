@@ -12,6 +12,10 @@
  * Do not edit the class manually.
  */
 
+// May contain unused imports in some cases
+// @ts-ignore
+import type { RegionRunnerPool } from './region-runner-pool'
+
 /**
  *
  * @export
@@ -36,4 +40,10 @@ export interface UpdateRegion {
    * @memberof UpdateRegion
    */
   snapshotManagerUrl?: string | null
+  /**
+   * Runner pool configuration expected from the runner-managers of the region
+   * @type {RegionRunnerPool}
+   * @memberof UpdateRegion
+   */
+  runnerPool?: RegionRunnerPool | null
 }
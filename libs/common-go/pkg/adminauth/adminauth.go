@@ -0,0 +1,115 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+)
+
+// Config holds the admin authentication methods. Each method is enabled by setting its fields;
+// a request is authenticated when any enabled method accepts it.
+type Config struct {
+	// Token is a static bearer token
+	Token string
+	// ClientCAFile is a PEM bundle used to verify client certificates (mTLS)
+	ClientCAFile string
+	// JWKSUrl, Issuer and Audience verify bearer JWTs issued by the control plane
+	JWKSUrl  string
+	Issuer   string
+	Audience string
+}
+
+var ErrUnauthenticated = errors.New("admin authentication required")
+
+// ErrNotConfigured is returned for every request when no authentication method is configured, so admin endpoints
+// fail closed rather than open
+var ErrNotConfigured = errors.New("admin authentication is not configured")
+
+// Authenticator authenticates requests to admin endpoints
+type Authenticator struct {
+	token     string
+	clientCAs *x509.CertPool
+	verifier  *oidc.IDTokenVerifier
+}
+
+func NewAuthenticator(ctx context.Context, config Config) (*Authenticator, error) {
+	authenticator := &Authenticator{
+		token: config.Token,
+	}
+
+	if config.ClientCAFile != "" {
+		pem, err := os.ReadFile(config.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read admin client CA file: %w", err)
+		}
+		authenticator.clientCAs = x509.NewCertPool()
+		if !authenticator.clientCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in admin client CA file %s", config.ClientCAFile)
+		}
+	}
+
+	if config.JWKSUrl != "" {
+		if config.Issuer == "" || config.Audience == "" {
+			return nil, errors.New("admin JWT authentication requires an issuer and an audience")
+		}
+		keySet := oidc.NewRemoteKeySet(ctx, config.JWKSUrl)
+		authenticator.verifier = oidc.NewVerifier(config.Issuer, keySet, &oidc.Config{ClientID: config.Audience})
+	}
+
+	return authenticator, nil
+}
+
+// Enabled reports whether any authentication method is configured
+func (a *Authenticator) Enabled() bool {
+	return a.token != "" || a.clientCAs != nil || a.verifier != nil
+}
+
+// ConfigureTLS makes a TLS server request client certificates verified against the admin client CAs.
+// Certificates are optional at the handshake so endpoints outside the admin surface keep working without one.
+func (a *Authenticator) ConfigureTLS(tlsConfig *tls.Config) {
+	if a.clientCAs == nil {
+		return
+	}
+	tlsConfig.ClientCAs = a.clientCAs
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+}
+
+// Authenticate returns the principal of an authenticated request
+func (a *Authenticator) Authenticate(r *http.Request) (string, error) {
+	if !a.Enabled() {
+		return "", ErrNotConfigured
+	}
+
+	if a.clientCAs != nil && r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		return "mtls:" + r.TLS.VerifiedChains[0][0].Subject.CommonName, nil
+	}
+
+	bearer, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || bearer == "" {
+		return "", ErrUnauthenticated
+	}
+
+	if a.token != "" && subtle.ConstantTimeCompare([]byte(bearer), []byte(a.token)) == 1 {
+		return "token", nil
+	}
+
+	if a.verifier != nil {
+		idToken, err := a.verifier.Verify(r.Context(), bearer)
+		if err == nil {
+			return "jwt:" + idToken.Subject, nil
+		}
+	}
+
+	return "", ErrUnauthenticated
+}
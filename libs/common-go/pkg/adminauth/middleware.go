@@ -0,0 +1,79 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminauth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// statusRecorder captures the response status for the audit log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// rejectionStatus is the status of a request rejected with err: forbidden when no method is configured, as no
+// credentials would be accepted, unauthorized otherwise
+func rejectionStatus(err error) int {
+	if errors.Is(err, ErrNotConfigured) {
+		return http.StatusForbidden
+	}
+	return http.StatusUnauthorized
+}
+
+// Middleware protects a net/http handler and writes an audit log entry for every request once it is handled.
+// Requests are rejected when no authentication method is configured.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := a.Authenticate(r)
+		if err != nil {
+			status := rejectionStatus(err)
+			audit(r, "", status)
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		audit(r, principal, recorder.status)
+	})
+}
+
+// GinHandler protects a gin handler and writes an audit log entry for every request once it is handled, with the
+// status the handler responded with. Requests are rejected when no authentication method is configured.
+func (a *Authenticator) GinHandler(handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		principal, err := a.Authenticate(ctx.Request)
+		if err != nil {
+			status := rejectionStatus(err)
+			audit(ctx.Request, "", status)
+			ctx.AbortWithStatusJSON(status, gin.H{"message": err.Error()})
+			return
+		}
+
+		handler(ctx)
+		audit(ctx.Request, principal, ctx.Writer.Status())
+	}
+}
+
+func audit(r *http.Request, principal string, status int) {
+	log.WithFields(log.Fields{
+		"audit":     true,
+		"principal": principal,
+		"method":    r.Method,
+		"path":      r.URL.Path,
+		"remote":    r.RemoteAddr,
+		"status":    status,
+	}).Info("Admin request")
+}
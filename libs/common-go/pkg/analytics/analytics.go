@@ -0,0 +1,178 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package analytics aggregates preview usage patterns into differentially private reports. Client identifiers
+// are only held as hashes salted per report window, and every exported count carries Laplace noise calibrated
+// to how much a single client can contribute, so a report reveals usage patterns but not individual clients.
+package analytics
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"math"
+	mathrand "math/rand/v2"
+	"sync"
+	"time"
+)
+
+// ReportPath is the endpoint analytics reports are posted to and served from
+const ReportPath = "/analytics-reports"
+
+// SessionLengthBuckets are the upper bounds of the session length buckets, sessions longer than the last bound
+// are counted in a final bucket
+var SessionLengthBuckets = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute, time.Hour, 4 * time.Hour}
+
+// Config bounds the privacy loss of each report
+type Config struct {
+	// Epsilon is the privacy budget of a report, split evenly between its two histograms
+	Epsilon float64
+	// SessionIdleTimeout ends a client's session after this long without requests
+	SessionIdleTimeout time.Duration
+	// MaxSessionsPerClient caps the sessions a client contributes to a report, which bounds the noise needed
+	MaxSessionsPerClient int
+}
+
+// Report is the noisy usage of a proxy instance within a window. Counts are rounded and never negative.
+type Report struct {
+	Source      string    `json:"source"`
+	WindowStart time.Time `json:"windowStart"`
+	WindowEnd   time.Time `json:"windowEnd"`
+	Epsilon     float64   `json:"epsilon"`
+	// ActiveClientsByHour is the number of distinct clients active in each UTC hour of the day, for peak hours
+	ActiveClientsByHour [24]int64 `json:"activeClientsByHour"`
+	// SessionLengthBucketsSec are the upper bounds of SessionLengths in seconds
+	SessionLengthBucketsSec []int64 `json:"sessionLengthBucketsSec"`
+	// SessionLengths counts sessions by length, with a final bucket for sessions longer than the last bound
+	SessionLengths []int64 `json:"sessionLengths"`
+}
+
+type clientActivity struct {
+	hours        [24]bool
+	sessionStart time.Time
+	lastSeen     time.Time
+	sessions     []time.Duration
+}
+
+// Aggregator accumulates preview usage until it is flushed into a report
+type Aggregator struct {
+	mu          sync.Mutex
+	config      Config
+	salt        []byte
+	windowStart time.Time
+	clients     map[string]*clientActivity
+}
+
+func NewAggregator(config Config) *Aggregator {
+	a := &Aggregator{config: config}
+	a.resetWindow(time.Now())
+	return a
+}
+
+func (a *Aggregator) resetWindow(now time.Time) {
+	a.salt = make([]byte, 32)
+	_, _ = rand.Read(a.salt)
+	a.windowStart = now
+	a.clients = make(map[string]*clientActivity)
+}
+
+// Record accounts a request of the client. The identifier is hashed with the window's salt right away, so raw
+// identifiers are never stored and hashes cannot be linked across windows since the salt is discarded on flush.
+func (a *Aggregator) Record(clientId string, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(clientId))
+	key := string(mac.Sum(nil)[:16])
+
+	client, found := a.clients[key]
+	if !found {
+		client = &clientActivity{sessionStart: now}
+		a.clients[key] = client
+	} else if now.Sub(client.lastSeen) > a.config.SessionIdleTimeout {
+		a.endSession(client)
+		client.sessionStart = now
+	}
+	client.lastSeen = now
+	client.hours[now.UTC().Hour()] = true
+}
+
+func (a *Aggregator) endSession(client *clientActivity) {
+	if len(client.sessions) < a.config.MaxSessionsPerClient {
+		client.sessions = append(client.sessions, client.lastSeen.Sub(client.sessionStart))
+	}
+}
+
+// Flush returns the noisy usage recorded since the previous flush and starts a new window. Sessions still open
+// are counted up to their last request, a session spanning two windows is reported as two.
+func (a *Aggregator) Flush(source string, now time.Time) *Report {
+	a.mu.Lock()
+	windowStart, clients := a.windowStart, a.clients
+	a.resetWindow(now)
+	a.mu.Unlock()
+
+	var activeByHour [24]int64
+	sessionLengths := make([]int64, len(SessionLengthBuckets)+1)
+	for _, client := range clients {
+		for hour, active := range client.hours {
+			if active {
+				activeByHour[hour]++
+			}
+		}
+
+		a.endSession(client)
+		for _, length := range client.sessions {
+			bucket := len(SessionLengthBuckets)
+			for i, bound := range SessionLengthBuckets {
+				if length <= bound {
+					bucket = i
+					break
+				}
+			}
+			sessionLengths[bucket]++
+		}
+	}
+
+	// A client is active in at most one hour bucket per started hour of the window, and every hour bucket once
+	hourSensitivity := math.Min(24, math.Ceil(now.Sub(windowStart).Hours())+1)
+	sessionSensitivity := float64(max(a.config.MaxSessionsPerClient, 1))
+	histogramEpsilon := a.config.Epsilon / 2
+
+	report := &Report{
+		Source:                  source,
+		WindowStart:             windowStart,
+		WindowEnd:               now,
+		Epsilon:                 a.config.Epsilon,
+		SessionLengthBucketsSec: make([]int64, len(SessionLengthBuckets)),
+		SessionLengths:          make([]int64, len(sessionLengths)),
+	}
+	for hour, count := range activeByHour {
+		report.ActiveClientsByHour[hour] = noisyCount(count, hourSensitivity/histogramEpsilon)
+	}
+	for i, bound := range SessionLengthBuckets {
+		report.SessionLengthBucketsSec[i] = int64(bound.Seconds())
+	}
+	for i, count := range sessionLengths {
+		report.SessionLengths[i] = noisyCount(count, sessionSensitivity/histogramEpsilon)
+	}
+
+	return report
+}
+
+// noisyCount adds Laplace noise of the given scale to the count. Rounding and clamping at zero are
+// post-processing and do not weaken the privacy guarantee.
+func noisyCount(count int64, scale float64) int64 {
+	return max(0, int64(math.Round(float64(count)+laplace(scale))))
+}
+
+func laplace(scale float64) float64 {
+	u := mathrand.Float64() - 0.5
+	for u == -0.5 {
+		u = mathrand.Float64() - 0.5
+	}
+	if u < 0 {
+		return scale * math.Log(1+2*u)
+	}
+	return -scale * math.Log(1-2*u)
+}
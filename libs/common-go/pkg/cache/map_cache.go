@@ -11,26 +11,39 @@ import (
 	cmap "github.com/orcaman/concurrent-map/v2"
 )
 
+type mapCacheEntry[T any] struct {
+	value     T
+	expiresAt time.Time
+}
+
+func (e mapCacheEntry[T]) expired() bool {
+	return !e.expiresAt.IsZero() && time.Now().After(e.expiresAt)
+}
+
 type MapCache[T any] struct {
-	cacheMap cmap.ConcurrentMap[string, T]
+	cacheMap cmap.ConcurrentMap[string, mapCacheEntry[T]]
 }
 
 func (c *MapCache[T]) Set(ctx context.Context, key string, value T, expiration time.Duration) error {
-	c.cacheMap.Set(key, value)
+	entry := mapCacheEntry[T]{value: value}
+	if expiration > 0 {
+		entry.expiresAt = time.Now().Add(expiration)
+	}
+	c.cacheMap.Set(key, entry)
 	return nil
 }
 
 func (c *MapCache[T]) Has(ctx context.Context, key string) (bool, error) {
-	_, ok := c.cacheMap.Get(key)
+	_, ok := c.get(key)
 	return ok, nil
 }
 
 func (c *MapCache[T]) Get(ctx context.Context, key string) (*T, error) {
-	value, ok := c.cacheMap.Get(key)
+	entry, ok := c.get(key)
 	if !ok {
 		return nil, errors.New("key not found")
 	}
-	return &value, nil
+	return &entry.value, nil
 }
 
 func (c *MapCache[T]) Delete(ctx context.Context, key string) error {
@@ -38,8 +51,22 @@ func (c *MapCache[T]) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+func (c *MapCache[T]) get(key string) (mapCacheEntry[T], bool) {
+	entry, ok := c.cacheMap.Get(key)
+	if !ok {
+		return entry, false
+	}
+	if entry.expired() {
+		c.cacheMap.RemoveCb(key, func(_ string, current mapCacheEntry[T], exists bool) bool {
+			return exists && current.expired()
+		})
+		return entry, false
+	}
+	return entry, true
+}
+
 func NewMapCache[T any]() *MapCache[T] {
 	return &MapCache[T]{
-		cacheMap: cmap.New[T](),
+		cacheMap: cmap.New[mapCacheEntry[T]](),
 	}
 }
@@ -0,0 +1,55 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package directive
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// Action is a change of the active directives
+type Action string
+
+const (
+	ActionApplied Action = "applied"
+	ActionLifted  Action = "lifted"
+	ActionExpired Action = "expired"
+)
+
+// AuditRecord is a single change of the active directives as seen by a component
+type AuditRecord struct {
+	Time   time.Time `json:"time"`
+	Action Action    `json:"action"`
+	// Source is how the change was received, "push" or "poll"
+	Source    string    `json:"source"`
+	Directive Directive `json:"directive"`
+}
+
+// AuditLog appends audit records as JSON lines to a local file
+type AuditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func OpenAuditLog(path string) (*AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLog{file: file}, nil
+}
+
+func (a *AuditLog) Write(record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, err = a.file.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,228 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package directive
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Path is the admin endpoint of the proxy and runner-manager the control plane pushes directives to
+	Path = "/directives"
+
+	// DefaultPollInterval is how often the directive set is fetched from the control plane when polling is configured
+	DefaultPollInterval = 5 * time.Second
+
+	// PushGracePeriod is how long a directive applied or lifted by a push is kept that way by polls that do not
+	// reflect the push yet
+	PushGracePeriod = time.Minute
+)
+
+// Kind is the emergency action a directive enforces
+type Kind string
+
+const (
+	// KindBlockSandboxPreview rejects all preview traffic of the sandbox identified by the target
+	KindBlockSandboxPreview Kind = "block-sandbox-preview"
+	// KindFreezeScaleDown stops runner-manager from removing nodes in the region identified by the target
+	KindFreezeScaleDown Kind = "freeze-scale-down"
+)
+
+// Directive is an emergency instruction issued by the control plane
+type Directive struct {
+	Id   string `json:"id"`
+	Kind Kind   `json:"kind"`
+	// Target is the sandbox ID for block-sandbox-preview and the region ID for freeze-scale-down
+	Target    string     `json:"target"`
+	Reason    string     `json:"reason,omitempty"`
+	IssuedBy  string     `json:"issuedBy,omitempty"`
+	IssuedAt  time.Time  `json:"issuedAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Set is the complete set of active directives. Every push or poll replaces the previous set, so lifting a
+// directive is done by leaving it out. Until a poll confirms a push, or for PushGracePeriod at most, it keeps the
+// directives the push applied and leaves out the ones it lifted, so a poll lagging behind the push does not undo it.
+type Set struct {
+	Directives []Directive `json:"directives"`
+}
+
+func (d *Directive) validate() error {
+	if d.Id == "" {
+		return errors.New("directive id is required")
+	}
+	if d.Kind != KindBlockSandboxPreview && d.Kind != KindFreezeScaleDown {
+		return fmt.Errorf("directive %s has unknown kind %q", d.Id, d.Kind)
+	}
+	if d.Target == "" {
+		return fmt.Errorf("directive %s has no target", d.Id)
+	}
+	return nil
+}
+
+func (d *Directive) expired(now time.Time) bool {
+	return d.ExpiresAt != nil && !now.Before(*d.ExpiresAt)
+}
+
+// Store holds the active directives of a component and records every change to the audit log
+type Store struct {
+	mu     sync.RWMutex
+	active map[string]Directive
+	// pushed holds the directives applied or lifted by a push that no poll has confirmed yet, by id
+	pushed map[string]pushedDirective
+	audit  *AuditLog
+}
+
+// pushedDirective is a directive applied or lifted by a push at the given time
+type pushedDirective struct {
+	directive Directive
+	applied   bool
+	at        time.Time
+}
+
+func NewStore(audit *AuditLog) *Store {
+	return &Store{
+		active: make(map[string]Directive),
+		pushed: make(map[string]pushedDirective),
+		audit:  audit,
+	}
+}
+
+// Replace makes the given set the active directives, auditing each directive applied or lifted. A polled set keeps
+// the changes of a recent push it does not reflect yet.
+func (s *Store) Replace(set Set, source string) error {
+	for i := range set.Directives {
+		if err := set.Directives[i].validate(); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	incoming := make(map[string]Directive, len(set.Directives))
+	for _, directive := range set.Directives {
+		incoming[directive.Id] = directive
+	}
+	if source == SourcePush {
+		s.trackPush(incoming, now)
+	} else {
+		s.mergePushed(incoming, now)
+	}
+
+	next := make(map[string]Directive, len(incoming))
+	for id, directive := range incoming {
+		if directive.expired(now) {
+			continue
+		}
+		next[id] = directive
+		if _, found := s.active[id]; !found {
+			s.record(ActionApplied, source, directive)
+		}
+	}
+
+	for id, directive := range s.active {
+		if _, found := next[id]; found {
+			continue
+		}
+		if directive.expired(now) {
+			s.record(ActionExpired, source, directive)
+		} else {
+			s.record(ActionLifted, source, directive)
+		}
+	}
+
+	s.active = next
+	publishMetrics(s.active)
+	return nil
+}
+
+// trackPush remembers the directives a pushed set applies and lifts, so polls do not undo them before they reflect
+// the push
+func (s *Store) trackPush(incoming map[string]Directive, now time.Time) {
+	for id, pushed := range s.pushed {
+		if now.Sub(pushed.at) >= PushGracePeriod {
+			delete(s.pushed, id)
+		}
+	}
+	for id, directive := range incoming {
+		s.pushed[id] = pushedDirective{directive: directive, applied: true, at: now}
+	}
+	for id, directive := range s.active {
+		if _, found := incoming[id]; !found {
+			s.pushed[id] = pushedDirective{directive: directive, applied: false, at: now}
+		}
+	}
+}
+
+// mergePushed keeps the pushed directives a polled set does not reflect yet. A push is forgotten once a poll agrees
+// with it or after PushGracePeriod, from then on polls alone decide.
+func (s *Store) mergePushed(incoming map[string]Directive, now time.Time) {
+	for id, pushed := range s.pushed {
+		_, polled := incoming[id]
+		if polled == pushed.applied || now.Sub(pushed.at) >= PushGracePeriod {
+			delete(s.pushed, id)
+			continue
+		}
+		if pushed.applied {
+			incoming[id] = pushed.directive
+		} else {
+			delete(incoming, id)
+		}
+	}
+}
+
+// Active returns the unexpired directive of the kind targeting target, or nil if there is none
+func (s *Store) Active(kind Kind, target string) *Directive {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	for _, directive := range s.active {
+		if directive.Kind == kind && directive.Target == target && !directive.expired(now) {
+			return &directive
+		}
+	}
+	return nil
+}
+
+// List returns the unexpired directives, oldest first
+func (s *Store) List() []Directive {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	directives := make([]Directive, 0, len(s.active))
+	for _, directive := range s.active {
+		if !directive.expired(now) {
+			directives = append(directives, directive)
+		}
+	}
+	sort.Slice(directives, func(i, j int) bool { return directives[i].IssuedAt.Before(directives[j].IssuedAt) })
+	return directives
+}
+
+func (s *Store) record(action Action, source string, directive Directive) {
+	log.WithFields(log.Fields{
+		"directiveId": directive.Id,
+		"kind":        directive.Kind,
+		"target":      directive.Target,
+		"reason":      directive.Reason,
+		"issuedBy":    directive.IssuedBy,
+		"source":      source,
+	}).Warnf("Directive %s", action)
+
+	if s.audit != nil {
+		if err := s.audit.Write(AuditRecord{Time: time.Now(), Action: action, Source: source, Directive: directive}); err != nil {
+			log.WithField("directiveId", directive.Id).WithError(err).Error("Failed to write directive audit record")
+		}
+	}
+}
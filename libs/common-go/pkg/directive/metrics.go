@@ -0,0 +1,27 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package directive
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var activeDirectives = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "directives_active",
+		Help: "Number of active control plane directives per kind",
+	},
+	[]string{"kind"},
+)
+
+func publishMetrics(active map[string]Directive) {
+	counts := map[Kind]int{KindBlockSandboxPreview: 0, KindFreezeScaleDown: 0}
+	for _, directive := range active {
+		counts[directive.Kind]++
+	}
+	for kind, count := range counts {
+		activeDirectives.WithLabelValues(string(kind)).Set(float64(count))
+	}
+}
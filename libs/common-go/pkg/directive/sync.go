@@ -0,0 +1,94 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package directive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	SourcePush = "push"
+	SourcePoll = "poll"
+)
+
+// Poll replaces the active directives with the set served at url every interval until the context is done.
+// Failed fetches keep the previous set, so a control plane outage neither applies nor lifts directives. A recent push
+// the fetched set does not reflect yet is kept, see Set.
+func (s *Store) Poll(ctx context.Context, url string, token string, interval time.Duration) {
+	httpClient := &http.Client{Timeout: interval}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		set, err := fetch(ctx, httpClient, url, token)
+		if err != nil {
+			log.WithError(err).Warn("Failed to fetch directives")
+		} else if err := s.Replace(*set, SourcePoll); err != nil {
+			log.WithError(err).Warn("Rejected fetched directives")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func fetch(ctx context.Context, httpClient *http.Client, url string, token string) (*Set, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("directives endpoint returned status %d", resp.StatusCode)
+	}
+
+	var set Set
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("invalid directive set: %w", err)
+	}
+	return &set, nil
+}
+
+// Handler serves the active directives on GET and replaces them with a pushed set on PUT
+func (s *Store) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var set Set
+			if err := json.NewDecoder(r.Body).Decode(&set); err != nil {
+				http.Error(w, "invalid directive set", http.StatusBadRequest)
+				return
+			}
+			if err := s.Replace(set, SourcePush); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Set{Directives: s.List()})
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package eviction
+
+import "time"
+
+const (
+	// NoticePath is the proxy endpoint runner-manager posts eviction notices to
+	NoticePath = "/eviction-notices"
+
+	// EvictionAtHeader is set on preview responses of sandboxes whose node is scheduled for removal
+	EvictionAtHeader = "X-Daytona-Eviction-At"
+
+	// NoticeTTL is how long a notice is honored after it was received; runner-manager
+	// re-sends active notices every cycle, so cancelled removals disappear on their own
+	NoticeTTL = 5 * time.Minute
+)
+
+// Notice announces that the node hosting a sandbox is going to be removed
+type Notice struct {
+	SandboxId  string    `json:"sandboxId"`
+	EvictionAt time.Time `json:"evictionAt"`
+}
+
+// NoticeBatch is the set of eviction notices a runner-manager instance currently holds
+type NoticeBatch struct {
+	Source  string   `json:"source"`
+	Notices []Notice `json:"notices"`
+}
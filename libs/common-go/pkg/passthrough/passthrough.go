@@ -0,0 +1,113 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package passthrough implements TLS passthrough of sandbox ports: the proxy routes TLS connections by their SNI
+// without terminating them and the runner splices them to the sandbox port, so the application's own certificate
+// and client certificate checks apply end to end.
+package passthrough
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// PortsLabel is the sandbox label listing the comma separated ports exposed with TLS passthrough
+	PortsLabel = "daytona.io/tls-passthrough-ports"
+
+	// UpgradeProtocol is the Upgrade header value of runner requests switching to a raw TCP stream
+	UpgradeProtocol = "daytona-tcp"
+)
+
+// errHelloRead aborts the handshake once the ClientHello was read
+var errHelloRead = errors.New("client hello read")
+
+// Ports returns the TLS passthrough ports listed in the sandbox labels, ignoring invalid entries
+func Ports(labels map[string]string) []int {
+	var ports []int
+	for _, value := range strings.Split(labels[PortsLabel], ",") {
+		port, err := strconv.Atoi(strings.TrimSpace(value))
+		if err == nil && port > 0 && port <= 65535 {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+// TunnelPath is the runner endpoint upgrading to a TCP stream to the sandbox port
+func TunnelPath(sandboxId string, port int) string {
+	return fmt.Sprintf("/sandboxes/%s/tcp/%d", sandboxId, port)
+}
+
+// PeekServerName reads the TLS ClientHello from the connection and returns its SNI server name along with a
+// connection replaying the bytes read, so the handshake can still be completed or passed through
+func PeekServerName(conn net.Conn, timeout time.Duration) (string, net.Conn, error) {
+	var peeked bytes.Buffer
+	var serverName string
+
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return "", nil, err
+	}
+	err := tls.Server(readOnlyConn{Conn: conn, reader: io.TeeReader(conn, &peeked)}, &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverName = hello.ServerName
+			return nil, errHelloRead
+		},
+	}).Handshake()
+	if resetErr := conn.SetReadDeadline(time.Time{}); resetErr != nil {
+		return "", nil, resetErr
+	}
+
+	replaying := &replayConn{Conn: conn, reader: io.MultiReader(&peeked, conn)}
+	if !errors.Is(err, errHelloRead) {
+		return "", replaying, fmt.Errorf("failed to read TLS client hello: %w", err)
+	}
+	return serverName, replaying, nil
+}
+
+// Splice copies between the connections in both directions until one side closes, then closes both
+func Splice(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	copyAndSignal := func(dst, src net.Conn) {
+		_, _ = io.Copy(dst, src)
+		done <- struct{}{}
+	}
+
+	go copyAndSignal(a, b)
+	go copyAndSignal(b, a)
+
+	<-done
+	a.Close()
+	b.Close()
+	<-done
+}
+
+// readOnlyConn lets a TLS server read the ClientHello without anything being written back to the client
+type readOnlyConn struct {
+	net.Conn
+	reader io.Reader
+}
+
+func (c readOnlyConn) Read(b []byte) (int, error)       { return c.reader.Read(b) }
+func (c readOnlyConn) Write(b []byte) (int, error)      { return 0, io.ErrClosedPipe }
+func (c readOnlyConn) Close() error                     { return nil }
+func (c readOnlyConn) SetDeadline(time.Time) error      { return nil }
+func (c readOnlyConn) SetReadDeadline(time.Time) error  { return nil }
+func (c readOnlyConn) SetWriteDeadline(time.Time) error { return nil }
+
+// replayConn reads the peeked bytes before the rest of the connection
+type replayConn struct {
+	net.Conn
+	reader io.Reader
+}
+
+func (c *replayConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
@@ -0,0 +1,32 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package protection
+
+import (
+	"encoding/json"
+)
+
+const (
+	// DoNotDisturbKey marks a sandbox (label) or pool node (annotation) that must not be interrupted by scale-down
+	DoNotDisturbKey = "daytona.io/do-not-disturb"
+
+	// DoNotDisturbValue is the only value of DoNotDisturbKey that enables the flag, the Daytona API label filter
+	// matches it exactly
+	DoNotDisturbValue = "true"
+
+	// DoNotDisturbHeader is set on preview responses of protected sandboxes so clients can show a banner
+	DoNotDisturbHeader = "X-Daytona-Do-Not-Disturb"
+)
+
+// IsDoNotDisturb reports whether the given labels or annotations carry the do-not-disturb flag. Only
+// DoNotDisturbValue enables it, so the proxy and runner-manager agree with the Daytona API label filter.
+func IsDoNotDisturb(values map[string]string) bool {
+	return values[DoNotDisturbKey] == DoNotDisturbValue
+}
+
+// DoNotDisturbLabelFilter returns the JSON encoded labels filter matching protected sandboxes in the Daytona API
+func DoNotDisturbLabelFilter() string {
+	filter, _ := json.Marshal(map[string]string{DoNotDisturbKey: DoNotDisturbValue})
+	return string(filter)
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package quota
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// bandwidthBucketIdleTTL is how long an untouched bucket is kept before it is swept. An idle bucket refills within a
+// second, so starting over with a full one paces the traffic the same.
+const bandwidthBucketIdleTTL = 10 * time.Minute
+
+type byteBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	lastSeen time.Time
+}
+
+// BandwidthLimiter paces byte streams per organization using a token bucket holding one second worth of traffic
+type BandwidthLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*byteBucket
+	lastSweep time.Time
+}
+
+func NewBandwidthLimiter() *BandwidthLimiter {
+	return &BandwidthLimiter{
+		buckets:   make(map[string]*byteBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// WaitN blocks until n bytes may be sent for the organization at bytesPerSecond.
+// n must not exceed bytesPerSecond.
+func (l *BandwidthLimiter) WaitN(ctx context.Context, organizationId string, bytesPerSecond int64, n int) error {
+	bucket := l.bucket(organizationId, bytesPerSecond)
+	rate := float64(bytesPerSecond)
+
+	for {
+		bucket.mu.Lock()
+		now := time.Now()
+		bucket.tokens = min(rate, bucket.tokens+now.Sub(bucket.lastSeen).Seconds()*rate)
+		bucket.lastSeen = now
+
+		if bucket.tokens >= float64(n) {
+			bucket.tokens -= float64(n)
+			bucket.mu.Unlock()
+			return nil
+		}
+
+		delay := time.Duration((float64(n) - bucket.tokens) / rate * float64(time.Second))
+		bucket.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+func (l *BandwidthLimiter) bucket(organizationId string, bytesPerSecond int64) *byteBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(time.Now())
+
+	bucket, ok := l.buckets[organizationId]
+	if !ok {
+		bucket = &byteBucket{tokens: float64(bytesPerSecond), lastSeen: time.Now()}
+		l.buckets[organizationId] = bucket
+	}
+	return bucket
+}
+
+// sweep drops buckets that have not been used recently so the limiter does not grow unbounded.
+// Must be called with l.mu held.
+func (l *BandwidthLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < bandwidthBucketIdleTTL {
+		return
+	}
+	l.lastSweep = now
+	for organizationId, bucket := range l.buckets {
+		bucket.mu.Lock()
+		idle := now.Sub(bucket.lastSeen)
+		bucket.mu.Unlock()
+		if idle > bandwidthBucketIdleTTL {
+			delete(l.buckets, organizationId)
+		}
+	}
+}
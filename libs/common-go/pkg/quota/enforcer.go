@@ -0,0 +1,58 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package quota
+
+import (
+	"context"
+	"time"
+
+	common_cache "github.com/daytonaio/common-go/pkg/cache"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Enforcer resolves organization quotas through a cache so that every consumer applies the same limits
+type Enforcer struct {
+	source    IQuotaSource
+	cache     common_cache.ICache[OrgQuota]
+	cacheTTL  time.Duration
+	bandwidth *BandwidthLimiter
+}
+
+func NewEnforcer(source IQuotaSource, cache common_cache.ICache[OrgQuota], cacheTTL time.Duration) *Enforcer {
+	return &Enforcer{
+		source:    source,
+		cache:     cache,
+		cacheTTL:  cacheTTL,
+		bandwidth: NewBandwidthLimiter(),
+	}
+}
+
+func (e *Enforcer) GetOrgQuota(ctx context.Context, organizationId string) (*OrgQuota, error) {
+	has, err := e.cache.Has(ctx, organizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	if has {
+		return e.cache.Get(ctx, organizationId)
+	}
+
+	quota, err := e.source.GetOrgQuota(ctx, organizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = e.cache.Set(ctx, organizationId, *quota, e.cacheTTL)
+	if err != nil {
+		log.Errorf("Failed to set organization quota in cache: %v", err)
+	}
+
+	return quota, nil
+}
+
+// Bandwidth returns the limiter shared by all preview traffic of the process
+func (e *Enforcer) Bandwidth() *BandwidthLimiter {
+	return e.bandwidth
+}
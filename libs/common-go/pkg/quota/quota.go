@@ -0,0 +1,44 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package quota
+
+// OrgQuota holds the limits enforced for a single organization. A zero limit means unlimited.
+type OrgQuota struct {
+	OrganizationId string `json:"organizationId"`
+	// MaxConcurrentSandboxes is the maximum number of sandboxes the organization may run at once
+	MaxConcurrentSandboxes int `json:"maxConcurrentSandboxes"`
+	// MaxPreviewBandwidth is the maximum preview traffic rate in bytes per second
+	MaxPreviewBandwidth int64 `json:"maxPreviewBandwidth"`
+	// MaxPreviewSessions is the maximum number of simultaneously active preview sessions across the organization's sandboxes
+	MaxPreviewSessions int `json:"maxPreviewSessions"`
+	// MaxReservedCpu is the maximum number of CPUs the organization may have allocated in the region
+	MaxReservedCpu float32 `json:"maxReservedCpu"`
+}
+
+// Usage is the current consumption of an organization measured against its quota
+type Usage struct {
+	ConcurrentSandboxes int
+	ReservedCpu         float32
+}
+
+// CountableFraction returns the share of the usage that falls within the quota, between 0 and 1.
+// Consumers scale the organization's demand by this fraction so over-quota demand is not acted upon.
+func (q *OrgQuota) CountableFraction(usage Usage) float32 {
+	fraction := float32(1)
+
+	if q.MaxConcurrentSandboxes > 0 && usage.ConcurrentSandboxes > q.MaxConcurrentSandboxes {
+		fraction = min(fraction, float32(q.MaxConcurrentSandboxes)/float32(usage.ConcurrentSandboxes))
+	}
+
+	if q.MaxReservedCpu > 0 && usage.ReservedCpu > q.MaxReservedCpu {
+		fraction = min(fraction, q.MaxReservedCpu/usage.ReservedCpu)
+	}
+
+	return fraction
+}
+
+// IsOverQuota reports whether any part of the usage exceeds the quota
+func (q *OrgQuota) IsOverQuota(usage Usage) bool {
+	return q.CountableFraction(usage) < 1
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package quota
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	apiclient "github.com/daytonaio/daytona/libs/api-client-go"
+)
+
+// PlanMaxPreviewSessionsProperty is the organization property holding the preview session limit of its plan
+const PlanMaxPreviewSessionsProperty = "maxPreviewSessions"
+
+type IQuotaSource interface {
+	GetOrgQuota(ctx context.Context, organizationId string) (*OrgQuota, error)
+}
+
+// APIQuotaSource reads organization quotas from the Daytona API. Limits the API does not
+// expose yet (concurrent sandboxes) are taken from the defaults.
+type APIQuotaSource struct {
+	apiClient  *apiclient.APIClient
+	regionId   string
+	defaults   OrgQuota
+	planLimits bool
+}
+
+// NewAPIQuotaSource creates a quota source. When regionId is empty the region CPU quota is not fetched.
+func NewAPIQuotaSource(apiClient *apiclient.APIClient, regionId string, defaults OrgQuota) *APIQuotaSource {
+	return &APIQuotaSource{
+		apiClient: apiClient,
+		regionId:  regionId,
+		defaults:  defaults,
+	}
+}
+
+// WithPlanLimits makes the source read the limits set on the organization, its preview bandwidth quota and the
+// preview session limit of its plan, falling back to the defaults for organizations without them
+func (s *APIQuotaSource) WithPlanLimits() *APIQuotaSource {
+	s.planLimits = true
+	return s
+}
+
+func (s *APIQuotaSource) GetOrgQuota(ctx context.Context, organizationId string) (*OrgQuota, error) {
+	quota := s.defaults
+	quota.OrganizationId = organizationId
+
+	if s.planLimits {
+		organization, _, err := s.apiClient.OrganizationsAPI.GetOrganization(ctx, organizationId).Execute()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get organization %s: %w", organizationId, err)
+		}
+		if maxPreviewSessions, ok := organization.AdditionalProperties[PlanMaxPreviewSessionsProperty].(float64); ok && maxPreviewSessions >= 0 {
+			quota.MaxPreviewSessions = int(math.Round(maxPreviewSessions))
+		}
+		if maxPreviewBandwidth, ok := organization.GetMaxPreviewBandwidthOk(); ok && maxPreviewBandwidth != nil && *maxPreviewBandwidth >= 0 {
+			quota.MaxPreviewBandwidth = int64(*maxPreviewBandwidth)
+		}
+	}
+
+	if s.regionId == "" {
+		return &quota, nil
+	}
+
+	overview, _, err := s.apiClient.OrganizationsAPI.GetOrganizationUsageOverview(ctx, organizationId).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage overview for organization %s: %w", organizationId, err)
+	}
+
+	for _, regionUsage := range overview.RegionUsage {
+		if regionUsage.RegionId == s.regionId {
+			quota.MaxReservedCpu = regionUsage.TotalCpuQuota
+			break
+		}
+	}
+
+	return &quota, nil
+}
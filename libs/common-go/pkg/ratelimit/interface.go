@@ -0,0 +1,38 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Result describes the outcome of a single rate limit check
+type Result struct {
+	Allowed bool
+	// RetryAfter is how long the caller should wait before the next attempt is expected to succeed.
+	// It is zero when the request was allowed.
+	RetryAfter time.Duration
+}
+
+type ILimiter interface {
+	// Allow consumes one unit for the given key if the limit permits it
+	Allow(ctx context.Context, key string) (*Result, error)
+	// Wait blocks until one unit for the given key can be consumed or the context is done
+	Wait(ctx context.Context, key string) error
+}
+
+// IStore holds the limiter state. Implementations must apply each operation atomically
+// so that several processes sharing the same store enforce a single limit.
+type IStore interface {
+	// TakeToken refills the bucket identified by key at the given rate (tokens per second)
+	// up to burst and consumes one token if available
+	TakeToken(ctx context.Context, key string, rate float64, burst int, now time.Time) (*Result, error)
+	// IncrementWindow counts one hit in the sliding window identified by key if the
+	// weighted count of the current and previous windows is below limit
+	IncrementWindow(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (*Result, error)
+	// AdmitMember marks member as active in the set identified by key if it already is, or if fewer than limit
+	// members were active within idleTimeout. A denied result's RetryAfter is when the oldest member goes idle.
+	AdmitMember(ctx context.Context, key string, member string, limit int, idleTimeout time.Duration, now time.Time) (*Result, error)
+}
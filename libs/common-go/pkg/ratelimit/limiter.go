@@ -0,0 +1,108 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// minWaitInterval bounds how often Wait polls the store when the store suggests a very short retry
+const minWaitInterval = 10 * time.Millisecond
+
+type TokenBucketLimiter struct {
+	name  string
+	store IStore
+	rate  float64
+	burst int
+}
+
+// NewTokenBucketLimiter creates a limiter allowing rate requests per second per key with bursts of up to burst requests.
+// The name is used as the metrics label and to namespace keys in the store.
+func NewTokenBucketLimiter(name string, store IStore, rate float64, burst int) (*TokenBucketLimiter, error) {
+	if rate <= 0 {
+		return nil, errors.New("rate must be positive")
+	}
+	if burst < 1 {
+		return nil, errors.New("burst must be at least 1")
+	}
+
+	return &TokenBucketLimiter{
+		name:  name,
+		store: store,
+		rate:  rate,
+		burst: burst,
+	}, nil
+}
+
+func (l *TokenBucketLimiter) Allow(ctx context.Context, key string) (*Result, error) {
+	result, err := l.store.TakeToken(ctx, l.name+":"+key, l.rate, l.burst, time.Now())
+	observe(l.name, result, err)
+	return result, err
+}
+
+func (l *TokenBucketLimiter) Wait(ctx context.Context, key string) error {
+	return wait(ctx, l.name, key, l.Allow)
+}
+
+type SlidingWindowLimiter struct {
+	name   string
+	store  IStore
+	limit  int
+	window time.Duration
+}
+
+// NewSlidingWindowLimiter creates a limiter allowing up to limit requests per key in any window-long period.
+// The name is used as the metrics label and to namespace keys in the store.
+func NewSlidingWindowLimiter(name string, store IStore, limit int, window time.Duration) (*SlidingWindowLimiter, error) {
+	if limit < 1 {
+		return nil, errors.New("limit must be at least 1")
+	}
+	if window <= 0 {
+		return nil, errors.New("window must be positive")
+	}
+
+	return &SlidingWindowLimiter{
+		name:   name,
+		store:  store,
+		limit:  limit,
+		window: window,
+	}, nil
+}
+
+func (l *SlidingWindowLimiter) Allow(ctx context.Context, key string) (*Result, error) {
+	result, err := l.store.IncrementWindow(ctx, l.name+":"+key, l.limit, l.window, time.Now())
+	observe(l.name, result, err)
+	return result, err
+}
+
+func (l *SlidingWindowLimiter) Wait(ctx context.Context, key string) error {
+	return wait(ctx, l.name, key, l.Allow)
+}
+
+func wait(ctx context.Context, name, key string, allow func(context.Context, string) (*Result, error)) error {
+	start := time.Now()
+	defer func() {
+		waitDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	}()
+
+	for {
+		result, err := allow(ctx, key)
+		if err != nil {
+			return err
+		}
+		if result.Allowed {
+			return nil
+		}
+
+		timer := time.NewTimer(max(result.RetryAfter, minWaitInterval))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
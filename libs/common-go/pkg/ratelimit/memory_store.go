@@ -0,0 +1,188 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// memoryStoreIdleTTL is how long an untouched key is kept before it is swept
+const memoryStoreIdleTTL = 10 * time.Minute
+
+type bucketState struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+type windowState struct {
+	start    time.Time
+	previous int
+	current  int
+	lastSeen time.Time
+}
+
+type memberSetState struct {
+	members     map[string]time.Time
+	idleTimeout time.Duration
+	lastSeen    time.Time
+}
+
+// MemoryStore keeps limiter state in process memory. Limits are enforced per process.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucketState
+	windows   map[string]*windowState
+	sets      map[string]*memberSetState
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*bucketState),
+		windows:   make(map[string]*windowState),
+		sets:      make(map[string]*memberSetState),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryStore) TakeToken(ctx context.Context, key string, rate float64, burst int, now time.Time) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &bucketState{tokens: float64(burst), lastSeen: now}
+		s.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.lastSeen).Seconds()
+	if elapsed > 0 {
+		bucket.tokens = math.Min(float64(burst), bucket.tokens+elapsed*rate)
+	}
+	bucket.lastSeen = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return &Result{Allowed: true}, nil
+	}
+
+	retryAfter := time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+	return &Result{Allowed: false, RetryAfter: retryAfter}, nil
+}
+
+func (s *MemoryStore) IncrementWindow(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	start := now.Truncate(window)
+
+	state, ok := s.windows[key]
+	if !ok {
+		state = &windowState{start: start}
+		s.windows[key] = state
+	}
+
+	switch {
+	case start.Equal(state.start):
+	case start.Sub(state.start) == window:
+		state.previous = state.current
+		state.current = 0
+		state.start = start
+	default:
+		state.previous = 0
+		state.current = 0
+		state.start = start
+	}
+	state.lastSeen = now
+
+	elapsedFraction := float64(now.Sub(start)) / float64(window)
+	weighted := float64(state.previous)*(1-elapsedFraction) + float64(state.current)
+
+	if weighted < float64(limit) {
+		state.current++
+		return &Result{Allowed: true}, nil
+	}
+
+	return &Result{Allowed: false, RetryAfter: slidingWindowRetryAfter(state.previous, state.current, limit, window, now.Sub(start))}, nil
+}
+
+func (s *MemoryStore) AdmitMember(ctx context.Context, key string, member string, limit int, idleTimeout time.Duration, now time.Time) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	set, ok := s.sets[key]
+	if !ok {
+		set = &memberSetState{members: make(map[string]time.Time)}
+		s.sets[key] = set
+	}
+	set.idleTimeout = idleTimeout
+	set.lastSeen = now
+
+	var oldest time.Time
+	for m, lastActive := range set.members {
+		if now.Sub(lastActive) > idleTimeout {
+			delete(set.members, m)
+			continue
+		}
+		if oldest.IsZero() || lastActive.Before(oldest) {
+			oldest = lastActive
+		}
+	}
+
+	if _, active := set.members[member]; active || len(set.members) < limit {
+		set.members[member] = now
+		return &Result{Allowed: true}, nil
+	}
+
+	return &Result{Allowed: false, RetryAfter: max(oldest.Add(idleTimeout).Sub(now), minWaitInterval)}, nil
+}
+
+// sweep drops keys that have not been used recently so the store does not grow unbounded.
+// Must be called with the lock held.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memoryStoreIdleTTL {
+		return
+	}
+	s.lastSweep = now
+
+	for key, bucket := range s.buckets {
+		if now.Sub(bucket.lastSeen) > memoryStoreIdleTTL {
+			delete(s.buckets, key)
+		}
+	}
+	for key, state := range s.windows {
+		if now.Sub(state.lastSeen) > memoryStoreIdleTTL {
+			delete(s.windows, key)
+		}
+	}
+	for key, set := range s.sets {
+		if now.Sub(set.lastSeen) > max(memoryStoreIdleTTL, set.idleTimeout) {
+			delete(s.sets, key)
+		}
+	}
+}
+
+// slidingWindowRetryAfter estimates when the weighted count drops below limit, assuming no further hits
+func slidingWindowRetryAfter(previous, current, limit int, window, elapsed time.Duration) time.Duration {
+	if current >= limit || previous == 0 {
+		// Only the rollover into the next window can free capacity
+		return window - elapsed
+	}
+
+	// previous*(1-t/window) + current < limit  =>  t > window*(1-(limit-current)/previous)
+	target := time.Duration(float64(window) * (1 - float64(limit-current)/float64(previous)))
+	if target <= elapsed {
+		return minWaitInterval
+	}
+	return target - elapsed
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		result, err := store.TakeToken(ctx, "key", 1, 3, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Allowed {
+			t.Fatalf("request %d should be allowed within burst", i)
+		}
+	}
+
+	result, err := store.TakeToken(ctx, "key", 1, 3, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Allowed {
+		t.Fatal("request beyond burst should be denied")
+	}
+	if result.RetryAfter <= 0 || result.RetryAfter > time.Second {
+		t.Fatalf("unexpected retry after %v", result.RetryAfter)
+	}
+
+	result, err = store.TakeToken(ctx, "key", 1, 3, now.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Allowed {
+		t.Fatal("request should be allowed after refill")
+	}
+
+	result, err = store.TakeToken(ctx, "other", 1, 3, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Allowed {
+		t.Fatal("keys should be limited independently")
+	}
+}
+
+func TestMemoryStoreSlidingWindow(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	window := time.Minute
+	start := time.Now().Truncate(window)
+
+	for i := 0; i < 4; i++ {
+		result, err := store.IncrementWindow(ctx, "key", 4, window, start.Add(time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Allowed {
+			t.Fatalf("request %d should be allowed within limit", i)
+		}
+	}
+
+	result, err := store.IncrementWindow(ctx, "key", 4, window, start.Add(2*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Allowed {
+		t.Fatal("request beyond limit should be denied")
+	}
+
+	// A quarter into the next window the previous window still weighs 3 hits
+	result, err = store.IncrementWindow(ctx, "key", 4, window, start.Add(window+window/4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Allowed {
+		t.Fatal("request should be allowed once the previous window decays")
+	}
+
+	result, err = store.IncrementWindow(ctx, "key", 4, window, start.Add(window+window/4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Allowed {
+		t.Fatal("weighted count should block the request")
+	}
+}
+
+func TestMemoryStoreAdmitMember(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	idleTimeout := 5 * time.Minute
+	now := time.Now()
+
+	for _, member := range []string{"a", "b"} {
+		result, err := store.AdmitMember(ctx, "key", member, 2, idleTimeout, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Allowed {
+			t.Fatalf("member %s should be admitted within limit", member)
+		}
+	}
+
+	result, err := store.AdmitMember(ctx, "key", "c", 2, idleTimeout, now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Allowed {
+		t.Fatal("member beyond limit should be denied")
+	}
+	if result.RetryAfter != 4*time.Minute {
+		t.Fatalf("retry after should be when the oldest member goes idle, got %v", result.RetryAfter)
+	}
+
+	result, err = store.AdmitMember(ctx, "key", "a", 2, idleTimeout, now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Allowed {
+		t.Fatal("active member should stay admitted at the limit")
+	}
+
+	// b went idle while a was refreshed, so c takes its place
+	result, err = store.AdmitMember(ctx, "key", "c", 2, idleTimeout, now.Add(idleTimeout+time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Allowed {
+		t.Fatal("member should be admitted once another one goes idle")
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	decisions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rate_limiter_decisions_total",
+			Help: "Total number of rate limit decisions",
+		},
+		[]string{"limiter", "result"},
+	)
+
+	storeErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rate_limiter_store_errors_total",
+			Help: "Total number of rate limiter store failures",
+		},
+		[]string{"limiter"},
+	)
+
+	waitDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "rate_limiter_wait_duration_seconds",
+			Help:    "Time spent waiting for the rate limiter to admit a request",
+			Buckets: []float64{0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"limiter"},
+	)
+)
+
+func observe(name string, result *Result, err error) {
+	if err != nil {
+		storeErrors.WithLabelValues(name).Inc()
+		return
+	}
+
+	if result.Allowed {
+		decisions.WithLabelValues(name, "allowed").Inc()
+	} else {
+		decisions.WithLabelValues(name, "denied").Inc()
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/daytonaio/proxy/cmd/proxy/config"
+	"github.com/redis/go-redis/v9"
+)
+
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000 * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate * 1000)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+
+return {allowed, retry}
+`)
+
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local weight = tonumber(ARGV[2])
+local window = tonumber(ARGV[3])
+
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local previous = tonumber(redis.call('GET', KEYS[2]) or '0')
+
+if previous * weight + current < limit then
+	redis.call('INCR', KEYS[1])
+	redis.call('PEXPIRE', KEYS[1], window * 2)
+	return {1, current, previous}
+end
+
+return {0, current, previous}
+`)
+
+var memberSetScript = redis.NewScript(`
+local member = ARGV[1]
+local limit = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local idle = tonumber(ARGV[4])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - idle)
+
+if redis.call('ZSCORE', KEYS[1], member) or redis.call('ZCARD', KEYS[1]) < limit then
+	redis.call('ZADD', KEYS[1], now, member)
+	redis.call('PEXPIRE', KEYS[1], idle)
+	return {1, 0}
+end
+
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return {0, tonumber(oldest[2]) + idle - now}
+`)
+
+var client *redis.Client
+
+// RedisStore keeps limiter state in Redis so that all replicas sharing it enforce a single limit
+type RedisStore struct {
+	redis     *redis.Client
+	keyPrefix string
+}
+
+func (s *RedisStore) TakeToken(ctx context.Context, key string, rate float64, burst int, now time.Time) (*Result, error) {
+	values, err := tokenBucketScript.Run(ctx, s.redis, []string{s.keyPrefix + key}, rate, burst, now.UnixMilli()).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 2 {
+		return nil, fmt.Errorf("unexpected token bucket script result: %v", values)
+	}
+
+	return &Result{
+		Allowed:    values[0] == 1,
+		RetryAfter: time.Duration(values[1]) * time.Millisecond,
+	}, nil
+}
+
+func (s *RedisStore) IncrementWindow(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (*Result, error) {
+	start := now.Truncate(window)
+	index := start.UnixMilli() / window.Milliseconds()
+	elapsed := now.Sub(start)
+	weight := 1 - float64(elapsed)/float64(window)
+
+	keys := []string{
+		fmt.Sprintf("%s%s:%d", s.keyPrefix, key, index),
+		fmt.Sprintf("%s%s:%d", s.keyPrefix, key, index-1),
+	}
+
+	values, err := slidingWindowScript.Run(ctx, s.redis, keys, limit, weight, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 3 {
+		return nil, fmt.Errorf("unexpected sliding window script result: %v", values)
+	}
+
+	if values[0] == 1 {
+		return &Result{Allowed: true}, nil
+	}
+
+	return &Result{
+		Allowed:    false,
+		RetryAfter: slidingWindowRetryAfter(int(values[2]), int(values[1]), limit, window, elapsed),
+	}, nil
+}
+
+func (s *RedisStore) AdmitMember(ctx context.Context, key string, member string, limit int, idleTimeout time.Duration, now time.Time) (*Result, error) {
+	values, err := memberSetScript.Run(ctx, s.redis, []string{s.keyPrefix + key}, member, limit, now.UnixMilli(), idleTimeout.Milliseconds()).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 2 {
+		return nil, fmt.Errorf("unexpected member set script result: %v", values)
+	}
+
+	if values[0] == 1 {
+		return &Result{Allowed: true}, nil
+	}
+
+	return &Result{
+		Allowed:    false,
+		RetryAfter: max(time.Duration(values[1])*time.Millisecond, minWaitInterval),
+	}, nil
+}
+
+func NewRedisStore(config *config.RedisConfig, keyPrefix string) (*RedisStore, error) {
+	if config.Host == nil || config.Port == nil {
+		return nil, errors.New("host and port are required")
+	}
+
+	password := ""
+	if config.Password != nil {
+		password = *config.Password
+	}
+
+	if client == nil {
+		options := &redis.Options{
+			Addr:     fmt.Sprintf("%s:%d", *config.Host, *config.Port),
+			Password: password,
+		}
+		if config.TLS != nil && *config.TLS {
+			options.TLSConfig = &tls.Config{}
+		}
+		client = redis.NewClient(options)
+	}
+
+	return &RedisStore{
+		redis:     client,
+		keyPrefix: keyPrefix,
+	}, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package signedurl implements preview URLs signed with an organization secret, which the proxy validates
+// against the sandbox's cached organization. A signed URL carries the organization, an expiry and an HMAC-SHA256
+// signature of the organization, sandbox, port and expiry as query parameters:
+//
+//	https://3000-<sandboxId>.proxy.example.com/hook?DAYTONA_SIGNATURE_ORG=<orgId>&DAYTONA_SIGNATURE_EXPIRES=<unix>&DAYTONA_SIGNATURE=<hex>
+package signedurl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	OrganizationParam = "DAYTONA_SIGNATURE_ORG"
+	ExpiresParam      = "DAYTONA_SIGNATURE_EXPIRES"
+	SignatureParam    = "DAYTONA_SIGNATURE"
+
+	// SecretPath is the proxy admin endpoint deriving an organization's signing secret
+	SecretPath = "/signed-url-secret"
+
+	// version prefixes the signed string so the scheme can change without old signatures becoming valid
+	version = "v2"
+)
+
+var (
+	ErrMissing   = errors.New("signed URL parameters are missing")
+	ErrExpired   = errors.New("signed URL is expired")
+	ErrMalformed = errors.New("signed URL parameters are malformed")
+	ErrSignature = errors.New("signed URL signature is invalid")
+)
+
+// Params are the signature query parameters of a signed URL
+type Params struct {
+	OrganizationId string
+	ExpiresAt      time.Time
+	Signature      string
+}
+
+// OrgSecret derives an organization's signing secret from the proxy's master secret. Organizations only ever
+// receive their own derived secret, the master secret stays with the proxy and the control plane.
+func OrgSecret(masterSecret string, organizationId string) string {
+	mac := hmac.New(sha256.New, []byte(masterSecret))
+	mac.Write([]byte("org:" + organizationId))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign returns the signature granting access to the organization's sandbox port until expiresAt
+func Sign(orgSecret string, organizationId string, sandboxId string, port int, expiresAt time.Time) string {
+	mac := hmac.New(sha256.New, []byte(orgSecret))
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%d\n%d", version, organizationId, sandboxId, port, expiresAt.Unix())
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Query returns the query parameters to add to a preview URL of the sandbox port
+func Query(orgSecret string, organizationId string, sandboxId string, port int, expiresAt time.Time) url.Values {
+	return url.Values{
+		OrganizationParam: []string{organizationId},
+		ExpiresParam:      []string{strconv.FormatInt(expiresAt.Unix(), 10)},
+		SignatureParam:    []string{Sign(orgSecret, organizationId, sandboxId, port, expiresAt)},
+	}
+}
+
+// ParseQuery extracts the signature parameters of a URL
+func ParseQuery(query url.Values) (*Params, error) {
+	organizationId := query.Get(OrganizationParam)
+	expires := query.Get(ExpiresParam)
+	signature := query.Get(SignatureParam)
+	if organizationId == "" || expires == "" || signature == "" {
+		return nil, ErrMissing
+	}
+
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	return &Params{
+		OrganizationId: organizationId,
+		ExpiresAt:      time.Unix(expiresUnix, 0),
+		Signature:      signature,
+	}, nil
+}
+
+// Verify checks that the parameters were signed with the organization's secret for the sandbox port and have not
+// expired. A signature is only valid for the organization it was issued under, but any organization can sign for any
+// sandbox ID: the caller must also check that the sandbox belongs to p.OrganizationId.
+func (p *Params) Verify(masterSecret string, sandboxId string, port int, now time.Time) error {
+	if !now.Before(p.ExpiresAt) {
+		return ErrExpired
+	}
+
+	signature, err := hex.DecodeString(p.Signature)
+	if err != nil {
+		return ErrMalformed
+	}
+
+	expected, _ := hex.DecodeString(Sign(OrgSecret(masterSecret, p.OrganizationId), p.OrganizationId, sandboxId, port, p.ExpiresAt))
+	if !hmac.Equal(signature, expected) {
+		return ErrSignature
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package slo
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	p95Latency = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "slo_org_p95_latency_seconds",
+			Help: "Rolling-window p95 upstream latency per organization",
+		},
+		[]string{"organization_id"},
+	)
+
+	availability = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "slo_org_availability_ratio",
+			Help: "Rolling-window share of requests the upstream answered without a server error per organization",
+		},
+		[]string{"organization_id"},
+	)
+
+	burnRate = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "slo_org_error_budget_burn_rate",
+			Help: "Rolling-window error budget burn rate per organization against the availability objective",
+		},
+		[]string{"organization_id"},
+	)
+
+	alerts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "slo_burn_alerts_total",
+			Help: "Total number of SLO burn alerts raised per organization",
+		},
+		[]string{"organization_id"},
+	)
+)
+
+// PublishMetrics exposes the report as gauges, dropping organizations without traffic in the window
+func PublishMetrics(report *Report) {
+	p95Latency.Reset()
+	availability.Reset()
+	burnRate.Reset()
+
+	for _, org := range report.Organizations {
+		p95Latency.WithLabelValues(org.OrganizationId).Set(org.P95LatencyMs / 1000)
+		availability.WithLabelValues(org.OrganizationId).Set(org.Availability)
+		burnRate.WithLabelValues(org.OrganizationId).Set(org.BurnRate)
+	}
+}
+
+// CountAlert records a raised SLO burn alert
+func CountAlert(organizationId string) {
+	alerts.WithLabelValues(organizationId).Inc()
+}
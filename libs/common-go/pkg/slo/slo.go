@@ -0,0 +1,194 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package slo
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ReportPath is the admin endpoint serving the per-organization SLO report
+const ReportPath = "/slo-report"
+
+// subWindows is the number of slices the rolling window is divided into; the window advances one slice at a time
+const subWindows = 12
+
+// latencyBounds are the upper bounds of the latency buckets percentiles are estimated from
+var latencyBounds = [...]time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+}
+
+// Objectives are the targets organizations are measured against. A zero value disables the objective.
+type Objectives struct {
+	LatencyP95Ms float64 `json:"latencyP95Ms,omitempty"`
+	Availability float64 `json:"availability,omitempty"`
+}
+
+// OrgReport is the rolling-window SLO measurement of a single organization
+type OrgReport struct {
+	OrganizationId string  `json:"organizationId"`
+	Requests       int64   `json:"requests"`
+	Errors         int64   `json:"errors"`
+	Availability   float64 `json:"availability"`
+	// BurnRate is how many times faster than allowed by the availability objective the error budget is consumed
+	BurnRate        float64 `json:"burnRate"`
+	AvailabilityMet bool    `json:"availabilityMet"`
+	// P95LatencyMs is the upper bound of the latency bucket holding the 95th percentile, capped at the largest bound
+	P95LatencyMs float64 `json:"p95LatencyMs"`
+	LatencyMet   bool    `json:"latencyMet"`
+}
+
+// Report is the SLO measurement of all organizations with traffic within the window
+type Report struct {
+	Source        string      `json:"source"`
+	WindowSeconds float64     `json:"windowSeconds"`
+	GeneratedAt   time.Time   `json:"generatedAt"`
+	Objectives    Objectives  `json:"objectives"`
+	Organizations []OrgReport `json:"organizations"`
+}
+
+// Alert is sent when an organization consumes its error budget faster than the alert threshold
+type Alert struct {
+	Source     string     `json:"source"`
+	Objectives Objectives `json:"objectives"`
+	OrgReport
+}
+
+type bucket struct {
+	slot     int64
+	requests int64
+	errors   int64
+	latency  [len(latencyBounds) + 1]int64 // The last count holds requests slower than all bounds
+}
+
+type orgWindow struct {
+	buckets [subWindows]bucket
+}
+
+// Tracker measures upstream latency and availability per organization over a rolling window
+type Tracker struct {
+	mu         sync.Mutex
+	slice      time.Duration
+	objectives Objectives
+	orgs       map[string]*orgWindow
+}
+
+func NewTracker(window time.Duration, objectives Objectives) *Tracker {
+	return &Tracker{
+		slice:      max(window/subWindows, time.Second),
+		objectives: objectives,
+		orgs:       make(map[string]*orgWindow),
+	}
+}
+
+// Observe records an upstream response of the organization; failed is true when the upstream was unavailable
+func (t *Tracker) Observe(organizationId string, latency time.Duration, failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	org, found := t.orgs[organizationId]
+	if !found {
+		org = &orgWindow{}
+		t.orgs[organizationId] = org
+	}
+
+	slot := time.Now().UnixNano() / int64(t.slice)
+	b := &org.buckets[slot%subWindows]
+	if b.slot != slot {
+		*b = bucket{slot: slot}
+	}
+
+	b.requests++
+	if failed {
+		b.errors++
+	}
+	b.latency[sort.Search(len(latencyBounds), func(i int) bool { return latency <= latencyBounds[i] })]++
+}
+
+// Report returns the measurement of every organization with traffic in the window and forgets the others
+func (t *Tracker) Report(source string) *Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	currentSlot := now.UnixNano() / int64(t.slice)
+	report := &Report{
+		Source:        source,
+		WindowSeconds: (t.slice * subWindows).Seconds(),
+		GeneratedAt:   now,
+		Objectives:    t.objectives,
+		Organizations: make([]OrgReport, 0, len(t.orgs)),
+	}
+
+	for organizationId, org := range t.orgs {
+		var total bucket
+		for _, b := range org.buckets {
+			if b.slot <= currentSlot-subWindows {
+				continue
+			}
+			total.requests += b.requests
+			total.errors += b.errors
+			for i, count := range b.latency {
+				total.latency[i] += count
+			}
+		}
+		if total.requests == 0 {
+			delete(t.orgs, organizationId)
+			continue
+		}
+
+		report.Organizations = append(report.Organizations, t.evaluate(organizationId, &total))
+	}
+
+	sort.Slice(report.Organizations, func(i, j int) bool {
+		return report.Organizations[i].OrganizationId < report.Organizations[j].OrganizationId
+	})
+	return report
+}
+
+func (t *Tracker) evaluate(organizationId string, total *bucket) OrgReport {
+	orgReport := OrgReport{
+		OrganizationId:  organizationId,
+		Requests:        total.requests,
+		Errors:          total.errors,
+		Availability:    1 - float64(total.errors)/float64(total.requests),
+		LatencyMet:      true,
+		AvailabilityMet: true,
+	}
+
+	// The 95th percentile falls into the first bucket reaching 95% of the requests
+	target := int64(math.Ceil(0.95 * float64(total.requests)))
+	var cumulative int64
+	for i, count := range total.latency {
+		cumulative += count
+		if cumulative >= target {
+			bound := latencyBounds[min(i, len(latencyBounds)-1)]
+			orgReport.P95LatencyMs = float64(bound.Milliseconds())
+			break
+		}
+	}
+
+	if t.objectives.LatencyP95Ms > 0 {
+		orgReport.LatencyMet = orgReport.P95LatencyMs <= t.objectives.LatencyP95Ms
+	}
+	if t.objectives.Availability > 0 && t.objectives.Availability < 1 {
+		orgReport.BurnRate = (1 - orgReport.Availability) / (1 - t.objectives.Availability)
+		orgReport.AvailabilityMet = orgReport.Availability >= t.objectives.Availability
+	}
+
+	return orgReport
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package traffic
+
+import (
+	"sync"
+	"time"
+)
+
+// ReportPath is the runner-manager endpoint proxies post traffic reports to
+const ReportPath = "/traffic-reports"
+
+// SandboxTraffic holds the preview traffic of a single sandbox within a report window
+type SandboxTraffic struct {
+	SandboxId string `json:"sandboxId"`
+	// RunnerProxyUrl identifies the runner hosting the sandbox
+	RunnerProxyUrl string `json:"runnerProxyUrl"`
+	Requests       int64  `json:"requests"`
+	BytesIn        int64  `json:"bytesIn"`
+	BytesOut       int64  `json:"bytesOut"`
+}
+
+// Report is the per-sandbox traffic a proxy instance served within a window
+type Report struct {
+	Source      string           `json:"source"`
+	WindowStart time.Time        `json:"windowStart"`
+	WindowEnd   time.Time        `json:"windowEnd"`
+	Sandboxes   []SandboxTraffic `json:"sandboxes"`
+}
+
+// Recorder accumulates per-sandbox traffic until it is flushed into a report
+type Recorder struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	sandboxes   map[string]*SandboxTraffic
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		windowStart: time.Now(),
+		sandboxes:   make(map[string]*SandboxTraffic),
+	}
+}
+
+// Record adds traffic to the sandbox's counters; requests is the number of new requests being accounted
+func (r *Recorder) Record(sandboxId, runnerProxyUrl string, requests, bytesIn, bytesOut int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sandbox, found := r.sandboxes[sandboxId]
+	if !found {
+		sandbox = &SandboxTraffic{
+			SandboxId:      sandboxId,
+			RunnerProxyUrl: runnerProxyUrl,
+		}
+		r.sandboxes[sandboxId] = sandbox
+	}
+	sandbox.Requests += requests
+	sandbox.BytesIn += bytesIn
+	sandbox.BytesOut += bytesOut
+}
+
+// Flush returns the traffic recorded since the previous flush and starts a new window
+func (r *Recorder) Flush(source string) *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	report := &Report{
+		Source:      source,
+		WindowStart: r.windowStart,
+		WindowEnd:   now,
+		Sandboxes:   make([]SandboxTraffic, 0, len(r.sandboxes)),
+	}
+	for _, sandbox := range r.sandboxes {
+		report.Sandboxes = append(report.Sandboxes, *sandbox)
+	}
+
+	r.windowStart = now
+	r.sandboxes = make(map[string]*SandboxTraffic)
+	return report
+}
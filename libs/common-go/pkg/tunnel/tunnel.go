@@ -0,0 +1,82 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package tunnel
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// HeartbeatPathPrefix is the proxy path under which runners that cannot reach the API directly send their healthchecks
+	HeartbeatPathPrefix = "/runner-heartbeat"
+
+	// HealthcheckPath is the only Daytona API path relayed under HeartbeatPathPrefix
+	HealthcheckPath = "/runners/healthcheck"
+
+	// NodeNameHeader carries the Kubernetes node a tunneled runner runs on
+	NodeNameHeader = "X-Daytona-Node-Name"
+
+	// ReportPath is the runner-manager endpoint proxies post tunnel liveness reports to
+	ReportPath = "/tunnel-reports"
+
+	// ForgetAfter is how long a tunneled runner is tracked after its last heartbeat
+	ForgetAfter = time.Hour
+)
+
+// RunnerTunnel is the liveness of a runner whose heartbeat is relayed by the proxy
+type RunnerTunnel struct {
+	// Domain is the domain the runner reports to the Daytona API
+	Domain        string    `json:"domain"`
+	NodeName      string    `json:"nodeName,omitempty"`
+	LastHeartbeat time.Time `json:"lastHeartbeat"`
+}
+
+// Report is the set of tunneled runners a proxy instance has relayed heartbeats for. SentAt is on the proxy's clock
+// like the heartbeats, so receivers can tell the age of each heartbeat without trusting the proxy's clock.
+type Report struct {
+	Source  string         `json:"source"`
+	SentAt  time.Time      `json:"sentAt"`
+	Runners []RunnerTunnel `json:"runners"`
+}
+
+// Tracker records the last relayed heartbeat per runner domain
+type Tracker struct {
+	mu      sync.Mutex
+	runners map[string]RunnerTunnel
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		runners: make(map[string]RunnerTunnel),
+	}
+}
+
+// Heartbeat records a relayed heartbeat of the runner
+func (t *Tracker) Heartbeat(domain, nodeName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.runners[domain] = RunnerTunnel{Domain: domain, NodeName: nodeName, LastHeartbeat: time.Now()}
+}
+
+// Report returns the tracked runners and forgets those not seen within ForgetAfter.
+// Runners with a stale heartbeat are still reported so receivers can tell a dead tunnel from a direct runner.
+func (t *Tracker) Report(source string) *Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := &Report{
+		Source:  source,
+		SentAt:  time.Now(),
+		Runners: make([]RunnerTunnel, 0, len(t.runners)),
+	}
+	for domain, runner := range t.runners {
+		if time.Since(runner.LastHeartbeat) > ForgetAfter {
+			delete(t.runners, domain)
+			continue
+		}
+		report.Runners = append(report.Runners, runner)
+	}
+	return report
+}